@@ -66,6 +66,9 @@ type CallOpts struct {
 	BlockNumber *big.Int        // Optional the block number on which the call should be performed
 	BlockHash   common.Hash     // Optional the block hash on which the call should be performed
 	Context     context.Context // Network context to support cancellation and timeouts (nil = no timeout)
+
+	Value      *big.Int         // Optional funds to transfer along the call, to simulate payable methods (nil = no funds)
+	AccessList types.AccessList // Optional access list of the call, carrying the predicates of precompiles
 }
 
 // NativeAssetCallOpts contains params for native asset call
@@ -91,6 +94,8 @@ type TransactOpts struct {
 
 	NoSend bool // Do all transact steps but do not send the transaction
 
+	AccessList types.AccessList // Optional access list of the transaction, carrying the predicates of precompiles
+
 	// If set, the transaction is transformed to perform the requested call through the native asset
 	// precompile. This will update the to address of the transaction to that of the native asset precompile
 	// and pack the requested [to] address, [assetID], [assetAmount], and [input] data for the transaction
@@ -198,7 +203,7 @@ func (c *BoundContract) Call(opts *CallOpts, results *[]interface{}, method stri
 		return err
 	}
 	var (
-		msg    = ethereum.CallMsg{From: opts.From, To: &c.address, Data: input}
+		msg    = ethereum.CallMsg{From: opts.From, To: &c.address, Value: opts.Value, Data: input, AccessList: opts.AccessList}
 		ctx    = ensureContext(opts.Context)
 		code   []byte
 		output []byte
@@ -273,6 +278,24 @@ func (c *BoundContract) Transact(opts *TransactOpts, method string, params ...in
 	return c.transact(opts, &c.address, input)
 }
 
+// EstimateGas estimates the gas needed to invoke the (paid) contract method
+// with params as input values, as Transact would with opts.
+func (c *BoundContract) EstimateGas(opts *TransactOpts, method string, params ...interface{}) (uint64, error) {
+	input, err := c.abi.Pack(method, params...)
+	if err != nil {
+		return 0, err
+	}
+	contract, input, err := wrapNativeAssetCall(opts, &c.address, input)
+	if err != nil {
+		return 0, err
+	}
+	value := opts.Value
+	if value == nil {
+		value = new(big.Int)
+	}
+	return c.estimateGasLimit(opts, contract, input, opts.GasPrice, opts.GasTipCap, opts.GasFeeCap, value)
+}
+
 // RawTransact initiates a transaction with the given raw calldata as the input.
 // It's usually used to initiate transactions for invoking **Fallback** function.
 func (c *BoundContract) RawTransact(opts *TransactOpts, calldata []byte) (*types.Transaction, error) {
@@ -362,13 +385,14 @@ func (c *BoundContract) createDynamicTx(opts *TransactOpts, contract *common.Add
 		return nil, err
 	}
 	baseTx := &types.DynamicFeeTx{
-		To:        contract,
-		Nonce:     nonce,
-		GasFeeCap: gasFeeCap,
-		GasTipCap: gasTipCap,
-		Gas:       gasLimit,
-		Value:     value,
-		Data:      input,
+		To:         contract,
+		Nonce:      nonce,
+		GasFeeCap:  gasFeeCap,
+		GasTipCap:  gasTipCap,
+		Gas:        gasLimit,
+		Value:      value,
+		Data:       input,
+		AccessList: opts.AccessList,
 	}
 	return types.NewTx(baseTx), nil
 }
@@ -405,6 +429,19 @@ func (c *BoundContract) createLegacyTx(opts *TransactOpts, contract *common.Addr
 	if err != nil {
 		return nil, err
 	}
+	if len(opts.AccessList) > 0 {
+		// Legacy transactions can't carry an access list, the chain ID is
+		// set when the transaction is signed.
+		return types.NewTx(&types.AccessListTx{
+			To:         contract,
+			Nonce:      nonce,
+			GasPrice:   gasPrice,
+			Gas:        gasLimit,
+			Value:      value,
+			Data:       input,
+			AccessList: opts.AccessList,
+		}), nil
+	}
 	baseTx := &types.LegacyTx{
 		To:       contract,
 		Nonce:    nonce,
@@ -426,13 +463,14 @@ func (c *BoundContract) estimateGasLimit(opts *TransactOpts, contract *common.Ad
 		}
 	}
 	msg := ethereum.CallMsg{
-		From:      opts.From,
-		To:        contract,
-		GasPrice:  gasPrice,
-		GasTipCap: gasTipCap,
-		GasFeeCap: gasFeeCap,
-		Value:     value,
-		Data:      input,
+		From:       opts.From,
+		To:         contract,
+		GasPrice:   gasPrice,
+		GasTipCap:  gasTipCap,
+		GasFeeCap:  gasFeeCap,
+		Value:      value,
+		Data:       input,
+		AccessList: opts.AccessList,
 	}
 	return c.transactor.EstimateGas(ensureContext(opts.Context), msg)
 }
@@ -35,7 +35,9 @@ import (
 	"bytes"
 	"fmt"
 	"go/format"
+	"maps"
 	"regexp"
+	"slices"
 	"strings"
 	"text/template"
 	"unicode"
@@ -88,11 +90,25 @@ func isKeyWord(arg string) bool {
 	return true
 }
 
+// BindOpts are the optional features of generated bindings.
+type BindOpts struct {
+	// Warp generates variants of the methods of the contracts attaching a warp
+	// message to their transactions and calls as a predicate of the warp
+	// precompile, for contracts receiving warp messages.
+	Warp bool
+}
+
 // Bind generates a Go wrapper around a contract ABI. This wrapper isn't meant
 // to be used as is in client code, but rather as an intermediate struct which
 // enforces compile time type safety and naming convention as opposed to having to
 // manually maintain hard coded strings that break on runtime.
 func Bind(types []string, abis []string, bytecodes []string, fsigs []map[string]string, pkg string, lang Lang, libs map[string]string, aliases map[string]string) (string, error) {
+	return BindWithOpts(types, abis, bytecodes, fsigs, pkg, lang, libs, aliases, BindOpts{})
+}
+
+// BindWithOpts generates a Go wrapper around a contract ABI like Bind, with the
+// optional features of opts.
+func BindWithOpts(types []string, abis []string, bytecodes []string, fsigs []map[string]string, pkg string, lang Lang, libs map[string]string, aliases map[string]string, opts BindOpts) (string, error) {
 	var (
 		// contracts is the map of each individual contract requested binding
 		contracts = make(map[string]*tmplContract)
@@ -122,6 +138,7 @@ func Bind(types []string, abis []string, bytecodes []string, fsigs []map[string]
 			calls     = make(map[string]*tmplMethod)
 			transacts = make(map[string]*tmplMethod)
 			events    = make(map[string]*tmplEvent)
+			errors    = make(map[string]*tmplError)
 			fallback  *tmplMethod
 			receive   *tmplMethod
 
@@ -132,6 +149,7 @@ func Bind(types []string, abis []string, bytecodes []string, fsigs []map[string]
 			callIdentifiers     = make(map[string]bool)
 			transactIdentifiers = make(map[string]bool)
 			eventIdentifiers    = make(map[string]bool)
+			errorIdentifiers    = make(map[string]bool)
 		)
 
 		for _, input := range evmABI.Constructor.Inputs {
@@ -237,6 +255,72 @@ func Bind(types []string, abis []string, bytecodes []string, fsigs []map[string]
 			// Append the event to the accumulator list
 			events[original.Name] = &tmplEvent{Original: original, Normalized: normalized}
 		}
+		// Errors are bound to types prefixed by the contract type, like the
+		// contract bindings and the event types. Iterate them in order, so that
+		// errors colliding with those types are renamed deterministically.
+		errorNames := slices.Sorted(maps.Keys(evmABI.Errors))
+		errorNormalizedNames := make(map[string]string, len(errorNames))
+		for _, name := range errorNames {
+			// Ensure there is no duplicated identifier
+			normalizedName := methodNormalizer[lang](alias(aliases, name))
+			// Name shouldn't start with a digit. It will make the generated code invalid.
+			if len(normalizedName) > 0 && unicode.IsDigit(rune(normalizedName[0])) {
+				normalizedName = fmt.Sprintf("E%s", normalizedName)
+				normalizedName = abi.ResolveNameConflict(normalizedName, func(identifier string) bool {
+					return errorIdentifiers[identifier]
+				})
+			}
+			if errorIdentifiers[normalizedName] {
+				return "", fmt.Errorf("duplicated identifier \"%s\"(normalized \"%s\"), use --alias for renaming", name, normalizedName)
+			}
+			errorIdentifiers[normalizedName] = true
+			errorNormalizedNames[name] = normalizedName
+		}
+		reservedErrorIdentifiers := make(map[string]bool)
+		for _, suffix := range contractTypeSuffixes {
+			reservedErrorIdentifiers[suffix] = true
+		}
+		for name := range eventIdentifiers {
+			reservedErrorIdentifiers[name] = true
+			reservedErrorIdentifiers[name+"Iterator"] = true
+		}
+		for _, name := range errorNames {
+			original := evmABI.Errors[name]
+			// Normalize the error for capital cases and non-anonymous inputs
+			normalized := original
+
+			normalizedName := errorNormalizedNames[name]
+			if reservedErrorIdentifiers[normalizedName] {
+				normalizedName = abi.ResolveNameConflict(normalizedName+"Error", func(identifier string) bool {
+					return reservedErrorIdentifiers[identifier] || errorIdentifiers[identifier]
+				})
+				errorIdentifiers[normalizedName] = true
+			}
+			normalized.Name = normalizedName
+
+			// The Error method of the struct reserves its name.
+			used := map[string]bool{"Error": true}
+			normalized.Inputs = make([]abi.Argument, len(original.Inputs))
+			copy(normalized.Inputs, original.Inputs)
+			for j, input := range normalized.Inputs {
+				if input.Name == "" || isKeyWord(input.Name) {
+					normalized.Inputs[j].Name = fmt.Sprintf("arg%d", j)
+				}
+				// Errors are bound to structs as well, ensure there is no
+				// camel-case-style name conflict.
+				for index := 0; ; index++ {
+					if !used[capitalise(normalized.Inputs[j].Name)] {
+						used[capitalise(normalized.Inputs[j].Name)] = true
+						break
+					}
+					normalized.Inputs[j].Name = fmt.Sprintf("%s%d", normalized.Inputs[j].Name, index)
+				}
+				if hasStruct(input.Type) {
+					bindStructType[lang](input.Type, structs)
+				}
+			}
+			errors[original.Name] = &tmplError{Original: original, Normalized: normalized}
+		}
 		// Add two special fallback functions if they exist
 		if evmABI.HasFallback() {
 			fallback = &tmplMethod{Original: evmABI.Fallback}
@@ -254,6 +338,7 @@ func Bind(types []string, abis []string, bytecodes []string, fsigs []map[string]
 			Fallback:    fallback,
 			Receive:     receive,
 			Events:      events,
+			Errors:      errors,
 			Libraries:   make(map[string]string),
 		}
 		// Function 4-byte signatures are stored in the same sequence
@@ -287,6 +372,7 @@ func Bind(types []string, abis []string, bytecodes []string, fsigs []map[string]
 		Contracts: contracts,
 		Libraries: libs,
 		Structs:   structs,
+		Warp:      opts.Warp,
 	}
 	buffer := new(bytes.Buffer)
 
@@ -319,6 +405,15 @@ var bindType = map[Lang]func(kind abi.Type, structs map[string]*tmplStruct) stri
 	LangGo: bindTypeGo,
 }
 
+// contractTypeSuffixes are the suffixes of the names of the types and variables
+// generated for each contract, which are prefixed by the contract type.
+var contractTypeSuffixes = []string{
+	"", "MetaData", "ABI", "FuncSigs", "Bin",
+	"Caller", "Transactor", "Filterer",
+	"Session", "CallerSession", "TransactorSession",
+	"Raw", "CallerRaw", "TransactorRaw",
+}
+
 // bindBasicTypeGo converts basic solidity types(except array, slice and tuple) to Go ones.
 func bindBasicTypeGo(kind abi.Type) string {
 	switch kind.T {
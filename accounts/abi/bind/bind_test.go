@@ -1932,17 +1932,83 @@ var bindTests = []struct {
 			if err != nil {
 				t.Error(err)
 			}
-			if err := contract.Error(new(bind.CallOpts)); err == nil {
+			err = contract.Error(new(bind.CallOpts))
+			if err == nil {
 				t.Fatalf("expected contract to throw error")
 			}
-			// TODO (MariusVanDerWijden unpack error using abigen
-			// once that is implemented
+			unpacked, ok := UnpackNewErrorsError(err)
+			if !ok {
+				t.Fatalf("failed to unpack custom error from %v", err)
+			}
+			myErr, ok := unpacked.(*NewErrorsMyError3)
+			if !ok {
+				t.Fatalf("unexpected custom error type: %T", unpacked)
+			}
+			if myErr.A.Int64() != 1 || myErr.B.Int64() != 2 || myErr.C.Int64() != 3 {
+				t.Fatalf("custom error content mismatch: have %+v, want {1, 2, 3}", myErr)
+			}
+			if have, want := myErr.Error(), "execution reverted: MyError3(1, 2, 3)"; have != want {
+				t.Fatalf("custom error message mismatch: have %q, want %q", have, want)
+			}
+			if _, ok := UnpackNewErrorsErrorData([]byte{1, 2, 3, 4}); ok {
+				t.Fatalf("unpacked unknown custom error")
+			}
+			_ = NewErrorsMyError2{Arg0: big.NewInt(0), Arg1: big.NewInt(0)}
 	   `,
 		nil,
 		nil,
 		nil,
 		nil,
 	},
+	// Tests that the types of errors colliding with the other generated types are renamed
+	{
+		`ErrorCollisions`, ``, []string{``},
+		[]string{`
+			[
+				{"type":"event","name":"Transfer","inputs":[]},
+				{"type":"error","name":"Caller","inputs":[{"name":"","type":"uint256"}]},
+				{"type":"error","name":"CallerError","inputs":[]},
+				{"type":"error","name":"Raw","inputs":[]},
+				{"type":"error","name":"Transfer","inputs":[]},
+				{"type":"error","name":"TransferIterator","inputs":[]}
+			]
+		`},
+		`
+			"math/big"
+		`,
+		`
+			var (
+				_ ErrorCollisionsCaller
+				_ ErrorCollisionsRaw
+				_ ErrorCollisionsTransfer
+				_ ErrorCollisionsTransferIterator
+				_ ErrorCollisionsCallerError
+				_ ErrorCollisionsRawError
+				_ ErrorCollisionsTransferError
+				_ ErrorCollisionsTransferIteratorError
+			)
+			parsed, err := ErrorCollisionsMetaData.GetAbi()
+			if err != nil {
+				t.Fatal(err)
+			}
+			abiErr := parsed.Errors["Caller"]
+			data, err := abiErr.Inputs.Pack(big.NewInt(7))
+			if err != nil {
+				t.Fatal(err)
+			}
+			unpacked, ok := UnpackErrorCollisionsErrorData(append(abiErr.ID[:4], data...))
+			if !ok {
+				t.Fatalf("failed to unpack custom error")
+			}
+			if callerErr, ok := unpacked.(*ErrorCollisionsCallerError0); !ok || callerErr.Arg0.Int64() != 7 {
+				t.Fatalf("unexpected custom error: %#v", unpacked)
+			}
+		`,
+		nil,
+		nil,
+		nil,
+		nil,
+	},
 	{
 		name: `ConstructorWithStructParam`,
 		contract: `
@@ -2097,6 +2163,99 @@ var bindTests = []struct {
 			}
 `,
 	},
+	// Test that the warp message variants of the methods are generated with
+	// the warp bind option (see bindOpts) and attach the message to calls and
+	// transactions.
+	{
+		name: "Warp",
+		contract: `
+		// SPDX-License-Identifier: GPL-3.0
+		pragma solidity >=0.4.22 <0.9.0;
+
+		// Returns true for every call, without reading the warp message.
+		contract Warp {
+			function received() public view returns (bool) { return true; }
+			function receiveMessage(uint32 index) public payable {}
+		}
+		`,
+		bytecode: []string{"0x600a600c600039600a6000f3600160005260206000f3"},
+		abi:      []string{`[{"inputs":[],"name":"received","outputs":[{"internalType":"bool","name":"","type":"bool"}],"stateMutability":"view","type":"function"},{"inputs":[{"internalType":"uint32","name":"index","type":"uint32"}],"name":"receiveMessage","outputs":[],"stateMutability":"payable","type":"function"}]`},
+		imports: `
+			"context"
+			"math/big"
+
+			"github.com/MetalBlockchain/coreth/accounts/abi/bind"
+			"github.com/MetalBlockchain/coreth/accounts/abi/bind/backends"
+			"github.com/MetalBlockchain/libevm/common"
+			"github.com/MetalBlockchain/libevm/core/types"
+			"github.com/MetalBlockchain/libevm/crypto"
+			"github.com/MetalBlockchain/metalgo/ids"
+		`,
+		tester: `
+			var (
+				gasCeil = uint64(30000000) // Note: from geth's ethconfig.Defaults.Miner.GasCeil
+				key, _  = crypto.GenerateKey()
+				user, _ = bind.NewKeyedTransactorWithChainID(key, big.NewInt(1337))
+				sim     = backends.NewSimulatedBackend(types.GenesisAlloc{user.From: {Balance: big.NewInt(1000000000000000000)}}, gasCeil)
+			)
+			defer sim.Close()
+
+			_, _, receiver, err := DeployWarp(user, sim)
+			if err != nil {
+				t.Fatalf("Failed to deploy contract: %v", err)
+			}
+			sim.Commit(true)
+
+			unsignedMessage, err := sim.NewWarpMessage(ids.GenerateTestID(), common.Address{0xaa}, []byte("hello"))
+			if err != nil {
+				t.Fatalf("Failed to create warp message: %v", err)
+			}
+			message, err := sim.SignWarpMessage(unsignedMessage)
+			if err != nil {
+				t.Fatalf("Failed to sign warp message: %v", err)
+			}
+
+			if received, err := receiver.ReceivedWithWarpMessage(nil, message); err != nil || !received {
+				t.Fatalf("Failed to call with warp message: %v %v", received, err)
+			}
+			if err := receiver.CallReceiveMessageWithWarpMessage(&bind.CallOpts{From: user.From, Value: big.NewInt(1)}, message, 0); err != nil {
+				t.Fatalf("Failed to simulate transaction with warp message: %v", err)
+			}
+			gas, err := receiver.EstimateReceiveMessageWithWarpMessage(user, message, 0)
+			if err != nil {
+				t.Fatalf("Failed to estimate gas with warp message: %v", err)
+			}
+			user.Value = big.NewInt(1)
+			tx, err := receiver.ReceiveMessageWithWarpMessage(user, message, 0)
+			if err != nil {
+				t.Fatalf("Failed to transact with warp message: %v", err)
+			}
+			if gas == 0 {
+				t.Fatalf("Estimated zero gas with warp message")
+			}
+			if have := tx.AccessList(); len(have) != 1 || have[0].Address != bind.WarpPredicate(message).Address {
+				t.Fatalf("Warp predicate missing from access list: %v", have)
+			}
+			if tx.Value().Cmp(big.NewInt(1)) != 0 {
+				t.Fatalf("Value mismatch: have %v, want 1", tx.Value())
+			}
+			sim.Commit(true)
+
+			receipt, err := bind.WaitMined(context.Background(), sim, tx)
+			if err != nil {
+				t.Fatalf("Failed to mine transaction: %v", err)
+			}
+			if receipt.Status != types.ReceiptStatusSuccessful {
+				t.Fatalf("Transaction failed: %v", receipt.Status)
+			}
+		`,
+	},
+}
+
+// bindOpts are the options with which the bindings of [bindTests] are
+// generated, if other than the defaults.
+var bindOpts = map[string]BindOpts{
+	"Warp": {Warp: true},
 }
 
 // The binding tests have been modified to run in two separate test
@@ -2143,7 +2302,7 @@ func golangBindings(t *testing.T, overload bool) {
 				types = []string{tt.name}
 			}
 			// Generate the binding and create a Go source file in the workspace
-			bind, err := Bind(types, tt.abi, tt.bytecode, tt.fsigs, "bindtest", LangGo, tt.libs, tt.aliases)
+			bind, err := BindWithOpts(types, tt.abi, tt.bytecode, tt.fsigs, "bindtest", LangGo, tt.libs, tt.aliases, bindOpts[tt.name])
 			if err != nil {
 				t.Fatalf("test %d: failed to generate binding: %v", i, err)
 			}
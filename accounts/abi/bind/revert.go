@@ -0,0 +1,53 @@
+// Copyright (C) 2019-2025, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package bind
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/MetalBlockchain/libevm/common/hexutil"
+)
+
+// dataError is an error carrying the data of a JSON-RPC error, such as the
+// revert data of a failed eth_call or eth_estimateGas.
+type dataError interface {
+	error
+	ErrorData() interface{}
+}
+
+// RevertData returns the revert data carried by [err], as returned by the
+// CallContract and EstimateGas methods of a backend when the execution is
+// reverted, and whether [err] carried any.
+func RevertData(err error) ([]byte, bool) {
+	var de dataError
+	if !errors.As(err, &de) {
+		return nil, false
+	}
+	switch data := de.ErrorData().(type) {
+	case string:
+		revert, err := hexutil.Decode(data)
+		if err != nil {
+			return nil, false
+		}
+		return revert, true
+	case hexutil.Bytes:
+		return data, true
+	case []byte:
+		return data, true
+	default:
+		return nil, false
+	}
+}
+
+// FormatError returns the message of a custom error [name] reverted with
+// [args], as generated bindings render the errors of their contract.
+func FormatError(name string, args ...interface{}) string {
+	formatted := make([]string, len(args))
+	for i, arg := range args {
+		formatted[i] = fmt.Sprintf("%v", arg)
+	}
+	return fmt.Sprintf("execution reverted: %s(%s)", name, strings.Join(formatted, ", "))
+}
@@ -0,0 +1,74 @@
+// Copyright (C) 2019-2025, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package bind
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/MetalBlockchain/libevm/common/hexutil"
+	"github.com/stretchr/testify/require"
+)
+
+type testDataError struct {
+	data interface{}
+}
+
+func (*testDataError) Error() string { return "execution reverted" }
+
+func (e *testDataError) ErrorData() interface{} { return e.data }
+
+func TestRevertData(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		want   []byte
+		wantOK bool
+	}{
+		{
+			name:   "hex string",
+			err:    &testDataError{data: "0x01020304"},
+			want:   []byte{1, 2, 3, 4},
+			wantOK: true,
+		},
+		{
+			name:   "bytes",
+			err:    &testDataError{data: hexutil.Bytes{1, 2}},
+			want:   []byte{1, 2},
+			wantOK: true,
+		},
+		{
+			name:   "wrapped",
+			err:    fmt.Errorf("call failed: %w", &testDataError{data: "0x01"}),
+			want:   []byte{1},
+			wantOK: true,
+		},
+		{
+			name: "invalid hex",
+			err:  &testDataError{data: "not hex"},
+		},
+		{
+			name: "unknown data",
+			err:  &testDataError{data: 1},
+		},
+		{
+			name: "no data",
+			err:  errors.New("execution reverted"),
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			data, ok := RevertData(test.err)
+			require.Equal(t, test.wantOK, ok)
+			require.Equal(t, test.want, data)
+		})
+	}
+}
+
+func TestFormatError(t *testing.T) {
+	require.Equal(t, "execution reverted: Unauthorized()", FormatError("Unauthorized"))
+	require.Equal(t, "execution reverted: InsufficientBalance(1, 2)", FormatError("InsufficientBalance", big.NewInt(1), uint64(2)))
+}
@@ -35,6 +35,7 @@ type tmplData struct {
 	Contracts map[string]*tmplContract // List of contracts to generate into this file
 	Libraries map[string]string        // Map the bytecode's link pattern to the library name
 	Structs   map[string]*tmplStruct   // Contract struct type definitions
+	Warp      bool                     // Whether to generate the warp message variants of the methods
 }
 
 // tmplContract contains the data needed to generate an individual contract binding.
@@ -49,6 +50,7 @@ type tmplContract struct {
 	Fallback    *tmplMethod            // Additional special fallback function
 	Receive     *tmplMethod            // Additional special receive function
 	Events      map[string]*tmplEvent  // Contract events accessors
+	Errors      map[string]*tmplError  // Contract custom errors
 	Libraries   map[string]string      // Same as tmplData, but filtered to only keep what the contract needs
 	Library     bool                   // Indicator whether the contract is a library
 }
@@ -68,6 +70,13 @@ type tmplEvent struct {
 	Normalized abi.Event // Normalized version of the parsed fields
 }
 
+// tmplError is a wrapper around an abi.Error that contains a few preprocessed
+// and cached data fields.
+type tmplError struct {
+	Original   abi.Error // Original error as parsed by the abi package
+	Normalized abi.Error // Normalized version of the parsed fields
+}
+
 // tmplField is a wrapper around a struct field with binding language
 // struct type definition and relative filed name.
 type tmplField struct {
@@ -108,6 +117,9 @@ import (
 	ethereum "github.com/MetalBlockchain/libevm"
 	"github.com/MetalBlockchain/libevm/common"
 	"github.com/MetalBlockchain/libevm/event"
+	{{- if .Warp}}
+	"github.com/MetalBlockchain/metalgo/vms/platformvm/warp"
+	{{- end}}
 )
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -364,6 +376,19 @@ var (
 		func (_{{$contract.Type}} *{{$contract.Type}}CallerSession) {{.Normalized.Name}}({{range $i, $_ := .Normalized.Inputs}}{{if ne $i 0}},{{end}} {{.Name}} {{bindtype .Type $structs}} {{end}}) ({{if .Structured}}struct{ {{range .Normalized.Outputs}}{{.Name}} {{bindtype .Type $structs}};{{end}} }, {{else}} {{range .Normalized.Outputs}}{{bindtype .Type $structs}},{{end}} {{end}} error) {
 		  return _{{$contract.Type}}.Contract.{{.Normalized.Name}}(&_{{$contract.Type}}.CallOpts {{range .Normalized.Inputs}}, {{.Name}}{{end}})
 		}
+
+		{{if $.Warp}}
+		// {{.Normalized.Name}}WithWarpMessage is a free data retrieval call binding the contract method 0x{{printf "%x" .Original.ID}},
+		// with warpMessage attached as a predicate of the warp precompile.
+		//
+		// The call is not verified by the chain, so warpMessage is returned by
+		// getVerifiedWarpMessage as valid even if it isn't signed by the source chain.
+		//
+		// Solidity: {{.Original.String}}
+		func (_{{$contract.Type}} *{{$contract.Type}}Caller) {{.Normalized.Name}}WithWarpMessage(opts *bind.CallOpts, warpMessage *warp.Message {{range .Normalized.Inputs}}, {{.Name}} {{bindtype .Type $structs}} {{end}}) ({{if .Structured}}struct{ {{range .Normalized.Outputs}}{{.Name}} {{bindtype .Type $structs}};{{end}} },{{else}}{{range .Normalized.Outputs}}{{bindtype .Type $structs}},{{end}}{{end}} error) {
+			return _{{$contract.Type}}.{{.Normalized.Name}}(opts.WithWarpMessage(warpMessage) {{range .Normalized.Inputs}}, {{.Name}}{{end}})
+		}
+		{{end}}
 	{{end}}
 
 	{{range .Transacts}}
@@ -387,6 +412,40 @@ var (
 		func (_{{$contract.Type}} *{{$contract.Type}}TransactorSession) {{.Normalized.Name}}({{range $i, $_ := .Normalized.Inputs}}{{if ne $i 0}},{{end}} {{.Name}} {{bindtype .Type $structs}} {{end}}) (*types.Transaction, error) {
 		  return _{{$contract.Type}}.Contract.{{.Normalized.Name}}(&_{{$contract.Type}}.TransactOpts {{range $i, $_ := .Normalized.Inputs}}, {{.Name}}{{end}})
 		}
+
+		{{if $.Warp}}
+		// {{.Normalized.Name}}WithWarpMessage is a paid mutator transaction binding the contract method 0x{{printf "%x" .Original.ID}},
+		// with warpMessage attached as a predicate of the warp precompile.
+		//
+		// Solidity: {{.Original.String}}
+		func (_{{$contract.Type}} *{{$contract.Type}}Transactor) {{.Normalized.Name}}WithWarpMessage(opts *bind.TransactOpts, warpMessage *warp.Message {{range .Normalized.Inputs}}, {{.Name}} {{bindtype .Type $structs}} {{end}}) (*types.Transaction, error) {
+			return _{{$contract.Type}}.{{.Normalized.Name}}(opts.WithWarpMessage(warpMessage) {{range .Normalized.Inputs}}, {{.Name}}{{end}})
+		}
+
+		// Estimate{{.Normalized.Name}}WithWarpMessage estimates the gas of a paid mutator transaction binding the contract method 0x{{printf "%x" .Original.ID}},
+		// with warpMessage attached as a predicate of the warp precompile.
+		//
+		// The estimate is not verified by the chain, so warpMessage is returned by
+		// getVerifiedWarpMessage as valid even if it isn't signed by the source chain.
+		//
+		// Solidity: {{.Original.String}}
+		func (_{{$contract.Type}} *{{$contract.Type}}Transactor) Estimate{{.Normalized.Name}}WithWarpMessage(opts *bind.TransactOpts, warpMessage *warp.Message {{range .Normalized.Inputs}}, {{.Name}} {{bindtype .Type $structs}} {{end}}) (uint64, error) {
+			return _{{$contract.Type}}.contract.EstimateGas(opts.WithWarpMessage(warpMessage), "{{.Original.Name}}" {{range .Normalized.Inputs}}, {{.Name}}{{end}})
+		}
+
+		// Call{{.Normalized.Name}}WithWarpMessage simulates a paid mutator transaction binding the contract method 0x{{printf "%x" .Original.ID}},
+		// with warpMessage attached as a predicate of the warp precompile and opts.Value
+		// transferred, returning the error the transaction would be reverted with.
+		//
+		// The simulation is not verified by the chain, so warpMessage is returned by
+		// getVerifiedWarpMessage as valid even if it isn't signed by the source chain.
+		//
+		// Solidity: {{.Original.String}}
+		func (_{{$contract.Type}} *{{$contract.Type}}Caller) Call{{.Normalized.Name}}WithWarpMessage(opts *bind.CallOpts, warpMessage *warp.Message {{range .Normalized.Inputs}}, {{.Name}} {{bindtype .Type $structs}} {{end}}) error {
+			var out []interface{}
+			return _{{$contract.Type}}.contract.Call(opts.WithWarpMessage(warpMessage), &out, "{{.Original.Name}}" {{range .Normalized.Inputs}}, {{.Name}}{{end}})
+		}
+		{{end}}
 	{{end}}
 
 	{{if .Fallback}}
@@ -578,5 +637,64 @@ var (
 		}
 
 	{{end}}
+
+	{{range .Errors}}
+		// {{$contract.Type}}{{.Normalized.Name}} represents a {{.Normalized.Name}} error raised by the {{$contract.Type}} contract.
+		//
+		// Solidity: {{.Original.String}}
+		type {{$contract.Type}}{{.Normalized.Name}} struct { {{range .Normalized.Inputs}}
+			{{capitalise .Name}} {{bindtype .Type $structs}}; {{end}}
+		}
+
+		// Error implements the error interface.
+		func (e *{{$contract.Type}}{{.Normalized.Name}}) Error() string {
+			return bind.FormatError("{{.Original.Name}}"{{range .Normalized.Inputs}}, e.{{capitalise .Name}}{{end}})
+		}
+	{{end}}
+
+	{{if .Errors}}
+		// Unpack{{.Type}}Error returns the custom error of the {{.Type}} contract the
+		// execution returning err was reverted with, as returned by CallContract and
+		// EstimateGas, and whether err was reverted with one.
+		func Unpack{{.Type}}Error(err error) (error, bool) {
+			data, ok := bind.RevertData(err)
+			if !ok {
+				return nil, false
+			}
+			return Unpack{{.Type}}ErrorData(data)
+		}
+
+		// Unpack{{.Type}}ErrorData returns the custom error of the {{.Type}} contract
+		// encoded in the revert data of an execution, such as the output of a traced
+		// failed transaction, and whether data encodes one.
+		func Unpack{{.Type}}ErrorData(data []byte) (error, bool) {
+			if len(data) < 4 {
+				return nil, false
+			}
+			parsed, err := {{.Type}}MetaData.GetAbi()
+			if err != nil {
+				return nil, false
+			}
+			abiErr, err := parsed.ErrorByID([4]byte(data[:4]))
+			if err != nil {
+				return nil, false
+			}
+			switch abiErr.Name {
+			{{range .Errors}}
+			case "{{.Original.Name}}":
+				{{if .Normalized.Inputs -}}
+				out, err := abiErr.Inputs.Unpack(data[4:])
+				if err != nil {
+					return nil, false
+				}
+				{{end -}}
+				return &{{$contract.Type}}{{.Normalized.Name}}{ {{range $i, $_ := .Normalized.Inputs}}
+					{{capitalise .Name}}: *abi.ConvertType(out[{{$i}}], new({{bindtype .Type $structs}})).(*{{bindtype .Type $structs}}),{{end}}
+				}, true
+			{{end}}
+			}
+			return nil, false
+		}
+	{{end}}
 {{end}}
 `
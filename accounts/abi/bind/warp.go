@@ -0,0 +1,46 @@
+// Copyright (C) 2019-2025, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package bind
+
+import (
+	"slices"
+
+	"github.com/MetalBlockchain/libevm/core/types"
+	"github.com/MetalBlockchain/metalgo/vms/evm/predicate"
+	"github.com/MetalBlockchain/metalgo/vms/platformvm/warp"
+
+	warpcontract "github.com/MetalBlockchain/coreth/precompile/contracts/warp"
+)
+
+// WarpPredicate returns the access tuple attaching [message] to a transaction
+// as a predicate of the warp precompile. The predicates of a transaction are
+// read by getVerifiedWarpMessage in the order of its access list.
+func WarpPredicate(message *warp.Message) types.AccessTuple {
+	return types.AccessTuple{
+		Address:     warpcontract.ContractAddress,
+		StorageKeys: predicate.New(message.Bytes()),
+	}
+}
+
+// WithWarpMessage returns a copy of opts with [message] appended to its access
+// list as a warp predicate.
+func (opts *TransactOpts) WithWarpMessage(message *warp.Message) *TransactOpts {
+	cpy := *opts
+	cpy.AccessList = append(slices.Clone(opts.AccessList), WarpPredicate(message))
+	return &cpy
+}
+
+// WithWarpMessage returns a copy of opts with [message] appended to its access
+// list as a warp predicate. A nil opts is treated as the default options.
+//
+// Predicates are only verified for transactions included in a block, so the
+// call is executed as if [message] was signed by its source chain.
+func (opts *CallOpts) WithWarpMessage(message *warp.Message) *CallOpts {
+	cpy := new(CallOpts)
+	if opts != nil {
+		*cpy = *opts
+	}
+	cpy.AccessList = append(slices.Clone(cpy.AccessList), WarpPredicate(message))
+	return cpy
+}
@@ -0,0 +1,98 @@
+// Copyright (C) 2019-2025, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package bind_test
+
+import (
+	"context"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/MetalBlockchain/libevm/common"
+	"github.com/MetalBlockchain/libevm/core/types"
+	"github.com/MetalBlockchain/libevm/core/vm"
+	"github.com/MetalBlockchain/libevm/crypto"
+	"github.com/MetalBlockchain/metalgo/ids"
+	"github.com/stretchr/testify/require"
+
+	"github.com/MetalBlockchain/coreth/accounts/abi"
+	"github.com/MetalBlockchain/coreth/accounts/abi/bind"
+	"github.com/MetalBlockchain/coreth/accounts/abi/bind/backends"
+	"github.com/MetalBlockchain/coreth/ethclient/simulated"
+)
+
+const warpReceiverABI = `[{"inputs":[],"name":"receiveMessage","outputs":[],"stateMutability":"nonpayable","type":"function"}]`
+
+func TestTransactWithWarpMessage(t *testing.T) {
+	require := require.New(t)
+	key, err := crypto.GenerateKey()
+	require.NoError(err)
+	auth, err := bind.NewKeyedTransactorWithChainID(key, big.NewInt(1337))
+	require.NoError(err)
+	receiver := common.Address{0x01, 0x02}
+	b := simulated.NewBackend(types.GenesisAlloc{
+		auth.From: {Balance: big.NewInt(1000000000000000000)},
+		receiver:  {Code: []byte{byte(vm.STOP)}},
+	})
+	sim := &backends.SimulatedBackend{
+		Backend: b,
+		Client:  b.Client(),
+	}
+	defer sim.Close()
+
+	unsignedMessage, err := b.NewWarpMessage(ids.GenerateTestID(), common.Address{0xaa}, []byte("hello"))
+	require.NoError(err)
+	message, err := b.SignWarpMessage(unsignedMessage)
+	require.NoError(err)
+
+	parsedABI, err := abi.JSON(strings.NewReader(warpReceiverABI))
+	require.NoError(err)
+	contract := bind.NewBoundContract(receiver, parsedABI, sim, sim, sim)
+
+	// The predicate is charged for when estimating gas.
+	gas, err := contract.EstimateGas(auth, "receiveMessage")
+	require.NoError(err)
+	warpGas, err := contract.EstimateGas(auth.WithWarpMessage(message), "receiveMessage")
+	require.NoError(err)
+	require.Greater(warpGas, gas)
+
+	var out []interface{}
+	require.NoError(contract.Call((*bind.CallOpts)(nil).WithWarpMessage(message), &out, "receiveMessage"))
+
+	tx, err := contract.Transact(auth.WithWarpMessage(message), "receiveMessage")
+	require.NoError(err)
+	require.Equal(types.AccessList{bind.WarpPredicate(message)}, tx.AccessList())
+	require.Empty(auth.AccessList)
+	sim.Commit(true)
+
+	receipt, err := bind.WaitMined(context.Background(), sim, tx)
+	require.NoError(err)
+	require.Equal(types.ReceiptStatusSuccessful, receipt.Status)
+}
+
+func TestBindWarp(t *testing.T) {
+	const contractABI = `[
+		{"inputs":[],"name":"received","outputs":[{"internalType":"bool","name":"","type":"bool"}],"stateMutability":"view","type":"function"},
+		{"inputs":[{"internalType":"uint32","name":"index","type":"uint32"}],"name":"receiveMessage","outputs":[],"stateMutability":"nonpayable","type":"function"}
+	]`
+	contractTypes := []string{"Receiver"}
+	abis := []string{contractABI}
+
+	code, err := bind.Bind(contractTypes, abis, []string{""}, nil, "bindtest", bind.LangGo, nil, nil)
+	require.NoError(t, err)
+	require.NotContains(t, code, "WithWarpMessage")
+	require.NotContains(t, code, "platformvm/warp")
+
+	code, err = bind.BindWithOpts(contractTypes, abis, []string{""}, nil, "bindtest", bind.LangGo, nil, nil, bind.BindOpts{Warp: true})
+	require.NoError(t, err)
+	require.Contains(t, code, `"github.com/MetalBlockchain/metalgo/vms/platformvm/warp"`)
+	for _, want := range []string{
+		"func (_Receiver *ReceiverCaller) ReceivedWithWarpMessage(opts *bind.CallOpts, warpMessage *warp.Message) (bool, error)",
+		"func (_Receiver *ReceiverTransactor) ReceiveMessageWithWarpMessage(opts *bind.TransactOpts, warpMessage *warp.Message, index uint32) (*types.Transaction, error)",
+		"func (_Receiver *ReceiverTransactor) EstimateReceiveMessageWithWarpMessage(opts *bind.TransactOpts, warpMessage *warp.Message, index uint32) (uint64, error)",
+		"func (_Receiver *ReceiverCaller) CallReceiveMessageWithWarpMessage(opts *bind.CallOpts, warpMessage *warp.Message, index uint32) error",
+	} {
+		require.Contains(t, code, want)
+	}
+}
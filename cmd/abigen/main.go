@@ -83,6 +83,10 @@ var (
 		Name:  "alias",
 		Usage: "Comma separated aliases for function and event renaming, e.g. original1=alias1, original2=alias2",
 	}
+	warpFlag = &cli.BoolFlag{
+		Name:  "warp",
+		Usage: "Generate variants of the methods attaching a warp message to their transactions and calls",
+	}
 )
 
 var app = flags.NewApp("Ethereum ABI wrapper code generator")
@@ -99,6 +103,7 @@ func init() {
 		outFlag,
 		langFlag,
 		aliasFlag,
+		warpFlag,
 	}
 	app.Action = abigen
 }
@@ -227,7 +232,10 @@ func abigen(c *cli.Context) error {
 		}
 	}
 	// Generate the contract binding
-	code, err := bind.Bind(types, abis, bins, sigs, c.String(pkgFlag.Name), lang, libs, aliases)
+	opts := bind.BindOpts{
+		Warp: c.Bool(warpFlag.Name),
+	}
+	code, err := bind.BindWithOpts(types, abis, bins, sigs, c.String(pkgFlag.Name), lang, libs, aliases, opts)
 	if err != nil {
 		utils.Fatalf("Failed to generate ABI binding: %v", err)
 	}
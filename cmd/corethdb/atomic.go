@@ -0,0 +1,67 @@
+// Copyright (C) 2019-2025, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package main
+
+import (
+	"encoding/json"
+	"errors"
+
+	"github.com/MetalBlockchain/metalgo/database/versiondb"
+	"github.com/urfave/cli/v2"
+
+	"github.com/MetalBlockchain/coreth/plugin/evm/atomic"
+	"github.com/MetalBlockchain/coreth/plugin/evm/customrawdb"
+
+	atomicstate "github.com/MetalBlockchain/coreth/plugin/evm/atomic/state"
+)
+
+var errAtomicTrieMismatch = errors.New("atomic trie does not match the atomic tx index")
+
+func verifyAtomicTrie(c *cli.Context) error {
+	db, err := openVMDatabase(c)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	_, lastAcceptedHeight, ok, err := db.lastAccepted()
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return errNoAcceptedBlock
+	}
+	// The VM opens the atomic repository on a versiondb wrapping its database,
+	// which the repository commits as it writes.
+	repo, err := atomicstate.NewAtomicTxRepository(versiondb.New(db.vmDB), atomic.Codec, lastAcceptedHeight)
+	if err != nil {
+		return err
+	}
+
+	config := atomicstate.VerifyConfig{
+		CommitInterval: c.Uint64(commitIntervalFlag.Name),
+		Repair:         c.Bool(repairFlag.Name),
+	}
+	if c.IsSet(startHeightFlag.Name) {
+		config.StartHeight = c.Uint64(startHeightFlag.Name)
+	} else {
+		// The atomic tx index does not contain the atomic txs accepted before
+		// the last state sync.
+		config.StartHeight = customrawdb.GetLatestSyncPerformed(db.chainDB)
+	}
+	result, err := atomicstate.VerifyAtomicTrie(repo, config)
+	if err != nil {
+		return err
+	}
+
+	encoder := json.NewEncoder(c.App.Writer)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(result); err != nil {
+		return err
+	}
+	if !result.OK() && !result.Repaired {
+		return errAtomicTrieMismatch
+	}
+	return nil
+}
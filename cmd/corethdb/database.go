@@ -0,0 +1,145 @@
+// Copyright (C) 2019-2025, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package main
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/MetalBlockchain/metalgo/database/leveldb"
+	"github.com/MetalBlockchain/metalgo/database/pebbledb"
+	"github.com/MetalBlockchain/metalgo/database/prefixdb"
+	"github.com/MetalBlockchain/metalgo/ids"
+	"github.com/MetalBlockchain/metalgo/utils/logging"
+	"github.com/MetalBlockchain/libevm/common"
+	"github.com/MetalBlockchain/libevm/core/rawdb"
+	"github.com/MetalBlockchain/libevm/ethdb"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/urfave/cli/v2"
+
+	"github.com/MetalBlockchain/coreth/plugin/evm/database"
+
+	avalanchedatabase "github.com/MetalBlockchain/metalgo/database"
+)
+
+// The layout of the VM database, which must match the prefixes used by the
+// VM and the atomic repository.
+var (
+	// vmDBPrefix is the prefix under which the node stores the database of
+	// each VM, within the prefix of the chain ID.
+	vmDBPrefix = []byte("vm")
+
+	ethDBPrefix     = []byte("ethdb")
+	acceptedPrefix  = []byte("snowman_accepted")
+	metadataPrefix  = []byte("metadata")
+	warpPrefix      = []byte("warp")
+	lastAcceptedKey = []byte("last_accepted_key")
+	// stateSyncSummaryKey is the key of the summary of an in-progress state
+	// sync in the metadata database.
+	stateSyncSummaryKey = []byte("stateSyncSummary")
+
+	atomicTxIDDBPrefix         = []byte("atomicTxDB")
+	atomicHeightTxDBPrefix     = []byte("atomicHeightTxDB")
+	atomicRepoMetadataDBPrefix = []byte("atomicRepoMetadataDB")
+	atomicTrieDBPrefix         = []byte("atomicTrieDB")
+	atomicTrieMetaDBPrefix     = []byte("atomicTrieMetaDB")
+
+	atomicTrieLastCommittedKey  = []byte("atomicTrieLastCommittedBlock")
+	atomicSharedMemoryCursorKey = []byte("atomicTrieLastAppliedToSharedMemory")
+	atomicMaxIndexedHeightKey   = []byte("maxIndexedAtomicTxHeight")
+)
+
+var (
+	errInvalidLastAccepted       = errors.New("invalid last accepted block hash")
+	errMissingLastAcceptedHeader = errors.New("header of the last accepted block is missing")
+)
+
+// vmDatabase holds the databases of the VM of a chain.
+type vmDatabase struct {
+	base    avalanchedatabase.Database
+	vmDB    avalanchedatabase.Database
+	chainDB ethdb.Database
+
+	acceptedBlockDB avalanchedatabase.Database
+	metadataDB      avalanchedatabase.Database
+	warpDB          avalanchedatabase.Database
+
+	atomicTxDB           avalanchedatabase.Database
+	atomicHeightTxDB     avalanchedatabase.Database
+	atomicRepoMetadataDB avalanchedatabase.Database
+	atomicTrieDB         avalanchedatabase.Database
+	atomicTrieMetaDB     avalanchedatabase.Database
+}
+
+func openVMDatabase(c *cli.Context) (*vmDatabase, error) {
+	chainID, err := ids.FromString(c.String(chainIDFlag.Name))
+	if err != nil {
+		return nil, fmt.Errorf("invalid chain ID: %w", err)
+	}
+	base, err := openBaseDatabase(c.String(dbTypeFlag.Name), c.String(dbFlag.Name))
+	if err != nil {
+		return nil, err
+	}
+	return newVMDatabase(base, chainID), nil
+}
+
+// newVMDatabase returns the databases of the VM of the chain with [chainID]
+// in the node database [base].
+func newVMDatabase(base avalanchedatabase.Database, chainID ids.ID) *vmDatabase {
+	vmDB := prefixdb.New(vmDBPrefix, prefixdb.New(chainID[:], base))
+	// The VM opens most of its databases on a versiondb wrapping [vmDB], so
+	// their prefixes are nested rather than compressed with the prefix of
+	// [vmDB]. Only the warp database is opened on [vmDB] directly.
+	return &vmDatabase{
+		base:                 base,
+		vmDB:                 vmDB,
+		chainDB:              rawdb.NewDatabase(database.WrapDatabase(prefixdb.NewNested(ethDBPrefix, vmDB))),
+		acceptedBlockDB:      prefixdb.NewNested(acceptedPrefix, vmDB),
+		metadataDB:           prefixdb.NewNested(metadataPrefix, vmDB),
+		warpDB:               prefixdb.New(warpPrefix, vmDB),
+		atomicTxDB:           prefixdb.NewNested(atomicTxIDDBPrefix, vmDB),
+		atomicHeightTxDB:     prefixdb.NewNested(atomicHeightTxDBPrefix, vmDB),
+		atomicRepoMetadataDB: prefixdb.NewNested(atomicRepoMetadataDBPrefix, vmDB),
+		atomicTrieDB:         prefixdb.NewNested(atomicTrieDBPrefix, vmDB),
+		atomicTrieMetaDB:     prefixdb.NewNested(atomicTrieMetaDBPrefix, vmDB),
+	}
+}
+
+func (db *vmDatabase) Close() error {
+	return db.base.Close()
+}
+
+// lastAccepted returns the hash and height of the last accepted block, or
+// false if the VM has not accepted a block yet.
+func (db *vmDatabase) lastAccepted() (common.Hash, uint64, bool, error) {
+	hashBytes, err := db.acceptedBlockDB.Get(lastAcceptedKey)
+	switch {
+	case errors.Is(err, avalanchedatabase.ErrNotFound):
+		return common.Hash{}, 0, false, nil
+	case err != nil:
+		return common.Hash{}, 0, false, err
+	case len(hashBytes) != common.HashLength:
+		return common.Hash{}, 0, false, fmt.Errorf("%w: %x", errInvalidLastAccepted, hashBytes)
+	}
+	hash := common.BytesToHash(hashBytes)
+	height := rawdb.ReadHeaderNumber(db.chainDB, hash)
+	if height == nil {
+		return common.Hash{}, 0, false, fmt.Errorf("%w: %s", errMissingLastAcceptedHeader, hash)
+	}
+	return hash, *height, true, nil
+}
+
+// openBaseDatabase opens the node database, and is replaced in tests.
+var openBaseDatabase = openDatabase
+
+func openDatabase(dbType string, path string) (avalanchedatabase.Database, error) {
+	switch dbType {
+	case leveldb.Name:
+		return leveldb.New(path, nil, logging.NoLog{}, prometheus.NewRegistry())
+	case pebbledb.Name:
+		return pebbledb.New(path, nil, logging.NoLog{}, prometheus.NewRegistry())
+	default:
+		return nil, fmt.Errorf("unknown database type %q", dbType)
+	}
+}
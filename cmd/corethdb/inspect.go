@@ -0,0 +1,333 @@
+// Copyright (C) 2019-2025, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/MetalBlockchain/libevm/common"
+	"github.com/MetalBlockchain/libevm/core/rawdb"
+	"github.com/MetalBlockchain/libevm/ethdb"
+	"github.com/MetalBlockchain/libevm/log"
+	"github.com/urfave/cli/v2"
+
+	"github.com/MetalBlockchain/coreth/plugin/evm/customrawdb"
+
+	avalanchedatabase "github.com/MetalBlockchain/metalgo/database"
+)
+
+// Prefixes of the chain database schema of libevm, which are not exported.
+var (
+	headerPrefix       = []byte("h") // headerPrefix + num (uint64 big endian) + hash -> header
+	headerHashSuffix   = []byte("n") // headerPrefix + num (uint64 big endian) + headerHashSuffix -> hash
+	headerNumberPrefix = []byte("H") // headerNumberPrefix + hash -> num (uint64 big endian)
+	blockBodyPrefix    = []byte("b") // blockBodyPrefix + num (uint64 big endian) + hash -> block body
+	receiptsPrefix     = []byte("r") // receiptsPrefix + num (uint64 big endian) + hash -> block receipts
+	txLookupPrefix     = []byte("l") // txLookupPrefix + hash -> transaction lookup metadata
+	bloomBitsPrefix    = []byte("B") // bloomBitsPrefix + bit (uint16 big endian) + section (uint64 big endian) + hash -> bloom bits
+	stateIDPrefix      = []byte("L") // stateIDPrefix + state root -> state id
+
+	// metadataKeys are the singleton keys of libevm.
+	metadataKeys = []string{
+		"DatabaseVersion", "LastHeader", "LastBlock", "LastFast", "LastFinalized",
+		"LastPivot", "TrieSync", "SnapshotDisabled", "SnapshotRoot", "SnapshotJournal",
+		"SnapshotGenerator", "SnapshotRecovery", "SnapshotSyncStatus", "SnapSyncStatus",
+		"TransactionIndexTail", "FastTransactionLookupLimit", "unclean-shutdown",
+		"InvalidBlock", "eth2-transition", "SkeletonSyncStatus", "LastStateID", "TrieJournal",
+	}
+)
+
+// categoryStat is the total size and number of the entries of a category of
+// data.
+type categoryStat struct {
+	Database string             `json:"database"`
+	Category string             `json:"category"`
+	Size     common.StorageSize `json:"size"`
+	Count    uint64             `json:"count"`
+}
+
+func (s *categoryStat) add(key, value []byte) {
+	s.Size += common.StorageSize(len(key) + len(value))
+	s.Count++
+}
+
+// blockMarker identifies a block recorded by a marker.
+type blockMarker struct {
+	Hash   common.Hash `json:"hash"`
+	Height *uint64     `json:"height,omitempty"`
+}
+
+// markers are the markers that determine how the node resumes on startup.
+type markers struct {
+	StateScheme       string                             `json:"stateScheme"`
+	LastAccepted      *blockMarker                       `json:"lastAccepted,omitempty"`
+	AcceptorTip       *blockMarker                       `json:"acceptorTip,omitempty"`
+	HeadBlock         *blockMarker                       `json:"headBlock,omitempty"`
+	SnapshotBlock     *blockMarker                       `json:"snapshotBlock,omitempty"`
+	SnapshotRoot      *common.Hash                       `json:"snapshotRoot,omitempty"`
+	TxIndexTail       *uint64                            `json:"txIndexTail,omitempty"`
+	OfflinePruning    *time.Time                         `json:"offlinePruning,omitempty"`
+	PruningDisabled   bool                               `json:"pruningDisabled"`
+	SyncPerformed     []uint64                           `json:"syncPerformed,omitempty"`
+	SyncSummary       bool                               `json:"syncSummary"`
+	SyncRoot          *common.Hash                       `json:"syncRoot,omitempty"`
+	SyncCodeToFetch   uint64                             `json:"syncCodeToFetch"`
+	BlockBackfill     *customrawdb.BlockBackfillProgress `json:"blockBackfill,omitempty"`
+	AtomicTrieCommit  *blockMarker                       `json:"atomicTrieCommit,omitempty"`
+	AtomicTxIndexed   *uint64                            `json:"atomicTxIndexed,omitempty"`
+	AtomicApplyCursor bool                               `json:"atomicApplyCursor"`
+}
+
+type inspectReport struct {
+	Categories []*categoryStat    `json:"categories"`
+	Total      common.StorageSize `json:"total"`
+	Markers    markers            `json:"markers"`
+}
+
+func inspect(c *cli.Context) error {
+	db, err := openVMDatabase(c)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	report := &inspectReport{}
+	report.Categories, err = inspectChainDB(db.chainDB)
+	if err != nil {
+		return err
+	}
+	for _, sub := range []struct {
+		database, category string
+		db                 avalanchedatabase.Database
+	}{
+		{"VM", "Last accepted block", db.acceptedBlockDB},
+		{"VM", "Metadata", db.metadataDB},
+		{"Warp", "Messages and signatures", db.warpDB},
+		{"Atomic repository", "Transactions by ID", db.atomicTxDB},
+		{"Atomic repository", "Transactions by height", db.atomicHeightTxDB},
+		{"Atomic repository", "Metadata", db.atomicRepoMetadataDB},
+		{"Atomic trie", "Trie nodes", db.atomicTrieDB},
+		{"Atomic trie", "Metadata", db.atomicTrieMetaDB},
+	} {
+		stat, err := inspectDB(sub.db, sub.database, sub.category)
+		if err != nil {
+			return err
+		}
+		report.Categories = append(report.Categories, stat)
+	}
+	for _, stat := range report.Categories {
+		report.Total += stat.Size
+	}
+	if err := readMarkers(db, &report.Markers); err != nil {
+		return err
+	}
+
+	encoder := json.NewEncoder(c.App.Writer)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(report)
+}
+
+// inspectChainDB returns the size of each category of data in [db].
+func inspectChainDB(db ethdb.Database) ([]*categoryStat, error) {
+	var (
+		stats   []*categoryStat
+		byName  = make(map[string]*categoryStat)
+		statFor = func(database, category string) *categoryStat {
+			stat, ok := byName[database+category]
+			if !ok {
+				stat = &categoryStat{Database: database, Category: category}
+				byName[database+category] = stat
+				stats = append(stats, stat)
+			}
+			return stat
+		}
+		count  uint64
+		start  = time.Now()
+		logged = time.Now()
+	)
+	it := db.NewIterator(nil, nil)
+	defer it.Release()
+	for it.Next() {
+		key, value := it.Key(), it.Value()
+		if category, ok := customrawdb.ExtKeyCategory(key); ok {
+			statFor("Coreth", category).add(key, value)
+		} else {
+			statFor("Key-Value store", chainDBCategory(key, value)).add(key, value)
+		}
+		count++
+		if count%1000 == 0 && time.Since(logged) > 8*time.Second {
+			log.Info("Inspecting chain database", "count", count, "elapsed", common.PrettyDuration(time.Since(start)))
+			logged = time.Now()
+		}
+	}
+	return stats, it.Error()
+}
+
+// chainDBCategory returns the category of a key of the libevm schema.
+func chainDBCategory(key, value []byte) string {
+	const numberLength = 8
+	switch {
+	case bytes.HasPrefix(key, headerPrefix) && len(key) == len(headerPrefix)+numberLength+common.HashLength:
+		return "Headers"
+	case bytes.HasPrefix(key, headerPrefix) && bytes.HasSuffix(key, headerHashSuffix) && len(key) == len(headerPrefix)+numberLength+len(headerHashSuffix):
+		return "Block number->hash"
+	case bytes.HasPrefix(key, headerNumberPrefix) && len(key) == len(headerNumberPrefix)+common.HashLength:
+		return "Block hash->number"
+	case bytes.HasPrefix(key, blockBodyPrefix) && len(key) == len(blockBodyPrefix)+numberLength+common.HashLength:
+		return "Bodies"
+	case bytes.HasPrefix(key, receiptsPrefix) && len(key) == len(receiptsPrefix)+numberLength+common.HashLength:
+		return "Receipt lists"
+	case bytes.HasPrefix(key, txLookupPrefix) && len(key) == len(txLookupPrefix)+common.HashLength:
+		return "Transaction index"
+	case bytes.HasPrefix(key, bloomBitsPrefix) && len(key) == len(bloomBitsPrefix)+2+numberLength+common.HashLength:
+		return "Bloombit index"
+	case bytes.HasPrefix(key, stateIDPrefix) && len(key) == len(stateIDPrefix)+common.HashLength:
+		return "Path trie state lookups"
+	case bytes.HasPrefix(key, rawdb.PreimagePrefix) && len(key) == len(rawdb.PreimagePrefix)+common.HashLength:
+		return "Trie preimages"
+	case bytes.HasPrefix(key, rawdb.SnapshotAccountPrefix) && len(key) == len(rawdb.SnapshotAccountPrefix)+common.HashLength:
+		return "Account snapshot"
+	case bytes.HasPrefix(key, rawdb.SnapshotStoragePrefix) && len(key) == len(rawdb.SnapshotStoragePrefix)+2*common.HashLength:
+		return "Storage snapshot"
+	}
+	if ok, _ := rawdb.IsCodeKey(key); ok {
+		return "Contract codes"
+	}
+	if rawdb.IsLegacyTrieNode(key, value) {
+		return "Hash trie nodes"
+	}
+	if ok, _ := rawdb.IsAccountTrieNode(key); ok {
+		return "Path trie account nodes"
+	}
+	if ok, _, _ := rawdb.IsStorageTrieNode(key); ok {
+		return "Path trie storage nodes"
+	}
+	for _, metadataKey := range metadataKeys {
+		if string(key) == metadataKey {
+			return "Singleton metadata"
+		}
+	}
+	return "Unaccounted"
+}
+
+// inspectDB returns the size of all of the data in [db].
+func inspectDB(db avalanchedatabase.Database, database, category string) (*categoryStat, error) {
+	stat := &categoryStat{Database: database, Category: category}
+	it := db.NewIterator()
+	defer it.Release()
+	for it.Next() {
+		stat.add(it.Key(), it.Value())
+	}
+	return stat, it.Error()
+}
+
+func readMarkers(db *vmDatabase, m *markers) error {
+	chainDB := db.chainDB
+	m.StateScheme = rawdb.ReadStateScheme(chainDB)
+
+	hash, height, ok, err := db.lastAccepted()
+	if err != nil {
+		return err
+	}
+	if ok {
+		m.LastAccepted = &blockMarker{Hash: hash, Height: &height}
+	}
+	acceptorTip, err := customrawdb.ReadAcceptorTip(chainDB)
+	if err != nil {
+		return err
+	}
+	m.AcceptorTip = newBlockMarker(chainDB, acceptorTip)
+	m.HeadBlock = newBlockMarker(chainDB, rawdb.ReadHeadBlockHash(chainDB))
+	m.SnapshotBlock = newBlockMarker(chainDB, customrawdb.ReadSnapshotBlockHash(chainDB))
+	if root := rawdb.ReadSnapshotRoot(chainDB); root != (common.Hash{}) {
+		m.SnapshotRoot = &root
+	}
+	m.TxIndexTail = rawdb.ReadTxIndexTail(chainDB)
+
+	// Reading the marker fails if offline pruning never ran.
+	offlinePruning, err := customrawdb.ReadOfflinePruning(chainDB)
+	if err == nil {
+		m.OfflinePruning = &offlinePruning
+	}
+	if m.PruningDisabled, err = customrawdb.HasPruningDisabled(chainDB); err != nil {
+		return err
+	}
+
+	syncPerformed := customrawdb.NewSyncPerformedIterator(chainDB)
+	for syncPerformed.Next() {
+		m.SyncPerformed = append(m.SyncPerformed, customrawdb.UnpackSyncPerformedKey(syncPerformed.Key()))
+	}
+	syncPerformed.Release()
+	if err := syncPerformed.Error(); err != nil {
+		return err
+	}
+	if m.SyncSummary, err = db.metadataDB.Has(stateSyncSummaryKey); err != nil {
+		return err
+	}
+	syncRoot, err := customrawdb.ReadSyncRoot(chainDB)
+	if err != nil {
+		return err
+	}
+	if syncRoot != (common.Hash{}) {
+		m.SyncRoot = &syncRoot
+	}
+	codeToFetch := customrawdb.NewCodeToFetchIterator(chainDB)
+	for codeToFetch.Next() {
+		m.SyncCodeToFetch++
+	}
+	codeToFetch.Release()
+	if err := codeToFetch.Error(); err != nil {
+		return err
+	}
+	if m.BlockBackfill, err = customrawdb.ReadBlockBackfillProgress(chainDB); err != nil {
+		return err
+	}
+
+	// The last committed height of the atomic trie maps to its root.
+	heightBytes, err := db.atomicTrieMetaDB.Get(atomicTrieLastCommittedKey)
+	switch {
+	case errors.Is(err, avalanchedatabase.ErrNotFound):
+	case err != nil:
+		return err
+	default:
+		height, err := avalanchedatabase.ParseUInt64(heightBytes)
+		if err != nil {
+			return err
+		}
+		root, err := db.atomicTrieMetaDB.Get(heightBytes)
+		if err != nil {
+			return err
+		}
+		m.AtomicTrieCommit = &blockMarker{Hash: common.BytesToHash(root), Height: &height}
+	}
+	indexedBytes, err := db.atomicRepoMetadataDB.Get(atomicMaxIndexedHeightKey)
+	switch {
+	case errors.Is(err, avalanchedatabase.ErrNotFound):
+	case err != nil:
+		return err
+	default:
+		// The height is only written once indexing completes. Until then, the
+		// key holds the ID of the last indexed transaction.
+		if indexed, err := avalanchedatabase.ParseUInt64(indexedBytes); err == nil {
+			m.AtomicTxIndexed = &indexed
+		}
+	}
+	m.AtomicApplyCursor, err = db.atomicTrieMetaDB.Has(atomicSharedMemoryCursorKey)
+	return err
+}
+
+// newBlockMarker returns the marker of the block with [hash], with its height
+// if the block is known, or nil if [hash] is empty.
+func newBlockMarker(db ethdb.KeyValueReader, hash common.Hash) *blockMarker {
+	if hash == (common.Hash{}) {
+		return nil
+	}
+	return &blockMarker{
+		Hash:   hash,
+		Height: rawdb.ReadHeaderNumber(db, hash),
+	}
+}
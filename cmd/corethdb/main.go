@@ -0,0 +1,133 @@
+// Copyright (C) 2019-2025, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// corethdb inspects and repairs the database of a stopped node, without
+// starting the VM.
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/MetalBlockchain/libevm/log"
+	"github.com/urfave/cli/v2"
+
+	"github.com/MetalBlockchain/coreth/internal/flags"
+)
+
+var (
+	dbFlag = &cli.StringFlag{
+		Name:     "db",
+		Usage:    "Path to the node database (the node must be stopped)",
+		Required: true,
+	}
+	dbTypeFlag = &cli.StringFlag{
+		Name:  "db-type",
+		Usage: "Type of the node database (leveldb or pebbledb)",
+		Value: "leveldb",
+	}
+	chainIDFlag = &cli.StringFlag{
+		Name:     "chain-id",
+		Usage:    "Blockchain ID of the chain, used to locate the chain in the node database",
+		Required: true,
+	}
+	rootFlag = &cli.StringFlag{
+		Name:  "root",
+		Usage: "State root to read (defaults to the root of the last accepted block)",
+	}
+	addressFlag = &cli.StringFlag{
+		Name:     "address",
+		Usage:    "Address of the account",
+		Required: true,
+	}
+	storageFlag = &cli.BoolFlag{
+		Name:  "storage",
+		Usage: "Include the storage slots of the account, by hashed key",
+	}
+	limitFlag = &cli.IntFlag{
+		Name:  "limit",
+		Usage: "Maximum number of storage slots to include (0 includes every slot)",
+		Value: 1000,
+	}
+	heightFlag = &cli.Uint64Flag{
+		Name:  "height",
+		Usage: "Height of the canonical block to set as the acceptor tip",
+	}
+	dryRunFlag = &cli.BoolFlag{
+		Name:  "dry-run",
+		Usage: "Report the changes without writing them",
+	}
+	startHeightFlag = &cli.Uint64Flag{
+		Name:  "start-height",
+		Usage: "Commit height to rebuild the atomic trie from (defaults to the height of the last state sync)",
+	}
+	commitIntervalFlag = &cli.Uint64Flag{
+		Name:  "commit-interval",
+		Usage: "Interval at which the node commits the atomic trie",
+		Value: 4096,
+	}
+	repairFlag = &cli.BoolFlag{
+		Name:  "repair",
+		Usage: "Overwrite the atomic trie nodes and committed roots with the rebuilt ones",
+	}
+	verbosityFlag = &cli.IntFlag{
+		Name:  "verbosity",
+		Usage: "Logging verbosity: 0=silent, 1=error, 2=warn, 3=info, 4=debug, 5=detail",
+		Value: 3,
+	}
+)
+
+var app = flags.NewApp("Coreth database inspection and repair tool")
+
+func init() {
+	app.Name = "corethdb"
+	app.Flags = []cli.Flag{dbFlag, dbTypeFlag, chainIDFlag, verbosityFlag}
+	app.Before = func(c *cli.Context) error {
+		log.SetDefault(log.NewLogger(log.NewTerminalHandlerWithLevel(os.Stderr, log.FromLegacyLevel(c.Int(verbosityFlag.Name)), true)))
+		return nil
+	}
+	app.Commands = []*cli.Command{
+		{
+			Name:   "inspect",
+			Usage:  "Print the size of each category of data and the chain markers as JSON",
+			Action: inspect,
+		},
+		{
+			Name:   "verify-state",
+			Usage:  "Verify that every trie node and contract code of a state is present and well-formed",
+			Flags:  []cli.Flag{rootFlag},
+			Action: verifyState,
+		},
+		{
+			Name:   "dump-account",
+			Usage:  "Print an account of a state as JSON",
+			Flags:  []cli.Flag{rootFlag, addressFlag, storageFlag, limitFlag},
+			Action: dumpAccount,
+		},
+		{
+			Name:   "clear-sync-markers",
+			Usage:  "Remove the markers of an in-progress state sync, so that the next state sync starts over",
+			Flags:  []cli.Flag{dryRunFlag},
+			Action: clearSyncMarkers,
+		},
+		{
+			Name:   "repair-acceptor-tip",
+			Usage:  "Point the acceptor tip back to a canonical block, so that the node reprocesses the blocks after it on startup",
+			Flags:  []cli.Flag{heightFlag, dryRunFlag},
+			Action: repairAcceptorTip,
+		},
+		{
+			Name:   "verify-atomic-trie",
+			Usage:  "Rebuild the atomic trie from the atomic tx index and compare it to the committed atomic trie roots",
+			Flags:  []cli.Flag{startHeightFlag, commitIntervalFlag, repairFlag},
+			Action: verifyAtomicTrie,
+		},
+	}
+}
+
+func main() {
+	if err := app.Run(os.Args); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
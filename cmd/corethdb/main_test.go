@@ -0,0 +1,206 @@
+// Copyright (C) 2019-2025, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/MetalBlockchain/metalgo/database/memdb"
+	"github.com/MetalBlockchain/metalgo/ids"
+	"github.com/MetalBlockchain/libevm/common"
+	"github.com/MetalBlockchain/libevm/core/rawdb"
+	"github.com/MetalBlockchain/libevm/core/types"
+	"github.com/MetalBlockchain/libevm/crypto"
+	"github.com/MetalBlockchain/libevm/triedb"
+	"github.com/stretchr/testify/require"
+
+	"github.com/MetalBlockchain/coreth/consensus/dummy"
+	"github.com/MetalBlockchain/coreth/core"
+	"github.com/MetalBlockchain/coreth/params"
+	"github.com/MetalBlockchain/coreth/plugin/evm/customrawdb"
+	"github.com/MetalBlockchain/coreth/triedb/hashdb"
+
+	avalanchedatabase "github.com/MetalBlockchain/metalgo/database"
+)
+
+// unclosableDB keeps the database open across the commands run by a test,
+// each of which closes the database it opened.
+type unclosableDB struct {
+	avalanchedatabase.Database
+}
+
+func (unclosableDB) Close() error { return nil }
+
+type testEnvironment struct {
+	chainID ids.ID
+	db      *vmDatabase
+	blocks  []*types.Block // indexed by height
+	account common.Address
+}
+
+// newTestEnvironment returns the memory database of a VM which accepted a
+// few blocks on top of a genesis with a contract account, and has the
+// commands open it.
+func newTestEnvironment(t *testing.T) *testEnvironment {
+	t.Helper()
+	require := require.New(t)
+
+	base := unclosableDB{memdb.New()}
+	original := openBaseDatabase
+	openBaseDatabase = func(string, string) (avalanchedatabase.Database, error) {
+		return base, nil
+	}
+	t.Cleanup(func() {
+		openBaseDatabase = original
+	})
+
+	env := &testEnvironment{
+		chainID: ids.GenerateTestID(),
+		account: common.Address{1},
+	}
+	env.db = newVMDatabase(base, env.chainID)
+
+	gspec := &core.Genesis{
+		Config: &params.ChainConfig{HomesteadBlock: new(big.Int)},
+		Alloc: types.GenesisAlloc{
+			env.account: {
+				Balance: big.NewInt(1000),
+				Nonce:   1,
+				Code:    []byte{0x60, 0x00},
+				Storage: map[common.Hash]common.Hash{{1}: {2}},
+			},
+		},
+	}
+	trieDB := triedb.NewDatabase(env.db.chainDB, &triedb.Config{DBOverride: hashdb.Config{}.BackendConstructor})
+	genesis, err := gspec.Commit(env.db.chainDB, trieDB)
+	require.NoError(err)
+	require.NoError(trieDB.Close())
+
+	_, blocks, _, err := core.GenerateChainWithGenesis(gspec, dummy.NewETHFaker(), 3, 0, func(int, *core.BlockGen) {})
+	require.NoError(err)
+	for _, block := range blocks {
+		rawdb.WriteBlock(env.db.chainDB, block)
+		rawdb.WriteCanonicalHash(env.db.chainDB, block.Hash(), block.NumberU64())
+	}
+	env.blocks = append([]*types.Block{genesis}, blocks...)
+	require.NoError(env.db.acceptedBlockDB.Put(lastAcceptedKey, env.lastAccepted().Hash().Bytes()))
+	return env
+}
+
+func (e *testEnvironment) lastAccepted() *types.Block {
+	return e.blocks[len(e.blocks)-1]
+}
+
+// run runs the command given by [args] and returns its output.
+func (e *testEnvironment) run(t *testing.T, args ...string) (string, error) {
+	t.Helper()
+
+	var output bytes.Buffer
+	writer := app.Writer
+	app.Writer = &output
+	defer func() {
+		app.Writer = writer
+	}()
+	args = append([]string{"corethdb", "--db", "memdb", "--chain-id", e.chainID.String(), "--verbosity", "0"}, args...)
+	err := app.Run(args)
+	return output.String(), err
+}
+
+func TestInspect(t *testing.T) {
+	require := require.New(t)
+	env := newTestEnvironment(t)
+
+	output, err := env.run(t, "inspect")
+	require.NoError(err)
+	var report inspectReport
+	require.NoError(json.Unmarshal([]byte(output), &report))
+	require.NotNil(report.Markers.LastAccepted)
+	require.Equal(env.lastAccepted().Hash(), report.Markers.LastAccepted.Hash)
+	require.NotNil(report.Markers.LastAccepted.Height)
+	require.Equal(env.lastAccepted().NumberU64(), *report.Markers.LastAccepted.Height)
+	require.NotZero(report.Total)
+}
+
+func TestVerifyState(t *testing.T) {
+	require := require.New(t)
+	env := newTestEnvironment(t)
+
+	_, err := env.run(t, "verify-state")
+	require.NoError(err)
+
+	// Remove the code of the contract account.
+	rawdb.DeleteCode(env.db.chainDB, crypto.Keccak256Hash([]byte{0x60, 0x00}))
+	_, err = env.run(t, "verify-state")
+	require.ErrorIs(err, errMissingCode)
+}
+
+func TestDumpAccount(t *testing.T) {
+	require := require.New(t)
+	env := newTestEnvironment(t)
+
+	output, err := env.run(t, "dump-account", "--address", env.account.Hex(), "--storage")
+	require.NoError(err)
+	var dump accountDump
+	require.NoError(json.Unmarshal([]byte(output), &dump))
+	require.Equal(env.lastAccepted().Root(), dump.Root)
+	require.Equal(env.account, dump.Address)
+	require.Equal(uint64(1), dump.Nonce)
+	require.Equal("1000", dump.Balance)
+	require.Equal([]byte{0x60, 0x00}, []byte(dump.Code))
+	require.Len(dump.Storage, 1)
+
+	_, err = env.run(t, "dump-account", "--address", common.Address{2}.Hex())
+	require.ErrorIs(err, errAccountNotFound)
+}
+
+func TestRepairAcceptorTip(t *testing.T) {
+	require := require.New(t)
+	env := newTestEnvironment(t)
+
+	unknownTip := common.Hash{1}
+	require.NoError(customrawdb.WriteAcceptorTip(env.db.chainDB, unknownTip))
+	_, err := env.run(t, "repair-acceptor-tip")
+	require.ErrorIs(err, errUnknownAcceptorTipHeight)
+	_, err = env.run(t, "repair-acceptor-tip", "--height", "4")
+	require.ErrorIs(err, errAcceptorTipAboveLastAccepted)
+
+	_, err = env.run(t, "repair-acceptor-tip", "--height", "2", "--dry-run")
+	require.NoError(err)
+	tip, err := customrawdb.ReadAcceptorTip(env.db.chainDB)
+	require.NoError(err)
+	require.Equal(unknownTip, tip)
+
+	_, err = env.run(t, "repair-acceptor-tip", "--height", "2")
+	require.NoError(err)
+	tip, err = customrawdb.ReadAcceptorTip(env.db.chainDB)
+	require.NoError(err)
+	require.Equal(env.blocks[2].Hash(), tip)
+}
+
+func TestClearSyncMarkers(t *testing.T) {
+	require := require.New(t)
+	env := newTestEnvironment(t)
+
+	syncRoot := common.Hash{1}
+	require.NoError(env.db.metadataDB.Put(stateSyncSummaryKey, []byte{1}))
+	require.NoError(customrawdb.WriteSyncRoot(env.db.chainDB, syncRoot))
+
+	_, err := env.run(t, "clear-sync-markers", "--dry-run")
+	require.NoError(err)
+	hasSummary, err := env.db.metadataDB.Has(stateSyncSummaryKey)
+	require.NoError(err)
+	require.True(hasSummary)
+
+	_, err = env.run(t, "clear-sync-markers")
+	require.NoError(err)
+	hasSummary, err = env.db.metadataDB.Has(stateSyncSummaryKey)
+	require.NoError(err)
+	require.False(hasSummary)
+	root, err := customrawdb.ReadSyncRoot(env.db.chainDB)
+	require.NoError(err)
+	require.Zero(root)
+}
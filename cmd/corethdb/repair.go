@@ -0,0 +1,118 @@
+// Copyright (C) 2019-2025, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package main
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/MetalBlockchain/libevm/common"
+	"github.com/MetalBlockchain/libevm/core/rawdb"
+	"github.com/MetalBlockchain/libevm/log"
+	"github.com/urfave/cli/v2"
+
+	"github.com/MetalBlockchain/coreth/plugin/evm/customrawdb"
+)
+
+var (
+	errAcceptorTipAboveLastAccepted = errors.New("acceptor tip must not be above the last accepted block")
+	errUnknownAcceptorTipHeight     = errors.New("height of the acceptor tip is unknown")
+	errMissingCanonicalBlock        = errors.New("missing canonical block")
+)
+
+func clearSyncMarkers(c *cli.Context) error {
+	db, err := openVMDatabase(c)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	hasSummary, err := db.metadataDB.Has(stateSyncSummaryKey)
+	if err != nil {
+		return err
+	}
+	syncRoot, err := customrawdb.ReadSyncRoot(db.chainDB)
+	if err != nil {
+		return err
+	}
+	log.Info("Found state sync markers", "summary", hasSummary, "root", syncRoot)
+	if c.Bool(dryRunFlag.Name) {
+		return nil
+	}
+
+	// Clear the summary first, so that an interrupted run never leaves a
+	// summary to resume without its progress markers.
+	if err := db.metadataDB.Delete(stateSyncSummaryKey); err != nil {
+		return err
+	}
+	if err := customrawdb.ClearAllSyncStorageTries(db.chainDB); err != nil {
+		return err
+	}
+	if err := customrawdb.ClearAllSyncSegments(db.chainDB); err != nil {
+		return err
+	}
+	if err := customrawdb.ClearAllCodeToFetch(db.chainDB); err != nil {
+		return err
+	}
+	if err := customrawdb.DeleteSyncRoot(db.chainDB); err != nil {
+		return err
+	}
+	log.Info("Cleared state sync markers")
+	return nil
+}
+
+func repairAcceptorTip(c *cli.Context) error {
+	db, err := openVMDatabase(c)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	lastAcceptedHash, lastAcceptedHeight, ok, err := db.lastAccepted()
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return errNoAcceptedBlock
+	}
+	tip, err := customrawdb.ReadAcceptorTip(db.chainDB)
+	if err != nil {
+		return err
+	}
+	log.Info("Found acceptor tip", "tip", tip, "lastAccepted", lastAcceptedHash, "lastAcceptedHeight", lastAcceptedHeight)
+
+	var height uint64
+	switch {
+	case c.IsSet(heightFlag.Name):
+		height = c.Uint64(heightFlag.Name)
+		if height > lastAcceptedHeight {
+			return fmt.Errorf("%w: %d > %d", errAcceptorTipAboveLastAccepted, height, lastAcceptedHeight)
+		}
+	case tip == (common.Hash{}) || tip == lastAcceptedHash:
+		log.Info("Acceptor tip is up to date")
+		return nil
+	default:
+		tipHeight := rawdb.ReadHeaderNumber(db.chainDB, tip)
+		if tipHeight == nil {
+			return fmt.Errorf("%w: pass --%s to set it", errUnknownAcceptorTipHeight, heightFlag.Name)
+		}
+		height = min(*tipHeight, lastAcceptedHeight)
+		if *tipHeight <= lastAcceptedHeight && rawdb.ReadCanonicalHash(db.chainDB, height) == tip && rawdb.HasBody(db.chainDB, tip, height) {
+			log.Info("Acceptor tip is a canonical block, and blocks after it will be reprocessed on startup", "height", height)
+			return nil
+		}
+	}
+
+	// The node reprocesses every block after the acceptor tip on startup, so
+	// moving the tip back to a canonical block is always safe.
+	hash := rawdb.ReadCanonicalHash(db.chainDB, height)
+	if hash == (common.Hash{}) || rawdb.ReadBlock(db.chainDB, hash, height) == nil {
+		return fmt.Errorf("%w at height %d", errMissingCanonicalBlock, height)
+	}
+	log.Info("Setting acceptor tip", "hash", hash, "height", height)
+	if c.Bool(dryRunFlag.Name) {
+		return nil
+	}
+	return customrawdb.WriteAcceptorTip(db.chainDB, hash)
+}
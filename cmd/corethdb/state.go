@@ -0,0 +1,279 @@
+// Copyright (C) 2019-2025, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/MetalBlockchain/libevm/common"
+	"github.com/MetalBlockchain/libevm/common/hexutil"
+	"github.com/MetalBlockchain/libevm/core/rawdb"
+	"github.com/MetalBlockchain/libevm/core/types"
+	"github.com/MetalBlockchain/libevm/crypto"
+	"github.com/MetalBlockchain/libevm/ethdb"
+	"github.com/MetalBlockchain/libevm/log"
+	"github.com/MetalBlockchain/libevm/rlp"
+	"github.com/MetalBlockchain/libevm/trie"
+	"github.com/MetalBlockchain/libevm/triedb"
+	"github.com/MetalBlockchain/libevm/triedb/database"
+	"github.com/urfave/cli/v2"
+
+	"github.com/MetalBlockchain/coreth/plugin/evm/customrawdb"
+	"github.com/MetalBlockchain/coreth/plugin/evm/customtypes"
+	"github.com/MetalBlockchain/coreth/triedb/hashdb"
+	"github.com/MetalBlockchain/coreth/triedb/pathdb"
+)
+
+var (
+	errNoAcceptedBlock    = errors.New("the VM has not accepted a block")
+	errFirewoodScheme     = errors.New("the firewood state scheme is not supported")
+	errAccountNotFound    = errors.New("account not found")
+	errMissingTrieNode    = errors.New("missing trie node")
+	errInvalidTrieNode    = errors.New("trie node does not match its hash")
+	errInvalidAccountLeaf = errors.New("invalid account")
+	errMissingCode        = errors.New("missing contract code")
+)
+
+// openState opens the trie database of [db] and returns it with the state
+// root given by --root, or the root of the last accepted block.
+func openState(c *cli.Context, db *vmDatabase) (*triedb.Database, common.Hash, error) {
+	config := &triedb.Config{}
+	switch scheme := rawdb.ReadStateScheme(db.chainDB); scheme {
+	case rawdb.PathScheme:
+		config.DBOverride = pathdb.Config{ReadOnly: true}.BackendConstructor
+	case customrawdb.FirewoodScheme:
+		return nil, common.Hash{}, errFirewoodScheme
+	default:
+		config.DBOverride = hashdb.Config{}.BackendConstructor
+	}
+	trieDB := triedb.NewDatabase(db.chainDB, config)
+
+	if root := c.String(rootFlag.Name); len(root) > 0 {
+		return trieDB, common.HexToHash(root), nil
+	}
+	hash, height, ok, err := db.lastAccepted()
+	if err != nil {
+		return nil, common.Hash{}, err
+	}
+	if !ok {
+		return nil, common.Hash{}, errNoAcceptedBlock
+	}
+	header := rawdb.ReadHeader(db.chainDB, hash, height)
+	if header == nil {
+		return nil, common.Hash{}, fmt.Errorf("%w: %s", errMissingLastAcceptedHeader, hash)
+	}
+	return trieDB, header.Root, nil
+}
+
+func verifyState(c *cli.Context) error {
+	db, err := openVMDatabase(c)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	trieDB, root, err := openState(c, db)
+	if err != nil {
+		return err
+	}
+	defer trieDB.Close()
+
+	log.Info("Verifying state", "root", root)
+	stats, err := traverseState(db.chainDB, trieDB, root)
+	if err != nil {
+		return err
+	}
+	log.Info("Verified state", "root", root, "nodes", stats.nodes, "accounts", stats.accounts, "slots", stats.slots, "codes", stats.codes, "elapsed", common.PrettyDuration(time.Since(stats.start)))
+	return nil
+}
+
+type traverseStats struct {
+	start                         time.Time
+	nodes, accounts, slots, codes uint64
+}
+
+// traverseState reads every trie node of the state with [root] and checks
+// that it matches its hash, and that the code of every contract is present.
+func traverseState(chainDB ethdb.KeyValueReader, trieDB *triedb.Database, root common.Hash) (*traverseStats, error) {
+	reader, err := trieDB.Reader(root)
+	if err != nil {
+		return nil, err
+	}
+	accountTrie, err := trie.New(trie.StateTrieID(root), trieDB)
+	if err != nil {
+		return nil, err
+	}
+	accountIt, err := accountTrie.NodeIterator(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := &traverseStats{start: time.Now()}
+	logged := time.Now()
+	for accountIt.Next(true) {
+		if err := verifyNode(reader, common.Hash{}, accountIt); err != nil {
+			return nil, err
+		}
+		stats.nodes++
+		if time.Since(logged) > 8*time.Second {
+			log.Info("Verifying state", "nodes", stats.nodes, "accounts", stats.accounts, "slots", stats.slots, "codes", stats.codes, "elapsed", common.PrettyDuration(time.Since(stats.start)))
+			logged = time.Now()
+		}
+		if !accountIt.Leaf() {
+			continue
+		}
+
+		stats.accounts++
+		accountHash := common.BytesToHash(accountIt.LeafKey())
+		var acc types.StateAccount
+		if err := rlp.DecodeBytes(accountIt.LeafBlob(), &acc); err != nil {
+			return nil, fmt.Errorf("%w %s: %w", errInvalidAccountLeaf, accountHash, err)
+		}
+		if acc.Root != types.EmptyRootHash {
+			storageTrie, err := trie.New(trie.StorageTrieID(root, accountHash, acc.Root), trieDB)
+			if err != nil {
+				return nil, fmt.Errorf("failed to open storage trie of %s: %w", accountHash, err)
+			}
+			storageIt, err := storageTrie.NodeIterator(nil)
+			if err != nil {
+				return nil, err
+			}
+			for storageIt.Next(true) {
+				if err := verifyNode(reader, accountHash, storageIt); err != nil {
+					return nil, err
+				}
+				stats.nodes++
+				if storageIt.Leaf() {
+					stats.slots++
+				}
+			}
+			if err := storageIt.Error(); err != nil {
+				return nil, fmt.Errorf("failed to iterate storage trie of %s: %w", accountHash, err)
+			}
+		}
+		if !bytes.Equal(acc.CodeHash, types.EmptyCodeHash.Bytes()) {
+			if !rawdb.HasCode(chainDB, common.BytesToHash(acc.CodeHash)) {
+				return nil, fmt.Errorf("%w: %x of %s", errMissingCode, acc.CodeHash, accountHash)
+			}
+			stats.codes++
+		}
+	}
+	if err := accountIt.Error(); err != nil {
+		return nil, fmt.Errorf("failed to iterate account trie: %w", err)
+	}
+	return stats, nil
+}
+
+// verifyNode checks that the node at the position of [it] in the trie owned
+// by [owner] is stored and matches its hash. Nodes embedded in their parent
+// have no hash and are not checked.
+func verifyNode(reader database.Reader, owner common.Hash, it trie.NodeIterator) error {
+	hash := it.Hash()
+	if hash == (common.Hash{}) {
+		return nil
+	}
+	blob, _ := reader.Node(owner, it.Path(), hash)
+	if len(blob) == 0 {
+		return fmt.Errorf("%w: %s (owner %s, path %x)", errMissingTrieNode, hash, owner, it.Path())
+	}
+	if got := crypto.Keccak256Hash(blob); got != hash {
+		return fmt.Errorf("%w: %s (owner %s, path %x, got %s)", errInvalidTrieNode, hash, owner, it.Path(), got)
+	}
+	return nil
+}
+
+type accountDump struct {
+	Root        common.Hash                   `json:"root"`
+	Address     common.Address                `json:"address"`
+	AddressHash common.Hash                   `json:"addressHash"`
+	Nonce       uint64                        `json:"nonce"`
+	Balance     string                        `json:"balance"`
+	IsMultiCoin bool                          `json:"isMultiCoin"`
+	StorageRoot common.Hash                   `json:"storageRoot"`
+	CodeHash    common.Hash                   `json:"codeHash"`
+	Code        hexutil.Bytes                 `json:"code,omitempty"`
+	Storage     map[common.Hash]hexutil.Bytes `json:"storage,omitempty"`
+}
+
+func dumpAccount(c *cli.Context) error {
+	address := c.String(addressFlag.Name)
+	if !common.IsHexAddress(address) {
+		return fmt.Errorf("invalid address %q", address)
+	}
+	db, err := openVMDatabase(c)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	trieDB, root, err := openState(c, db)
+	if err != nil {
+		return err
+	}
+	defer trieDB.Close()
+
+	stateTrie, err := trie.NewStateTrie(trie.StateTrieID(root), trieDB)
+	if err != nil {
+		return err
+	}
+	addr := common.HexToAddress(address)
+	acc, err := stateTrie.GetAccount(addr)
+	if err != nil {
+		return err
+	}
+	if acc == nil {
+		return fmt.Errorf("%w: %s in state %s", errAccountNotFound, addr, root)
+	}
+
+	dump := &accountDump{
+		Root:        root,
+		Address:     addr,
+		AddressHash: crypto.Keccak256Hash(addr.Bytes()),
+		Nonce:       acc.Nonce,
+		Balance:     acc.Balance.Dec(),
+		IsMultiCoin: customtypes.IsMultiCoin(acc),
+		StorageRoot: acc.Root,
+		CodeHash:    common.BytesToHash(acc.CodeHash),
+	}
+	if !bytes.Equal(acc.CodeHash, types.EmptyCodeHash.Bytes()) {
+		dump.Code = rawdb.ReadCode(db.chainDB, dump.CodeHash)
+	}
+	if c.Bool(storageFlag.Name) && acc.Root != types.EmptyRootHash {
+		dump.Storage, err = dumpStorage(trieDB, trie.StorageTrieID(root, dump.AddressHash, acc.Root), c.Int(limitFlag.Name))
+		if err != nil {
+			return err
+		}
+	}
+
+	encoder := json.NewEncoder(c.App.Writer)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(dump)
+}
+
+// dumpStorage returns up to [limit] slots of the storage trie with [id] by
+// hashed key, or every slot if [limit] is 0.
+func dumpStorage(trieDB *triedb.Database, id *trie.ID, limit int) (map[common.Hash]hexutil.Bytes, error) {
+	storageTrie, err := trie.New(id, trieDB)
+	if err != nil {
+		return nil, err
+	}
+	nodeIt, err := storageTrie.NodeIterator(nil)
+	if err != nil {
+		return nil, err
+	}
+	storage := make(map[common.Hash]hexutil.Bytes)
+	it := trie.NewIterator(nodeIt)
+	for it.Next() && (limit == 0 || len(storage) < limit) {
+		_, value, _, err := rlp.Split(it.Value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid storage slot %x: %w", it.Key, err)
+		}
+		storage[common.BytesToHash(it.Key)] = common.CopyBytes(value)
+	}
+	return storage, it.Err
+}
@@ -0,0 +1,49 @@
+// Copyright (C) 2019-2025, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/MetalBlockchain/metalgo/ids"
+	"github.com/MetalBlockchain/metalgo/snow"
+	"github.com/MetalBlockchain/libevm/core/state"
+	"github.com/MetalBlockchain/libevm/core/types"
+
+	"github.com/MetalBlockchain/coreth/consensus/dummy"
+	"github.com/MetalBlockchain/coreth/params"
+	"github.com/MetalBlockchain/coreth/plugin/evm/atomic"
+	"github.com/MetalBlockchain/coreth/plugin/evm/customtypes"
+
+	atomicvm "github.com/MetalBlockchain/coreth/plugin/evm/atomic/vm"
+)
+
+var errNoAVAXAssetID = errors.New("replaying atomic transactions requires --avax-asset-id")
+
+// atomicCallbacks returns the consensus callbacks that apply the atomic
+// transactions of each replayed block to its state, as the VM does.
+//
+// Unlike the VM, the transactions are not verified against shared memory or
+// the atomic trie, since every replayed block was previously accepted.
+func atomicCallbacks(chainConfig *params.ChainConfig, avaxAssetID ids.ID) dummy.ConsensusCallbacks {
+	ctx := &snow.Context{AVAXAssetID: avaxAssetID}
+	return dummy.ConsensusCallbacks{
+		OnExtraStateChange: func(block *types.Block, parent *types.Header, statedb *state.StateDB) (*big.Int, *big.Int, error) {
+			isApricotPhase5 := params.GetExtra(chainConfig).IsApricotPhase5(block.Time())
+			txs, err := atomic.ExtractAtomicTxs(customtypes.BlockExtData(block), isApricotPhase5, atomic.Codec)
+			if err != nil {
+				return nil, nil, err
+			}
+			if len(txs) == 0 {
+				return nil, nil, nil
+			}
+			if avaxAssetID == ids.Empty {
+				return nil, nil, fmt.Errorf("%w: block %d contains %d atomic txs", errNoAVAXAssetID, block.NumberU64(), len(txs))
+			}
+			return atomicvm.ApplyTxs(ctx, chainConfig, block, parent, statedb, txs)
+		},
+	}
+}
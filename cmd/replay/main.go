@@ -0,0 +1,160 @@
+// Copyright (C) 2019-2025, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// replay re-executes a range of accepted blocks against a copy of their parent
+// state built in each compared state scheme, reporting the time spent
+// executing, hashing and committing each block. It is used to compare state
+// schemes and cache settings on real workloads.
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/MetalBlockchain/libevm/core/rawdb"
+	"github.com/MetalBlockchain/libevm/log"
+	"github.com/urfave/cli/v2"
+
+	"github.com/MetalBlockchain/coreth/cmd/utils"
+	"github.com/MetalBlockchain/coreth/core"
+	"github.com/MetalBlockchain/coreth/internal/flags"
+	"github.com/MetalBlockchain/coreth/plugin/evm/customrawdb"
+)
+
+var (
+	dbFlag = &cli.StringFlag{
+		Name:     "db",
+		Usage:    "Path to the node database holding the parent state of the first replayed block in the hash or path scheme, which is only read",
+		Required: true,
+	}
+	dbTypeFlag = &cli.StringFlag{
+		Name:  "db-type",
+		Usage: "Type of the node database (leveldb or pebbledb)",
+		Value: "leveldb",
+	}
+	workDirFlag = &cli.StringFlag{
+		Name:  "work-dir",
+		Usage: "Directory to create the temporary database of each state scheme in, which must fit a copy of the parent state (defaults to the system temporary directory)",
+	}
+	genesisFlag = &cli.StringFlag{
+		Name:     "genesis",
+		Usage:    "Path to the C-Chain genesis JSON",
+		Required: true,
+	}
+	networkIDFlag = &cli.UintFlag{
+		Name:  "network-id",
+		Usage: "ID of the network the blocks were accepted on, used to schedule network upgrades",
+		Value: 1,
+	}
+	chainIDFlag = &cli.StringFlag{
+		Name:     "chain-id",
+		Usage:    "Blockchain ID of the chain, used to locate the chain in the node database",
+		Required: true,
+	}
+	avaxAssetIDFlag = &cli.StringFlag{
+		Name:  "avax-asset-id",
+		Usage: "ID of the AVAX asset, required to replay blocks containing atomic transactions",
+	}
+	blocksFlag = &cli.StringFlag{
+		Name:  "blocks",
+		Usage: "Path to an RLP block export (e.g. from admin_exportChain, optionally gzipped) to replay instead of the blocks in the database",
+	}
+	startFlag = &cli.Uint64Flag{
+		Name:     "start",
+		Usage:    "Number of the first block to replay",
+		Required: true,
+	}
+	endFlag = &cli.Uint64Flag{
+		Name:  "end",
+		Usage: "Number of the last block to replay (0 replays until the last available block)",
+	}
+	stateSchemesFlag = &cli.StringSliceFlag{
+		Name:  "state-schemes",
+		Usage: fmt.Sprintf("State schemes to replay the blocks with, each from a copy of the parent state (%s, %s or %s)", rawdb.HashScheme, rawdb.PathScheme, customrawdb.FirewoodScheme),
+		Value: cli.NewStringSlice(rawdb.HashScheme),
+	}
+	trieCleanCacheFlag = &cli.IntFlag{
+		Name:  "trie-clean-cache",
+		Usage: "Size of the clean trie cache in MB",
+		Value: core.DefaultCacheConfig.TrieCleanLimit,
+	}
+	trieDirtyCacheFlag = &cli.IntFlag{
+		Name:  "trie-dirty-cache",
+		Usage: "Size of the dirty trie cache in MB",
+		Value: core.DefaultCacheConfig.TrieDirtyLimit,
+	}
+	snapshotCacheFlag = &cli.IntFlag{
+		Name:  "snapshot-cache",
+		Usage: "Size of the snapshot cache in MB (0 disables snapshots)",
+		Value: core.DefaultCacheConfig.SnapshotLimit,
+	}
+	commitIntervalFlag = &cli.Uint64Flag{
+		Name:  "commit-interval",
+		Usage: "Number of blocks between commits of the state to disk",
+		Value: core.DefaultCacheConfig.CommitInterval,
+	}
+	pruningFlag = &cli.BoolFlag{
+		Name:  "pruning",
+		Usage: "Whether to prune the state, as on a non-archive node",
+		Value: true,
+	}
+	stateHistoryFlag = &cli.Uint64Flag{
+		Name:  "state-history",
+		Usage: "Number of recent blocks for which state histories are kept (path and firewood schemes)",
+		Value: core.DefaultCacheConfig.StateHistory,
+	}
+	outputFlag = &cli.StringFlag{
+		Name:  "output",
+		Usage: "Path to write the per-block timings to as CSV",
+	}
+	verbosityFlag = &cli.IntFlag{
+		Name:  "verbosity",
+		Usage: "Logging verbosity: 0=silent, 1=error, 2=warn, 3=info, 4=debug, 5=detail",
+		Value: 3,
+	}
+)
+
+var app = flags.NewApp("Historical block replay benchmark")
+
+func init() {
+	app.Name = "replay"
+	app.Flags = []cli.Flag{
+		dbFlag,
+		dbTypeFlag,
+		workDirFlag,
+		genesisFlag,
+		networkIDFlag,
+		chainIDFlag,
+		avaxAssetIDFlag,
+		blocksFlag,
+		startFlag,
+		endFlag,
+		stateSchemesFlag,
+		trieCleanCacheFlag,
+		trieDirtyCacheFlag,
+		snapshotCacheFlag,
+		commitIntervalFlag,
+		pruningFlag,
+		stateHistoryFlag,
+		outputFlag,
+		verbosityFlag,
+	}
+	app.Action = replay
+}
+
+func main() {
+	if err := app.Run(os.Args); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func replay(c *cli.Context) error {
+	log.SetDefault(log.NewLogger(log.NewTerminalHandlerWithLevel(os.Stderr, log.FromLegacyLevel(c.Int(verbosityFlag.Name)), true)))
+
+	config, err := newReplayConfig(c)
+	if err != nil {
+		utils.Fatalf("Invalid configuration: %v", err)
+	}
+	return run(config)
+}
@@ -0,0 +1,361 @@
+// Copyright (C) 2019-2025, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package main
+
+import (
+	"compress/gzip"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/MetalBlockchain/metalgo/database/leveldb"
+	"github.com/MetalBlockchain/metalgo/database/pebbledb"
+	"github.com/MetalBlockchain/metalgo/database/prefixdb"
+	"github.com/MetalBlockchain/metalgo/ids"
+	"github.com/MetalBlockchain/metalgo/snow"
+	"github.com/MetalBlockchain/metalgo/upgrade"
+	"github.com/MetalBlockchain/metalgo/utils/logging"
+	"github.com/MetalBlockchain/libevm/common"
+	"github.com/MetalBlockchain/libevm/core/rawdb"
+	"github.com/MetalBlockchain/libevm/core/types"
+	"github.com/MetalBlockchain/libevm/core/vm"
+	"github.com/MetalBlockchain/libevm/ethdb"
+	"github.com/MetalBlockchain/libevm/log"
+	"github.com/MetalBlockchain/libevm/rlp"
+	"github.com/MetalBlockchain/libevm/triedb"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/urfave/cli/v2"
+
+	"github.com/MetalBlockchain/coreth/consensus/dummy"
+	"github.com/MetalBlockchain/coreth/core"
+	"github.com/MetalBlockchain/coreth/params"
+	"github.com/MetalBlockchain/coreth/params/extras"
+	"github.com/MetalBlockchain/coreth/plugin/evm/customrawdb"
+	"github.com/MetalBlockchain/coreth/plugin/evm/database"
+
+	avalanchedatabase "github.com/MetalBlockchain/metalgo/database"
+	warpcontract "github.com/MetalBlockchain/coreth/precompile/contracts/warp"
+)
+
+var (
+	// vmDBPrefix is the prefix under which the node stores the database of
+	// each VM, within the prefix of the chain ID.
+	vmDBPrefix = []byte("vm")
+	// ethDBPrefix is the prefix of the chain database within the VM database.
+	ethDBPrefix = []byte("ethdb")
+
+	errNoParent = errors.New("parent of the first replayed block is not in the database")
+)
+
+type replayConfig struct {
+	dbPath      string
+	dbType      string
+	workDir     string
+	schemes     []string
+	genesisPath string
+	networkID   uint32
+	chainID     ids.ID
+	avaxAssetID ids.ID
+	blocksPath  string
+	start       uint64
+	end         uint64
+	outputPath  string
+	cacheConfig core.CacheConfig
+}
+
+func newReplayConfig(c *cli.Context) (replayConfig, error) {
+	config := replayConfig{
+		dbPath:      c.String(dbFlag.Name),
+		dbType:      c.String(dbTypeFlag.Name),
+		workDir:     c.String(workDirFlag.Name),
+		schemes:     c.StringSlice(stateSchemesFlag.Name),
+		genesisPath: c.String(genesisFlag.Name),
+		networkID:   uint32(c.Uint(networkIDFlag.Name)),
+		blocksPath:  c.String(blocksFlag.Name),
+		start:       c.Uint64(startFlag.Name),
+		end:         c.Uint64(endFlag.Name),
+		outputPath:  c.String(outputFlag.Name),
+		cacheConfig: *core.DefaultCacheConfig,
+	}
+	var err error
+	config.chainID, err = ids.FromString(c.String(chainIDFlag.Name))
+	if err != nil {
+		return config, fmt.Errorf("invalid chain ID: %w", err)
+	}
+	if assetID := c.String(avaxAssetIDFlag.Name); assetID != "" {
+		config.avaxAssetID, err = ids.FromString(assetID)
+		if err != nil {
+			return config, fmt.Errorf("invalid AVAX asset ID: %w", err)
+		}
+	}
+	if config.start == 0 {
+		return config, errors.New("cannot replay the genesis block")
+	}
+	if config.end != 0 && config.end < config.start {
+		return config, fmt.Errorf("end %d is before start %d", config.end, config.start)
+	}
+
+	cacheConfig := &config.cacheConfig
+	cacheConfig.TrieCleanLimit = c.Int(trieCleanCacheFlag.Name)
+	cacheConfig.TrieDirtyLimit = c.Int(trieDirtyCacheFlag.Name)
+	cacheConfig.SnapshotLimit = c.Int(snapshotCacheFlag.Name)
+	cacheConfig.CommitInterval = c.Uint64(commitIntervalFlag.Name)
+	cacheConfig.Pruning = c.Bool(pruningFlag.Name)
+	cacheConfig.StateHistory = c.Uint64(stateHistoryFlag.Name)
+	// Replayed blocks are not served, so skip maintaining the tx index.
+	cacheConfig.SkipTxIndexing = true
+	// Generate the snapshot of the built parent state before replaying, rather
+	// than concurrently with the replayed blocks.
+	cacheConfig.SnapshotWait = true
+	if len(config.schemes) == 0 {
+		return config, errors.New("no state scheme to replay with")
+	}
+	for _, scheme := range config.schemes {
+		switch scheme {
+		case rawdb.HashScheme, rawdb.PathScheme, customrawdb.FirewoodScheme:
+		default:
+			return config, fmt.Errorf("unknown state scheme %q", scheme)
+		}
+	}
+	return config, nil
+}
+
+func run(config replayConfig) error {
+	baseDB, err := openDatabase(config.dbType, config.dbPath)
+	if err != nil {
+		return err
+	}
+	defer baseDB.Close()
+
+	vmDB := prefixdb.New(vmDBPrefix, prefixdb.New(config.chainID[:], baseDB))
+	sourceDB := rawdb.NewDatabase(database.WrapDatabase(prefixdb.NewNested(ethDBPrefix, vmDB)))
+	sourceTrieDB, err := openSourceState(sourceDB)
+	if err != nil {
+		return err
+	}
+	defer sourceTrieDB.Close()
+
+	genesis, err := readGenesis(config)
+	if err != nil {
+		return err
+	}
+	parentNumber := config.start - 1
+	parentHash := rawdb.ReadCanonicalHash(sourceDB, parentNumber)
+	parent := rawdb.ReadBlock(sourceDB, parentHash, parentNumber)
+	if parent == nil {
+		return fmt.Errorf("%w: block %d", errNoParent, parentNumber)
+	}
+
+	var output io.Writer = io.Discard
+	if config.outputPath != "" {
+		f, err := os.Create(config.outputPath)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		output = f
+	}
+	w := csv.NewWriter(output)
+	if err := w.Write(csvHeader); err != nil {
+		return err
+	}
+
+	for _, scheme := range config.schemes {
+		if err := replayScheme(config, scheme, sourceDB, sourceTrieDB, genesis, parent, w); err != nil {
+			return fmt.Errorf("replaying with the %s state scheme: %w", scheme, err)
+		}
+	}
+	return nil
+}
+
+// replayScheme builds the state of [parent] in [scheme] in a temporary
+// database, and replays the blocks following it against that database.
+func replayScheme(
+	config replayConfig,
+	scheme string,
+	sourceDB ethdb.Database,
+	sourceTrieDB *triedb.Database,
+	genesis *core.Genesis,
+	parent *types.Block,
+	w *csv.Writer,
+) error {
+	dir, err := os.MkdirTemp(config.workDir, "replay-"+scheme+"-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+
+	db, err := openDatabase(config.dbType, filepath.Join(dir, "db"))
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	chainDB := rawdb.NewDatabase(database.WrapDatabase(db))
+
+	cacheConfig := config.cacheConfig
+	cacheConfig.StateScheme = scheme
+	cacheConfig.ChainDataDir = dir
+	if scheme == customrawdb.FirewoodScheme {
+		// Firewood keeps its own state and does not support snapshots.
+		cacheConfig.SnapshotLimit = 0
+	}
+
+	log.Info("Building parent state", "scheme", scheme, "number", parent.NumberU64(), "root", parent.Root(), "dir", dir)
+	if err := copyChain(sourceDB, chainDB, genesis, parent, scheme); err != nil {
+		return err
+	}
+	if err := buildState(sourceDB, sourceTrieDB, parent.Root(), chainDB, scheme, dir); err != nil {
+		return err
+	}
+
+	blocks, closeBlocks, err := openBlocks(config, sourceDB)
+	if err != nil {
+		return err
+	}
+	defer closeBlocks()
+
+	engine := dummy.NewFakerWithCallbacks(atomicCallbacks(genesis.Config, config.avaxAssetID))
+	chain, err := core.NewBlockChain(chainDB, &cacheConfig, genesis, engine, vm.Config{}, parent.Hash(), true)
+	if err != nil {
+		return fmt.Errorf("failed to create blockchain at block %d: %w", parent.NumberU64(), err)
+	}
+	defer chain.Stop()
+
+	r := newReporter(w, scheme)
+
+	statsCh := make(chan core.BlockInsertStatsEvent, 1)
+	sub := chain.SubscribeBlockInsertStatsEvent(statsCh)
+	defer sub.Unsubscribe()
+
+	log.Info("Replaying blocks", "start", config.start, "end", config.end, "scheme", scheme)
+	for {
+		block, err := blocks()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if block.NumberU64() < config.start {
+			continue
+		}
+		if config.end != 0 && block.NumberU64() > config.end {
+			break
+		}
+
+		if err := chain.InsertBlock(block); err != nil {
+			return fmt.Errorf("failed to insert block %d (%s): %w", block.NumberU64(), block.Hash(), err)
+		}
+		stats := <-statsCh
+
+		acceptStart := time.Now()
+		if err := chain.Accept(block); err != nil {
+			return fmt.Errorf("failed to accept block %d (%s): %w", block.NumberU64(), block.Hash(), err)
+		}
+		chain.DrainAcceptorQueue()
+
+		if err := r.report(stats, time.Since(acceptStart)); err != nil {
+			return err
+		}
+	}
+	r.summarize()
+	return nil
+}
+
+func openDatabase(dbType string, path string) (avalanchedatabase.Database, error) {
+	switch dbType {
+	case leveldb.Name:
+		return leveldb.New(path, nil, logging.NoLog{}, prometheus.NewRegistry())
+	case pebbledb.Name:
+		return pebbledb.New(path, nil, logging.NoLog{}, prometheus.NewRegistry())
+	default:
+		return nil, fmt.Errorf("unknown database type %q", dbType)
+	}
+}
+
+// readGenesis parses the genesis at [config.genesisPath], populating its chain
+// config with the network upgrades of [config.networkID].
+func readGenesis(config replayConfig) (*core.Genesis, error) {
+	genesisBytes, err := os.ReadFile(config.genesisPath)
+	if err != nil {
+		return nil, err
+	}
+	genesis := new(core.Genesis)
+	if err := json.Unmarshal(genesisBytes, genesis); err != nil {
+		return nil, fmt.Errorf("parsing genesis: %w", err)
+	}
+
+	upgrades := upgrade.GetConfig(config.networkID)
+	configExtra := params.GetExtra(genesis.Config)
+	configExtra.AvalancheContext = extras.AvalancheContext{
+		SnowCtx: &snow.Context{
+			NetworkID:   config.networkID,
+			ChainID:     config.chainID,
+			AVAXAssetID: config.avaxAssetID,
+		},
+	}
+	configExtra.NetworkUpgrades = extras.GetNetworkUpgrades(upgrades)
+	// Match the VM, which schedules the warp precompile with Durango.
+	if configExtra.DurangoBlockTimestamp != nil {
+		configExtra.PrecompileUpgrades = append(configExtra.PrecompileUpgrades, extras.PrecompileUpgrade{
+			Config: warpcontract.NewDefaultConfig(configExtra.DurangoBlockTimestamp),
+		})
+	}
+	if err := configExtra.Verify(); err != nil {
+		return nil, fmt.Errorf("invalid chain config: %w", err)
+	}
+	if err := params.SetEthUpgrades(genesis.Config); err != nil {
+		return nil, fmt.Errorf("setting eth upgrades: %w", err)
+	}
+	return genesis, nil
+}
+
+// openBlocks returns an iterator over the blocks to replay, which returns
+// io.EOF once every block has been returned.
+func openBlocks(config replayConfig, chainDB ethdb.Database) (func() (*types.Block, error), func(), error) {
+	if config.blocksPath == "" {
+		next := config.start
+		return func() (*types.Block, error) {
+			hash := rawdb.ReadCanonicalHash(chainDB, next)
+			if hash == (common.Hash{}) {
+				return nil, io.EOF
+			}
+			block := rawdb.ReadBlock(chainDB, hash, next)
+			if block == nil {
+				return nil, fmt.Errorf("block %d (%s) not found", next, hash)
+			}
+			next++
+			return block, nil
+		}, func() {}, nil
+	}
+
+	f, err := os.Open(config.blocksPath)
+	if err != nil {
+		return nil, nil, err
+	}
+	var reader io.Reader = f
+	if strings.HasSuffix(config.blocksPath, ".gz") {
+		if reader, err = gzip.NewReader(reader); err != nil {
+			f.Close()
+			return nil, nil, err
+		}
+	}
+	stream := rlp.NewStream(reader, 0)
+	return func() (*types.Block, error) {
+		block := new(types.Block)
+		if err := stream.Decode(block); err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil, io.EOF
+			}
+			return nil, fmt.Errorf("failed to parse block: %w", err)
+		}
+		return block, nil
+	}, func() { f.Close() }, nil
+}
@@ -0,0 +1,144 @@
+// Copyright (C) 2019-2025, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package main
+
+import (
+	"encoding/csv"
+	"strconv"
+	"time"
+
+	"github.com/MetalBlockchain/libevm/common"
+	"github.com/MetalBlockchain/libevm/log"
+
+	"github.com/MetalBlockchain/coreth/core"
+)
+
+// statsReportInterval is the interval at which progress is logged.
+const statsReportInterval = 8 * time.Second
+
+var csvHeader = []string{
+	"scheme", "number", "hash", "txs", "gas",
+	"execution_us", "state_reads_us", "trie_hash_us", "validation_us", "commit_us", "write_us", "insert_us", "accept_us",
+}
+
+// timings holds the time spent in each phase of replaying blocks.
+type timings struct {
+	execution  time.Duration
+	stateReads time.Duration
+	trieHash   time.Duration
+	validation time.Duration
+	commit     time.Duration
+	write      time.Duration
+	insert     time.Duration
+	accept     time.Duration
+}
+
+func (t *timings) add(stats core.BlockInsertStatsEvent, accept time.Duration) {
+	t.execution += stats.Execution
+	t.stateReads += stats.StateReads
+	t.trieHash += stats.TrieHash
+	t.validation += stats.Validation
+	t.commit += stats.Commit
+	t.write += stats.Write
+	t.insert += stats.Total
+	t.accept += accept
+}
+
+// reporter writes the timings of each block replayed with a state scheme as
+// CSV and accumulates them for the final summary.
+type reporter struct {
+	csv    *csv.Writer
+	scheme string
+
+	start      time.Time
+	lastReport time.Time
+	blocks     uint64
+	txs        uint64
+	gas        uint64
+	total      timings
+}
+
+func newReporter(w *csv.Writer, scheme string) *reporter {
+	now := time.Now()
+	return &reporter{
+		csv:        w,
+		scheme:     scheme,
+		start:      now,
+		lastReport: now,
+	}
+}
+
+func (r *reporter) report(stats core.BlockInsertStatsEvent, accept time.Duration) error {
+	block := stats.Block
+	r.blocks++
+	r.txs += uint64(len(block.Transactions()))
+	r.gas += block.GasUsed()
+	r.total.add(stats, accept)
+
+	err := r.csv.Write([]string{
+		r.scheme,
+		strconv.FormatUint(block.NumberU64(), 10),
+		block.Hash().Hex(),
+		strconv.Itoa(len(block.Transactions())),
+		strconv.FormatUint(block.GasUsed(), 10),
+		micros(stats.Execution),
+		micros(stats.StateReads),
+		micros(stats.TrieHash),
+		micros(stats.Validation),
+		micros(stats.Commit),
+		micros(stats.Write),
+		micros(stats.Total),
+		micros(accept),
+	})
+	if err != nil {
+		return err
+	}
+
+	log.Debug("Replayed block", "number", block.Number(), "hash", block.Hash(), "txs", len(block.Transactions()), "gas", block.GasUsed(),
+		"execution", common.PrettyDuration(stats.Execution), "trieHash", common.PrettyDuration(stats.TrieHash),
+		"commit", common.PrettyDuration(stats.Commit), "accept", common.PrettyDuration(accept))
+	if time.Since(r.lastReport) > statsReportInterval {
+		r.csv.Flush()
+		log.Info("Replaying blocks", "scheme", r.scheme, "number", block.Number(), "blocks", r.blocks, "txs", r.txs, "mgas/s", r.mgasPerSecond(),
+			"elapsed", common.PrettyDuration(time.Since(r.start)))
+		r.lastReport = time.Now()
+	}
+	return r.csv.Error()
+}
+
+func (r *reporter) mgasPerSecond() float64 {
+	elapsed := (r.total.insert + r.total.accept).Seconds()
+	if elapsed == 0 {
+		return 0
+	}
+	return float64(r.gas) / 1e6 / elapsed
+}
+
+// summarize logs the total and mean time spent in each phase.
+func (r *reporter) summarize() {
+	r.csv.Flush()
+	if r.blocks == 0 {
+		log.Warn("No blocks were replayed", "scheme", r.scheme)
+		return
+	}
+	mean := func(d time.Duration) common.PrettyDuration {
+		return common.PrettyDuration(d / time.Duration(r.blocks))
+	}
+	log.Info("Replay complete", "scheme", r.scheme, "blocks", r.blocks, "txs", r.txs, "gas", r.gas, "mgas/s", r.mgasPerSecond(),
+		"elapsed", common.PrettyDuration(time.Since(r.start)))
+	log.Info("Total time per phase", "scheme", r.scheme,
+		"execution", common.PrettyDuration(r.total.execution), "stateReads", common.PrettyDuration(r.total.stateReads),
+		"trieHash", common.PrettyDuration(r.total.trieHash), "validation", common.PrettyDuration(r.total.validation),
+		"commit", common.PrettyDuration(r.total.commit), "write", common.PrettyDuration(r.total.write),
+		"insert", common.PrettyDuration(r.total.insert), "accept", common.PrettyDuration(r.total.accept))
+	log.Info("Mean time per block", "scheme", r.scheme,
+		"execution", mean(r.total.execution), "stateReads", mean(r.total.stateReads),
+		"trieHash", mean(r.total.trieHash), "validation", mean(r.total.validation),
+		"commit", mean(r.total.commit), "write", mean(r.total.write),
+		"insert", mean(r.total.insert), "accept", mean(r.total.accept))
+}
+
+func micros(d time.Duration) string {
+	return strconv.FormatInt(d.Microseconds(), 10)
+}
@@ -0,0 +1,359 @@
+// Copyright (C) 2019-2025, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/MetalBlockchain/libevm/common"
+	"github.com/MetalBlockchain/libevm/core/rawdb"
+	"github.com/MetalBlockchain/libevm/core/types"
+	"github.com/MetalBlockchain/libevm/ethdb"
+	"github.com/MetalBlockchain/libevm/log"
+	"github.com/MetalBlockchain/libevm/rlp"
+	"github.com/MetalBlockchain/libevm/trie"
+	"github.com/MetalBlockchain/libevm/triedb"
+
+	"github.com/MetalBlockchain/coreth/core"
+	"github.com/MetalBlockchain/coreth/plugin/evm/customrawdb"
+	"github.com/MetalBlockchain/coreth/triedb/hashdb"
+	"github.com/MetalBlockchain/coreth/triedb/pathdb"
+
+	ffi "github.com/ava-labs/firewood-go-ethhash/ffi"
+)
+
+const (
+	// ancestorsWindow is the number of ancestors of a block whose hash can be
+	// read by the BLOCKHASH opcode.
+	ancestorsWindow = 256
+	// firewoodBatchSize is the number of state entries written to firewood at
+	// once while building the parent state.
+	firewoodBatchSize = 100_000
+	// firewoodFileName is the file in the chain data directory that
+	// [core.BlockChain] keeps the firewood state in.
+	firewoodFileName = "firewood_state"
+)
+
+var (
+	errFirewoodSource    = errors.New("the parent state cannot be read from a firewood database")
+	errMissingHeader     = errors.New("missing header")
+	errMissingCode       = errors.New("missing contract code")
+	errStateRootMismatch = errors.New("state root mismatch")
+)
+
+// openSourceState opens the trie database of the node database, in the scheme
+// its state was written with.
+func openSourceState(chainDB ethdb.Database) (*triedb.Database, error) {
+	config := &triedb.Config{}
+	switch scheme := rawdb.ReadStateScheme(chainDB); scheme {
+	case rawdb.PathScheme:
+		config.DBOverride = pathdb.Config{ReadOnly: true}.BackendConstructor
+	case customrawdb.FirewoodScheme:
+		return nil, errFirewoodSource
+	default:
+		config.DBOverride = hashdb.Config{}.BackendConstructor
+	}
+	return triedb.NewDatabase(chainDB, config), nil
+}
+
+// copyChain writes the genesis, [parent] and its ancestors readable by the
+// BLOCKHASH opcode from [src] to [dst], marking [parent] as the head.
+func copyChain(src ethdb.Database, dst ethdb.Database, genesis *core.Genesis, parent *types.Block, scheme string) error {
+	if scheme == rawdb.HashScheme {
+		// The hash scheme only considers the state initialized once the
+		// genesis state is committed, and would otherwise commit it again.
+		trieDB := triedb.NewDatabase(dst, &triedb.Config{DBOverride: hashdb.Config{}.BackendConstructor})
+		defer trieDB.Close()
+		if _, err := genesis.Commit(dst, trieDB); err != nil {
+			return fmt.Errorf("committing genesis: %w", err)
+		}
+	} else {
+		block := genesis.ToBlock()
+		rawdb.WriteBlock(dst, block)
+		rawdb.WriteCanonicalHash(dst, block.Hash(), 0)
+		rawdb.WriteChainConfig(dst, block.Hash(), genesis.Config)
+	}
+
+	batch := dst.NewBatch()
+	first := uint64(1)
+	if parent.NumberU64() > ancestorsWindow {
+		first = parent.NumberU64() - ancestorsWindow
+	}
+	for number := first; number < parent.NumberU64(); number++ {
+		hash := rawdb.ReadCanonicalHash(src, number)
+		header := rawdb.ReadHeader(src, hash, number)
+		if header == nil {
+			return fmt.Errorf("%w: block %d", errMissingHeader, number)
+		}
+		rawdb.WriteHeader(batch, header)
+		rawdb.WriteCanonicalHash(batch, hash, number)
+	}
+	rawdb.WriteBlock(batch, parent)
+	rawdb.WriteCanonicalHash(batch, parent.Hash(), parent.NumberU64())
+	rawdb.WriteHeadBlockHash(batch, parent.Hash())
+	rawdb.WriteHeadHeaderHash(batch, parent.Hash())
+	return batch.Write()
+}
+
+// buildState writes the state with [root] from [srcTrieDB] to [dst] in
+// [scheme], so that every scheme replays from the same parent state. The
+// firewood state is written to [chainDataDir].
+func buildState(srcDB ethdb.Database, srcTrieDB *triedb.Database, root common.Hash, dst ethdb.Database, scheme string, chainDataDir string) error {
+	start := time.Now()
+	var w stateWriter
+	if scheme == customrawdb.FirewoodScheme {
+		fw, err := newFirewoodWriter(filepath.Join(chainDataDir, firewoodFileName))
+		if err != nil {
+			return err
+		}
+		w = fw
+	} else {
+		w = newTrieWriter(dst, scheme)
+	}
+
+	accounts, err := iterateState(srcDB, srcTrieDB, root, dst, w)
+	if err != nil {
+		w.close()
+		return err
+	}
+	got, err := w.commit()
+	if err != nil {
+		return err
+	}
+	if got != root {
+		return fmt.Errorf("%w: built %s, want %s", errStateRootMismatch, got, root)
+	}
+	log.Info("Built parent state", "scheme", scheme, "root", root, "accounts", accounts, "elapsed", common.PrettyDuration(time.Since(start)))
+	return nil
+}
+
+// iterateState passes every account and storage slot of the state with [root]
+// to [w] in key order, and copies the code of every contract to [dst]. It
+// returns the number of accounts.
+func iterateState(srcDB ethdb.Database, srcTrieDB *triedb.Database, root common.Hash, dst ethdb.Database, w stateWriter) (uint64, error) {
+	accountTrie, err := trie.New(trie.StateTrieID(root), srcTrieDB)
+	if err != nil {
+		return 0, err
+	}
+	nodeIt, err := accountTrie.NodeIterator(nil)
+	if err != nil {
+		return 0, err
+	}
+
+	var (
+		accounts uint64
+		logged   = time.Now()
+		batch    = dst.NewBatch()
+		accIt    = trie.NewIterator(nodeIt)
+	)
+	for accIt.Next() {
+		accountHash := common.BytesToHash(accIt.Key)
+		var acc types.StateAccount
+		if err := rlp.DecodeBytes(accIt.Value, &acc); err != nil {
+			return 0, fmt.Errorf("invalid account %s: %w", accountHash, err)
+		}
+		if acc.Root != types.EmptyRootHash {
+			storageTrie, err := trie.New(trie.StorageTrieID(root, accountHash, acc.Root), srcTrieDB)
+			if err != nil {
+				return 0, fmt.Errorf("failed to open storage trie of %s: %w", accountHash, err)
+			}
+			nodeIt, err := storageTrie.NodeIterator(nil)
+			if err != nil {
+				return 0, err
+			}
+			slotIt := trie.NewIterator(nodeIt)
+			for slotIt.Next() {
+				if err := w.updateStorage(accountHash, common.BytesToHash(slotIt.Key), slotIt.Value); err != nil {
+					return 0, err
+				}
+			}
+			if slotIt.Err != nil {
+				return 0, fmt.Errorf("failed to iterate storage trie of %s: %w", accountHash, slotIt.Err)
+			}
+			if err := w.commitStorage(accountHash, acc.Root); err != nil {
+				return 0, err
+			}
+		}
+		if !bytes.Equal(acc.CodeHash, types.EmptyCodeHash.Bytes()) {
+			codeHash := common.BytesToHash(acc.CodeHash)
+			code := rawdb.ReadCode(srcDB, codeHash)
+			if len(code) == 0 {
+				return 0, fmt.Errorf("%w: %s of %s", errMissingCode, codeHash, accountHash)
+			}
+			rawdb.WriteCode(batch, codeHash, code)
+			if batch.ValueSize() > ethdb.IdealBatchSize {
+				if err := batch.Write(); err != nil {
+					return 0, err
+				}
+				batch.Reset()
+			}
+		}
+		if err := w.updateAccount(accountHash, accIt.Value); err != nil {
+			return 0, err
+		}
+
+		accounts++
+		if time.Since(logged) > statsReportInterval {
+			log.Info("Building parent state", "accounts", accounts, "at", accountHash)
+			logged = time.Now()
+		}
+	}
+	if accIt.Err != nil {
+		return 0, fmt.Errorf("failed to iterate account trie: %w", accIt.Err)
+	}
+	return accounts, batch.Write()
+}
+
+// stateWriter writes the state built by [iterateState] in a state scheme.
+type stateWriter interface {
+	updateAccount(accountHash common.Hash, blob []byte) error
+	updateStorage(accountHash, slotHash common.Hash, blob []byte) error
+	// commitStorage is called once every slot of the account has been updated,
+	// with the storage root the slots must hash to.
+	commitStorage(accountHash common.Hash, root common.Hash) error
+	// commit writes the remaining state and returns its root.
+	commit() (common.Hash, error)
+	// close releases the writer if commit is never called.
+	close()
+}
+
+// trieWriter writes the trie nodes of the state to a key-value store in the
+// hash or path scheme.
+type trieWriter struct {
+	batch    ethdb.Batch
+	scheme   string
+	accounts *trie.StackTrie
+	storage  *trie.StackTrie
+	err      error
+}
+
+func newTrieWriter(db ethdb.Database, scheme string) *trieWriter {
+	w := &trieWriter{
+		batch:  db.NewBatch(),
+		scheme: scheme,
+	}
+	w.accounts = w.newStackTrie(common.Hash{})
+	return w
+}
+
+func (w *trieWriter) newStackTrie(owner common.Hash) *trie.StackTrie {
+	options := trie.NewStackTrieOptions().WithWriter(func(path []byte, hash common.Hash, blob []byte) {
+		rawdb.WriteTrieNode(w.batch, owner, path, hash, blob, w.scheme)
+		if w.err == nil && w.batch.ValueSize() > ethdb.IdealBatchSize {
+			w.err = w.batch.Write()
+			w.batch.Reset()
+		}
+	})
+	return trie.NewStackTrie(options)
+}
+
+func (w *trieWriter) updateAccount(accountHash common.Hash, blob []byte) error {
+	if err := w.accounts.Update(accountHash[:], blob); err != nil {
+		return err
+	}
+	return w.err
+}
+
+func (w *trieWriter) updateStorage(accountHash, slotHash common.Hash, blob []byte) error {
+	if w.storage == nil {
+		w.storage = w.newStackTrie(accountHash)
+	}
+	if err := w.storage.Update(slotHash[:], blob); err != nil {
+		return err
+	}
+	return w.err
+}
+
+func (w *trieWriter) commitStorage(accountHash common.Hash, root common.Hash) error {
+	if w.storage == nil {
+		return fmt.Errorf("%w: empty storage of %s, want %s", errStateRootMismatch, accountHash, root)
+	}
+	got := w.storage.Commit()
+	w.storage = nil
+	if got != root {
+		return fmt.Errorf("%w: built storage %s of %s, want %s", errStateRootMismatch, got, accountHash, root)
+	}
+	return w.err
+}
+
+func (w *trieWriter) commit() (common.Hash, error) {
+	root := w.accounts.Commit()
+	if w.err != nil {
+		return common.Hash{}, w.err
+	}
+	return root, w.batch.Write()
+}
+
+func (*trieWriter) close() {}
+
+// firewoodWriter writes the state to a new firewood database. Firewood stores
+// account and storage entries by their hashed key, rather than trie nodes, so
+// storage roots are only checked as part of the state root.
+type firewoodWriter struct {
+	db     *ffi.Database
+	keys   [][]byte
+	values [][]byte
+	root   common.Hash
+}
+
+func newFirewoodWriter(path string) (*firewoodWriter, error) {
+	db, err := ffi.New(path, ffi.DefaultConfig())
+	if err != nil {
+		return nil, fmt.Errorf("failed to create firewood database: %w", err)
+	}
+	return &firewoodWriter{db: db, root: types.EmptyRootHash}, nil
+}
+
+func (w *firewoodWriter) updateAccount(accountHash common.Hash, blob []byte) error {
+	return w.add(accountHash[:], blob)
+}
+
+func (w *firewoodWriter) updateStorage(accountHash, slotHash common.Hash, blob []byte) error {
+	return w.add(append(accountHash[:], slotHash[:]...), blob)
+}
+
+func (*firewoodWriter) commitStorage(common.Hash, common.Hash) error {
+	return nil
+}
+
+func (w *firewoodWriter) add(key, value []byte) error {
+	w.keys = append(w.keys, key)
+	w.values = append(w.values, common.CopyBytes(value))
+	if len(w.keys) < firewoodBatchSize {
+		return nil
+	}
+	return w.flush()
+}
+
+func (w *firewoodWriter) flush() error {
+	if len(w.keys) == 0 {
+		return nil
+	}
+	root, err := w.db.Update(w.keys, w.values)
+	if err != nil {
+		return fmt.Errorf("failed to write firewood batch: %w", err)
+	}
+	w.root = common.BytesToHash(root)
+	w.keys, w.values = w.keys[:0], w.values[:0]
+	return nil
+}
+
+func (w *firewoodWriter) commit() (common.Hash, error) {
+	defer w.close()
+	if err := w.flush(); err != nil {
+		return common.Hash{}, err
+	}
+	return w.root, nil
+}
+
+// close closes the firewood database, which [core.BlockChain] opens again.
+func (w *firewoodWriter) close() {
+	if err := w.db.Close(); err != nil {
+		log.Error("Failed to close firewood database", "err", err)
+	}
+}
@@ -11,6 +11,8 @@ import (
 
 	"github.com/spf13/pflag"
 	"github.com/spf13/viper"
+
+	"github.com/MetalBlockchain/coreth/cmd/simulator/workload"
 )
 
 const Version = "v0.1.1"
@@ -29,12 +31,26 @@ const (
 	BatchSizeKey      = "batch-size"
 	MetricsPortKey    = "metrics-port"
 	MetricsOutputKey  = "metrics-output"
+	WorkloadsKey      = "workloads"
+	StorageSlotsKey   = "storage-slots"
+	TargetTPSKey      = "target-tps"
+	RampUpKey         = "ramp-up"
+	DurationKey       = "duration"
+	RampDownKey       = "ramp-down"
+
+	AtomicURIKey              = "atomic-uri"
+	AtomicDestinationChainKey = "atomic-destination-chain"
+	AtomicAssetIDKey          = "atomic-asset-id"
+	AtomicExportAmountKey     = "atomic-export-amount"
 )
 
 var (
 	ErrNoEndpoints = errors.New("must specify at least one endpoint")
 	ErrNoWorkers   = errors.New("must specify non-zero number of workers")
 	ErrNoTxs       = errors.New("must specify non-zero number of txs-per-worker")
+	ErrNoWorkloads = errors.New("must specify at least one workload")
+	ErrNoAssetID   = errors.New("must specify atomic-asset-id to run the atomic workload")
+	ErrNoSchedule  = errors.New("must specify a non-zero ramp-up, duration or ramp-down with target-tps")
 )
 
 type Config struct {
@@ -48,6 +64,17 @@ type Config struct {
 	BatchSize     uint64        `json:"batch-size"`
 	MetricsPort   uint64        `json:"metrics-port"`
 	MetricsOutput string        `json:"metrics-output"`
+	Workloads     []string      `json:"workloads"`
+	StorageSlots  uint64        `json:"storage-slots"`
+	TargetTPS     float64       `json:"target-tps"`
+	RampUp        time.Duration `json:"ramp-up"`
+	Duration      time.Duration `json:"duration"`
+	RampDown      time.Duration `json:"ramp-down"`
+
+	AtomicURI              string `json:"atomic-uri"`
+	AtomicDestinationChain string `json:"atomic-destination-chain"`
+	AtomicAssetID          string `json:"atomic-asset-id"`
+	AtomicExportAmount     uint64 `json:"atomic-export-amount"`
 }
 
 func BuildConfig(v *viper.Viper) (Config, error) {
@@ -62,6 +89,17 @@ func BuildConfig(v *viper.Viper) (Config, error) {
 		BatchSize:     v.GetUint64(BatchSizeKey),
 		MetricsPort:   v.GetUint64(MetricsPortKey),
 		MetricsOutput: v.GetString(MetricsOutputKey),
+		Workloads:     v.GetStringSlice(WorkloadsKey),
+		StorageSlots:  v.GetUint64(StorageSlotsKey),
+		TargetTPS:     v.GetFloat64(TargetTPSKey),
+		RampUp:        v.GetDuration(RampUpKey),
+		Duration:      v.GetDuration(DurationKey),
+		RampDown:      v.GetDuration(RampDownKey),
+
+		AtomicURI:              v.GetString(AtomicURIKey),
+		AtomicDestinationChain: v.GetString(AtomicDestinationChainKey),
+		AtomicAssetID:          v.GetString(AtomicAssetIDKey),
+		AtomicExportAmount:     v.GetUint64(AtomicExportAmountKey),
 	}
 	if len(c.Endpoints) == 0 {
 		return c, ErrNoEndpoints
@@ -69,9 +107,27 @@ func BuildConfig(v *viper.Viper) (Config, error) {
 	if c.Workers == 0 {
 		return c, ErrNoWorkers
 	}
-	if c.TxsPerWorker == 0 {
+	if c.TargetTPS < 0 {
+		return c, fmt.Errorf("invalid target tps %f < 0", c.TargetTPS)
+	}
+	if c.OpenLoop() {
+		if c.RampUp+c.Duration+c.RampDown <= 0 {
+			return c, ErrNoSchedule
+		}
+	} else if c.TxsPerWorker == 0 {
 		return c, ErrNoTxs
 	}
+	if len(c.Workloads) == 0 {
+		return c, ErrNoWorkloads
+	}
+	for _, name := range c.Workloads {
+		if err := workload.Verify(name); err != nil {
+			return c, err
+		}
+		if name == workload.Atomic && c.AtomicAssetID == "" {
+			return c, ErrNoAssetID
+		}
+	}
 	// Note: it's technically valid for the fee/tip cap to be 0, but cannot
 	// be less than 0.
 	if c.MaxFeeCap < 0 {
@@ -83,6 +139,12 @@ func BuildConfig(v *viper.Viper) (Config, error) {
 	return c, nil
 }
 
+// OpenLoop returns true if txs should be issued at [TargetTPS] rather than in
+// batches of [BatchSize].
+func (c Config) OpenLoop() bool {
+	return c.TargetTPS > 0
+}
+
 func BuildViper(fs *pflag.FlagSet, args []string) (*viper.Viper, error) {
 	if err := fs.Parse(args); err != nil {
 		return nil, err
@@ -125,5 +187,15 @@ func addSimulatorFlags(fs *pflag.FlagSet) {
 	fs.String(LogLevelKey, "info", "Specify the log level to use in the simulator")
 	fs.Uint64(BatchSizeKey, 100, "Specify the batchsize for the worker to issue and confirm txs")
 	fs.Uint64(MetricsPortKey, 8082, "Specify the port to use for the metrics server")
-	fs.String(MetricsOutputKey, "", "Specify the file to write metrics in json format, or empty to write to stdout (defaults to stdout)")
+	fs.String(MetricsOutputKey, "", "Specify the file to write the versioned json summary of the issued txs, their latencies per workload and the metrics, or empty to write to stdout (defaults to stdout)")
+	fs.StringSlice(WorkloadsKey, []string{workload.Transfer}, fmt.Sprintf("Specify a comma separated list of workloads assigned to workers in round-robin order (supported: %s)", strings.Join(workload.Names(), ", ")))
+	fs.Float64(TargetTPSKey, 0, "Specify the total rate of transactions per second to issue across all workers in open-loop mode, ignoring txs-per-worker and batch-size (0 runs closed-loop batches)")
+	fs.Duration(RampUpKey, 0, "Specify the time over which the open-loop rate ramps up from 0 to target-tps")
+	fs.Duration(DurationKey, time.Minute, "Specify the time for which the open-loop rate is sustained at target-tps")
+	fs.Duration(RampDownKey, 0, "Specify the time over which the open-loop rate ramps down from target-tps to 0")
+	fs.Uint64(StorageSlotsKey, 10, "Specify the number of fresh storage slots written by each transaction of the storage workload")
+	fs.String(AtomicURIKey, "http://127.0.0.1:9650", "Specify the node URI to issue atomic transactions to")
+	fs.String(AtomicDestinationChainKey, "X", "Specify the chain the atomic workload exports to and imports from")
+	fs.String(AtomicAssetIDKey, "", "Specify the ID of the AVAX asset (required by the atomic workload)")
+	fs.Uint64(AtomicExportAmountKey, 1_000_000, "Specify the amount of nAVAX exported by each transaction of the atomic workload")
 }
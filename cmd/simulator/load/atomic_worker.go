@@ -0,0 +1,39 @@
+// Copyright (C) 2019-2025, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package load
+
+import (
+	"context"
+
+	"github.com/MetalBlockchain/coreth/cmd/simulator/workload"
+	"github.com/MetalBlockchain/coreth/ethclient"
+	"github.com/MetalBlockchain/coreth/plugin/evm/client"
+)
+
+type atomicTxWorker struct {
+	client     *ethclient.Client
+	avaxClient client.Client
+}
+
+// NewAtomicTxWorker creates and returns a new atomicTxWorker that issues atomic transactions through
+// [avaxClient] and confirms them by polling their status until they are accepted.
+func NewAtomicTxWorker(client *ethclient.Client, avaxClient client.Client) *atomicTxWorker {
+	return &atomicTxWorker{
+		client:     client,
+		avaxClient: avaxClient,
+	}
+}
+
+func (aw *atomicTxWorker) IssueTx(ctx context.Context, tx *workload.AtomicTx) error {
+	_, err := aw.avaxClient.IssueTx(ctx, tx.SignedBytes())
+	return err
+}
+
+func (aw *atomicTxWorker) ConfirmTx(ctx context.Context, tx *workload.AtomicTx) error {
+	return workload.AwaitAtomicTx(ctx, aw.avaxClient, tx.ID())
+}
+
+func (aw *atomicTxWorker) LatestHeight(ctx context.Context) (uint64, error) {
+	return aw.client.BlockNumber(ctx)
+}
@@ -14,7 +14,8 @@ import (
 	"syscall"
 	"time"
 
-	"github.com/MetalBlockchain/libevm/common"
+	"github.com/MetalBlockchain/metalgo/api/info"
+	"github.com/MetalBlockchain/metalgo/ids"
 	"github.com/MetalBlockchain/libevm/core/types"
 	"github.com/MetalBlockchain/libevm/log"
 	"golang.org/x/sync/errgroup"
@@ -23,11 +24,12 @@ import (
 	"github.com/MetalBlockchain/coreth/cmd/simulator/key"
 	"github.com/MetalBlockchain/coreth/cmd/simulator/metrics"
 	"github.com/MetalBlockchain/coreth/cmd/simulator/txs"
+	"github.com/MetalBlockchain/coreth/cmd/simulator/workload"
 	"github.com/MetalBlockchain/coreth/ethclient"
 	"github.com/MetalBlockchain/coreth/params"
 
+	evmclient "github.com/MetalBlockchain/coreth/plugin/evm/client"
 	ethcrypto "github.com/MetalBlockchain/libevm/crypto"
-	ethparams "github.com/MetalBlockchain/libevm/params"
 )
 
 const (
@@ -38,10 +40,13 @@ const (
 // Each worker/txSequence pair issues [batchSize] transactions, confirms all
 // of them as accepted, and then moves to the next batch until the txSequence
 // is exhausted.
+// If the Loader has a schedule, each worker/txSequence pair instead issues
+// transactions at the rate of the schedule without waiting for confirmations.
 type Loader[T txs.THash] struct {
 	clients     []txs.Worker[T]
 	txSequences []txs.TxSequence[T]
 	batchSize   uint64
+	schedule    txs.Schedule
 	metrics     *metrics.Metrics
 }
 
@@ -59,10 +64,30 @@ func New[T txs.THash](
 	}
 }
 
+// NewOpenLoop returns a Loader issuing transactions from each worker at the
+// rate of [schedule].
+func NewOpenLoop[T txs.THash](
+	clients []txs.Worker[T],
+	txSequences []txs.TxSequence[T],
+	schedule txs.Schedule,
+	metrics *metrics.Metrics,
+) *Loader[T] {
+	return &Loader[T]{
+		clients:     clients,
+		txSequences: txSequences,
+		schedule:    schedule,
+		metrics:     metrics,
+	}
+}
+
 func (l *Loader[T]) Execute(ctx context.Context) error {
-	log.Info("Constructing tx agents...", "numAgents", len(l.txSequences))
+	log.Info("Constructing tx agents...", "numAgents", len(l.txSequences), "openLoop", l.schedule != nil)
 	agents := make([]txs.Agent[T], 0, len(l.txSequences))
 	for i := 0; i < len(l.txSequences); i++ {
+		if l.schedule != nil {
+			agents = append(agents, txs.NewOpenLoopAgent(l.txSequences[i], l.clients[i], l.schedule, l.metrics))
+			continue
+		}
 		agents = append(agents, txs.NewIssueNAgent(l.txSequences[i], l.clients[i], l.batchSize, l.metrics))
 	}
 
@@ -183,12 +208,77 @@ func ExecuteLoader(ctx context.Context, config config.Config) error {
 		}
 	}
 
-	// Each address needs: params.GWei * MaxFeeCap * ethparams.TxGas * TxsPerWorker total wei
-	// to fund gas for all of their transactions.
-	maxFeeCap := new(big.Int).Mul(big.NewInt(params.GWei), big.NewInt(config.MaxFeeCap))
-	minFundsPerAddr := new(big.Int).Mul(maxFeeCap, big.NewInt(int64(config.TxsPerWorker*ethparams.TxGas)))
+	bigGwei := big.NewInt(params.GWei)
+	gasTipCap := new(big.Int).Mul(bigGwei, big.NewInt(config.MaxTipCap))
+	gasFeeCap := new(big.Int).Mul(bigGwei, big.NewInt(config.MaxFeeCap))
+	client := clients[0]
+	chainID, err := client.ChainID(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to fetch chainID: %w", err)
+	}
+	txParams := workload.TxParams{
+		ChainID:      chainID,
+		Signer:       types.LatestSignerForChainID(chainID),
+		GasTipCap:    gasTipCap,
+		GasFeeCap:    gasFeeCap,
+		StorageSlots: config.StorageSlots,
+	}
+
+	opts := loaderOptions{
+		txsPerWorker: config.TxsPerWorker,
+		batchSize:    config.BatchSize,
+	}
+	if config.OpenLoop() {
+		opts.schedule = txs.NewRampSchedule(config.TargetTPS/float64(config.Workers), config.RampUp, config.Duration, config.RampDown)
+		opts.txsPerWorker = opts.schedule.NumTxs()
+		log.Info("Running open-loop schedule", "targetTPS", config.TargetTPS, "rampUp", config.RampUp, "duration", config.Duration, "rampDown", config.RampDown, "numTxsPerWorker", opts.txsPerWorker)
+	}
+
+	// Assign workloads to workers in round-robin order.
+	workloadWorkers := make(map[string][]int)
+	workloadNames := make([]string, 0, len(config.Workloads))
+	for i := 0; i < config.Workers; i++ {
+		name := config.Workloads[i%len(config.Workloads)]
+		if _, ok := workloadWorkers[name]; !ok {
+			workloadNames = append(workloadNames, name)
+		}
+		workloadWorkers[name] = append(workloadWorkers[name], i)
+	}
+
+	var (
+		atomicWorkload *workload.AtomicWorkload
+		avaxClient     evmclient.Client
+	)
+	if _, ok := workloadWorkers[workload.Atomic]; ok {
+		avaxClient = evmclient.NewCChainClient(config.AtomicURI)
+		atomicWorkload, err = newAtomicWorkload(ctx, config, client, avaxClient, gasFeeCap)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Each address needs enough funds to pay for its workload's setup and
+	// TxsPerWorker transactions. For EVM workloads this is
+	// params.GWei * MaxFeeCap * gas total wei.
+	minFundsPerAddr := new(big.Int)
+	for _, name := range workloadNames {
+		var funds *big.Int
+		if name == workload.Atomic {
+			fundsPerTx, err := atomicWorkload.FundsPerTx()
+			if err != nil {
+				return fmt.Errorf("failed to calculate atomic tx funds: %w", err)
+			}
+			funds = new(big.Int).Mul(fundsPerTx, new(big.Int).SetUint64(opts.txsPerWorker))
+		} else {
+			gas := workload.SetupGas(name) + opts.txsPerWorker*workload.GasPerTx(name, txParams)
+			funds = new(big.Int).Mul(gasFeeCap, new(big.Int).SetUint64(gas))
+		}
+		if funds.Cmp(minFundsPerAddr) > 0 {
+			minFundsPerAddr = funds
+		}
+	}
 	fundStart := time.Now()
-	log.Info("Distributing funds", "numTxsPerWorker", config.TxsPerWorker, "minFunds", minFundsPerAddr)
+	log.Info("Distributing funds", "numTxsPerWorker", opts.txsPerWorker, "minFunds", minFundsPerAddr)
 	keys, err = DistributeFunds(ctx, clients[0], keys, config.Workers, minFundsPerAddr, m)
 	if err != nil {
 		return err
@@ -196,52 +286,166 @@ func ExecuteLoader(ctx context.Context, config config.Config) error {
 	log.Info("Distributed funds successfully", "time", time.Since(fundStart))
 
 	pks := make([]*ecdsa.PrivateKey, 0, len(keys))
-	senders := make([]common.Address, 0, len(keys))
 	for _, key := range keys {
 		pks = append(pks, key.PrivKey)
-		senders = append(senders, key.Address)
 	}
 
-	bigGwei := big.NewInt(params.GWei)
-	gasTipCap := new(big.Int).Mul(bigGwei, big.NewInt(config.MaxTipCap))
-	gasFeeCap := new(big.Int).Mul(bigGwei, big.NewInt(config.MaxFeeCap))
-	client := clients[0]
-	chainID, err := client.ChainID(ctx)
-	if err != nil {
-		return fmt.Errorf("failed to fetch chainID: %w", err)
-	}
-	signer := types.LatestSignerForChainID(chainID)
-
-	log.Info("Creating transaction sequences...")
-	txGenerator := func(key *ecdsa.PrivateKey, nonce uint64) (*types.Transaction, error) {
-		addr := ethcrypto.PubkeyToAddress(key.PublicKey)
-		return types.SignNewTx(key, signer, &types.DynamicFeeTx{
-			ChainID:   chainID,
-			Nonce:     nonce,
-			GasTipCap: gasTipCap,
-			GasFeeCap: gasFeeCap,
-			Gas:       ethparams.TxGas,
-			To:        &addr,
-			Data:      nil,
-			Value:     common.Big0,
-		})
-	}
+	log.Info("Creating transaction sequences...", "workloads", workloadNames)
 	txSequenceStart := time.Now()
-	txSequences, err := txs.GenerateTxSequences(ctx, txGenerator, clients[0], pks, config.TxsPerWorker, false)
-	if err != nil {
-		return err
+	executors := make([]func(context.Context) error, 0, len(workloadNames))
+	for _, name := range workloadNames {
+		workers := workloadWorkers[name]
+		workloadClients := make([]*ethclient.Client, 0, len(workers))
+		workloadKeys := make([]*ecdsa.PrivateKey, 0, len(workers))
+		for _, i := range workers {
+			workloadClients = append(workloadClients, clients[i])
+			workloadKeys = append(workloadKeys, pks[i])
+		}
+
+		workloadMetrics := m.ForWorkload(name)
+		if name == workload.Atomic {
+			loader, err := newAtomicLoader(ctx, atomicWorkload, avaxClient, workloadClients, workloadKeys, opts, workloadMetrics)
+			if err != nil {
+				return err
+			}
+			executors = append(executors, loader.Execute)
+			continue
+		}
+		loader, err := newEVMLoader(ctx, name, txParams, workloadClients, workloadKeys, opts, workloadMetrics)
+		if err != nil {
+			return err
+		}
+		executors = append(executors, loader.Execute)
 	}
 	log.Info("Created transaction sequences successfully", "time", time.Since(txSequenceStart))
 
-	workers := make([]txs.Worker[*types.Transaction], 0, len(clients))
-	for i, client := range clients {
-		workers = append(workers, NewSingleAddressTxWorker(client, ethcrypto.PubkeyToAddress(pks[i].PublicKey)))
+	eg := errgroup.Group{}
+	for _, execute := range executors {
+		eg.Go(func() error {
+			return execute(ctx)
+		})
 	}
-	loader := New(workers, txSequences, config.BatchSize, m)
-	err = loader.Execute(ctx)
+	err = eg.Wait()
 	prerr := m.Print(config.MetricsOutput) // Print regardless of execution error
 	if prerr != nil {
 		log.Warn("Failed to print metrics", "error", prerr)
 	}
 	return err
 }
+
+// loaderOptions determine how many transactions each worker issues and how
+// they are scheduled.
+type loaderOptions struct {
+	txsPerWorker uint64
+	batchSize    uint64
+	// schedule is the open-loop schedule, or nil to issue closed-loop batches
+	// of [batchSize].
+	schedule txs.Schedule
+}
+
+func newLoader[T txs.THash](workers []txs.Worker[T], txSequences []txs.TxSequence[T], opts loaderOptions, m *metrics.Metrics) *Loader[T] {
+	if opts.schedule != nil {
+		return NewOpenLoop(workers, txSequences, opts.schedule, m)
+	}
+	return New(workers, txSequences, opts.batchSize, m)
+}
+
+// newEVMLoader sets up the EVM workload [name] for each of [keys] and returns
+// a Loader issuing the transactions of the workload from each key.
+func newEVMLoader(
+	ctx context.Context,
+	name string,
+	txParams workload.TxParams,
+	clients []*ethclient.Client,
+	keys []*ecdsa.PrivateKey,
+	opts loaderOptions,
+	m *metrics.Metrics,
+) (*Loader[*types.Transaction], error) {
+	workloads := make([]workload.EVM, len(keys))
+	eg, egCtx := errgroup.WithContext(ctx)
+	for i, key := range keys {
+		w, err := workload.NewEVM(name, txParams)
+		if err != nil {
+			return nil, err
+		}
+		workloads[i] = w
+		eg.Go(func() error {
+			return w.Setup(egCtx, clients[i], key)
+		})
+	}
+	if err := eg.Wait(); err != nil {
+		return nil, fmt.Errorf("failed to set up %s workload: %w", name, err)
+	}
+
+	txSequences := make([]txs.TxSequence[*types.Transaction], 0, len(keys))
+	workers := make([]txs.Worker[*types.Transaction], 0, len(keys))
+	for i, key := range keys {
+		txSequence, err := txs.GenerateTxSequence(ctx, workloads[i].CreateTx, clients[i], key, opts.txsPerWorker, false)
+		if err != nil {
+			return nil, err
+		}
+		txSequences = append(txSequences, txSequence)
+		workers = append(workers, NewSingleAddressTxWorker(clients[i], ethcrypto.PubkeyToAddress(key.PublicKey)))
+	}
+	return newLoader(workers, txSequences, opts, m), nil
+}
+
+// newAtomicLoader returns a Loader issuing the atomic transactions of
+// [atomicWorkload] from each of [keys].
+func newAtomicLoader(
+	ctx context.Context,
+	atomicWorkload *workload.AtomicWorkload,
+	avaxClient evmclient.Client,
+	clients []*ethclient.Client,
+	keys []*ecdsa.PrivateKey,
+	opts loaderOptions,
+	m *metrics.Metrics,
+) (*Loader[*workload.AtomicTx], error) {
+	txSequences := make([]txs.TxSequence[*workload.AtomicTx], 0, len(keys))
+	workers := make([]txs.Worker[*workload.AtomicTx], 0, len(keys))
+	for i, key := range keys {
+		txSequence, err := atomicWorkload.GenerateTxSequence(ctx, key, opts.txsPerWorker)
+		if err != nil {
+			return nil, err
+		}
+		txSequences = append(txSequences, txSequence)
+		workers = append(workers, NewAtomicTxWorker(clients[i], avaxClient))
+	}
+	// Each atomic tx is only built once the previous one from the same key has
+	// been accepted, so they are always issued one at a time.
+	if opts.schedule != nil || opts.batchSize != 1 {
+		log.Warn("Ignoring batch size and target TPS for atomic workload", "batchSize", opts.batchSize)
+	}
+	return New(workers, txSequences, 1, m), nil
+}
+
+// newAtomicWorkload returns the atomic workload described by [config], using
+// the node at [config.AtomicURI] to look up the network and chain IDs. The
+// atomic txs are issued at a base fee of at most [maxBaseFee].
+func newAtomicWorkload(ctx context.Context, config config.Config, client *ethclient.Client, avaxClient evmclient.Client, maxBaseFee *big.Int) (*workload.AtomicWorkload, error) {
+	avaxAssetID, err := ids.FromString(config.AtomicAssetID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse atomic asset ID %q: %w", config.AtomicAssetID, err)
+	}
+	infoClient := info.NewClient(config.AtomicURI)
+	networkID, err := infoClient.GetNetworkID(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch network ID: %w", err)
+	}
+	blockchainID, err := infoClient.GetBlockchainID(ctx, "C")
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch C-Chain ID: %w", err)
+	}
+	destinationChainID, err := infoClient.GetBlockchainID(ctx, config.AtomicDestinationChain)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch ID of chain %q: %w", config.AtomicDestinationChain, err)
+	}
+	return workload.NewAtomicWorkload(workload.AtomicParams{
+		NetworkID:          networkID,
+		BlockchainID:       blockchainID,
+		DestinationChainID: destinationChainID,
+		AVAXAssetID:        avaxAssetID,
+		ExportAmount:       config.AtomicExportAmount,
+		MaxBaseFee:         maxBaseFee,
+	}, client, avaxClient), nil
+}
@@ -10,20 +10,42 @@ import (
 	"fmt"
 	"net/http"
 	"os"
+	"sync"
+	"time"
 
 	"github.com/MetalBlockchain/libevm/log"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 )
 
+const workloadLabel = "workload"
+
+// DefaultWorkload is the workload label used by [NewMetrics].
+const DefaultWorkload = "default"
+
 type Metrics struct {
 	reg *prometheus.Registry
+
+	// workloads holds the stats of every workload reporting to [reg]
+	workloads *workloads
+	stats     *workloadStats
+
+	issuanceTxTimes               *prometheus.SummaryVec
+	confirmationTxTimes           *prometheus.SummaryVec
+	issuanceToConfirmationTxTimes *prometheus.SummaryVec
+	issuedTxs                     *prometheus.CounterVec
+	confirmedTxs                  *prometheus.CounterVec
+
 	// Summary of the quantiles of Individual Issuance Tx Times
-	IssuanceTxTimes prometheus.Summary
+	IssuanceTxTimes prometheus.Observer
 	// Summary of the quantiles of Individual Confirmation Tx Times
-	ConfirmationTxTimes prometheus.Summary
+	ConfirmationTxTimes prometheus.Observer
 	// Summary of the quantiles of Individual Issuance To Confirmation Tx Times
-	IssuanceToConfirmationTxTimes prometheus.Summary
+	IssuanceToConfirmationTxTimes prometheus.Observer
+	// Number of txs issued
+	IssuedTxs prometheus.Counter
+	// Number of txs confirmed
+	ConfirmedTxs prometheus.Counter
 }
 
 func NewDefaultMetrics() *Metrics {
@@ -34,27 +56,105 @@ func NewDefaultMetrics() *Metrics {
 // NewMetrics creates and returns a Metrics and registers it with a Collector
 func NewMetrics(reg *prometheus.Registry) *Metrics {
 	m := &Metrics{
-		reg: reg,
-		IssuanceTxTimes: prometheus.NewSummary(prometheus.SummaryOpts{
+		reg:       reg,
+		workloads: &workloads{stats: make(map[string]*workloadStats)},
+		issuanceTxTimes: prometheus.NewSummaryVec(prometheus.SummaryOpts{
 			Name:       "tx_issuance_time",
 			Help:       "Individual Tx Issuance Times for a Load Test",
 			Objectives: map[float64]float64{0.5: 0.05, 0.9: 0.01, 0.99: 0.001},
-		}),
-		ConfirmationTxTimes: prometheus.NewSummary(prometheus.SummaryOpts{
+		}, []string{workloadLabel}),
+		confirmationTxTimes: prometheus.NewSummaryVec(prometheus.SummaryOpts{
 			Name:       "tx_confirmation_time",
 			Help:       "Individual Tx Confirmation Times for a Load Test",
 			Objectives: map[float64]float64{0.5: 0.05, 0.9: 0.01, 0.99: 0.001},
-		}),
-		IssuanceToConfirmationTxTimes: prometheus.NewSummary(prometheus.SummaryOpts{
+		}, []string{workloadLabel}),
+		issuanceToConfirmationTxTimes: prometheus.NewSummaryVec(prometheus.SummaryOpts{
 			Name:       "tx_issuance_to_confirmation_time",
 			Help:       "Individual Tx Issuance To Confirmation Times for a Load Test",
 			Objectives: map[float64]float64{0.5: 0.05, 0.9: 0.01, 0.99: 0.001},
-		}),
+		}, []string{workloadLabel}),
+		issuedTxs: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "txs_issued",
+			Help: "Number of Txs Issued for a Load Test",
+		}, []string{workloadLabel}),
+		confirmedTxs: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "txs_confirmed",
+			Help: "Number of Txs Confirmed for a Load Test",
+		}, []string{workloadLabel}),
+	}
+	reg.MustRegister(m.issuanceTxTimes)
+	reg.MustRegister(m.confirmationTxTimes)
+	reg.MustRegister(m.issuanceToConfirmationTxTimes)
+	reg.MustRegister(m.issuedTxs)
+	reg.MustRegister(m.confirmedTxs)
+	return m.ForWorkload(DefaultWorkload)
+}
+
+// ForWorkload returns a Metrics reporting to the same registry as [m], with
+// each metric labeled by [workload].
+func (m *Metrics) ForWorkload(workload string) *Metrics {
+	return &Metrics{
+		reg:                           m.reg,
+		workloads:                     m.workloads,
+		stats:                         m.workloads.get(workload),
+		issuanceTxTimes:               m.issuanceTxTimes,
+		confirmationTxTimes:           m.confirmationTxTimes,
+		issuanceToConfirmationTxTimes: m.issuanceToConfirmationTxTimes,
+		issuedTxs:                     m.issuedTxs,
+		confirmedTxs:                  m.confirmedTxs,
+		IssuanceTxTimes:               m.issuanceTxTimes.WithLabelValues(workload),
+		ConfirmationTxTimes:           m.confirmationTxTimes.WithLabelValues(workload),
+		IssuanceToConfirmationTxTimes: m.issuanceToConfirmationTxTimes.WithLabelValues(workload),
+		IssuedTxs:                     m.issuedTxs.WithLabelValues(workload),
+		ConfirmedTxs:                  m.confirmedTxs.WithLabelValues(workload),
+	}
+}
+
+// TxIssued records that a tx was issued at [issuedAt].
+func (m *Metrics) TxIssued(issuedAt time.Time) {
+	m.IssuedTxs.Inc()
+	m.stats.txIssued(issuedAt)
+}
+
+// TxConfirmed records that a tx issued at [issuedAt] was confirmed at
+// [confirmedAt].
+func (m *Metrics) TxConfirmed(issuedAt, confirmedAt time.Time) {
+	m.IssuanceToConfirmationTxTimes.Observe(confirmedAt.Sub(issuedAt).Seconds())
+	m.ConfirmedTxs.Inc()
+	m.stats.txConfirmed(issuedAt, confirmedAt)
+}
+
+type workloads struct {
+	lock  sync.Mutex
+	stats map[string]*workloadStats
+}
+
+func (w *workloads) get(workload string) *workloadStats {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	stats, ok := w.stats[workload]
+	if !ok {
+		stats = &workloadStats{}
+		w.stats[workload] = stats
 	}
-	reg.MustRegister(m.IssuanceTxTimes)
-	reg.MustRegister(m.ConfirmationTxTimes)
-	reg.MustRegister(m.IssuanceToConfirmationTxTimes)
-	return m
+	return stats
+}
+
+// Summary returns the summary of every workload reporting to the registry of
+// [m].
+func (m *Metrics) Summary() Summary {
+	m.workloads.lock.Lock()
+	defer m.workloads.lock.Unlock()
+
+	summary := Summary{
+		Version:   SummaryVersion,
+		Workloads: make(map[string]WorkloadSummary, len(m.workloads.stats)),
+	}
+	for workload, stats := range m.workloads.stats {
+		summary.Workloads[workload] = stats.summary()
+	}
+	return summary
 }
 
 type MetricsServer struct {
@@ -117,6 +217,11 @@ func (m *Metrics) Print(outputFile string) error {
 				fmt.Printf("Type: %s, Name: %s, Description: %s, Values: %s\n", mf.GetType().String(), mf.GetName(), mf.GetHelp(), m.String())
 			}
 		}
+		for workload, ws := range m.Summary().Workloads {
+			l := ws.IssuanceToConfirmation
+			fmt.Printf("Workload: %s, Issued: %d, Confirmed: %d, TPS: %.2f, Latency (s): min=%.3f mean=%.3f p50=%.3f p90=%.3f p95=%.3f p99=%.3f max=%.3f\n",
+				workload, ws.IssuedTxs, ws.ConfirmedTxs, ws.ConfirmedTPS, l.Min, l.Mean, l.P50, l.P90, l.P95, l.P99, l.Max)
+		}
 		fmt.Println("***************")
 	} else {
 		jsonFile, err := os.Create(outputFile)
@@ -125,7 +230,9 @@ func (m *Metrics) Print(outputFile string) error {
 		}
 		defer jsonFile.Close()
 
-		if err := json.NewEncoder(jsonFile).Encode(metrics); err != nil {
+		summary := m.Summary()
+		summary.Metrics = metrics
+		if err := json.NewEncoder(jsonFile).Encode(summary); err != nil {
 			return err
 		}
 	}
@@ -0,0 +1,130 @@
+// Copyright (C) 2019-2025, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package metrics
+
+import (
+	"math"
+	"slices"
+	"sync"
+	"time"
+
+	dto "github.com/prometheus/client_model/go"
+)
+
+// SummaryVersion is the version of the format of [Summary], which is
+// incremented on every incompatible change.
+const SummaryVersion = 1
+
+// Summary is the result of a load test, written to the metrics output.
+type Summary struct {
+	Version   uint32                     `json:"version"`
+	Workloads map[string]WorkloadSummary `json:"workloads"`
+	// Metrics holds the Prometheus metrics gathered at the end of the load
+	// test.
+	Metrics []*dto.MetricFamily `json:"metrics,omitempty"`
+}
+
+// WorkloadSummary summarizes the txs issued by a single workload.
+type WorkloadSummary struct {
+	IssuedTxs    uint64 `json:"issuedTxs"`
+	ConfirmedTxs uint64 `json:"confirmedTxs"`
+	// Duration is the time in seconds between the first issuance and the last
+	// confirmation of the workload.
+	Duration float64 `json:"duration"`
+	// ConfirmedTPS is the number of confirmed txs per second over [Duration].
+	ConfirmedTPS float64 `json:"confirmedTPS"`
+	// IssuanceToConfirmation summarizes the time in seconds between the
+	// issuance and the confirmation of each tx.
+	IssuanceToConfirmation LatencySummary `json:"issuanceToConfirmation"`
+}
+
+// LatencySummary holds the percentiles of a set of latencies, in seconds.
+type LatencySummary struct {
+	Min  float64 `json:"min"`
+	Mean float64 `json:"mean"`
+	P50  float64 `json:"p50"`
+	P90  float64 `json:"p90"`
+	P95  float64 `json:"p95"`
+	P99  float64 `json:"p99"`
+	Max  float64 `json:"max"`
+}
+
+// workloadStats records every tx issued and confirmed by a workload, so that
+// exact latency percentiles can be reported at the end of a load test.
+type workloadStats struct {
+	lock sync.Mutex
+
+	issued        uint64
+	firstIssuance time.Time
+	lastConfirm   time.Time
+	latencies     []time.Duration
+}
+
+func (s *workloadStats) txIssued(issuedAt time.Time) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.issued++
+	if s.firstIssuance.IsZero() || issuedAt.Before(s.firstIssuance) {
+		s.firstIssuance = issuedAt
+	}
+}
+
+func (s *workloadStats) txConfirmed(issuedAt, confirmedAt time.Time) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.latencies = append(s.latencies, confirmedAt.Sub(issuedAt))
+	if confirmedAt.After(s.lastConfirm) {
+		s.lastConfirm = confirmedAt
+	}
+}
+
+func (s *workloadStats) summary() WorkloadSummary {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	summary := WorkloadSummary{
+		IssuedTxs:              s.issued,
+		ConfirmedTxs:           uint64(len(s.latencies)),
+		IssuanceToConfirmation: summarizeLatencies(s.latencies),
+	}
+	if s.lastConfirm.After(s.firstIssuance) {
+		summary.Duration = s.lastConfirm.Sub(s.firstIssuance).Seconds()
+		summary.ConfirmedTPS = float64(summary.ConfirmedTxs) / summary.Duration
+	}
+	return summary
+}
+
+func summarizeLatencies(latencies []time.Duration) LatencySummary {
+	if len(latencies) == 0 {
+		return LatencySummary{}
+	}
+	sorted := slices.Clone(latencies)
+	slices.Sort(sorted)
+
+	var total time.Duration
+	for _, latency := range sorted {
+		total += latency
+	}
+	return LatencySummary{
+		Min:  sorted[0].Seconds(),
+		Mean: (total / time.Duration(len(sorted))).Seconds(),
+		P50:  percentile(sorted, 0.50).Seconds(),
+		P90:  percentile(sorted, 0.90).Seconds(),
+		P95:  percentile(sorted, 0.95).Seconds(),
+		P99:  percentile(sorted, 0.99).Seconds(),
+		Max:  sorted[len(sorted)-1].Seconds(),
+	}
+}
+
+// percentile returns the [p] percentile of [sorted] using the nearest-rank
+// method.
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	rank := int(math.Ceil(p * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
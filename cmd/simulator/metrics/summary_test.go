@@ -0,0 +1,56 @@
+// Copyright (C) 2019-2025, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package metrics
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSummarizeLatencies(t *testing.T) {
+	var latencies []time.Duration
+	for i := 100; i > 0; i-- {
+		latencies = append(latencies, time.Duration(i)*time.Millisecond)
+	}
+	require.Equal(t, LatencySummary{
+		Min:  0.001,
+		Mean: 0.0505,
+		P50:  0.050,
+		P90:  0.090,
+		P95:  0.095,
+		P99:  0.099,
+		Max:  0.100,
+	}, summarizeLatencies(latencies))
+	require.Equal(t, LatencySummary{}, summarizeLatencies(nil))
+}
+
+func TestSummary(t *testing.T) {
+	require := require.New(t)
+
+	m := NewMetrics(prometheus.NewRegistry()).ForWorkload("transfer")
+	start := time.Unix(100, 0)
+	m.TxIssued(start)
+	m.TxIssued(start.Add(time.Second))
+	m.TxConfirmed(start, start.Add(time.Second))
+	m.TxConfirmed(start.Add(time.Second), start.Add(2*time.Second))
+
+	summaryJSON, err := json.Marshal(m.Summary())
+	require.NoError(err)
+	require.JSONEq(`{
+		"version": 1,
+		"workloads": {
+			"transfer": {
+				"issuedTxs": 2,
+				"confirmedTxs": 2,
+				"duration": 2,
+				"confirmedTPS": 1,
+				"issuanceToConfirmation": {"min": 1, "mean": 1, "p50": 1, "p90": 1, "p95": 1, "p99": 1, "max": 1}
+			}
+		}
+	}`, string(summaryJSON))
+}
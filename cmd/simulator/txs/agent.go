@@ -24,6 +24,9 @@ type THash interface {
 // transactions.
 type TxSequence[T THash] interface {
 	Chan() <-chan T
+	// Err returns the error which ended the sequence early, if any. It may
+	// only be called once the channel is closed.
+	Err() error
 }
 
 // Worker defines the interface for issuance and confirmation of transactions.
@@ -102,6 +105,7 @@ func (a issueNAgent[T]) Execute(ctx context.Context) error {
 				}
 				issuanceIndividualDuration := time.Since(issuanceIndividualStart)
 				m.IssuanceTxTimes.Observe(issuanceIndividualDuration.Seconds())
+				m.TxIssued(issuanceIndividualStart)
 				txs = append(txs, tx)
 			}
 		}
@@ -118,9 +122,8 @@ func (a issueNAgent[T]) Execute(ctx context.Context) error {
 				return fmt.Errorf("failed to await transaction %d: %w", i, err)
 			}
 			confirmationIndividualDuration := time.Since(confirmedIndividualStart)
-			issuanceToConfirmationIndividualDuration := time.Since(txMap[tx.Hash()])
 			m.ConfirmationTxTimes.Observe(confirmationIndividualDuration.Seconds())
-			m.IssuanceToConfirmationTxTimes.Observe(issuanceToConfirmationIndividualDuration.Seconds())
+			m.TxConfirmed(txMap[tx.Hash()], time.Now())
 			delete(txMap, tx.Hash())
 			confirmedCount++
 		}
@@ -131,6 +134,9 @@ func (a issueNAgent[T]) Execute(ctx context.Context) error {
 
 		// Check if this is the last batch, if so write the final log and return
 		if !moreTxs {
+			if err := a.sequence.Err(); err != nil {
+				return fmt.Errorf("failed to generate transaction %d: %w", confirmedCount, err)
+			}
 			totalTime := time.Since(start).Seconds()
 			log.Info("Execution complete", "totalTxs", confirmedCount, "totalTime", totalTime, "TPS", float64(confirmedCount)/totalTime,
 				"issuanceTime", totalIssuedTime.Seconds(), "confirmedTime", totalConfirmedTime.Seconds())
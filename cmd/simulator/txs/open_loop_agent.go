@@ -0,0 +1,118 @@
+// Copyright (C) 2019-2025, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package txs
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/MetalBlockchain/libevm/log"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/MetalBlockchain/coreth/cmd/simulator/metrics"
+)
+
+// openLoopMaxPending is the maximum number of issued txs awaiting
+// confirmation by an openLoopAgent. Once reached, issuance waits for the oldest
+// tx to confirm and falls behind the schedule.
+const openLoopMaxPending = 10_000
+
+// openLoopAgent issues txs at the rate defined by a Schedule, regardless of
+// how long previously issued txs take to confirm.
+type openLoopAgent[T THash] struct {
+	sequence TxSequence[T]
+	worker   Worker[T]
+	schedule Schedule
+	metrics  *metrics.Metrics
+}
+
+// NewOpenLoopAgent creates a new openLoopAgent
+func NewOpenLoopAgent[T THash](sequence TxSequence[T], worker Worker[T], schedule Schedule, metrics *metrics.Metrics) Agent[T] {
+	return &openLoopAgent[T]{
+		sequence: sequence,
+		worker:   worker,
+		schedule: schedule,
+		metrics:  metrics,
+	}
+}
+
+type issuedTx[T THash] struct {
+	tx       T
+	issuedAt time.Time
+}
+
+// Execute issues txs according to the schedule until either the schedule or
+// the sequence ends, and confirms them concurrently.
+func (a openLoopAgent[T]) Execute(ctx context.Context) error {
+	var (
+		m      = a.metrics
+		txChan = a.sequence.Chan()
+		// Issuance only waits for confirmations once [openLoopMaxPending]
+		// txs are pending.
+		issued = make(chan issuedTx[T], min(a.schedule.NumTxs(), openLoopMaxPending))
+	)
+	eg, egCtx := errgroup.WithContext(ctx)
+	start := time.Now()
+	eg.Go(func() error {
+		defer close(issued)
+
+		for n := uint64(1); ; n++ {
+			offset, ok := a.schedule.TimeOf(n)
+			if !ok {
+				return nil
+			}
+			select {
+			case <-egCtx.Done():
+				return egCtx.Err()
+			case <-time.After(time.Until(start.Add(offset))):
+			}
+
+			var tx T
+			select {
+			case <-egCtx.Done():
+				return egCtx.Err()
+			case tx, ok = <-txChan:
+				if !ok {
+					if err := a.sequence.Err(); err != nil {
+						return fmt.Errorf("failed to generate transaction %d: %w", n, err)
+					}
+					log.Warn("Tx sequence ended before the schedule", "issuedTxs", n-1)
+					return nil
+				}
+			}
+			issuedAt := time.Now()
+			if lag := issuedAt.Sub(start.Add(offset)); lag > time.Second {
+				log.Debug("Issuance is behind schedule", "tx", n, "lag", lag)
+			}
+			if err := a.worker.IssueTx(egCtx, tx); err != nil {
+				return fmt.Errorf("failed to issue transaction %d: %w", n, err)
+			}
+			m.IssuanceTxTimes.Observe(time.Since(issuedAt).Seconds())
+			m.TxIssued(issuedAt)
+			select {
+			case <-egCtx.Done():
+				return egCtx.Err()
+			case issued <- issuedTx[T]{tx: tx, issuedAt: issuedAt}:
+			}
+		}
+	})
+	eg.Go(func() error {
+		confirmedCount := 0
+		for itx := range issued {
+			confirmedStart := time.Now()
+			if err := a.worker.ConfirmTx(egCtx, itx.tx); err != nil {
+				return fmt.Errorf("failed to await transaction %d: %w", confirmedCount, err)
+			}
+			now := time.Now()
+			m.ConfirmationTxTimes.Observe(now.Sub(confirmedStart).Seconds())
+			m.TxConfirmed(itx.issuedAt, now)
+			confirmedCount++
+		}
+		totalTime := time.Since(start).Seconds()
+		log.Info("Execution complete", "totalTxs", confirmedCount, "totalTime", totalTime, "TPS", float64(confirmedCount)/totalTime)
+		return nil
+	})
+	return eg.Wait()
+}
@@ -0,0 +1,94 @@
+// Copyright (C) 2019-2025, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package txs
+
+import (
+	"math"
+	"time"
+)
+
+// Stage is a period of a Schedule during which the issuance rate changes
+// linearly from StartRate to EndRate, in txs per second.
+type Stage struct {
+	Duration  time.Duration
+	StartRate float64
+	EndRate   float64
+}
+
+// numTxs returns the number of txs issued during the stage.
+func (s Stage) numTxs() float64 {
+	return (s.StartRate + s.EndRate) / 2 * s.Duration.Seconds()
+}
+
+// timeOf returns the offset from the start of the stage at which [n] txs have
+// been issued during the stage.
+func (s Stage) timeOf(n float64) time.Duration {
+	if n <= 0 {
+		return 0
+	}
+	// The number of txs issued after t seconds is a*t + b*t^2/2, so
+	//
+	//	t = (sqrt(a^2 + 2*b*n) - a) / b = 2*n / (sqrt(a^2 + 2*b*n) + a)
+	//
+	// The second form also holds when the rate is constant (b == 0). When the
+	// rate decreases (b < 0), the discriminant reaches 0 at the end of the
+	// stage, so rounding errors are clamped.
+	a := s.StartRate
+	b := (s.EndRate - s.StartRate) / s.Duration.Seconds()
+	discriminant := max(a*a+2*b*n, 0)
+	denominator := math.Sqrt(discriminant) + a
+	if denominator <= 0 {
+		return s.Duration
+	}
+	t := 2 * n / denominator
+	return min(time.Duration(t*float64(time.Second)), s.Duration)
+}
+
+// Schedule is a sequence of stages defining the rate at which txs are issued
+// by an open-loop agent.
+type Schedule []Stage
+
+// NewRampSchedule returns a Schedule that ramps the issuance rate up from 0 to
+// [rate] over [rampUp], sustains [rate] for [duration] and ramps the rate back
+// down to 0 over [rampDown].
+func NewRampSchedule(rate float64, rampUp, duration, rampDown time.Duration) Schedule {
+	var schedule Schedule
+	if rampUp > 0 {
+		schedule = append(schedule, Stage{Duration: rampUp, StartRate: 0, EndRate: rate})
+	}
+	if duration > 0 {
+		schedule = append(schedule, Stage{Duration: duration, StartRate: rate, EndRate: rate})
+	}
+	if rampDown > 0 {
+		schedule = append(schedule, Stage{Duration: rampDown, StartRate: rate, EndRate: 0})
+	}
+	return schedule
+}
+
+// NumTxs returns the number of txs issued over the whole schedule.
+func (s Schedule) NumTxs() uint64 {
+	var n float64
+	for _, stage := range s {
+		n += stage.numTxs()
+	}
+	return uint64(n)
+}
+
+// TimeOf returns the offset from the start of the schedule at which the
+// [n]th tx should be issued, or false if the schedule ends before the [n]th tx.
+func (s Schedule) TimeOf(n uint64) (time.Duration, bool) {
+	var (
+		offset time.Duration
+		target = float64(n)
+	)
+	for _, stage := range s {
+		stageTxs := stage.numTxs()
+		if target <= stageTxs {
+			return offset + stage.timeOf(target), true
+		}
+		target -= stageTxs
+		offset += stage.Duration
+	}
+	return 0, false
+}
@@ -0,0 +1,118 @@
+// Copyright (C) 2019-2025, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package txs
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestScheduleTimeOf(t *testing.T) {
+	tests := []struct {
+		name     string
+		schedule Schedule
+		numTxs   uint64
+		times    map[uint64]time.Duration
+	}{
+		{
+			name:     "constant",
+			schedule: NewRampSchedule(10, 0, 10*time.Second, 0),
+			numTxs:   100,
+			times: map[uint64]time.Duration{
+				0:   0,
+				5:   500 * time.Millisecond,
+				100: 10 * time.Second,
+			},
+		},
+		{
+			name:     "ramp_up",
+			schedule: NewRampSchedule(10, 10*time.Second, 0, 0),
+			numTxs:   50,
+			times: map[uint64]time.Duration{
+				0:  0,
+				8:  4 * time.Second,
+				50: 10 * time.Second,
+			},
+		},
+		{
+			name:     "ramp_down",
+			schedule: NewRampSchedule(10, 0, 0, 10*time.Second),
+			numTxs:   50,
+			times: map[uint64]time.Duration{
+				0: 0,
+				// The last tx is issued when the rate reaches 0.
+				50: 10 * time.Second,
+			},
+		},
+		{
+			name:     "ramp_up_sustain_ramp_down",
+			schedule: NewRampSchedule(10, 10*time.Second, 10*time.Second, 10*time.Second),
+			numTxs:   200,
+			times: map[uint64]time.Duration{
+				50:  10 * time.Second,
+				100: 15 * time.Second,
+				150: 20 * time.Second,
+				200: 30 * time.Second,
+			},
+		},
+		{
+			name:     "zero_rate",
+			schedule: NewRampSchedule(0, time.Second, time.Second, time.Second),
+			numTxs:   0,
+			times: map[uint64]time.Duration{
+				0: 0,
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require := require.New(t)
+
+			require.Equal(test.numTxs, test.schedule.NumTxs())
+			for n, want := range test.times {
+				got, ok := test.schedule.TimeOf(n)
+				require.True(ok, "tx %d", n)
+				require.InDelta(want, got, float64(time.Microsecond), "tx %d", n)
+			}
+
+			var total time.Duration
+			for _, stage := range test.schedule {
+				total += stage.Duration
+			}
+			var prev time.Duration
+			for n := uint64(0); n <= test.numTxs; n++ {
+				got, ok := test.schedule.TimeOf(n)
+				require.True(ok, "tx %d", n)
+				require.GreaterOrEqual(got, prev, "tx %d is issued before tx %d", n, n-1)
+				require.LessOrEqual(got, total, "tx %d is issued after the end of the schedule", n)
+				prev = got
+			}
+			_, ok := test.schedule.TimeOf(test.numTxs + 1)
+			require.False(ok)
+		})
+	}
+}
+
+// TestStageTimeOfRampDown checks every count of txs of stages ramping down, for
+// which rounding errors used to yield the square root of a negative number.
+func TestStageTimeOfRampDown(t *testing.T) {
+	for _, rate := range []float64{0.3, 1, 7, 333.3, 10_000} {
+		for _, duration := range []time.Duration{time.Millisecond, 3 * time.Second, 17 * time.Minute} {
+			stage := Stage{
+				Duration:  duration,
+				StartRate: rate,
+				EndRate:   0,
+			}
+			numTxs := stage.numTxs()
+			for _, n := range []float64{0, numTxs / 3, numTxs / 2, numTxs, numTxs * (1 + 1e-15)} {
+				got := stage.timeOf(n)
+				require.GreaterOrEqual(t, got, time.Duration(0), "rate %f, duration %s, tx %f", rate, duration, n)
+				require.LessOrEqual(t, got, duration, "rate %f, duration %s, tx %f", rate, duration, n)
+			}
+			require.InEpsilon(t, float64(duration), float64(stage.timeOf(numTxs)), 1e-6)
+		}
+	}
+}
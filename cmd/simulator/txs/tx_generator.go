@@ -15,12 +15,12 @@ import (
 	ethcrypto "github.com/MetalBlockchain/libevm/crypto"
 )
 
-var _ TxSequence[*types.Transaction] = (*txSequence)(nil)
+var _ TxSequence[*types.Transaction] = (*txSequence[*types.Transaction])(nil)
 
 type CreateTx func(key *ecdsa.PrivateKey, nonce uint64) (*types.Transaction, error)
 
 func GenerateTxSequence(ctx context.Context, generator CreateTx, client *ethclient.Client, key *ecdsa.PrivateKey, numTxs uint64, async bool) (TxSequence[*types.Transaction], error) {
-	sequence := &txSequence{
+	sequence := &txSequence[*types.Transaction]{
 		txChan: make(chan *types.Transaction, numTxs),
 	}
 
@@ -54,7 +54,7 @@ func GenerateTxSequences(ctx context.Context, generator CreateTx, client *ethcli
 	return txSequences, nil
 }
 
-func addTxs(ctx context.Context, txSequence *txSequence, generator CreateTx, client *ethclient.Client, key *ecdsa.PrivateKey, numTxs uint64) error {
+func addTxs(ctx context.Context, txSequence *txSequence[*types.Transaction], generator CreateTx, client *ethclient.Client, key *ecdsa.PrivateKey, numTxs uint64) error {
 	address := ethcrypto.PubkeyToAddress(key.PublicKey)
 	startingNonce, err := client.NonceAt(ctx, address, nil)
 	if err != nil {
@@ -71,22 +71,56 @@ func addTxs(ctx context.Context, txSequence *txSequence, generator CreateTx, cli
 	return nil
 }
 
-type txSequence struct {
-	txChan chan *types.Transaction
+// GenerateLazyTxSequence returns a sequence of [numTxs] txs, where the i-th tx
+// is only created by [next] once the previous tx has been received from the
+// sequence. This allows txs to depend on the state of the chain resulting from
+// the previous ones. If [next] fails, the sequence ends early with its error.
+func GenerateLazyTxSequence[T THash](ctx context.Context, next func(ctx context.Context, i uint64) (T, error), numTxs uint64) TxSequence[T] {
+	sequence := &txSequence[T]{
+		txChan: make(chan T),
+	}
+	go func() {
+		defer close(sequence.txChan)
+
+		for i := uint64(0); i < numTxs; i++ {
+			tx, err := next(ctx, i)
+			if err != nil {
+				sequence.err = err
+				return
+			}
+			select {
+			case sequence.txChan <- tx:
+			case <-ctx.Done():
+				sequence.err = ctx.Err()
+				return
+			}
+		}
+	}()
+	return sequence
 }
 
-func ConvertTxSliceToSequence(txs []*types.Transaction) TxSequence[*types.Transaction] {
-	txChan := make(chan *types.Transaction, len(txs))
+type txSequence[T THash] struct {
+	txChan chan T
+	// err is only written before txChan is closed.
+	err error
+}
+
+func ConvertTxSliceToSequence[T THash](txs []T) TxSequence[T] {
+	txChan := make(chan T, len(txs))
 	for _, tx := range txs {
 		txChan <- tx
 	}
 	close(txChan)
 
-	return &txSequence{
+	return &txSequence[T]{
 		txChan: txChan,
 	}
 }
 
-func (t *txSequence) Chan() <-chan *types.Transaction {
+func (t *txSequence[T]) Chan() <-chan T {
 	return t.txChan
 }
+
+func (t *txSequence[T]) Err() error {
+	return t.err
+}
@@ -0,0 +1,59 @@
+// Copyright (C) 2019-2025, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package txs
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/MetalBlockchain/libevm/common"
+	"github.com/stretchr/testify/require"
+)
+
+type testTx uint64
+
+func (tx testTx) Hash() common.Hash {
+	return common.BigToHash(new(big.Int).SetUint64(uint64(tx)))
+}
+
+func TestGenerateLazyTxSequence(t *testing.T) {
+	require := require.New(t)
+
+	errTest := errors.New("test error")
+	var created []uint64
+	next := func(_ context.Context, i uint64) (testTx, error) {
+		created = append(created, i)
+		if i == 2 {
+			return 0, errTest
+		}
+		return testTx(i), nil
+	}
+	sequence := GenerateLazyTxSequence(context.Background(), next, 5)
+
+	var received []testTx
+	for tx := range sequence.Chan() {
+		received = append(received, tx)
+	}
+	require.Equal([]testTx{0, 1}, received)
+	require.Equal([]uint64{0, 1, 2}, created)
+	require.ErrorIs(sequence.Err(), errTest)
+}
+
+func TestGenerateLazyTxSequenceComplete(t *testing.T) {
+	require := require.New(t)
+
+	next := func(_ context.Context, i uint64) (testTx, error) {
+		return testTx(i), nil
+	}
+	sequence := GenerateLazyTxSequence(context.Background(), next, 3)
+
+	var received []testTx
+	for tx := range sequence.Chan() {
+		received = append(received, tx)
+	}
+	require.Equal([]testTx{0, 1, 2}, received)
+	require.NoError(sequence.Err())
+}
@@ -0,0 +1,296 @@
+// Copyright (C) 2019-2025, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package workload
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/MetalBlockchain/metalgo/ids"
+	"github.com/MetalBlockchain/metalgo/utils/crypto/secp256k1"
+	"github.com/MetalBlockchain/metalgo/vms/components/avax"
+	"github.com/MetalBlockchain/metalgo/vms/secp256k1fx"
+	"github.com/MetalBlockchain/libevm/common"
+	"github.com/MetalBlockchain/libevm/log"
+
+	"github.com/MetalBlockchain/coreth/cmd/simulator/txs"
+	"github.com/MetalBlockchain/coreth/ethclient"
+	"github.com/MetalBlockchain/coreth/plugin/evm/atomic"
+	"github.com/MetalBlockchain/coreth/plugin/evm/client"
+
+	ethcrypto "github.com/MetalBlockchain/libevm/crypto"
+)
+
+// maxImportUTXOs is the maximum number of UTXOs consumed by a single import.
+const maxImportUTXOs = 64
+
+// AtomicTx wraps an atomic transaction so that it can be issued by a
+// [txs.Agent].
+type AtomicTx struct {
+	*atomic.Tx
+}
+
+func (tx *AtomicTx) Hash() common.Hash {
+	return common.Hash(tx.ID())
+}
+
+// AtomicParams are the parameters of the atomic workload.
+type AtomicParams struct {
+	NetworkID uint32
+	// BlockchainID is the ID of the chain the atomic txs are issued to.
+	BlockchainID ids.ID
+	// DestinationChainID is the ID of the chain funds are exported to and
+	// imported from.
+	DestinationChainID ids.ID
+	AVAXAssetID        ids.ID
+	// ExportAmount is the amount of [AVAXAssetID], in nAVAX, exported by
+	// each export tx.
+	ExportAmount uint64
+	// MaxBaseFee is the maximum base fee, in wei, the atomic txs are issued
+	// at. Each tx pays the base fee of the chain when it is built, which must
+	// not exceed MaxBaseFee, so that it bounds the funds consumed by each tx.
+	MaxBaseFee *big.Int
+}
+
+// AtomicWorkload issues atomic txs through the avax API of the chain.
+//
+// The sequence generated for each key starts with an import of any UTXOs
+// already exported to the key from the destination chain, followed by export
+// txs of [AtomicParams.ExportAmount] to the key's address on the destination
+// chain.
+//
+// The atomic mempool only holds a single pending export per address, so each
+// tx of a sequence is only built once the previous one has been accepted.
+type AtomicWorkload struct {
+	params     AtomicParams
+	client     *ethclient.Client
+	avaxClient client.Client
+}
+
+// NewAtomicWorkload returns an AtomicWorkload that reads nonces from
+// [ethClient] and atomic UTXOs from [avaxClient].
+func NewAtomicWorkload(params AtomicParams, ethClient *ethclient.Client, avaxClient client.Client) *AtomicWorkload {
+	return &AtomicWorkload{
+		params:     params,
+		client:     ethClient,
+		avaxClient: avaxClient,
+	}
+}
+
+func (*AtomicWorkload) Name() string { return Atomic }
+
+// FundsPerTx returns the balance, in wei, consumed by each export tx at
+// [AtomicParams.MaxBaseFee].
+func (a *AtomicWorkload) FundsPerTx() (*big.Int, error) {
+	// The fee does not depend on the exporting key, so use a random one.
+	key, err := secp256k1.NewPrivateKey()
+	if err != nil {
+		return nil, err
+	}
+	tx, err := a.newExportTx(key, 0, a.params.MaxBaseFee)
+	if err != nil {
+		return nil, err
+	}
+	exportTx := tx.UnsignedAtomicTx.(*atomic.UnsignedExportTx)
+	return new(big.Int).Mul(new(big.Int).SetUint64(exportTx.Ins[0].Amount), atomic.X2CRate.ToBig()), nil
+}
+
+// GenerateTxSequence returns the sequence of [numTxs] atomic txs issued
+// from [key].
+//
+// Each tx is built once the previous one has been accepted, at the nonce and
+// base fee of the chain at that time, so the txs of the sequence must be issued
+// one at a time.
+func (a *AtomicWorkload) GenerateTxSequence(ctx context.Context, key *ecdsa.PrivateKey, numTxs uint64) (txs.TxSequence[*AtomicTx], error) {
+	secpKey, err := secp256k1.ToPrivateKey(ethcrypto.FromECDSA(key))
+	if err != nil {
+		return nil, err
+	}
+
+	var prev *AtomicTx
+	next := func(ctx context.Context, i uint64) (*AtomicTx, error) {
+		if prev != nil {
+			if err := AwaitAtomicTx(ctx, a.avaxClient, prev.ID()); err != nil {
+				return nil, err
+			}
+		}
+		tx, err := a.nextTx(ctx, secpKey, i == 0)
+		if err != nil {
+			return nil, err
+		}
+		prev = tx
+		return tx, nil
+	}
+	return txs.GenerateLazyTxSequence(ctx, next, numTxs), nil
+}
+
+// nextTx returns the next tx to issue from [key], which is an import of any
+// UTXOs exported to [key] if [first], or an export otherwise.
+func (a *AtomicWorkload) nextTx(ctx context.Context, key *secp256k1.PrivateKey, first bool) (*AtomicTx, error) {
+	baseFee, err := a.client.EstimateBaseFee(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch base fee: %w", err)
+	}
+	if baseFee.Cmp(a.params.MaxBaseFee) > 0 {
+		return nil, fmt.Errorf("base fee %d exceeds the maximum of %d", baseFee, a.params.MaxBaseFee)
+	}
+
+	if first {
+		tx, err := a.newImportTx(ctx, key, baseFee)
+		if err != nil || tx != nil {
+			return tx, err
+		}
+	}
+
+	address := key.EthAddress()
+	nonce, err := a.client.NonceAt(ctx, address, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch nonce of %s: %w", address, err)
+	}
+	return a.newExportTx(key, nonce, baseFee)
+}
+
+// AwaitAtomicTx polls the status of the atomic tx [txID] through [avaxClient]
+// until it is accepted.
+func AwaitAtomicTx(ctx context.Context, avaxClient client.Client, txID ids.ID) error {
+	for {
+		status, err := avaxClient.GetAtomicTxStatus(ctx, txID)
+		if err != nil {
+			return fmt.Errorf("failed to await atomic tx %s: %w", txID, err)
+		}
+		log.Debug("confirming atomic tx", "txID", txID, "status", status)
+		switch status {
+		case atomic.Accepted:
+			return nil
+		case atomic.Dropped:
+			return fmt.Errorf("atomic tx %s was dropped", txID)
+		}
+
+		select {
+		case <-time.After(time.Second):
+		case <-ctx.Done():
+			return fmt.Errorf("failed to await atomic tx %s: %w", txID, ctx.Err())
+		}
+	}
+}
+
+// newExportTx returns an export of [AtomicParams.ExportAmount] from the EVM
+// account of [key] at [nonce] to the address of [key] on the destination
+// chain, paying [baseFee].
+func (a *AtomicWorkload) newExportTx(key *secp256k1.PrivateKey, nonce uint64, baseFee *big.Int) (*AtomicTx, error) {
+	utx := &atomic.UnsignedExportTx{
+		NetworkID:        a.params.NetworkID,
+		BlockchainID:     a.params.BlockchainID,
+		DestinationChain: a.params.DestinationChainID,
+		Ins: []atomic.EVMInput{{
+			Address: key.EthAddress(),
+			AssetID: a.params.AVAXAssetID,
+			Nonce:   nonce,
+		}},
+		ExportedOutputs: []*avax.TransferableOutput{{
+			Asset: avax.Asset{ID: a.params.AVAXAssetID},
+			Out: &secp256k1fx.TransferOutput{
+				Amt: a.params.ExportAmount,
+				OutputOwners: secp256k1fx.OutputOwners{
+					Threshold: 1,
+					Addrs:     []ids.ShortID{key.Address()},
+				},
+			},
+		}},
+	}
+	fee, err := fee(utx, baseFee)
+	if err != nil {
+		return nil, err
+	}
+	utx.Ins[0].Amount = a.params.ExportAmount + fee
+
+	tx := &atomic.Tx{UnsignedAtomicTx: utx}
+	if err := tx.Sign(atomic.Codec, [][]*secp256k1.PrivateKey{{key}}); err != nil {
+		return nil, err
+	}
+	return &AtomicTx{Tx: tx}, nil
+}
+
+// newImportTx returns an import of the UTXOs exported to [key] on the
+// destination chain paying [baseFee], or nil if there is nothing to import.
+func (a *AtomicWorkload) newImportTx(ctx context.Context, key *secp256k1.PrivateKey, baseFee *big.Int) (*AtomicTx, error) {
+	utxosBytes, _, _, err := a.avaxClient.GetAtomicUTXOs(ctx, []ids.ShortID{key.Address()}, a.params.DestinationChainID.String(), maxImportUTXOs, ids.ShortEmpty, ids.Empty)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch atomic UTXOs of %s: %w", key.Address(), err)
+	}
+
+	var (
+		ins     []*avax.TransferableInput
+		signers [][]*secp256k1.PrivateKey
+		amount  uint64
+	)
+	for _, utxoBytes := range utxosBytes {
+		utxo := &avax.UTXO{}
+		if _, err := atomic.Codec.Unmarshal(utxoBytes, utxo); err != nil {
+			return nil, fmt.Errorf("failed to parse atomic UTXO: %w", err)
+		}
+		out, ok := utxo.Out.(*secp256k1fx.TransferOutput)
+		if !ok || utxo.AssetID() != a.params.AVAXAssetID || out.Threshold != 1 || len(out.Addrs) != 1 {
+			continue
+		}
+		ins = append(ins, &avax.TransferableInput{
+			UTXOID: utxo.UTXOID,
+			Asset:  utxo.Asset,
+			In: &secp256k1fx.TransferInput{
+				Amt:   out.Amt,
+				Input: secp256k1fx.Input{SigIndices: []uint32{0}},
+			},
+		})
+		signers = append(signers, []*secp256k1.PrivateKey{key})
+		amount += out.Amt
+	}
+	if len(ins) == 0 {
+		return nil, nil
+	}
+	avax.SortTransferableInputsWithSigners(ins, signers)
+
+	utx := &atomic.UnsignedImportTx{
+		NetworkID:      a.params.NetworkID,
+		BlockchainID:   a.params.BlockchainID,
+		SourceChain:    a.params.DestinationChainID,
+		ImportedInputs: ins,
+		Outs: []atomic.EVMOutput{{
+			Address: key.EthAddress(),
+			AssetID: a.params.AVAXAssetID,
+		}},
+	}
+	fee, err := fee(utx, baseFee)
+	if err != nil {
+		return nil, err
+	}
+	if amount <= fee {
+		log.Info("Skipping import of atomic UTXOs worth less than the fee", "address", key.Address(), "amount", amount, "fee", fee)
+		return nil, nil
+	}
+	utx.Outs[0].Amount = amount - fee
+
+	tx := &atomic.Tx{UnsignedAtomicTx: utx}
+	if err := tx.Sign(atomic.Codec, signers); err != nil {
+		return nil, err
+	}
+	return &AtomicTx{Tx: tx}, nil
+}
+
+// fee returns the fee of [utx] at [baseFee].
+func fee(utx atomic.UnsignedAtomicTx, baseFee *big.Int) (uint64, error) {
+	// Sign without credentials to populate the bytes of [utx], which are
+	// required to calculate the gas used.
+	tx := &atomic.Tx{UnsignedAtomicTx: utx}
+	if err := tx.Sign(atomic.Codec, nil); err != nil {
+		return 0, err
+	}
+	gasUsed, err := tx.GasUsed(true)
+	if err != nil {
+		return 0, err
+	}
+	return atomic.CalculateDynamicFee(gasUsed, baseFee)
+}
@@ -0,0 +1,607 @@
+// Copyright (C) 2019-2025, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// The bindings below follow the output of abigen, but the bytecode of each
+// contract is assembled by hand from its source, and checked against it by
+// bindings_test.go. Keep them in sync when changing the sources.
+
+package bindings
+
+import (
+	"errors"
+	"math/big"
+	"strings"
+
+	"github.com/MetalBlockchain/coreth/accounts/abi"
+	"github.com/MetalBlockchain/coreth/accounts/abi/bind"
+	ethereum "github.com/MetalBlockchain/libevm"
+	"github.com/MetalBlockchain/libevm/common"
+	"github.com/MetalBlockchain/libevm/core/types"
+	"github.com/MetalBlockchain/libevm/event"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var (
+	_ = errors.New
+	_ = big.NewInt
+	_ = strings.NewReader
+	_ = ethereum.NotFound
+	_ = bind.Bind
+	_ = common.Big1
+	_ = types.BloomLookup
+	_ = event.NewSubscription
+	_ = abi.ConvertType
+)
+
+// SimulatorStorageMetaData contains all meta data concerning the SimulatorStorage contract.
+var SimulatorStorageMetaData = &bind.MetaData{
+	ABI: "[{\"stateMutability\":\"nonpayable\",\"type\":\"fallback\"}]",
+	Bin: "0x6100208061000b5f395ff35f545f355b801561001b57906001018080559060019003610004565b505f5500",
+}
+
+// SimulatorStorageABI is the input ABI used to generate the binding from.
+// Deprecated: Use SimulatorStorageMetaData.ABI instead.
+var SimulatorStorageABI = SimulatorStorageMetaData.ABI
+
+// SimulatorStorageBin is the compiled bytecode used for deploying new contracts.
+// Deprecated: Use SimulatorStorageMetaData.Bin instead.
+var SimulatorStorageBin = SimulatorStorageMetaData.Bin
+
+// DeploySimulatorStorage deploys a new Ethereum contract, binding an instance of SimulatorStorage to it.
+func DeploySimulatorStorage(auth *bind.TransactOpts, backend bind.ContractBackend) (common.Address, *types.Transaction, *SimulatorStorage, error) {
+	parsed, err := SimulatorStorageMetaData.GetAbi()
+	if err != nil {
+		return common.Address{}, nil, nil, err
+	}
+	if parsed == nil {
+		return common.Address{}, nil, nil, errors.New("GetABI returned nil")
+	}
+
+	address, tx, contract, err := bind.DeployContract(auth, *parsed, common.FromHex(SimulatorStorageBin), backend)
+	if err != nil {
+		return common.Address{}, nil, nil, err
+	}
+	return address, tx, &SimulatorStorage{SimulatorStorageCaller: SimulatorStorageCaller{contract: contract}, SimulatorStorageTransactor: SimulatorStorageTransactor{contract: contract}, SimulatorStorageFilterer: SimulatorStorageFilterer{contract: contract}}, nil
+}
+
+// SimulatorStorage is an auto generated Go binding around an Ethereum contract.
+type SimulatorStorage struct {
+	SimulatorStorageCaller     // Read-only binding to the contract
+	SimulatorStorageTransactor // Write-only binding to the contract
+	SimulatorStorageFilterer   // Log filterer for contract events
+}
+
+// SimulatorStorageCaller is an auto generated read-only Go binding around an Ethereum contract.
+type SimulatorStorageCaller struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// SimulatorStorageTransactor is an auto generated write-only Go binding around an Ethereum contract.
+type SimulatorStorageTransactor struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// SimulatorStorageFilterer is an auto generated log filtering Go binding around an Ethereum contract events.
+type SimulatorStorageFilterer struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// SimulatorStorageSession is an auto generated Go binding around an Ethereum contract,
+// with pre-set call and transact options.
+type SimulatorStorageSession struct {
+	Contract     *SimulatorStorage // Generic contract binding to set the session for
+	CallOpts     bind.CallOpts     // Call options to use throughout this session
+	TransactOpts bind.TransactOpts // Transaction auth options to use throughout this session
+}
+
+// SimulatorStorageCallerSession is an auto generated read-only Go binding around an Ethereum contract,
+// with pre-set call options.
+type SimulatorStorageCallerSession struct {
+	Contract *SimulatorStorageCaller // Generic contract caller binding to set the session for
+	CallOpts bind.CallOpts           // Call options to use throughout this session
+}
+
+// SimulatorStorageTransactorSession is an auto generated write-only Go binding around an Ethereum contract,
+// with pre-set transact options.
+type SimulatorStorageTransactorSession struct {
+	Contract     *SimulatorStorageTransactor // Generic contract transactor binding to set the session for
+	TransactOpts bind.TransactOpts           // Transaction auth options to use throughout this session
+}
+
+// SimulatorStorageRaw is an auto generated low-level Go binding around an Ethereum contract.
+type SimulatorStorageRaw struct {
+	Contract *SimulatorStorage // Generic contract binding to access the raw methods on
+}
+
+// SimulatorStorageCallerRaw is an auto generated low-level read-only Go binding around an Ethereum contract.
+type SimulatorStorageCallerRaw struct {
+	Contract *SimulatorStorageCaller // Generic read-only contract binding to access the raw methods on
+}
+
+// SimulatorStorageTransactorRaw is an auto generated low-level write-only Go binding around an Ethereum contract.
+type SimulatorStorageTransactorRaw struct {
+	Contract *SimulatorStorageTransactor // Generic write-only contract binding to access the raw methods on
+}
+
+// NewSimulatorStorage creates a new instance of SimulatorStorage, bound to a specific deployed contract.
+func NewSimulatorStorage(address common.Address, backend bind.ContractBackend) (*SimulatorStorage, error) {
+	contract, err := bindSimulatorStorage(address, backend, backend, backend)
+	if err != nil {
+		return nil, err
+	}
+	return &SimulatorStorage{SimulatorStorageCaller: SimulatorStorageCaller{contract: contract}, SimulatorStorageTransactor: SimulatorStorageTransactor{contract: contract}, SimulatorStorageFilterer: SimulatorStorageFilterer{contract: contract}}, nil
+}
+
+// NewSimulatorStorageCaller creates a new read-only instance of SimulatorStorage, bound to a specific deployed contract.
+func NewSimulatorStorageCaller(address common.Address, caller bind.ContractCaller) (*SimulatorStorageCaller, error) {
+	contract, err := bindSimulatorStorage(address, caller, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &SimulatorStorageCaller{contract: contract}, nil
+}
+
+// NewSimulatorStorageTransactor creates a new write-only instance of SimulatorStorage, bound to a specific deployed contract.
+func NewSimulatorStorageTransactor(address common.Address, transactor bind.ContractTransactor) (*SimulatorStorageTransactor, error) {
+	contract, err := bindSimulatorStorage(address, nil, transactor, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &SimulatorStorageTransactor{contract: contract}, nil
+}
+
+// NewSimulatorStorageFilterer creates a new log filterer instance of SimulatorStorage, bound to a specific deployed contract.
+func NewSimulatorStorageFilterer(address common.Address, filterer bind.ContractFilterer) (*SimulatorStorageFilterer, error) {
+	contract, err := bindSimulatorStorage(address, nil, nil, filterer)
+	if err != nil {
+		return nil, err
+	}
+	return &SimulatorStorageFilterer{contract: contract}, nil
+}
+
+// bindSimulatorStorage binds a generic wrapper to an already deployed contract.
+func bindSimulatorStorage(address common.Address, caller bind.ContractCaller, transactor bind.ContractTransactor, filterer bind.ContractFilterer) (*bind.BoundContract, error) {
+	parsed, err := SimulatorStorageMetaData.GetAbi()
+	if err != nil {
+		return nil, err
+	}
+	return bind.NewBoundContract(address, *parsed, caller, transactor, filterer), nil
+}
+
+// Call invokes the (constant) contract method with params as input values and
+// sets the output to result. The result type might be a single field for simple
+// returns, a slice of interfaces for anonymous returns and a struct for named
+// returns.
+func (_SimulatorStorage *SimulatorStorageRaw) Call(opts *bind.CallOpts, result *[]interface{}, method string, params ...interface{}) error {
+	return _SimulatorStorage.Contract.SimulatorStorageCaller.contract.Call(opts, result, method, params...)
+}
+
+// Transfer initiates a plain transaction to move funds to the contract, calling
+// its default method if one is available.
+func (_SimulatorStorage *SimulatorStorageRaw) Transfer(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return _SimulatorStorage.Contract.SimulatorStorageTransactor.contract.Transfer(opts)
+}
+
+// Transact invokes the (paid) contract method with params as input values.
+func (_SimulatorStorage *SimulatorStorageRaw) Transact(opts *bind.TransactOpts, method string, params ...interface{}) (*types.Transaction, error) {
+	return _SimulatorStorage.Contract.SimulatorStorageTransactor.contract.Transact(opts, method, params...)
+}
+
+// Call invokes the (constant) contract method with params as input values and
+// sets the output to result. The result type might be a single field for simple
+// returns, a slice of interfaces for anonymous returns and a struct for named
+// returns.
+func (_SimulatorStorage *SimulatorStorageCallerRaw) Call(opts *bind.CallOpts, result *[]interface{}, method string, params ...interface{}) error {
+	return _SimulatorStorage.Contract.contract.Call(opts, result, method, params...)
+}
+
+// Transfer initiates a plain transaction to move funds to the contract, calling
+// its default method if one is available.
+func (_SimulatorStorage *SimulatorStorageTransactorRaw) Transfer(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return _SimulatorStorage.Contract.contract.Transfer(opts)
+}
+
+// Transact invokes the (paid) contract method with params as input values.
+func (_SimulatorStorage *SimulatorStorageTransactorRaw) Transact(opts *bind.TransactOpts, method string, params ...interface{}) (*types.Transaction, error) {
+	return _SimulatorStorage.Contract.contract.Transact(opts, method, params...)
+}
+
+// Fallback is a paid mutator transaction binding the contract fallback function.
+//
+// Solidity: fallback() returns()
+func (_SimulatorStorage *SimulatorStorageTransactor) Fallback(opts *bind.TransactOpts, calldata []byte) (*types.Transaction, error) {
+	return _SimulatorStorage.contract.RawTransact(opts, calldata)
+}
+
+// Fallback is a paid mutator transaction binding the contract fallback function.
+//
+// Solidity: fallback() returns()
+func (_SimulatorStorage *SimulatorStorageSession) Fallback(calldata []byte) (*types.Transaction, error) {
+	return _SimulatorStorage.Contract.Fallback(&_SimulatorStorage.TransactOpts, calldata)
+}
+
+// Fallback is a paid mutator transaction binding the contract fallback function.
+//
+// Solidity: fallback() returns()
+func (_SimulatorStorage *SimulatorStorageTransactorSession) Fallback(calldata []byte) (*types.Transaction, error) {
+	return _SimulatorStorage.Contract.Fallback(&_SimulatorStorage.TransactOpts, calldata)
+}
+
+// SimulatorTokenMetaData contains all meta data concerning the SimulatorToken contract.
+var SimulatorTokenMetaData = &bind.MetaData{
+	ABI: "[{\"inputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"constructor\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":true,\"internalType\":\"address\",\"name\":\"from\",\"type\":\"address\"},{\"indexed\":true,\"internalType\":\"address\",\"name\":\"to\",\"type\":\"address\"},{\"indexed\":false,\"internalType\":\"uint256\",\"name\":\"value\",\"type\":\"uint256\"}],\"name\":\"Transfer\",\"type\":\"event\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"account\",\"type\":\"address\"}],\"name\":\"balanceOf\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"balance\",\"type\":\"uint256\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"to\",\"type\":\"address\"},{\"internalType\":\"uint256\",\"name\":\"amount\",\"type\":\"uint256\"}],\"name\":\"transfer\",\"outputs\":[{\"internalType\":\"bool\",\"name\":\"\",\"type\":\"bool\"}],\"stateMutability\":\"nonpayable\",\"type\":\"function\"}]",
+	Bin: "0x6b033b2e3c9fd0803ce800000033556100788061001a5f395ff35f3560e01c8063a9059cbb1461002b57806370a082311461001f575b5f5ffd5b50600435545f5260205ff35b50602435335481811061001b578190033355600435805482018155905f52337fddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef60205fa360015f5260205ff3",
+}
+
+// SimulatorTokenABI is the input ABI used to generate the binding from.
+// Deprecated: Use SimulatorTokenMetaData.ABI instead.
+var SimulatorTokenABI = SimulatorTokenMetaData.ABI
+
+// SimulatorTokenBin is the compiled bytecode used for deploying new contracts.
+// Deprecated: Use SimulatorTokenMetaData.Bin instead.
+var SimulatorTokenBin = SimulatorTokenMetaData.Bin
+
+// DeploySimulatorToken deploys a new Ethereum contract, binding an instance of SimulatorToken to it.
+func DeploySimulatorToken(auth *bind.TransactOpts, backend bind.ContractBackend) (common.Address, *types.Transaction, *SimulatorToken, error) {
+	parsed, err := SimulatorTokenMetaData.GetAbi()
+	if err != nil {
+		return common.Address{}, nil, nil, err
+	}
+	if parsed == nil {
+		return common.Address{}, nil, nil, errors.New("GetABI returned nil")
+	}
+
+	address, tx, contract, err := bind.DeployContract(auth, *parsed, common.FromHex(SimulatorTokenBin), backend)
+	if err != nil {
+		return common.Address{}, nil, nil, err
+	}
+	return address, tx, &SimulatorToken{SimulatorTokenCaller: SimulatorTokenCaller{contract: contract}, SimulatorTokenTransactor: SimulatorTokenTransactor{contract: contract}, SimulatorTokenFilterer: SimulatorTokenFilterer{contract: contract}}, nil
+}
+
+// SimulatorToken is an auto generated Go binding around an Ethereum contract.
+type SimulatorToken struct {
+	SimulatorTokenCaller     // Read-only binding to the contract
+	SimulatorTokenTransactor // Write-only binding to the contract
+	SimulatorTokenFilterer   // Log filterer for contract events
+}
+
+// SimulatorTokenCaller is an auto generated read-only Go binding around an Ethereum contract.
+type SimulatorTokenCaller struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// SimulatorTokenTransactor is an auto generated write-only Go binding around an Ethereum contract.
+type SimulatorTokenTransactor struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// SimulatorTokenFilterer is an auto generated log filtering Go binding around an Ethereum contract events.
+type SimulatorTokenFilterer struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// SimulatorTokenSession is an auto generated Go binding around an Ethereum contract,
+// with pre-set call and transact options.
+type SimulatorTokenSession struct {
+	Contract     *SimulatorToken   // Generic contract binding to set the session for
+	CallOpts     bind.CallOpts     // Call options to use throughout this session
+	TransactOpts bind.TransactOpts // Transaction auth options to use throughout this session
+}
+
+// SimulatorTokenCallerSession is an auto generated read-only Go binding around an Ethereum contract,
+// with pre-set call options.
+type SimulatorTokenCallerSession struct {
+	Contract *SimulatorTokenCaller // Generic contract caller binding to set the session for
+	CallOpts bind.CallOpts         // Call options to use throughout this session
+}
+
+// SimulatorTokenTransactorSession is an auto generated write-only Go binding around an Ethereum contract,
+// with pre-set transact options.
+type SimulatorTokenTransactorSession struct {
+	Contract     *SimulatorTokenTransactor // Generic contract transactor binding to set the session for
+	TransactOpts bind.TransactOpts         // Transaction auth options to use throughout this session
+}
+
+// SimulatorTokenRaw is an auto generated low-level Go binding around an Ethereum contract.
+type SimulatorTokenRaw struct {
+	Contract *SimulatorToken // Generic contract binding to access the raw methods on
+}
+
+// SimulatorTokenCallerRaw is an auto generated low-level read-only Go binding around an Ethereum contract.
+type SimulatorTokenCallerRaw struct {
+	Contract *SimulatorTokenCaller // Generic read-only contract binding to access the raw methods on
+}
+
+// SimulatorTokenTransactorRaw is an auto generated low-level write-only Go binding around an Ethereum contract.
+type SimulatorTokenTransactorRaw struct {
+	Contract *SimulatorTokenTransactor // Generic write-only contract binding to access the raw methods on
+}
+
+// NewSimulatorToken creates a new instance of SimulatorToken, bound to a specific deployed contract.
+func NewSimulatorToken(address common.Address, backend bind.ContractBackend) (*SimulatorToken, error) {
+	contract, err := bindSimulatorToken(address, backend, backend, backend)
+	if err != nil {
+		return nil, err
+	}
+	return &SimulatorToken{SimulatorTokenCaller: SimulatorTokenCaller{contract: contract}, SimulatorTokenTransactor: SimulatorTokenTransactor{contract: contract}, SimulatorTokenFilterer: SimulatorTokenFilterer{contract: contract}}, nil
+}
+
+// NewSimulatorTokenCaller creates a new read-only instance of SimulatorToken, bound to a specific deployed contract.
+func NewSimulatorTokenCaller(address common.Address, caller bind.ContractCaller) (*SimulatorTokenCaller, error) {
+	contract, err := bindSimulatorToken(address, caller, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &SimulatorTokenCaller{contract: contract}, nil
+}
+
+// NewSimulatorTokenTransactor creates a new write-only instance of SimulatorToken, bound to a specific deployed contract.
+func NewSimulatorTokenTransactor(address common.Address, transactor bind.ContractTransactor) (*SimulatorTokenTransactor, error) {
+	contract, err := bindSimulatorToken(address, nil, transactor, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &SimulatorTokenTransactor{contract: contract}, nil
+}
+
+// NewSimulatorTokenFilterer creates a new log filterer instance of SimulatorToken, bound to a specific deployed contract.
+func NewSimulatorTokenFilterer(address common.Address, filterer bind.ContractFilterer) (*SimulatorTokenFilterer, error) {
+	contract, err := bindSimulatorToken(address, nil, nil, filterer)
+	if err != nil {
+		return nil, err
+	}
+	return &SimulatorTokenFilterer{contract: contract}, nil
+}
+
+// bindSimulatorToken binds a generic wrapper to an already deployed contract.
+func bindSimulatorToken(address common.Address, caller bind.ContractCaller, transactor bind.ContractTransactor, filterer bind.ContractFilterer) (*bind.BoundContract, error) {
+	parsed, err := SimulatorTokenMetaData.GetAbi()
+	if err != nil {
+		return nil, err
+	}
+	return bind.NewBoundContract(address, *parsed, caller, transactor, filterer), nil
+}
+
+// Call invokes the (constant) contract method with params as input values and
+// sets the output to result. The result type might be a single field for simple
+// returns, a slice of interfaces for anonymous returns and a struct for named
+// returns.
+func (_SimulatorToken *SimulatorTokenRaw) Call(opts *bind.CallOpts, result *[]interface{}, method string, params ...interface{}) error {
+	return _SimulatorToken.Contract.SimulatorTokenCaller.contract.Call(opts, result, method, params...)
+}
+
+// Transfer initiates a plain transaction to move funds to the contract, calling
+// its default method if one is available.
+func (_SimulatorToken *SimulatorTokenRaw) Transfer(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return _SimulatorToken.Contract.SimulatorTokenTransactor.contract.Transfer(opts)
+}
+
+// Transact invokes the (paid) contract method with params as input values.
+func (_SimulatorToken *SimulatorTokenRaw) Transact(opts *bind.TransactOpts, method string, params ...interface{}) (*types.Transaction, error) {
+	return _SimulatorToken.Contract.SimulatorTokenTransactor.contract.Transact(opts, method, params...)
+}
+
+// Call invokes the (constant) contract method with params as input values and
+// sets the output to result. The result type might be a single field for simple
+// returns, a slice of interfaces for anonymous returns and a struct for named
+// returns.
+func (_SimulatorToken *SimulatorTokenCallerRaw) Call(opts *bind.CallOpts, result *[]interface{}, method string, params ...interface{}) error {
+	return _SimulatorToken.Contract.contract.Call(opts, result, method, params...)
+}
+
+// Transfer initiates a plain transaction to move funds to the contract, calling
+// its default method if one is available.
+func (_SimulatorToken *SimulatorTokenTransactorRaw) Transfer(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return _SimulatorToken.Contract.contract.Transfer(opts)
+}
+
+// Transact invokes the (paid) contract method with params as input values.
+func (_SimulatorToken *SimulatorTokenTransactorRaw) Transact(opts *bind.TransactOpts, method string, params ...interface{}) (*types.Transaction, error) {
+	return _SimulatorToken.Contract.contract.Transact(opts, method, params...)
+}
+
+// BalanceOf is a free data retrieval call binding the contract method 0x70a08231.
+//
+// Solidity: function balanceOf(address account) view returns(uint256 balance)
+func (_SimulatorToken *SimulatorTokenCaller) BalanceOf(opts *bind.CallOpts, account common.Address) (*big.Int, error) {
+	var out []interface{}
+	err := _SimulatorToken.contract.Call(opts, &out, "balanceOf", account)
+
+	if err != nil {
+		return *new(*big.Int), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(*big.Int)).(**big.Int)
+
+	return out0, err
+
+}
+
+// BalanceOf is a free data retrieval call binding the contract method 0x70a08231.
+//
+// Solidity: function balanceOf(address account) view returns(uint256 balance)
+func (_SimulatorToken *SimulatorTokenSession) BalanceOf(account common.Address) (*big.Int, error) {
+	return _SimulatorToken.Contract.BalanceOf(&_SimulatorToken.CallOpts, account)
+}
+
+// BalanceOf is a free data retrieval call binding the contract method 0x70a08231.
+//
+// Solidity: function balanceOf(address account) view returns(uint256 balance)
+func (_SimulatorToken *SimulatorTokenCallerSession) BalanceOf(account common.Address) (*big.Int, error) {
+	return _SimulatorToken.Contract.BalanceOf(&_SimulatorToken.CallOpts, account)
+}
+
+// Transfer is a paid mutator transaction binding the contract method 0xa9059cbb.
+//
+// Solidity: function transfer(address to, uint256 amount) returns(bool)
+func (_SimulatorToken *SimulatorTokenTransactor) Transfer(opts *bind.TransactOpts, to common.Address, amount *big.Int) (*types.Transaction, error) {
+	return _SimulatorToken.contract.Transact(opts, "transfer", to, amount)
+}
+
+// Transfer is a paid mutator transaction binding the contract method 0xa9059cbb.
+//
+// Solidity: function transfer(address to, uint256 amount) returns(bool)
+func (_SimulatorToken *SimulatorTokenSession) Transfer(to common.Address, amount *big.Int) (*types.Transaction, error) {
+	return _SimulatorToken.Contract.Transfer(&_SimulatorToken.TransactOpts, to, amount)
+}
+
+// Transfer is a paid mutator transaction binding the contract method 0xa9059cbb.
+//
+// Solidity: function transfer(address to, uint256 amount) returns(bool)
+func (_SimulatorToken *SimulatorTokenTransactorSession) Transfer(to common.Address, amount *big.Int) (*types.Transaction, error) {
+	return _SimulatorToken.Contract.Transfer(&_SimulatorToken.TransactOpts, to, amount)
+}
+
+// SimulatorTokenTransferIterator is returned from FilterTransfer and is used to iterate over the raw logs and unpacked data for Transfer events raised by the SimulatorToken contract.
+type SimulatorTokenTransferIterator struct {
+	Event *SimulatorTokenTransfer // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log        // Log channel receiving the found contract events
+	sub  ethereum.Subscription // Subscription for errors, completion and termination
+	done bool                  // Whether the subscription completed delivering logs
+	fail error                 // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *SimulatorTokenTransferIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(SimulatorTokenTransfer)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(SimulatorTokenTransfer)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *SimulatorTokenTransferIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *SimulatorTokenTransferIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// SimulatorTokenTransfer represents a Transfer event raised by the SimulatorToken contract.
+type SimulatorTokenTransfer struct {
+	From  common.Address
+	To    common.Address
+	Value *big.Int
+	Raw   types.Log // Blockchain specific contextual infos
+}
+
+// FilterTransfer is a free log retrieval operation binding the contract event 0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef.
+//
+// Solidity: event Transfer(address indexed from, address indexed to, uint256 value)
+func (_SimulatorToken *SimulatorTokenFilterer) FilterTransfer(opts *bind.FilterOpts, from []common.Address, to []common.Address) (*SimulatorTokenTransferIterator, error) {
+
+	var fromRule []interface{}
+	for _, fromItem := range from {
+		fromRule = append(fromRule, fromItem)
+	}
+	var toRule []interface{}
+	for _, toItem := range to {
+		toRule = append(toRule, toItem)
+	}
+
+	logs, sub, err := _SimulatorToken.contract.FilterLogs(opts, "Transfer", fromRule, toRule)
+	if err != nil {
+		return nil, err
+	}
+	return &SimulatorTokenTransferIterator{contract: _SimulatorToken.contract, event: "Transfer", logs: logs, sub: sub}, nil
+}
+
+// WatchTransfer is a free log subscription operation binding the contract event 0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef.
+//
+// Solidity: event Transfer(address indexed from, address indexed to, uint256 value)
+func (_SimulatorToken *SimulatorTokenFilterer) WatchTransfer(opts *bind.WatchOpts, sink chan<- *SimulatorTokenTransfer, from []common.Address, to []common.Address) (event.Subscription, error) {
+
+	var fromRule []interface{}
+	for _, fromItem := range from {
+		fromRule = append(fromRule, fromItem)
+	}
+	var toRule []interface{}
+	for _, toItem := range to {
+		toRule = append(toRule, toItem)
+	}
+
+	logs, sub, err := _SimulatorToken.contract.WatchLogs(opts, "Transfer", fromRule, toRule)
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(SimulatorTokenTransfer)
+				if err := _SimulatorToken.contract.UnpackLog(event, "Transfer", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// ParseTransfer is a log parse operation binding the contract event 0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef.
+//
+// Solidity: event Transfer(address indexed from, address indexed to, uint256 value)
+func (_SimulatorToken *SimulatorTokenFilterer) ParseTransfer(log types.Log) (*SimulatorTokenTransfer, error) {
+	event := new(SimulatorTokenTransfer)
+	if err := _SimulatorToken.contract.UnpackLog(event, "Transfer", log); err != nil {
+		return nil, err
+	}
+	event.Raw = log
+	return event, nil
+}
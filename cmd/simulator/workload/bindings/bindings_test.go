@@ -0,0 +1,118 @@
+// Copyright (C) 2019-2025, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package bindings
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/MetalBlockchain/libevm/common"
+	"github.com/MetalBlockchain/libevm/core/rawdb"
+	"github.com/MetalBlockchain/libevm/core/state"
+	"github.com/MetalBlockchain/libevm/core/types"
+	"github.com/MetalBlockchain/libevm/core/vm"
+	"github.com/stretchr/testify/require"
+
+	"github.com/MetalBlockchain/coreth/core/vm/runtime"
+	"github.com/MetalBlockchain/coreth/params"
+)
+
+func newRuntimeConfig(t *testing.T, origin common.Address) *runtime.Config {
+	statedb, err := state.New(types.EmptyRootHash, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	require.NoError(t, err)
+	return &runtime.Config{
+		ChainConfig: params.TestChainConfig,
+		Origin:      origin,
+		State:       statedb,
+	}
+}
+
+// TestSimulatorStorageBin checks that [SimulatorStorageBin] behaves as the
+// fallback of contracts/contracts/SimulatorStorage.sol: each call writes n
+// previously unused slots, and slot 0 holds the number of slots written.
+func TestSimulatorStorageBin(t *testing.T) {
+	require := require.New(t)
+
+	cfg := newRuntimeConfig(t, common.Address{1})
+	_, contract, _, err := runtime.Create(common.FromHex(SimulatorStorageBin), cfg)
+	require.NoError(err)
+
+	var written uint64
+	for _, n := range []uint64{3, 0, 2} {
+		_, _, err := runtime.Call(contract, common.BigToHash(new(big.Int).SetUint64(n)).Bytes(), cfg)
+		require.NoError(err)
+		written += n
+		require.Equal(common.BigToHash(new(big.Int).SetUint64(written)), cfg.State.GetState(contract, common.Hash{}))
+	}
+	for slot := uint64(1); slot <= written; slot++ {
+		key := common.BigToHash(new(big.Int).SetUint64(slot))
+		require.Equal(key, cfg.State.GetState(contract, key))
+	}
+	require.Equal(common.Hash{}, cfg.State.GetState(contract, common.BigToHash(new(big.Int).SetUint64(written+1))))
+}
+
+// TestSimulatorTokenBin checks that [SimulatorTokenBin] behaves as
+// contracts/contracts/SimulatorToken.sol: the deployer is minted 10^27 tokens,
+// balances are stored in the slot of their holder, and transfer emits
+// Transfer and reverts if the balance of the caller is insufficient.
+func TestSimulatorTokenBin(t *testing.T) {
+	require := require.New(t)
+
+	var (
+		deployer  = common.Address{1}
+		recipient = common.Address{2}
+		supply, _ = new(big.Int).SetString("1000000000000000000000000000", 10)
+		amount    = big.NewInt(10)
+		cfg       = newRuntimeConfig(t, deployer)
+	)
+	_, token, _, err := runtime.Create(common.FromHex(SimulatorTokenBin), cfg)
+	require.NoError(err)
+	require.Equal(common.BigToHash(supply), cfg.State.GetState(token, common.BytesToHash(deployer.Bytes())))
+
+	tokenABI, err := SimulatorTokenMetaData.GetAbi()
+	require.NoError(err)
+	balanceOf := func(account common.Address) *big.Int {
+		input, err := tokenABI.Pack("balanceOf", account)
+		require.NoError(err)
+		ret, _, err := runtime.Call(token, input, cfg)
+		require.NoError(err)
+		out, err := tokenABI.Unpack("balanceOf", ret)
+		require.NoError(err)
+		return out[0].(*big.Int)
+	}
+	require.Equal(supply, balanceOf(deployer))
+	require.Zero(balanceOf(recipient).Sign())
+
+	transfer, err := tokenABI.Pack("transfer", recipient, amount)
+	require.NoError(err)
+	ret, _, err := runtime.Call(token, transfer, cfg)
+	require.NoError(err)
+	out, err := tokenABI.Unpack("transfer", ret)
+	require.NoError(err)
+	require.Equal([]interface{}{true}, out)
+	require.Equal(new(big.Int).Sub(supply, amount), balanceOf(deployer))
+	require.Equal(amount, balanceOf(recipient))
+
+	logs := cfg.State.Logs()
+	require.Len(logs, 1)
+	require.Equal(token, logs[0].Address)
+	require.Equal([]common.Hash{
+		tokenABI.Events["Transfer"].ID,
+		common.BytesToHash(deployer.Bytes()),
+		common.BytesToHash(recipient.Bytes()),
+	}, logs[0].Topics)
+	require.Equal(common.BigToHash(amount).Bytes(), logs[0].Data)
+
+	// The recipient cannot transfer more than its balance.
+	cfg.Origin = recipient
+	transfer, err = tokenABI.Pack("transfer", deployer, new(big.Int).Add(amount, common.Big1))
+	require.NoError(err)
+	_, _, err = runtime.Call(token, transfer, cfg)
+	require.ErrorIs(err, vm.ErrExecutionReverted)
+	require.Equal(amount, balanceOf(recipient))
+
+	// Calls of unknown functions revert.
+	_, _, err = runtime.Call(token, []byte{0x01, 0x02, 0x03, 0x04}, cfg)
+	require.ErrorIs(err, vm.ErrExecutionReverted)
+}
@@ -0,0 +1,6 @@
+// Copyright (C) 2019-2025, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package bindings contains the bindings of the contracts deployed by the
+// simulator workloads, whose sources are in contracts/contracts.
+package bindings
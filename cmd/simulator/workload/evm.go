@@ -0,0 +1,198 @@
+// Copyright (C) 2019-2025, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package workload
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"encoding/binary"
+	"fmt"
+	"math/big"
+
+	"github.com/MetalBlockchain/libevm/common"
+	"github.com/MetalBlockchain/libevm/core/types"
+	"github.com/MetalBlockchain/libevm/log"
+
+	"github.com/MetalBlockchain/coreth/accounts/abi/bind"
+	"github.com/MetalBlockchain/coreth/cmd/simulator/workload/bindings"
+	"github.com/MetalBlockchain/coreth/ethclient"
+	"github.com/MetalBlockchain/coreth/precompile/contracts/warp"
+
+	ethcrypto "github.com/MetalBlockchain/libevm/crypto"
+	ethparams "github.com/MetalBlockchain/libevm/params"
+)
+
+const (
+	transferGas      = ethparams.TxGas
+	erc20DeployGas   = 300_000
+	erc20TransferGas = 100_000
+	deployGas        = 100_000
+	warpSendGas      = 200_000
+
+	// storageBaseGas covers the intrinsic gas of a storage call and the update
+	// of the slot counter, and storageSlotGas covers writing a single unused
+	// slot.
+	storageBaseGas = 50_000
+	storageSlotGas = 25_000
+)
+
+func storageCallGas(slots uint64) uint64 {
+	return storageBaseGas + slots*storageSlotGas
+}
+
+var (
+	_ EVM = (*transfer)(nil)
+	_ EVM = (*erc20)(nil)
+	_ EVM = (*storage)(nil)
+	_ EVM = (*deploy)(nil)
+	_ EVM = (*warpSend)(nil)
+)
+
+// transfer issues zero value transfers from the key to itself.
+type transfer struct {
+	params TxParams
+}
+
+func (*transfer) Name() string { return Transfer }
+
+func (*transfer) Setup(context.Context, *ethclient.Client, *ecdsa.PrivateKey) error {
+	return nil
+}
+
+func (t *transfer) CreateTx(key *ecdsa.PrivateKey, nonce uint64) (*types.Transaction, error) {
+	addr := ethcrypto.PubkeyToAddress(key.PublicKey)
+	return t.params.signTx(key, nonce, &addr, transferGas, nil)
+}
+
+// erc20 issues ERC-20 transfers of a single token unit to a new recipient for
+// every transaction, against a token deployed by the key during Setup.
+type erc20 struct {
+	params TxParams
+	token  common.Address
+}
+
+func (*erc20) Name() string { return ERC20 }
+
+func (e *erc20) Setup(ctx context.Context, client *ethclient.Client, key *ecdsa.PrivateKey) error {
+	token, err := e.params.deployContract(ctx, client, key, common.FromHex(bindings.SimulatorTokenBin), erc20DeployGas)
+	if err != nil {
+		return fmt.Errorf("failed to deploy ERC-20 token: %w", err)
+	}
+	e.token = token
+	return nil
+}
+
+func (e *erc20) CreateTx(key *ecdsa.PrivateKey, nonce uint64) (*types.Transaction, error) {
+	// Derive a distinct recipient for each transaction so that every transfer
+	// writes a previously unused balance slot.
+	sender := ethcrypto.PubkeyToAddress(key.PublicKey)
+	recipient := common.BytesToAddress(ethcrypto.Keccak256(sender.Bytes(), binary.BigEndian.AppendUint64(nil, nonce)))
+
+	tokenABI, err := bindings.SimulatorTokenMetaData.GetAbi()
+	if err != nil {
+		return nil, err
+	}
+	data, err := tokenABI.Pack("transfer", recipient, common.Big1)
+	if err != nil {
+		return nil, err
+	}
+	return e.params.signTx(key, nonce, &e.token, erc20TransferGas, data)
+}
+
+// storage issues calls that each write [TxParams.StorageSlots] previously
+// unused storage slots of a contract deployed by the key during Setup.
+type storage struct {
+	params   TxParams
+	contract common.Address
+}
+
+func (*storage) Name() string { return Storage }
+
+func (s *storage) Setup(ctx context.Context, client *ethclient.Client, key *ecdsa.PrivateKey) error {
+	contract, err := s.params.deployContract(ctx, client, key, common.FromHex(bindings.SimulatorStorageBin), deployGas)
+	if err != nil {
+		return fmt.Errorf("failed to deploy storage contract: %w", err)
+	}
+	s.contract = contract
+	return nil
+}
+
+func (s *storage) CreateTx(key *ecdsa.PrivateKey, nonce uint64) (*types.Transaction, error) {
+	data := common.LeftPadBytes(new(big.Int).SetUint64(s.params.StorageSlots).Bytes(), common.HashLength)
+	return s.params.signTx(key, nonce, &s.contract, storageCallGas(s.params.StorageSlots), data)
+}
+
+// deploy issues contract creations of the storage contract.
+type deploy struct {
+	params TxParams
+}
+
+func (*deploy) Name() string { return Deploy }
+
+func (*deploy) Setup(context.Context, *ethclient.Client, *ecdsa.PrivateKey) error {
+	return nil
+}
+
+func (d *deploy) CreateTx(key *ecdsa.PrivateKey, nonce uint64) (*types.Transaction, error) {
+	return d.params.signTx(key, nonce, nil, deployGas, common.FromHex(bindings.SimulatorStorageBin))
+}
+
+// warpSend issues sendWarpMessage calls to the warp precompile.
+type warpSend struct {
+	params TxParams
+}
+
+func (*warpSend) Name() string { return Warp }
+
+func (*warpSend) Setup(context.Context, *ethclient.Client, *ecdsa.PrivateKey) error {
+	return nil
+}
+
+func (w *warpSend) CreateTx(key *ecdsa.PrivateKey, nonce uint64) (*types.Transaction, error) {
+	sender := ethcrypto.PubkeyToAddress(key.PublicKey)
+	data, err := warp.PackSendWarpMessage([]byte(fmt.Sprintf("simulator %s-%d", sender, nonce)))
+	if err != nil {
+		return nil, err
+	}
+	return w.params.signTx(key, nonce, &warp.Module.Address, warpSendGas, data)
+}
+
+func (p TxParams) signTx(key *ecdsa.PrivateKey, nonce uint64, to *common.Address, gas uint64, data []byte) (*types.Transaction, error) {
+	return types.SignNewTx(key, p.Signer, &types.DynamicFeeTx{
+		ChainID:   p.ChainID,
+		Nonce:     nonce,
+		GasTipCap: p.GasTipCap,
+		GasFeeCap: p.GasFeeCap,
+		Gas:       gas,
+		To:        to,
+		Data:      data,
+		Value:     common.Big0,
+	})
+}
+
+// deployContract deploys [code] from [key] and waits for the deployment to be
+// accepted.
+func (p TxParams) deployContract(ctx context.Context, client *ethclient.Client, key *ecdsa.PrivateKey, code []byte, gas uint64) (common.Address, error) {
+	sender := ethcrypto.PubkeyToAddress(key.PublicKey)
+	nonce, err := client.NonceAt(ctx, sender, nil)
+	if err != nil {
+		return common.Address{}, fmt.Errorf("failed to fetch nonce of %s: %w", sender, err)
+	}
+	tx, err := p.signTx(key, nonce, nil, gas, code)
+	if err != nil {
+		return common.Address{}, err
+	}
+	if err := client.SendTransaction(ctx, tx); err != nil {
+		return common.Address{}, fmt.Errorf("failed to issue deployment %s: %w", tx.Hash(), err)
+	}
+	receipt, err := bind.WaitMined(ctx, client, tx)
+	if err != nil {
+		return common.Address{}, fmt.Errorf("failed to await deployment %s: %w", tx.Hash(), err)
+	}
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		return common.Address{}, fmt.Errorf("deployment %s reverted", tx.Hash())
+	}
+	log.Info("Deployed contract", "sender", sender, "address", receipt.ContractAddress, "txHash", tx.Hash())
+	return receipt.ContractAddress, nil
+}
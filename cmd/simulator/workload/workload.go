@@ -0,0 +1,118 @@
+// Copyright (C) 2019-2025, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package workload
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/MetalBlockchain/libevm/core/types"
+
+	"github.com/MetalBlockchain/coreth/ethclient"
+)
+
+// Names of the supported workloads.
+const (
+	Transfer = "transfer"
+	ERC20    = "erc20"
+	Storage  = "storage"
+	Deploy   = "deploy"
+	Warp     = "warp"
+	Atomic   = "atomic"
+)
+
+var ErrUnknownWorkload = errors.New("unknown workload")
+
+// Names returns the names of all supported workloads.
+func Names() []string {
+	return []string{Transfer, ERC20, Storage, Deploy, Warp, Atomic}
+}
+
+// Verify returns an error if [name] is not a supported workload.
+func Verify(name string) error {
+	for _, n := range Names() {
+		if n == name {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %q", ErrUnknownWorkload, name)
+}
+
+// TxParams are the parameters shared by every transaction issued by an EVM
+// workload.
+type TxParams struct {
+	ChainID   *big.Int
+	Signer    types.Signer
+	GasTipCap *big.Int
+	GasFeeCap *big.Int
+	// StorageSlots is the number of fresh storage slots written by each
+	// transaction of the storage workload.
+	StorageSlots uint64
+}
+
+// EVM is a workload that issues EVM transactions from a single key.
+type EVM interface {
+	// Name returns the name of the workload, which is used to label metrics.
+	Name() string
+	// Setup performs any one-off work required before CreateTx may be called,
+	// such as deploying a contract from [key]. Setup consumes nonces of [key],
+	// so it must be called before the key's tx sequence is generated.
+	Setup(ctx context.Context, client *ethclient.Client, key *ecdsa.PrivateKey) error
+	// CreateTx returns the signed transaction to issue from [key] at [nonce].
+	CreateTx(key *ecdsa.PrivateKey, nonce uint64) (*types.Transaction, error)
+}
+
+// NewEVM returns a new instance of the EVM workload [name]. A separate
+// instance must be created for each key, since workloads may hold per-key
+// state populated during Setup.
+func NewEVM(name string, params TxParams) (EVM, error) {
+	switch name {
+	case Transfer:
+		return &transfer{params: params}, nil
+	case ERC20:
+		return &erc20{params: params}, nil
+	case Storage:
+		return &storage{params: params}, nil
+	case Deploy:
+		return &deploy{params: params}, nil
+	case Warp:
+		return &warpSend{params: params}, nil
+	default:
+		return nil, fmt.Errorf("%w: %q is not an EVM workload", ErrUnknownWorkload, name)
+	}
+}
+
+// GasPerTx returns the maximum gas consumed by a single transaction of the
+// workload [name].
+func GasPerTx(name string, params TxParams) uint64 {
+	switch name {
+	case Transfer:
+		return transferGas
+	case ERC20:
+		return erc20TransferGas
+	case Storage:
+		return storageCallGas(params.StorageSlots)
+	case Deploy:
+		return deployGas
+	case Warp:
+		return warpSendGas
+	default:
+		return 0
+	}
+}
+
+// SetupGas returns the maximum gas consumed by Setup of the workload [name].
+func SetupGas(name string) uint64 {
+	switch name {
+	case ERC20:
+		return erc20DeployGas
+	case Storage:
+		return deployGas
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,186 @@
+// Copyright (C) 2019-2025, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package workload
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/MetalBlockchain/metalgo/snow/snowtest"
+	"github.com/MetalBlockchain/metalgo/utils/crypto/secp256k1"
+	"github.com/MetalBlockchain/libevm/common"
+	"github.com/MetalBlockchain/libevm/core/rawdb"
+	"github.com/MetalBlockchain/libevm/core/state"
+	"github.com/MetalBlockchain/libevm/core/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/MetalBlockchain/coreth/cmd/simulator/workload/bindings"
+	"github.com/MetalBlockchain/coreth/core/vm/runtime"
+	"github.com/MetalBlockchain/coreth/params"
+	"github.com/MetalBlockchain/coreth/plugin/evm/atomic"
+
+	ethcrypto "github.com/MetalBlockchain/libevm/crypto"
+	ethparams "github.com/MetalBlockchain/libevm/params"
+)
+
+func newTestTxParams() TxParams {
+	config := params.TestChainConfig
+	return TxParams{
+		ChainID:      config.ChainID,
+		Signer:       types.LatestSigner(config),
+		GasTipCap:    big.NewInt(1),
+		GasFeeCap:    big.NewInt(100),
+		StorageSlots: 3,
+	}
+}
+
+func TestEVMWorkloads(t *testing.T) {
+	var (
+		txParams = newTestTxParams()
+		contract = common.Address{1}
+	)
+	for _, name := range Names() {
+		if name == Atomic {
+			continue
+		}
+		t.Run(name, func(t *testing.T) {
+			require := require.New(t)
+
+			key, err := ethcrypto.GenerateKey()
+			require.NoError(err)
+			workload, err := NewEVM(name, txParams)
+			require.NoError(err)
+			require.Equal(name, workload.Name())
+
+			// Skip Setup, which requires a node, by using a fixed contract.
+			switch workload := workload.(type) {
+			case *erc20:
+				workload.token = contract
+			case *storage:
+				workload.contract = contract
+			}
+
+			const nonce = 5
+			tx, err := workload.CreateTx(key, nonce)
+			require.NoError(err)
+
+			sender, err := types.Sender(txParams.Signer, tx)
+			require.NoError(err)
+			require.Equal(ethcrypto.PubkeyToAddress(key.PublicKey), sender)
+			require.Equal(uint64(nonce), tx.Nonce())
+			require.Equal(txParams.ChainID, tx.ChainId())
+			require.Equal(GasPerTx(name, txParams), tx.Gas())
+			require.Zero(tx.Value().Sign())
+
+			require.Greater(tx.Gas(), maxIntrinsicGas(tx))
+		})
+	}
+
+	_, err := NewEVM(Atomic, txParams)
+	require.ErrorIs(t, err, ErrUnknownWorkload)
+	require.ErrorIs(t, Verify("unknown"), ErrUnknownWorkload)
+}
+
+// maxIntrinsicGas returns an upper bound of the intrinsic gas of [tx].
+func maxIntrinsicGas(tx *types.Transaction) uint64 {
+	gas := ethparams.TxGas
+	if tx.To() == nil {
+		gas = ethparams.TxGasContractCreation
+	}
+	return gas + uint64(len(tx.Data()))*ethparams.TxDataNonZeroGasEIP2028
+}
+
+// TestContracts executes the transactions of the workloads deploying
+// contracts, and checks that they succeed within their gas limits.
+func TestContracts(t *testing.T) {
+	require := require.New(t)
+
+	statedb, err := state.New(types.EmptyRootHash, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	require.NoError(err)
+	var (
+		txParams = newTestTxParams()
+		deployer = common.Address{1}
+		cfg      = &runtime.Config{
+			ChainConfig: params.TestChainConfig,
+			Origin:      deployer,
+			State:       statedb,
+		}
+	)
+
+	cfg.GasLimit = erc20DeployGas
+	_, token, _, err := runtime.Create(common.FromHex(bindings.SimulatorTokenBin), cfg)
+	require.NoError(err)
+
+	workload, err := NewEVM(ERC20, txParams)
+	require.NoError(err)
+	workload.(*erc20).token = token
+	key, err := ethcrypto.GenerateKey()
+	require.NoError(err)
+	tx, err := workload.CreateTx(key, 0)
+	require.NoError(err)
+
+	cfg.GasLimit = tx.Gas() - maxIntrinsicGas(tx)
+	ret, _, err := runtime.Call(token, tx.Data(), cfg)
+	require.NoError(err)
+	require.Equal(common.LeftPadBytes(common.Big1.Bytes(), common.HashLength), ret)
+
+	recipient := common.BytesToAddress(tx.Data()[4+common.HashLength-common.AddressLength : 4+common.HashLength])
+	tokenABI, err := bindings.SimulatorTokenMetaData.GetAbi()
+	require.NoError(err)
+	balanceOf, err := tokenABI.Pack("balanceOf", recipient)
+	require.NoError(err)
+	ret, _, err = runtime.Call(token, balanceOf, cfg)
+	require.NoError(err)
+	require.Equal(common.LeftPadBytes(common.Big1.Bytes(), common.HashLength), ret)
+
+	cfg.GasLimit = deployGas
+	_, contract, _, err := runtime.Create(common.FromHex(bindings.SimulatorStorageBin), cfg)
+	require.NoError(err)
+
+	workload, err = NewEVM(Storage, txParams)
+	require.NoError(err)
+	workload.(*storage).contract = contract
+	tx, err = workload.CreateTx(key, 0)
+	require.NoError(err)
+
+	cfg.GasLimit = tx.Gas() - maxIntrinsicGas(tx)
+	_, _, err = runtime.Call(contract, tx.Data(), cfg)
+	require.NoError(err)
+	slots := txParams.StorageSlots
+	require.Equal(common.BigToHash(new(big.Int).SetUint64(slots)), statedb.GetState(contract, common.Hash{}))
+	require.Equal(common.BigToHash(new(big.Int).SetUint64(slots)), statedb.GetState(contract, common.BigToHash(new(big.Int).SetUint64(slots))))
+}
+
+func TestAtomicWorkloadExportTx(t *testing.T) {
+	require := require.New(t)
+
+	ctx := snowtest.Context(t, snowtest.CChainID)
+	workload := NewAtomicWorkload(AtomicParams{
+		NetworkID:          ctx.NetworkID,
+		BlockchainID:       ctx.ChainID,
+		DestinationChainID: ctx.XChainID,
+		AVAXAssetID:        ctx.AVAXAssetID,
+		ExportAmount:       1_000_000,
+		MaxBaseFee:         big.NewInt(25 * params.GWei),
+	}, nil, nil)
+
+	key, err := secp256k1.NewPrivateKey()
+	require.NoError(err)
+	tx, err := workload.newExportTx(key, 3, big.NewInt(params.GWei))
+	require.NoError(err)
+
+	exportTx, ok := tx.UnsignedAtomicTx.(*atomic.UnsignedExportTx)
+	require.True(ok)
+	require.Equal(uint64(3), exportTx.Ins[0].Nonce)
+	rules := params.GetRulesExtra(params.TestChainConfig.Rules(common.Big0, params.IsMergeTODO, 0))
+	require.NoError(exportTx.Verify(ctx, *rules))
+
+	fundsPerTx, err := workload.FundsPerTx()
+	require.NoError(err)
+	require.Equal(new(big.Int).Mul(new(big.Int).SetUint64(exportTx.Ins[0].Amount), atomic.X2CRate.ToBig()), fundsPerTx)
+
+	parsed, err := atomic.ExtractAtomicTx(tx.SignedBytes(), atomic.Codec)
+	require.NoError(err)
+	require.Equal(tx.ID(), parsed.ID())
+}
@@ -0,0 +1,23 @@
+//SPDX-License-Identifier: MIT
+pragma solidity ^0.8.0;
+
+// SimulatorStorage is the contract deployed by the storage and deploy workloads
+// of the load simulator. Each call interprets its calldata as a uint256 n and
+// writes n previously unused storage slots. Slot 0 holds the number of slots
+// written so far.
+contract SimulatorStorage {
+  fallback() external {
+    assembly {
+      let count := sload(0)
+      for {
+        let n := calldataload(0)
+      } gt(n, 0) {
+        n := sub(n, 1)
+      } {
+        count := add(count, 1)
+        sstore(count, count)
+      }
+      sstore(0, count)
+    }
+  }
+}
@@ -0,0 +1,39 @@
+//SPDX-License-Identifier: MIT
+pragma solidity ^0.8.0;
+
+// SimulatorToken is the minimal ERC-20 token deployed by the erc20 workload of
+// the load simulator. It mints 10^27 tokens to the deployer and only supports
+// transfer and balanceOf. Balances are stored in the slot equal to the address
+// of their holder, so that the gas used by each transfer is fixed and minimal.
+contract SimulatorToken {
+  event Transfer(address indexed from, address indexed to, uint256 value);
+
+  constructor() {
+    assembly {
+      sstore(caller(), 1000000000000000000000000000)
+    }
+  }
+
+  // balanceOf returns the balance of account.
+  function balanceOf(address account) external view returns (uint256 balance) {
+    assembly {
+      balance := sload(account)
+    }
+  }
+
+  // transfer moves amount tokens from the caller to to, reverting if the
+  // balance of the caller is insufficient.
+  function transfer(address to, uint256 amount) external returns (bool) {
+    uint256 balance;
+    assembly {
+      balance := sload(caller())
+    }
+    require(balance >= amount);
+    assembly {
+      sstore(caller(), sub(balance, amount))
+      sstore(to, add(sload(to), amount))
+    }
+    emit Transfer(msg.sender, to, amount);
+    return true;
+  }
+}
@@ -0,0 +1,49 @@
+// Copyright (C) 2019-2025, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// SPDX-License-Identifier: MIT
+
+pragma solidity ^0.8.0;
+
+// IAssetToken is the ERC-20 token of a multicoin asset. Its balances are the
+// multicoin balances of the asset.
+interface IAssetToken {
+  event Transfer(address indexed from, address indexed to, uint256 value);
+  event Approval(address indexed owner, address indexed spender, uint256 value);
+
+  function name() external view returns (string memory);
+
+  function symbol() external view returns (string memory);
+
+  function decimals() external view returns (uint8);
+
+  // totalSupply reverts, as the balances of multicoin assets aren't summed.
+  function totalSupply() external view returns (uint256);
+
+  function balanceOf(address account) external view returns (uint256);
+
+  function transfer(address to, uint256 value) external returns (bool);
+
+  function allowance(address owner, address spender) external view returns (uint256);
+
+  function approve(address spender, uint256 value) external returns (bool);
+
+  // transferFrom never spends an allowance of type(uint256).max.
+  function transferFrom(address from, address to, uint256 value) external returns (bool);
+
+  // assetID returns the normalized ID of the asset of the token.
+  function assetID() external view returns (bytes32);
+}
+
+// IAssetTokenRegistry holds the ERC-20 tokens of the multicoin assets registered
+// by the precompile config.
+interface IAssetTokenRegistry {
+  // register returns the address of the token of [assetID], reverting if the asset isn't registered.
+  function register(bytes32 assetID) external view returns (address token);
+
+  // tokenAddress returns the address of the token of [assetID], whether or not it is registered.
+  function tokenAddress(bytes32 assetID) external pure returns (address token);
+
+  // assetID returns the asset ID of [token], or zero if it isn't a registered token.
+  function assetID(address token) external view returns (bytes32);
+}
@@ -0,0 +1,22 @@
+// Copyright (C) 2019-2025, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// SPDX-License-Identifier: MIT
+
+pragma solidity ^0.8.0;
+
+interface IFeeState {
+  // getFeeState returns the ACP-176 fee state resulting from the execution of the
+  // parent block.
+  function getFeeState() external view returns (uint64 capacity, uint64 excess, uint64 targetExcess);
+
+  // getTarget returns the target gas consumed per second of the parent block's fee state.
+  function getTarget() external view returns (uint64 target);
+
+  // getMaxCapacity returns the maximum possible accrued gas capacity of the parent block's
+  // fee state.
+  function getMaxCapacity() external view returns (uint64 maxCapacity);
+
+  // getGasPrice returns the required fee per gas of the parent block's fee state.
+  function getGasPrice() external view returns (uint64 gasPrice);
+}
@@ -117,6 +117,9 @@ var (
 	badBlockCounter              = metrics.GetOrRegisterCounter("chain/block/bad/count", nil)
 
 	txUnindexTimer      = metrics.GetOrRegisterCounter("chain/txs/unindex", nil)
+	txIndexTimer        = metrics.GetOrRegisterCounter("chain/txs/index", nil)
+	txIndexTailGauge    = metrics.GetOrRegisterGauge("chain/txs/index/tail", nil)
+	txIndexRemainGauge  = metrics.GetOrRegisterGauge("chain/txs/index/remaining", nil)
 	acceptedTxsCounter  = metrics.GetOrRegisterCounter("chain/txs/accepted", nil)
 	processedTxsCounter = metrics.GetOrRegisterCounter("chain/txs/processed", nil)
 
@@ -199,7 +202,12 @@ type CacheConfig struct {
 	AcceptedCacheSize               int     // Depth of accepted headers cache and accepted logs cache at the accepted tip
 	TransactionHistory              uint64  // Number of recent blocks for which to maintain transaction lookup indices
 	SkipTxIndexing                  bool    // Whether to skip transaction indexing
+	LogIndex                        bool    // Whether to index the logs of accepted blocks by address and topic
+	LogIndexHistory                 uint64  // Number of recent blocks whose logs are indexed (0 = all)
+	BodyHistory                     uint64  // Number of recent blocks whose bodies are retained (0 = all)
+	ReceiptHistory                  uint64  // Number of recent blocks whose receipts are retained (0 = all)
 	StateHistory                    uint64  // Number of blocks from head whose state histories are reserved.
+	StateHistoryIndex               bool    // Whether to index the state histories of the path scheme to serve historical states
 	StateScheme                     string  // Scheme used to store ethereum states and merkle tree nodes on top
 
 	ChainDataDir    string // Directory to store chain data in (used by Firewood)
@@ -222,6 +230,7 @@ func (c *CacheConfig) triedbConfig() *triedb.Config {
 			StateHistory:   c.StateHistory,
 			CleanCacheSize: c.TrieCleanLimit * 1024 * 1024,
 			DirtyCacheSize: c.TrieDirtyLimit * 1024 * 1024,
+			IndexHistory:   c.StateHistoryIndex,
 		}.BackendConstructor
 	}
 	if c.StateScheme == customrawdb.FirewoodScheme {
@@ -298,6 +307,8 @@ type BlockChain struct {
 	triedb       *triedb.Database // The database handler for maintaining trie nodes.
 	stateCache   state.Database   // State database to reuse between imports (contains state cache)
 	txIndexer    *txIndexer       // Transaction indexer, might be nil if not enabled
+	logIndexer   *logIndexer      // Log indexer, might be nil if not enabled
+	chainPruner  *chainPruner     // Body and receipt pruner, might be nil if not enabled
 	stateManager TrieWriter
 
 	hc                *HeaderChain
@@ -309,6 +320,7 @@ type BlockChain struct {
 	logsFeed          event.Feed
 	logsAcceptedFeed  event.Feed
 	blockProcFeed     event.Feed
+	blockStatsFeed    event.Feed
 	txAcceptedFeed    event.Feed
 	scope             event.SubscriptionScope
 	genesisBlock      *types.Block
@@ -494,9 +506,19 @@ func NewBlockChain(
 	// Start processing accepted blocks effects in the background
 	go bc.startAcceptor()
 
-	// Start tx indexer if it's enabled.
-	if bc.cacheConfig.TransactionHistory != 0 {
-		bc.txIndexer = newTxIndexer(bc.cacheConfig.TransactionHistory, bc)
+	// Start tx indexer if it's enabled, or if the transactions of the blocks
+	// below the index tail must be indexed again after the limit was removed.
+	reindexTxs := !bc.cacheConfig.SkipTxIndexing
+	if tail := rawdb.ReadTxIndexTail(bc.db); bc.cacheConfig.TransactionHistory != 0 || (reindexTxs && tail != nil && *tail > 0) {
+		bc.txIndexer = newTxIndexer(bc.cacheConfig.TransactionHistory, reindexTxs, bc)
+	}
+	// Start log indexer if it's enabled.
+	if bc.cacheConfig.LogIndex {
+		bc.logIndexer = newLogIndexer(bc.cacheConfig.LogIndexHistory, bc)
+	}
+	// Start history pruner if bodies or receipts are not retained for every block.
+	if bc.cacheConfig.BodyHistory != 0 || bc.cacheConfig.ReceiptHistory != 0 {
+		bc.chainPruner = newChainPruner(bc.cacheConfig.BodyHistory, bc.cacheConfig.ReceiptHistory, bc)
 	}
 	return bc, nil
 }
@@ -962,6 +984,14 @@ func (bc *BlockChain) stopWithoutSaving() {
 	if bc.txIndexer != nil {
 		bc.txIndexer.close()
 	}
+	// Signal shutdown log indexer.
+	if bc.logIndexer != nil {
+		bc.logIndexer.close()
+	}
+	// Signal shutdown history pruner.
+	if bc.chainPruner != nil {
+		bc.chainPruner.close()
+	}
 
 	log.Info("Closing quit channel")
 	close(bc.quit)
@@ -1427,8 +1457,22 @@ func (bc *BlockChain) insertBlock(block *types.Block, writes bool) error {
 	storageCommitTimer.Inc(statedb.StorageCommits.Milliseconds())   // Storage commits are complete, we can mark them
 	snapshotCommitTimer.Inc(statedb.SnapshotCommits.Milliseconds()) // Snapshot commits are complete, we can mark them
 	triedbCommitTimer.Inc(statedb.TrieDBCommits.Milliseconds())     // Trie database commits are complete, we can mark them
-	blockWriteTimer.Inc((time.Since(wstart) - statedb.AccountCommits - statedb.StorageCommits - statedb.SnapshotCommits - statedb.TrieDBCommits).Milliseconds())
-	blockInsertTimer.Inc(time.Since(start).Milliseconds())
+	commit := statedb.AccountCommits + statedb.StorageCommits + statedb.SnapshotCommits + statedb.TrieDBCommits
+	write := time.Since(wstart) - commit
+	blockWriteTimer.Inc(write.Milliseconds())
+	total := time.Since(start)
+	blockInsertTimer.Inc(total.Milliseconds())
+
+	bc.blockStatsFeed.Send(BlockInsertStatsEvent{
+		Block:      block,
+		Execution:  ptime - trieRead,
+		StateReads: trieRead,
+		TrieHash:   triehash + trieUpdate,
+		Validation: vtime - (triehash + trieUpdate),
+		Commit:     commit,
+		Write:      write,
+		Total:      total,
+	})
 
 	log.Debug("Inserted new block", "number", block.Number(), "hash", block.Hash(),
 		"parentHash", block.ParentHash(),
@@ -0,0 +1,88 @@
+// Copyright (C) 2019-2025, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package core
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/MetalBlockchain/libevm/common"
+	"github.com/MetalBlockchain/libevm/core/rawdb"
+	"github.com/MetalBlockchain/libevm/core/types"
+	"github.com/MetalBlockchain/libevm/core/vm"
+	"github.com/MetalBlockchain/libevm/crypto"
+	ethparams "github.com/MetalBlockchain/libevm/params"
+	"github.com/stretchr/testify/require"
+
+	"github.com/MetalBlockchain/coreth/consensus/dummy"
+	"github.com/MetalBlockchain/coreth/params"
+	"github.com/MetalBlockchain/coreth/plugin/evm/upgrade/ap3"
+)
+
+// TestStateHistoryIndex tests that an archive node using the path scheme
+// serves the accounts and storage slots of historical states from its indexed
+// state histories.
+func TestStateHistoryIndex(t *testing.T) {
+	const (
+		numBlocks = 10
+		transfer  = 1000
+	)
+	var (
+		require = require.New(t)
+		engine  = dummy.NewCoinbaseFaker()
+		key1, _ = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+		addr1   = crypto.PubkeyToAddress(key1.PublicKey)
+		addr2   = common.Address{0x02}
+		// The contract stores the block number in slot 0 when called:
+		// NUMBER PUSH1 0 SSTORE STOP
+		contract = common.Address{0x03}
+		funds    = new(big.Int).Mul(big.NewInt(100), big.NewInt(params.Ether))
+		gspec    = &Genesis{
+			Config: params.TestChainConfig,
+			Alloc: types.GenesisAlloc{
+				addr1:    {Balance: funds},
+				contract: {Code: []byte{byte(vm.NUMBER), byte(vm.PUSH1), 0, byte(vm.SSTORE), byte(vm.STOP)}},
+			},
+			BaseFee: big.NewInt(ap3.InitialBaseFee),
+		}
+		signer = types.LatestSigner(gspec.Config)
+	)
+
+	_, blocks, _, err := GenerateChainWithGenesis(gspec, engine, numBlocks, 10, func(i int, b *BlockGen) {
+		tx, err := types.SignTx(types.NewTransaction(uint64(2*i), addr2, big.NewInt(transfer), ethparams.TxGas, b.BaseFee(), nil), signer, key1)
+		require.NoError(err)
+		b.AddTx(tx)
+		tx, err = types.SignTx(types.NewTransaction(uint64(2*i+1), contract, common.Big0, 100_000, b.BaseFee(), nil), signer, key1)
+		require.NoError(err)
+		b.AddTx(tx)
+	})
+	require.NoError(err)
+
+	cacheConfig := DefaultCacheConfigWithScheme(rawdb.PathScheme)
+	cacheConfig.Pruning = false
+	cacheConfig.SnapshotLimit = 0
+	cacheConfig.StateHistoryIndex = true
+	cacheConfig.ChainDataDir = t.TempDir()
+	chain, err := NewBlockChain(rawdb.NewMemoryDatabase(), cacheConfig, gspec, engine, vm.Config{}, common.Hash{}, false)
+	require.NoError(err)
+	defer chain.Stop()
+
+	_, err = chain.InsertChain(blocks)
+	require.NoError(err)
+	for _, block := range blocks {
+		require.NoError(chain.Accept(block))
+	}
+	chain.DrainAcceptorQueue()
+
+	for number := uint64(0); number <= numBlocks; number++ {
+		header := chain.GetHeaderByNumber(number)
+		statedb, err := chain.StateAt(header.Root)
+		require.NoError(err, "block %d", number)
+
+		require.Equal(number*transfer, statedb.GetBalance(addr2).Uint64(), "block %d", number)
+		require.Equal(2*number, statedb.GetNonce(addr1), "block %d", number)
+		require.Equal(common.BigToHash(new(big.Int).SetUint64(number)), statedb.GetState(contract, common.Hash{}), "block %d", number)
+		require.NotEmpty(statedb.GetCode(contract), "block %d", number)
+	}
+}
@@ -340,6 +340,12 @@ func (bc *BlockChain) SubscribeBlockProcessingEvent(ch chan<- bool) event.Subscr
 	return bc.scope.Track(bc.blockProcFeed.Subscribe(ch))
 }
 
+// SubscribeBlockInsertStatsEvent registers a subscription of BlockInsertStatsEvent,
+// posted after each block is inserted.
+func (bc *BlockChain) SubscribeBlockInsertStatsEvent(ch chan<- BlockInsertStatsEvent) event.Subscription {
+	return bc.scope.Track(bc.blockStatsFeed.Subscribe(ch))
+}
+
 // SubscribeChainAcceptedEvent registers a subscription of ChainEvent.
 func (bc *BlockChain) SubscribeChainAcceptedEvent(ch chan<- ChainEvent) event.Subscription {
 	return bc.scope.Track(bc.chainAcceptedFeed.Subscribe(ch))
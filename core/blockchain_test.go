@@ -1137,3 +1137,48 @@ func TestEIP3651(t *testing.T) {
 		t.Fatalf("sender balance incorrect: expected %d, got %d", expected, actual)
 	}
 }
+
+// TestBlockInsertStatsEvent checks that the insertion of every block posts a
+// BlockInsertStatsEvent, whose phases do not exceed the total insertion time.
+func TestBlockInsertStatsEvent(t *testing.T) {
+	var (
+		key1, _ = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+		addr1   = crypto.PubkeyToAddress(key1.PublicKey)
+		gspec   = &Genesis{
+			Config: &params.ChainConfig{HomesteadBlock: new(big.Int)},
+			Alloc:  types.GenesisAlloc{addr1: {Balance: big.NewInt(1000000)}},
+		}
+	)
+	blockchain, err := createBlockChain(rawdb.NewMemoryDatabase(), pruningConfig, gspec, common.Hash{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer blockchain.Stop()
+
+	signer := types.HomesteadSigner{}
+	_, chain, _, err := GenerateChainWithGenesis(gspec, blockchain.engine, 3, 10, func(i int, gen *BlockGen) {
+		tx, _ := types.SignTx(types.NewTransaction(gen.TxNonce(addr1), common.Address{1}, big.NewInt(10000), ethparams.TxGas, nil, nil), signer, key1)
+		gen.AddTx(tx)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	statsCh := make(chan BlockInsertStatsEvent, len(chain))
+	sub := blockchain.SubscribeBlockInsertStatsEvent(statsCh)
+	defer sub.Unsubscribe()
+
+	if _, err := blockchain.InsertChain(chain); err != nil {
+		t.Fatal(err)
+	}
+	for _, block := range chain {
+		stats := <-statsCh
+		if stats.Block.Hash() != block.Hash() {
+			t.Fatalf("got stats of block %s, want %s", stats.Block.Hash(), block.Hash())
+		}
+		phases := stats.Execution + stats.StateReads + stats.TrieHash + stats.Validation + stats.Commit + stats.Write
+		if stats.Total <= 0 || phases > stats.Total {
+			t.Fatalf("block %d: phases took %s, which exceeds the total of %s", block.NumberU64(), phases, stats.Total)
+		}
+	}
+}
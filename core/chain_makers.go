@@ -108,10 +108,11 @@ func (b *BlockGen) Difficulty() *big.Int {
 // block.
 func (b *BlockGen) SetParentBeaconRoot(root common.Hash) {
 	b.header.ParentBeaconRoot = &root
-	var (
-		blockContext = NewEVMBlockContext(b.header, b.cm, &b.header.Coinbase)
-		vmenv        = vm.NewEVM(blockContext, vm.TxContext{}, b.statedb, b.cm.config, vm.Config{})
-	)
+	blockContext, err := NewEVMBlockContext(b.header, b.cm, &b.header.Coinbase)
+	if err != nil {
+		panic(err)
+	}
+	vmenv := vm.NewEVM(blockContext, vm.TxContext{}, b.statedb, b.cm.config, vm.Config{})
 	ProcessBeaconBlockRoot(root, vmenv, b.statedb)
 }
 
@@ -127,7 +128,14 @@ func (b *BlockGen) addTx(bc *BlockChain, vmConfig vm.Config, tx *types.Transacti
 		b.SetCoinbase(common.Address{})
 	}
 	b.statedb.SetTxContext(tx.Hash(), len(b.txs))
-	blockContext := NewEVMBlockContext(b.header, bc, &b.header.Coinbase)
+	var chain ChainContext = b.cm
+	if bc != nil {
+		chain = bc
+	}
+	blockContext, err := NewEVMBlockContext(b.header, chain, &b.header.Coinbase)
+	if err != nil {
+		panic(err)
+	}
 	receipt, err := ApplyTransaction(b.cm.config, bc, blockContext, b.gasPool, b.statedb, b.header, tx, &b.header.GasUsed, vmConfig)
 	if err != nil {
 		panic(err)
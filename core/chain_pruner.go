@@ -0,0 +1,266 @@
+// Copyright (C) 2019-2025, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package core
+
+import (
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/MetalBlockchain/libevm/common"
+	"github.com/MetalBlockchain/libevm/core/rawdb"
+	"github.com/MetalBlockchain/libevm/ethdb"
+	"github.com/MetalBlockchain/libevm/log"
+	"github.com/MetalBlockchain/libevm/metrics"
+
+	"github.com/MetalBlockchain/coreth/plugin/evm/customrawdb"
+)
+
+// historyPruneBatchBlocks is the number of blocks pruned before checking for
+// shutdown.
+const historyPruneBatchBlocks = 1024
+
+var (
+	bodyTailGauge      = metrics.GetOrRegisterGauge("chain/history/bodies/tail", nil)
+	receiptTailGauge   = metrics.GetOrRegisterGauge("chain/history/receipts/tail", nil)
+	historyPruneTimer  = metrics.GetOrRegisterCounter("chain/history/prune", nil)
+	historyPruneCount  = metrics.GetOrRegisterCounter("chain/history/prune/count", nil)
+	errHistoryPruneEnd = errors.New("history pruning interrupted")
+)
+
+// historyTier is a kind of block data retained for a limited number of
+// blocks from the last accepted block.
+type historyTier struct {
+	name   string
+	limit  uint64 // 0 retains the data of every block
+	gauge  metrics.Gauge
+	read   func(ethdb.KeyValueReader) (*uint64, error)
+	write  func(ethdb.KeyValueWriter, uint64) error
+	delete func(ethdb.KeyValueWriter, common.Hash, uint64)
+}
+
+// chainPruner deletes the bodies and receipts of the accepted blocks older
+// than their configured retention, in the background. The genesis block is
+// always retained, as it is loaded when the chain is initialized.
+//
+// Receipts are pruned before bodies, so that the receipts of a block are never
+// retained without its body. Neither are pruned for the blocks still in the
+// log index or the transaction index, as the indexers may lag behind the pruner
+// and read the bodies or receipts of the blocks they unindex.
+type chainPruner struct {
+	db       ethdb.Database
+	head     atomic.Uint64         // Number of the last accepted block
+	tiers    []historyTier         // Tiers to prune, in order
+	retained func() (uint64, bool) // Lowest block that must be retained, if any
+
+	term   chan chan struct{}
+	closed chan struct{}
+}
+
+// newChainPruner initializes the pruner of the bodies and receipts older
+// than [bodyLimit] and [receiptLimit] blocks respectively. A limit of 0
+// retains the data of every block.
+func newChainPruner(bodyLimit, receiptLimit uint64, chain *BlockChain) *chainPruner {
+	pruner := &chainPruner{
+		db:    chain.db,
+		tiers: historyTiers(bodyLimit, receiptLimit),
+		retained: func() (uint64, bool) {
+			lowest, ok := uint64(0), false
+			if tail, _, indexed := chain.LogIndexRange(); indexed {
+				lowest, ok = tail, true
+			}
+			// The transactions are indexed from the genesis block until the
+			// index tail is first written.
+			if chain.txIndexer != nil {
+				txTail := uint64(0)
+				if tail := rawdb.ReadTxIndexTail(chain.db); tail != nil {
+					txTail = *tail
+				}
+				if !ok || txTail < lowest {
+					lowest, ok = txTail, true
+				}
+			}
+			return lowest, ok
+		},
+		term:   make(chan chan struct{}),
+		closed: make(chan struct{}),
+	}
+	pruner.head.Store(chain.LastAcceptedBlock().NumberU64())
+	for _, tier := range pruner.tiers {
+		if tail, err := tier.read(chain.db); err == nil && tail != nil {
+			tier.gauge.Update(int64(*tail))
+		}
+	}
+
+	chain.wg.Add(1)
+	go func() {
+		defer chain.wg.Done()
+		pruner.loop(chain)
+	}()
+
+	log.Info("Initialized chain history pruner", "bodies", bodyLimit, "receipts", receiptLimit)
+	return pruner
+}
+
+// historyTiers returns the tiers retaining the bodies and receipts of
+// [bodyLimit] and [receiptLimit] blocks respectively, in the order they must
+// be pruned.
+func historyTiers(bodyLimit, receiptLimit uint64) []historyTier {
+	return []historyTier{
+		{
+			name:   "receipts",
+			limit:  receiptLimit,
+			gauge:  receiptTailGauge,
+			read:   customrawdb.ReadReceiptTail,
+			write:  customrawdb.WriteReceiptTail,
+			delete: rawdb.DeleteReceipts,
+		},
+		{
+			name:   "bodies",
+			limit:  bodyLimit,
+			gauge:  bodyTailGauge,
+			read:   customrawdb.ReadBodyTail,
+			write:  customrawdb.WriteBodyTail,
+			delete: rawdb.DeleteBody,
+		},
+	}
+}
+
+// loop is the scheduler of the pruner, running a pruning task whenever blocks
+// are accepted.
+func (pruner *chainPruner) loop(chain *BlockChain) {
+	defer close(pruner.closed)
+
+	var (
+		stop chan struct{} // Non-nil if background routine is active.
+		done chan struct{} // Non-nil if background routine is active.
+		last uint64        // The head of the last launched task
+
+		headCh = make(chan ChainEvent)
+		sub    = chain.SubscribeChainAcceptedEvent(headCh)
+	)
+	if sub == nil {
+		log.Warn("could not create chain accepted subscription to prune history")
+		return
+	}
+	defer sub.Unsubscribe()
+
+	launch := func() {
+		stop = make(chan struct{})
+		done = make(chan struct{})
+		last = pruner.head.Load()
+		go pruner.run(last, stop, done)
+	}
+	launch()
+
+	for {
+		select {
+		case head := <-headCh:
+			pruner.head.Store(head.Block.NumberU64())
+			if done == nil {
+				launch()
+			}
+		case <-done:
+			stop = nil
+			done = nil
+			// Blocks accepted as the task completed are pruned by a new task.
+			if pruner.head.Load() != last {
+				launch()
+			}
+		case ch := <-pruner.term:
+			if stop != nil {
+				close(stop)
+			}
+			if done != nil {
+				log.Info("Waiting background history pruner to exit")
+				<-done
+			}
+			close(ch)
+			return
+		}
+	}
+}
+
+// run prunes the data of each tier older than its limit from [head], until
+// there is nothing left to prune or [stop] is closed.
+func (pruner *chainPruner) run(head uint64, stop chan struct{}, done chan struct{}) {
+	start := time.Now()
+	defer func() {
+		historyPruneTimer.Inc(time.Since(start).Milliseconds())
+		close(done)
+	}()
+
+	for _, tier := range pruner.tiers {
+		if tier.limit == 0 || head < tier.limit {
+			continue
+		}
+		target := head - tier.limit + 1
+		if pruner.retained != nil {
+			if lowest, ok := pruner.retained(); ok {
+				target = min(target, lowest)
+			}
+		}
+		if err := pruner.prune(tier, target, stop); err != nil {
+			if !errors.Is(err, errHistoryPruneEnd) {
+				log.Error("Failed to prune history", "kind", tier.name, "err", err)
+			}
+			return
+		}
+	}
+}
+
+// prune deletes the data of [tier] for the blocks below [target], from the
+// tail of the tier.
+func (pruner *chainPruner) prune(tier historyTier, target uint64, stop chan struct{}) error {
+	tail, err := tier.read(pruner.db)
+	if err != nil {
+		return err
+	}
+	from := uint64(1) // The genesis block is retained
+	if tail != nil {
+		from = max(from, *tail)
+	}
+	for from < target {
+		select {
+		case <-stop:
+			return errHistoryPruneEnd
+		default:
+		}
+
+		// Only the blocks stored locally are visited, as blocks may be
+		// missing below the last state sync.
+		var (
+			batch           = pruner.db.NewBatch()
+			numbers, hashes = rawdb.ReadAllCanonicalHashes(pruner.db, from, target, historyPruneBatchBlocks)
+			next            = target
+		)
+		for i, number := range numbers {
+			tier.delete(batch, hashes[i], number)
+		}
+		if len(numbers) == historyPruneBatchBlocks {
+			next = numbers[len(numbers)-1] + 1
+		}
+		if err := tier.write(batch, next); err != nil {
+			return err
+		}
+		if err := batch.Write(); err != nil {
+			return fmt.Errorf("failed to write pruned %s: %w", tier.name, err)
+		}
+		historyPruneCount.Inc(int64(len(numbers)))
+		tier.gauge.Update(int64(next))
+		from = next
+	}
+	return nil
+}
+
+// close shutdown the pruner. Safe to be called for multiple times.
+func (pruner *chainPruner) close() {
+	ch := make(chan struct{})
+	select {
+	case pruner.term <- ch:
+		<-ch
+	case <-pruner.closed:
+	}
+}
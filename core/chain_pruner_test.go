@@ -0,0 +1,144 @@
+// Copyright (C) 2019-2025, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package core
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/MetalBlockchain/libevm/common"
+	"github.com/MetalBlockchain/libevm/core/rawdb"
+	"github.com/MetalBlockchain/libevm/core/types"
+	"github.com/MetalBlockchain/libevm/core/vm"
+	"github.com/MetalBlockchain/libevm/crypto"
+	"github.com/MetalBlockchain/libevm/ethdb"
+	"github.com/stretchr/testify/require"
+
+	"github.com/MetalBlockchain/coreth/consensus/dummy"
+	"github.com/MetalBlockchain/coreth/params"
+	"github.com/MetalBlockchain/coreth/plugin/evm/customrawdb"
+	"github.com/MetalBlockchain/coreth/plugin/evm/upgrade/ap3"
+)
+
+// TestChainHistoryRetention tests that the bodies, receipts and log index
+// entries of the blocks older than their retention are deleted.
+func TestChainHistoryRetention(t *testing.T) {
+	const (
+		numBlocks       = 20
+		bodyHistory     = 8
+		receiptHistory  = 6
+		logIndexHistory = 4
+	)
+	var (
+		require = require.New(t)
+		engine  = dummy.NewCoinbaseFaker()
+		key1, _ = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+		addr1   = crypto.PubkeyToAddress(key1.PublicKey)
+		// The contract emits a log with the block number as topic when called:
+		// NUMBER PUSH1 0 PUSH1 0 LOG1 STOP
+		contract = common.Address{0x03}
+		funds    = new(big.Int).Mul(big.NewInt(100), big.NewInt(params.Ether))
+		gspec    = &Genesis{
+			Config: params.TestChainConfig,
+			Alloc: types.GenesisAlloc{
+				addr1:    {Balance: funds},
+				contract: {Code: []byte{byte(vm.NUMBER), byte(vm.PUSH1), 0, byte(vm.PUSH1), 0, byte(vm.LOG1), byte(vm.STOP)}},
+			},
+			BaseFee: big.NewInt(ap3.InitialBaseFee),
+		}
+		signer = types.LatestSigner(gspec.Config)
+	)
+	_, blocks, _, err := GenerateChainWithGenesis(gspec, engine, numBlocks, 10, func(i int, b *BlockGen) {
+		tx, err := types.SignTx(types.NewTransaction(uint64(i), contract, common.Big0, 30_000, b.BaseFee(), nil), signer, key1)
+		require.NoError(err)
+		b.AddTx(tx)
+	})
+	require.NoError(err)
+
+	var (
+		db          = rawdb.NewMemoryDatabase()
+		cacheConfig = *DefaultCacheConfig
+	)
+	cacheConfig.BodyHistory = bodyHistory
+	cacheConfig.ReceiptHistory = receiptHistory
+	cacheConfig.LogIndex = true
+	cacheConfig.LogIndexHistory = logIndexHistory
+	chain, err := NewBlockChain(db, &cacheConfig, gspec, engine, vm.Config{}, common.Hash{}, false)
+	require.NoError(err)
+	defer chain.Stop()
+
+	_, err = chain.InsertChain(blocks)
+	require.NoError(err)
+	for _, block := range blocks {
+		require.NoError(chain.Accept(block))
+	}
+	chain.DrainAcceptorQueue()
+
+	waitTail := func(read func(ethdb.KeyValueReader) (*uint64, error), expected uint64) {
+		require.Eventually(func() bool {
+			tail, err := read(db)
+			return err == nil && tail != nil && *tail == expected
+		}, 5*time.Second, 10*time.Millisecond)
+	}
+	waitTail(customrawdb.ReadBodyTail, numBlocks-bodyHistory+1)
+	waitTail(customrawdb.ReadReceiptTail, numBlocks-receiptHistory+1)
+	require.Eventually(func() bool {
+		tail, head, ok := chain.LogIndexRange()
+		return ok && tail == numBlocks-logIndexHistory+1 && head == numBlocks
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// The genesis block is always retained.
+	require.NotNil(rawdb.ReadBody(db, chain.Genesis().Hash(), 0))
+	for _, block := range blocks {
+		number := block.NumberU64()
+		require.Equal(number > numBlocks-bodyHistory, rawdb.ReadBody(db, block.Hash(), number) != nil, "body of block %d", number)
+		require.Equal(number > numBlocks-receiptHistory, rawdb.ReadRawReceipts(db, block.Hash(), number) != nil, "receipts of block %d", number)
+	}
+
+	// Entries below the indexed range are not read by log filters.
+	var expected []uint64
+	for number := uint64(numBlocks - logIndexHistory + 1); number <= numBlocks; number++ {
+		expected = append(expected, number)
+	}
+	numbers, err := customrawdb.ReadLogIndexAddressBlocks(db, contract, numBlocks-logIndexHistory+1, numBlocks)
+	require.NoError(err)
+	require.Equal(expected, numbers)
+}
+
+// TestChainPrunerRetainsLogIndex tests that the bodies and receipts of the
+// blocks still in the log index are not pruned.
+func TestChainPrunerRetainsLogIndex(t *testing.T) {
+	var (
+		require = require.New(t)
+		db      = rawdb.NewMemoryDatabase()
+		logTail uint64
+		pruner  = &chainPruner{
+			db:    db,
+			tiers: historyTiers(8, 6),
+			retained: func() (uint64, bool) {
+				return logTail, true
+			},
+		}
+	)
+	prune := func(head uint64, expectedReceiptTail, expectedBodyTail uint64) {
+		pruner.run(head, make(chan struct{}), make(chan struct{}))
+
+		tail, err := customrawdb.ReadReceiptTail(db)
+		require.NoError(err)
+		require.NotNil(tail)
+		require.Equal(expectedReceiptTail, *tail)
+		tail, err = customrawdb.ReadBodyTail(db)
+		require.NoError(err)
+		require.NotNil(tail)
+		require.Equal(expectedBodyTail, *tail)
+	}
+
+	// The log indexer has not unindexed the blocks below block 10 yet.
+	logTail = 10
+	prune(20, 10, 10)
+
+	logTail = 17
+	prune(20, 15, 13)
+}
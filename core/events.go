@@ -28,6 +28,8 @@
 package core
 
 import (
+	"time"
+
 	"github.com/MetalBlockchain/libevm/common"
 	"github.com/MetalBlockchain/libevm/core/types"
 )
@@ -56,3 +58,17 @@ type ChainSideEvent struct {
 }
 
 type ChainHeadEvent struct{ Block *types.Block }
+
+// BlockInsertStatsEvent is posted after a block is inserted, with the time
+// spent in each phase of its insertion.
+type BlockInsertStatsEvent struct {
+	Block *types.Block
+
+	Execution  time.Duration // EVM processing, excluding state reads
+	StateReads time.Duration // Account and storage reads from the snapshot and tries
+	TrieHash   time.Duration // Account and storage trie updates and hashing
+	Validation time.Duration // Block validation, excluding trie updates and hashing
+	Commit     time.Duration // Account, storage, snapshot and trie database commits
+	Write      time.Duration // Block write, excluding commits
+	Total      time.Duration
+}
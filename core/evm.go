@@ -29,6 +29,8 @@ package core
 
 import (
 	"bytes"
+	"errors"
+	"fmt"
 	"math/big"
 
 	"github.com/MetalBlockchain/coreth/consensus"
@@ -45,6 +47,11 @@ import (
 	"github.com/holiman/uint256"
 )
 
+var (
+	errMissingChainConfig = errors.New("chain context does not provide the chain config")
+	errMissingParent      = errors.New("missing parent header")
+)
+
 func init() {
 	vm.RegisterHooks(hooks{})
 }
@@ -115,7 +122,14 @@ type ChainContext interface {
 }
 
 // NewEVMBlockContext creates a new context for use in the EVM.
-func NewEVMBlockContext(header *types.Header, chain ChainContext, author *common.Address) vm.BlockContext {
+//
+// After Fortuna, the parent header must be available through the chain to
+// expose its fee state, otherwise an error is returned.
+func NewEVMBlockContext(header *types.Header, chain ChainContext, author *common.Address) (vm.BlockContext, error) {
+	extra, err := evmContextExtra(header, chain)
+	if err != nil {
+		return vm.BlockContext{}, err
+	}
 	var (
 		beneficiary common.Address
 		baseFee     *big.Int
@@ -148,24 +162,74 @@ func NewEVMBlockContext(header *types.Header, chain ChainContext, author *common
 		Header: &types.Header{
 			Number: new(big.Int).Set(header.Number),
 			Time:   header.Time,
-			Extra:  header.Extra,
+			Extra:  extra,
 		},
+	}, nil
+}
+
+// chainConfigReader is implemented by [ChainContext]s which are able to provide
+// the chain configuration, such as the [BlockChain].
+type chainConfigReader interface {
+	Config() *params.ChainConfig
+}
+
+// evmContextExtra returns the Extra field to expose through the EVM block
+// context.
+//
+// After Fortuna, the fee state prefix is replaced with the fee state of the
+// parent block. Unlike the fee state of the block itself, the parent fee state
+// is known before the block is built, so it can be consistently exposed to
+// precompiles during both block building and verification. Any predicate
+// results following the prefix are maintained.
+//
+// As the parent fee state is exposed to precompiles, it is part of the
+// consensus rules. Failing to provide it would expose the fee state after the
+// block instead, so an error is returned.
+func evmContextExtra(header *types.Header, chain ChainContext) ([]byte, error) {
+	if header.Number.Sign() == 0 {
+		return header.Extra, nil
+	}
+	reader, ok := chain.(chainConfigReader)
+	if !ok {
+		return nil, fmt.Errorf("%w: %T", errMissingChainConfig, chain)
+	}
+	config := params.GetExtra(reader.Config())
+	if !config.IsFortuna(header.Time) {
+		return header.Extra, nil
 	}
+	number := header.Number.Uint64() - 1
+	parent := chain.GetHeader(header.ParentHash, number)
+	if parent == nil {
+		return nil, fmt.Errorf("%w: %s (%d)", errMissingParent, header.ParentHash, number)
+	}
+	state, err := customheader.ParentFeeState(config, parent)
+	if err != nil {
+		return nil, fmt.Errorf("parent fee state of block %d: %w", header.Number, err)
+	}
+	rules := config.GetAvalancheRules(header.Time)
+	return customheader.SetPredicateBytesInExtra(
+		rules,
+		state.Bytes(),
+		customheader.PredicateBytesFromExtra(rules, header.Extra),
+	), nil
 }
 
 // NewEVMBlockContextWithPredicateResults creates a new context for use in the
 // EVM with an override for the predicate results. The miner uses this to pass
 // predicate results to the EVM when header.Extra is not fully formed yet.
-func NewEVMBlockContextWithPredicateResults(rules extras.AvalancheRules, header *types.Header, chain ChainContext, author *common.Address, predicateBytes []byte) vm.BlockContext {
-	blockCtx := NewEVMBlockContext(header, chain, author)
+func NewEVMBlockContextWithPredicateResults(rules extras.AvalancheRules, header *types.Header, chain ChainContext, author *common.Address, predicateBytes []byte) (vm.BlockContext, error) {
+	blockCtx, err := NewEVMBlockContext(header, chain, author)
+	if err != nil {
+		return vm.BlockContext{}, err
+	}
 	// Note this only sets the block context, which is the hand-off point for
 	// the EVM. The actual header is not modified.
 	blockCtx.Header.Extra = customheader.SetPredicateBytesInExtra(
 		rules,
-		bytes.Clone(header.Extra),
+		bytes.Clone(blockCtx.Header.Extra),
 		predicateBytes,
 	)
-	return blockCtx
+	return blockCtx, nil
 }
 
 // NewEVMTxContext creates a new transaction context for a single transaction.
@@ -0,0 +1,183 @@
+// Copyright (C) 2019-2025, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package core
+
+import (
+	"math/big"
+	"slices"
+	"testing"
+
+	"github.com/MetalBlockchain/metalgo/vms/components/gas"
+	"github.com/MetalBlockchain/libevm/common"
+	"github.com/MetalBlockchain/libevm/core/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/MetalBlockchain/coreth/consensus"
+	"github.com/MetalBlockchain/coreth/params"
+	"github.com/MetalBlockchain/coreth/plugin/evm/upgrade/acp176"
+)
+
+// headerChain is a [ChainContext] which does not provide the chain config.
+type headerChain map[common.Hash]*types.Header
+
+func (c headerChain) Engine() consensus.Engine { return nil }
+
+func (c headerChain) GetHeader(hash common.Hash, number uint64) *types.Header {
+	header, ok := c[hash]
+	if !ok || header.Number.Uint64() != number {
+		return nil
+	}
+	return header
+}
+
+type configuredHeaderChain struct {
+	headerChain
+	config *params.ChainConfig
+}
+
+func (c configuredHeaderChain) Config() *params.ChainConfig { return c.config }
+
+func TestEVMContextExtra(t *testing.T) {
+	parentState := acp176.State{
+		Gas: gas.State{
+			Capacity: 1_000,
+			Excess:   2_000,
+		},
+		TargetExcess: 3_000,
+	}
+	var (
+		predicateBytes = []byte{1, 2, 3}
+		// childState is the fee state claimed by the child block, which must
+		// not be exposed through the EVM block context.
+		childState = acp176.State{TargetExcess: 4_000}
+		childExtra = append(childState.Bytes(), predicateBytes...)
+
+		genesis = &types.Header{
+			Number: big.NewInt(0),
+		}
+		parent = &types.Header{
+			ParentHash: genesis.Hash(),
+			Number:     big.NewInt(1),
+			Extra:      parentState.Bytes(),
+		}
+		invalidParent = &types.Header{
+			ParentHash: genesis.Hash(),
+			Number:     big.NewInt(1),
+			Extra:      []byte{1},
+		}
+		headers = headerChain{
+			genesis.Hash():       genesis,
+			parent.Hash():        parent,
+			invalidParent.Hash(): invalidParent,
+		}
+	)
+	tests := []struct {
+		name    string
+		chain   ChainContext
+		header  *types.Header
+		want    []byte
+		wantErr error
+	}{
+		{
+			name:  "genesis",
+			chain: headers,
+			header: &types.Header{
+				Number: big.NewInt(0),
+				Extra:  childExtra,
+			},
+			want: childExtra,
+		},
+		{
+			name:  "pre_fortuna",
+			chain: configuredHeaderChain{headers, params.TestEtnaChainConfig},
+			header: &types.Header{
+				ParentHash: parent.Hash(),
+				Number:     big.NewInt(2),
+				Extra:      childExtra,
+			},
+			want: childExtra,
+		},
+		{
+			name:  "fortuna_after_genesis",
+			chain: configuredHeaderChain{headers, params.TestFortunaChainConfig},
+			header: &types.Header{
+				ParentHash: genesis.Hash(),
+				Number:     big.NewInt(1),
+				Extra:      childExtra,
+			},
+			want: append((&acp176.State{}).Bytes(), predicateBytes...),
+		},
+		{
+			name:  "fortuna",
+			chain: configuredHeaderChain{headers, params.TestFortunaChainConfig},
+			header: &types.Header{
+				ParentHash: parent.Hash(),
+				Number:     big.NewInt(2),
+				Extra:      childExtra,
+			},
+			want: append(parentState.Bytes(), predicateBytes...),
+		},
+		{
+			name:  "fortuna_without_predicates",
+			chain: configuredHeaderChain{headers, params.TestFortunaChainConfig},
+			header: &types.Header{
+				ParentHash: parent.Hash(),
+				Number:     big.NewInt(2),
+				Extra:      childState.Bytes(),
+			},
+			want: parentState.Bytes(),
+		},
+		{
+			name:  "missing_chain",
+			chain: nil,
+			header: &types.Header{
+				ParentHash: parent.Hash(),
+				Number:     big.NewInt(2),
+				Extra:      childExtra,
+			},
+			wantErr: errMissingChainConfig,
+		},
+		{
+			name:  "missing_chain_config",
+			chain: headers,
+			header: &types.Header{
+				ParentHash: parent.Hash(),
+				Number:     big.NewInt(2),
+				Extra:      childExtra,
+			},
+			wantErr: errMissingChainConfig,
+		},
+		{
+			name:  "missing_parent",
+			chain: configuredHeaderChain{headers, params.TestFortunaChainConfig},
+			header: &types.Header{
+				ParentHash: common.Hash{1},
+				Number:     big.NewInt(2),
+				Extra:      childExtra,
+			},
+			wantErr: errMissingParent,
+		},
+		{
+			name:  "invalid_parent_fee_state",
+			chain: configuredHeaderChain{headers, params.TestFortunaChainConfig},
+			header: &types.Header{
+				ParentHash: invalidParent.Hash(),
+				Number:     big.NewInt(2),
+				Extra:      childExtra,
+			},
+			wantErr: acp176.ErrStateInsufficientLength,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require := require.New(t)
+
+			original := slices.Clone(test.header.Extra)
+			got, err := evmContextExtra(test.header, test.chain)
+			require.ErrorIs(err, test.wantErr)
+			require.Equal(test.want, got)
+			require.Equal(original, test.header.Extra, "header must not be modified")
+		})
+	}
+}
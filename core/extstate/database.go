@@ -9,25 +9,37 @@ import (
 	"github.com/MetalBlockchain/libevm/triedb"
 
 	"github.com/MetalBlockchain/coreth/triedb/firewood"
+	"github.com/MetalBlockchain/coreth/triedb/pathdb"
 )
 
 func NewDatabaseWithConfig(db ethdb.Database, config *triedb.Config) state.Database {
 	coredb := state.NewDatabaseWithConfig(db, config)
-	return wrapIfFirewood(coredb)
+	return wrapBackend(coredb)
 }
 
 func NewDatabaseWithNodeDB(db ethdb.Database, triedb *triedb.Database) state.Database {
 	coredb := state.NewDatabaseWithNodeDB(db, triedb)
-	return wrapIfFirewood(coredb)
+	return wrapBackend(coredb)
 }
 
-func wrapIfFirewood(db state.Database) state.Database {
-	fw, ok := db.TrieDB().Backend().(*firewood.Database)
-	if !ok {
+// wrapBackend wraps [db] if its trie database backend requires the tries to
+// be opened differently.
+func wrapBackend(db state.Database) state.Database {
+	switch backend := db.TrieDB().Backend().(type) {
+	case *firewood.Database:
+		return &firewoodAccessorDb{
+			Database: db,
+			fw:       backend,
+		}
+	case *pathdb.Database:
+		if !backend.HistoryIndexed() {
+			return db
+		}
+		return &pathHistoryAccessorDb{
+			Database: db,
+			pathdb:   backend,
+		}
+	default:
 		return db
 	}
-	return &firewoodAccessorDb{
-		Database: db,
-		fw:       fw,
-	}
 }
@@ -0,0 +1,97 @@
+// Copyright (C) 2019-2025, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package extstate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/MetalBlockchain/libevm/common"
+	"github.com/MetalBlockchain/libevm/core/state"
+	"github.com/MetalBlockchain/libevm/core/types"
+	"github.com/MetalBlockchain/libevm/rlp"
+	"github.com/MetalBlockchain/libevm/trie"
+
+	"github.com/MetalBlockchain/coreth/plugin/evm/customtypes"
+)
+
+var (
+	ErrMissingPreimage     = errors.New("missing preimage of storage key")
+	ErrTooManyStorageSlots = errors.New("too many storage slots to enumerate multicoin balances")
+)
+
+// NormalizeCoinID returns the asset ID under which the multicoin balance of
+// [coinID] is stored. Asset IDs that only differ in the lowest bit of their
+// first byte share their balance.
+func NormalizeCoinID(coinID common.Hash) common.Hash {
+	normalizeCoinID(&coinID)
+	return coinID
+}
+
+// MultiCoinBalances returns the non-zero multicoin balances of [addr] in the
+// state with [root], keyed by normalized asset ID (see [NormalizeCoinID]).
+//
+// The storage keys are hashed in the trie, so the balances can only be
+// enumerated if the preimages of the keys were recorded when they were
+// written. Otherwise [ErrMissingPreimage] is returned. Callers should check that
+// preimages are recorded before calling it, as the walk only fails when reaching
+// a key without a preimage.
+//
+// Every storage slot of [addr] is visited, so the walk is aborted with
+// [ErrTooManyStorageSlots] after [maxSlots] slots, or with the error of [ctx]
+// once it is done.
+func MultiCoinBalances(ctx context.Context, db state.Database, root common.Hash, addr common.Address, maxSlots int) (map[common.Hash]*big.Int, error) {
+	tr, err := db.OpenTrie(root)
+	if err != nil {
+		return nil, err
+	}
+	account, err := tr.GetAccount(addr)
+	if err != nil {
+		return nil, err
+	}
+	if account == nil || !customtypes.IsMultiCoin(account) || account.Root == types.EmptyRootHash {
+		return nil, nil
+	}
+	storageTrie, err := db.OpenStorageTrie(root, addr, account.Root, tr)
+	if err != nil {
+		return nil, err
+	}
+	nodeIt, err := storageTrie.NodeIterator(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		balances = make(map[common.Hash]*big.Int)
+		slots    int
+		it       = trie.NewIterator(nodeIt)
+	)
+	for it.Next() {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		if slots++; slots > maxSlots {
+			return nil, fmt.Errorf("%w: more than %d slots", ErrTooManyStorageSlots, maxSlots)
+		}
+		preimage := storageTrie.GetKey(it.Key)
+		if preimage == nil {
+			return nil, fmt.Errorf("%w: %x", ErrMissingPreimage, it.Key)
+		}
+		key := common.BytesToHash(preimage)
+		// Keys of regular storage slots are normalized to have this bit unset.
+		if key[0]&0x01 == 0 {
+			continue
+		}
+		_, content, _, err := rlp.Split(it.Value)
+		if err != nil {
+			return nil, err
+		}
+		if balance := new(big.Int).SetBytes(content); balance.Sign() != 0 {
+			balances[key] = balance
+		}
+	}
+	return balances, it.Err
+}
@@ -0,0 +1,60 @@
+// Copyright (C) 2019-2025, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package extstate
+
+import (
+	"github.com/MetalBlockchain/libevm/common"
+	"github.com/MetalBlockchain/libevm/core/state"
+
+	"github.com/MetalBlockchain/coreth/triedb/pathdb"
+)
+
+var (
+	_ state.Database = (*pathHistoryAccessorDb)(nil)
+	_ state.Trie     = (*pathdb.HistoryTrie)(nil)
+	_ state.Trie     = (*pathdb.HistoryStorageTrie)(nil)
+)
+
+// pathHistoryAccessorDb opens the states that are no longer held by the path
+// database from its indexed state histories.
+type pathHistoryAccessorDb struct {
+	state.Database
+	pathdb *pathdb.Database
+}
+
+// OpenTrie opens the main account trie, falling back to the state histories
+// if the state is not held by the path database.
+func (db *pathHistoryAccessorDb) OpenTrie(root common.Hash) (state.Trie, error) {
+	tr, err := db.Database.OpenTrie(root)
+	if err == nil {
+		return tr, nil
+	}
+	historyTrie, historyErr := pathdb.NewHistoryTrie(root, db.pathdb)
+	if historyErr != nil {
+		return nil, err
+	}
+	return historyTrie, nil
+}
+
+// OpenStorageTrie opens a wrapped version of the account trie if the state is
+// read from the state histories.
+func (db *pathHistoryAccessorDb) OpenStorageTrie(stateRoot common.Hash, address common.Address, root common.Hash, self state.Trie) (state.Trie, error) {
+	if accountTrie, ok := self.(*pathdb.HistoryTrie); ok {
+		return pathdb.NewHistoryStorageTrie(accountTrie, root), nil
+	}
+	return db.Database.OpenStorageTrie(stateRoot, address, root, self)
+}
+
+// CopyTrie returns a deep copy of the given trie.
+// It can be altered by the caller.
+func (db *pathHistoryAccessorDb) CopyTrie(t state.Trie) state.Trie {
+	switch t := t.(type) {
+	case *pathdb.HistoryTrie:
+		return t.Copy()
+	case *pathdb.HistoryStorageTrie:
+		return nil // The storage trie just wraps the account trie, so we must re-open it separately.
+	default:
+		return db.Database.CopyTrie(t)
+	}
+}
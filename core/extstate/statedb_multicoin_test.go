@@ -4,6 +4,7 @@
 package extstate
 
 import (
+	"context"
 	"math/big"
 	"testing"
 
@@ -13,6 +14,7 @@ import (
 	"github.com/MetalBlockchain/libevm/core/types"
 	"github.com/MetalBlockchain/libevm/crypto"
 	"github.com/MetalBlockchain/libevm/libevm/stateconf"
+	"github.com/MetalBlockchain/libevm/triedb"
 	"github.com/holiman/uint256"
 	"github.com/stretchr/testify/require"
 
@@ -173,3 +175,53 @@ func TestGenerateMultiCoinAccounts(t *testing.T) {
 	actualAssetBalance := new(big.Int).SetBytes(storageBytes)
 	require.Equal(t, assetBalance, actualAssetBalance, "incorrect asset balance")
 }
+
+func TestMultiCoinBalances(t *testing.T) {
+	require := require.New(t)
+
+	addr := common.Address{1}
+	assetID1 := common.Hash{1}
+	assetID2 := common.Hash{2}
+	commit := func(db state.Database) common.Hash {
+		statedb, err := state.New(types.EmptyRootHash, db, nil)
+		require.NoError(err)
+
+		wrappedStateDB := New(statedb)
+		wrappedStateDB.AddBalanceMultiCoin(addr, assetID1, big.NewInt(10))
+		wrappedStateDB.AddBalanceMultiCoin(addr, assetID2, big.NewInt(20))
+		wrappedStateDB.SetState(addr, common.Hash{3}, common.Hash{4})
+		wrappedStateDB.AddBalanceMultiCoin(common.Address{2}, assetID1, big.NewInt(30))
+		root, err := wrappedStateDB.Commit(0, false)
+		require.NoError(err)
+		return root
+	}
+
+	db := NewDatabaseWithConfig(rawdb.NewMemoryDatabase(), &triedb.Config{Preimages: true})
+	root := commit(db)
+	ctx := context.Background()
+	balances, err := MultiCoinBalances(ctx, db, root, addr, 3)
+	require.NoError(err)
+	require.Equal(map[common.Hash]*big.Int{
+		NormalizeCoinID(assetID1): big.NewInt(10),
+		NormalizeCoinID(assetID2): big.NewInt(20),
+	}, balances)
+
+	balances, err = MultiCoinBalances(ctx, db, root, common.Address{3}, 3)
+	require.NoError(err)
+	require.Empty(balances)
+
+	// The walk is bounded by the number of storage slots, including the slots
+	// which aren't balances, and by the context.
+	_, err = MultiCoinBalances(ctx, db, root, addr, 2)
+	require.ErrorIs(err, ErrTooManyStorageSlots)
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	_, err = MultiCoinBalances(canceled, db, root, addr, 3)
+	require.ErrorIs(err, context.Canceled)
+
+	// The asset IDs can't be enumerated without the preimages of the keys.
+	db = state.NewDatabase(rawdb.NewMemoryDatabase())
+	root = commit(db)
+	_, err = MultiCoinBalances(ctx, db, root, addr, 3)
+	require.ErrorIs(err, ErrMissingPreimage)
+}
@@ -0,0 +1,367 @@
+// Copyright (C) 2019-2025, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package core
+
+import (
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/MetalBlockchain/libevm/common"
+	"github.com/MetalBlockchain/libevm/core/rawdb"
+	"github.com/MetalBlockchain/libevm/core/types"
+	"github.com/MetalBlockchain/libevm/ethdb"
+	"github.com/MetalBlockchain/libevm/log"
+	"github.com/MetalBlockchain/libevm/metrics"
+
+	"github.com/MetalBlockchain/coreth/plugin/evm/customrawdb"
+)
+
+// logIndexBatchBlocks is the number of blocks indexed before checking for
+// newly accepted blocks and shutdown.
+const logIndexBatchBlocks = 1024
+
+var (
+	logIndexTailGauge     = metrics.GetOrRegisterGauge("chain/logs/index/tail", nil)
+	logIndexHeadGauge     = metrics.GetOrRegisterGauge("chain/logs/index/head", nil)
+	logIndexCoverageGauge = metrics.GetOrRegisterGaugeFloat64("chain/logs/index/coverage", nil)
+	logIndexTimer         = metrics.GetOrRegisterTimer("chain/logs/index", nil)
+)
+
+// logIndexer maintains an index of the logs of accepted blocks by address, and
+// by address and topic, so that log filters over wide block ranges only read
+// the blocks containing matching logs.
+//
+// The indexed blocks are always a contiguous range. Accepted blocks are
+// indexed as they are processed by the acceptor, and the blocks below the
+// range are backfilled in the background until genesis, or until a block
+// whose receipts are not available (e.g. blocks skipped by state sync). If
+// the index is limited, the blocks older than the limit are unindexed.
+type logIndexer struct {
+	// limit is the maximum number of blocks from head whose logs are indexed:
+	//  * 0: means the entire chain should be indexed
+	//  * N: means the latest N blocks [HEAD-N+1, HEAD] should be indexed
+	limit   uint64
+	db      ethdb.Database
+	head    atomic.Uint64                             // Number of the last accepted block
+	indexed atomic.Pointer[customrawdb.LogIndexRange] // Range of indexed blocks, nil if none
+
+	// backfilled is set once no more blocks can be indexed below the range.
+	// It is only accessed by the running indexing task.
+	backfilled bool
+
+	term   chan chan struct{}
+	closed chan struct{}
+	chain  *BlockChain
+}
+
+// newLogIndexer initializes the log indexer, resuming from the range indexed
+// by a previous run.
+func newLogIndexer(limit uint64, chain *BlockChain) *logIndexer {
+	indexer := &logIndexer{
+		limit:  limit,
+		db:     chain.db,
+		term:   make(chan chan struct{}),
+		closed: make(chan struct{}),
+		chain:  chain,
+	}
+	head := chain.LastAcceptedBlock().NumberU64()
+	indexer.head.Store(head)
+
+	indexed, err := customrawdb.ReadLogIndexRange(chain.db)
+	if err != nil {
+		log.Error("Failed to read log index range, rebuilding it", "err", err)
+		indexed = nil
+	}
+	if indexed != nil && indexed.Head > head {
+		log.Warn("Log index is ahead of the last accepted block, rebuilding it", "indexed", indexed.Head, "accepted", head)
+		indexed = nil
+	}
+	indexer.indexed.Store(indexed)
+	indexer.updateGauges()
+
+	chain.wg.Add(1)
+	go func() {
+		defer chain.wg.Done()
+		indexer.loop(chain)
+	}()
+
+	if indexed == nil {
+		log.Info("Initialized log indexer", "accepted", head, "limit", limit)
+	} else {
+		log.Info("Initialized log indexer", "accepted", head, "limit", limit, "tail", indexed.Tail, "head", indexed.Head)
+	}
+	return indexer
+}
+
+// loop is the scheduler of the indexer, running an indexing task whenever
+// blocks are accepted.
+func (indexer *logIndexer) loop(chain *BlockChain) {
+	defer close(indexer.closed)
+
+	var (
+		stop chan struct{} // Non-nil if background routine is active.
+		done chan struct{} // Non-nil if background routine is active.
+
+		headCh = make(chan ChainEvent)
+		sub    = chain.SubscribeChainAcceptedEvent(headCh)
+	)
+	if sub == nil {
+		log.Warn("could not create chain accepted subscription to index logs")
+		return
+	}
+	defer sub.Unsubscribe()
+
+	launch := func() {
+		stop = make(chan struct{})
+		done = make(chan struct{})
+		go indexer.run(stop, done)
+	}
+	launch()
+
+	for {
+		select {
+		case head := <-headCh:
+			indexer.head.Store(head.Block.NumberU64())
+			if done == nil {
+				launch()
+			}
+		case <-done:
+			stop = nil
+			done = nil
+			// Blocks accepted as the task completed are indexed by a new task.
+			if indexed := indexer.indexed.Load(); indexed != nil && indexed.Head < indexer.head.Load() {
+				launch()
+			}
+		case ch := <-indexer.term:
+			if stop != nil {
+				close(stop)
+			}
+			if done != nil {
+				log.Info("Waiting background log indexer to exit")
+				<-done
+			}
+			close(ch)
+			return
+		}
+	}
+}
+
+// run indexes the accepted blocks above the indexed range, then unindexes the
+// blocks older than the limit or backfills the blocks below the range, until
+// there is nothing left to do or [stop] is closed. Accepted blocks are indexed
+// first, so that the head of the index follows the chain during backfilling.
+func (indexer *logIndexer) run(stop chan struct{}, done chan struct{}) {
+	defer close(done)
+
+	for {
+		select {
+		case <-stop:
+			return
+		default:
+		}
+
+		var (
+			start   = time.Now()
+			head    = indexer.head.Load()
+			indexed = indexer.indexed.Load()
+			err     error
+		)
+		switch {
+		case indexed == nil || indexed.Head < head:
+			err = indexer.indexAccepted(indexed, head)
+		case indexed.Tail < indexer.lowest(head):
+			err = indexer.unindex(indexed, indexer.lowest(head))
+		case !indexer.backfilled && indexed.Tail > indexer.lowest(head):
+			err = indexer.backfill(indexed, indexer.lowest(head))
+		default:
+			return
+		}
+		logIndexTimer.UpdateSince(start)
+		if err != nil {
+			log.Error("Failed to index logs", "err", err)
+			return
+		}
+	}
+}
+
+// indexAccepted indexes a batch of the accepted blocks above [indexed], up to
+// [head], ending early if the batch grew too large. If no block is indexed, or a block above the range is unavailable,
+// the index restarts from [head] and the blocks below it are backfilled.
+func (indexer *logIndexer) indexAccepted(indexed *customrawdb.LogIndexRange, head uint64) error {
+	from := head
+	if indexed != nil {
+		from = indexed.Head + 1
+	}
+	to := min(head, from+logIndexBatchBlocks-1)
+
+	batch := indexer.db.NewBatch()
+	for number := from; number <= to; number++ {
+		logs, ok := indexer.readLogs(number)
+		if !ok && indexed == nil {
+			return fmt.Errorf("receipts of accepted block %d not found", number)
+		}
+		if !ok {
+			log.Warn("Restarting log index from the last accepted block", "missing", number, "accepted", head)
+			indexer.backfilled = false
+			return indexer.indexAccepted(nil, head)
+		}
+		full, err := indexer.writeEntries(batch, number, logs)
+		if err != nil {
+			return err
+		}
+		if full {
+			to = number
+			break
+		}
+	}
+	next := &customrawdb.LogIndexRange{Tail: from, Head: to}
+	if indexed != nil {
+		next.Tail = indexed.Tail
+	}
+	return indexer.commit(batch, next)
+}
+
+// backfill indexes a batch of the blocks below [indexed], down to [lowest],
+// ending early if the batch grew too large.
+func (indexer *logIndexer) backfill(indexed *customrawdb.LogIndexRange, lowest uint64) error {
+	var (
+		batch = indexer.db.NewBatch()
+		tail  = indexed.Tail
+		until = lowest // Lowest block of the batch
+	)
+	if tail > lowest+logIndexBatchBlocks {
+		until = tail - logIndexBatchBlocks
+	}
+	for tail > until {
+		logs, ok := indexer.readLogs(tail - 1)
+		if !ok {
+			log.Info("Stopped backfilling log index at unavailable block", "number", tail-1, "tail", tail)
+			indexer.backfilled = true
+			break
+		}
+		full, err := indexer.writeEntries(batch, tail-1, logs)
+		if err != nil {
+			return err
+		}
+		tail--
+		if full {
+			break
+		}
+	}
+	if tail == 0 {
+		log.Info("Finished backfilling log index", "head", indexed.Head)
+		indexer.backfilled = true
+	}
+	return indexer.commit(batch, &customrawdb.LogIndexRange{Tail: tail, Head: indexed.Head})
+}
+
+// unindex removes a batch of the blocks of [indexed] below [lowest].
+func (indexer *logIndexer) unindex(indexed *customrawdb.LogIndexRange, lowest uint64) error {
+	var (
+		batch = indexer.db.NewBatch()
+		tail  = indexed.Tail
+		until = min(lowest, tail+logIndexBatchBlocks)
+	)
+	for ; tail < until; tail++ {
+		// The receipts of the block are needed to find its entries, so the
+		// receipts must be retained for longer than the logs are indexed.
+		logs, ok := indexer.readLogs(tail)
+		if !ok {
+			continue
+		}
+		if err := customrawdb.DeleteLogIndexEntries(batch, tail, logs); err != nil {
+			return err
+		}
+	}
+	return indexer.commit(batch, &customrawdb.LogIndexRange{Tail: tail, Head: indexed.Head})
+}
+
+// lowest returns the number of the lowest block to index when [head] is the
+// last accepted block.
+func (indexer *logIndexer) lowest(head uint64) uint64 {
+	if indexer.limit == 0 || head < indexer.limit {
+		return 0
+	}
+	return head - indexer.limit + 1
+}
+
+// readLogs returns the logs of the accepted block with [number], and whether
+// its receipts are available.
+func (indexer *logIndexer) readLogs(number uint64) ([]*types.Log, bool) {
+	hash := rawdb.ReadCanonicalHash(indexer.db, number)
+	if hash == (common.Hash{}) {
+		return nil, false
+	}
+	receipts := rawdb.ReadRawReceipts(indexer.db, hash, number)
+	if receipts == nil {
+		return nil, false
+	}
+	var logs []*types.Log
+	for _, receipt := range receipts {
+		logs = append(logs, receipt.Logs...)
+	}
+	return logs, true
+}
+
+// writeEntries adds the index entries of the block with [number] to [batch],
+// and returns whether the batch grew large enough to be committed. Entries are
+// only written along with the range covering them, so that an interrupted
+// batch cannot leave entries outside of the recorded range.
+func (*logIndexer) writeEntries(batch ethdb.Batch, number uint64, logs []*types.Log) (bool, error) {
+	if err := customrawdb.WriteLogIndexEntries(batch, number, logs); err != nil {
+		return false, err
+	}
+	return batch.ValueSize() >= ethdb.IdealBatchSize, nil
+}
+
+// commit writes [batch] along with the [indexed] range.
+func (indexer *logIndexer) commit(batch ethdb.Batch, indexed *customrawdb.LogIndexRange) error {
+	if err := customrawdb.WriteLogIndexRange(batch, indexed); err != nil {
+		return err
+	}
+	if err := batch.Write(); err != nil {
+		return fmt.Errorf("failed to write log index entries: %w", err)
+	}
+	indexer.indexed.Store(indexed)
+	indexer.updateGauges()
+	return nil
+}
+
+// updateGauges reports the indexed range, and the fraction of the accepted
+// blocks it covers.
+func (indexer *logIndexer) updateGauges() {
+	indexed := indexer.indexed.Load()
+	if indexed == nil {
+		logIndexCoverageGauge.Update(0)
+		return
+	}
+	head := indexer.head.Load()
+	logIndexTailGauge.Update(int64(indexed.Tail))
+	logIndexHeadGauge.Update(int64(indexed.Head))
+	logIndexCoverageGauge.Update(float64(indexed.Head-indexed.Tail+1) / float64(head-indexer.lowest(head)+1))
+}
+
+// close shutdown the indexer. Safe to be called for multiple times.
+func (indexer *logIndexer) close() {
+	ch := make(chan struct{})
+	select {
+	case indexer.term <- ch:
+		<-ch
+	case <-indexer.closed:
+	}
+}
+
+// LogIndexRange returns the range of accepted blocks whose logs are indexed,
+// or false if the log index is disabled or empty.
+func (bc *BlockChain) LogIndexRange() (uint64, uint64, bool) {
+	if bc.logIndexer == nil {
+		return 0, 0, false
+	}
+	indexed := bc.logIndexer.indexed.Load()
+	if indexed == nil {
+		return 0, 0, false
+	}
+	return indexed.Tail, indexed.Head, true
+}
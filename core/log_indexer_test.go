@@ -0,0 +1,113 @@
+// Copyright (C) 2019-2025, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package core
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/MetalBlockchain/libevm/common"
+	"github.com/MetalBlockchain/libevm/core/rawdb"
+	"github.com/MetalBlockchain/libevm/core/types"
+	"github.com/MetalBlockchain/libevm/core/vm"
+	"github.com/MetalBlockchain/libevm/crypto"
+	"github.com/stretchr/testify/require"
+
+	"github.com/MetalBlockchain/coreth/consensus/dummy"
+	"github.com/MetalBlockchain/coreth/params"
+	"github.com/MetalBlockchain/coreth/plugin/evm/customrawdb"
+	"github.com/MetalBlockchain/coreth/plugin/evm/upgrade/ap3"
+)
+
+// TestLogIndexer tests that the log index is backfilled when enabled on an
+// existing chain, and follows the accepted blocks.
+func TestLogIndexer(t *testing.T) {
+	const numBlocks = 20
+	var (
+		require = require.New(t)
+		engine  = dummy.NewCoinbaseFaker()
+		key1, _ = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+		addr1   = crypto.PubkeyToAddress(key1.PublicKey)
+		// The contract emits a log with the block number as topic when called:
+		// NUMBER PUSH1 0 PUSH1 0 LOG1 STOP
+		contract = common.Address{0x03}
+		funds    = new(big.Int).Mul(big.NewInt(100), big.NewInt(params.Ether))
+		gspec    = &Genesis{
+			Config: params.TestChainConfig,
+			Alloc: types.GenesisAlloc{
+				addr1:    {Balance: funds},
+				contract: {Code: []byte{byte(vm.NUMBER), byte(vm.PUSH1), 0, byte(vm.PUSH1), 0, byte(vm.LOG1), byte(vm.STOP)}},
+			},
+			BaseFee: big.NewInt(ap3.InitialBaseFee),
+		}
+		signer = types.LatestSigner(gspec.Config)
+		nonce  uint64
+	)
+	// The contract is called in odd blocks.
+	_, blocks, _, err := GenerateChainWithGenesis(gspec, engine, numBlocks, 10, func(i int, b *BlockGen) {
+		if i%2 != 0 {
+			return
+		}
+		tx, err := types.SignTx(types.NewTransaction(nonce, contract, common.Big0, 30_000, b.BaseFee(), nil), signer, key1)
+		require.NoError(err)
+		b.AddTx(tx)
+		nonce++
+	})
+	require.NoError(err)
+
+	var (
+		db          = rawdb.NewMemoryDatabase()
+		cacheConfig = *DefaultCacheConfig
+	)
+	insertAndAccept := func(chain *BlockChain, blocks []*types.Block) {
+		_, err := chain.InsertChain(blocks)
+		require.NoError(err)
+		for _, block := range blocks {
+			require.NoError(chain.Accept(block))
+		}
+		chain.DrainAcceptorQueue()
+	}
+	waitIndexed := func(chain *BlockChain, head uint64) {
+		require.Eventually(func() bool {
+			tail, indexedHead, ok := chain.LogIndexRange()
+			return ok && tail == 0 && indexedHead == head
+		}, 5*time.Second, 10*time.Millisecond)
+	}
+
+	// Accept the first half of the chain without indexing logs.
+	chain, err := NewBlockChain(db, &cacheConfig, gspec, engine, vm.Config{}, common.Hash{}, false)
+	require.NoError(err)
+	insertAndAccept(chain, blocks[:numBlocks/2])
+	_, _, ok := chain.LogIndexRange()
+	require.False(ok)
+	chain.Stop()
+
+	// The blocks accepted before the index was enabled are backfilled.
+	cacheConfig.LogIndex = true
+	chain, err = NewBlockChain(db, &cacheConfig, gspec, engine, vm.Config{}, blocks[numBlocks/2-1].Hash(), false)
+	require.NoError(err)
+	defer chain.Stop()
+	waitIndexed(chain, numBlocks/2)
+
+	// Accepted blocks are indexed.
+	insertAndAccept(chain, blocks[numBlocks/2:])
+	waitIndexed(chain, numBlocks)
+
+	var expected []uint64
+	for number := uint64(1); number <= numBlocks; number += 2 {
+		expected = append(expected, number)
+	}
+	numbers, err := customrawdb.ReadLogIndexAddressBlocks(db, contract, 0, numBlocks)
+	require.NoError(err)
+	require.Equal(expected, numbers)
+
+	numbers, err = customrawdb.ReadLogIndexTopicBlocks(db, contract, 0, common.BigToHash(big.NewInt(15)), 0, numBlocks)
+	require.NoError(err)
+	require.Equal([]uint64{15}, numbers)
+
+	numbers, err = customrawdb.ReadLogIndexAddressBlocks(db, addr1, 0, numBlocks)
+	require.NoError(err)
+	require.Empty(numbers)
+}
@@ -87,10 +87,13 @@ func (p *StateProcessor) Process(block *types.Block, parent *types.Header, state
 		return nil, nil, 0, err
 	}
 
+	context, err := NewEVMBlockContext(header, p.bc, nil)
+	if err != nil {
+		return nil, nil, 0, err
+	}
 	var (
-		context = NewEVMBlockContext(header, p.bc, nil)
-		vmenv   = vm.NewEVM(context, vm.TxContext{}, statedb, p.config, cfg)
-		signer  = types.MakeSigner(p.config, header.Number, header.Time)
+		vmenv  = vm.NewEVM(context, vm.TxContext{}, statedb, p.config, cfg)
+		signer = types.MakeSigner(p.config, header.Number, header.Time)
 	)
 
 	if beaconRoot := block.BeaconRoot(); beaconRoot != nil {
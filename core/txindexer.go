@@ -28,14 +28,22 @@
 package core
 
 import (
+	"errors"
 	"fmt"
+	"sync/atomic"
 	"time"
 
+	"github.com/MetalBlockchain/libevm/common"
 	"github.com/MetalBlockchain/libevm/core/rawdb"
+	"github.com/MetalBlockchain/libevm/core/types"
 	"github.com/MetalBlockchain/libevm/ethdb"
 	"github.com/MetalBlockchain/libevm/log"
 )
 
+// txReindexBatchBlocks is the number of blocks reindexed before the index tail
+// is updated and shutdown is checked.
+const txReindexBatchBlocks = 1024
+
 // TxIndexProgress is the struct describing the progress for transaction indexing.
 type TxIndexProgress struct {
 	Indexed   uint64 // number of blocks whose transactions are indexed
@@ -55,7 +63,13 @@ type txIndexer struct {
 	//  * 0: means the entire chain should be indexed
 	//  * N: means the latest N blocks [HEAD-N+1, HEAD] should be indexed
 	//       and all others shouldn't.
-	limit    uint64
+	limit uint64
+	// reindex is whether the transactions of the blocks added to the range,
+	// e.g. when the limit is increased, are indexed again.
+	reindex bool
+	// floor is the lowest block whose transactions can be indexed, as the
+	// bodies of older blocks are not available.
+	floor    atomic.Uint64
 	db       ethdb.Database
 	progress chan chan TxIndexProgress
 	term     chan chan struct{}
@@ -64,10 +78,13 @@ type txIndexer struct {
 	chain *BlockChain
 }
 
-// newTxIndexer initializes the transaction indexer.
-func newTxIndexer(limit uint64, chain *BlockChain) *txIndexer {
+// newTxIndexer initializes the transaction indexer. If [reindex] is set, the
+// transactions of the blocks below the index tail and within the range are
+// indexed again.
+func newTxIndexer(limit uint64, reindex bool, chain *BlockChain) *txIndexer {
 	indexer := &txIndexer{
 		limit:    limit,
+		reindex:  reindex,
 		db:       chain.db,
 		progress: make(chan chan TxIndexProgress),
 		term:     make(chan chan struct{}),
@@ -113,10 +130,81 @@ func (indexer *txIndexer) run(tail *uint64, head uint64, stop chan struct{}, don
 		tailValue = *tail
 	}
 
-	if head-indexer.limit+1 >= tailValue {
+	// The transactions of the blocks in [from, HEAD] should be indexed.
+	from := uint64(0)
+	if indexer.limit != 0 && head >= indexer.limit {
+		from = head - indexer.limit + 1
+	}
+	switch {
+	case from > tailValue:
 		// Unindex a part of stale indices and forward index tail to HEAD-limit
-		rawdb.UnindexTransactions(indexer.db, tailValue, head-indexer.limit+1, stop, false)
+		rawdb.UnindexTransactions(indexer.db, tailValue, from, stop, false)
+	case max(from, indexer.floor.Load()) < tailValue && indexer.reindex:
+		// Index the blocks added to the range and move the index tail backward
+		indexer.reindexTransactions(max(from, indexer.floor.Load()), tailValue, stop)
+	}
+}
+
+// reindexTransactions indexes the transactions of the blocks in [from, to)
+// from the highest one, moving the index tail backward as blocks are indexed.
+// Indexing stops at the first block whose body is not available, e.g. blocks
+// below the last state sync or whose body was pruned.
+func (indexer *txIndexer) reindexTransactions(from uint64, to uint64, stop chan struct{}) {
+	start := time.Now()
+	defer func() {
+		txIndexTimer.Inc(time.Since(start).Milliseconds())
+	}()
+
+	var (
+		tail  = to
+		batch = indexer.db.NewBatch()
+	)
+	for tail > from {
+		hash := rawdb.ReadCanonicalHash(indexer.db, tail-1)
+		var body *types.Body
+		if hash != (common.Hash{}) {
+			body = rawdb.ReadBody(indexer.db, hash, tail-1)
+		}
+		if body == nil {
+			log.Info("Stopped reindexing transactions at unavailable block", "number", tail-1, "tail", tail)
+			indexer.floor.Store(tail)
+			break
+		}
+		hashes := make([]common.Hash, len(body.Transactions))
+		for i, tx := range body.Transactions {
+			hashes[i] = tx.Hash()
+		}
+		rawdb.WriteTxLookupEntries(batch, tail-1, hashes)
+		tail--
+
+		if (to-tail)%txReindexBatchBlocks == 0 {
+			if !indexer.writeTail(batch, tail) {
+				return
+			}
+			select {
+			case <-stop:
+				log.Info("Transaction reindexing interrupted", "tail", tail, "target", from)
+				return
+			default:
+			}
+		}
+	}
+	if tail == to || !indexer.writeTail(batch, tail) {
+		return
 	}
+	log.Info("Reindexed transactions", "blocks", to-tail, "tail", tail, "elapsed", common.PrettyDuration(time.Since(start)))
+}
+
+// writeTail writes [batch] along with the index [tail], returning whether
+// it succeeded.
+func (indexer *txIndexer) writeTail(batch ethdb.Batch, tail uint64) bool {
+	rawdb.WriteTxIndexTail(batch, tail)
+	if err := batch.Write(); err != nil {
+		log.Error("Failed to write transaction indices", "err", err)
+		return false
+	}
+	batch.Reset()
+	return true
 }
 
 // loop is the scheduler of the indexer, assigning indexing/unindexing tasks depending
@@ -141,11 +229,13 @@ func (indexer *txIndexer) loop(chain *BlockChain) {
 	log.Info("Initialized transaction unindexer", "limit", indexer.limit)
 
 	// Launch the initial processing if chain is not empty (head != genesis).
-	// This step is useful in these scenarios that chain has no progress.
-	if head := indexer.chain.CurrentBlock(); head != nil && head.Number.Uint64() > indexer.limit {
+	// This step is useful in these scenarios that chain has no progress. It
+	// runs even if the chain is shorter than the limit, as the blocks below
+	// the index tail may have to be indexed again.
+	if head := indexer.chain.CurrentBlock(); head != nil {
+		lastHead = head.Number.Uint64()
 		stop = make(chan struct{})
 		done = make(chan struct{})
-		lastHead = head.Number.Uint64()
 		indexer.chain.wg.Add(1)
 		go func() {
 			indexer.lockedRun(head.Number.Uint64(), stop, done)
@@ -155,10 +245,7 @@ func (indexer *txIndexer) loop(chain *BlockChain) {
 		select {
 		case head := <-headCh:
 			headNum := head.Block.NumberU64()
-			if headNum < indexer.limit {
-				break
-			}
-
+			lastHead = headNum
 			if done == nil {
 				stop = make(chan struct{})
 				done = make(chan struct{})
@@ -167,7 +254,6 @@ func (indexer *txIndexer) loop(chain *BlockChain) {
 					indexer.lockedRun(headNum, stop, done)
 				}()
 			}
-			lastHead = head.Block.NumberU64()
 		case <-done:
 			stop = nil
 			done = nil
@@ -193,9 +279,15 @@ func (indexer *txIndexer) report(head uint64, tail *uint64) TxIndexProgress {
 	if indexer.limit == 0 || total > head {
 		total = head + 1 // genesis included
 	}
-	var indexed uint64
+	// The blocks below the floor cannot be indexed.
+	if floor := indexer.floor.Load(); floor > head+1-total {
+		total = head + 1 - min(floor, head+1)
+	}
+	// The transactions of accepted blocks are indexed as they are accepted,
+	// so every block is indexed if the tail was never moved.
+	indexed := head + 1
 	if tail != nil {
-		indexed = head - *tail + 1
+		indexed = head + 1 - min(*tail, head+1)
 	}
 	// The value of indexed might be larger than total if some blocks need
 	// to be unindexed, avoiding a negative remaining.
@@ -209,6 +301,18 @@ func (indexer *txIndexer) report(head uint64, tail *uint64) TxIndexProgress {
 	}
 }
 
+// txIndexProgress retrieves the tx indexing progress, or an error if the
+// background tx indexer is already stopped.
+func (indexer *txIndexer) txIndexProgress() (TxIndexProgress, error) {
+	ch := make(chan TxIndexProgress, 1)
+	select {
+	case indexer.progress <- ch:
+		return <-ch, nil
+	case <-indexer.closed:
+		return TxIndexProgress{}, errors.New("indexer is closed")
+	}
+}
+
 // close shutdown the indexer. Safe to be called for multiple times.
 func (indexer *txIndexer) close() {
 	ch := make(chan struct{})
@@ -224,6 +328,21 @@ func (indexer *txIndexer) close() {
 func (indexer *txIndexer) lockedRun(head uint64, stop chan struct{}, done chan struct{}) {
 	indexer.chain.txIndexTailLock.Lock()
 	indexer.run(rawdb.ReadTxIndexTail(indexer.db), head, stop, done)
+	tail := rawdb.ReadTxIndexTail(indexer.db)
 	indexer.chain.txIndexTailLock.Unlock()
+
+	if tail != nil {
+		txIndexTailGauge.Update(int64(*tail))
+	}
+	txIndexRemainGauge.Update(int64(indexer.report(head, tail).Remaining))
 	indexer.chain.wg.Done()
 }
+
+// TxIndexProgress returns the transaction indexing progress, or an error if
+// the transaction indexer is not running.
+func (bc *BlockChain) TxIndexProgress() (TxIndexProgress, error) {
+	if bc.txIndexer == nil {
+		return TxIndexProgress{}, errors.New("tx indexer is not enabled")
+	}
+	return bc.txIndexer.txIndexProgress()
+}
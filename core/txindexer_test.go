@@ -31,6 +31,7 @@ import (
 	"fmt"
 	"math/big"
 	"testing"
+	"time"
 
 	"github.com/MetalBlockchain/coreth/consensus/dummy"
 	"github.com/MetalBlockchain/coreth/core/coretest"
@@ -257,6 +258,88 @@ func TestTransactionSkipIndexing(t *testing.T) {
 	chain.Stop()
 }
 
+// TestTransactionReindexing tests that the transactions of older blocks are
+// indexed again when the transaction history is increased, down to the first
+// block whose body is not available.
+func TestTransactionReindexing(t *testing.T) {
+	require := require.New(t)
+	var (
+		key1, _ = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+		key2, _ = crypto.HexToECDSA("8a1f9a8f95be41cd7ccb6168179afb4504aefe388d1e14474d32c45c72ce7b7a")
+		addr1   = crypto.PubkeyToAddress(key1.PublicKey)
+		addr2   = crypto.PubkeyToAddress(key2.PublicKey)
+		funds   = big.NewInt(10000000000000)
+		gspec   = &Genesis{
+			Config: &params.ChainConfig{HomesteadBlock: new(big.Int)},
+			Alloc:  types.GenesisAlloc{addr1: {Balance: funds}},
+		}
+		signer = types.LatestSigner(gspec.Config)
+	)
+	_, blocks, _, err := GenerateChainWithGenesis(gspec, dummy.NewFakerWithCallbacks(TestCallbacks), 128, 10, func(i int, block *BlockGen) {
+		tx, err := types.SignTx(types.NewTransaction(block.TxNonce(addr1), addr2, big.NewInt(10000), ethparams.TxGas, nil, nil), signer, key1)
+		require.NoError(err)
+		block.AddTx(tx)
+	})
+	require.NoError(err)
+	head := blocks[len(blocks)-1]
+
+	conf := &CacheConfig{
+		TrieCleanLimit:            256,
+		TrieDirtyLimit:            256,
+		TrieDirtyCommitTarget:     20,
+		TriePrefetcherParallelism: 4,
+		Pruning:                   true,
+		CommitInterval:            4096,
+		StateHistory:              32,
+		SnapshotLimit:             256,
+		SnapshotNoBuild:           true, // Ensure the test errors if snapshot initialization fails
+		AcceptorQueueLimit:        64,
+		TransactionHistory:        32,
+	}
+	chainDB := rawdb.NewMemoryDatabase()
+	chain, err := createAndInsertChain(chainDB, conf, gspec, blocks, common.Hash{}, nil)
+	require.NoError(err)
+	tail := head.NumberU64() - conf.TransactionHistory + 1
+	coretest.CheckTxIndices(t, &tail, tail, head.NumberU64(), head.NumberU64(), chainDB, false)
+	chain.Stop()
+
+	waitIndexed := func(chain *BlockChain) {
+		require.Eventually(func() bool {
+			progress, err := chain.TxIndexProgress()
+			return err == nil && progress.Done()
+		}, 30*time.Second, 10*time.Millisecond)
+	}
+
+	// Increasing the history indexes the blocks added to the range.
+	conf.TransactionHistory = 64
+	chain, err = createBlockChain(chainDB, conf, gspec, head.Hash())
+	require.NoError(err)
+	tail = head.NumberU64() - conf.TransactionHistory + 1
+	coretest.CheckTxIndices(t, &tail, tail, head.NumberU64(), head.NumberU64(), chainDB, false)
+	waitIndexed(chain)
+	chain.Stop()
+
+	// Increasing the history beyond the length of the chain indexes every
+	// block down to the first missing body.
+	missing := blocks[39]
+	rawdb.DeleteBody(chainDB, missing.Hash(), missing.NumberU64())
+	conf.TransactionHistory = 2 * head.NumberU64()
+	chain, err = createBlockChain(chainDB, conf, gspec, head.Hash())
+	require.NoError(err)
+	tail = missing.NumberU64() + 1
+	coretest.CheckTxIndices(t, &tail, tail, head.NumberU64(), head.NumberU64(), chainDB, true)
+	waitIndexed(chain)
+	chain.Stop()
+
+	// Removing the limit doesn't index the blocks below the missing body either.
+	conf.TransactionHistory = 0
+	chain, err = createBlockChain(chainDB, conf, gspec, head.Hash())
+	require.NoError(err)
+	defer chain.Stop()
+	coretest.CheckTxIndices(t, &tail, tail, head.NumberU64(), head.NumberU64(), chainDB, true)
+	waitIndexed(chain)
+}
+
 func createAndInsertChain(db ethdb.Database, cacheConfig *CacheConfig, gspec *Genesis, blocks types.Blocks, lastAcceptedHash common.Hash, accepted func(*types.Block)) (*BlockChain, error) {
 	chain, err := createBlockChain(db, cacheConfig, gspec, lastAcceptedHash)
 	if err != nil {
@@ -84,6 +84,11 @@ func (b *EthAPIBackend) HistoricalProofQueryWindow() uint64 {
 	return b.historicalProofQueryWindow
 }
 
+// PreimagesEnabled returns true if the preimages of trie keys are recorded.
+func (b *EthAPIBackend) PreimagesEnabled() bool {
+	return b.eth.config.Preimages
+}
+
 func (b *EthAPIBackend) IsAllowUnfinalizedQueries() bool {
 	return b.allowUnfinalizedQueries
 }
@@ -100,6 +105,10 @@ func (b *EthAPIBackend) LastAcceptedBlock() *types.Block {
 	return b.eth.LastAcceptedBlock()
 }
 
+func (b *EthAPIBackend) TxIndexProgress() (core.TxIndexProgress, error) {
+	return b.eth.blockchain.TxIndexProgress()
+}
+
 func (b *EthAPIBackend) HeaderByNumber(ctx context.Context, number rpc.BlockNumber) (*types.Header, error) {
 	if err := ctx.Err(); err != nil {
 		return nil, err
@@ -255,7 +264,7 @@ func (b *EthAPIBackend) StateAndHeaderByNumber(ctx context.Context, number rpc.B
 	if header == nil {
 		return nil, nil, errors.New("header not found")
 	}
-	stateDb, err := b.eth.BlockChain().StateAt(header.Root)
+	stateDb, err := b.stateAt(ctx, header)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -277,7 +286,7 @@ func (b *EthAPIBackend) StateAndHeaderByNumberOrHash(ctx context.Context, blockN
 		if header == nil {
 			return nil, nil, errors.New("header for hash not found")
 		}
-		stateDb, err := b.eth.BlockChain().StateAt(header.Root)
+		stateDb, err := b.stateAt(ctx, header)
 		if err != nil {
 			return nil, nil, err
 		}
@@ -286,6 +295,16 @@ func (b *EthAPIBackend) StateAndHeaderByNumberOrHash(ctx context.Context, blockN
 	return nil, nil, errors.New("invalid arguments; neither block nor hash specified")
 }
 
+// stateAt returns the state of [header], reconstructing it if it has been
+// pruned and state reconstruction is enabled.
+func (b *EthAPIBackend) stateAt(ctx context.Context, header *types.Header) (*state.StateDB, error) {
+	stateDb, err := b.eth.BlockChain().StateAt(header.Root)
+	if err == nil || b.eth.stateReconstructor == nil {
+		return stateDb, err
+	}
+	return b.eth.stateReconstructor.stateAt(ctx, header)
+}
+
 func (b *EthAPIBackend) GetReceipts(ctx context.Context, hash common.Hash) (types.Receipts, error) {
 	if err := ctx.Err(); err != nil {
 		return nil, err
@@ -300,7 +319,7 @@ func (b *EthAPIBackend) GetLogs(ctx context.Context, hash common.Hash, number ui
 	return b.eth.blockchain.GetLogs(hash, number), nil
 }
 
-func (b *EthAPIBackend) GetEVM(ctx context.Context, msg *core.Message, state *state.StateDB, header *types.Header, vmConfig *vm.Config, blockCtx *vm.BlockContext) *vm.EVM {
+func (b *EthAPIBackend) GetEVM(ctx context.Context, msg *core.Message, state *state.StateDB, header *types.Header, vmConfig *vm.Config, blockCtx *vm.BlockContext) (*vm.EVM, error) {
 	if vmConfig == nil {
 		vmConfig = b.eth.blockchain.GetVMConfig()
 	}
@@ -309,9 +328,13 @@ func (b *EthAPIBackend) GetEVM(ctx context.Context, msg *core.Message, state *st
 	if blockCtx != nil {
 		context = *blockCtx
 	} else {
-		context = core.NewEVMBlockContext(header, b.eth.BlockChain(), nil)
+		var err error
+		context, err = core.NewEVMBlockContext(header, b.eth.BlockChain(), nil)
+		if err != nil {
+			return nil, err
+		}
 	}
-	return vm.NewEVM(context, txContext, state, b.ChainConfig(), *vmConfig)
+	return vm.NewEVM(context, txContext, state, b.ChainConfig(), *vmConfig), nil
 }
 
 func (b *EthAPIBackend) SubscribeRemovedLogsEvent(ch chan<- core.RemovedLogsEvent) event.Subscription {
@@ -503,6 +526,10 @@ func (b *EthAPIBackend) ServiceFilter(ctx context.Context, session *bloombits.Ma
 	}
 }
 
+func (b *EthAPIBackend) LogIndexRange() (uint64, uint64, bool) {
+	return b.eth.blockchain.LogIndexRange()
+}
+
 func (b *EthAPIBackend) Engine() consensus.Engine {
 	return b.eth.engine
 }
@@ -33,9 +33,11 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/MetalBlockchain/coreth/core/extstate"
 	"github.com/MetalBlockchain/coreth/internal/ethapi"
 	"github.com/MetalBlockchain/coreth/plugin/evm/customrawdb"
 	"github.com/MetalBlockchain/coreth/rpc"
+	"github.com/MetalBlockchain/coreth/triedb/pathdb"
 	"github.com/MetalBlockchain/libevm/common"
 	"github.com/MetalBlockchain/libevm/common/hexutil"
 	"github.com/MetalBlockchain/libevm/core/rawdb"
@@ -60,8 +62,58 @@ func NewDebugAPI(eth *Ethereum) *DebugAPI {
 	return &DebugAPI{eth: eth}
 }
 
+// Dump is a [state.Dump] whose accounts include their multicoin balances.
+type Dump struct {
+	Root     string                 `json:"root"`
+	Accounts map[string]DumpAccount `json:"accounts"`
+	Next     []byte                 `json:"next,omitempty"` // nil if no more accounts
+}
+
+// DumpAccount is a [state.DumpAccount] with the multicoin balances of the
+// account, keyed by normalized asset ID (see [extstate.NormalizeCoinID]).
+type DumpAccount struct {
+	state.DumpAccount
+	MultiCoinBalances map[common.Hash]*hexutil.Big `json:"multiCoinBalances,omitempty"`
+}
+
+// dumpMultiCoinMaxStorageSlots is the maximum number of storage slots of each
+// account walked to enumerate its multicoin balances in a [Dump].
+const dumpMultiCoinMaxStorageSlots = 1024
+
+// dumpMultiCoin adds the multicoin balances of the accounts of [dump], read
+// from the state with [root], to it.
+//
+// Like the storage keys of the dump, the asset IDs are read from preimages, so
+// the balances are omitted if [preimages] is false or if the preimages of an
+// account aren't recorded. They are also omitted for accounts with more than
+// [dumpMultiCoinMaxStorageSlots] storage slots.
+func dumpMultiCoin(ctx context.Context, db state.Database, root common.Hash, dump state.Dump, preimages bool) (Dump, error) {
+	result := Dump{
+		Root:     dump.Root,
+		Accounts: make(map[string]DumpAccount, len(dump.Accounts)),
+		Next:     dump.Next,
+	}
+	for key, account := range dump.Accounts {
+		dumpAccount := DumpAccount{DumpAccount: account}
+		if preimages && account.Address != nil {
+			balances, err := extstate.MultiCoinBalances(ctx, db, root, *account.Address, dumpMultiCoinMaxStorageSlots)
+			if err != nil && !errors.Is(err, extstate.ErrMissingPreimage) && !errors.Is(err, extstate.ErrTooManyStorageSlots) {
+				return Dump{}, err
+			}
+			for assetID, balance := range balances {
+				if dumpAccount.MultiCoinBalances == nil {
+					dumpAccount.MultiCoinBalances = make(map[common.Hash]*hexutil.Big, len(balances))
+				}
+				dumpAccount.MultiCoinBalances[assetID] = (*hexutil.Big)(balance)
+			}
+		}
+		result.Accounts[key] = dumpAccount
+	}
+	return result, nil
+}
+
 // DumpBlock retrieves the entire state of the database at a given block.
-func (api *DebugAPI) DumpBlock(blockNr rpc.BlockNumber) (state.Dump, error) {
+func (api *DebugAPI) DumpBlock(ctx context.Context, blockNr rpc.BlockNumber) (Dump, error) {
 	opts := &state.DumpConfig{
 		OnlyWithAddresses: true,
 		Max:               AccountRangeMaxResults, // Sanity limit over RPC
@@ -76,18 +128,21 @@ func (api *DebugAPI) DumpBlock(blockNr rpc.BlockNumber) (state.Dump, error) {
 	} else {
 		block := api.eth.blockchain.GetBlockByNumber(uint64(blockNr))
 		if block == nil {
-			return state.Dump{}, fmt.Errorf("block #%d not found", blockNr)
+			return Dump{}, fmt.Errorf("block #%d not found", blockNr)
 		}
 		header = block.Header()
 	}
 	if header == nil {
-		return state.Dump{}, fmt.Errorf("block #%d not found", blockNr)
+		return Dump{}, fmt.Errorf("block #%d not found", blockNr)
+	}
+	if err := api.checkTrieNodes(header.Root); err != nil {
+		return Dump{}, err
 	}
 	stateDb, err := api.eth.BlockChain().StateAt(header.Root)
 	if err != nil {
-		return state.Dump{}, err
+		return Dump{}, err
 	}
-	return stateDb.RawDump(opts), nil
+	return dumpMultiCoin(ctx, stateDb.Database(), header.Root, stateDb.RawDump(opts), api.eth.config.Preimages)
 }
 
 // Preimage is a debug API function that returns the preimage for a sha3 hash, if known.
@@ -109,9 +164,12 @@ func (api *DebugAPI) GetBadBlocks(ctx context.Context) ([]*ethapi.BadBlockArgs,
 const AccountRangeMaxResults = 256
 
 // AccountRange enumerates all accounts in the given block and start point in paging request
-func (api *DebugAPI) AccountRange(blockNrOrHash rpc.BlockNumberOrHash, start hexutil.Bytes, maxResults int, nocode, nostorage, incompletes bool) (state.Dump, error) {
-	var stateDb *state.StateDB
-	var err error
+func (api *DebugAPI) AccountRange(ctx context.Context, blockNrOrHash rpc.BlockNumberOrHash, start hexutil.Bytes, maxResults int, nocode, nostorage, incompletes bool) (Dump, error) {
+	var (
+		stateDb *state.StateDB
+		root    common.Hash
+		err     error
+	)
 
 	if number, ok := blockNrOrHash.Number(); ok {
 		var header *types.Header
@@ -124,28 +182,33 @@ func (api *DebugAPI) AccountRange(blockNrOrHash rpc.BlockNumberOrHash, start hex
 		} else {
 			block := api.eth.blockchain.GetBlockByNumber(uint64(number))
 			if block == nil {
-				return state.Dump{}, fmt.Errorf("block #%d not found", number)
+				return Dump{}, fmt.Errorf("block #%d not found", number)
 			}
 			header = block.Header()
 		}
 		if header == nil {
-			return state.Dump{}, fmt.Errorf("block #%d not found", number)
+			return Dump{}, fmt.Errorf("block #%d not found", number)
 		}
-		stateDb, err = api.eth.BlockChain().StateAt(header.Root)
+		root = header.Root
+		stateDb, err = api.eth.BlockChain().StateAt(root)
 		if err != nil {
-			return state.Dump{}, err
+			return Dump{}, err
 		}
 	} else if hash, ok := blockNrOrHash.Hash(); ok {
 		block := api.eth.blockchain.GetBlockByHash(hash)
 		if block == nil {
-			return state.Dump{}, fmt.Errorf("block %s not found", hash.Hex())
+			return Dump{}, fmt.Errorf("block %s not found", hash.Hex())
 		}
-		stateDb, err = api.eth.BlockChain().StateAt(block.Root())
+		root = block.Root()
+		stateDb, err = api.eth.BlockChain().StateAt(root)
 		if err != nil {
-			return state.Dump{}, err
+			return Dump{}, err
 		}
 	} else {
-		return state.Dump{}, errors.New("either block number or block hash must be specified")
+		return Dump{}, errors.New("either block number or block hash must be specified")
+	}
+	if err := api.checkTrieNodes(root); err != nil {
+		return Dump{}, err
 	}
 
 	opts := &state.DumpConfig{
@@ -158,7 +221,7 @@ func (api *DebugAPI) AccountRange(blockNrOrHash rpc.BlockNumberOrHash, start hex
 	if maxResults > AccountRangeMaxResults || maxResults <= 0 {
 		opts.Max = AccountRangeMaxResults
 	}
-	return stateDb.RawDump(opts), nil
+	return dumpMultiCoin(ctx, stateDb.Database(), root, stateDb.RawDump(opts), api.eth.config.Preimages)
 }
 
 // StorageRangeResult is the result of a debug_storageRangeAt API call.
@@ -187,6 +250,9 @@ func (api *DebugAPI) StorageRangeAt(ctx context.Context, blockNrOrHash rpc.Block
 	if block == nil {
 		return StorageRangeResult{}, fmt.Errorf("block %v not found", blockNrOrHash)
 	}
+	if err := api.checkTrieNodes(block.Root()); err != nil {
+		return StorageRangeResult{}, err
+	}
 	_, _, statedb, release, err := api.eth.stateAtTransaction(ctx, block, txIndex, 0)
 	if err != nil {
 		return StorageRangeResult{}, err
@@ -298,6 +364,12 @@ func (api *DebugAPI) getModifiedAccounts(startBlock, endBlock *types.Block) ([]c
 	if startBlock.Number().Uint64() >= endBlock.Number().Uint64() {
 		return nil, fmt.Errorf("start block height (%d) must be less than end block height (%d)", startBlock.Number().Uint64(), endBlock.Number().Uint64())
 	}
+	if err := api.checkTrieNodes(startBlock.Root()); err != nil {
+		return nil, err
+	}
+	if err := api.checkTrieNodes(endBlock.Root()); err != nil {
+		return nil, err
+	}
 	triedb := api.eth.BlockChain().TrieDB()
 
 	oldTrie, err := trie.NewStateTrie(trie.StateTrieID(startBlock.Root()), triedb)
@@ -388,3 +460,12 @@ func (api *DebugAPI) GetAccessibleState(from, to rpc.BlockNumber) (uint64, error
 func (api *DebugAPI) isFirewood() bool {
 	return api.eth.blockchain.CacheConfig().StateScheme == customrawdb.FirewoodScheme
 }
+
+// checkTrieNodes returns an error if the state with [root] is read from the
+// indexed state histories, which do not hold the trie nodes to iterate.
+func (api *DebugAPI) checkTrieNodes(root common.Hash) error {
+	if db, ok := api.eth.blockchain.TrieDB().Backend().(*pathdb.Database); ok && db.IsHistoricalState(root) {
+		return pathdb.ErrHistoricalStateUnsupported
+	}
+	return nil
+}
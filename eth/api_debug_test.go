@@ -30,12 +30,14 @@ package eth
 import (
 	"bytes"
 	"fmt"
+	"math/big"
 	"reflect"
 	"strings"
 	"testing"
 
 	"github.com/MetalBlockchain/coreth/core/extstate"
 	"github.com/MetalBlockchain/libevm/common"
+	"github.com/MetalBlockchain/libevm/common/hexutil"
 	"github.com/MetalBlockchain/libevm/core/rawdb"
 	"github.com/MetalBlockchain/libevm/core/state"
 	"github.com/MetalBlockchain/libevm/core/types"
@@ -167,6 +169,46 @@ func TestEmptyAccountRange(t *testing.T) {
 	}
 }
 
+func TestDumpMultiCoin(t *testing.T) {
+	t.Parallel()
+
+	var (
+		db      = extstate.NewDatabaseWithConfig(rawdb.NewMemoryDatabase(), &triedb.Config{Preimages: true})
+		sdb, _  = state.New(types.EmptyRootHash, db, nil)
+		addr    = common.Address{0x01}
+		other   = common.Address{0x02}
+		assetID = common.Hash{0x03}
+	)
+	extstate.New(sdb).AddBalanceMultiCoin(addr, assetID, big.NewInt(10))
+	sdb.AddBalance(other, uint256.NewInt(1))
+	root, _ := sdb.Commit(0, false)
+	sdb, _ = state.New(root, db, nil)
+
+	dump, err := dumpMultiCoin(context.Background(), db, root, sdb.RawDump(&state.DumpConfig{OnlyWithAddresses: true}), true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(dump.Accounts) != 2 {
+		t.Fatalf("expected 2 accounts, got %d", len(dump.Accounts))
+	}
+	want := map[common.Hash]*hexutil.Big{assetID: (*hexutil.Big)(big.NewInt(10))}
+	if got := dump.Accounts[addr.Hex()].MultiCoinBalances; !reflect.DeepEqual(got, want) {
+		t.Fatalf("multicoin balances mismatch: have %v, want %v", got, want)
+	}
+	if got := dump.Accounts[other.Hex()].MultiCoinBalances; got != nil {
+		t.Fatalf("expected no multicoin balances, got %v", got)
+	}
+
+	// The storage isn't walked without preimages.
+	dump, err = dumpMultiCoin(context.Background(), db, root, sdb.RawDump(&state.DumpConfig{OnlyWithAddresses: true}), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := dump.Accounts[addr.Hex()].MultiCoinBalances; got != nil {
+		t.Fatalf("expected no multicoin balances without preimages, got %v", got)
+	}
+}
+
 func TestStorageRangeAt(t *testing.T) {
 	t.Parallel()
 
@@ -112,6 +112,8 @@ type Ethereum struct {
 
 	shutdownTracker *shutdowncheck.ShutdownTracker // Tracks if and when the node has shutdown ungracefully
 
+	stateReconstructor *stateReconstructor // Regenerates pruned historical states, nil if disabled
+
 	stackRPCs []rpc.API
 
 	settings Settings // Settings for Ethereum API
@@ -233,8 +235,13 @@ func New(
 			AcceptedCacheSize:               config.AcceptedCacheSize,
 			TransactionHistory:              config.TransactionHistory,
 			SkipTxIndexing:                  config.SkipTxIndexing,
+			LogIndex:                        config.LogIndex,
+			LogIndexHistory:                 config.LogIndexHistory,
+			BodyHistory:                     config.BodyHistory,
+			ReceiptHistory:                  config.ReceiptHistory,
 			StateHistory:                    config.StateHistory,
 			StateScheme:                     scheme,
+			StateHistoryIndex:               config.StateHistoryIndex,
 			ChainDataDir:                    chainDataDir,
 		}
 	)
@@ -251,6 +258,18 @@ func New(
 		return nil, err
 	}
 
+	// Reconstructing states is only needed by pruning nodes, and relies on
+	// the reference counting of the hash scheme.
+	if config.StateReconstruction && config.Pruning && scheme == rawdb.HashScheme {
+		eth.stateReconstructor = newStateReconstructor(
+			eth.blockchain,
+			chainDb,
+			config.StateReconstructionCacheSize,
+			config.StateReconstructionMaxBlocks,
+			config.StateReconstructionParallelism,
+		)
+	}
+
 	eth.bloomIndexer.Start(eth.blockchain)
 
 	// Uncomment the following to enable the new blobpool
@@ -280,7 +299,7 @@ func New(
 		historicalProofQueryWindow: config.HistoricalProofQueryWindow,
 		eth:                        eth,
 	}
-	if config.Pruning {
+	if config.Pruning && eth.stateReconstructor == nil {
 		eth.APIBackend.historicalProofQueryWindow = config.StateHistory
 	}
 	if config.AllowUnprotectedTxs {
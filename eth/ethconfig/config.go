@@ -144,6 +144,14 @@ type Config struct {
 	// For non-archive nodes, it is forcibly set to the value of StateHistory.
 	HistoricalProofQueryWindow uint64
 
+	// StateReconstruction enables regenerating the state of blocks within
+	// HistoricalProofQueryWindow on pruning nodes, by re-executing the blocks
+	// after the nearest persisted state into an in-memory trie database.
+	StateReconstruction            bool
+	StateReconstructionCacheSize   int    // Number of reconstructed states to keep in memory
+	StateReconstructionMaxBlocks   uint64 // Maximum number of blocks to re-execute to reconstruct a state
+	StateReconstructionParallelism int    // Maximum number of states to reconstruct concurrently
+
 	// AllowUnprotectedTxs allow unprotected transactions to be locally issued.
 	// Unprotected transactions are transactions that are signed without EIP-155
 	// replay protection.
@@ -176,11 +184,27 @@ type Config struct {
 	// consistent with persistent state.
 	StateScheme string `toml:",omitempty"`
 
+	// StateHistoryIndex indexes the state histories of the path scheme by
+	// account and storage slot, so that archive nodes serve historical states
+	// without persisting their trie nodes.
+	StateHistoryIndex bool
+
 	// SkipTxIndexing skips indexing transactions.
 	// This is useful for validators that don't need to index transactions.
 	// TransactionHistory can be still used to control unindexing old transactions.
 	SkipTxIndexing bool
 
+	// LogIndex indexes the logs of accepted blocks by address and topic, so
+	// that log filters over wide block ranges are served from the index.
+	LogIndex bool
+
+	// LogIndexHistory, BodyHistory and ReceiptHistory are the maximum number
+	// of blocks from head whose logs are indexed, and whose bodies and
+	// receipts are retained. 0 means no limit.
+	LogIndexHistory uint64 `toml:",omitempty"`
+	BodyHistory     uint64 `toml:",omitempty"`
+	ReceiptHistory  uint64 `toml:",omitempty"`
+
 	// TODO: remove once we move SuggestPriceOptions to AVAX/custom API
 	PriceOptionConfig ethapi.PriceOptionConfig
 }
@@ -47,6 +47,10 @@ func (c Config) MarshalTOML() (interface{}, error) {
 		RPCTxFeeCap                     float64 `toml:",omitempty"`
 		AllowUnfinalizedQueries         bool
 		HistoricalProofQueryWindow      uint64
+		StateReconstruction             bool
+		StateReconstructionCacheSize    int
+		StateReconstructionMaxBlocks    uint64
+		StateReconstructionParallelism  int
 		AllowUnprotectedTxs             bool
 		AllowUnprotectedTxHashes        []common.Hash
 		OfflinePruning                  bool
@@ -56,7 +60,12 @@ func (c Config) MarshalTOML() (interface{}, error) {
 		TransactionHistory              uint64 `toml:",omitempty"`
 		StateHistory                    uint64 `toml:",omitempty"`
 		StateScheme                     string `toml:",omitempty"`
+		StateHistoryIndex               bool
 		SkipTxIndexing                  bool
+		LogIndex                        bool
+		LogIndexHistory                 uint64 `toml:",omitempty"`
+		BodyHistory                     uint64 `toml:",omitempty"`
+		ReceiptHistory                  uint64 `toml:",omitempty"`
 		PriceOptionConfig               ethapi.PriceOptionConfig
 	}
 	var enc Config
@@ -90,6 +99,10 @@ func (c Config) MarshalTOML() (interface{}, error) {
 	enc.RPCTxFeeCap = c.RPCTxFeeCap
 	enc.AllowUnfinalizedQueries = c.AllowUnfinalizedQueries
 	enc.HistoricalProofQueryWindow = c.HistoricalProofQueryWindow
+	enc.StateReconstruction = c.StateReconstruction
+	enc.StateReconstructionCacheSize = c.StateReconstructionCacheSize
+	enc.StateReconstructionMaxBlocks = c.StateReconstructionMaxBlocks
+	enc.StateReconstructionParallelism = c.StateReconstructionParallelism
 	enc.AllowUnprotectedTxs = c.AllowUnprotectedTxs
 	enc.AllowUnprotectedTxHashes = c.AllowUnprotectedTxHashes
 	enc.OfflinePruning = c.OfflinePruning
@@ -99,7 +112,12 @@ func (c Config) MarshalTOML() (interface{}, error) {
 	enc.TransactionHistory = c.TransactionHistory
 	enc.StateHistory = c.StateHistory
 	enc.StateScheme = c.StateScheme
+	enc.StateHistoryIndex = c.StateHistoryIndex
 	enc.SkipTxIndexing = c.SkipTxIndexing
+	enc.LogIndex = c.LogIndex
+	enc.LogIndexHistory = c.LogIndexHistory
+	enc.BodyHistory = c.BodyHistory
+	enc.ReceiptHistory = c.ReceiptHistory
 	enc.PriceOptionConfig = c.PriceOptionConfig
 	return &enc, nil
 }
@@ -137,6 +155,10 @@ func (c *Config) UnmarshalTOML(unmarshal func(interface{}) error) error {
 		RPCTxFeeCap                     *float64 `toml:",omitempty"`
 		AllowUnfinalizedQueries         *bool
 		HistoricalProofQueryWindow      *uint64
+		StateReconstruction             *bool
+		StateReconstructionCacheSize    *int
+		StateReconstructionMaxBlocks    *uint64
+		StateReconstructionParallelism  *int
 		AllowUnprotectedTxs             *bool
 		AllowUnprotectedTxHashes        []common.Hash
 		OfflinePruning                  *bool
@@ -146,7 +168,12 @@ func (c *Config) UnmarshalTOML(unmarshal func(interface{}) error) error {
 		TransactionHistory              *uint64 `toml:",omitempty"`
 		StateHistory                    *uint64 `toml:",omitempty"`
 		StateScheme                     *string `toml:",omitempty"`
+		StateHistoryIndex               *bool
 		SkipTxIndexing                  *bool
+		LogIndex                        *bool
+		LogIndexHistory                 *uint64 `toml:",omitempty"`
+		BodyHistory                     *uint64 `toml:",omitempty"`
+		ReceiptHistory                  *uint64 `toml:",omitempty"`
 		PriceOptionConfig               *ethapi.PriceOptionConfig
 	}
 	var dec Config
@@ -243,6 +270,18 @@ func (c *Config) UnmarshalTOML(unmarshal func(interface{}) error) error {
 	if dec.HistoricalProofQueryWindow != nil {
 		c.HistoricalProofQueryWindow = *dec.HistoricalProofQueryWindow
 	}
+	if dec.StateReconstruction != nil {
+		c.StateReconstruction = *dec.StateReconstruction
+	}
+	if dec.StateReconstructionCacheSize != nil {
+		c.StateReconstructionCacheSize = *dec.StateReconstructionCacheSize
+	}
+	if dec.StateReconstructionMaxBlocks != nil {
+		c.StateReconstructionMaxBlocks = *dec.StateReconstructionMaxBlocks
+	}
+	if dec.StateReconstructionParallelism != nil {
+		c.StateReconstructionParallelism = *dec.StateReconstructionParallelism
+	}
 	if dec.AllowUnprotectedTxs != nil {
 		c.AllowUnprotectedTxs = *dec.AllowUnprotectedTxs
 	}
@@ -270,9 +309,24 @@ func (c *Config) UnmarshalTOML(unmarshal func(interface{}) error) error {
 	if dec.StateScheme != nil {
 		c.StateScheme = *dec.StateScheme
 	}
+	if dec.StateHistoryIndex != nil {
+		c.StateHistoryIndex = *dec.StateHistoryIndex
+	}
 	if dec.SkipTxIndexing != nil {
 		c.SkipTxIndexing = *dec.SkipTxIndexing
 	}
+	if dec.LogIndex != nil {
+		c.LogIndex = *dec.LogIndex
+	}
+	if dec.LogIndexHistory != nil {
+		c.LogIndexHistory = *dec.LogIndexHistory
+	}
+	if dec.BodyHistory != nil {
+		c.BodyHistory = *dec.BodyHistory
+	}
+	if dec.ReceiptHistory != nil {
+		c.ReceiptHistory = *dec.ReceiptHistory
+	}
 	if dec.PriceOptionConfig != nil {
 		c.PriceOptionConfig = *dec.PriceOptionConfig
 	}
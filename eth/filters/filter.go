@@ -197,7 +197,10 @@ func (f *Filter) Logs(ctx context.Context) ([]*types.Log, error) {
 
 	// If the requested range of blocks exceeds the maximum number of blocks allowed by the backend
 	// return an error instead of searching for the logs.
-	if maxBlocks := f.sys.backend.GetMaxBlocksPerRequest(); f.end-f.begin >= maxBlocks && maxBlocks > 0 {
+	// Ranges fully covered by the log index are not limited, as only the
+	// blocks containing matching logs are read, and their number is limited
+	// instead.
+	if maxBlocks := f.sys.backend.GetMaxBlocksPerRequest(); f.end-f.begin >= maxBlocks && maxBlocks > 0 && !f.logIndexCovers(f.end) {
 		return nil, fmt.Errorf("requested too many blocks from %d to %d, maximum is set to %d", f.begin, f.end, maxBlocks)
 	}
 	// Gather all indexed logs, and finish with non indexed ones
@@ -237,6 +240,18 @@ func (f *Filter) rangeLogsAsync(ctx context.Context) (chan *types.Log, chan erro
 			size, sections = f.sys.backend.BloomStatus()
 			err            error
 		)
+		// Serve the blocks covered by the log index first, then the remaining
+		// blocks from the bloom bits.
+		if indexEnd, ok := f.logIndexEnd(); ok {
+			if err = f.logIndexLogs(ctx, min(end, indexEnd), logChan); err != nil {
+				errChan <- err
+				return
+			}
+			if f.begin > f.end {
+				errChan <- nil
+				return
+			}
+		}
 		if indexed := sections * size; indexed > uint64(f.begin) {
 			if indexed > end {
 				indexed = end + 1
@@ -83,6 +83,10 @@ type Backend interface {
 	BloomStatus() (uint64, uint64)
 	ServiceFilter(ctx context.Context, session *bloombits.MatcherSession)
 
+	// LogIndexRange returns the range of blocks whose logs are indexed by
+	// address and topic, or false if there is none.
+	LogIndexRange() (uint64, uint64, bool)
+
 	// Added to the backend interface to support limiting of logs requests
 	IsAllowUnfinalizedQueries() bool
 	LastAcceptedBlock() *types.Block
@@ -55,15 +55,16 @@ import (
 )
 
 type testBackend struct {
-	db                ethdb.Database
-	sections          uint64
-	txFeed            event.Feed
-	acceptedTxFeed    event.Feed
-	logsFeed          event.Feed
-	rmLogsFeed        event.Feed
-	pendingLogsFeed   event.Feed
-	chainFeed         event.Feed
-	chainAcceptedFeed event.Feed
+	db                  ethdb.Database
+	sections            uint64
+	maxBlocksPerRequest int64
+	txFeed              event.Feed
+	acceptedTxFeed      event.Feed
+	logsFeed            event.Feed
+	rmLogsFeed          event.Feed
+	pendingLogsFeed     event.Feed
+	chainFeed           event.Feed
+	chainAcceptedFeed   event.Feed
 }
 
 func (b *testBackend) ChainConfig() *params.ChainConfig {
@@ -84,7 +85,7 @@ func (b *testBackend) IsAllowUnfinalizedQueries() bool {
 }
 
 func (b *testBackend) GetMaxBlocksPerRequest() int64 {
-	return 0
+	return b.maxBlocksPerRequest
 }
 
 func (b *testBackend) LastAcceptedBlock() *types.Block {
@@ -216,6 +217,14 @@ func (b *testBackend) ServiceFilter(ctx context.Context, session *bloombits.Matc
 	}()
 }
 
+func (b *testBackend) LogIndexRange() (uint64, uint64, bool) {
+	indexed, _ := customrawdb.ReadLogIndexRange(b.db)
+	if indexed == nil {
+		return 0, 0, false
+	}
+	return indexed.Tail, indexed.Head, true
+}
+
 func newTestFilterSystem(t testing.TB, db ethdb.Database, cfg Config) (*testBackend, *FilterSystem) {
 	backend := &testBackend{db: db}
 	sys := NewFilterSystem(backend, cfg)
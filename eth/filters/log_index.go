@@ -0,0 +1,159 @@
+// Copyright (C) 2019-2025, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package filters
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/MetalBlockchain/libevm/core/types"
+	"github.com/MetalBlockchain/libevm/ethdb"
+
+	"github.com/MetalBlockchain/coreth/plugin/evm/customrawdb"
+	"github.com/MetalBlockchain/coreth/rpc"
+)
+
+// logIndexQueryBlocks is the number of blocks whose index entries are read at
+// once, bounding the memory used by queries over wide ranges.
+const logIndexQueryBlocks = 1 << 16
+
+// logIndexEnd returns the last block whose logs can be served from the log
+// index, and whether the first block of the filter is indexed. The log index
+// is keyed by address, so filters without addresses are not served from it.
+func (f *Filter) logIndexEnd() (uint64, bool) {
+	if len(f.addresses) == 0 || f.begin < 0 {
+		return 0, false
+	}
+	tail, head, ok := f.sys.backend.LogIndexRange()
+	if !ok || uint64(f.begin) < tail || uint64(f.begin) > head {
+		return 0, false
+	}
+	return head, true
+}
+
+// logIndexCovers returns whether the logs of the filter up to [end] are all
+// served from the log index.
+func (f *Filter) logIndexCovers(end int64) bool {
+	head, ok := f.logIndexEnd()
+	return ok && end >= 0 && uint64(end) <= head
+}
+
+// logIndexLogs returns the logs matching the filter criteria up to [end], by
+// only inspecting the blocks found in the log index. As the range served from
+// the log index is not limited, the number of blocks inspected is limited by
+// the maximum number of blocks per request instead.
+func (f *Filter) logIndexLogs(ctx context.Context, end uint64, logChan chan *types.Log) error {
+	var (
+		db        = f.sys.backend.ChainDb()
+		maxBlocks = f.sys.backend.GetMaxBlocksPerRequest()
+		begin     = f.begin
+		inspected int64
+	)
+	for f.begin <= int64(end) {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		var (
+			from = uint64(f.begin)
+			to   = min(end, from+logIndexQueryBlocks-1)
+		)
+		numbers, err := f.logIndexBlocks(db, from, to)
+		if err != nil {
+			return err
+		}
+		inspected += int64(len(numbers))
+		if maxBlocks > 0 && inspected > maxBlocks {
+			return fmt.Errorf("requested logs from too many blocks from %d to %d, maximum is set to %d", begin, end, maxBlocks)
+		}
+		for _, number := range numbers {
+			header, err := f.sys.backend.HeaderByNumber(ctx, rpc.BlockNumber(number))
+			if header == nil || err != nil {
+				return err
+			}
+			found, err := f.checkMatches(ctx, header)
+			if err != nil {
+				return err
+			}
+			for _, log := range found {
+				select {
+				case logChan <- log:
+				case <-ctx.Done():
+					return ctx.Err()
+				}
+			}
+		}
+		f.begin = int64(to) + 1
+	}
+	return nil
+}
+
+// logIndexBlocks returns the numbers of the blocks in [from, to] which may
+// contain logs matching the filter criteria, in ascending order.
+//
+// For each address, the blocks are those indexed for the address and one of
+// the topics at every constrained position. The logs of these blocks must
+// still be checked, as the topics may be matched by different logs.
+func (f *Filter) logIndexBlocks(db ethdb.Iteratee, from, to uint64) ([]uint64, error) {
+	var numbers []uint64
+	for _, address := range f.addresses {
+		var (
+			matches     []uint64
+			constrained bool
+		)
+		for position, topics := range f.topics {
+			if len(topics) == 0 {
+				continue // empty rule set == wildcard
+			}
+			var union []uint64
+			for _, topic := range topics {
+				found, err := customrawdb.ReadLogIndexTopicBlocks(db, address, uint8(position), topic, from, to)
+				if err != nil {
+					return nil, err
+				}
+				union = append(union, found...)
+			}
+			slices.Sort(union)
+			union = slices.Compact(union)
+			if constrained {
+				matches = intersectSorted(matches, union)
+			} else {
+				matches = union
+				constrained = true
+			}
+		}
+		if !constrained {
+			var err error
+			matches, err = customrawdb.ReadLogIndexAddressBlocks(db, address, from, to)
+			if err != nil {
+				return nil, err
+			}
+		}
+		numbers = append(numbers, matches...)
+	}
+	slices.Sort(numbers)
+	return slices.Compact(numbers), nil
+}
+
+// intersectSorted returns the elements of the sorted slices [a] and [b]
+// present in both.
+func intersectSorted(a, b []uint64) []uint64 {
+	var (
+		result []uint64
+		i, j   int
+	)
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] < b[j]:
+			i++
+		case a[i] > b[j]:
+			j++
+		default:
+			result = append(result, a[i])
+			i++
+			j++
+		}
+	}
+	return result
+}
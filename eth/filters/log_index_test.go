@@ -0,0 +1,186 @@
+// Copyright (C) 2019-2025, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package filters
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/MetalBlockchain/libevm/common"
+	"github.com/MetalBlockchain/libevm/core/rawdb"
+	"github.com/MetalBlockchain/libevm/core/types"
+	"github.com/MetalBlockchain/libevm/triedb"
+	"github.com/stretchr/testify/require"
+
+	"github.com/MetalBlockchain/coreth/consensus/dummy"
+	"github.com/MetalBlockchain/coreth/core"
+	"github.com/MetalBlockchain/coreth/params"
+	"github.com/MetalBlockchain/coreth/plugin/evm/customrawdb"
+)
+
+func TestLogIndexFilters(t *testing.T) {
+	var (
+		db           = rawdb.NewMemoryDatabase()
+		backend, sys = newTestFilterSystem(t, db, Config{})
+		addr1        = common.BytesToAddress([]byte("jeff"))
+		addr2        = common.BytesToAddress([]byte("ethereum"))
+		topic1       = common.BytesToHash([]byte("topic1"))
+		topic2       = common.BytesToHash([]byte("topic2"))
+
+		gspec = &core.Genesis{
+			Alloc:   types.GenesisAlloc{},
+			BaseFee: big.NewInt(1),
+			Config:  params.TestChainConfig,
+		}
+		// Logs emitted by block number
+		blockLogs = map[uint64]*types.Log{
+			10:   {Address: addr1, Topics: []common.Hash{topic1}},
+			100:  {Address: addr1, Topics: []common.Hash{topic2, topic1}},
+			500:  {Address: addr2, Topics: []common.Hash{topic1}},
+			1500: {Address: addr1, Topics: []common.Hash{topic1}},
+			2000: {Address: addr1, Topics: []common.Hash{topic2}},
+		}
+	)
+	_, chain, receipts, err := core.GenerateChainWithGenesis(gspec, dummy.NewFaker(), 2000, 10, func(i int, gen *core.BlockGen) {
+		log, ok := blockLogs[uint64(i+1)]
+		if !ok {
+			return
+		}
+		receipt := types.NewReceipt(nil, false, 0)
+		receipt.Logs = []*types.Log{log}
+		receipt.Bloom = types.CreateBloom(types.Receipts{receipt})
+		gen.AddUncheckedReceipt(receipt)
+		gen.AddUncheckedTx(types.NewTransaction(999, common.HexToAddress("0x999"), big.NewInt(999), 999, gen.BaseFee(), nil))
+	})
+	require.NoError(t, err)
+	gspec.MustCommit(db, triedb.NewDatabase(db, triedb.HashDefaults))
+	for i, block := range chain {
+		rawdb.WriteBlock(db, block)
+		rawdb.WriteCanonicalHash(db, block.Hash(), block.NumberU64())
+		rawdb.WriteHeadBlockHash(db, block.Hash())
+		rawdb.WriteReceipts(db, block.Hash(), block.NumberU64(), receipts[i])
+	}
+
+	// Index the logs of blocks [5, 1600].
+	for number, log := range blockLogs {
+		if number >= 5 && number <= 1600 {
+			require.NoError(t, customrawdb.WriteLogIndexEntries(db, number, []*types.Log{log}))
+		}
+	}
+	require.NoError(t, customrawdb.WriteLogIndexRange(db, &customrawdb.LogIndexRange{Tail: 5, Head: 1600}))
+
+	tests := []struct {
+		name      string
+		maxBlocks int64
+		begin     int64
+		end       int64
+		addresses []common.Address
+		topics    [][]common.Hash
+		expected  []uint64
+		err       string
+	}{
+		{
+			name:      "covered range is not limited",
+			maxBlocks: 100,
+			begin:     5,
+			end:       1600,
+			addresses: []common.Address{addr1},
+			expected:  []uint64{10, 100, 1500},
+		},
+		{
+			name:      "covered range is limited by matching blocks",
+			maxBlocks: 2,
+			begin:     5,
+			end:       1600,
+			addresses: []common.Address{addr1},
+			err:       "requested logs from too many blocks from 5 to 1600, maximum is set to 2",
+		},
+		{
+			name:      "range above index is limited",
+			maxBlocks: 100,
+			begin:     5,
+			end:       2000,
+			addresses: []common.Address{addr1},
+			err:       "requested too many blocks from 5 to 2000, maximum is set to 100",
+		},
+		{
+			name:      "range below index is limited",
+			maxBlocks: 100,
+			begin:     0,
+			end:       1600,
+			addresses: []common.Address{addr1},
+			err:       "requested too many blocks from 0 to 1600, maximum is set to 100",
+		},
+		{
+			name:      "range above index",
+			begin:     5,
+			end:       2000,
+			addresses: []common.Address{addr1},
+			topics:    [][]common.Hash{{topic2}},
+			expected:  []uint64{100, 2000},
+		},
+		{
+			name:      "range below index",
+			begin:     0,
+			end:       1600,
+			addresses: []common.Address{addr1},
+			expected:  []uint64{10, 100, 1500},
+		},
+		{
+			name:      "topic position",
+			begin:     5,
+			end:       1600,
+			addresses: []common.Address{addr1},
+			topics:    [][]common.Hash{nil, {topic1}},
+			expected:  []uint64{100},
+		},
+		{
+			name:      "multiple addresses",
+			begin:     5,
+			end:       1600,
+			addresses: []common.Address{addr1, addr2},
+			topics:    [][]common.Hash{{topic1}},
+			expected:  []uint64{10, 500, 1500},
+		},
+		{
+			name:      "multiple topics",
+			begin:     5,
+			end:       1600,
+			addresses: []common.Address{addr1},
+			topics:    [][]common.Hash{{topic1, topic2}, {topic1}},
+			expected:  []uint64{100},
+		},
+		{
+			name:      "no match",
+			begin:     5,
+			end:       1600,
+			addresses: []common.Address{addr2},
+			topics:    [][]common.Hash{{topic2}},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			backend.maxBlocksPerRequest = test.maxBlocks
+			logs, err := sys.NewRangeFilter(test.begin, test.end, test.addresses, test.topics).Logs(context.Background())
+			if test.err != "" {
+				require.EqualError(t, err, test.err)
+				return
+			}
+			require.NoError(t, err)
+			var numbers []uint64
+			for _, log := range logs {
+				require.Equal(t, blockLogs[log.BlockNumber].Address, log.Address)
+				numbers = append(numbers, log.BlockNumber)
+			}
+			require.Equal(t, test.expected, numbers)
+		})
+	}
+}
+
+func TestIntersectSorted(t *testing.T) {
+	require.Equal(t, []uint64{2, 5}, intersectSorted([]uint64{1, 2, 3, 5, 8}, []uint64{2, 4, 5, 9}))
+	require.Empty(t, intersectSorted([]uint64{1, 3}, []uint64{2, 4}))
+	require.Empty(t, intersectSorted(nil, []uint64{1}))
+}
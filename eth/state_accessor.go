@@ -257,13 +257,16 @@ func (eth *Ethereum) stateAtTransaction(ctx context.Context, block *types.Block,
 	if txIndex == 0 && len(block.Transactions()) == 0 {
 		return nil, vm.BlockContext{}, statedb, release, nil
 	}
+	context, err := core.NewEVMBlockContext(block.Header(), eth.blockchain, nil)
+	if err != nil {
+		return nil, vm.BlockContext{}, nil, nil, err
+	}
 	// Recompute transactions up to the target index.
 	signer := types.MakeSigner(eth.blockchain.Config(), block.Number(), block.Time())
 	for idx, tx := range block.Transactions() {
 		// Assemble the transaction call message and return if the requested offset
 		msg, _ := core.TransactionToMessage(tx, signer, block.BaseFee())
 		txContext := core.NewEVMTxContext(msg)
-		context := core.NewEVMBlockContext(block.Header(), eth.blockchain, nil)
 		if idx == txIndex {
 			return msg, context, statedb, release, nil
 		}
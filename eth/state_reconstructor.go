@@ -0,0 +1,247 @@
+// Copyright (C) 2019-2025, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package eth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/MetalBlockchain/libevm/common"
+	"github.com/MetalBlockchain/libevm/common/lru"
+	"github.com/MetalBlockchain/libevm/core/state"
+	"github.com/MetalBlockchain/libevm/core/types"
+	"github.com/MetalBlockchain/libevm/core/vm"
+	"github.com/MetalBlockchain/libevm/ethdb"
+	"github.com/MetalBlockchain/libevm/log"
+	"github.com/MetalBlockchain/libevm/triedb"
+	"golang.org/x/sync/singleflight"
+
+	"github.com/MetalBlockchain/coreth/core"
+	"github.com/MetalBlockchain/coreth/core/extstate"
+	"github.com/MetalBlockchain/coreth/triedb/hashdb"
+)
+
+var (
+	errReconstructionUnavailable = errors.New("no persisted state to reconstruct from")
+	errReconstructedRootMismatch = errors.New("reconstructed state root does not match the block")
+)
+
+// reconstructedState is a state regenerated by a [stateReconstructor]. Its
+// trie nodes are held in memory by [tdb], which may be shared with the
+// states reconstructed on top of it.
+type reconstructedState struct {
+	database state.Database
+	tdb      *triedb.Database
+	root     common.Hash
+}
+
+// stateReconstructor regenerates the state of blocks that is not persisted
+// on pruning nodes, by re-executing the blocks after the nearest ancestor
+// with a persisted or reconstructed state into an in-memory trie database.
+// The reconstructed states are kept in an LRU cache, so that consecutive
+// queries at the same or later heights do not re-execute the same blocks.
+type stateReconstructor struct {
+	blockchain *core.BlockChain
+	chainDb    ethdb.Database
+
+	maxBlocks uint64        // Maximum number of blocks to re-execute per state
+	slots     chan struct{} // Bounds the number of concurrent reconstructions
+
+	group singleflight.Group // Deduplicates concurrent reconstructions of a block
+
+	lock      sync.Mutex
+	cacheSize int
+	states    lru.BasicLRU[common.Hash, *reconstructedState] // Reconstructed states by block hash
+}
+
+func newStateReconstructor(blockchain *core.BlockChain, chainDb ethdb.Database, cacheSize int, maxBlocks uint64, parallelism int) *stateReconstructor {
+	return &stateReconstructor{
+		blockchain: blockchain,
+		chainDb:    chainDb,
+		maxBlocks:  maxBlocks,
+		slots:      make(chan struct{}, parallelism),
+		cacheSize:  cacheSize,
+		states:     lru.NewBasicLRU[common.Hash, *reconstructedState](cacheSize),
+	}
+}
+
+// stateAt returns the state of the block with [header], reconstructing it if
+// it is not cached. The reconstruction is aborted at the deadline of [ctx],
+// which bounds it by the API max duration.
+func (r *stateReconstructor) stateAt(ctx context.Context, header *types.Header) (*state.StateDB, error) {
+	hash := header.Hash()
+	if cached, ok := r.get(hash); ok {
+		return state.New(cached.root, cached.database, nil)
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	results := r.group.DoChan(hash.Hex(), func() (interface{}, error) {
+		// The reconstruction is shared by all the callers querying the block,
+		// so it must not be aborted when the caller that started it is
+		// canceled.
+		detachedCtx, cancel := detachContext(ctx)
+		defer cancel()
+
+		select {
+		case r.slots <- struct{}{}:
+		case <-detachedCtx.Done():
+			return nil, detachedCtx.Err()
+		}
+		defer func() { <-r.slots }()
+
+		block := r.blockchain.GetBlock(hash, header.Number.Uint64())
+		if block == nil {
+			return nil, fmt.Errorf("block %s not found", hash)
+		}
+		return r.reconstruct(detachedCtx, block)
+	})
+
+	var res singleflight.Result
+	select {
+	case res = <-results:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	if res.Err != nil {
+		return nil, res.Err
+	}
+	reconstructed := res.Val.(*reconstructedState)
+	return state.New(reconstructed.root, reconstructed.database, nil)
+}
+
+// detachContext returns a context that is not canceled with [ctx], but has
+// the same deadline.
+func detachContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	detached := context.WithoutCancel(ctx)
+	if deadline, ok := ctx.Deadline(); ok {
+		return context.WithDeadline(detached, deadline)
+	}
+	return context.WithCancel(detached)
+}
+
+// reconstruct regenerates the state of [block] and adds it to the cache.
+func (r *stateReconstructor) reconstruct(ctx context.Context, block *types.Block) (_ *reconstructedState, err error) {
+	// Find the nearest ancestor with a cached state, or with a state persisted
+	// on disk. The ephemeral trie database isolates the re-executed state from
+	// the live one, so that it is never persisted.
+	var (
+		diskTdb      = triedb.NewDatabase(r.chainDb, &triedb.Config{DBOverride: hashdb.Config{}.BackendConstructor})
+		diskDatabase = extstate.NewDatabaseWithNodeDB(r.chainDb, diskTdb)
+
+		current  = block.Header()
+		pending  []common.Hash // Blocks to re-execute, from the last to the first
+		database state.Database
+		tdb      *triedb.Database
+		statedb  *state.StateDB
+	)
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		if cached, ok := r.get(current.Hash()); ok {
+			if statedb, err = state.New(cached.root, cached.database, nil); err == nil {
+				database, tdb = cached.database, cached.tdb
+				break
+			}
+		}
+		if statedb, err = state.New(current.Root, diskDatabase, nil); err == nil {
+			database, tdb = diskDatabase, diskTdb
+			break
+		}
+		if uint64(len(pending)) == r.maxBlocks || current.Number.Uint64() == 0 {
+			return nil, fmt.Errorf("%w within %d blocks of block %d", errReconstructionUnavailable, r.maxBlocks, block.NumberU64())
+		}
+		pending = append(pending, current.Hash())
+		parent := r.blockchain.GetHeader(current.ParentHash, current.Number.Uint64()-1)
+		if parent == nil {
+			return nil, fmt.Errorf("missing header %s %d", current.ParentHash, current.Number.Uint64()-1)
+		}
+		current = parent
+	}
+
+	// Re-execute the blocks on top of the state found, holding a reference
+	// to the latest root only.
+	var (
+		start        = time.Now()
+		from         = current.Number.Uint64()
+		root         = current.Root
+		parentHeader = current
+		referenced   common.Hash
+	)
+	defer func() {
+		if err != nil && referenced != (common.Hash{}) {
+			tdb.Dereference(referenced)
+		}
+	}()
+	for i := len(pending) - 1; i >= 0; i-- {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		next := r.blockchain.GetBlockByHash(pending[i])
+		if next == nil {
+			return nil, fmt.Errorf("block %s not found", pending[i])
+		}
+		if _, _, _, err := r.blockchain.Processor().Process(next, parentHeader, statedb, vm.Config{}); err != nil {
+			return nil, fmt.Errorf("processing block %d failed: %w", next.NumberU64(), err)
+		}
+		root, err = statedb.Commit(next.NumberU64(), r.blockchain.Config().IsEIP158(next.Number()))
+		if err != nil {
+			return nil, fmt.Errorf("state commit failed for block %d: %w", next.NumberU64(), err)
+		}
+		if statedb, err = state.New(root, database, nil); err != nil {
+			return nil, fmt.Errorf("state reset after block %d failed: %w", next.NumberU64(), err)
+		}
+		tdb.Reference(root, common.Hash{})
+		if referenced != (common.Hash{}) {
+			tdb.Dereference(referenced)
+		}
+		referenced = root
+		parentHeader = next.Header()
+	}
+	if root != block.Root() {
+		return nil, fmt.Errorf("%w: block %d has root %s, reconstructed %s", errReconstructedRootMismatch, block.NumberU64(), block.Root(), root)
+	}
+	if referenced == (common.Hash{}) {
+		// No block was re-executed, so the state found must be referenced
+		// before it is cached.
+		tdb.Reference(root, common.Hash{})
+	}
+	log.Debug("Reconstructed historical state", "number", block.NumberU64(), "hash", block.Hash(), "from", from, "elapsed", common.PrettyDuration(time.Since(start)))
+
+	reconstructed := &reconstructedState{
+		database: database,
+		tdb:      tdb,
+		root:     root,
+	}
+	r.add(block.Hash(), reconstructed)
+	return reconstructed, nil
+}
+
+func (r *stateReconstructor) get(hash common.Hash) (*reconstructedState, bool) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	return r.states.Get(hash)
+}
+
+// add caches [reconstructed], dereferencing the least recently used state if
+// the cache is full. Queries still reading an evicted state may fail with a
+// missing trie node.
+func (r *stateReconstructor) add(hash common.Hash, reconstructed *reconstructedState) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if existing, ok := r.states.Peek(hash); ok {
+		existing.tdb.Dereference(existing.root)
+	} else if r.states.Len() >= r.cacheSize {
+		if _, evicted, ok := r.states.RemoveOldest(); ok {
+			evicted.tdb.Dereference(evicted.root)
+		}
+	}
+	r.states.Add(hash, reconstructed)
+}
@@ -0,0 +1,166 @@
+// Copyright (C) 2019-2025, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package eth
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/MetalBlockchain/libevm/common"
+	"github.com/MetalBlockchain/libevm/core/rawdb"
+	"github.com/MetalBlockchain/libevm/core/types"
+	"github.com/MetalBlockchain/libevm/core/vm"
+	"github.com/MetalBlockchain/libevm/crypto"
+	"github.com/MetalBlockchain/libevm/ethdb"
+	ethparams "github.com/MetalBlockchain/libevm/params"
+	"github.com/stretchr/testify/require"
+
+	"github.com/MetalBlockchain/coreth/consensus/dummy"
+	"github.com/MetalBlockchain/coreth/core"
+	"github.com/MetalBlockchain/coreth/params"
+)
+
+const reconstructorTestTransfer = 1000
+
+var (
+	reconstructorTestKey, _  = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+	reconstructorTestAddr    = crypto.PubkeyToAddress(reconstructorTestKey.PublicKey)
+	reconstructorTestAddr2   = common.Address{0x02}
+	reconstructorTestBalance = big.NewInt(params.Ether)
+)
+
+// newReconstructorTestChain returns a pruning blockchain committing the state
+// every 4 blocks, with [n] accepted blocks each transferring to
+// [reconstructorTestAddr2].
+func newReconstructorTestChain(t *testing.T, n int) (*core.BlockChain, ethdb.Database) {
+	t.Helper()
+	gspec := &core.Genesis{
+		Config: params.TestChainConfig,
+		Alloc: types.GenesisAlloc{
+			reconstructorTestAddr: {Balance: reconstructorTestBalance},
+		},
+	}
+	engine := dummy.NewETHFaker()
+	signer := types.LatestSigner(gspec.Config)
+	_, blocks, _, err := core.GenerateChainWithGenesis(gspec, engine, n, 10, func(i int, b *core.BlockGen) {
+		tx, err := types.SignTx(types.NewTx(&types.LegacyTx{
+			Nonce:    uint64(i),
+			To:       &reconstructorTestAddr2,
+			Value:    big.NewInt(reconstructorTestTransfer),
+			Gas:      ethparams.TxGas,
+			GasPrice: b.BaseFee(),
+		}), signer, reconstructorTestKey)
+		require.NoError(t, err)
+		b.AddTx(tx)
+	})
+	require.NoError(t, err)
+
+	chainDb := rawdb.NewMemoryDatabase()
+	cacheConfig := &core.CacheConfig{
+		TrieCleanLimit:            256,
+		TrieDirtyLimit:            256,
+		TriePrefetcherParallelism: 4,
+		Pruning:                   true,
+		CommitInterval:            4,
+		StateScheme:               rawdb.HashScheme,
+		StateHistory:              2,
+		ChainDataDir:              t.TempDir(),
+	}
+	chain, err := core.NewBlockChain(chainDb, cacheConfig, gspec, engine, vm.Config{}, common.Hash{}, false)
+	require.NoError(t, err)
+	t.Cleanup(chain.Stop)
+
+	_, err = chain.InsertChain(blocks)
+	require.NoError(t, err)
+	for _, block := range blocks {
+		require.NoError(t, chain.Accept(block))
+	}
+	chain.DrainAcceptorQueue()
+	return chain, chainDb
+}
+
+func TestStateReconstructor(t *testing.T) {
+	require := require.New(t)
+
+	chain, chainDb := newReconstructorTestChain(t, 10)
+	r := newStateReconstructor(chain, chainDb, 2, 4, 1)
+
+	for _, number := range []uint64{7, 6, 8, 3} {
+		header := chain.GetHeaderByNumber(number)
+		statedb, err := r.stateAt(context.Background(), header)
+		require.NoError(err, "block %d", number)
+		require.Equal(header.Root, statedb.IntermediateRoot(true), "block %d", number)
+		expected := new(big.Int).SetUint64(number * reconstructorTestTransfer)
+		require.Zero(expected.Cmp(statedb.GetBalance(reconstructorTestAddr2).ToBig()), "block %d", number)
+	}
+
+	// Only the most recently reconstructed states are cached.
+	_, ok := r.get(chain.GetHeaderByNumber(3).Hash())
+	require.True(ok)
+	_, ok = r.get(chain.GetHeaderByNumber(8).Hash())
+	require.True(ok)
+	_, ok = r.get(chain.GetHeaderByNumber(7).Hash())
+	require.False(ok)
+}
+
+func TestStateReconstructorMaxBlocks(t *testing.T) {
+	require := require.New(t)
+
+	chain, chainDb := newReconstructorTestChain(t, 10)
+	r := newStateReconstructor(chain, chainDb, 2, 2, 1)
+
+	// Block 7 is 3 blocks after the state committed at block 4.
+	_, err := r.stateAt(context.Background(), chain.GetHeaderByNumber(7))
+	require.ErrorIs(err, errReconstructionUnavailable)
+
+	// Once block 6 is reconstructed, block 7 can be reconstructed from it.
+	_, err = r.stateAt(context.Background(), chain.GetHeaderByNumber(6))
+	require.NoError(err)
+	header := chain.GetHeaderByNumber(7)
+	statedb, err := r.stateAt(context.Background(), header)
+	require.NoError(err)
+	require.Equal(header.Root, statedb.IntermediateRoot(true))
+}
+
+func TestStateReconstructorCanceled(t *testing.T) {
+	chain, chainDb := newReconstructorTestChain(t, 10)
+	r := newStateReconstructor(chain, chainDb, 2, 4, 1)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := r.stateAt(ctx, chain.GetHeaderByNumber(7))
+	require.ErrorIs(t, err, context.Canceled)
+}
+
+func TestStateReconstructorSharedCanceled(t *testing.T) {
+	require := require.New(t)
+
+	chain, chainDb := newReconstructorTestChain(t, 10)
+	r := newStateReconstructor(chain, chainDb, 2, 4, 1)
+	header := chain.GetHeaderByNumber(7)
+
+	// Hold the only slot, so that the reconstruction started by the first
+	// caller is still in progress when it is canceled.
+	r.slots <- struct{}{}
+	ctx, cancel := context.WithCancel(context.Background())
+	firstErr := make(chan error, 1)
+	go func() {
+		_, err := r.stateAt(ctx, header)
+		firstErr <- err
+	}()
+	cancel()
+	require.ErrorIs(<-firstErr, context.Canceled)
+
+	// The second caller is not canceled with the first one.
+	secondErr := make(chan error, 1)
+	go func() {
+		_, err := r.stateAt(context.Background(), header)
+		secondErr <- err
+	}()
+	<-r.slots
+	require.NoError(<-secondErr)
+	_, ok := r.get(header.Hash())
+	require.True(ok)
+}
@@ -268,6 +268,39 @@ func (api *API) TraceChain(ctx context.Context, start, end rpc.BlockNumber, conf
 	return sub, nil
 }
 
+// traceChainTask traces all the transactions contained within the block of the
+// task on top of the task state. Failures are recorded in the task results.
+func (api *API) traceChainTask(ctx context.Context, task *blockTraceTask, config *TraceConfig) {
+	blockCtx, err := core.NewEVMBlockContext(task.block.Header(), api.chainContext(ctx), nil)
+	if err != nil {
+		for i, tx := range task.block.Transactions() {
+			task.results[i] = &txTraceResult{TxHash: tx.Hash(), Error: err.Error()}
+		}
+		log.Warn("Tracing failed", "block", task.block.NumberU64(), "err", err)
+		return
+	}
+	signer := types.MakeSigner(api.backend.ChainConfig(), task.block.Number(), task.block.Time())
+	// Trace all the transactions contained within
+	for i, tx := range task.block.Transactions() {
+		msg, _ := core.TransactionToMessage(tx, signer, task.block.BaseFee())
+		txctx := &Context{
+			BlockHash:   task.block.Hash(),
+			BlockNumber: task.block.Number(),
+			TxIndex:     i,
+			TxHash:      tx.Hash(),
+		}
+		res, err := api.traceTx(ctx, msg, txctx, blockCtx, task.statedb, config)
+		if err != nil {
+			task.results[i] = &txTraceResult{TxHash: tx.Hash(), Error: err.Error()}
+			log.Warn("Tracing failed", "hash", tx.Hash(), "block", task.block.NumberU64(), "err", err)
+			break
+		}
+		// Only delete empty objects if EIP158/161 (a.k.a Spurious Dragon) is in effect
+		task.statedb.Finalise(api.backend.ChainConfig().IsEIP158(task.block.Number()))
+		task.results[i] = &txTraceResult{TxHash: tx.Hash(), Result: res}
+	}
+}
+
 // traceChain configures a new tracer according to the provided configuration, and
 // executes all the transactions contained within. The tracing chain range includes
 // the end block but excludes the start one. The return value will be one item per
@@ -297,29 +330,8 @@ func (api *API) traceChain(start, end *types.Block, config *TraceConfig, closed
 
 			// Fetch and execute the block trace taskCh
 			for task := range taskCh {
-				var (
-					signer   = types.MakeSigner(api.backend.ChainConfig(), task.block.Number(), task.block.Time())
-					blockCtx = core.NewEVMBlockContext(task.block.Header(), api.chainContext(ctx), nil)
-				)
-				// Trace all the transactions contained within
-				for i, tx := range task.block.Transactions() {
-					msg, _ := core.TransactionToMessage(tx, signer, task.block.BaseFee())
-					txctx := &Context{
-						BlockHash:   task.block.Hash(),
-						BlockNumber: task.block.Number(),
-						TxIndex:     i,
-						TxHash:      tx.Hash(),
-					}
-					res, err := api.traceTx(ctx, msg, txctx, blockCtx, task.statedb, config)
-					if err != nil {
-						task.results[i] = &txTraceResult{TxHash: tx.Hash(), Error: err.Error()}
-						log.Warn("Tracing failed", "hash", tx.Hash(), "block", task.block.NumberU64(), "err", err)
-						break
-					}
-					// Only delete empty objects if EIP158/161 (a.k.a Spurious Dragon) is in effect
-					task.statedb.Finalise(api.backend.ChainConfig().IsEIP158(task.block.Number()))
-					task.results[i] = &txTraceResult{TxHash: tx.Hash(), Result: res}
-				}
+				api.traceChainTask(ctx, task, config)
+
 				// Tracing state is used up, queue it for de-referencing. Note the
 				// state is the parent state of trace block, use block.number-1 as
 				// the state number.
@@ -558,11 +570,14 @@ func (api *API) IntermediateRoots(ctx context.Context, hash common.Hash, config
 	}
 	defer release()
 
+	vmctx, err := core.NewEVMBlockContext(block.Header(), api.chainContext(ctx), nil)
+	if err != nil {
+		return nil, err
+	}
 	var (
 		roots              []common.Hash
 		signer             = types.MakeSigner(api.backend.ChainConfig(), block.Number(), block.Time())
 		chainConfig        = api.backend.ChainConfig()
-		vmctx              = core.NewEVMBlockContext(block.Header(), api.chainContext(ctx), nil)
 		deleteEmptyObjects = chainConfig.IsEIP158(block.Number())
 	)
 	for i, tx := range block.Transactions() {
@@ -644,11 +659,14 @@ func (api *baseAPI) traceBlock(ctx context.Context, block *types.Block, config *
 		}
 	}
 	// Native tracers have low overhead
+	blockCtx, err := core.NewEVMBlockContext(block.Header(), api.chainContext(ctx), nil)
+	if err != nil {
+		return nil, err
+	}
 	var (
 		txs       = block.Transactions()
 		blockHash = block.Hash()
 		is158     = api.backend.ChainConfig().IsEIP158(block.Number())
-		blockCtx  = core.NewEVMBlockContext(block.Header(), api.chainContext(ctx), nil)
 		signer    = types.MakeSigner(api.backend.ChainConfig(), block.Number(), block.Time())
 		results   = make([]*txTraceResult, len(txs))
 	)
@@ -678,10 +696,13 @@ func (api *baseAPI) traceBlock(ctx context.Context, block *types.Block, config *
 // Worker threads take the tasks and the prestate and trace them.
 func (api *baseAPI) traceBlockParallel(ctx context.Context, block *types.Block, statedb *state.StateDB, config *TraceConfig) ([]*txTraceResult, error) {
 	// Execute all the transaction contained within the block concurrently
+	blockCtx, err := core.NewEVMBlockContext(block.Header(), api.chainContext(ctx), nil)
+	if err != nil {
+		return nil, err
+	}
 	var (
 		txs       = block.Transactions()
 		blockHash = block.Hash()
-		blockCtx  = core.NewEVMBlockContext(block.Header(), api.chainContext(ctx), nil)
 		signer    = types.MakeSigner(api.backend.ChainConfig(), block.Number(), block.Time())
 		results   = make([]*txTraceResult, len(txs))
 		pend      sync.WaitGroup
@@ -789,11 +810,14 @@ func (api *FileTracerAPI) standardTraceBlockToFile(ctx context.Context, block *t
 	logConfig.Debug = true
 
 	// Execute transaction, either tracing all or just the requested one
+	vmctx, err := core.NewEVMBlockContext(block.Header(), api.chainContext(ctx), nil)
+	if err != nil {
+		return nil, err
+	}
 	var (
 		dumps       []string
 		signer      = types.MakeSigner(api.backend.ChainConfig(), block.Number(), block.Time())
 		chainConfig = api.backend.ChainConfig()
-		vmctx       = core.NewEVMBlockContext(block.Header(), api.chainContext(ctx), nil)
 		canon       = true
 	)
 	// Check if there are any overrides: the caller may wish to enable a future
@@ -959,7 +983,10 @@ func (api *API) TraceCall(ctx context.Context, args ethapi.TransactionArgs, bloc
 	}
 	defer release()
 
-	vmctx := core.NewEVMBlockContext(block.Header(), api.chainContext(ctx), nil)
+	vmctx, err := core.NewEVMBlockContext(block.Header(), api.chainContext(ctx), nil)
+	if err != nil {
+		return nil, err
+	}
 
 	// Apply the customization rules if required.
 	if config != nil {
@@ -216,12 +216,15 @@ func (b *testBackend) StateAtTransaction(ctx context.Context, block *types.Block
 	if txIndex == 0 && len(block.Transactions()) == 0 {
 		return nil, vm.BlockContext{}, statedb, release, nil
 	}
+	context, err := core.NewEVMBlockContext(block.Header(), b.chain, nil)
+	if err != nil {
+		return nil, vm.BlockContext{}, nil, nil, err
+	}
 	// Recompute transactions up to the target index.
 	signer := types.MakeSigner(b.chainConfig, block.Number(), block.Time())
 	for idx, tx := range block.Transactions() {
 		msg, _ := core.TransactionToMessage(tx, signer, block.BaseFee())
 		txContext := core.NewEVMTxContext(msg)
-		context := core.NewEVMBlockContext(block.Header(), b.chain, nil)
 		if idx == txIndex {
 			return msg, context, statedb, release, nil
 		}
@@ -40,18 +40,20 @@ import (
 	"github.com/MetalBlockchain/libevm/common"
 	"github.com/MetalBlockchain/libevm/common/hexutil"
 	"github.com/MetalBlockchain/libevm/core/types"
+	avalancherpc "github.com/MetalBlockchain/metalgo/utils/rpc"
 )
 
 // Client is a wrapper around rpc.Client that implements geth-specific functionality.
 //
 // If you want to use the standardized Ethereum RPC functionality, use ethclient.Client instead.
 type Client struct {
-	c *rpc.Client
+	c     *rpc.Client
+	admin avalancherpc.EndpointRequester // nil unless the client was dialed
 }
 
 // New creates a client that uses the given RPC client.
 func New(c *rpc.Client) *Client {
-	return &Client{c}
+	return &Client{c: c}
 }
 
 // CreateAccessList tries to create an access list for a specific transaction based on the
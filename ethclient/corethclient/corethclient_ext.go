@@ -0,0 +1,304 @@
+// Copyright (C) 2019-2025, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package corethclient
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math/big"
+
+	"github.com/MetalBlockchain/libevm/common"
+	"github.com/MetalBlockchain/libevm/common/hexutil"
+	"github.com/MetalBlockchain/libevm/core/types"
+	"github.com/MetalBlockchain/metalgo/ids"
+	"github.com/MetalBlockchain/metalgo/utils/crypto/bls"
+	"github.com/MetalBlockchain/metalgo/vms/platformvm/warp"
+
+	"github.com/MetalBlockchain/coreth/ethclient"
+	"github.com/MetalBlockchain/coreth/plugin/evm/config"
+	"github.com/MetalBlockchain/coreth/rpc"
+
+	ethereum "github.com/MetalBlockchain/libevm"
+	avalancherpc "github.com/MetalBlockchain/metalgo/utils/rpc"
+)
+
+var errNoAdminEndpoint = errors.New("client has no admin endpoint")
+
+// Dial connects a client to the chain served at [endpoint].
+func Dial(endpoint string) (*Client, error) {
+	return DialContext(context.Background(), endpoint)
+}
+
+// DialContext connects a client to the chain served at [endpoint], such as
+// http://127.0.0.1:9650/ext/bc/C, through both its JSON-RPC and admin
+// endpoints.
+func DialContext(ctx context.Context, endpoint string) (*Client, error) {
+	c, err := rpc.DialContext(ctx, endpoint+"/rpc")
+	if err != nil {
+		return nil, err
+	}
+	return &Client{
+		c:     c,
+		admin: avalancherpc.NewEndpointRequester(endpoint + "/admin"),
+	}, nil
+}
+
+// Close closes the underlying RPC connection.
+func (ec *Client) Close() {
+	ec.c.Close()
+}
+
+// Price is a suggested fee of a dynamic fee transaction.
+type Price struct {
+	GasTip *big.Int
+	GasFee *big.Int
+}
+
+// PriceOptions are the fees suggested to a user for a transaction to be
+// included slowly, normally or fast.
+type PriceOptions struct {
+	Slow   *Price
+	Normal *Price
+	Fast   *Price
+}
+
+// SuggestPriceOptions returns the suggested fees of a dynamic fee transaction.
+// It returns nil if the chain isn't running with dynamic fees.
+func (ec *Client) SuggestPriceOptions(ctx context.Context) (*PriceOptions, error) {
+	type price struct {
+		GasTip *hexutil.Big `json:"maxPriorityFeePerGas"`
+		GasFee *hexutil.Big `json:"maxFeePerGas"`
+	}
+	type priceOptions struct {
+		Slow   *price `json:"slow"`
+		Normal *price `json:"normal"`
+		Fast   *price `json:"fast"`
+	}
+
+	var res *priceOptions
+	if err := ec.c.CallContext(ctx, &res, "eth_suggestPriceOptions"); err != nil {
+		return nil, err
+	}
+	if res == nil {
+		return nil, nil
+	}
+	toPrice := func(p *price) *Price {
+		if p == nil {
+			return nil
+		}
+		return &Price{
+			GasTip: p.GasTip.ToInt(),
+			GasFee: p.GasFee.ToInt(),
+		}
+	}
+	return &PriceOptions{
+		Slow:   toPrice(res.Slow),
+		Normal: toPrice(res.Normal),
+		Fast:   toPrice(res.Fast),
+	}, nil
+}
+
+// BadBlockReason is the reason a block was rejected.
+type BadBlockReason struct {
+	ChainConfig json.RawMessage `json:"chainConfig"`
+	Receipts    types.Receipts  `json:"receipts"`
+	Number      uint64          `json:"number"`
+	Hash        common.Hash     `json:"hash"`
+	Error       string          `json:"error"`
+}
+
+// BadBlock is a block the node rejected.
+type BadBlock struct {
+	Hash   common.Hash
+	Block  json.RawMessage // the block, as returned by eth_getBlockByHash
+	RLP    []byte
+	Reason *BadBlockReason
+}
+
+// BadBlocks returns the last blocks the node rejected.
+func (ec *Client) BadBlocks(ctx context.Context) ([]*BadBlock, error) {
+	type badBlock struct {
+		Hash   common.Hash     `json:"hash"`
+		Block  json.RawMessage `json:"block"`
+		RLP    string          `json:"rlp"`
+		Reason *BadBlockReason `json:"reason"`
+	}
+
+	var res []*badBlock
+	if err := ec.c.CallContext(ctx, &res, "eth_getBadBlocks"); err != nil {
+		return nil, err
+	}
+	blocks := make([]*BadBlock, len(res))
+	for i, b := range res {
+		// The RLP is replaced by the encoding error if the block can't be
+		// encoded.
+		rlp, _ := hexutil.Decode(b.RLP)
+		blocks[i] = &BadBlock{
+			Hash:   b.Hash,
+			Block:  b.Block,
+			RLP:    rlp,
+			Reason: b.Reason,
+		}
+	}
+	return blocks, nil
+}
+
+// DetailedExecutionResult is the result of a message call, including its
+// error instead of failing the call.
+type DetailedExecutionResult struct {
+	UsedGas    uint64 // Total used gas, including the refunded gas
+	ErrCode    int    // JSON-RPC error code of the execution error, if any
+	Err        string // Execution error, if any
+	ReturnData []byte // Data returned by the EVM, the result or the revert data
+}
+
+// CallDetailed executes a message call transaction like
+// ethclient.CallContract, returning its gas usage and execution error in the
+// result.
+//
+// blockNumber and overrides are the same as in CallContract.
+func (ec *Client) CallDetailed(ctx context.Context, msg ethereum.CallMsg, blockNumber *big.Int, overrides *map[common.Address]OverrideAccount) (*DetailedExecutionResult, error) {
+	type detailedExecutionResult struct {
+		UsedGas    uint64        `json:"gas"`
+		ErrCode    int           `json:"errCode"`
+		Err        string        `json:"err"`
+		ReturnData hexutil.Bytes `json:"returnData"`
+	}
+
+	var res detailedExecutionResult
+	err := ec.c.CallContext(
+		ctx, &res, "eth_callDetailed", toCallArg(msg),
+		ethclient.ToBlockNumArg(blockNumber), toOverrideMap(overrides),
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &DetailedExecutionResult{
+		UsedGas:    res.UsedGas,
+		ErrCode:    res.ErrCode,
+		Err:        res.Err,
+		ReturnData: res.ReturnData,
+	}, nil
+}
+
+// AssetBalanceAt returns the multicoin balance of [assetID] held by [account].
+// The block number can be nil, in which case the balance is taken from the
+// latest known block.
+func (ec *Client) AssetBalanceAt(ctx context.Context, account common.Address, assetID ids.ID, blockNumber *big.Int) (*big.Int, error) {
+	var result hexutil.Big
+	err := ec.c.CallContext(ctx, &result, "eth_getAssetBalance", account, assetID, ethclient.ToBlockNumArg(blockNumber))
+	return (*big.Int)(&result), err
+}
+
+// AssetBalance is the multicoin balance of an asset held by an account.
+type AssetBalance struct {
+	// NormalizedAssetID is the asset ID with the lowest bit of its first byte
+	// set. Asset IDs which only differ in this bit share their balance.
+	NormalizedAssetID ids.ID
+	Balance           *big.Int
+}
+
+// AssetBalancesAt returns the non-zero multicoin balances held by [account],
+// sorted by normalized asset ID. The block number can be nil, in which case the
+// balances are taken from the latest known block.
+//
+// The node must record preimages to enumerate the assets, otherwise an error
+// is returned.
+func (ec *Client) AssetBalancesAt(ctx context.Context, account common.Address, blockNumber *big.Int) ([]AssetBalance, error) {
+	type assetBalance struct {
+		NormalizedAssetID ids.ID       `json:"normalizedAssetID"`
+		Balance           *hexutil.Big `json:"balance"`
+	}
+
+	var res []assetBalance
+	if err := ec.c.CallContext(ctx, &res, "eth_getAssetBalances", account, ethclient.ToBlockNumArg(blockNumber)); err != nil {
+		return nil, err
+	}
+	balances := make([]AssetBalance, len(res))
+	for i, balance := range res {
+		balances[i] = AssetBalance{
+			NormalizedAssetID: balance.NormalizedAssetID,
+			Balance:           balance.Balance.ToInt(),
+		}
+	}
+	return balances, nil
+}
+
+// GetWarpMessage returns the warp message sent by the chain with [messageID].
+func (ec *Client) GetWarpMessage(ctx context.Context, messageID ids.ID) (*warp.UnsignedMessage, error) {
+	var res hexutil.Bytes
+	if err := ec.c.CallContext(ctx, &res, "warp_getMessage", messageID); err != nil {
+		return nil, err
+	}
+	return warp.ParseUnsignedMessage(res)
+}
+
+// GetWarpMessageSignature returns the signature of the node of the warp
+// message sent by the chain with [messageID].
+func (ec *Client) GetWarpMessageSignature(ctx context.Context, messageID ids.ID) (*bls.Signature, error) {
+	var res hexutil.Bytes
+	if err := ec.c.CallContext(ctx, &res, "warp_getMessageSignature", messageID); err != nil {
+		return nil, err
+	}
+	return bls.SignatureFromBytes(res)
+}
+
+// GetWarpBlockSignature returns the signature of the node of the warp message
+// attesting that the block [blockID] was accepted by the chain.
+func (ec *Client) GetWarpBlockSignature(ctx context.Context, blockID ids.ID) (*bls.Signature, error) {
+	var res hexutil.Bytes
+	if err := ec.c.CallContext(ctx, &res, "warp_getBlockSignature", blockID); err != nil {
+		return nil, err
+	}
+	return bls.SignatureFromBytes(res)
+}
+
+// GetWarpMessageAggregateSignature returns the warp message sent by the chain
+// with [messageID], signed by at least [quorumNum] percent of the weight of
+// the validators of [subnetID]. If [subnetID] is empty, the message is signed
+// by the validators of the subnet of the chain.
+func (ec *Client) GetWarpMessageAggregateSignature(ctx context.Context, messageID ids.ID, quorumNum uint64, subnetID ids.ID) (*warp.Message, error) {
+	var res hexutil.Bytes
+	if err := ec.c.CallContext(ctx, &res, "warp_getMessageAggregateSignature", messageID, quorumNum, subnetIDArg(subnetID)); err != nil {
+		return nil, err
+	}
+	return warp.ParseMessage(res)
+}
+
+// GetWarpBlockAggregateSignature returns the warp message attesting that the
+// block [blockID] was accepted by the chain, signed by at least [quorumNum]
+// percent of the weight of the validators of [subnetID]. If [subnetID] is
+// empty, the message is signed by the validators of the subnet of the chain.
+func (ec *Client) GetWarpBlockAggregateSignature(ctx context.Context, blockID ids.ID, quorumNum uint64, subnetID ids.ID) (*warp.Message, error) {
+	var res hexutil.Bytes
+	if err := ec.c.CallContext(ctx, &res, "warp_getBlockAggregateSignature", blockID, quorumNum, subnetIDArg(subnetID)); err != nil {
+		return nil, err
+	}
+	return warp.ParseMessage(res)
+}
+
+func subnetIDArg(subnetID ids.ID) string {
+	if subnetID == ids.Empty {
+		return ""
+	}
+	return subnetID.String()
+}
+
+// GetVMConfig returns the config of the VM running the chain. It requires the
+// admin API of the VM to be enabled and the client to be dialed.
+func (ec *Client) GetVMConfig(ctx context.Context, options ...avalancherpc.Option) (*config.Config, error) {
+	if ec.admin == nil {
+		return nil, errNoAdminEndpoint
+	}
+	type configReply struct {
+		Config *config.Config `json:"config"`
+	}
+
+	res := &configReply{}
+	if err := ec.admin.SendRequest(ctx, "admin.getVMConfig", struct{}{}, res, options...); err != nil {
+		return nil, err
+	}
+	return res.Config, nil
+}
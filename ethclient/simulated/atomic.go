@@ -0,0 +1,504 @@
+// Copyright (C) 2019-2025, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package simulated
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+
+	"github.com/MetalBlockchain/libevm/common"
+	"github.com/MetalBlockchain/libevm/core/state"
+	"github.com/MetalBlockchain/libevm/core/types"
+	"github.com/MetalBlockchain/libevm/log"
+	"github.com/MetalBlockchain/metalgo/codec"
+	"github.com/MetalBlockchain/metalgo/codec/linearcodec"
+	"github.com/MetalBlockchain/metalgo/database/memdb"
+	"github.com/MetalBlockchain/metalgo/ids"
+	"github.com/MetalBlockchain/metalgo/snow"
+	"github.com/MetalBlockchain/metalgo/snow/consensus/snowman"
+	"github.com/MetalBlockchain/metalgo/utils/crypto/secp256k1"
+	"github.com/MetalBlockchain/metalgo/utils/logging"
+	"github.com/MetalBlockchain/metalgo/utils/set"
+	"github.com/MetalBlockchain/metalgo/utils/timer/mockable"
+	"github.com/MetalBlockchain/metalgo/vms/components/avax"
+	"github.com/MetalBlockchain/metalgo/vms/secp256k1fx"
+
+	"github.com/MetalBlockchain/coreth/consensus/dummy"
+	"github.com/MetalBlockchain/coreth/core"
+	"github.com/MetalBlockchain/coreth/core/extstate"
+	"github.com/MetalBlockchain/coreth/params"
+	"github.com/MetalBlockchain/coreth/params/extras"
+	"github.com/MetalBlockchain/coreth/plugin/evm/atomic"
+	"github.com/MetalBlockchain/coreth/plugin/evm/atomic/atomictest"
+	"github.com/MetalBlockchain/coreth/plugin/evm/customtypes"
+	"github.com/MetalBlockchain/coreth/plugin/evm/extension"
+	"github.com/MetalBlockchain/coreth/utils"
+
+	avalancheatomic "github.com/MetalBlockchain/metalgo/chains/atomic"
+	atomicvm "github.com/MetalBlockchain/coreth/plugin/evm/atomic/vm"
+	customheader "github.com/MetalBlockchain/coreth/plugin/evm/header"
+)
+
+const (
+	secpCacheSize = 1024
+
+	// maxAtomicUTXOs is the maximum number of UTXOs read from shared memory
+	// at once.
+	maxAtomicUTXOs = 1024
+)
+
+var (
+	_ secp256k1fx.VM            = (*atomicState)(nil)
+	_ atomicvm.BlockFetcher     = (*atomicState)(nil)
+	_ extension.ExtendedBlock   = (*atomicBlock)(nil)
+	_ atomic.AtomicBlockContext = (*atomicBlockExtension)(nil)
+	_ extension.BlockExtension  = (*atomicBlockExtension)(nil)
+)
+
+var (
+	errNoSharedMemory             = errors.New("backend has no shared memory")
+	errUnknownBlock               = errors.New("unknown block")
+	errConflictingPendingAtomicTx = errors.New("atomic tx conflicts with a pending atomic tx")
+)
+
+// atomicState stands in for the atomic VM of a simulated chain, sharing an
+// in-memory shared memory with the X-Chain. Atomic txs are included in the
+// next blocks committed, and their atomic operations are applied to shared
+// memory when their block is accepted.
+//
+// Assumes Apricot Phase 5 is active, so that blocks include batches of atomic
+// txs.
+type atomicState struct {
+	ctx       *snow.Context
+	config    *params.ChainConfig
+	memories  *atomictest.SharedMemories
+	clock     *mockable.Clock
+	codec     codec.Registry
+	fx        secp256k1fx.Fx
+	secpCache *secp256k1.RecoverCache
+
+	// chain is nil while the accepted blocks are loaded on startup, and set
+	// before any block is built.
+	chain *core.BlockChain
+
+	lock    sync.Mutex
+	pending []*atomic.Tx
+}
+
+// newAtomicState sets the shared memory of [ctx] to a new in-memory shared
+// memory.
+func newAtomicState(ctx *snow.Context, config *params.ChainConfig, clock *mockable.Clock) (*atomicState, error) {
+	memories := atomictest.NewSharedMemories(
+		avalancheatomic.NewMemory(memdb.New()),
+		ctx.ChainID,
+		ctx.XChainID,
+	)
+	ctx.SharedMemory = memories.ThisChain
+
+	a := &atomicState{
+		ctx:       ctx,
+		config:    config,
+		memories:  memories,
+		clock:     clock,
+		codec:     linearcodec.NewDefault(),
+		secpCache: secp256k1.NewRecoverCache(secpCacheSize),
+	}
+	if err := a.fx.Initialize(a); err != nil {
+		return nil, err
+	}
+	if err := a.fx.Bootstrapped(); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+func (a *atomicState) setChain(chain *core.BlockChain) {
+	a.chain = chain
+}
+
+// CodecRegistry implements the secp256k1fx interface
+func (a *atomicState) CodecRegistry() codec.Registry { return a.codec }
+
+// Clock implements the secp256k1fx interface
+func (a *atomicState) Clock() *mockable.Clock { return a.clock }
+
+// Logger implements the secp256k1fx interface
+func (a *atomicState) Logger() logging.Logger { return a.ctx.Log }
+
+func (a *atomicState) consensusCallbacks() dummy.ConsensusCallbacks {
+	return dummy.ConsensusCallbacks{
+		OnFinalizeAndAssemble: a.onFinalizeAndAssemble,
+		OnExtraStateChange:    a.onExtraStateChange,
+	}
+}
+
+func (a *atomicState) rules(header *types.Header) extras.Rules {
+	return *params.GetRulesExtra(a.config.Rules(header.Number, params.IsMergeTODO, header.Time))
+}
+
+// onFinalizeAndAssemble includes the pending atomic txs valid on top of
+// [parent] in the block of [header], as the VM does: a single tx before
+// ApricotPhase5, and as many txs as fit in the atomic gas limit after it.
+// Invalid txs are discarded.
+func (a *atomicState) onFinalizeAndAssemble(
+	header *types.Header,
+	parent *types.Header,
+	statedb *state.StateDB,
+	_ []*types.Transaction,
+) ([]byte, *big.Int, *big.Int, error) {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	var (
+		txs          []*atomic.Tx
+		inputs       set.Set[ids.ID]
+		contribution = new(big.Int)
+		gasUsed      = new(big.Int)
+		gasLimit     uint64
+		rules        = a.rules(header)
+	)
+	if rules.IsApricotPhase5 {
+		var err error
+		gasLimit, err = customheader.RemainingAtomicGasCapacity(params.GetExtra(a.config), parent, header)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+	}
+	for len(a.pending) > 0 && (rules.IsApricotPhase5 || len(txs) == 0) {
+		tx := a.pending[0]
+		txContribution, txGasUsed := new(big.Int), new(big.Int)
+		if rules.IsApricotPhase4 {
+			var err error
+			txContribution, txGasUsed, err = tx.BlockFeeContribution(rules.IsApricotPhase5, a.ctx.AVAXAssetID, header.BaseFee)
+			if err != nil {
+				// The fee paid by [tx] may no longer cover the base fee.
+				log.Debug("discarding atomic tx due to insufficient fee", "txID", tx.ID(), "err", err)
+				a.pending = a.pending[1:]
+				continue
+			}
+		}
+		// Leave [tx] and the following txs to the next blocks.
+		if total := new(big.Int).Add(gasUsed, txGasUsed); rules.IsApricotPhase5 && !utils.BigLessOrEqualUint64(total, gasLimit) {
+			break
+		}
+		a.pending = a.pending[1:]
+
+		if inputs.Overlaps(tx.InputUTXOs()) {
+			log.Debug("discarding atomic tx due to overlapping input utxos", "txID", tx.ID())
+			continue
+		}
+		snapshot := statedb.Snapshot()
+		if err := a.verifyTx(tx, parent.Hash(), header.BaseFee, statedb, rules); err != nil {
+			log.Debug("discarding atomic tx due to failed verification", "txID", tx.ID(), "err", err)
+			statedb.RevertToSnapshot(snapshot)
+			continue
+		}
+		txs = append(txs, tx)
+		inputs.Union(tx.InputUTXOs())
+		contribution.Add(contribution, txContribution)
+		gasUsed.Add(gasUsed, txGasUsed)
+	}
+	if len(txs) == 0 {
+		return nil, nil, nil, nil
+	}
+
+	// Blocks hold a batch of atomic txs since ApricotPhase5.
+	var toMarshal any = txs
+	if !rules.IsApricotPhase5 {
+		toMarshal = txs[0]
+	}
+	txsBytes, err := atomic.Codec.Marshal(atomic.CodecVersion, toMarshal)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to marshal batch of atomic transactions due to %w", err)
+	}
+	if !rules.IsApricotPhase4 {
+		return txsBytes, nil, nil, nil
+	}
+	return txsBytes, contribution, gasUsed, nil
+}
+
+// onExtraStateChange verifies the atomic txs of [block] and applies them to
+// [statedb] as the VM does.
+func (a *atomicState) onExtraStateChange(block *types.Block, parent *types.Header, statedb *state.StateDB) (*big.Int, *big.Int, error) {
+	var (
+		header = block.Header()
+		rules  = a.rules(header)
+	)
+	txs, err := atomic.ExtractAtomicTxs(customtypes.BlockExtData(block), rules.IsApricotPhase5, atomic.Codec)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(txs) == 0 {
+		return nil, nil, nil
+	}
+	// Accepted blocks loaded on startup were verified before.
+	if a.chain != nil {
+		if err := a.verifyTxs(txs, block.ParentHash(), block.BaseFee(), rules); err != nil {
+			return nil, nil, fmt.Errorf("invalid block due to failed semantic verify: %w at height %d", err, block.NumberU64())
+		}
+	}
+	return atomicvm.ApplyTxs(a.ctx, a.config, block, parent, statedb, txs)
+}
+
+// verifyTx verifies that [tx] is valid to be included in a block on top of
+// [parentHash], and applies its state transfer to [statedb].
+func (a *atomicState) verifyTx(tx *atomic.Tx, parentHash common.Hash, baseFee *big.Int, statedb *state.StateDB, rules extras.Rules) error {
+	if err := a.verifyTxs([]*atomic.Tx{tx}, parentHash, baseFee, rules); err != nil {
+		return err
+	}
+	return tx.UnsignedAtomicTx.EVMStateTransfer(a.ctx, extstate.New(statedb))
+}
+
+// verifyTxs verifies that [txs] are valid to be included in a block on top of
+// [parentHash], without conflicting with each other or with the atomic txs of
+// the ancestors of the block which are not accepted yet.
+func (a *atomicState) verifyTxs(txs []*atomic.Tx, parentHash common.Hash, baseFee *big.Int, rules extras.Rules) error {
+	parent, err := a.GetExtendedBlock(context.TODO(), ids.ID(parentHash))
+	if err != nil {
+		return err
+	}
+	verifier := &atomicvm.VerifierBackend{
+		Ctx:          a.ctx,
+		Fx:           &a.fx,
+		Rules:        rules,
+		Bootstrapped: true,
+		BlockFetcher: a,
+		SecpCache:    a.secpCache,
+	}
+	inputs := set.Set[ids.ID]{}
+	for _, tx := range txs {
+		if err := verifier.SemanticVerify(tx, parent, baseFee); err != nil {
+			return err
+		}
+		txInputs := tx.InputUTXOs()
+		if inputs.Overlaps(txInputs) {
+			return atomicvm.ErrConflictingAtomicInputs
+		}
+		inputs.Union(txInputs)
+	}
+	return nil
+}
+
+// accept applies the atomic operations of the txs of [block] to shared memory.
+func (a *atomicState) accept(block *types.Block) error {
+	txs, err := atomic.ExtractAtomicTxs(customtypes.BlockExtData(block), a.rules(block.Header()).IsApricotPhase5, atomic.Codec)
+	if err != nil {
+		return err
+	}
+	for _, tx := range txs {
+		chainID, requests, err := tx.AtomicOps()
+		if err != nil {
+			return err
+		}
+		if err := a.ctx.SharedMemory.Apply(map[ids.ID]*avalancheatomic.Requests{chainID: requests}); err != nil {
+			return fmt.Errorf("failed to apply atomic operations of tx %s: %w", tx.ID(), err)
+		}
+	}
+	return nil
+}
+
+// GetExtendedBlock implements the atomicvm.BlockFetcher interface
+func (a *atomicState) GetExtendedBlock(_ context.Context, blkID ids.ID) (extension.ExtendedBlock, error) {
+	block := a.chain.GetBlockByHash(common.Hash(blkID))
+	if block == nil {
+		return nil, fmt.Errorf("%w: %s", errUnknownBlock, blkID)
+	}
+	txs, err := atomic.ExtractAtomicTxs(customtypes.BlockExtData(block), a.rules(block.Header()).IsApricotPhase5, atomic.Codec)
+	if err != nil {
+		return nil, err
+	}
+	return &atomicBlock{block: block, txs: txs}, nil
+}
+
+// LastAcceptedExtendedBlock implements the atomicvm.BlockFetcher interface.
+// Only the height of the returned block is read.
+func (a *atomicState) LastAcceptedExtendedBlock() extension.ExtendedBlock {
+	return &atomicBlock{block: a.chain.LastAcceptedBlock()}
+}
+
+// atomicBlock is a block of the simulated chain as walked by the atomic tx
+// verifier, looking for conflicting atomic txs in the ancestors of a block.
+// Only the methods called by the verifier are implemented.
+type atomicBlock struct {
+	snowman.Block
+	block *types.Block
+	txs   []*atomic.Tx
+}
+
+func (b *atomicBlock) ID() ids.ID { return ids.ID(b.block.Hash()) }
+
+func (b *atomicBlock) Parent() ids.ID { return ids.ID(b.block.ParentHash()) }
+
+func (b *atomicBlock) Height() uint64 { return b.block.NumberU64() }
+
+func (b *atomicBlock) GetEthBlock() *types.Block { return b.block }
+
+func (b *atomicBlock) GetBlockExtension() extension.BlockExtension {
+	return &atomicBlockExtension{txs: b.txs}
+}
+
+// atomicBlockExtension exposes the atomic txs of an atomicBlock.
+type atomicBlockExtension struct {
+	extension.BlockExtension
+	txs []*atomic.Tx
+}
+
+func (e *atomicBlockExtension) AtomicTxs() []*atomic.Tx { return e.txs }
+
+func (n *Backend) atomicState() (*atomicState, error) {
+	if n.atomic == nil {
+		return nil, errNoSharedMemory
+	}
+	return n.atomic, nil
+}
+
+// XChainID returns the ID of the chain sharing memory with the simulated
+// chain.
+func (n *Backend) XChainID() ids.ID {
+	return n.snowCtx.XChainID
+}
+
+// AVAXAssetID returns the ID of the asset held by the accounts of the
+// simulated chain as their balances.
+func (n *Backend) AVAXAssetID() ids.ID {
+	return n.snowCtx.AVAXAssetID
+}
+
+// SharedMemories returns the views of the simulated chain and of the X-Chain
+// on their shared memory.
+func (n *Backend) SharedMemories() (*atomictest.SharedMemories, error) {
+	a, err := n.atomicState()
+	if err != nil {
+		return nil, err
+	}
+	return a.memories, nil
+}
+
+// AddUTXO exports from the X-Chain to the simulated chain a new UTXO of
+// [amount] of [assetID] owned by [owner], to be imported.
+func (n *Backend) AddUTXO(assetID ids.ID, amount uint64, owner ids.ShortID) (*avax.UTXO, error) {
+	a, err := n.atomicState()
+	if err != nil {
+		return nil, err
+	}
+	utxo := &avax.UTXO{
+		UTXOID: avax.UTXOID{TxID: ids.GenerateTestID()},
+		Asset:  avax.Asset{ID: assetID},
+		Out: &secp256k1fx.TransferOutput{
+			Amt: amount,
+			OutputOwners: secp256k1fx.OutputOwners{
+				Threshold: 1,
+				Addrs:     []ids.ShortID{owner},
+			},
+		},
+	}
+	utxoBytes, err := atomic.Codec.Marshal(atomic.CodecVersion, utxo)
+	if err != nil {
+		return nil, err
+	}
+	inputID := utxo.InputID()
+	err = a.memories.PeerChain.Apply(map[ids.ID]*avalancheatomic.Requests{
+		a.ctx.ChainID: {PutRequests: []*avalancheatomic.Element{{
+			Key:    inputID[:],
+			Value:  utxoBytes,
+			Traits: [][]byte{owner.Bytes()},
+		}}},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return utxo, nil
+}
+
+// ExportedUTXOs returns the UTXOs exported by the simulated chain to the
+// X-Chain owned by [addrs].
+func (n *Backend) ExportedUTXOs(addrs ...ids.ShortID) ([]*avax.UTXO, error) {
+	a, err := n.atomicState()
+	if err != nil {
+		return nil, err
+	}
+	utxos, _, _, err := avax.GetAtomicUTXOs(a.memories.PeerChain, atomic.Codec, a.ctx.ChainID, set.Of(addrs...), ids.ShortEmpty, ids.Empty, maxAtomicUTXOs)
+	return utxos, err
+}
+
+// atomicTxContext returns the head of the chain, its state, the rules of the
+// next block and an estimate of its base fee.
+func (a *atomicState) atomicTxContext() (*types.Header, *state.StateDB, extras.Rules, *big.Int, error) {
+	head := a.chain.CurrentHeader()
+	statedb, err := a.chain.StateAt(head.Root)
+	if err != nil {
+		return nil, nil, extras.Rules{}, nil, err
+	}
+	// The base fee decreases over time, so estimating it at the time of the
+	// head covers the base fee of the next block.
+	baseFee, err := customheader.EstimateNextBaseFee(params.GetExtra(a.config), head, head.Time)
+	if err != nil {
+		return nil, nil, extras.Rules{}, nil, err
+	}
+	return head, statedb, a.rules(head), baseFee, nil
+}
+
+// NewImportTx returns a tx importing to [to] every UTXO exported by the
+// X-Chain to the simulated chain owned by the addresses of [keys].
+func (n *Backend) NewImportTx(to common.Address, keys ...*secp256k1.PrivateKey) (*atomic.Tx, error) {
+	a, err := n.atomicState()
+	if err != nil {
+		return nil, err
+	}
+	_, _, rules, baseFee, err := a.atomicTxContext()
+	if err != nil {
+		return nil, err
+	}
+	kc := secp256k1fx.NewKeychain(keys...)
+	utxos, _, _, err := avax.GetAtomicUTXOs(a.ctx.SharedMemory, atomic.Codec, a.ctx.XChainID, kc.Addresses(), ids.ShortEmpty, ids.Empty, maxAtomicUTXOs)
+	if err != nil {
+		return nil, fmt.Errorf("problem retrieving atomic UTXOs: %w", err)
+	}
+	return atomic.NewImportTx(a.ctx, rules, a.clock.Unix(), a.ctx.XChainID, to, baseFee, kc, utxos)
+}
+
+// NewExportTx returns a tx exporting [amount] of [assetID] from the accounts
+// of [keys] to [to] on the X-Chain.
+func (n *Backend) NewExportTx(assetID ids.ID, amount uint64, to ids.ShortID, keys ...*secp256k1.PrivateKey) (*atomic.Tx, error) {
+	a, err := n.atomicState()
+	if err != nil {
+		return nil, err
+	}
+	_, statedb, rules, baseFee, err := a.atomicTxContext()
+	if err != nil {
+		return nil, err
+	}
+	return atomic.NewExportTx(a.ctx, rules, extstate.New(statedb), assetID, amount, a.ctx.XChainID, to, baseFee, keys)
+}
+
+// IssueAtomicTx verifies [tx] on top of the head of the chain and adds it to
+// the atomic txs included in the next blocks committed.
+func (n *Backend) IssueAtomicTx(tx *atomic.Tx) error {
+	a, err := n.atomicState()
+	if err != nil {
+		return err
+	}
+	head, statedb, rules, baseFee, err := a.atomicTxContext()
+	if err != nil {
+		return err
+	}
+
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	inputs := tx.InputUTXOs()
+	for _, pending := range a.pending {
+		if pending.ID() == tx.ID() || pending.InputUTXOs().Overlaps(inputs) {
+			return fmt.Errorf("%w: %s", errConflictingPendingAtomicTx, pending.ID())
+		}
+	}
+	if err := a.verifyTx(tx, head.Hash(), baseFee, statedb, rules); err != nil {
+		return err
+	}
+	a.pending = append(a.pending, tx)
+	return nil
+}
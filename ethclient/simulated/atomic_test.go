@@ -0,0 +1,126 @@
+// Copyright (C) 2019-2025, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package simulated
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/MetalBlockchain/libevm/common"
+	"github.com/MetalBlockchain/libevm/core/types"
+	"github.com/MetalBlockchain/metalgo/database"
+	"github.com/MetalBlockchain/metalgo/utils/crypto/secp256k1"
+	"github.com/MetalBlockchain/metalgo/vms/secp256k1fx"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAtomicImportExport(t *testing.T) {
+	require := require.New(t)
+	key, err := secp256k1.NewPrivateKey()
+	require.NoError(err)
+	sim := NewBackendWithSharedMemory(types.GenesisAlloc{})
+	defer sim.Close()
+	client := sim.Client()
+	ctx := context.Background()
+
+	// Import a UTXO exported by the X-Chain.
+	utxo, err := sim.AddUTXO(sim.AVAXAssetID(), 50_000_000, key.Address())
+	require.NoError(err)
+	importTx, err := sim.NewImportTx(key.EthAddress(), key)
+	require.NoError(err)
+	require.NoError(sim.IssueAtomicTx(importTx))
+	require.ErrorIs(sim.IssueAtomicTx(importTx), errConflictingPendingAtomicTx)
+	sim.Commit(true)
+
+	balance, err := client.BalanceAt(ctx, key.EthAddress(), nil)
+	require.NoError(err)
+	require.Positive(balance.Sign())
+	memories, err := sim.SharedMemories()
+	require.NoError(err)
+	inputID := utxo.InputID()
+	_, err = memories.ThisChain.Get(sim.XChainID(), [][]byte{inputID[:]})
+	require.ErrorIs(err, database.ErrNotFound)
+
+	// The imported UTXO cannot be imported again.
+	_, err = sim.AddUTXO(sim.AVAXAssetID(), 1_000_000, key.Address())
+	require.NoError(err)
+	require.Error(sim.IssueAtomicTx(importTx))
+
+	// Export to the X-Chain.
+	exportTx, err := sim.NewExportTx(sim.AVAXAssetID(), 10_000_000, key.Address(), key)
+	require.NoError(err)
+	require.NoError(sim.IssueAtomicTx(exportTx))
+	sim.Commit(true)
+
+	utxos, err := sim.ExportedUTXOs(key.Address())
+	require.NoError(err)
+	require.Len(utxos, 1)
+	require.Equal(sim.AVAXAssetID(), utxos[0].AssetID())
+	out, ok := utxos[0].Out.(*secp256k1fx.TransferOutput)
+	require.True(ok)
+	require.Equal(uint64(10_000_000), out.Amount())
+
+	// Atomic txs are not applied to shared memory until their block is
+	// accepted.
+	exportTx, err = sim.NewExportTx(sim.AVAXAssetID(), 5_000_000, key.Address(), key)
+	require.NoError(err)
+	require.NoError(sim.IssueAtomicTx(exportTx))
+	sim.Commit(false)
+	utxos, err = sim.ExportedUTXOs(key.Address())
+	require.NoError(err)
+	require.Len(utxos, 1)
+	sim.Commit(true)
+	utxos, err = sim.ExportedUTXOs(key.Address())
+	require.NoError(err)
+	require.Len(utxos, 2)
+}
+
+func TestNoSharedMemory(t *testing.T) {
+	sim := NewBackend(types.GenesisAlloc{})
+	defer sim.Close()
+
+	_, err := sim.SharedMemories()
+	require.ErrorIs(t, err, errNoSharedMemory)
+	_, err = sim.NewExportTx(sim.AVAXAssetID(), 1, [20]byte{}, nil)
+	require.ErrorIs(t, err, errNoSharedMemory)
+}
+
+func TestAtomicTxInsufficientFee(t *testing.T) {
+	require := require.New(t)
+	key, err := secp256k1.NewPrivateKey()
+	require.NoError(err)
+	sim := NewBackendWithSharedMemory(types.GenesisAlloc{})
+	defer sim.Close()
+
+	_, err = sim.AddUTXO(sim.AVAXAssetID(), 50_000_000, key.Address())
+	require.NoError(err)
+	importTx, err := sim.NewImportTx(key.EthAddress(), key)
+	require.NoError(err)
+	require.NoError(sim.IssueAtomicTx(importTx))
+
+	// The fee paid by the tx does not cover a higher base fee, so it is
+	// discarded rather than blocking the following blocks.
+	a, err := sim.atomicState()
+	require.NoError(err)
+	head, statedb, _, baseFee, err := a.atomicTxContext()
+	require.NoError(err)
+	header := types.CopyHeader(head)
+	header.Number = new(big.Int).Add(head.Number, common.Big1)
+	header.ParentHash = head.Hash()
+	header.GasUsed = 0
+	header.BaseFee = new(big.Int).Mul(baseFee, big.NewInt(100))
+	txsBytes, _, _, err := a.onFinalizeAndAssemble(header, head, statedb, nil)
+	require.NoError(err)
+	require.Nil(txsBytes)
+	require.Empty(a.pending)
+
+	// The tx can be issued again, and included at the current base fee.
+	require.NoError(sim.IssueAtomicTx(importTx))
+	sim.Commit(true)
+	require.Empty(a.pending)
+	balance, err := sim.Client().BalanceAt(context.Background(), key.EthAddress(), nil)
+	require.NoError(err)
+	require.Positive(balance.Sign())
+}
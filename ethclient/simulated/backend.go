@@ -32,6 +32,8 @@ import (
 	"math/big"
 	"time"
 
+	"github.com/MetalBlockchain/metalgo/ids"
+	"github.com/MetalBlockchain/metalgo/snow"
 	"github.com/MetalBlockchain/metalgo/utils/timer/mockable"
 	"github.com/MetalBlockchain/coreth/consensus/dummy"
 	"github.com/MetalBlockchain/coreth/constants"
@@ -42,7 +44,10 @@ import (
 	"github.com/MetalBlockchain/coreth/interfaces"
 	"github.com/MetalBlockchain/coreth/node"
 	"github.com/MetalBlockchain/coreth/params"
+	"github.com/MetalBlockchain/coreth/params/extras"
+	warpcontract "github.com/MetalBlockchain/coreth/precompile/contracts/warp"
 	"github.com/MetalBlockchain/coreth/rpc"
+	"github.com/MetalBlockchain/coreth/utils"
 	ethereum "github.com/MetalBlockchain/libevm"
 	"github.com/MetalBlockchain/libevm/common"
 	"github.com/MetalBlockchain/libevm/core/rawdb"
@@ -82,19 +87,37 @@ type simClient struct {
 // Backend is a simulated blockchain. You can use it to test your contracts or
 // other code that interacts with the Ethereum chain.
 type Backend struct {
-	eth    *eth.Ethereum
-	client simClient
-	clock  *mockable.Clock
-	server *rpc.Server
+	eth     *eth.Ethereum
+	client  simClient
+	clock   *mockable.Clock
+	server  *rpc.Server
+	snowCtx *snow.Context
+	warp    *warpState
+	atomic  *atomicState // nil if the backend has no shared memory
 }
 
 // NewBackend creates a new simulated blockchain that can be used as a backend for
 // contract bindings in unit tests.
 //
-// A simulated backend always uses chainID 1337.
+// A simulated backend always uses chainID 1337, and enables the warp precompile
+// to receive messages signed by its warp validators.
 func NewBackend(alloc types.GenesisAlloc, options ...func(nodeConf *node.Config, ethConf *ethconfig.Config)) *Backend {
-	chainConfig := *params.TestChainConfig
+	return newBackend(alloc, false, options)
+}
+
+// NewBackendWithSharedMemory creates a new simulated blockchain sharing an
+// in-memory shared memory with the X-Chain, to test import and export
+// transactions in addition to contracts.
+func NewBackendWithSharedMemory(alloc types.GenesisAlloc, options ...func(nodeConf *node.Config, ethConf *ethconfig.Config)) *Backend {
+	return newBackend(alloc, true, options)
+}
+
+func newBackend(alloc types.GenesisAlloc, sharedMemory bool, options []func(nodeConf *node.Config, ethConf *ethconfig.Config)) *Backend {
+	chainConfig := params.Copy(params.TestChainConfig)
 	chainConfig.ChainID = big.NewInt(1337)
+	params.GetExtra(&chainConfig).PrecompileUpgrades = []extras.PrecompileUpgrade{
+		{Config: warpcontract.NewDefaultConfig(utils.NewUint64(0))},
+	}
 
 	// Create the default configurations for the outer node shell and the Ethereum
 	// service to mutate with the options afterwards
@@ -118,7 +141,7 @@ func NewBackend(alloc types.GenesisAlloc, options ...func(nodeConf *node.Config,
 	if err != nil {
 		panic(err) // this should never happen
 	}
-	sim, err := newWithNode(stack, &ethConf, 0)
+	sim, err := newWithNode(stack, &ethConf, 0, sharedMemory)
 	if err != nil {
 		panic(err) // this should never happen
 	}
@@ -127,13 +150,31 @@ func NewBackend(alloc types.GenesisAlloc, options ...func(nodeConf *node.Config,
 
 // newWithNode sets up a simulated backend on an existing node. The provided node
 // must not be started and will be started by this method.
-func newWithNode(stack *node.Node, conf *eth.Config, blockPeriod uint64) (*Backend, error) {
+func newWithNode(stack *node.Node, conf *eth.Config, blockPeriod uint64, sharedMemory bool) (*Backend, error) {
 	chaindb := rawdb.NewMemoryDatabase()
 	clock := &mockable.Clock{}
 	clock.Set(time.Unix(0, 0))
 
-	engine := dummy.NewFakerWithModeAndClock(
-		dummy.Mode{ModeSkipCoinbase: true}, clock,
+	snowCtx := newSnowContext()
+	warp, err := newWarpState(snowCtx)
+	if err != nil {
+		return nil, err
+	}
+	var (
+		shared    *atomicState
+		callbacks dummy.ConsensusCallbacks
+	)
+	if sharedMemory {
+		shared, err = newAtomicState(snowCtx, conf.Genesis.Config, clock)
+		if err != nil {
+			return nil, err
+		}
+		callbacks = shared.consensusCallbacks()
+	}
+	params.GetExtra(conf.Genesis.Config).SnowCtx = snowCtx
+
+	engine := dummy.NewDummyEngine(
+		callbacks, dummy.Mode{ModeSkipCoinbase: true}, clock, nil,
 	)
 
 	backend, err := eth.New(
@@ -143,6 +184,9 @@ func newWithNode(stack *node.Node, conf *eth.Config, blockPeriod uint64) (*Backe
 	if err != nil {
 		return nil, err
 	}
+	if shared != nil {
+		shared.setChain(backend.BlockChain())
+	}
 	server := rpc.NewServer(0)
 	for _, api := range backend.APIs() {
 		if err := server.RegisterName(api.Namespace, api.Service); err != nil {
@@ -150,10 +194,13 @@ func newWithNode(stack *node.Node, conf *eth.Config, blockPeriod uint64) (*Backe
 		}
 	}
 	return &Backend{
-		eth:    backend,
-		client: simClient{ethclient.NewClient(rpc.DialInProc(server))},
-		clock:  clock,
-		server: server,
+		eth:     backend,
+		client:  simClient{ethclient.NewClient(rpc.DialInProc(server))},
+		clock:   clock,
+		server:  server,
+		snowCtx: snowCtx,
+		warp:    warp,
+		atomic:  shared,
 	}, nil
 }
 
@@ -185,7 +232,7 @@ func (n *Backend) buildBlock(accept bool, gap uint64) (common.Hash, error) {
 	}
 
 	n.clock.Set(time.Unix(int64(parent.Time+gap), 0))
-	block, err := n.eth.Miner().GenerateBlock(nil)
+	block, err := n.eth.Miner().GenerateBlock(n.warp.predicateContext())
 	if err != nil {
 		return common.Hash{}, err
 	}
@@ -219,6 +266,11 @@ func (n *Backend) acceptAncestors(block *types.Block) error {
 		if err := chain.Accept(toAccept[i]); err != nil {
 			return err
 		}
+		if n.atomic != nil {
+			if err := n.atomic.accept(toAccept[i]); err != nil {
+				return err
+			}
+		}
 	}
 	return nil
 }
@@ -284,6 +336,12 @@ func (n *Backend) AdjustTime(adjustment time.Duration) error {
 	return err
 }
 
+// BlockchainID returns the ID of the simulated chain, as returned by the warp
+// precompile's getBlockchainID.
+func (n *Backend) BlockchainID() ids.ID {
+	return n.snowCtx.ChainID
+}
+
 // Client returns a client that accesses the simulated chain.
 func (n *Backend) Client() Client {
 	return n.client
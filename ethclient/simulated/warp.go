@@ -0,0 +1,258 @@
+// Copyright (C) 2019-2025, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package simulated
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/MetalBlockchain/libevm/common"
+	"github.com/MetalBlockchain/libevm/core/types"
+	"github.com/MetalBlockchain/metalgo/ids"
+	"github.com/MetalBlockchain/metalgo/snow"
+	"github.com/MetalBlockchain/metalgo/snow/engine/snowman/block"
+	"github.com/MetalBlockchain/metalgo/snow/validators"
+	"github.com/MetalBlockchain/metalgo/snow/validators/validatorstest"
+	"github.com/MetalBlockchain/metalgo/utils/constants"
+	"github.com/MetalBlockchain/metalgo/utils/crypto/bls"
+	"github.com/MetalBlockchain/metalgo/utils/crypto/bls/signer/localsigner"
+	"github.com/MetalBlockchain/metalgo/utils/logging"
+	"github.com/MetalBlockchain/metalgo/utils/set"
+	"github.com/MetalBlockchain/metalgo/vms/evm/predicate"
+	"github.com/MetalBlockchain/metalgo/vms/platformvm/warp"
+	"github.com/MetalBlockchain/metalgo/vms/platformvm/warp/payload"
+
+	"github.com/MetalBlockchain/coreth/precompile/precompileconfig"
+
+	warpcontract "github.com/MetalBlockchain/coreth/precompile/contracts/warp"
+)
+
+const (
+	// defaultWarpValidators is the size of the validator set of a new backend.
+	defaultWarpValidators = 5
+
+	// warpPChainHeight is the P-Chain height blocks are built at. The
+	// validator set is the same at every height.
+	warpPChainHeight = 1
+)
+
+var (
+	blockchainID = ids.ID{'s', 'i', 'm', 'u', 'l', 'a', 't', 'e', 'd'}
+	xChainID     = ids.ID{'x', 'c', 'h', 'a', 'i', 'n'}
+	avaxAssetID  = ids.ID{'a', 'v', 'a', 'x'}
+
+	errNoWarpValidators        = errors.New("no warp validators")
+	errUnknownWarpValidator    = errors.New("signer is not a warp validator")
+	errDuplicateWarpNodeID     = errors.New("duplicate warp validator node ID")
+	errZeroWarpValidatorWeight = errors.New("warp validator weight must be positive")
+)
+
+// WarpValidator is a member of the validator set verifying the warp messages
+// received by a simulated backend.
+type WarpValidator struct {
+	NodeID ids.NodeID
+	Signer bls.Signer
+	Weight uint64
+}
+
+// NewWarpValidators returns [n] validators with random keys and equal weights.
+func NewWarpValidators(n int) ([]*WarpValidator, error) {
+	vdrs := make([]*WarpValidator, n)
+	for i := range vdrs {
+		signer, err := localsigner.New()
+		if err != nil {
+			return nil, err
+		}
+		vdrs[i] = &WarpValidator{
+			NodeID: ids.GenerateTestNodeID(),
+			Signer: signer,
+			Weight: 100,
+		}
+	}
+	return vdrs, nil
+}
+
+// newSnowContext returns the context of the simulated chain, validated by
+// the Primary Network of a local network.
+func newSnowContext() *snow.Context {
+	return &snow.Context{
+		NetworkID:   constants.UnitTestID,
+		SubnetID:    constants.PrimaryNetworkID,
+		ChainID:     blockchainID,
+		CChainID:    blockchainID,
+		XChainID:    xChainID,
+		AVAXAssetID: avaxAssetID,
+		Log:         logging.NoLog{},
+	}
+}
+
+// warpState is the fake network the simulated chain receives warp messages
+// from. Every chain is considered to be validated by the same validator set.
+type warpState struct {
+	snowCtx *snow.Context
+
+	lock       sync.RWMutex
+	validators []*WarpValidator
+}
+
+// newWarpState sets the validator state of [snowCtx] to a new set of
+// validators of every chain.
+func newWarpState(snowCtx *snow.Context) (*warpState, error) {
+	vdrs, err := NewWarpValidators(defaultWarpValidators)
+	if err != nil {
+		return nil, err
+	}
+	s := &warpState{
+		snowCtx:    snowCtx,
+		validators: vdrs,
+	}
+	snowCtx.ValidatorState = &validatorstest.State{
+		GetSubnetIDF: func(context.Context, ids.ID) (ids.ID, error) {
+			return constants.PrimaryNetworkID, nil
+		},
+		GetValidatorSetF: s.getValidatorSet,
+	}
+	return s, nil
+}
+
+func (s *warpState) getValidatorSet(context.Context, uint64, ids.ID) (map[ids.NodeID]*validators.GetValidatorOutput, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	vdrs := make(map[ids.NodeID]*validators.GetValidatorOutput, len(s.validators))
+	for _, vdr := range s.validators {
+		vdrs[vdr.NodeID] = &validators.GetValidatorOutput{
+			NodeID:    vdr.NodeID,
+			PublicKey: vdr.Signer.PublicKey(),
+			Weight:    vdr.Weight,
+		}
+	}
+	return vdrs, nil
+}
+
+// predicateContext returns the context the predicates of the transactions of
+// a new block are verified in.
+func (s *warpState) predicateContext() *precompileconfig.PredicateContext {
+	return &precompileconfig.PredicateContext{
+		SnowCtx: s.snowCtx,
+		ProposerVMBlockCtx: &block.Context{
+			PChainHeight: warpPChainHeight,
+		},
+	}
+}
+
+// SetWarpValidators replaces the validator set verifying the warp messages
+// received by the simulated chain. The predicates of the transactions of the
+// blocks committed afterwards are verified against [vdrs].
+func (n *Backend) SetWarpValidators(vdrs []*WarpValidator) error {
+	if len(vdrs) == 0 {
+		return errNoWarpValidators
+	}
+	nodeIDs := set.NewSet[ids.NodeID](len(vdrs))
+	for _, vdr := range vdrs {
+		if vdr.Weight == 0 {
+			return fmt.Errorf("%w: %s", errZeroWarpValidatorWeight, vdr.NodeID)
+		}
+		if nodeIDs.Contains(vdr.NodeID) {
+			return fmt.Errorf("%w: %s", errDuplicateWarpNodeID, vdr.NodeID)
+		}
+		nodeIDs.Add(vdr.NodeID)
+	}
+
+	n.warp.lock.Lock()
+	defer n.warp.lock.Unlock()
+	n.warp.validators = append([]*WarpValidator(nil), vdrs...)
+	return nil
+}
+
+// WarpValidators returns the validator set verifying the warp messages
+// received by the simulated chain.
+func (n *Backend) WarpValidators() []*WarpValidator {
+	n.warp.lock.RLock()
+	defer n.warp.lock.RUnlock()
+	return append([]*WarpValidator(nil), n.warp.validators...)
+}
+
+// NewWarpMessage returns an unsigned warp message with an AddressedCall
+// [payloadBytes] sent by [sourceAddress] on [sourceChainID] in the network of
+// the simulated chain.
+func (n *Backend) NewWarpMessage(sourceChainID ids.ID, sourceAddress common.Address, payloadBytes []byte) (*warp.UnsignedMessage, error) {
+	addressedCall, err := payload.NewAddressedCall(sourceAddress.Bytes(), payloadBytes)
+	if err != nil {
+		return nil, err
+	}
+	return warp.NewUnsignedMessage(n.warp.snowCtx.NetworkID, sourceChainID, addressedCall.Bytes())
+}
+
+// SignWarpMessage signs [unsignedMessage] with the keys of [signers], or of
+// every warp validator if none is given. Signing with less than the quorum of
+// the validator weight produces a message failing verification. Each signer
+// may only be given once.
+func (n *Backend) SignWarpMessage(unsignedMessage *warp.UnsignedMessage, signers ...*WarpValidator) (*warp.Message, error) {
+	if len(signers) == 0 {
+		signers = n.WarpValidators()
+	}
+	signing := set.NewSet[ids.NodeID](len(signers))
+	for _, signer := range signers {
+		if signing.Contains(signer.NodeID) {
+			return nil, fmt.Errorf("%w: %s", errDuplicateWarpNodeID, signer.NodeID)
+		}
+		signing.Add(signer.NodeID)
+	}
+
+	vdrSet, err := warp.GetCanonicalValidatorSetFromChainID(
+		context.Background(),
+		n.warp.snowCtx.ValidatorState,
+		warpPChainHeight,
+		unsignedMessage.SourceChainID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	var (
+		signerBits = set.NewBits()
+		signatures []*bls.Signature
+		signed     = set.NewSet[ids.NodeID](len(signers))
+	)
+	for i, vdr := range vdrSet.Validators {
+		for _, nodeID := range vdr.NodeIDs {
+			if signing.Contains(nodeID) {
+				signerBits.Add(i)
+				signed.Add(nodeID)
+			}
+		}
+	}
+	for _, signer := range signers {
+		if !signed.Contains(signer.NodeID) {
+			return nil, fmt.Errorf("%w: %s", errUnknownWarpValidator, signer.NodeID)
+		}
+		sig, err := signer.Signer.Sign(unsignedMessage.Bytes())
+		if err != nil {
+			return nil, err
+		}
+		signatures = append(signatures, sig)
+	}
+	aggregateSignature, err := bls.AggregateSignatures(signatures)
+	if err != nil {
+		return nil, err
+	}
+
+	signature := &warp.BitSetSignature{
+		Signers: signerBits.Bytes(),
+	}
+	copy(signature.Signature[:], bls.SignatureToBytes(aggregateSignature))
+	return warp.NewMessage(unsignedMessage, signature)
+}
+
+// AppendWarpPredicate returns [accessList] with [message] attached as a
+// predicate of the warp precompile. The predicates of a transaction are read
+// by getVerifiedWarpMessage in the order they were appended.
+func AppendWarpPredicate(accessList types.AccessList, message *warp.Message) types.AccessList {
+	return append(accessList, types.AccessTuple{
+		Address:     warpcontract.ContractAddress,
+		StorageKeys: predicate.New(message.Bytes()),
+	})
+}
@@ -0,0 +1,116 @@
+// Copyright (C) 2019-2025, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package simulated
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/MetalBlockchain/libevm/common"
+	"github.com/MetalBlockchain/libevm/core/types"
+	"github.com/MetalBlockchain/libevm/core/vm"
+	ethparams "github.com/MetalBlockchain/libevm/params"
+	"github.com/MetalBlockchain/metalgo/ids"
+	"github.com/MetalBlockchain/metalgo/vms/platformvm/warp"
+	"github.com/stretchr/testify/require"
+
+	warpcontract "github.com/MetalBlockchain/coreth/precompile/contracts/warp"
+)
+
+// warpReceiverCode stores in slot 0 whether the first warp message of the
+// calling transaction is verified.
+func warpReceiverCode() []byte {
+	selector := warpcontract.WarpABI.Methods["getVerifiedWarpMessage"].ID
+	code := []byte{byte(vm.PUSH4)}
+	code = append(code, selector...)
+	code = append(code,
+		byte(vm.PUSH1), 0xe0, byte(vm.SHL), byte(vm.PUSH1), 0, byte(vm.MSTORE), // getVerifiedWarpMessage(0)
+		byte(vm.PUSH1), 0x40, byte(vm.PUSH1), 0, // retSize, retOffset
+		byte(vm.PUSH1), 0x24, byte(vm.PUSH1), 0, // argsSize, argsOffset
+		byte(vm.PUSH1), 0, // value
+		byte(vm.PUSH20),
+	)
+	code = append(code, warpcontract.ContractAddress.Bytes()...)
+	return append(code,
+		byte(vm.GAS), byte(vm.CALL), byte(vm.POP),
+		byte(vm.PUSH1), 0x20, byte(vm.MLOAD), // valid
+		byte(vm.PUSH1), 0, byte(vm.SSTORE),
+		byte(vm.STOP),
+	)
+}
+
+func TestWarpMessage(t *testing.T) {
+	require := require.New(t)
+	receiver := common.Address{0x01, 0x02}
+	sim := NewBackend(types.GenesisAlloc{
+		testAddr: {Balance: big.NewInt(10000000000000000)},
+		receiver: {Code: warpReceiverCode()},
+	})
+	defer sim.Close()
+	client := sim.Client()
+	ctx := context.Background()
+
+	vdrs, err := NewWarpValidators(4)
+	require.NoError(err)
+	require.NoError(sim.SetWarpValidators(vdrs))
+	require.Len(sim.WarpValidators(), 4)
+
+	unsignedMessage, err := sim.NewWarpMessage(ids.GenerateTestID(), common.Address{0xaa}, []byte("hello"))
+	require.NoError(err)
+
+	receive := func(message *warp.Message) bool {
+		t.Helper()
+		head, err := client.HeaderByNumber(ctx, nil)
+		require.NoError(err)
+		nonce, err := client.NonceAt(ctx, testAddr, nil)
+		require.NoError(err)
+		chainID, err := client.ChainID(ctx)
+		require.NoError(err)
+		tx, err := types.SignTx(types.NewTx(&types.DynamicFeeTx{
+			ChainID:    chainID,
+			Nonce:      nonce,
+			GasTipCap:  big.NewInt(ethparams.GWei),
+			GasFeeCap:  new(big.Int).Add(head.BaseFee, big.NewInt(ethparams.GWei)),
+			Gas:        1_000_000,
+			To:         &receiver,
+			AccessList: AppendWarpPredicate(nil, message),
+		}), types.LatestSignerForChainID(chainID), testKey)
+		require.NoError(err)
+		require.NoError(client.SendTransaction(ctx, tx))
+		sim.Commit(true)
+
+		receipt, err := client.TransactionReceipt(ctx, tx.Hash())
+		require.NoError(err)
+		require.Equal(types.ReceiptStatusSuccessful, receipt.Status)
+		valid, err := client.StorageAt(ctx, receiver, common.Hash{}, nil)
+		require.NoError(err)
+		return common.BytesToHash(valid) == common.BigToHash(common.Big1)
+	}
+
+	// A message signed by every validator is verified.
+	message, err := sim.SignWarpMessage(unsignedMessage)
+	require.NoError(err)
+	require.True(receive(message))
+
+	// A message signed by less than the quorum is not.
+	message, err = sim.SignWarpMessage(unsignedMessage, vdrs[0], vdrs[1])
+	require.NoError(err)
+	require.False(receive(message))
+
+	// Nor is a message signed by a previous validator set.
+	message, err = sim.SignWarpMessage(unsignedMessage, vdrs[:3]...)
+	require.NoError(err)
+	newVdrs, err := NewWarpValidators(4)
+	require.NoError(err)
+	require.NoError(sim.SetWarpValidators(newVdrs))
+	require.False(receive(message))
+
+	_, err = sim.SignWarpMessage(unsignedMessage, vdrs[0])
+	require.ErrorIs(err, errUnknownWarpValidator)
+
+	// Signing twice with the same validator does not add to its weight.
+	_, err = sim.SignWarpMessage(unsignedMessage, newVdrs[0], newVdrs[1], newVdrs[0])
+	require.ErrorIs(err, errDuplicateWarpNodeID)
+}
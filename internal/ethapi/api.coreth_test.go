@@ -10,10 +10,12 @@ import (
 
 	"github.com/MetalBlockchain/libevm/common"
 	"github.com/MetalBlockchain/libevm/common/hexutil"
+	"github.com/MetalBlockchain/libevm/core/types"
 	"github.com/stretchr/testify/require"
 
 	"github.com/MetalBlockchain/coreth/params"
 	"github.com/MetalBlockchain/coreth/plugin/evm/upgrade/acp176"
+	"github.com/MetalBlockchain/coreth/rpc"
 )
 
 type testSuggestPriceOptionsBackend struct {
@@ -37,6 +39,43 @@ func (b *testSuggestPriceOptionsBackend) PriceOptionsConfig() PriceOptionConfig
 	return b.cfg
 }
 
+// testChainContextBackend serves the [headers] by hash, and the [canonical]
+// headers by number.
+type testChainContextBackend struct {
+	ChainContextBackend // embed the interface to avoid implementing unused methods
+
+	headers   map[common.Hash]*types.Header
+	canonical map[uint64]*types.Header
+}
+
+func (b *testChainContextBackend) HeaderByHash(_ context.Context, hash common.Hash) (*types.Header, error) {
+	return b.headers[hash], nil
+}
+
+func (b *testChainContextBackend) HeaderByNumber(_ context.Context, number rpc.BlockNumber) (*types.Header, error) {
+	return b.canonical[uint64(number)], nil
+}
+
+func TestChainContextGetHeader(t *testing.T) {
+	require := require.New(t)
+
+	var (
+		canonical = &types.Header{Number: big.NewInt(1)}
+		sibling   = &types.Header{Number: big.NewInt(1), Extra: []byte{1}}
+		backend   = &testChainContextBackend{
+			headers: map[common.Hash]*types.Header{
+				canonical.Hash(): canonical,
+				sibling.Hash():   sibling,
+			},
+			canonical: map[uint64]*types.Header{1: canonical},
+		}
+		chain = NewChainContext(context.Background(), backend)
+	)
+	require.Equal(canonical, chain.GetHeader(canonical.Hash(), 1))
+	require.Nil(chain.GetHeader(sibling.Hash(), 1), "non-canonical header")
+	require.Nil(chain.GetHeader(canonical.Hash(), 2), "wrong number")
+}
+
 func TestSuggestPriceOptions(t *testing.T) {
 	testCfg := PriceOptionConfig{
 		SlowFeePercentage: 95,
@@ -43,6 +43,7 @@ import (
 	"github.com/MetalBlockchain/coreth/plugin/evm/customtypes"
 	"github.com/MetalBlockchain/coreth/rpc"
 	"github.com/MetalBlockchain/coreth/triedb/firewood"
+	"github.com/MetalBlockchain/coreth/triedb/pathdb"
 	"github.com/MetalBlockchain/libevm/accounts"
 	"github.com/MetalBlockchain/libevm/accounts/keystore"
 	"github.com/MetalBlockchain/libevm/accounts/scwallet"
@@ -150,6 +151,27 @@ func (s *EthereumAPI) Syncing() (interface{}, error) {
 	return false, nil
 }
 
+// TxIndexProgressResult is the progress of indexing the transactions of the
+// blocks within the transaction history.
+type TxIndexProgressResult struct {
+	TxIndexFinishedBlocks  hexutil.Uint64 `json:"txIndexFinishedBlocks"`
+	TxIndexRemainingBlocks hexutil.Uint64 `json:"txIndexRemainingBlocks"`
+}
+
+// TxIndexProgress returns the progress of indexing the transactions of the
+// blocks within the transaction history, which are indexed again in the
+// background when the transaction history is increased.
+func (s *EthereumAPI) TxIndexProgress() (*TxIndexProgressResult, error) {
+	progress, err := s.b.TxIndexProgress()
+	if err != nil {
+		return nil, err
+	}
+	return &TxIndexProgressResult{
+		TxIndexFinishedBlocks:  hexutil.Uint64(progress.Indexed),
+		TxIndexRemainingBlocks: hexutil.Uint64(progress.Remaining),
+	}, nil
+}
+
 // TxPoolAPI offers and API for the transaction pool. It only operates on data that is non-confidential.
 type TxPoolAPI struct {
 	b Backend
@@ -704,8 +726,13 @@ func (s *BlockChainAPI) GetProof(ctx context.Context, address common.Address, st
 	if statedb == nil || err != nil {
 		return nil, err
 	}
-	if _, ok := statedb.Database().TrieDB().Backend().(*firewood.Database); ok {
+	switch db := statedb.Database().TrieDB().Backend().(type) {
+	case *firewood.Database:
 		return nil, errors.New("firewood database does not yet support getProof")
+	case *pathdb.Database:
+		if db.IsHistoricalState(header.Root) {
+			return nil, pathdb.ErrHistoricalStateUnsupported
+		}
 	}
 	codeHash := statedb.GetCodeHash(address)
 	storageRoot := statedb.GetStorageRoot(address)
@@ -1048,7 +1075,9 @@ func (diff *BlockOverrides) Apply(blockCtx *vm.BlockContext) {
 // ChainContextBackend provides methods required to implement ChainContext.
 type ChainContextBackend interface {
 	Engine() consensus.Engine
+	HeaderByHash(context.Context, common.Hash) (*types.Header, error)
 	HeaderByNumber(context.Context, rpc.BlockNumber) (*types.Header, error)
+	ChainConfig() *params.ChainConfig
 }
 
 // ChainContext is an implementation of core.ChainContext. It's main use-case
@@ -1067,11 +1096,17 @@ func (context *ChainContext) Engine() consensus.Engine {
 	return context.b.Engine()
 }
 
+func (context *ChainContext) Config() *params.ChainConfig {
+	return context.b.ChainConfig()
+}
+
 func (context *ChainContext) GetHeader(hash common.Hash, number uint64) *types.Header {
 	// This method is called to get the hash for a block number when executing the BLOCKHASH
-	// opcode. Hence no need to search for non-canonical blocks.
+	// opcode, and to get the parent fee state after Fortuna. Only canonical headers
+	// which the backend allows to be queried are returned, so a missing parent
+	// results in an error when creating the block context.
 	header, err := context.b.HeaderByNumber(context.ctx, rpc.BlockNumber(number))
-	if err != nil || header.Hash() != hash {
+	if err != nil || header == nil || header.Hash() != hash {
 		return nil
 	}
 	return header
@@ -1095,7 +1130,10 @@ func doCall(ctx context.Context, b Backend, args TransactionArgs, state *state.S
 	defer cancel()
 
 	// Get a new instance of the EVM.
-	blockCtx := core.NewEVMBlockContext(header, NewChainContext(ctx, b), nil)
+	blockCtx, err := core.NewEVMBlockContext(header, NewChainContext(ctx, b), nil)
+	if err != nil {
+		return nil, err
+	}
 	if blockOverrides != nil {
 		blockOverrides.Apply(&blockCtx)
 	}
@@ -1103,7 +1141,10 @@ func doCall(ctx context.Context, b Backend, args TransactionArgs, state *state.S
 	if err != nil {
 		return nil, err
 	}
-	evm := b.GetEVM(ctx, msg, state, header, &vm.Config{NoBaseFee: true}, &blockCtx)
+	evm, err := b.GetEVM(ctx, msg, state, header, &vm.Config{NoBaseFee: true}, &blockCtx)
+	if err != nil {
+		return nil, err
+	}
 
 	// Wait for the context to be done and cancel the evm. Even if the
 	// EVM has finished, cancelling may be done (repeatedly)
@@ -1554,7 +1595,10 @@ func AccessList(ctx context.Context, b Backend, blockNrOrHash rpc.BlockNumberOrH
 		// Apply the transaction with the access list tracer
 		tracer := logger.NewAccessListTracer(accessList, args.from(), to, precompiles)
 		config := vm.Config{Tracer: tracer, NoBaseFee: true}
-		vmenv := b.GetEVM(ctx, msg, statedb, header, &config, nil)
+		vmenv, err := b.GetEVM(ctx, msg, statedb, header, &config, nil)
+		if err != nil {
+			return nil, 0, nil, err
+		}
 		res, err := core.ApplyMessage(vmenv, msg, new(core.GasPool).AddGas(msg.GasLimit))
 		if err != nil {
 			return nil, 0, nil, fmt.Errorf("failed to apply transaction: %v err: %v", args.toTransaction().Hash(), err)
@@ -7,15 +7,26 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"slices"
 
+	"github.com/MetalBlockchain/metalgo/ids"
 	"github.com/MetalBlockchain/libevm/common"
 	"github.com/MetalBlockchain/libevm/common/hexutil"
 	"github.com/MetalBlockchain/libevm/rlp"
 
 	"github.com/MetalBlockchain/coreth/core"
+	"github.com/MetalBlockchain/coreth/core/extstate"
 	"github.com/MetalBlockchain/coreth/rpc"
 )
 
+// errPreimagesDisabled is returned when enumerating the multicoin balances of
+// an account, which requires the preimages of the storage keys.
+var errPreimagesDisabled = errors.New("multicoin balances can only be enumerated with preimages enabled")
+
+// assetBalancesMaxStorageSlots is the maximum number of storage slots of an
+// account walked by GetAssetBalances.
+const assetBalancesMaxStorageSlots = 10_000
+
 type DetailedExecutionResult struct {
 	UsedGas    uint64        `json:"gas"`        // Total used gas but include the refunded gas
 	ErrCode    int           `json:"errCode"`    // EVM error code
@@ -89,6 +100,63 @@ func (s *BlockChainAPI) GetBadBlocks() ([]*BadBlockArgs, error) {
 	return results, nil
 }
 
+// GetAssetBalance returns the multicoin balance of [assetID] for the given
+// address in the state of the given block. The rpc.LatestBlockNumber,
+// rpc.PendingBlockNumber and rpc.AcceptedBlockNumber meta block numbers are
+// also allowed.
+func (s *BlockChainAPI) GetAssetBalance(ctx context.Context, address common.Address, assetID ids.ID, blockNrOrHash rpc.BlockNumberOrHash) (*hexutil.Big, error) {
+	state, _, err := s.b.StateAndHeaderByNumberOrHash(ctx, blockNrOrHash)
+	if state == nil || err != nil {
+		return nil, err
+	}
+	balance := extstate.New(state).GetBalanceMultiCoin(address, common.Hash(assetID))
+	return (*hexutil.Big)(balance), state.Error()
+}
+
+// AssetBalance is the multicoin balance of an asset held by an address.
+//
+// Asset IDs which only differ in the lowest bit of their first byte share their
+// balance, so the balance is identified by the normalized asset ID with this bit
+// set (see [extstate.NormalizeCoinID]) rather than by the asset ID it was
+// credited with.
+type AssetBalance struct {
+	NormalizedAssetID ids.ID       `json:"normalizedAssetID"`
+	Balance           *hexutil.Big `json:"balance"`
+}
+
+// GetAssetBalances returns the non-zero multicoin balances of the given address
+// in the state of the given block, sorted by normalized asset ID.
+//
+// The asset IDs are read from the preimages of the storage keys, so an error is
+// returned if the node doesn't record preimages (`preimages-enabled`), or if the
+// balances were written before preimages were enabled. As every storage slot of
+// the address is walked, an error is also returned if it has more than
+// [assetBalancesMaxStorageSlots] slots.
+func (s *BlockChainAPI) GetAssetBalances(ctx context.Context, address common.Address, blockNrOrHash rpc.BlockNumberOrHash) ([]AssetBalance, error) {
+	if !s.b.PreimagesEnabled() {
+		return nil, errPreimagesDisabled
+	}
+	state, header, err := s.b.StateAndHeaderByNumberOrHash(ctx, blockNrOrHash)
+	if state == nil || err != nil {
+		return nil, err
+	}
+	balances, err := extstate.MultiCoinBalances(ctx, state.Database(), header.Root, address, assetBalancesMaxStorageSlots)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]AssetBalance, 0, len(balances))
+	for assetID, balance := range balances {
+		result = append(result, AssetBalance{
+			NormalizedAssetID: ids.ID(assetID),
+			Balance:           (*hexutil.Big)(balance),
+		})
+	}
+	slices.SortFunc(result, func(a, b AssetBalance) int {
+		return a.NormalizedAssetID.Compare(b.NormalizedAssetID)
+	})
+	return result, nil
+}
+
 // stateQueryBlockNumberAllowed returns a nil error if:
 //   - the node is configured to accept any state query (the query window is zero)
 //   - the block given has its number within the query window before the last accepted block.
@@ -4,15 +4,24 @@
 package ethapi
 
 import (
+	"context"
 	"errors"
 	"math/big"
 	"testing"
 
+	"github.com/MetalBlockchain/metalgo/ids"
 	"github.com/MetalBlockchain/libevm/common"
+	"github.com/MetalBlockchain/libevm/common/hexutil"
+	"github.com/MetalBlockchain/libevm/core/rawdb"
+	"github.com/MetalBlockchain/libevm/core/state"
 	"github.com/MetalBlockchain/libevm/core/types"
+	"github.com/MetalBlockchain/libevm/triedb"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
 
+	"github.com/MetalBlockchain/coreth/core"
+	"github.com/MetalBlockchain/coreth/core/extstate"
 	"github.com/MetalBlockchain/coreth/rpc"
 )
 
@@ -134,3 +143,100 @@ func TestBlockChainAPI_stateQueryBlockNumberAllowed(t *testing.T) {
 		})
 	}
 }
+
+func TestEthereumAPI_Syncing(t *testing.T) {
+	backend := NewMockBackend(gomock.NewController(t))
+
+	got, err := NewEthereumAPI(backend).Syncing()
+	assert.NoError(t, err)
+	assert.Equal(t, false, got)
+}
+
+func TestEthereumAPI_TxIndexProgress(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		progress core.TxIndexProgress
+		err      error
+		want     *TxIndexProgressResult
+	}{
+		"indexer_disabled": {
+			err: errors.New("tx indexer is not enabled"),
+		},
+		"indexing_done": {
+			progress: core.TxIndexProgress{Indexed: 100},
+			want:     &TxIndexProgressResult{TxIndexFinishedBlocks: 100},
+		},
+		"indexing": {
+			progress: core.TxIndexProgress{Indexed: 60, Remaining: 40},
+			want:     &TxIndexProgressResult{TxIndexFinishedBlocks: 60, TxIndexRemainingBlocks: 40},
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+
+			backend := NewMockBackend(ctrl)
+			backend.EXPECT().TxIndexProgress().Return(testCase.progress, testCase.err)
+
+			got, err := NewEthereumAPI(backend).TxIndexProgress()
+			assert.ErrorIs(t, err, testCase.err)
+			assert.Equal(t, testCase.want, got)
+		})
+	}
+}
+
+func TestBlockChainAPI_GetAssetBalances(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	var (
+		db       = extstate.NewDatabaseWithConfig(rawdb.NewMemoryDatabase(), &triedb.Config{Preimages: true})
+		addr     = common.Address{1}
+		assetID1 = ids.ID{0x01}
+		assetID2 = ids.ID{0x03}
+	)
+	statedb, err := state.New(types.EmptyRootHash, db, nil)
+	require.NoError(err)
+	wrappedStateDB := extstate.New(statedb)
+	wrappedStateDB.AddBalanceMultiCoin(addr, common.Hash(assetID2), big.NewInt(20))
+	wrappedStateDB.AddBalanceMultiCoin(addr, common.Hash(assetID1), big.NewInt(10))
+	root, err := statedb.Commit(0, false)
+	require.NoError(err)
+	statedb, err = state.New(root, db, nil)
+	require.NoError(err)
+
+	backend := NewMockBackend(gomock.NewController(t))
+	backend.EXPECT().
+		StateAndHeaderByNumberOrHash(gomock.Any(), gomock.Any()).
+		Return(statedb, &types.Header{Root: root}, nil).
+		AnyTimes()
+	backend.EXPECT().PreimagesEnabled().Return(true)
+	api := NewBlockChainAPI(backend)
+	blockNrOrHash := rpc.BlockNumberOrHashWithNumber(rpc.LatestBlockNumber)
+
+	balance, err := api.GetAssetBalance(ctx, addr, assetID1, blockNrOrHash)
+	require.NoError(err)
+	require.Equal((*hexutil.Big)(big.NewInt(10)), balance)
+	balance, err = api.GetAssetBalance(ctx, common.Address{2}, assetID1, blockNrOrHash)
+	require.NoError(err)
+	require.Zero(balance.ToInt().Sign())
+
+	balances, err := api.GetAssetBalances(ctx, addr, blockNrOrHash)
+	require.NoError(err)
+	require.Equal([]AssetBalance{
+		{NormalizedAssetID: assetID1, Balance: (*hexutil.Big)(big.NewInt(10))},
+		{NormalizedAssetID: assetID2, Balance: (*hexutil.Big)(big.NewInt(20))},
+	}, balances)
+}
+
+func TestBlockChainAPI_GetAssetBalancesPreimagesDisabled(t *testing.T) {
+	backend := NewMockBackend(gomock.NewController(t))
+	backend.EXPECT().PreimagesEnabled().Return(false)
+	api := NewBlockChainAPI(backend)
+
+	_, err := api.GetAssetBalances(context.Background(), common.Address{1}, rpc.BlockNumberOrHashWithNumber(rpc.LatestBlockNumber))
+	require.ErrorIs(t, err, errPreimagesDisabled)
+}
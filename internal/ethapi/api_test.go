@@ -561,16 +561,19 @@ func (b testBackend) GetReceipts(ctx context.Context, hash common.Hash) (types.R
 	receipts := rawdb.ReadReceipts(b.db, hash, header.Number.Uint64(), header.Time, b.chain.Config())
 	return receipts, nil
 }
-func (b testBackend) GetEVM(ctx context.Context, msg *core.Message, state *state.StateDB, header *types.Header, vmConfig *vm.Config, blockContext *vm.BlockContext) *vm.EVM {
+func (b testBackend) GetEVM(ctx context.Context, msg *core.Message, state *state.StateDB, header *types.Header, vmConfig *vm.Config, blockContext *vm.BlockContext) (*vm.EVM, error) {
 	if vmConfig == nil {
 		vmConfig = b.chain.GetVMConfig()
 	}
 	txContext := core.NewEVMTxContext(msg)
-	context := core.NewEVMBlockContext(header, b.chain, nil)
 	if blockContext != nil {
-		context = *blockContext
+		return vm.NewEVM(*blockContext, txContext, state, b.chain.Config(), *vmConfig), nil
 	}
-	return vm.NewEVM(context, txContext, state, b.chain.Config(), *vmConfig)
+	context, err := core.NewEVMBlockContext(header, b.chain, nil)
+	if err != nil {
+		return nil, err
+	}
+	return vm.NewEVM(context, txContext, state, b.chain.Config(), *vmConfig), nil
 }
 func (b testBackend) SubscribeChainEvent(ch chan<- core.ChainEvent) event.Subscription {
 	panic("implement me")
@@ -626,6 +629,9 @@ func (b testBackend) EstimateBaseFee(ctx context.Context) (*big.Int, error) {
 	panic("implement me")
 }
 func (b testBackend) LastAcceptedBlock() *types.Block { panic("implement me") }
+func (b testBackend) TxIndexProgress() (core.TxIndexProgress, error) {
+	panic("implement me")
+}
 func (b testBackend) SuggestPrice(ctx context.Context) (*big.Int, error) {
 	panic("implement me")
 }
@@ -635,6 +641,9 @@ func (b testBackend) IsArchive() bool {
 func (b testBackend) HistoricalProofQueryWindow() (queryWindow uint64) {
 	panic("implement me")
 }
+func (b testBackend) PreimagesEnabled() bool {
+	panic("implement me")
+}
 
 func TestEstimateGas(t *testing.T) {
 	t.Parallel()
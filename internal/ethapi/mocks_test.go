@@ -276,11 +276,12 @@ func (mr *MockBackendMockRecorder) GetBody(ctx, hash, number any) *gomock.Call {
 }
 
 // GetEVM mocks base method.
-func (m *MockBackend) GetEVM(ctx context.Context, msg *core.Message, state *state.StateDB, header *types.Header, vmConfig *vm.Config, blockCtx *vm.BlockContext) *vm.EVM {
+func (m *MockBackend) GetEVM(ctx context.Context, msg *core.Message, state *state.StateDB, header *types.Header, vmConfig *vm.Config, blockCtx *vm.BlockContext) (*vm.EVM, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GetEVM", ctx, msg, state, header, vmConfig, blockCtx)
 	ret0, _ := ret[0].(*vm.EVM)
-	return ret0
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
 // GetEVM indicates an expected call of GetEVM.
@@ -469,6 +470,20 @@ func (mr *MockBackendMockRecorder) LastAcceptedBlock() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LastAcceptedBlock", reflect.TypeOf((*MockBackend)(nil).LastAcceptedBlock))
 }
 
+// PreimagesEnabled mocks base method.
+func (m *MockBackend) PreimagesEnabled() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PreimagesEnabled")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// PreimagesEnabled indicates an expected call of PreimagesEnabled.
+func (mr *MockBackendMockRecorder) PreimagesEnabled() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PreimagesEnabled", reflect.TypeOf((*MockBackend)(nil).PreimagesEnabled))
+}
+
 // PriceOptionsConfig mocks base method.
 func (m *MockBackend) PriceOptionsConfig() PriceOptionConfig {
 	m.ctrl.T.Helper()
@@ -726,6 +741,21 @@ func (mr *MockBackendMockRecorder) SuggestPrice(ctx any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SuggestPrice", reflect.TypeOf((*MockBackend)(nil).SuggestPrice), ctx)
 }
 
+// TxIndexProgress mocks base method.
+func (m *MockBackend) TxIndexProgress() (core.TxIndexProgress, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TxIndexProgress")
+	ret0, _ := ret[0].(core.TxIndexProgress)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TxIndexProgress indicates an expected call of TxIndexProgress.
+func (mr *MockBackendMockRecorder) TxIndexProgress() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TxIndexProgress", reflect.TypeOf((*MockBackend)(nil).TxIndexProgress))
+}
+
 // TxPoolContent mocks base method.
 func (m *MockBackend) TxPoolContent() (map[common.Address][]*types.Transaction, map[common.Address][]*types.Transaction) {
 	m.ctrl.T.Helper()
@@ -4,6 +4,7 @@
 package params
 
 import (
+	"errors"
 	"fmt"
 	"maps"
 	"math/big"
@@ -19,6 +20,7 @@ import (
 
 	"github.com/MetalBlockchain/coreth/nativeasset"
 	"github.com/MetalBlockchain/coreth/params/extras"
+	"github.com/MetalBlockchain/coreth/plugin/evm/upgrade/acp176"
 	"github.com/MetalBlockchain/coreth/precompile/contract"
 	"github.com/MetalBlockchain/coreth/precompile/modules"
 	"github.com/MetalBlockchain/coreth/precompile/precompileconfig"
@@ -31,6 +33,8 @@ import (
 // (August 2nd, 2025, 04:00 UTC)
 const invalidateDelegateUnix = 1754107200
 
+var errFeeStateUnavailable = errors.New("fee state is unavailable prior to Fortuna")
+
 type RulesExtra extras.Rules
 
 func GetRulesExtra(r Rules) *extras.Rules {
@@ -137,6 +141,8 @@ func makePrecompile(contract contract.StatefulPrecompiledContract) libevm.Precom
 			blockContext: &precompileBlockContext{
 				number:           env.BlockNumber(),
 				time:             env.BlockTime(),
+				rules:            rules,
+				extra:            header.Extra,
 				predicateResults: predicateResults,
 			},
 		}
@@ -159,7 +165,7 @@ func (r RulesExtra) PrecompileOverride(addr common.Address) (libevm.PrecompiledC
 		return p, true
 	}
 	if _, ok := r.Precompiles[addr]; !ok {
-		return nil, false
+		return r.precompileOverrideDerived(addr)
 	}
 	module, ok := modules.GetPrecompileModuleByAddress(addr)
 	if !ok {
@@ -169,6 +175,19 @@ func (r RulesExtra) PrecompileOverride(addr common.Address) (libevm.PrecompiledC
 	return makePrecompile(module.Contract), true
 }
 
+// precompileOverrideDerived returns the contract of the enabled precompile that
+// is also accessible at [addr], if any.
+func (r RulesExtra) precompileOverrideDerived(addr common.Address) (libevm.PrecompiledContract, bool) {
+	module, ok := modules.GetPrecompileModuleByDerivedAddress(addr)
+	if !ok {
+		return nil, false
+	}
+	if _, ok := r.Precompiles[module.Address]; !ok {
+		return nil, false
+	}
+	return makePrecompile(module.Contract), true
+}
+
 type accessibleState struct {
 	env          vm.PrecompileEnvironment
 	blockContext *precompileBlockContext
@@ -203,8 +222,12 @@ func (a accessibleState) GetPrecompileEnv() vm.PrecompileEnvironment {
 }
 
 type precompileBlockContext struct {
-	number           *big.Int
-	time             uint64
+	number *big.Int
+	time   uint64
+	rules  extras.AvalancheRules
+	// extra is the Extra field of the EVM block context header. After
+	// Fortuna, it is prefixed with the fee state of the parent block.
+	extra            []byte
 	predicateResults predicate.BlockResults
 }
 
@@ -219,3 +242,10 @@ func (p *precompileBlockContext) Timestamp() uint64 {
 func (p *precompileBlockContext) GetPredicateResults(txHash common.Hash, precompileAddress common.Address) set.Bits {
 	return p.predicateResults.Get(txHash, precompileAddress)
 }
+
+func (p *precompileBlockContext) GetParentFeeState() (acp176.State, error) {
+	if !p.rules.IsFortuna {
+		return acp176.State{}, errFeeStateUnavailable
+	}
+	return acp176.ParseState(p.extra)
+}
@@ -0,0 +1,63 @@
+// Copyright (C) 2019-2025, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package params
+
+import (
+	"testing"
+
+	"github.com/MetalBlockchain/libevm/common"
+	"github.com/stretchr/testify/require"
+
+	"github.com/MetalBlockchain/coreth/precompile/contracts/assettoken"
+	"github.com/MetalBlockchain/coreth/precompile/precompileconfig"
+	"github.com/MetalBlockchain/coreth/utils"
+)
+
+func TestPrecompileOverrideDerived(t *testing.T) {
+	var (
+		token   = assettoken.TokenAddress(common.Hash{1})
+		enabled = map[common.Address]precompileconfig.Config{
+			assettoken.ContractAddress: assettoken.NewConfig(utils.NewUint64(0), nil),
+		}
+	)
+	tests := []struct {
+		name        string
+		precompiles map[common.Address]precompileconfig.Config
+		addr        common.Address
+		want        bool
+	}{
+		{
+			name:        "token enabled",
+			precompiles: enabled,
+			addr:        token,
+			want:        true,
+		},
+		{
+			name: "token disabled",
+			addr: token,
+		},
+		{
+			name:        "non-token enabled",
+			precompiles: enabled,
+			addr:        common.HexToAddress("0x0123"),
+		},
+		{
+			name:        "unregistered precompile enabled",
+			precompiles: enabled,
+			addr:        common.HexToAddress("0x02000000000000000000000000000000000000ff"),
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require := require.New(t)
+
+			rules := RulesExtra{Precompiles: test.precompiles}
+			p, ok := rules.PrecompileOverride(test.addr)
+			require.Equal(test.want, ok)
+			if !test.want {
+				require.Nil(p)
+			}
+		})
+	}
+}
@@ -4,6 +4,7 @@
 package evm
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 
@@ -83,3 +84,18 @@ func (p *Admin) GetVMConfig(_ *http.Request, _ *struct{}, reply *client.ConfigRe
 	reply.Config = &p.vm.config
 	return nil
 }
+
+// ExportStateSnapshot writes a state sync snapshot to the specified directory
+func (p *Admin) ExportStateSnapshot(r *http.Request, args *client.ExportStateSnapshotArgs, reply *client.ExportStateSnapshotReply) error {
+	log.Info("Admin: ExportStateSnapshot called", "dir", args.Dir, "height", args.Height)
+
+	if len(args.Dir) == 0 {
+		return errors.New("dir must be specified")
+	}
+	manifest, err := p.vm.exportStateSnapshot(r.Context(), args.Dir, uint64(args.Height))
+	if err != nil {
+		return fmt.Errorf("failed to export state snapshot: %w", err)
+	}
+	reply.Manifest = manifest
+	return nil
+}
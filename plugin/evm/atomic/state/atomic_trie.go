@@ -71,19 +71,10 @@ func newAtomicTrie(
 		}
 	}
 
-	trieDB := triedb.NewDatabase(
-		rawdb.NewDatabase(database.WrapDatabase(atomicTrieDB)),
-		&triedb.Config{
-			DBOverride: hashdb.Config{
-				CleanCacheSize: 64 * units.MiB, // Allocate 64MB of memory for clean cache
-			}.BackendConstructor,
-		},
-	)
-
 	return &AtomicTrie{
 		commitInterval:      commitHeightInterval,
 		metadataDB:          metadataDB,
-		trieDB:              trieDB,
+		trieDB:              newAtomicTrieDB(atomicTrieDB),
 		codec:               codec,
 		lastCommittedRoot:   root,
 		lastCommittedHeight: height,
@@ -96,6 +87,18 @@ func newAtomicTrie(
 	}, nil
 }
 
+// newAtomicTrieDB returns the trie database of the atomic trie stored in [db].
+func newAtomicTrieDB(db avalanchedatabase.Database) *triedb.Database {
+	return triedb.NewDatabase(
+		rawdb.NewDatabase(database.WrapDatabase(db)),
+		&triedb.Config{
+			DBOverride: hashdb.Config{
+				CleanCacheSize: 64 * units.MiB, // Allocate 64MB of memory for clean cache
+			}.BackendConstructor,
+		},
+	)
+}
+
 // lastCommittedRootIfExists returns the last committed trie root and height if it exists
 // else returns empty common.Hash{} and 0
 // returns error only if there are issues with the underlying data store
@@ -0,0 +1,358 @@
+// Copyright (C) 2019-2025, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package state
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/MetalBlockchain/metalgo/database/leveldb"
+	"github.com/MetalBlockchain/metalgo/utils/logging"
+	"github.com/MetalBlockchain/libevm/common"
+	"github.com/MetalBlockchain/libevm/core/types"
+	"github.com/MetalBlockchain/libevm/crypto"
+	"github.com/MetalBlockchain/libevm/ethdb"
+	"github.com/MetalBlockchain/libevm/log"
+	"github.com/MetalBlockchain/libevm/trie"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/MetalBlockchain/coreth/plugin/evm/atomic"
+
+	avalanchedatabase "github.com/MetalBlockchain/metalgo/database"
+)
+
+var (
+	errZeroCommitInterval = errors.New("commit interval must be greater than 0")
+	errInvalidStartHeight = errors.New("start height must be a commit height at or below the last committed height")
+	errMissingStartRoot   = errors.New("no atomic trie root committed at start height")
+	errInvalidEndHeight   = errors.New("end height must be a commit height between the start height and the last committed height")
+	errRepairEndHeight    = errors.New("cannot repair the atomic trie up to an end height")
+	errInvalidTrieNode    = errors.New("atomic trie node does not match its hash")
+)
+
+// VerifyConfig configures [VerifyAtomicTrie].
+type VerifyConfig struct {
+	// CommitInterval is the interval at which the atomic trie was committed.
+	CommitInterval uint64
+
+	// StartHeight is the commit height to rebuild the atomic trie from. The
+	// root committed at StartHeight is trusted. Nodes that state synced must
+	// start at or above the height they synced to, as the atomic repository
+	// does not index the atomic txs accepted before it.
+	StartHeight uint64
+
+	// EndHeight is the commit height to verify the atomic trie up to. If
+	// zero, the atomic trie is verified up to the last committed height.
+	// Roots committed at or below EndHeight are not modified while the VM is
+	// running, so a fixed EndHeight allows verifying without blocking accept.
+	EndHeight uint64
+
+	// TempDir is the directory the rebuilt trie is written to when not
+	// repairing. If empty, [os.TempDir] is used.
+	TempDir string
+
+	// Repair overwrites the trie nodes and the committed roots with the
+	// rebuilt ones. The VM must not be running while repairing.
+	Repair bool
+}
+
+// RootMismatch is a commit height at which the committed atomic trie root
+// differs from the root rebuilt from the atomic repository.
+type RootMismatch struct {
+	Height  uint64      `json:"height"`
+	Stored  common.Hash `json:"stored"`
+	Rebuilt common.Hash `json:"rebuilt"`
+}
+
+// VerifyResult is the result of [VerifyAtomicTrie].
+type VerifyResult struct {
+	StartHeight         uint64         `json:"startHeight"`
+	LastCommittedHeight uint64         `json:"lastCommittedHeight"`
+	LastCommittedRoot   common.Hash    `json:"lastCommittedRoot"`
+	CommitsVerified     uint64         `json:"commitsVerified"`
+	Mismatches          []RootMismatch `json:"mismatches"`
+	// NodeError is the error found while reading the trie nodes of the last
+	// committed root, if any.
+	NodeError string `json:"nodeError,omitempty"`
+	Repaired  bool   `json:"repaired"`
+}
+
+// OK returns true if the atomic trie matches the atomic repository.
+func (r *VerifyResult) OK() bool {
+	return len(r.Mismatches) == 0 && len(r.NodeError) == 0
+}
+
+// VerifyAtomicTrie rebuilds the atomic trie from the height index of [repo],
+// from [config.StartHeight] up to [config.EndHeight] or the last committed
+// height, and compares the rebuilt root against the committed root at each
+// commit height. It also checks that every trie node of the last verified root
+// is present and matches its hash.
+//
+// Unless [config.Repair] is set, the rebuilt trie is written to a temporary
+// database that is removed on return, and nothing is written to [repo].
+func VerifyAtomicTrie(repo *AtomicRepository, config VerifyConfig) (*VerifyResult, error) {
+	if config.CommitInterval == 0 {
+		return nil, errZeroCommitInterval
+	}
+	lastCommittedRoot, lastCommittedHeight, err := lastCommittedRootIfExists(repo.metadataDB)
+	if err != nil {
+		return nil, err
+	}
+	if lastCommittedRoot == (common.Hash{}) {
+		lastCommittedRoot = types.EmptyRootHash
+	}
+	if config.StartHeight%config.CommitInterval != 0 || config.StartHeight > lastCommittedHeight {
+		return nil, fmt.Errorf("%w: %d (last committed height %d)", errInvalidStartHeight, config.StartHeight, lastCommittedHeight)
+	}
+	if config.EndHeight != 0 && config.EndHeight != lastCommittedHeight {
+		if config.Repair {
+			return nil, fmt.Errorf("%w: %d (last committed height %d)", errRepairEndHeight, config.EndHeight, lastCommittedHeight)
+		}
+		if config.EndHeight%config.CommitInterval != 0 || config.EndHeight < config.StartHeight || config.EndHeight > lastCommittedHeight {
+			return nil, fmt.Errorf("%w: %d (last committed height %d)", errInvalidEndHeight, config.EndHeight, lastCommittedHeight)
+		}
+		lastCommittedHeight = config.EndHeight
+		lastCommittedRoot, err = getRoot(repo.metadataDB, lastCommittedHeight)
+		if err != nil {
+			return nil, err
+		}
+	}
+	startRoot, err := getRoot(repo.metadataDB, config.StartHeight)
+	if err != nil {
+		return nil, err
+	}
+	if startRoot == (common.Hash{}) {
+		return nil, fmt.Errorf("%w: %d", errMissingStartRoot, config.StartHeight)
+	}
+
+	result := &VerifyResult{
+		StartHeight:         config.StartHeight,
+		LastCommittedHeight: lastCommittedHeight,
+		LastCommittedRoot:   lastCommittedRoot,
+		Mismatches:          []RootMismatch{},
+	}
+	if err := verifyAtomicTrieNodes(repo.atomicTrieDB, lastCommittedRoot); err != nil {
+		log.Warn("Found invalid atomic trie nodes", "root", lastCommittedRoot, "err", err)
+		result.NodeError = err.Error()
+	}
+
+	// When only verifying, the rebuilt trie is written to a temporary on-disk
+	// database, so that its size is bounded by [atomicTrieMemoryCap]. The
+	// trie at [startRoot] is copied to it first.
+	atomicTrieDB := repo.atomicTrieDB
+	if !config.Repair {
+		dir, err := os.MkdirTemp(config.TempDir, "atomic-trie-verify-")
+		if err != nil {
+			return nil, err
+		}
+		defer os.RemoveAll(dir)
+		tempDB, err := leveldb.New(dir, nil, logging.NoLog{}, prometheus.NewRegistry())
+		if err != nil {
+			return nil, err
+		}
+		defer tempDB.Close()
+		if err := copyAtomicTrieNodes(repo.atomicTrieDB, tempDB, startRoot); err != nil {
+			return nil, fmt.Errorf("failed to copy atomic trie at start height %d: %w", config.StartHeight, err)
+		}
+		atomicTrieDB = tempDB
+	}
+	rebuilt := &AtomicTrie{
+		commitInterval:      config.CommitInterval,
+		metadataDB:          repo.metadataDB,
+		trieDB:              newAtomicTrieDB(atomicTrieDB),
+		codec:               repo.codec,
+		lastCommittedRoot:   startRoot,
+		lastCommittedHeight: config.StartHeight,
+		lastAcceptedRoot:    startRoot,
+		memoryCap:           atomicTrieMemoryCap,
+	}
+	defer rebuilt.trieDB.Close()
+
+	// verifyCommit compares [root] against the root committed at [height],
+	// and commits it if repairing.
+	verifyCommit := func(height uint64, root common.Hash) error {
+		stored, err := getRoot(repo.metadataDB, height)
+		if err != nil {
+			return err
+		}
+		result.CommitsVerified++
+		if stored != root {
+			log.Warn("Atomic trie root mismatch", "height", height, "stored", stored, "rebuilt", root)
+			result.Mismatches = append(result.Mismatches, RootMismatch{
+				Height:  height,
+				Stored:  stored,
+				Rebuilt: root,
+			})
+		}
+		if !config.Repair {
+			return nil
+		}
+		if err := rebuilt.Commit(height, root); err != nil {
+			return err
+		}
+		return repo.db.Commit()
+	}
+
+	log.Info("Verifying atomic trie", "startHeight", config.StartHeight, "lastCommittedHeight", lastCommittedHeight, "repair", config.Repair)
+	var (
+		start      = time.Now()
+		lastUpdate = time.Now()
+		root       = startRoot
+		nextCommit = config.StartHeight + config.CommitInterval
+	)
+	tr, err := rebuilt.OpenTrie(root)
+	if err != nil {
+		return nil, err
+	}
+	iter := repo.IterateByHeight(config.StartHeight + 1)
+	defer iter.Release()
+	for iter.Next() {
+		height := binary.BigEndian.Uint64(iter.Key())
+		if height > lastCommittedHeight {
+			break
+		}
+		// Commit heights without atomic txs have the root of the last
+		// height with atomic txs before them.
+		for ; nextCommit < height; nextCommit += config.CommitInterval {
+			if err := verifyCommit(nextCommit, root); err != nil {
+				return nil, err
+			}
+		}
+
+		txs, err := atomic.ExtractAtomicTxs(iter.Value(), true, repo.codec)
+		if err != nil {
+			return nil, err
+		}
+		combinedOps, err := mergeAtomicOps(txs)
+		if err != nil {
+			return nil, err
+		}
+		if err := rebuilt.UpdateTrie(tr, height, combinedOps); err != nil {
+			return nil, err
+		}
+		newRoot, nodes, err := tr.Commit(false)
+		if err != nil {
+			return nil, err
+		}
+		if err := rebuilt.InsertTrie(nodes, newRoot); err != nil {
+			return nil, err
+		}
+		rebuilt.trieDB.Dereference(root)
+		root = newRoot
+
+		if height == nextCommit {
+			if err := verifyCommit(nextCommit, root); err != nil {
+				return nil, err
+			}
+			nextCommit += config.CommitInterval
+		}
+		// Trie must be re-opened after committing (not safe for re-use after commit)
+		tr, err = rebuilt.OpenTrie(root)
+		if err != nil {
+			return nil, err
+		}
+
+		if time.Since(lastUpdate) > progressLogFrequency {
+			log.Info("Verifying atomic trie", "height", height, "commitsVerified", result.CommitsVerified, "mismatches", len(result.Mismatches))
+			lastUpdate = time.Now()
+		}
+	}
+	if err := iter.Error(); err != nil {
+		return nil, err
+	}
+	for ; nextCommit <= lastCommittedHeight; nextCommit += config.CommitInterval {
+		if err := verifyCommit(nextCommit, root); err != nil {
+			return nil, err
+		}
+	}
+	result.Repaired = config.Repair
+
+	log.Info(
+		"Finished verifying atomic trie",
+		"commitsVerified", result.CommitsVerified,
+		"mismatches", len(result.Mismatches),
+		"nodeError", result.NodeError,
+		"repaired", result.Repaired,
+		"time", time.Since(start),
+	)
+	return result, nil
+}
+
+// verifyAtomicTrieNodes reads every node of the atomic trie with [root] from
+// [db], and checks that it matches its hash.
+func verifyAtomicTrieNodes(db avalanchedatabase.Database, root common.Hash) error {
+	if root == types.EmptyRootHash {
+		return nil
+	}
+	trieDB := newAtomicTrieDB(db)
+	defer trieDB.Close()
+
+	reader, err := trieDB.Reader(root)
+	if err != nil {
+		return err
+	}
+	tr, err := trie.New(trie.TrieID(root), trieDB)
+	if err != nil {
+		return err
+	}
+	it, err := tr.NodeIterator(nil)
+	if err != nil {
+		return err
+	}
+	for it.Next(true) {
+		hash := it.Hash()
+		if hash == (common.Hash{}) {
+			// Nodes embedded in their parent have no hash.
+			continue
+		}
+		blob, _ := reader.Node(common.Hash{}, it.Path(), hash)
+		if got := crypto.Keccak256Hash(blob); got != hash {
+			return fmt.Errorf("%w: %s at path %x", errInvalidTrieNode, hash, it.Path())
+		}
+	}
+	return it.Error()
+}
+
+// copyAtomicTrieNodes writes every node of the atomic trie with [root] from
+// [src] to [dst].
+func copyAtomicTrieNodes(src avalanchedatabase.Database, dst avalanchedatabase.Database, root common.Hash) error {
+	if root == types.EmptyRootHash {
+		return nil
+	}
+	trieDB := newAtomicTrieDB(src)
+	defer trieDB.Close()
+
+	tr, err := trie.New(trie.TrieID(root), trieDB)
+	if err != nil {
+		return err
+	}
+	it, err := tr.NodeIterator(nil)
+	if err != nil {
+		return err
+	}
+	batch := dst.NewBatch()
+	for it.Next(true) {
+		hash := it.Hash()
+		if hash == (common.Hash{}) {
+			continue
+		}
+		if err := batch.Put(hash[:], it.NodeBlob()); err != nil {
+			return err
+		}
+		if batch.Size() < ethdb.IdealBatchSize {
+			continue
+		}
+		if err := batch.Write(); err != nil {
+			return err
+		}
+		batch.Reset()
+	}
+	if err := it.Error(); err != nil {
+		return err
+	}
+	return batch.Write()
+}
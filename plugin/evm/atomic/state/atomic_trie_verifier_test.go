@@ -0,0 +1,209 @@
+// Copyright (C) 2019-2025, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package state
+
+import (
+	"testing"
+
+	"github.com/MetalBlockchain/metalgo/database/memdb"
+	"github.com/MetalBlockchain/metalgo/database/versiondb"
+	"github.com/MetalBlockchain/metalgo/ids"
+	"github.com/MetalBlockchain/libevm/common"
+	"github.com/stretchr/testify/require"
+
+	"github.com/MetalBlockchain/coreth/plugin/evm/atomic"
+	"github.com/MetalBlockchain/coreth/plugin/evm/atomic/atomictest"
+
+	avalancheatomic "github.com/MetalBlockchain/metalgo/chains/atomic"
+)
+
+// newVerifierTestRepo returns an atomic repository with atomic txs written up
+// to [lastAcceptedHeight], and an atomic trie initialized from it.
+func newVerifierTestRepo(t *testing.T, lastAcceptedHeight uint64, commitInterval uint64, txsPerHeight func(uint64) int) (*AtomicRepository, *AtomicTrie) {
+	t.Helper()
+	repo, err := NewAtomicTxRepository(versiondb.New(memdb.New()), atomictest.TestTxCodec, lastAcceptedHeight)
+	require.NoError(t, err)
+	operationsMap := make(map[uint64]map[ids.ID]*avalancheatomic.Requests)
+	writeTxs(t, repo, 1, lastAcceptedHeight+1, txsPerHeight, nil, operationsMap)
+
+	atomicBackend, err := NewAtomicBackend(atomictest.TestSharedMemory(), nil, repo, lastAcceptedHeight, common.Hash{}, commitInterval)
+	require.NoError(t, err)
+	return repo, atomicBackend.AtomicTrie()
+}
+
+func TestVerifyAtomicTrie(t *testing.T) {
+	tests := map[string]struct {
+		lastAcceptedHeight      uint64
+		txsPerHeight            func(uint64) int
+		expectedCommitsVerified uint64
+	}{
+		"genesis": {
+			lastAcceptedHeight:      0,
+			txsPerHeight:            constTxsPerHeight(0),
+			expectedCommitsVerified: 0,
+		},
+		"before first commit": {
+			lastAcceptedHeight:      5,
+			txsPerHeight:            constTxsPerHeight(3),
+			expectedCommitsVerified: 0,
+		},
+		"many commits": {
+			lastAcceptedHeight:      105,
+			txsPerHeight:            constTxsPerHeight(3),
+			expectedCommitsVerified: 10,
+		},
+		"some blocks without atomic tx": {
+			lastAcceptedHeight: 101,
+			txsPerHeight: func(height uint64) int {
+				if height <= 50 || height == 101 {
+					return 1
+				}
+				return 0
+			},
+			expectedCommitsVerified: 10,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			require := require.New(t)
+
+			repo, atomicTrie := newVerifierTestRepo(t, test.lastAcceptedHeight, 10, test.txsPerHeight)
+			lastCommittedRoot, lastCommittedHeight := atomicTrie.LastCommitted()
+
+			result, err := VerifyAtomicTrie(repo, VerifyConfig{CommitInterval: 10})
+			require.NoError(err)
+			require.True(result.OK(), "unexpected result: %+v", result)
+			require.Equal(lastCommittedHeight, result.LastCommittedHeight)
+			require.Equal(lastCommittedRoot, result.LastCommittedRoot)
+			require.Equal(test.expectedCommitsVerified, result.CommitsVerified)
+			require.False(result.Repaired)
+		})
+	}
+}
+
+func TestVerifyAtomicTrieStartHeight(t *testing.T) {
+	require := require.New(t)
+
+	repo, _ := newVerifierTestRepo(t, 55, 10, constTxsPerHeight(2))
+
+	result, err := VerifyAtomicTrie(repo, VerifyConfig{CommitInterval: 10, StartHeight: 20})
+	require.NoError(err)
+	require.True(result.OK(), "unexpected result: %+v", result)
+	require.Equal(uint64(3), result.CommitsVerified)
+
+	_, err = VerifyAtomicTrie(repo, VerifyConfig{CommitInterval: 10, StartHeight: 25})
+	require.ErrorIs(err, errInvalidStartHeight)
+	_, err = VerifyAtomicTrie(repo, VerifyConfig{CommitInterval: 10, StartHeight: 60})
+	require.ErrorIs(err, errInvalidStartHeight)
+	_, err = VerifyAtomicTrie(repo, VerifyConfig{})
+	require.ErrorIs(err, errZeroCommitInterval)
+}
+
+func TestVerifyAtomicTrieEndHeight(t *testing.T) {
+	require := require.New(t)
+
+	repo, atomicTrie := newVerifierTestRepo(t, 55, 10, constTxsPerHeight(2))
+	storedRoot30, err := atomicTrie.Root(30)
+	require.NoError(err)
+
+	// Index another tx above the end height, which the atomic trie does not
+	// contain.
+	require.NoError(repo.Write(45, []*atomic.Tx{atomictest.GenerateTestExportTx()}))
+	require.NoError(repo.db.Commit())
+
+	result, err := VerifyAtomicTrie(repo, VerifyConfig{CommitInterval: 10, StartHeight: 10, EndHeight: 30, TempDir: t.TempDir()})
+	require.NoError(err)
+	require.True(result.OK(), "unexpected result: %+v", result)
+	require.Equal(uint64(2), result.CommitsVerified)
+	require.Equal(uint64(30), result.LastCommittedHeight)
+	require.Equal(storedRoot30, result.LastCommittedRoot)
+
+	result, err = VerifyAtomicTrie(repo, VerifyConfig{CommitInterval: 10, StartHeight: 10})
+	require.NoError(err)
+	require.Len(result.Mismatches, 1)
+	require.Equal(uint64(50), result.Mismatches[0].Height)
+
+	_, err = VerifyAtomicTrie(repo, VerifyConfig{CommitInterval: 10, EndHeight: 35})
+	require.ErrorIs(err, errInvalidEndHeight)
+	_, err = VerifyAtomicTrie(repo, VerifyConfig{CommitInterval: 10, EndHeight: 60})
+	require.ErrorIs(err, errInvalidEndHeight)
+	_, err = VerifyAtomicTrie(repo, VerifyConfig{CommitInterval: 10, StartHeight: 30, EndHeight: 20})
+	require.ErrorIs(err, errInvalidEndHeight)
+	_, err = VerifyAtomicTrie(repo, VerifyConfig{CommitInterval: 10, EndHeight: 30, Repair: true})
+	require.ErrorIs(err, errRepairEndHeight)
+}
+
+func TestVerifyAtomicTrieRepairsRoots(t *testing.T) {
+	require := require.New(t)
+
+	repo, atomicTrie := newVerifierTestRepo(t, 35, 10, constTxsPerHeight(2))
+	storedRoot, lastCommittedHeight := atomicTrie.LastCommitted()
+	storedRoot10, err := atomicTrie.Root(10)
+	require.NoError(err)
+
+	// Index another tx below the last committed height, which the atomic trie
+	// does not contain.
+	require.NoError(repo.Write(15, []*atomic.Tx{atomictest.GenerateTestExportTx()}))
+	require.NoError(repo.db.Commit())
+
+	result, err := VerifyAtomicTrie(repo, VerifyConfig{CommitInterval: 10})
+	require.NoError(err)
+	require.False(result.OK())
+	require.Empty(result.NodeError)
+	require.Len(result.Mismatches, 2)
+	require.Equal(uint64(20), result.Mismatches[0].Height)
+	require.Equal(uint64(30), result.Mismatches[1].Height)
+	require.Equal(storedRoot, result.Mismatches[1].Stored)
+	rebuiltRoot := result.Mismatches[1].Rebuilt
+
+	// Verifying must not modify the atomic trie.
+	root, err := atomicTrie.Root(lastCommittedHeight)
+	require.NoError(err)
+	require.Equal(storedRoot, root)
+
+	result, err = VerifyAtomicTrie(repo, VerifyConfig{CommitInterval: 10, Repair: true})
+	require.NoError(err)
+	require.Len(result.Mismatches, 2)
+	require.True(result.Repaired)
+
+	result, err = VerifyAtomicTrie(repo, VerifyConfig{CommitInterval: 10})
+	require.NoError(err)
+	require.True(result.OK(), "unexpected result: %+v", result)
+	require.Equal(rebuiltRoot, result.LastCommittedRoot)
+
+	// The roots before the first mismatch are unchanged, and the repaired
+	// trie is used when the atomic backend is initialized again.
+	atomicBackend, err := NewAtomicBackend(atomictest.TestSharedMemory(), nil, repo, 35, common.Hash{}, 10)
+	require.NoError(err)
+	root, height := atomicBackend.AtomicTrie().LastCommitted()
+	require.Equal(lastCommittedHeight, height)
+	require.Equal(rebuiltRoot, root)
+	root, err = atomicBackend.AtomicTrie().Root(10)
+	require.NoError(err)
+	require.Equal(storedRoot10, root)
+}
+
+func TestVerifyAtomicTrieRepairsNodes(t *testing.T) {
+	require := require.New(t)
+
+	repo, atomicTrie := newVerifierTestRepo(t, 25, 10, constTxsPerHeight(2))
+	root, _ := atomicTrie.LastCommitted()
+
+	// Corrupt the root node of the last committed root.
+	require.NoError(repo.atomicTrieDB.Put(root[:], []byte{0x01, 0x02, 0x03}))
+	require.NoError(repo.db.Commit())
+
+	result, err := VerifyAtomicTrie(repo, VerifyConfig{CommitInterval: 10})
+	require.NoError(err)
+	require.False(result.OK())
+	require.NotEmpty(result.NodeError)
+	require.Empty(result.Mismatches)
+
+	_, err = VerifyAtomicTrie(repo, VerifyConfig{CommitInterval: 10, Repair: true})
+	require.NoError(err)
+
+	result, err = VerifyAtomicTrie(repo, VerifyConfig{CommitInterval: 10})
+	require.NoError(err)
+	require.True(result.OK(), "unexpected result: %+v", result)
+}
@@ -15,7 +15,7 @@ import (
 	"github.com/MetalBlockchain/coreth/plugin/evm/message"
 )
 
-var _ message.Syncable = (*Summary)(nil)
+var _ message.ExtendedSyncable = (*Summary)(nil)
 
 // Summary provides the information necessary to sync a node starting
 // at the given block.
@@ -65,6 +65,11 @@ func (a *Summary) String() string {
 	return fmt.Sprintf("Summary(BlockHash=%s, BlockNumber=%d, BlockRoot=%s, AtomicRoot=%s)", a.BlockHash, a.BlockNumber, a.BlockRoot, a.AtomicRoot)
 }
 
+// ExtraTrieRoots returns the root of the atomic trie at the summary height.
+func (a *Summary) ExtraTrieRoots() map[message.NodeType]common.Hash {
+	return map[message.NodeType]common.Hash{TrieNode: a.AtomicRoot}
+}
+
 func (a *Summary) Accept(context.Context) (block.StateSyncMode, error) {
 	if a.acceptImpl == nil {
 		return block.StateSyncSkipped, fmt.Errorf("accept implementation not specified for summary: %s", a)
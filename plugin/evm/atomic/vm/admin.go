@@ -0,0 +1,51 @@
+// Copyright (C) 2019-2025, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vm
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/MetalBlockchain/libevm/log"
+
+	"github.com/MetalBlockchain/coreth/plugin/evm/client"
+	"github.com/MetalBlockchain/coreth/plugin/evm/customrawdb"
+
+	atomicstate "github.com/MetalBlockchain/coreth/plugin/evm/atomic/state"
+)
+
+// AdminAPI offers admin API methods for the atomic state of the VM
+type AdminAPI struct{ vm *VM }
+
+// VerifyAtomicTrie rebuilds the atomic trie from the atomic repository and
+// compares it to the committed atomic trie roots, up to the last committed
+// height when the call is made. The atomic trie is not repaired, as repairing
+// requires the node to be stopped (see cmd/corethdb).
+func (service *AdminAPI) VerifyAtomicTrie(_ *http.Request, args *client.VerifyAtomicTrieArgs, reply *client.VerifyAtomicTrieReply) error {
+	// Blocks accepted while verifying only commit roots above the end height,
+	// so the lock is not held while verifying.
+	service.vm.Ctx.Lock.Lock()
+	_, lastCommittedHeight := service.vm.AtomicBackend.AtomicTrie().LastCommitted()
+	service.vm.Ctx.Lock.Unlock()
+
+	config := atomicstate.VerifyConfig{
+		CommitInterval: service.vm.InnerVM.Config().CommitInterval,
+		EndHeight:      lastCommittedHeight,
+	}
+	if args.StartHeight != nil {
+		config.StartHeight = uint64(*args.StartHeight)
+	} else {
+		// The atomic repository does not index the atomic txs accepted before
+		// the last state sync.
+		config.StartHeight = customrawdb.GetLatestSyncPerformed(service.vm.InnerVM.Ethereum().ChainDb())
+	}
+	log.Info("Admin: VerifyAtomicTrie called", "startHeight", config.StartHeight, "endHeight", config.EndHeight)
+
+	result, err := atomicstate.VerifyAtomicTrie(service.vm.AtomicTxRepository, config)
+	if err != nil {
+		return fmt.Errorf("failed to verify atomic trie: %w", err)
+	}
+	reply.Result = result
+	return nil
+}
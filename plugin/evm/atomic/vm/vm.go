@@ -74,6 +74,7 @@ const (
 	maxAtomicTxMempoolGas   = ap5.AtomicGasLimit
 	atomicTxGossipNamespace = "atomic_tx_gossip"
 	avaxEndpoint            = "/avax"
+	avaxAdminEndpoint       = "/avax/admin"
 )
 
 type VM struct {
@@ -356,8 +357,23 @@ func (vm *VM) CreateHandlers(ctx context.Context) (map[string]http.Handler, erro
 	if err != nil {
 		return nil, fmt.Errorf("failed to register service for AVAX API due to %w", err)
 	}
+	apis[avaxEndpoint], err = vm.InnerVM.AuthHandler("avax", avaxAPI)
+	if err != nil {
+		return nil, err
+	}
 	log.Info("AVAX API enabled")
-	apis[avaxEndpoint] = avaxAPI
+
+	if vm.InnerVM.Config().AdminAPIEnabled {
+		avaxAdminAPI, err := rpc.NewHandler("admin", &AdminAPI{vm})
+		if err != nil {
+			return nil, fmt.Errorf("failed to register service for AVAX admin API due to %w", err)
+		}
+		apis[avaxAdminEndpoint], err = vm.InnerVM.AuthHandler("admin", avaxAdminAPI)
+		if err != nil {
+			return nil, err
+		}
+		log.Info("AVAX admin API enabled")
+	}
 	return apis, nil
 }
 
@@ -642,10 +658,8 @@ func (vm *VM) onFinalizeAndAssemble(
 
 func (vm *VM) onExtraStateChange(block *types.Block, parent *types.Header, statedb *state.StateDB) (*big.Int, *big.Int, error) {
 	var (
-		batchContribution *big.Int = big.NewInt(0)
-		batchGasUsed      *big.Int = big.NewInt(0)
-		header                     = block.Header()
-		chainConfig                = vm.InnerVM.ChainConfig()
+		header      = block.Header()
+		chainConfig = vm.InnerVM.ChainConfig()
 		// We cannot use chain config from InnerVM since it's not available when this function is called for the first time (bc.loadLastState).
 		rules      = chainConfig.Rules(header.Number, params.IsMergeTODO, header.Time)
 		rulesExtra = *params.GetRulesExtra(rules)
@@ -680,15 +694,39 @@ func (vm *VM) onExtraStateChange(block *types.Block, parent *types.Header, state
 	if len(txs) == 0 {
 		return nil, nil, nil
 	}
+	return ApplyTxs(vm.Ctx, chainConfig, block, parent, statedb, txs)
+}
+
+// ApplyTxs applies the state transfers of the atomic [txs] included in
+// [block] to [statedb], and returns their contribution to the block fee and
+// the atomic gas they used.
+//
+// The txs must have been verified against shared memory and the ancestors of
+// [block] beforehand.
+func ApplyTxs(
+	ctx *snow.Context,
+	chainConfig *params.ChainConfig,
+	block *types.Block,
+	parent *types.Header,
+	statedb *state.StateDB,
+	txs []*atomic.Tx,
+) (*big.Int, *big.Int, error) {
+	var (
+		batchContribution *big.Int = big.NewInt(0)
+		batchGasUsed      *big.Int = big.NewInt(0)
+		header                     = block.Header()
+		rules                      = chainConfig.Rules(header.Number, params.IsMergeTODO, header.Time)
+		rulesExtra                 = *params.GetRulesExtra(rules)
+	)
 
 	wrappedStateDB := extstate.New(statedb)
 	for _, tx := range txs {
-		if err := tx.UnsignedAtomicTx.EVMStateTransfer(vm.Ctx, wrappedStateDB); err != nil {
+		if err := tx.UnsignedAtomicTx.EVMStateTransfer(ctx, wrappedStateDB); err != nil {
 			return nil, nil, err
 		}
 		// If ApricotPhase4 is enabled, calculate the block fee contribution
 		if rulesExtra.IsApricotPhase4 {
-			contribution, gasUsed, err := tx.BlockFeeContribution(rulesExtra.IsApricotPhase5, vm.Ctx.AVAXAssetID, block.BaseFee())
+			contribution, gasUsed, err := tx.BlockFeeContribution(rulesExtra.IsApricotPhase5, ctx.AVAXAssetID, block.BaseFee())
 			if err != nil {
 				return nil, nil, err
 			}
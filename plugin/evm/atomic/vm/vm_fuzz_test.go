@@ -0,0 +1,370 @@
+// Copyright (C) 2019-2025, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vm
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/MetalBlockchain/metalgo/database/memdb"
+	"github.com/MetalBlockchain/metalgo/ids"
+	"github.com/MetalBlockchain/metalgo/snow/consensus/snowman"
+	"github.com/MetalBlockchain/metalgo/snow/snowtest"
+	"github.com/MetalBlockchain/metalgo/upgrade/upgradetest"
+	"github.com/MetalBlockchain/metalgo/utils/crypto/secp256k1"
+	"github.com/MetalBlockchain/metalgo/utils/hashing"
+	"github.com/MetalBlockchain/metalgo/utils/set"
+	"github.com/MetalBlockchain/metalgo/vms/components/avax"
+	"github.com/MetalBlockchain/metalgo/vms/components/chain"
+	"github.com/MetalBlockchain/metalgo/vms/secp256k1fx"
+	"github.com/MetalBlockchain/libevm/common"
+	"github.com/MetalBlockchain/libevm/core/types"
+	"github.com/MetalBlockchain/libevm/rlp"
+	"github.com/stretchr/testify/require"
+
+	"github.com/MetalBlockchain/coreth/consensus/dummy"
+	"github.com/MetalBlockchain/coreth/constants"
+	"github.com/MetalBlockchain/coreth/core"
+	"github.com/MetalBlockchain/coreth/core/state"
+	"github.com/MetalBlockchain/coreth/params/paramstest"
+	"github.com/MetalBlockchain/coreth/plugin/evm/atomic"
+	"github.com/MetalBlockchain/coreth/plugin/evm/extension"
+	"github.com/MetalBlockchain/coreth/plugin/evm/vmtest"
+
+	avalancheatomic "github.com/MetalBlockchain/metalgo/chains/atomic"
+	ethparams "github.com/MetalBlockchain/libevm/params"
+)
+
+const (
+	fuzzImportAmount = uint64(50_000_000)
+	fuzzExportAmount = uint64(10_000_000)
+	// maxFuzzOps bounds the number of operations applied to a VM per input.
+	maxFuzzOps = 32
+)
+
+// Operations applied by [FuzzAtomicBlocks]. The low bits of each input byte
+// select the operation and the remaining bits select the import tx.
+const (
+	fuzzOpIssue = iota
+	fuzzOpForceIssue
+	fuzzOpAccept
+	fuzzOpReject
+	numFuzzOps
+)
+
+func fuzzOp(op int, txIndex int) byte {
+	return byte(txIndex*numFuzzOps + op)
+}
+
+// newFuzzVM returns a VM whose shared memory holds one AVAX UTXO for each of
+// [vmtest.TestKeys], which is shut down at the end of the exec.
+func newFuzzVM(t *testing.T) *VM {
+	vm := setupFuzzVM(t)
+	t.Cleanup(func() {
+		require.NoError(t, vm.Shutdown(context.Background()))
+	})
+	return vm
+}
+
+// sharedFuzzVM returns a function returning the same VM, as created by
+// [newFuzzVM], to every exec of the fuzz target. It must only be used by
+// targets that don't modify the state of the VM, as creating a VM dominates
+// the cost of an exec.
+func sharedFuzzVM(f *testing.F) func(t *testing.T) *VM {
+	var (
+		once sync.Once
+		vm   *VM
+	)
+	f.Cleanup(func() {
+		if vm != nil {
+			require.NoError(f, vm.Shutdown(context.Background()))
+		}
+	})
+	return func(t *testing.T) *VM {
+		once.Do(func() {
+			vm = setupFuzzVM(t)
+		})
+		require.NotNil(t, vm, "failed to create the shared VM")
+		return vm
+	}
+}
+
+func setupFuzzVM(t *testing.T) *VM {
+	fork := upgradetest.Latest
+	vm := newAtomicTestVM()
+	tvm := vmtest.SetupTestVM(t, vm, vmtest.TestVMConfig{
+		Fork: &fork,
+	})
+
+	utxos := make(map[ids.ShortID]uint64, len(vmtest.TestShortIDAddrs))
+	for _, addr := range vmtest.TestShortIDAddrs {
+		utxos[addr] = fuzzImportAmount
+	}
+	require.NoError(t, addUTXOs(tvm.AtomicMemory, vm.Ctx, utxos))
+	return vm
+}
+
+// fuzzImportTxs returns, for every key, an import tx of its UTXO to each of
+// [vmtest.TestEthAddrs]. Import txs of the same key conflict with each other.
+func fuzzImportTxs(t *testing.T, vm *VM) []*atomic.Tx {
+	var txs []*atomic.Tx
+	for _, key := range vmtest.TestKeys {
+		for _, to := range vmtest.TestEthAddrs {
+			tx, err := vm.newImportTx(vm.Ctx.XChainID, to, vmtest.InitialBaseFee, []*secp256k1.PrivateKey{key})
+			require.NoError(t, err)
+			txs = append(txs, tx)
+		}
+	}
+	return txs
+}
+
+// atomicTxSeeds returns the signed bytes of valid import and export txs of the
+// chain created by [newFuzzVM].
+func atomicTxSeeds(f *testing.F) [][]byte {
+	ctx := snowtest.Context(f, snowtest.CChainID)
+	rules := *vmtest.ForkToRules(upgradetest.Latest)
+	memory := avalancheatomic.NewMemory(memdb.New())
+
+	var seeds [][]byte
+	for i, key := range vmtest.TestKeys {
+		addr := vmtest.TestShortIDAddrs[i]
+		txID, err := ids.ToID(hashing.ComputeHash256(addr.Bytes()))
+		require.NoError(f, err)
+		utxo, err := addUTXO(memory, ctx, txID, 0, ctx.AVAXAssetID, fuzzImportAmount, addr)
+		require.NoError(f, err)
+
+		importTx, err := atomic.NewImportTx(ctx, rules, uint64(time.Now().Unix()), ctx.XChainID, vmtest.TestEthAddrs[i], vmtest.InitialBaseFee, secp256k1fx.NewKeychain(key), []*avax.UTXO{utxo})
+		require.NoError(f, err)
+		seeds = append(seeds, importTx.SignedBytes())
+
+		exportTx := &atomic.Tx{UnsignedAtomicTx: &atomic.UnsignedExportTx{
+			NetworkID:        ctx.NetworkID,
+			BlockchainID:     ctx.ChainID,
+			DestinationChain: ctx.XChainID,
+			Ins: []atomic.EVMInput{{
+				Address: vmtest.TestEthAddrs[i],
+				Amount:  2 * fuzzExportAmount,
+				AssetID: ctx.AVAXAssetID,
+			}},
+			ExportedOutputs: []*avax.TransferableOutput{{
+				Asset: avax.Asset{ID: ctx.AVAXAssetID},
+				Out: &secp256k1fx.TransferOutput{
+					Amt: fuzzExportAmount,
+					OutputOwners: secp256k1fx.OutputOwners{
+						Threshold: 1,
+						Addrs:     []ids.ShortID{addr},
+					},
+				},
+			}},
+		}}
+		require.NoError(f, exportTx.Sign(atomic.Codec, [][]*secp256k1.PrivateKey{{key}}))
+		seeds = append(seeds, exportTx.SignedBytes())
+	}
+	return seeds
+}
+
+// blockSeeds returns the bytes of the genesis block of the chain created by
+// [newFuzzVM], and of blocks on top of it including an eth tx and an atomic
+// tx.
+func blockSeeds(f *testing.F) [][]byte {
+	genesis := vmtest.NewTestGenesis(paramstest.ForkToChainConfig[upgradetest.Latest])
+	importTx, err := atomic.ExtractAtomicTx(atomicTxSeeds(f)[0], atomic.Codec)
+	require.NoError(f, err)
+
+	cb := dummy.ConsensusCallbacks{
+		OnFinalizeAndAssemble: func(header *types.Header, _ *types.Header, _ *state.StateDB, txs []*types.Transaction) ([]byte, *big.Int, *big.Int, error) {
+			if len(txs) != 0 {
+				return nil, nil, nil, nil
+			}
+			contribution, gasUsed, err := importTx.BlockFeeContribution(true, snowtest.AVAXAssetID, header.BaseFee)
+			if err != nil {
+				return nil, nil, nil, err
+			}
+			extData, err := atomic.Codec.Marshal(atomic.CodecVersion, []*atomic.Tx{importTx})
+			return extData, contribution, gasUsed, err
+		},
+	}
+	signer := types.LatestSigner(genesis.Config)
+	_, blocks, _, err := core.GenerateChainWithGenesis(genesis, dummy.NewFakerWithCallbacks(cb), 2, 10, func(i int, g *core.BlockGen) {
+		g.SetCoinbase(constants.BlackholeAddr)
+		if i != 0 {
+			return
+		}
+		tx := types.NewTransaction(g.TxNonce(vmtest.TestEthAddrs[0]), vmtest.TestEthAddrs[1], common.Big1, ethparams.TxGas, vmtest.InitialBaseFee, nil)
+		signedTx, err := types.SignTx(tx, signer, vmtest.TestKeys[0].ToECDSA())
+		require.NoError(f, err)
+		g.AddTx(signedTx)
+	})
+	require.NoError(f, err)
+
+	var seeds [][]byte
+	for _, blk := range append([]*types.Block{genesis.ToBlock()}, blocks...) {
+		blkBytes, err := rlp.EncodeToBytes(blk)
+		require.NoError(f, err)
+		seeds = append(seeds, blkBytes)
+	}
+	return seeds
+}
+
+// atomicTxsOf returns the atomic txs included in [blk].
+func atomicTxsOf(t *testing.T, blk snowman.Block) []*atomic.Tx {
+	wrappedBlk, ok := blk.(*chain.BlockWrapper).Block.(extension.ExtendedBlock)
+	require.True(t, ok)
+	blockExtension, ok := wrappedBlk.GetBlockExtension().(atomic.AtomicBlockContext)
+	require.True(t, ok)
+	return blockExtension.AtomicTxs()
+}
+
+// requireNoDoubleSpend checks that the atomic txs of [blk] neither spend an
+// input twice nor spend any of [spent], and returns the inputs they spend.
+func requireNoDoubleSpend(t *testing.T, blk snowman.Block, spent set.Set[ids.ID]) set.Set[ids.ID] {
+	var inputs set.Set[ids.ID]
+	for _, tx := range atomicTxsOf(t, blk) {
+		txInputs := tx.InputUTXOs()
+		require.False(t, inputs.Overlaps(txInputs), "block spends an input twice")
+		inputs.Union(txInputs)
+	}
+	require.False(t, spent.Overlaps(inputs), "block spends an accepted input")
+	return inputs
+}
+
+// FuzzAtomicTxSemanticVerify checks that decoding atomic txs is canonical and
+// that their semantic verification against a fresh chain is deterministic.
+func FuzzAtomicTxSemanticVerify(f *testing.F) {
+	for _, seed := range atomicTxSeeds(f) {
+		f.Add(seed)
+	}
+	// Semantic verification doesn't modify the chain.
+	fuzzVM := sharedFuzzVM(f)
+	f.Fuzz(func(t *testing.T, txBytes []byte) {
+		require := require.New(t)
+
+		tx, err := atomic.ExtractAtomicTx(txBytes, atomic.Codec)
+		if err != nil {
+			return
+		}
+		// A tx has exactly one encoding, so its ID can not be malleated.
+		require.Equal(txBytes, tx.SignedBytes())
+
+		vm := fuzzVM(t)
+		backend := NewVerifierBackend(vm, vm.CurrentRules())
+		parent := vm.LastAcceptedExtendedBlock()
+		err = backend.SemanticVerify(tx, parent, vmtest.InitialBaseFee)
+		require.Equal(fmt.Sprint(err), fmt.Sprint(backend.SemanticVerify(tx, parent, vmtest.InitialBaseFee)))
+		if err != nil {
+			return
+		}
+
+		numInputs := 0
+		switch utx := tx.UnsignedAtomicTx.(type) {
+		case *atomic.UnsignedImportTx:
+			numInputs = len(utx.ImportedInputs)
+		case *atomic.UnsignedExportTx:
+			numInputs = len(utx.Ins)
+		}
+		require.Equal(numInputs, tx.InputUTXOs().Len(), "tx spends an input twice")
+	})
+}
+
+// FuzzParseBlock checks that parsing arbitrary block bytes, which verifies the
+// block syntactically, doesn't panic, and that parsed blocks pass the syntactic
+// verification of their atomic txs and re-encode to the same block.
+func FuzzParseBlock(f *testing.F) {
+	for _, seed := range blockSeeds(f) {
+		f.Add(seed)
+	}
+	// Parsed blocks are neither verified nor accepted, so they don't modify the
+	// chain.
+	fuzzVM := sharedFuzzVM(f)
+	f.Fuzz(func(t *testing.T, blkBytes []byte) {
+		require := require.New(t)
+		ctx := context.Background()
+
+		vm := fuzzVM(t)
+		blk, err := vm.ParseBlock(ctx, blkBytes)
+		if err != nil {
+			return
+		}
+		wrappedBlk, ok := blk.(*chain.BlockWrapper).Block.(extension.ExtendedBlock)
+		require.True(ok)
+		ethBlock := wrappedBlk.GetEthBlock()
+		rules := vm.rules(ethBlock.Number(), ethBlock.Time())
+		require.NoError(wrappedBlk.GetBlockExtension().SyntacticVerify(rules))
+
+		reparsedBlk, err := vm.ParseBlock(ctx, blk.Bytes())
+		require.NoError(err)
+		require.Equal(blk.ID(), reparsedBlk.ID())
+		require.Equal(blk.Bytes(), reparsedBlk.Bytes())
+	})
+}
+
+// FuzzAtomicBlocks applies sequences of operations issuing conflicting import
+// txs, and building, accepting and rejecting blocks including them. No
+// verified block may double spend a UTXO, and the accepted blocks must verify
+// identically on a second VM. Each exec modifies the chain, so it creates its
+// own VMs.
+func FuzzAtomicBlocks(f *testing.F) {
+	f.Add([]byte{fuzzOp(fuzzOpIssue, 0), fuzzOp(fuzzOpAccept, 0)})
+	f.Add([]byte{fuzzOp(fuzzOpIssue, 0), fuzzOp(fuzzOpAccept, 0), fuzzOp(fuzzOpForceIssue, 1), fuzzOp(fuzzOpAccept, 0)})
+	f.Add([]byte{fuzzOp(fuzzOpForceIssue, 0), fuzzOp(fuzzOpForceIssue, 1), fuzzOp(fuzzOpAccept, 0)})
+	f.Add([]byte{fuzzOp(fuzzOpIssue, 0), fuzzOp(fuzzOpReject, 0), fuzzOp(fuzzOpIssue, 3), fuzzOp(fuzzOpAccept, 0), fuzzOp(fuzzOpIssue, 6), fuzzOp(fuzzOpAccept, 0)})
+	f.Fuzz(func(t *testing.T, ops []byte) {
+		require := require.New(t)
+		ctx := context.Background()
+		if len(ops) > maxFuzzOps {
+			ops = ops[:maxFuzzOps]
+		}
+
+		vm := newFuzzVM(t)
+		txs := fuzzImportTxs(t, vm)
+		var (
+			spent    set.Set[ids.ID]
+			accepted [][]byte
+		)
+		for _, op := range ops {
+			tx := txs[int(op/numFuzzOps)%len(txs)]
+			switch op % numFuzzOps {
+			case fuzzOpIssue:
+				_ = vm.AtomicMempool.AddLocalTx(tx)
+			case fuzzOpForceIssue:
+				_ = vm.AtomicMempool.ForceAddTx(tx)
+			case fuzzOpAccept, fuzzOpReject:
+				vm.clock.Set(vm.clock.Time().Add(2 * time.Second))
+				// The block is verified when it is built.
+				blk, err := vm.BuildBlock(ctx)
+				if err != nil {
+					continue
+				}
+				require.NoError(blk.Verify(ctx))
+				inputs := requireNoDoubleSpend(t, blk, spent)
+				if op%numFuzzOps == fuzzOpReject {
+					require.NoError(blk.Reject(ctx))
+					continue
+				}
+				require.NoError(vm.SetPreference(ctx, blk.ID()))
+				require.NoError(blk.Accept(ctx))
+				spent.Union(inputs)
+				accepted = append(accepted, blk.Bytes())
+			}
+		}
+
+		replayVM := newFuzzVM(t)
+		replayVM.clock.Set(vm.clock.Time())
+		for _, blkBytes := range accepted {
+			blk, err := replayVM.ParseBlock(ctx, blkBytes)
+			require.NoError(err)
+			require.NoError(blk.Verify(ctx))
+			require.NoError(blk.Accept(ctx))
+		}
+		lastAccepted, err := vm.LastAccepted(ctx)
+		require.NoError(err)
+		replayLastAccepted, err := replayVM.LastAccepted(ctx)
+		require.NoError(err)
+		require.Equal(lastAccepted, replayLastAccepted)
+	})
+}
@@ -0,0 +1,117 @@
+// Copyright (C) 2019-2025, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package wallet
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/MetalBlockchain/metalgo/ids"
+	"github.com/MetalBlockchain/metalgo/utils/set"
+	"github.com/MetalBlockchain/metalgo/vms/components/avax"
+	"github.com/MetalBlockchain/libevm/common"
+
+	"github.com/MetalBlockchain/coreth/ethclient"
+	"github.com/MetalBlockchain/coreth/ethclient/corethclient"
+	"github.com/MetalBlockchain/coreth/plugin/evm/atomic"
+	"github.com/MetalBlockchain/coreth/plugin/evm/client"
+)
+
+// maxUTXOsToFetch is the maximum number of UTXOs fetched per request.
+const maxUTXOsToFetch = 1024
+
+var (
+	_ Backend = (*clientBackend)(nil)
+
+	errBalanceOverflow = errors.New("balance overflows uint64")
+)
+
+// clientBackend reads the chain and issues atomic txs over RPC.
+type clientBackend struct {
+	chain        Context
+	client       client.Client
+	ethClient    *ethclient.Client
+	corethClient *corethclient.Client
+}
+
+// NewClientBackend returns a Backend reading [chain] through [avaxClient], the
+// client of its avax API, and [ethClient], the client of its eth API.
+func NewClientBackend(chain Context, avaxClient client.Client, ethClient *ethclient.Client) Backend {
+	return &clientBackend{
+		chain:        chain,
+		client:       avaxClient,
+		ethClient:    ethClient,
+		corethClient: corethclient.New(ethClient.Client()),
+	}
+}
+
+func (b *clientBackend) UTXOs(ctx context.Context, sourceChain ids.ID, addrs set.Set[ids.ShortID]) ([]*avax.UTXO, error) {
+	var (
+		addrList  = addrs.List()
+		startAddr ids.ShortID
+		startUTXO ids.ID
+		utxos     []*avax.UTXO
+	)
+	for {
+		utxosBytes, endAddr, endUTXO, err := b.client.GetAtomicUTXOs(ctx, addrList, sourceChain.String(), maxUTXOsToFetch, startAddr, startUTXO)
+		if err != nil {
+			return nil, err
+		}
+		for _, utxoBytes := range utxosBytes {
+			utxo := &avax.UTXO{}
+			if _, err := atomic.Codec.Unmarshal(utxoBytes, utxo); err != nil {
+				return nil, fmt.Errorf("failed to unmarshal UTXO: %w", err)
+			}
+			utxos = append(utxos, utxo)
+		}
+		if len(utxosBytes) < maxUTXOsToFetch {
+			return utxos, nil
+		}
+		startAddr, startUTXO = endAddr, endUTXO
+	}
+}
+
+func (b *clientBackend) Balance(ctx context.Context, addr common.Address, assetID ids.ID) (uint64, error) {
+	if assetID != b.chain.AVAXAssetID {
+		balance, err := b.corethClient.AssetBalanceAt(ctx, addr, assetID, nil)
+		if err != nil {
+			return 0, err
+		}
+		return balanceUint64(balance, addr, assetID)
+	}
+	balance, err := b.ethClient.BalanceAt(ctx, addr, nil)
+	if err != nil {
+		return 0, err
+	}
+	// Convert the balance from wei to nAVAX.
+	return balanceUint64(new(big.Int).Div(balance, atomic.X2CRate.ToBig()), addr, assetID)
+}
+
+// balanceUint64 returns [balance] of [assetID] held by [addr] as the uint64
+// amounts of atomic txs, erroring rather than truncating it.
+func balanceUint64(balance *big.Int, addr common.Address, assetID ids.ID) (uint64, error) {
+	if !balance.IsUint64() {
+		return 0, fmt.Errorf("%w: %s of %s held by %s", errBalanceOverflow, balance, assetID, addr)
+	}
+	return balance.Uint64(), nil
+}
+
+func (b *clientBackend) Nonce(ctx context.Context, addr common.Address) (uint64, error) {
+	return b.ethClient.NonceAt(ctx, addr, nil)
+}
+
+func (b *clientBackend) BaseFee(ctx context.Context) (*big.Int, error) {
+	return b.ethClient.EstimateBaseFee(ctx)
+}
+
+func (b *clientBackend) IssueTx(ctx context.Context, tx *atomic.Tx) error {
+	_, err := b.client.IssueTx(ctx, tx.SignedBytes())
+	return err
+}
+
+func (b *clientBackend) TxStatus(ctx context.Context, txID ids.ID) (atomic.Status, error) {
+	return b.client.GetAtomicTxStatus(ctx, txID)
+}
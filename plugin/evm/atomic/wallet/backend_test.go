@@ -0,0 +1,27 @@
+// Copyright (C) 2019-2025, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package wallet
+
+import (
+	"math"
+	"math/big"
+	"testing"
+
+	"github.com/MetalBlockchain/metalgo/ids"
+	"github.com/MetalBlockchain/libevm/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBalanceUint64(t *testing.T) {
+	require := require.New(t)
+	assetID := ids.GenerateTestID()
+
+	balance, err := balanceUint64(new(big.Int).SetUint64(math.MaxUint64), common.Address{}, assetID)
+	require.NoError(err)
+	require.Equal(uint64(math.MaxUint64), balance)
+
+	overflow := new(big.Int).Add(new(big.Int).SetUint64(math.MaxUint64), big.NewInt(1))
+	_, err = balanceUint64(overflow, common.Address{}, assetID)
+	require.ErrorIs(err, errBalanceOverflow)
+}
@@ -0,0 +1,403 @@
+// Copyright (C) 2019-2025, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package wallet builds, signs and issues the atomic txs moving funds between
+// the C-Chain and the other chains of the primary network.
+package wallet
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/MetalBlockchain/metalgo/ids"
+	"github.com/MetalBlockchain/metalgo/utils"
+	"github.com/MetalBlockchain/metalgo/utils/crypto/secp256k1"
+	"github.com/MetalBlockchain/metalgo/utils/math"
+	"github.com/MetalBlockchain/metalgo/utils/set"
+	"github.com/MetalBlockchain/metalgo/utils/timer/mockable"
+	"github.com/MetalBlockchain/metalgo/vms/components/avax"
+	"github.com/MetalBlockchain/metalgo/vms/secp256k1fx"
+	"github.com/MetalBlockchain/libevm/common"
+
+	"github.com/MetalBlockchain/coreth/plugin/evm/atomic"
+)
+
+const pollFrequency = 100 * time.Millisecond
+
+var (
+	ErrInsufficientFunds = errors.New("insufficient funds")
+	ErrTxDropped         = errors.New("tx was dropped")
+)
+
+// Context is the chain the wallet builds atomic txs for.
+type Context struct {
+	NetworkID    uint32
+	BlockchainID ids.ID
+	AVAXAssetID  ids.ID
+	// IsBanff is whether Banff is active on the chain, after which atomic txs
+	// can only move AVAX.
+	IsBanff bool
+}
+
+// Backend is the view of the chain the wallet builds atomic txs against, and
+// issues them to.
+type Backend interface {
+	// UTXOs returns the UTXOs exported by [sourceChain] to the chain owned by
+	// any of [addrs].
+	UTXOs(ctx context.Context, sourceChain ids.ID, addrs set.Set[ids.ShortID]) ([]*avax.UTXO, error)
+	// Balance returns the balance of [assetID] of [addr], denominated in nAVAX
+	// for AVAX.
+	Balance(ctx context.Context, addr common.Address, assetID ids.ID) (uint64, error)
+	// Nonce returns the nonce of [addr].
+	Nonce(ctx context.Context, addr common.Address) (uint64, error)
+	// BaseFee returns the estimated base fee of the next block.
+	BaseFee(ctx context.Context) (*big.Int, error)
+	// IssueTx issues [tx] to the chain.
+	IssueTx(ctx context.Context, tx *atomic.Tx) error
+	// TxStatus returns the status of the atomic tx [txID].
+	TxStatus(ctx context.Context, txID ids.ID) (atomic.Status, error)
+}
+
+// Wallet builds and issues the atomic txs spending the funds of a set of keys.
+// The funds of the keys are spent in order.
+//
+// Assumes Apricot Phase 5 is active. Atomic txs of assets other than AVAX are
+// only built before Banff.
+type Wallet struct {
+	chain    Context
+	backend  Backend
+	keys     []*secp256k1.PrivateKey
+	keychain *secp256k1fx.Keychain
+	clock    mockable.Clock
+}
+
+// New returns a wallet spending the funds of [keys] on [chain].
+func New(chain Context, backend Backend, keys ...*secp256k1.PrivateKey) *Wallet {
+	return &Wallet{
+		chain:    chain,
+		backend:  backend,
+		keys:     keys,
+		keychain: secp256k1fx.NewKeychain(keys...),
+	}
+}
+
+// Addresses returns the addresses of the keys of the wallet owning UTXOs.
+func (w *Wallet) Addresses() set.Set[ids.ShortID] {
+	return w.keychain.Addresses()
+}
+
+// EthAddresses returns the addresses of the accounts of the keys of the wallet.
+func (w *Wallet) EthAddresses() []common.Address {
+	addrs := make([]common.Address, len(w.keys))
+	for i, key := range w.keys {
+		addrs[i] = key.EthAddress()
+	}
+	return addrs
+}
+
+// NewImportTx returns a tx importing to [to] every UTXO exported by
+// [sourceChain] spendable by the keys of the wallet. The fee is paid from the
+// imported AVAX. After Banff, UTXOs of assets other than AVAX are left
+// unspent.
+func (w *Wallet) NewImportTx(ctx context.Context, sourceChain ids.ID, to common.Address) (*atomic.Tx, error) {
+	utxos, err := w.backend.UTXOs(ctx, sourceChain, w.keychain.Addresses())
+	if err != nil {
+		return nil, fmt.Errorf("problem retrieving atomic UTXOs: %w", err)
+	}
+	baseFee, err := w.backend.BaseFee(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("problem estimating base fee: %w", err)
+	}
+
+	var (
+		now      = uint64(w.clock.Time().Unix())
+		ins      []*avax.TransferableInput
+		signers  [][]*secp256k1.PrivateKey
+		imported = make(map[ids.ID]uint64)
+	)
+	for _, utxo := range utxos {
+		assetID := utxo.AssetID()
+		if w.chain.IsBanff && assetID != w.chain.AVAXAssetID {
+			continue
+		}
+		inIntf, utxoSigners, err := w.keychain.Spend(utxo.Out, now)
+		if err != nil {
+			// The UTXO isn't spendable by the keys yet.
+			continue
+		}
+		in, ok := inIntf.(avax.TransferableIn)
+		if !ok {
+			continue
+		}
+		imported[assetID], err = math.Add(imported[assetID], in.Amount())
+		if err != nil {
+			return nil, err
+		}
+		ins = append(ins, &avax.TransferableInput{
+			UTXOID: utxo.UTXOID,
+			Asset:  utxo.Asset,
+			In:     in,
+		})
+		signers = append(signers, utxoSigners)
+	}
+	if len(ins) == 0 {
+		return nil, atomic.ErrNoImportInputs
+	}
+	avax.SortTransferableInputsWithSigners(ins, signers)
+
+	outs := make([]atomic.EVMOutput, 0, len(imported))
+	for assetID, amount := range imported {
+		// The AVAX output is added once the fee is known.
+		if assetID == w.chain.AVAXAssetID {
+			continue
+		}
+		outs = append(outs, atomic.EVMOutput{
+			Address: to,
+			Amount:  amount,
+			AssetID: assetID,
+		})
+	}
+
+	utx := &atomic.UnsignedImportTx{
+		NetworkID:      w.chain.NetworkID,
+		BlockchainID:   w.chain.BlockchainID,
+		SourceChain:    sourceChain,
+		ImportedInputs: ins,
+		Outs:           outs,
+	}
+	gasUsed, err := signedGasUsed(utx)
+	if err != nil {
+		return nil, err
+	}
+	feeWithoutChange, err := atomic.CalculateDynamicFee(gasUsed, baseFee)
+	if err != nil {
+		return nil, err
+	}
+	feeWithChange, err := atomic.CalculateDynamicFee(gasUsed+atomic.EVMOutputGas, baseFee)
+	if err != nil {
+		return nil, err
+	}
+
+	importedAVAX := imported[w.chain.AVAXAssetID]
+	if importedAVAX < feeWithoutChange {
+		return nil, fmt.Errorf("%w: importing %d nAVAX to pay a fee of %d nAVAX", ErrInsufficientFunds, importedAVAX, feeWithoutChange)
+	}
+	if importedAVAX > feeWithChange {
+		utx.Outs = append(utx.Outs, atomic.EVMOutput{
+			Address: to,
+			Amount:  importedAVAX - feeWithChange,
+			AssetID: w.chain.AVAXAssetID,
+		})
+	}
+	if len(utx.Outs) == 0 {
+		return nil, atomic.ErrNoEVMOutputs
+	}
+	utils.Sort(utx.Outs)
+
+	tx := &atomic.Tx{UnsignedAtomicTx: utx}
+	return tx, tx.Sign(atomic.Codec, signers)
+}
+
+// NewExportTx returns a tx exporting [amount] of [assetID] from the accounts
+// of the keys of the wallet to [to] on [destinationChain]. The fee is paid from
+// the AVAX balances of the accounts.
+func (w *Wallet) NewExportTx(ctx context.Context, destinationChain ids.ID, assetID ids.ID, amount uint64, to ids.ShortID) (*atomic.Tx, error) {
+	if w.chain.IsBanff && assetID != w.chain.AVAXAssetID {
+		return nil, atomic.ErrExportNonAVAXOutputBanff
+	}
+	baseFee, err := w.backend.BaseFee(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("problem estimating base fee: %w", err)
+	}
+
+	utx := &atomic.UnsignedExportTx{
+		NetworkID:        w.chain.NetworkID,
+		BlockchainID:     w.chain.BlockchainID,
+		DestinationChain: destinationChain,
+		ExportedOutputs: []*avax.TransferableOutput{{
+			Asset: avax.Asset{ID: assetID},
+			Out: &secp256k1fx.TransferOutput{
+				Amt: amount,
+				OutputOwners: secp256k1fx.OutputOwners{
+					Threshold: 1,
+					Addrs:     []ids.ShortID{to},
+				},
+			},
+		}},
+	}
+	var (
+		signers    [][]*secp256k1.PrivateKey
+		avaxAmount = amount
+	)
+	if assetID != w.chain.AVAXAssetID {
+		utx.Ins, signers, err = w.spend(ctx, assetID, amount)
+		if err != nil {
+			return nil, err
+		}
+		avaxAmount = 0
+	}
+
+	gasUsed, err := signedGasUsed(utx)
+	if err != nil {
+		return nil, err
+	}
+	avaxIns, avaxSigners, err := w.spendWithFee(ctx, avaxAmount, gasUsed, baseFee)
+	if err != nil {
+		return nil, err
+	}
+	utx.Ins = append(utx.Ins, avaxIns...)
+	signers = append(signers, avaxSigners...)
+	atomic.SortEVMInputsAndSigners(utx.Ins, signers)
+
+	tx := &atomic.Tx{UnsignedAtomicTx: utx}
+	return tx, tx.Sign(atomic.Codec, signers)
+}
+
+// spend returns the inputs spending [amount] of [assetID] from the accounts of
+// the keys of the wallet.
+func (w *Wallet) spend(ctx context.Context, assetID ids.ID, amount uint64) ([]atomic.EVMInput, [][]*secp256k1.PrivateKey, error) {
+	var (
+		ins     []atomic.EVMInput
+		signers [][]*secp256k1.PrivateKey
+	)
+	for _, key := range w.keys {
+		if amount == 0 {
+			break
+		}
+		addr := key.EthAddress()
+		balance, err := w.backend.Balance(ctx, addr, assetID)
+		if err != nil {
+			return nil, nil, err
+		}
+		if balance == 0 {
+			continue
+		}
+		in, err := w.newInput(ctx, addr, assetID, min(balance, amount))
+		if err != nil {
+			return nil, nil, err
+		}
+		ins = append(ins, in)
+		signers = append(signers, []*secp256k1.PrivateKey{key})
+		amount -= in.Amount
+	}
+	if amount > 0 {
+		return nil, nil, fmt.Errorf("%w: missing %d of %s", ErrInsufficientFunds, amount, assetID)
+	}
+	return ins, signers, nil
+}
+
+// spendWithFee returns the AVAX inputs spending [amount] and the fee of a tx
+// using [gasUsed] without them from the accounts of the keys of the wallet.
+// Each input adds to the fee, so accounts with a balance not covering the fee
+// of their input are skipped.
+func (w *Wallet) spendWithFee(ctx context.Context, amount uint64, gasUsed uint64, baseFee *big.Int) ([]atomic.EVMInput, [][]*secp256k1.PrivateKey, error) {
+	fee, err := atomic.CalculateDynamicFee(gasUsed, baseFee)
+	if err != nil {
+		return nil, nil, err
+	}
+	amount, err = math.Add(amount, fee)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var (
+		ins     []atomic.EVMInput
+		signers [][]*secp256k1.PrivateKey
+	)
+	for _, key := range w.keys {
+		if amount == 0 {
+			break
+		}
+		newFee, err := atomic.CalculateDynamicFee(gasUsed+atomic.EVMInputGas, baseFee)
+		if err != nil {
+			return nil, nil, err
+		}
+		inputFee := newFee - fee
+
+		addr := key.EthAddress()
+		balance, err := w.backend.Balance(ctx, addr, w.chain.AVAXAssetID)
+		if err != nil {
+			return nil, nil, err
+		}
+		if balance <= inputFee {
+			continue
+		}
+		gasUsed += atomic.EVMInputGas
+		fee = newFee
+		amount, err = math.Add(amount, inputFee)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		in, err := w.newInput(ctx, addr, w.chain.AVAXAssetID, min(balance, amount))
+		if err != nil {
+			return nil, nil, err
+		}
+		ins = append(ins, in)
+		signers = append(signers, []*secp256k1.PrivateKey{key})
+		amount -= in.Amount
+	}
+	if amount > 0 {
+		return nil, nil, fmt.Errorf("%w: missing %d nAVAX", ErrInsufficientFunds, amount)
+	}
+	return ins, signers, nil
+}
+
+func (w *Wallet) newInput(ctx context.Context, addr common.Address, assetID ids.ID, amount uint64) (atomic.EVMInput, error) {
+	nonce, err := w.backend.Nonce(ctx, addr)
+	if err != nil {
+		return atomic.EVMInput{}, err
+	}
+	return atomic.EVMInput{
+		Address: addr,
+		Amount:  amount,
+		AssetID: assetID,
+		Nonce:   nonce,
+	}, nil
+}
+
+// signedGasUsed returns the gas used by [utx] once signed.
+func signedGasUsed(utx atomic.UnsignedAtomicTx) (uint64, error) {
+	tx := &atomic.Tx{UnsignedAtomicTx: utx}
+	if err := tx.Sign(atomic.Codec, nil); err != nil {
+		return 0, err
+	}
+	return tx.GasUsed(true)
+}
+
+// IssueTx issues [tx] and waits until it is accepted.
+func (w *Wallet) IssueTx(ctx context.Context, tx *atomic.Tx) error {
+	if err := w.backend.IssueTx(ctx, tx); err != nil {
+		return err
+	}
+	return w.AwaitTx(ctx, tx.ID())
+}
+
+// AwaitTx waits until the atomic tx [txID] is accepted. It returns
+// [ErrTxDropped] if the tx was dropped.
+func (w *Wallet) AwaitTx(ctx context.Context, txID ids.ID) error {
+	ticker := time.NewTicker(pollFrequency)
+	defer ticker.Stop()
+
+	for {
+		status, err := w.backend.TxStatus(ctx, txID)
+		if err != nil {
+			return err
+		}
+		switch status {
+		case atomic.Accepted:
+			return nil
+		case atomic.Dropped:
+			return fmt.Errorf("%w: %s", ErrTxDropped, txID)
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
@@ -0,0 +1,256 @@
+// Copyright (C) 2019-2025, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package wallet_test
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/MetalBlockchain/metalgo/ids"
+	"github.com/MetalBlockchain/metalgo/upgrade/upgradetest"
+	"github.com/MetalBlockchain/metalgo/utils/crypto/secp256k1"
+	"github.com/MetalBlockchain/metalgo/utils/units"
+	"github.com/MetalBlockchain/metalgo/vms/secp256k1fx"
+	"github.com/stretchr/testify/require"
+
+	"github.com/MetalBlockchain/coreth/plugin/evm/atomic"
+	"github.com/MetalBlockchain/coreth/plugin/evm/atomic/wallet"
+	"github.com/MetalBlockchain/coreth/plugin/evm/atomic/wallet/wallettest"
+	"github.com/MetalBlockchain/coreth/plugin/evm/vmtest"
+)
+
+func newKey(t *testing.T) *secp256k1.PrivateKey {
+	key, err := secp256k1.NewPrivateKey()
+	require.NoError(t, err)
+	return key
+}
+
+// requireFee checks that [tx] burns exactly the fee of its gas at [baseFee].
+func requireFee(t *testing.T, tx *atomic.Tx, avaxAssetID ids.ID, baseFee *big.Int) uint64 {
+	gasUsed, err := tx.GasUsed(true)
+	require.NoError(t, err)
+	fee, err := atomic.CalculateDynamicFee(gasUsed, baseFee)
+	require.NoError(t, err)
+	burned, err := tx.Burned(avaxAssetID)
+	require.NoError(t, err)
+	require.Equal(t, fee, burned)
+	return burned
+}
+
+func TestImportExport(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+	backend := wallettest.NewBackend(t, *vmtest.ForkToRules(upgradetest.Latest))
+	chain := backend.Context()
+	baseFee, err := backend.BaseFee(ctx)
+	require.NoError(err)
+
+	key := newKey(t)
+	addr := key.EthAddress()
+	w := wallet.New(chain, backend, key)
+	_, err = backend.AddUTXO(chain.AVAXAssetID, units.Avax, key.Address())
+	require.NoError(err)
+	_, err = backend.AddUTXO(chain.AVAXAssetID, units.Avax, key.Address())
+	require.NoError(err)
+
+	tx, err := w.NewImportTx(ctx, backend.XChainID(), addr)
+	require.NoError(err)
+	fee := requireFee(t, tx, chain.AVAXAssetID, baseFee)
+	require.NoError(w.IssueTx(ctx, tx))
+
+	balance, err := backend.Balance(ctx, addr, chain.AVAXAssetID)
+	require.NoError(err)
+	require.Equal(2*units.Avax-fee, balance)
+	utxos, err := backend.UTXOs(ctx, backend.XChainID(), w.Addresses())
+	require.NoError(err)
+	require.Empty(utxos)
+
+	// The imported UTXOs can't be imported again.
+	_, err = w.NewImportTx(ctx, backend.XChainID(), addr)
+	require.ErrorIs(err, atomic.ErrNoImportInputs)
+	require.Error(backend.IssueTx(ctx, tx))
+
+	to := newKey(t).Address()
+	tx, err = w.NewExportTx(ctx, backend.XChainID(), chain.AVAXAssetID, units.Avax, to)
+	require.NoError(err)
+	exportFee := requireFee(t, tx, chain.AVAXAssetID, baseFee)
+	require.NoError(w.IssueTx(ctx, tx))
+
+	newBalance, err := backend.Balance(ctx, addr, chain.AVAXAssetID)
+	require.NoError(err)
+	require.Equal(balance-units.Avax-exportFee, newBalance)
+	nonce, err := backend.Nonce(ctx, addr)
+	require.NoError(err)
+	require.Equal(uint64(1), nonce)
+
+	exported, err := backend.ExportedUTXOs(to)
+	require.NoError(err)
+	require.Len(exported, 1)
+	require.Equal(chain.AVAXAssetID, exported[0].AssetID())
+	require.Equal(units.Avax, exported[0].Out.(*secp256k1fx.TransferOutput).Amount())
+}
+
+func TestImportInsufficientFunds(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+	backend := wallettest.NewBackend(t, *vmtest.ForkToRules(upgradetest.Latest))
+	chain := backend.Context()
+
+	key := newKey(t)
+	w := wallet.New(chain, backend, key)
+	_, err := w.NewImportTx(ctx, backend.XChainID(), key.EthAddress())
+	require.ErrorIs(err, atomic.ErrNoImportInputs)
+
+	_, err = backend.AddUTXO(chain.AVAXAssetID, 1, key.Address())
+	require.NoError(err)
+	_, err = w.NewImportTx(ctx, backend.XChainID(), key.EthAddress())
+	require.ErrorIs(err, wallet.ErrInsufficientFunds)
+
+	// UTXOs owned by other keys aren't imported.
+	_, err = backend.AddUTXO(chain.AVAXAssetID, units.Avax, newKey(t).Address())
+	require.NoError(err)
+	_, err = w.NewImportTx(ctx, backend.XChainID(), key.EthAddress())
+	require.ErrorIs(err, wallet.ErrInsufficientFunds)
+}
+
+func TestExportFromMultipleAccounts(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+	backend := wallettest.NewBackend(t, *vmtest.ForkToRules(upgradetest.Latest))
+	chain := backend.Context()
+	baseFee, err := backend.BaseFee(ctx)
+	require.NoError(err)
+
+	empty, key0, key1 := newKey(t), newKey(t), newKey(t)
+	backend.SetBalance(key0.EthAddress(), chain.AVAXAssetID, units.Avax)
+	backend.SetBalance(key1.EthAddress(), chain.AVAXAssetID, units.Avax)
+	w := wallet.New(chain, backend, empty, key0, key1)
+
+	to := newKey(t).Address()
+	_, err = w.NewExportTx(ctx, backend.XChainID(), chain.AVAXAssetID, 2*units.Avax, to)
+	require.ErrorIs(err, wallet.ErrInsufficientFunds)
+
+	amount := 3 * units.Avax / 2
+	tx, err := w.NewExportTx(ctx, backend.XChainID(), chain.AVAXAssetID, amount, to)
+	require.NoError(err)
+	require.Len(tx.UnsignedAtomicTx.(*atomic.UnsignedExportTx).Ins, 2)
+	fee := requireFee(t, tx, chain.AVAXAssetID, baseFee)
+	require.NoError(w.IssueTx(ctx, tx))
+
+	var total uint64
+	for _, addr := range w.EthAddresses() {
+		balance, err := backend.Balance(ctx, addr, chain.AVAXAssetID)
+		require.NoError(err)
+		total += balance
+	}
+	require.Equal(2*units.Avax-amount-fee, total)
+}
+
+func TestBaseFeeIncrease(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+	backend := wallettest.NewBackend(t, *vmtest.ForkToRules(upgradetest.Latest))
+	chain := backend.Context()
+
+	key := newKey(t)
+	w := wallet.New(chain, backend, key)
+	_, err := backend.AddUTXO(chain.AVAXAssetID, units.Avax, key.Address())
+	require.NoError(err)
+	tx, err := w.NewImportTx(ctx, backend.XChainID(), key.EthAddress())
+	require.NoError(err)
+
+	// The tx doesn't pay for a base fee higher than estimated.
+	baseFee, err := backend.BaseFee(ctx)
+	require.NoError(err)
+	backend.SetBaseFee(new(big.Int).Mul(baseFee, big.NewInt(2)))
+	require.Error(w.IssueTx(ctx, tx))
+
+	tx, err = w.NewImportTx(ctx, backend.XChainID(), key.EthAddress())
+	require.NoError(err)
+	require.NoError(w.IssueTx(ctx, tx))
+}
+
+func TestMultiCoin(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+	// Assets other than AVAX can't be moved across chains since Banff.
+	backend := wallettest.NewBackend(t, *vmtest.ForkToRules(upgradetest.ApricotPhase5))
+	chain := backend.Context()
+
+	key := newKey(t)
+	addr := key.EthAddress()
+	assetID := ids.GenerateTestID()
+	w := wallet.New(chain, backend, key)
+	_, err := backend.AddUTXO(chain.AVAXAssetID, units.Avax, key.Address())
+	require.NoError(err)
+	_, err = backend.AddUTXO(assetID, 100, key.Address())
+	require.NoError(err)
+
+	tx, err := w.NewImportTx(ctx, backend.XChainID(), addr)
+	require.NoError(err)
+	require.NoError(w.IssueTx(ctx, tx))
+	balance, err := backend.Balance(ctx, addr, assetID)
+	require.NoError(err)
+	require.Equal(uint64(100), balance)
+
+	to := newKey(t).Address()
+	_, err = w.NewExportTx(ctx, backend.XChainID(), assetID, 101, to)
+	require.ErrorIs(err, wallet.ErrInsufficientFunds)
+	tx, err = w.NewExportTx(ctx, backend.XChainID(), assetID, 60, to)
+	require.NoError(err)
+	require.NoError(w.IssueTx(ctx, tx))
+
+	balance, err = backend.Balance(ctx, addr, assetID)
+	require.NoError(err)
+	require.Equal(uint64(40), balance)
+	exported, err := backend.ExportedUTXOs(to)
+	require.NoError(err)
+	require.Len(exported, 1)
+	require.Equal(assetID, exported[0].AssetID())
+}
+
+func TestAwaitTx(t *testing.T) {
+	backend := wallettest.NewBackend(t, *vmtest.ForkToRules(upgradetest.Latest))
+	w := wallet.New(backend.Context(), backend)
+
+	// Unknown txs are waited for until the context is done.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	require.ErrorIs(t, w.AwaitTx(ctx, ids.GenerateTestID()), context.Canceled)
+}
+
+func TestMultiCoinBanff(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+	backend := wallettest.NewBackend(t, *vmtest.ForkToRules(upgradetest.Latest))
+	chain := backend.Context()
+
+	key := newKey(t)
+	addr := key.EthAddress()
+	assetID := ids.GenerateTestID()
+	w := wallet.New(chain, backend, key)
+	_, err := backend.AddUTXO(chain.AVAXAssetID, units.Avax, key.Address())
+	require.NoError(err)
+	utxo, err := backend.AddUTXO(assetID, 100, key.Address())
+	require.NoError(err)
+
+	// Only the AVAX UTXO is imported.
+	tx, err := w.NewImportTx(ctx, backend.XChainID(), addr)
+	require.NoError(err)
+	for _, in := range tx.UnsignedAtomicTx.(*atomic.UnsignedImportTx).ImportedInputs {
+		require.Equal(chain.AVAXAssetID, in.AssetID())
+	}
+	require.NoError(w.IssueTx(ctx, tx))
+	balance, err := backend.Balance(ctx, addr, assetID)
+	require.NoError(err)
+	require.Zero(balance)
+	utxos, err := backend.UTXOs(ctx, backend.XChainID(), w.Addresses())
+	require.NoError(err)
+	require.Len(utxos, 1)
+	require.Equal(utxo.InputID(), utxos[0].InputID())
+
+	_, err = w.NewExportTx(ctx, backend.XChainID(), assetID, 1, newKey(t).Address())
+	require.ErrorIs(err, atomic.ErrExportNonAVAXOutputBanff)
+}
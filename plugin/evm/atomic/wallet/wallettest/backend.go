@@ -0,0 +1,341 @@
+// Copyright (C) 2019-2025, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package wallettest provides an in-memory chain to build and issue atomic txs
+// against in tests.
+package wallettest
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"sync"
+	"testing"
+
+	"github.com/MetalBlockchain/metalgo/codec"
+	"github.com/MetalBlockchain/metalgo/codec/linearcodec"
+	"github.com/MetalBlockchain/metalgo/database/memdb"
+	"github.com/MetalBlockchain/metalgo/ids"
+	"github.com/MetalBlockchain/metalgo/snow"
+	"github.com/MetalBlockchain/metalgo/snow/snowtest"
+	"github.com/MetalBlockchain/metalgo/utils/crypto/secp256k1"
+	"github.com/MetalBlockchain/metalgo/utils/logging"
+	"github.com/MetalBlockchain/metalgo/utils/set"
+	"github.com/MetalBlockchain/metalgo/utils/timer/mockable"
+	"github.com/MetalBlockchain/metalgo/vms/components/avax"
+	"github.com/MetalBlockchain/metalgo/vms/secp256k1fx"
+	"github.com/MetalBlockchain/libevm/common"
+	"github.com/holiman/uint256"
+	"github.com/stretchr/testify/require"
+
+	"github.com/MetalBlockchain/coreth/params/extras"
+	"github.com/MetalBlockchain/coreth/plugin/evm/atomic"
+	"github.com/MetalBlockchain/coreth/plugin/evm/atomic/atomictest"
+	"github.com/MetalBlockchain/coreth/plugin/evm/atomic/wallet"
+	"github.com/MetalBlockchain/coreth/plugin/evm/extension"
+	"github.com/MetalBlockchain/coreth/plugin/evm/upgrade/ap4"
+	"github.com/MetalBlockchain/coreth/utils"
+
+	avalancheatomic "github.com/MetalBlockchain/metalgo/chains/atomic"
+	atomicvm "github.com/MetalBlockchain/coreth/plugin/evm/atomic/vm"
+)
+
+const maxUTXOs = 1024
+
+var (
+	_ wallet.Backend = (*Backend)(nil)
+
+	errNoProcessingBlocks = errors.New("no processing blocks")
+)
+
+// Backend is an in-memory C-Chain sharing memory with the X-Chain. Atomic txs
+// are verified as by the atomic VM when issued, and accepted immediately.
+type Backend struct {
+	ctx      *snow.Context
+	memories *atomictest.SharedMemories
+	clock    mockable.Clock
+	codec    codec.Registry
+	fx       secp256k1fx.Fx
+	verifier *atomicvm.VerifierBackend
+
+	lock     sync.Mutex
+	baseFee  *big.Int
+	state    *stateDB
+	statuses map[ids.ID]atomic.Status
+}
+
+// NewBackend returns an empty chain verifying atomic txs with [rules].
+func NewBackend(t testing.TB, rules extras.Rules) *Backend {
+	ctx := snowtest.Context(t, snowtest.CChainID)
+	ctx.ValidatorState = utils.NewTestValidatorState()
+	memories := atomictest.NewSharedMemories(
+		avalancheatomic.NewMemory(memdb.New()),
+		ctx.ChainID,
+		ctx.XChainID,
+	)
+	ctx.SharedMemory = memories.ThisChain
+
+	b := &Backend{
+		ctx:      ctx,
+		memories: memories,
+		codec:    linearcodec.NewDefault(),
+		baseFee:  big.NewInt(ap4.MinBaseFee),
+		state:    newStateDB(),
+		statuses: make(map[ids.ID]atomic.Status),
+	}
+	require.NoError(t, b.fx.Initialize(b))
+	require.NoError(t, b.fx.Bootstrapped())
+	b.verifier = &atomicvm.VerifierBackend{
+		Ctx:          ctx,
+		Fx:           &b.fx,
+		Rules:        rules,
+		Bootstrapped: true,
+		BlockFetcher: b,
+		SecpCache:    secp256k1.NewRecoverCache(maxUTXOs),
+	}
+	return b
+}
+
+// CodecRegistry implements the secp256k1fx interface
+func (b *Backend) CodecRegistry() codec.Registry { return b.codec }
+
+// Clock implements the secp256k1fx interface
+func (b *Backend) Clock() *mockable.Clock { return &b.clock }
+
+// Logger implements the secp256k1fx interface
+func (b *Backend) Logger() logging.Logger { return b.ctx.Log }
+
+// GetExtendedBlock implements the atomicvm.BlockFetcher interface. Every atomic
+// tx is accepted when issued, so there are no processing blocks to fetch.
+func (*Backend) GetExtendedBlock(context.Context, ids.ID) (extension.ExtendedBlock, error) {
+	return nil, errNoProcessingBlocks
+}
+
+// LastAcceptedExtendedBlock implements the atomicvm.BlockFetcher interface.
+func (*Backend) LastAcceptedExtendedBlock() extension.ExtendedBlock {
+	return acceptedBlock{}
+}
+
+// acceptedBlock stands in for the last accepted block, of which only the
+// height is read.
+type acceptedBlock struct {
+	extension.ExtendedBlock
+}
+
+func (acceptedBlock) Height() uint64 { return 0 }
+
+// Context returns the chain the wallet builds atomic txs for.
+func (b *Backend) Context() wallet.Context {
+	return wallet.Context{
+		NetworkID:    b.ctx.NetworkID,
+		BlockchainID: b.ctx.ChainID,
+		AVAXAssetID:  b.ctx.AVAXAssetID,
+		IsBanff:      b.verifier.Rules.IsBanff,
+	}
+}
+
+// XChainID returns the ID of the chain sharing memory with the chain.
+func (b *Backend) XChainID() ids.ID {
+	return b.ctx.XChainID
+}
+
+// SetBaseFee sets the base fee atomic txs must pay for.
+func (b *Backend) SetBaseFee(baseFee *big.Int) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	b.baseFee = baseFee
+}
+
+// SetBalance sets the balance of [assetID] of [addr] to [amount], denominated
+// in nAVAX for AVAX.
+func (b *Backend) SetBalance(addr common.Address, assetID ids.ID, amount uint64) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	if assetID == b.ctx.AVAXAssetID {
+		b.state.balances[addr] = new(uint256.Int).Mul(uint256.NewInt(amount), atomic.X2CRate)
+		return
+	}
+	b.state.multiCoinBalance(addr)[common.Hash(assetID)] = new(big.Int).SetUint64(amount)
+}
+
+// AddUTXO exports from the X-Chain to the chain a new UTXO of [amount] of
+// [assetID] owned by [owner], to be imported.
+func (b *Backend) AddUTXO(assetID ids.ID, amount uint64, owner ids.ShortID) (*avax.UTXO, error) {
+	utxo := &avax.UTXO{
+		UTXOID: avax.UTXOID{TxID: ids.GenerateTestID()},
+		Asset:  avax.Asset{ID: assetID},
+		Out: &secp256k1fx.TransferOutput{
+			Amt: amount,
+			OutputOwners: secp256k1fx.OutputOwners{
+				Threshold: 1,
+				Addrs:     []ids.ShortID{owner},
+			},
+		},
+	}
+	utxoBytes, err := atomic.Codec.Marshal(atomic.CodecVersion, utxo)
+	if err != nil {
+		return nil, err
+	}
+	inputID := utxo.InputID()
+	err = b.memories.PeerChain.Apply(map[ids.ID]*avalancheatomic.Requests{
+		b.ctx.ChainID: {PutRequests: []*avalancheatomic.Element{{
+			Key:    inputID[:],
+			Value:  utxoBytes,
+			Traits: [][]byte{owner.Bytes()},
+		}}},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return utxo, nil
+}
+
+// ExportedUTXOs returns the UTXOs exported by the chain to the X-Chain owned by
+// [addrs].
+func (b *Backend) ExportedUTXOs(addrs ...ids.ShortID) ([]*avax.UTXO, error) {
+	utxos, _, _, err := avax.GetAtomicUTXOs(b.memories.PeerChain, atomic.Codec, b.ctx.ChainID, set.Of(addrs...), ids.ShortEmpty, ids.Empty, maxUTXOs)
+	return utxos, err
+}
+
+func (b *Backend) UTXOs(_ context.Context, sourceChain ids.ID, addrs set.Set[ids.ShortID]) ([]*avax.UTXO, error) {
+	utxos, _, _, err := avax.GetAtomicUTXOs(b.ctx.SharedMemory, atomic.Codec, sourceChain, addrs, ids.ShortEmpty, ids.Empty, maxUTXOs)
+	return utxos, err
+}
+
+func (b *Backend) Balance(_ context.Context, addr common.Address, assetID ids.ID) (uint64, error) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	if assetID == b.ctx.AVAXAssetID {
+		return new(uint256.Int).Div(b.state.GetBalance(addr), atomic.X2CRate).Uint64(), nil
+	}
+	return b.state.GetBalanceMultiCoin(addr, common.Hash(assetID)).Uint64(), nil
+}
+
+func (b *Backend) Nonce(_ context.Context, addr common.Address) (uint64, error) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	return b.state.GetNonce(addr), nil
+}
+
+func (b *Backend) BaseFee(context.Context) (*big.Int, error) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	return new(big.Int).Set(b.baseFee), nil
+}
+
+// IssueTx verifies [tx], applies its state transfer and its atomic operations,
+// and marks it as accepted.
+func (b *Backend) IssueTx(_ context.Context, tx *atomic.Tx) error {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	if err := b.verifier.SemanticVerify(tx, acceptedBlock{}, b.baseFee); err != nil {
+		return err
+	}
+	state := b.state.copy()
+	if err := tx.UnsignedAtomicTx.EVMStateTransfer(b.ctx, state); err != nil {
+		return err
+	}
+	chainID, requests, err := tx.AtomicOps()
+	if err != nil {
+		return err
+	}
+	if err := b.ctx.SharedMemory.Apply(map[ids.ID]*avalancheatomic.Requests{chainID: requests}); err != nil {
+		return err
+	}
+	b.state = state
+	b.statuses[tx.ID()] = atomic.Accepted
+	return nil
+}
+
+func (b *Backend) TxStatus(_ context.Context, txID ids.ID) (atomic.Status, error) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	return b.statuses[txID], nil
+}
+
+var _ atomic.StateDB = (*stateDB)(nil)
+
+// stateDB holds the balances and nonces of the accounts of the chain.
+type stateDB struct {
+	balances  map[common.Address]*uint256.Int
+	multiCoin map[common.Address]map[common.Hash]*big.Int
+	nonces    map[common.Address]uint64
+}
+
+func newStateDB() *stateDB {
+	return &stateDB{
+		balances:  make(map[common.Address]*uint256.Int),
+		multiCoin: make(map[common.Address]map[common.Hash]*big.Int),
+		nonces:    make(map[common.Address]uint64),
+	}
+}
+
+func (s *stateDB) copy() *stateDB {
+	cpy := newStateDB()
+	for addr, balance := range s.balances {
+		cpy.balances[addr] = balance.Clone()
+	}
+	for addr, balances := range s.multiCoin {
+		for coinID, balance := range balances {
+			cpy.multiCoinBalance(addr)[coinID] = new(big.Int).Set(balance)
+		}
+	}
+	for addr, nonce := range s.nonces {
+		cpy.nonces[addr] = nonce
+	}
+	return cpy
+}
+
+func (s *stateDB) multiCoinBalance(addr common.Address) map[common.Hash]*big.Int {
+	balances, ok := s.multiCoin[addr]
+	if !ok {
+		balances = make(map[common.Hash]*big.Int)
+		s.multiCoin[addr] = balances
+	}
+	return balances
+}
+
+func (s *stateDB) AddBalance(addr common.Address, amount *uint256.Int) {
+	s.balances[addr] = new(uint256.Int).Add(s.GetBalance(addr), amount)
+}
+
+func (s *stateDB) AddBalanceMultiCoin(addr common.Address, coinID common.Hash, amount *big.Int) {
+	s.multiCoinBalance(addr)[coinID] = new(big.Int).Add(s.GetBalanceMultiCoin(addr, coinID), amount)
+}
+
+func (s *stateDB) SubBalance(addr common.Address, amount *uint256.Int) {
+	s.balances[addr] = new(uint256.Int).Sub(s.GetBalance(addr), amount)
+}
+
+func (s *stateDB) SubBalanceMultiCoin(addr common.Address, coinID common.Hash, amount *big.Int) {
+	s.multiCoinBalance(addr)[coinID] = new(big.Int).Sub(s.GetBalanceMultiCoin(addr, coinID), amount)
+}
+
+func (s *stateDB) GetBalance(addr common.Address) *uint256.Int {
+	if balance, ok := s.balances[addr]; ok {
+		return balance
+	}
+	return new(uint256.Int)
+}
+
+func (s *stateDB) GetBalanceMultiCoin(addr common.Address, coinID common.Hash) *big.Int {
+	if balance, ok := s.multiCoin[addr][coinID]; ok {
+		return balance
+	}
+	return new(big.Int)
+}
+
+func (s *stateDB) GetNonce(addr common.Address) uint64 {
+	return s.nonces[addr]
+}
+
+func (s *stateDB) SetNonce(addr common.Address, nonce uint64) {
+	s.nonces[addr] = nonce
+}
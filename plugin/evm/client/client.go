@@ -18,6 +18,9 @@ import (
 
 	"github.com/MetalBlockchain/coreth/plugin/evm/atomic"
 	"github.com/MetalBlockchain/coreth/plugin/evm/config"
+	"github.com/MetalBlockchain/coreth/sync/syncfile"
+
+	atomicstate "github.com/MetalBlockchain/coreth/plugin/evm/atomic/state"
 )
 
 // Interface compliance
@@ -37,19 +40,23 @@ type Client interface {
 	LockProfile(ctx context.Context, options ...rpc.Option) error
 	SetLogLevel(ctx context.Context, level slog.Level, options ...rpc.Option) error
 	GetVMConfig(ctx context.Context, options ...rpc.Option) (*config.Config, error)
+	ExportStateSnapshot(ctx context.Context, dir string, height uint64, options ...rpc.Option) (*syncfile.Manifest, error)
+	VerifyAtomicTrie(ctx context.Context, startHeight *uint64, options ...rpc.Option) (*atomicstate.VerifyResult, error)
 }
 
 // Client implementation for interacting with EVM [chain]
 type client struct {
-	requester      rpc.EndpointRequester
-	adminRequester rpc.EndpointRequester
+	requester          rpc.EndpointRequester
+	adminRequester     rpc.EndpointRequester
+	avaxAdminRequester rpc.EndpointRequester
 }
 
 // NewClient returns a Client for interacting with EVM [chain]
 func NewClient(uri, chain string) Client {
 	return &client{
-		requester:      rpc.NewEndpointRequester(fmt.Sprintf("%s/ext/bc/%s/avax", uri, chain)),
-		adminRequester: rpc.NewEndpointRequester(fmt.Sprintf("%s/ext/bc/%s/admin", uri, chain)),
+		requester:          rpc.NewEndpointRequester(fmt.Sprintf("%s/ext/bc/%s/avax", uri, chain)),
+		adminRequester:     rpc.NewEndpointRequester(fmt.Sprintf("%s/ext/bc/%s/admin", uri, chain)),
+		avaxAdminRequester: rpc.NewEndpointRequester(fmt.Sprintf("%s/ext/bc/%s/avax/admin", uri, chain)),
 	}
 }
 
@@ -172,3 +179,46 @@ func (c *client) GetVMConfig(ctx context.Context, options ...rpc.Option) (*confi
 	err := c.adminRequester.SendRequest(ctx, "admin.getVMConfig", struct{}{}, res, options...)
 	return res.Config, err
 }
+
+type ExportStateSnapshotArgs struct {
+	Dir    string      `json:"dir"`
+	Height json.Uint64 `json:"height"`
+}
+
+type ExportStateSnapshotReply struct {
+	Manifest *syncfile.Manifest `json:"manifest"`
+}
+
+// ExportStateSnapshot writes a state sync snapshot of the summary at [height]
+// to [dir] on the node. If [height] is zero, the last summary is exported.
+func (c *client) ExportStateSnapshot(ctx context.Context, dir string, height uint64, options ...rpc.Option) (*syncfile.Manifest, error) {
+	res := &ExportStateSnapshotReply{}
+	err := c.adminRequester.SendRequest(ctx, "admin.exportStateSnapshot", &ExportStateSnapshotArgs{
+		Dir:    dir,
+		Height: json.Uint64(height),
+	}, res, options...)
+	return res.Manifest, err
+}
+
+type VerifyAtomicTrieArgs struct {
+	StartHeight *json.Uint64 `json:"startHeight,omitempty"`
+}
+
+type VerifyAtomicTrieReply struct {
+	Result *atomicstate.VerifyResult `json:"result"`
+}
+
+// VerifyAtomicTrie rebuilds the atomic trie from the atomic repository on the
+// node and compares it to the committed atomic trie roots. If [startHeight] is
+// nil, the atomic trie is rebuilt from the height the node last state synced
+// to, or from genesis.
+func (c *client) VerifyAtomicTrie(ctx context.Context, startHeight *uint64, options ...rpc.Option) (*atomicstate.VerifyResult, error) {
+	args := &VerifyAtomicTrieArgs{}
+	if startHeight != nil {
+		height := json.Uint64(*startHeight)
+		args.StartHeight = &height
+	}
+	res := &VerifyAtomicTrieReply{}
+	err := c.avaxAdminRequester.SendRequest(ctx, "admin.verifyAtomicTrie", args, res, options...)
+	return res.Result, err
+}
@@ -9,6 +9,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/MetalBlockchain/metalgo/ids"
 	"github.com/MetalBlockchain/metalgo/utils/constants"
 	"github.com/MetalBlockchain/metalgo/vms/components/gas"
 	"github.com/MetalBlockchain/libevm/common"
@@ -66,10 +67,16 @@ type Config struct {
 	PopulateMissingTriesParallelism int     `json:"populate-missing-tries-parallelism"` // Number of concurrent readers to use when re-populating missing tries on startup.
 	PruneWarpDB                     bool    `json:"prune-warp-db-enabled"`              // Determines if the warpDB should be cleared on startup
 
-	// HistoricalProofQueryWindow is, when running in archive mode only, the number of blocks before the
-	// last accepted block to be accepted for proof state queries.
+	// HistoricalProofQueryWindow is, when running in archive mode or with state reconstruction enabled,
+	// the number of blocks before the last accepted block to be accepted for proof state queries.
 	HistoricalProofQueryWindow uint64 `json:"historical-proof-query-window,omitempty"`
 
+	// State Reconstruction Settings
+	StateReconstructionEnabled     bool   `json:"state-reconstruction-enabled"`     // If enabled, pruning nodes re-execute blocks to serve state queries within the historical proof query window
+	StateReconstructionCacheSize   int    `json:"state-reconstruction-cache-size"`  // Number of reconstructed states to keep in memory
+	StateReconstructionMaxBlocks   uint64 `json:"state-reconstruction-max-blocks"`  // Maximum number of blocks to re-execute to reconstruct a single state
+	StateReconstructionParallelism int    `json:"state-reconstruction-parallelism"` // Maximum number of states to reconstruct concurrently
+
 	// Metric Settings
 	MetricsExpensiveEnabled bool `json:"metrics-expensive-enabled"` // Debug-level metrics that might impact runtime performance
 
@@ -132,6 +139,15 @@ type Config struct {
 	StateSyncMinBlocks       uint64 `json:"state-sync-min-blocks"`
 	StateSyncRequestSize     uint16 `json:"state-sync-request-size"`
 
+	// State sync snapshot settings
+	StateSyncSnapshotDir       string `json:"state-sync-snapshot-dir"`        // Syncs from the snapshot in this directory on startup
+	StateSyncSnapshotSummaryID string `json:"state-sync-snapshot-summary-id"` // Required ID of the snapshot's summary
+
+	// Block backfill settings
+	BlockBackfillEnabled     bool   `json:"block-backfill-enabled"`     // Fetches the blocks preceding the last state sync in the background
+	BlockBackfillToHeight    uint64 `json:"block-backfill-to-height"`   // Height of the oldest block to backfill
+	BlockBackfillParallelism int    `json:"block-backfill-parallelism"` // Number of block ranges fetched concurrently
+
 	// Database Settings
 	InspectDatabase bool `json:"inspect-database"` // Inspects the database on startup if enabled.
 
@@ -161,6 +177,18 @@ type Config struct {
 	// TransactionHistory can be still used to control unindexing old transactions.
 	SkipTxIndexing bool `json:"skip-tx-indexing"`
 
+	// LogIndexEnabled indexes the logs of accepted blocks by address and topic,
+	// so that getLogs requests over wide block ranges are served from the index.
+	LogIndexEnabled bool `json:"log-index-enabled"`
+	// LogIndexHistory is the maximum number of blocks from head whose logs are
+	// indexed when LogIndexEnabled is set (0 means no limit).
+	LogIndexHistory uint64 `json:"log-index-history"`
+
+	// BodyHistory and ReceiptHistory are the maximum number of blocks from head
+	// whose bodies and receipts are retained (0 means no limit).
+	BodyHistory    uint64 `json:"body-history"`
+	ReceiptHistory uint64 `json:"receipt-history"`
+
 	// WarpOffChainMessages encodes off-chain messages (unrelated to any on-chain event ie. block or AddressedCall)
 	// that the node should be willing to sign.
 	// Note: only supports AddressedCall payloads as defined here:
@@ -172,8 +200,30 @@ type Config struct {
 	BatchRequestLimit    uint64 `json:"batch-request-limit"`
 	BatchResponseMaxSize uint64 `json:"batch-response-max-size"`
 
+	// RPC authentication settings
+	RPCAuthSecretFile       string   `json:"rpc-auth-secret-file"`
+	RPCAuthPublicNamespaces []string `json:"rpc-auth-public-namespaces"`
+
+	// HTTP RPC rate limiting settings
+	HTTPRateLimitRefillRate       float64            `json:"http-rate-limit-refill-rate"`
+	HTTPRateLimitMaxStored        float64            `json:"http-rate-limit-max-stored"`
+	HTTPRateLimitKeyHeader        string             `json:"http-rate-limit-key-header"`
+	HTTPRateLimitClasses          []RateLimitClass   `json:"http-rate-limit-classes"`
+	HTTPRateLimitMethodCosts      map[string]float64 `json:"http-rate-limit-method-costs"`
+	HTTPRateLimitTrustedProxies   []string           `json:"http-rate-limit-trusted-proxies"`
+	HTTPRateLimitIPv6PrefixLength int                `json:"http-rate-limit-ipv6-prefix-length"`
+	HTTPRateLimitMaxClients       int                `json:"http-rate-limit-max-clients"`
+
 	// Database Scheme
 	StateScheme string `json:"state-scheme"`
+
+	// ExperimentalPathScheme allows the path scheme, which is untested in
+	// production and does not support proofs of historical states.
+	ExperimentalPathScheme bool `json:"experimental-path-scheme-enabled"`
+
+	// StateHistoryIndex enables, for archive nodes using the path scheme, the
+	// indexing of state histories by account and storage slot.
+	StateHistoryIndex bool `json:"state-history-index-enabled"`
 }
 
 // GetConfig returns a new config object with the default values set and the
@@ -252,6 +302,73 @@ func (c *Config) validate(networkID uint32) error {
 	if c.Pruning && c.StateHistory == 0 {
 		return errors.New("cannot use state history of 0 with pruning enabled")
 	}
+	if c.StateReconstructionEnabled {
+		// Archive nodes persist every state, so there is nothing to
+		// reconstruct.
+		if !c.Pruning {
+			return errors.New("state-reconstruction-enabled requires pruning to be enabled")
+		}
+		if c.StateReconstructionCacheSize <= 0 {
+			return fmt.Errorf("state-reconstruction-cache-size is %d but must be positive", c.StateReconstructionCacheSize)
+		}
+		if c.StateReconstructionMaxBlocks == 0 {
+			return errors.New("cannot use state reconstruction max blocks of 0 with state reconstruction enabled")
+		}
+		if c.StateReconstructionParallelism <= 0 {
+			return fmt.Errorf("state-reconstruction-parallelism is %d but must be positive", c.StateReconstructionParallelism)
+		}
+	}
+
+	switch {
+	case len(c.StateSyncSnapshotSummaryID) > 0 && len(c.StateSyncSnapshotDir) == 0:
+		return errors.New("cannot set state-sync-snapshot-summary-id without state-sync-snapshot-dir")
+	case len(c.StateSyncSnapshotDir) > 0 && len(c.StateSyncSnapshotSummaryID) == 0:
+		// The state in a snapshot is only verified against the snapshot's own
+		// summary, which must therefore be checked against a trusted ID.
+		return errors.New("cannot set state-sync-snapshot-dir without state-sync-snapshot-summary-id")
+	case len(c.StateSyncSnapshotSummaryID) > 0:
+		if _, err := ids.FromString(c.StateSyncSnapshotSummaryID); err != nil {
+			return fmt.Errorf("failed to parse state-sync-snapshot-summary-id: %w", err)
+		}
+	}
+
+	if c.BlockBackfillEnabled && c.BlockBackfillParallelism <= 0 {
+		return fmt.Errorf("block-backfill-parallelism is %d but must be positive", c.BlockBackfillParallelism)
+	}
+
+	if !withinHistory(c.ReceiptHistory, c.BodyHistory) {
+		return fmt.Errorf("receipt-history (%d) cannot exceed body-history (%d)", c.ReceiptHistory, c.BodyHistory)
+	}
+	// The transactions of a block are unindexed after the block is dropped
+	// from the range, which requires its body.
+	if c.BodyHistory != 0 && (c.TransactionHistory == 0 || c.TransactionHistory >= c.BodyHistory) {
+		return fmt.Errorf("transaction-history (%d) must be less than body-history (%d)", c.TransactionHistory, c.BodyHistory)
+	}
+	if c.LogIndexHistory != 0 && !c.LogIndexEnabled {
+		return errors.New("cannot set log-index-history while log-index-enabled is disabled")
+	}
+	// The log index must be unindexed before the receipts it refers to are
+	// pruned.
+	if c.LogIndexEnabled && c.ReceiptHistory != 0 && (c.LogIndexHistory == 0 || c.LogIndexHistory >= c.ReceiptHistory) {
+		return fmt.Errorf("log-index-history (%d) must be less than receipt-history (%d)", c.LogIndexHistory, c.ReceiptHistory)
+	}
+	// Blocks since the last committed state are re-processed on startup.
+	if c.Pruning && c.BodyHistory != 0 && c.BodyHistory < c.CommitInterval {
+		return fmt.Errorf("body-history (%d) cannot be less than commit-interval (%d)", c.BodyHistory, c.CommitInterval)
+	}
+
+	if c.HTTPRateLimitRefillRate < 0 {
+		return fmt.Errorf("http-rate-limit-refill-rate is %f but must not be negative", c.HTTPRateLimitRefillRate)
+	}
+	if c.HTTPRateLimitRefillRate > 0 && c.HTTPRateLimitMaxStored <= 0 {
+		return fmt.Errorf("http-rate-limit-max-stored is %f but must be positive", c.HTTPRateLimitMaxStored)
+	}
+	if c.HTTPRateLimitIPv6PrefixLength < 1 || c.HTTPRateLimitIPv6PrefixLength > 128 {
+		return fmt.Errorf("http-rate-limit-ipv6-prefix-length is %d but must be in the range [1, 128]", c.HTTPRateLimitIPv6PrefixLength)
+	}
+	if c.HTTPRateLimitMaxClients < 1 {
+		return fmt.Errorf("http-rate-limit-max-clients is %d but must be positive", c.HTTPRateLimitMaxClients)
+	}
 
 	if c.PushGossipPercentStake < 0 || c.PushGossipPercentStake > 1 {
 		return fmt.Errorf("push-gossip-percent-stake is %f but must be in the range [0, 1]", c.PushGossipPercentStake)
@@ -259,6 +376,21 @@ func (c *Config) validate(networkID uint32) error {
 	return nil
 }
 
+// RateLimitClass configures the limits of the HTTP RPC clients presenting one
+// of Keys in the http-rate-limit-key-header header.
+type RateLimitClass struct {
+	Name       string   `json:"name"`
+	Keys       []string `json:"keys"`
+	RefillRate float64  `json:"refill-rate"`
+	MaxStored  float64  `json:"max-stored"`
+}
+
+// withinHistory returns whether retaining [history] blocks from head does not
+// exceed retaining [limit] blocks, where 0 retains every block.
+func withinHistory(history, limit uint64) bool {
+	return limit == 0 || (history != 0 && history <= limit)
+}
+
 // deprecate returns a string of deprecation messages for the config.
 // This is used to log a message when the config is loaded and contains deprecated flags.
 // This function should be kept as a placeholder even if it is empty.
@@ -159,6 +159,116 @@ func TestGetConfig(t *testing.T) {
 				require.Equal(t, uint64(100), config.TxPoolPriceLimit)
 			},
 		},
+		{
+			name:       "history retention tiers",
+			configJSON: []byte(`{"body-history": 10000, "receipt-history": 5000, "transaction-history": 9000, "log-index-enabled": true, "log-index-history": 4000}`),
+			networkID:  constants.TahoeID,
+			expected: func(t *testing.T, config Config) {
+				require.Equal(t, uint64(10000), config.BodyHistory)
+				require.Equal(t, uint64(5000), config.ReceiptHistory)
+				require.Equal(t, uint64(4000), config.LogIndexHistory)
+			},
+		},
+		{
+			name:        "log index history equal to receipt history",
+			configJSON:  []byte(`{"body-history": 10000, "receipt-history": 5000, "transaction-history": 9000, "log-index-enabled": true, "log-index-history": 5000}`),
+			networkID:   constants.TahoeID,
+			expectError: true,
+		},
+		{
+			name:        "receipt history exceeds body history",
+			configJSON:  []byte(`{"body-history": 10000, "receipt-history": 20000, "transaction-history": 9000}`),
+			networkID:   constants.TahoeID,
+			expectError: true,
+		},
+		{
+			name:        "transaction history equal to body history",
+			configJSON:  []byte(`{"body-history": 10000, "receipt-history": 5000, "transaction-history": 10000}`),
+			networkID:   constants.TahoeID,
+			expectError: true,
+		},
+		{
+			name:        "unlimited transaction history with limited body history",
+			configJSON:  []byte(`{"body-history": 10000}`),
+			networkID:   constants.TahoeID,
+			expectError: true,
+		},
+		{
+			name:        "body history below commit interval",
+			configJSON:  []byte(`{"body-history": 100, "transaction-history": 99}`),
+			networkID:   constants.TahoeID,
+			expectError: true,
+		},
+		{
+			name:       "http rate limit classes",
+			configJSON: []byte(`{"http-rate-limit-refill-rate": 10, "http-rate-limit-max-stored": 100, "http-rate-limit-key-header": "X-Api-Key", "http-rate-limit-classes": [{"name": "partner", "keys": ["secret"], "refill-rate": 100, "max-stored": 1000}], "http-rate-limit-method-costs": {"debug_*": 10}}`),
+			networkID:  constants.TahoeID,
+			expected: func(t *testing.T, config Config) {
+				require.Equal(t, []RateLimitClass{{Name: "partner", Keys: []string{"secret"}, RefillRate: 100, MaxStored: 1000}}, config.HTTPRateLimitClasses)
+				require.Equal(t, map[string]float64{"debug_*": 10}, config.HTTPRateLimitMethodCosts)
+			},
+		},
+		{
+			name:       "http rate limit defaults",
+			configJSON: []byte(`{"http-rate-limit-refill-rate": 10, "http-rate-limit-max-stored": 100}`),
+			networkID:  constants.TahoeID,
+			expected: func(t *testing.T, config Config) {
+				require.Equal(t, 64, config.HTTPRateLimitIPv6PrefixLength)
+				require.Equal(t, 100_000, config.HTTPRateLimitMaxClients)
+			},
+		},
+		{
+			name:        "http rate limit ipv6 prefix too long",
+			configJSON:  []byte(`{"http-rate-limit-ipv6-prefix-length": 129}`),
+			networkID:   constants.TahoeID,
+			expectError: true,
+		},
+		{
+			name:        "http rate limit without clients",
+			configJSON:  []byte(`{"http-rate-limit-max-clients": 0}`),
+			networkID:   constants.TahoeID,
+			expectError: true,
+		},
+		{
+			name:        "state sync snapshot without summary ID",
+			configJSON:  []byte(`{"state-sync-snapshot-dir": "/snapshot"}`),
+			networkID:   constants.TahoeID,
+			expectError: true,
+		},
+		{
+			name:        "summary ID without state sync snapshot",
+			configJSON:  []byte(`{"state-sync-snapshot-summary-id": "SkB92YpWm4Q2ijQHH34cqbKkCZWszsiQgHVjtNeFF2HdvDQU"}`),
+			networkID:   constants.TahoeID,
+			expectError: true,
+		},
+		{
+			name:       "state sync snapshot with summary ID",
+			configJSON: []byte(`{"state-sync-snapshot-dir": "/snapshot", "state-sync-snapshot-summary-id": "SkB92YpWm4Q2ijQHH34cqbKkCZWszsiQgHVjtNeFF2HdvDQU"}`),
+			networkID:  constants.TahoeID,
+			expected: func(t *testing.T, config Config) {
+				require.Equal(t, "/snapshot", config.StateSyncSnapshotDir)
+			},
+		},
+		{
+			name:        "state reconstruction without pruning",
+			configJSON:  []byte(`{"pruning-enabled": false, "state-reconstruction-enabled": true}`),
+			networkID:   constants.TahoeID,
+			expectError: true,
+		},
+		{
+			name:       "state reconstruction with pruning",
+			configJSON: []byte(`{"pruning-enabled": true, "state-reconstruction-enabled": true}`),
+			networkID:  constants.TahoeID,
+			expected: func(t *testing.T, config Config) {
+				require.True(t, config.StateReconstructionEnabled)
+			},
+		},
+		{
+			name:        "http rate limit without max stored",
+			configJSON:  []byte(`{"http-rate-limit-refill-rate": 10}`),
+			networkID:   constants.TahoeID,
+			expectError: true,
+		},
 		{
 			name:       "nil config uses defaults",
 			configJSON: nil,
@@ -77,9 +77,15 @@ func NewDefaultConfig() Config {
 		StateSyncMinBlocks: 300_000,
 		// the number of key/values to ask peers for per request
 		StateSyncRequestSize: 1024,
-		StateHistory:         uint64(32),
+		// the number of block ranges to fetch concurrently when backfilling
+		BlockBackfillParallelism: 8,
+		StateHistory:             uint64(32),
 		// Estimated block count in 24 hours with 2s block accept period
 		HistoricalProofQueryWindow: uint64(24 * time.Hour / (2 * time.Second)),
+		// State reconstruction defaults
+		StateReconstructionCacheSize:   16,
+		StateReconstructionMaxBlocks:   defaultCommitInterval,
+		StateReconstructionParallelism: 2,
 		// Price Option Defaults
 		PriceOptionSlowFeePercentage: uint64(95),
 		PriceOptionFastFeePercentage: uint64(105),
@@ -95,6 +101,13 @@ func NewDefaultConfig() Config {
 		// RPC settings
 		BatchRequestLimit:    1000,
 		BatchResponseMaxSize: 25 * 1000 * 1000, // 25MB
+		RPCAuthPublicNamespaces: []string{
+			"eth",
+			"net",
+			"web3",
+		},
+		HTTPRateLimitIPv6PrefixLength: 64,
+		HTTPRateLimitMaxClients:       100_000,
 	}
 }
 
@@ -0,0 +1,138 @@
+// Copyright (C) 2019-2025, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package evm
+
+import (
+	"context"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/MetalBlockchain/metalgo/ids"
+	"github.com/MetalBlockchain/metalgo/upgrade/upgradetest"
+	"github.com/MetalBlockchain/metalgo/utils/crypto/bls"
+	"github.com/MetalBlockchain/metalgo/vms/platformvm/warp/payload"
+	"github.com/MetalBlockchain/libevm/common"
+	"github.com/MetalBlockchain/libevm/core/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/MetalBlockchain/coreth/ethclient/corethclient"
+	"github.com/MetalBlockchain/coreth/plugin/evm/upgrade/ap0"
+	"github.com/MetalBlockchain/coreth/plugin/evm/vmtest"
+
+	ethereum "github.com/MetalBlockchain/libevm"
+	ethparams "github.com/MetalBlockchain/libevm/params"
+	avagoUtils "github.com/MetalBlockchain/metalgo/utils"
+	avalancheWarp "github.com/MetalBlockchain/metalgo/vms/platformvm/warp"
+	warpcontract "github.com/MetalBlockchain/coreth/precompile/contracts/warp"
+)
+
+func TestCorethClient(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+	fork := upgradetest.Durango
+	vm := newDefaultTestVM()
+	vmtest.SetupTestVM(t, vm, vmtest.TestVMConfig{
+		Fork:       &fork,
+		ConfigJSON: `{"admin-api-enabled": true, "warp-api-enabled": true}`,
+	})
+	defer func() {
+		require.NoError(vm.Shutdown(ctx))
+	}()
+
+	handlers, err := vm.CreateHandlers(ctx)
+	require.NoError(err)
+	mux := http.NewServeMux()
+	for endpoint, handler := range handlers {
+		if endpoint == ethWSEndpoint {
+			continue
+		}
+		mux.Handle(endpoint, handler)
+	}
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	client, err := corethclient.DialContext(ctx, server.URL)
+	require.NoError(err)
+	defer client.Close()
+
+	config, err := client.GetVMConfig(ctx)
+	require.NoError(err)
+	require.True(config.WarpAPIEnabled)
+
+	priceOptions, err := client.SuggestPriceOptions(ctx)
+	require.NoError(err)
+	require.NotNil(priceOptions)
+	require.NotNil(priceOptions.Normal)
+
+	badBlocks, err := client.BadBlocks(ctx)
+	require.NoError(err)
+	require.Empty(badBlocks)
+
+	// A call to an account without code uses the intrinsic gas only.
+	to := common.Address{0x01}
+	res, err := client.CallDetailed(ctx, ethereum.CallMsg{
+		From: vmtest.TestEthAddrs[0],
+		To:   &to,
+	}, nil, nil)
+	require.NoError(err)
+	require.Equal(ethparams.TxGas, res.UsedGas)
+	require.Empty(res.Err)
+
+	// An execution error is returned in the result.
+	overrides := map[common.Address]corethclient.OverrideAccount{
+		to: {Code: common.FromHex("0x600080fd")}, // PUSH1 0 DUP1 REVERT
+	}
+	res, err = client.CallDetailed(ctx, ethereum.CallMsg{
+		From: vmtest.TestEthAddrs[0],
+		To:   &to,
+	}, nil, &overrides)
+	require.NoError(err)
+	require.NotEmpty(res.Err)
+
+	// Send a warp message.
+	payloadData := avagoUtils.RandomBytes(100)
+	warpSendMessageInput, err := warpcontract.PackSendWarpMessage(payloadData)
+	require.NoError(err)
+	addressedPayload, err := payload.NewAddressedCall(
+		vmtest.TestEthAddrs[0].Bytes(),
+		payloadData,
+	)
+	require.NoError(err)
+	expectedUnsignedMessage, err := avalancheWarp.NewUnsignedMessage(
+		vm.ctx.NetworkID,
+		vm.ctx.ChainID,
+		addressedPayload.Bytes(),
+	)
+	require.NoError(err)
+
+	tx := types.NewTransaction(uint64(0), warpcontract.ContractAddress, big.NewInt(1), 100_000, big.NewInt(ap0.MinGasPrice), warpSendMessageInput)
+	signedTx, err := types.SignTx(tx, types.LatestSignerForChainID(vm.chainConfig.ChainID), vmtest.TestKeys[0].ToECDSA())
+	require.NoError(err)
+	blk, err := vmtest.IssueTxsAndBuild([]*types.Transaction{signedTx}, vm)
+	require.NoError(err)
+	require.NoError(vm.SetPreference(ctx, blk.ID()))
+	require.NoError(blk.Accept(ctx))
+	vm.blockChain.DrainAcceptorQueue()
+
+	unsignedMessage, err := client.GetWarpMessage(ctx, expectedUnsignedMessage.ID())
+	require.NoError(err)
+	require.Equal(expectedUnsignedMessage.Bytes(), unsignedMessage.Bytes())
+
+	signature, err := client.GetWarpMessageSignature(ctx, expectedUnsignedMessage.ID())
+	require.NoError(err)
+	require.True(bls.Verify(vm.ctx.PublicKey, signature, expectedUnsignedMessage.Bytes()))
+
+	signature, err = client.GetWarpBlockSignature(ctx, blk.ID())
+	require.NoError(err)
+	blockHashPayload, err := payload.NewHash(blk.ID())
+	require.NoError(err)
+	blockMessage, err := avalancheWarp.NewUnsignedMessage(vm.ctx.NetworkID, vm.ctx.ChainID, blockHashPayload.Bytes())
+	require.NoError(err)
+	require.True(bls.Verify(vm.ctx.PublicKey, signature, blockMessage.Bytes()))
+
+	_, err = client.GetWarpMessage(ctx, ids.GenerateTestID())
+	require.Error(err)
+}
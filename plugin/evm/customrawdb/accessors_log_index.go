@@ -0,0 +1,133 @@
+// Copyright (C) 2019-2025, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package customrawdb
+
+import (
+	"encoding/binary"
+
+	"github.com/MetalBlockchain/metalgo/utils/wrappers"
+	"github.com/MetalBlockchain/libevm/common"
+	"github.com/MetalBlockchain/libevm/core/types"
+	"github.com/MetalBlockchain/libevm/ethdb"
+	"github.com/MetalBlockchain/libevm/rlp"
+)
+
+// LogIndexRange is the range of accepted blocks whose logs are indexed.
+type LogIndexRange struct {
+	// Tail and Head are the numbers of the first and last indexed block.
+	Tail uint64
+	Head uint64
+}
+
+// WriteLogIndexRange writes the range of blocks whose logs are indexed.
+func WriteLogIndexRange(db ethdb.KeyValueWriter, r *LogIndexRange) error {
+	data, err := rlp.EncodeToBytes(r)
+	if err != nil {
+		return err
+	}
+	return db.Put(logIndexRangeKey, data)
+}
+
+// ReadLogIndexRange reads the range of blocks whose logs are indexed.
+// If no block was indexed, nil is returned.
+func ReadLogIndexRange(db ethdb.KeyValueReader) (*LogIndexRange, error) {
+	has, err := db.Has(logIndexRangeKey)
+	if err != nil || !has {
+		return nil, err
+	}
+	data, err := db.Get(logIndexRangeKey)
+	if err != nil {
+		return nil, err
+	}
+	r := new(LogIndexRange)
+	if err := rlp.DecodeBytes(data, r); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// logIndexAddressKeyPrefix = logIndexAddressPrefix + address
+func logIndexAddressKeyPrefix(address common.Address) []byte {
+	prefix := make([]byte, 0, logIndexAddressKeyLength)
+	prefix = append(prefix, logIndexAddressPrefix...)
+	return append(prefix, address[:]...)
+}
+
+// logIndexTopicKeyPrefix = logIndexTopicPrefix + address + topic position + topic
+func logIndexTopicKeyPrefix(address common.Address, position uint8, topic common.Hash) []byte {
+	prefix := make([]byte, 0, logIndexTopicKeyLength)
+	prefix = append(prefix, logIndexTopicPrefix...)
+	prefix = append(prefix, address[:]...)
+	prefix = append(prefix, position)
+	return append(prefix, topic[:]...)
+}
+
+// WriteLogIndexEntries indexes the block with [number] by the address of each
+// of its [logs], and by the address and each topic of the log.
+func WriteLogIndexEntries(db ethdb.KeyValueWriter, number uint64, logs []*types.Log) error {
+	for _, log := range logs {
+		if err := db.Put(binary.BigEndian.AppendUint64(logIndexAddressKeyPrefix(log.Address), number), nil); err != nil {
+			return err
+		}
+		// The EVM emits at most 4 topics per log.
+		for i, topic := range log.Topics {
+			key := binary.BigEndian.AppendUint64(logIndexTopicKeyPrefix(log.Address, uint8(i), topic), number)
+			if err := db.Put(key, nil); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// DeleteLogIndexEntries removes the entries written by [WriteLogIndexEntries]
+// for the block with [number] and its [logs].
+func DeleteLogIndexEntries(db ethdb.KeyValueWriter, number uint64, logs []*types.Log) error {
+	for _, log := range logs {
+		if err := db.Delete(binary.BigEndian.AppendUint64(logIndexAddressKeyPrefix(log.Address), number)); err != nil {
+			return err
+		}
+		for i, topic := range log.Topics {
+			key := binary.BigEndian.AppendUint64(logIndexTopicKeyPrefix(log.Address, uint8(i), topic), number)
+			if err := db.Delete(key); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// ReadLogIndexAddressBlocks returns the numbers of the indexed blocks in
+// [from, to] containing logs emitted by [address], in ascending order.
+func ReadLogIndexAddressBlocks(db ethdb.Iteratee, address common.Address, from, to uint64) ([]uint64, error) {
+	return readLogIndexBlocks(db, logIndexAddressKeyPrefix(address), from, to)
+}
+
+// ReadLogIndexTopicBlocks returns the numbers of the indexed blocks in
+// [from, to] containing logs emitted by [address] with [topic] at [position],
+// in ascending order.
+func ReadLogIndexTopicBlocks(db ethdb.Iteratee, address common.Address, position uint8, topic common.Hash, from, to uint64) ([]uint64, error) {
+	return readLogIndexBlocks(db, logIndexTopicKeyPrefix(address, position, topic), from, to)
+}
+
+// readLogIndexBlocks returns the block numbers of the index entries with
+// [prefix] in [from, to].
+func readLogIndexBlocks(db ethdb.Iteratee, prefix []byte, from, to uint64) ([]uint64, error) {
+	it := db.NewIterator(prefix, binary.BigEndian.AppendUint64(nil, from))
+	defer it.Release()
+
+	var numbers []uint64
+	for it.Next() {
+		key := it.Key()
+		if len(key) != len(prefix)+wrappers.LongLen {
+			continue
+		}
+		number := binary.BigEndian.Uint64(key[len(prefix):])
+		if number > to {
+			break
+		}
+		numbers = append(numbers, number)
+	}
+	return numbers, it.Error()
+}
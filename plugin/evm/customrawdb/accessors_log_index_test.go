@@ -0,0 +1,118 @@
+// Copyright (C) 2019-2025, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package customrawdb
+
+import (
+	"testing"
+
+	"github.com/MetalBlockchain/libevm/common"
+	"github.com/MetalBlockchain/libevm/core/rawdb"
+	"github.com/MetalBlockchain/libevm/core/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLogIndexRange(t *testing.T) {
+	require := require.New(t)
+	db := rawdb.NewMemoryDatabase()
+
+	r, err := ReadLogIndexRange(db)
+	require.NoError(err)
+	require.Nil(r)
+
+	expected := &LogIndexRange{Tail: 5, Head: 100}
+	require.NoError(WriteLogIndexRange(db, expected))
+	r, err = ReadLogIndexRange(db)
+	require.NoError(err)
+	require.Equal(expected, r)
+}
+
+func TestReadLogIndexBlocks(t *testing.T) {
+	require := require.New(t)
+	db := rawdb.NewMemoryDatabase()
+
+	var (
+		addr      = common.Address{1}
+		otherAddr = common.Address{2}
+		topic     = common.Hash{3}
+		otherHash = common.Hash{4}
+	)
+	for _, number := range []uint64{2, 5, 9, 256} {
+		require.NoError(WriteLogIndexEntries(db, number, []*types.Log{
+			{Address: addr, Topics: []common.Hash{topic, otherHash}},
+			{Address: addr, Topics: []common.Hash{topic}}, // Indexed once per block
+		}))
+	}
+	require.NoError(WriteLogIndexEntries(db, 7, []*types.Log{
+		{Address: otherAddr, Topics: []common.Hash{topic}},
+		{Address: addr, Topics: []common.Hash{otherHash, topic}},
+	}))
+
+	tests := []struct {
+		name     string
+		read     func(from, to uint64) ([]uint64, error)
+		from, to uint64
+		expected []uint64
+	}{
+		{
+			name: "address",
+			read: func(from, to uint64) ([]uint64, error) {
+				return ReadLogIndexAddressBlocks(db, addr, from, to)
+			},
+			from:     0,
+			to:       1000,
+			expected: []uint64{2, 5, 7, 9, 256},
+		},
+		{
+			name: "address bounded",
+			read: func(from, to uint64) ([]uint64, error) {
+				return ReadLogIndexAddressBlocks(db, addr, from, to)
+			},
+			from:     5,
+			to:       9,
+			expected: []uint64{5, 7, 9},
+		},
+		{
+			name: "other address",
+			read: func(from, to uint64) ([]uint64, error) {
+				return ReadLogIndexAddressBlocks(db, otherAddr, from, to)
+			},
+			from:     0,
+			to:       1000,
+			expected: []uint64{7},
+		},
+		{
+			name: "topic",
+			read: func(from, to uint64) ([]uint64, error) {
+				return ReadLogIndexTopicBlocks(db, addr, 0, topic, from, to)
+			},
+			from:     3,
+			to:       255,
+			expected: []uint64{5, 9},
+		},
+		{
+			name: "topic position",
+			read: func(from, to uint64) ([]uint64, error) {
+				return ReadLogIndexTopicBlocks(db, addr, 1, topic, from, to)
+			},
+			from:     0,
+			to:       1000,
+			expected: []uint64{7},
+		},
+		{
+			name: "no match",
+			read: func(from, to uint64) ([]uint64, error) {
+				return ReadLogIndexTopicBlocks(db, otherAddr, 0, otherHash, from, to)
+			},
+			from: 0,
+			to:   1000,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			numbers, err := test.read(test.from, test.to)
+			require.NoError(err)
+			require.Equal(test.expected, numbers)
+		})
+	}
+}
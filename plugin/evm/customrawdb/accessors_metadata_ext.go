@@ -4,9 +4,11 @@
 package customrawdb
 
 import (
+	"encoding/binary"
 	"fmt"
 	"time"
 
+	"github.com/MetalBlockchain/metalgo/utils/wrappers"
 	"github.com/MetalBlockchain/libevm/common"
 	"github.com/MetalBlockchain/libevm/ethdb"
 	"github.com/MetalBlockchain/libevm/rlp"
@@ -112,3 +114,82 @@ func ReadAcceptorTip(db ethdb.KeyValueReader) (common.Hash, error) {
 	}
 	return common.BytesToHash(h), nil
 }
+
+// BlockBackfillProgress is the progress of the backfill of the blocks before
+// a state sync.
+type BlockBackfillProgress struct {
+	// SyncHeight is the height of the state sync whose ancestors are backfilled.
+	SyncHeight uint64
+	// Hash and Height identify the oldest block backfilled so far. Every block
+	// from Height to SyncHeight is stored with its receipts.
+	Hash   common.Hash
+	Height uint64
+}
+
+// WriteBlockBackfillProgress writes the progress of the block backfill.
+func WriteBlockBackfillProgress(db ethdb.KeyValueWriter, progress *BlockBackfillProgress) error {
+	data, err := rlp.EncodeToBytes(progress)
+	if err != nil {
+		return err
+	}
+	return db.Put(blockBackfillKey, data)
+}
+
+// ReadBlockBackfillProgress reads the progress of the block backfill.
+// If no backfill was started, nil is returned.
+func ReadBlockBackfillProgress(db ethdb.KeyValueReader) (*BlockBackfillProgress, error) {
+	has, err := db.Has(blockBackfillKey)
+	if err != nil || !has {
+		return nil, err
+	}
+	data, err := db.Get(blockBackfillKey)
+	if err != nil {
+		return nil, err
+	}
+	progress := new(BlockBackfillProgress)
+	if err := rlp.DecodeBytes(data, progress); err != nil {
+		return nil, err
+	}
+	return progress, nil
+}
+
+// WriteBodyTail writes the number of the oldest block whose body is retained.
+func WriteBodyTail(db ethdb.KeyValueWriter, number uint64) error {
+	return db.Put(bodyTailKey, binary.BigEndian.AppendUint64(nil, number))
+}
+
+// ReadBodyTail reads the number of the oldest block whose body is retained.
+// If no body was pruned, nil is returned.
+func ReadBodyTail(db ethdb.KeyValueReader) (*uint64, error) {
+	return readBlockNumber(db, bodyTailKey)
+}
+
+// WriteReceiptTail writes the number of the oldest block whose receipts are
+// retained.
+func WriteReceiptTail(db ethdb.KeyValueWriter, number uint64) error {
+	return db.Put(receiptTailKey, binary.BigEndian.AppendUint64(nil, number))
+}
+
+// ReadReceiptTail reads the number of the oldest block whose receipts are
+// retained. If no receipt was pruned, nil is returned.
+func ReadReceiptTail(db ethdb.KeyValueReader) (*uint64, error) {
+	return readBlockNumber(db, receiptTailKey)
+}
+
+// readBlockNumber reads the block number stored at `key`, or nil if there is
+// no value present.
+func readBlockNumber(db ethdb.KeyValueReader, key []byte) (*uint64, error) {
+	has, err := db.Has(key)
+	if err != nil || !has {
+		return nil, err
+	}
+	data, err := db.Get(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) != wrappers.LongLen {
+		return nil, fmt.Errorf("value has incorrect length %d", len(data))
+	}
+	number := binary.BigEndian.Uint64(data)
+	return &number, nil
+}
@@ -0,0 +1,136 @@
+// Copyright (C) 2019-2025, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package customrawdb
+
+import (
+	"encoding/binary"
+
+	"github.com/MetalBlockchain/metalgo/utils/wrappers"
+	"github.com/MetalBlockchain/libevm/common"
+	"github.com/MetalBlockchain/libevm/ethdb"
+	"github.com/MetalBlockchain/libevm/rlp"
+)
+
+// StateHistoryIndex is the range and size of the state histories indexed by
+// an archive node using the path scheme.
+type StateHistoryIndex struct {
+	// Tail and Head are the ids of the first and last indexed state history.
+	Tail uint64
+	Head uint64
+	// DataBytes and IndexBytes are the sizes of the state histories, and of
+	// the account and storage indexes referencing them.
+	DataBytes  uint64
+	IndexBytes uint64
+}
+
+// WriteStateHistoryIndex writes the range and size of the indexed state histories.
+func WriteStateHistoryIndex(db ethdb.KeyValueWriter, index *StateHistoryIndex) error {
+	data, err := rlp.EncodeToBytes(index)
+	if err != nil {
+		return err
+	}
+	return db.Put(stateHistoryIndexKey, data)
+}
+
+// ReadStateHistoryIndex reads the range and size of the indexed state histories.
+// If no state history was indexed, nil is returned.
+func ReadStateHistoryIndex(db ethdb.KeyValueReader) (*StateHistoryIndex, error) {
+	has, err := db.Has(stateHistoryIndexKey)
+	if err != nil || !has {
+		return nil, err
+	}
+	data, err := db.Get(stateHistoryIndexKey)
+	if err != nil {
+		return nil, err
+	}
+	index := new(StateHistoryIndex)
+	if err := rlp.DecodeBytes(data, index); err != nil {
+		return nil, err
+	}
+	return index, nil
+}
+
+// stateHistoryKey = stateHistoryPrefix + id
+func stateHistoryKey(id uint64) []byte {
+	return binary.BigEndian.AppendUint64(append([]byte{}, stateHistoryPrefix...), id)
+}
+
+// stateHistoryAccountKey = stateHistoryAccountPrefix + address + id
+func stateHistoryAccountKey(address common.Address, id uint64) []byte {
+	key := make([]byte, 0, stateHistoryAccountKeyLength)
+	key = append(key, stateHistoryAccountPrefix...)
+	key = append(key, address[:]...)
+	return binary.BigEndian.AppendUint64(key, id)
+}
+
+// stateHistoryStorageKey = stateHistoryStoragePrefix + address + slot hash + id
+func stateHistoryStorageKey(address common.Address, slotHash common.Hash, id uint64) []byte {
+	key := make([]byte, 0, stateHistoryStorageKeyLength)
+	key = append(key, stateHistoryStoragePrefix...)
+	key = append(key, address[:]...)
+	key = append(key, slotHash[:]...)
+	return binary.BigEndian.AppendUint64(key, id)
+}
+
+// WriteStateHistory writes the encoded state history with [id].
+func WriteStateHistory(db ethdb.KeyValueWriter, id uint64, data []byte) error {
+	return db.Put(stateHistoryKey(id), data)
+}
+
+// ReadStateHistory reads the encoded state history with [id].
+func ReadStateHistory(db ethdb.KeyValueReader, id uint64) ([]byte, error) {
+	return db.Get(stateHistoryKey(id))
+}
+
+// WriteStateHistoryAccount indexes that the state history with [id] modifies
+// the account at [address].
+func WriteStateHistoryAccount(db ethdb.KeyValueWriter, address common.Address, id uint64) error {
+	return db.Put(stateHistoryAccountKey(address, id), nil)
+}
+
+// WriteStateHistoryStorage indexes that the state history with [id] modifies
+// the storage slot with [slotHash] of the account at [address].
+func WriteStateHistoryStorage(db ethdb.KeyValueWriter, address common.Address, slotHash common.Hash, id uint64) error {
+	return db.Put(stateHistoryStorageKey(address, slotHash, id), nil)
+}
+
+// ReadStateHistoryAccountChange returns the id of the first state history
+// after [after] and up to [limit] modifying the account at [address].
+func ReadStateHistoryAccountChange(db ethdb.Iteratee, address common.Address, after, limit uint64) (uint64, bool, error) {
+	prefix := make([]byte, 0, stateHistoryAccountKeyLength)
+	prefix = append(prefix, stateHistoryAccountPrefix...)
+	prefix = append(prefix, address[:]...)
+	return readStateHistoryChange(db, prefix, after, limit)
+}
+
+// ReadStateHistoryStorageChange returns the id of the first state history
+// after [after] and up to [limit] modifying the storage slot with [slotHash]
+// of the account at [address].
+func ReadStateHistoryStorageChange(db ethdb.Iteratee, address common.Address, slotHash common.Hash, after, limit uint64) (uint64, bool, error) {
+	prefix := make([]byte, 0, stateHistoryStorageKeyLength)
+	prefix = append(prefix, stateHistoryStoragePrefix...)
+	prefix = append(prefix, address[:]...)
+	prefix = append(prefix, slotHash[:]...)
+	return readStateHistoryChange(db, prefix, after, limit)
+}
+
+// readStateHistoryChange seeks the first index entry with [prefix] whose id is
+// in (after, limit].
+func readStateHistoryChange(db ethdb.Iteratee, prefix []byte, after, limit uint64) (uint64, bool, error) {
+	if after >= limit {
+		return 0, false, nil
+	}
+	it := db.NewIterator(prefix, binary.BigEndian.AppendUint64(nil, after+1))
+	defer it.Release()
+
+	for it.Next() {
+		key := it.Key()
+		if len(key) != len(prefix)+wrappers.LongLen {
+			continue
+		}
+		id := binary.BigEndian.Uint64(key[len(prefix):])
+		return id, id <= limit, nil
+	}
+	return 0, false, it.Error()
+}
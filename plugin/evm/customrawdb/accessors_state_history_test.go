@@ -0,0 +1,75 @@
+// Copyright (C) 2019-2025, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package customrawdb
+
+import (
+	"testing"
+
+	"github.com/MetalBlockchain/libevm/common"
+	"github.com/MetalBlockchain/libevm/core/rawdb"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStateHistoryIndex(t *testing.T) {
+	require := require.New(t)
+	db := rawdb.NewMemoryDatabase()
+
+	index, err := ReadStateHistoryIndex(db)
+	require.NoError(err)
+	require.Nil(index)
+
+	expected := &StateHistoryIndex{Tail: 1, Head: 10, DataBytes: 100, IndexBytes: 200}
+	require.NoError(WriteStateHistoryIndex(db, expected))
+	index, err = ReadStateHistoryIndex(db)
+	require.NoError(err)
+	require.Equal(expected, index)
+}
+
+func TestReadStateHistoryChange(t *testing.T) {
+	require := require.New(t)
+	db := rawdb.NewMemoryDatabase()
+
+	var (
+		addr      = common.Address{1}
+		otherAddr = common.Address{2}
+		slot      = common.Hash{3}
+	)
+	for _, id := range []uint64{2, 5, 9} {
+		require.NoError(WriteStateHistoryAccount(db, addr, id))
+		require.NoError(WriteStateHistoryStorage(db, addr, slot, id+1))
+	}
+	require.NoError(WriteStateHistoryAccount(db, otherAddr, 3))
+
+	tests := []struct {
+		after, limit uint64
+		expectedID   uint64
+		expectedOK   bool
+	}{
+		{after: 0, limit: 10, expectedID: 2, expectedOK: true},
+		{after: 2, limit: 10, expectedID: 5, expectedOK: true},
+		{after: 4, limit: 10, expectedID: 5, expectedOK: true},
+		{after: 5, limit: 8, expectedOK: false},
+		{after: 9, limit: 10, expectedOK: false},
+		{after: 10, limit: 10, expectedOK: false},
+	}
+	for _, test := range tests {
+		id, ok, err := ReadStateHistoryAccountChange(db, addr, test.after, test.limit)
+		require.NoError(err)
+		require.Equal(test.expectedOK, ok, "after %d, limit %d", test.after, test.limit)
+		if ok {
+			require.Equal(test.expectedID, id)
+		}
+
+		id, ok, err = ReadStateHistoryStorageChange(db, addr, slot, test.after+1, test.limit+1)
+		require.NoError(err)
+		require.Equal(test.expectedOK, ok, "after %d, limit %d", test.after+1, test.limit+1)
+		if ok {
+			require.Equal(test.expectedID+1, id)
+		}
+	}
+
+	_, ok, err := ReadStateHistoryStorageChange(db, otherAddr, slot, 0, 10)
+	require.NoError(err)
+	require.False(ok)
+}
@@ -32,6 +32,11 @@ func WriteSyncRoot(db ethdb.KeyValueWriter, root common.Hash) error {
 	return db.Put(syncRootKey, root[:])
 }
 
+// DeleteSyncRoot removes the root of the main trie of the in-progress sync.
+func DeleteSyncRoot(db ethdb.KeyValueWriter) error {
+	return db.Delete(syncRootKey)
+}
+
 // AddCodeToFetch adds a marker that we need to fetch the code for `hash`.
 func AddCodeToFetch(db ethdb.KeyValueWriter, hash common.Hash) {
 	if err := db.Put(codeToFetchKey(hash), nil); err != nil {
@@ -56,6 +61,12 @@ func NewCodeToFetchIterator(db ethdb.Iteratee) ethdb.Iterator {
 	)
 }
 
+// ClearAllCodeToFetch removes all markers of code that needs to be fetched
+// from db.
+func ClearAllCodeToFetch(db ethdb.KeyValueStore) error {
+	return clearPrefix(db, CodeToFetchPrefix, codeToFetchKeyLength)
+}
+
 func codeToFetchKey(hash common.Hash) []byte {
 	codeToFetchKey := make([]byte, codeToFetchKeyLength)
 	copy(codeToFetchKey, CodeToFetchPrefix)
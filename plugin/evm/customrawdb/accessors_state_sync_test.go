@@ -32,3 +32,47 @@ func TestClearPrefix(t *testing.T) {
 	require.NoError(it.Error())
 	require.Equal(1, count)
 }
+
+func TestClearSyncProgress(t *testing.T) {
+	require := require.New(t)
+	db := rawdb.NewMemoryDatabase()
+	require.NoError(WriteSyncRoot(db, common.Hash{1}))
+	AddCodeToFetch(db, common.Hash{2})
+	AddCodeToFetch(db, common.Hash{3})
+	require.NoError(WriteSyncPerformed(db, 10))
+
+	require.NoError(DeleteSyncRoot(db))
+	require.NoError(ClearAllCodeToFetch(db))
+
+	root, err := ReadSyncRoot(db)
+	require.NoError(err)
+	require.Zero(root)
+	it := NewCodeToFetchIterator(db)
+	defer it.Release()
+	require.False(it.Next())
+	require.NoError(it.Error())
+	// Completed syncs are still recorded.
+	require.Equal(uint64(10), GetLatestSyncPerformed(db))
+}
+
+func TestExtKeyCategory(t *testing.T) {
+	require := require.New(t)
+	db := rawdb.NewMemoryDatabase()
+	require.NoError(WriteSyncSegment(db, common.Hash{1}, common.Hash{}))
+	require.NoError(WriteAcceptorTip(db, common.Hash{2}))
+	rawdb.WriteCanonicalHash(db, common.Hash{3}, 1)
+
+	categories := make(map[string]int)
+	it := db.NewIterator(nil, nil)
+	defer it.Release()
+	for it.Next() {
+		if category, ok := ExtKeyCategory(it.Key()); ok {
+			categories[category]++
+		}
+	}
+	require.NoError(it.Error())
+	require.Equal(map[string]int{
+		"Trie segments":   1,
+		"Coreth metadata": 1,
+	}, categories)
+}
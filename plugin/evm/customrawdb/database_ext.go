@@ -12,21 +12,61 @@ import (
 	"github.com/MetalBlockchain/libevm/ethdb"
 )
 
+// extKeyCategories are the categories of keys coreth adds to the chain
+// database, in addition to those of libevm.
+var extKeyCategories = []struct {
+	database  string
+	name      string
+	keyLen    int
+	keyPrefix []byte
+}{
+	{"State sync", "Trie segments", syncSegmentsKeyLength, syncSegmentsPrefix},
+	{"State sync", "Storage tries to fetch", syncStorageTriesKeyLength, syncStorageTriesPrefix},
+	{"State sync", "Code to fetch", codeToFetchKeyLength, CodeToFetchPrefix},
+	{"State sync", "Block numbers synced to", syncPerformedKeyLength, syncPerformedPrefix},
+	{"State history", "State histories", stateHistoryKeyLength, stateHistoryPrefix},
+	{"State history", "Account index", stateHistoryAccountKeyLength, stateHistoryAccountPrefix},
+	{"State history", "Storage index", stateHistoryStorageKeyLength, stateHistoryStoragePrefix},
+	{"Log index", "Address index", logIndexAddressKeyLength, logIndexAddressPrefix},
+	{"Log index", "Topic index", logIndexTopicKeyLength, logIndexTopicPrefix},
+}
+
+// extMetadataKeys are the singleton keys coreth adds to the chain database.
+var extMetadataKeys = [][]byte{
+	snapshotBlockHashKey,
+	offlinePruningKey,
+	populateMissingTriesKey,
+	pruningDisabledKey,
+	acceptorTipKey,
+	blockBackfillKey,
+	stateHistoryIndexKey,
+	logIndexRangeKey,
+	bodyTailKey,
+	receiptTailKey,
+	syncRootKey,
+}
+
+// ExtKeyCategory returns the name of the category of [key] if it is one of
+// the keys coreth adds to the chain database, in addition to those of libevm.
+// Singleton keys are in the "Coreth metadata" category.
+func ExtKeyCategory(key []byte) (string, bool) {
+	for _, c := range extKeyCategories {
+		if len(key) == c.keyLen && bytes.HasPrefix(key, c.keyPrefix) {
+			return c.name, true
+		}
+	}
+	for _, k := range extMetadataKeys {
+		if bytes.Equal(key, k) {
+			return "Coreth metadata", true
+		}
+	}
+	return "", false
+}
+
 // InspectDatabase traverses the entire database and checks the size
 // of all different categories of data.
 func InspectDatabase(db ethdb.Database, keyPrefix, keyStart []byte) error {
-	type stat = rawdb.DatabaseStat
-	stats := []struct {
-		name      string
-		keyLen    int
-		keyPrefix []byte
-		stat      *stat
-	}{
-		{"Trie segments", syncSegmentsKeyLength, syncSegmentsPrefix, &stat{}},
-		{"Storage tries to fetch", syncStorageTriesKeyLength, syncStorageTriesPrefix, &stat{}},
-		{"Code to fetch", codeToFetchKeyLength, CodeToFetchPrefix, &stat{}},
-		{"Block numbers synced to", syncPerformedKeyLength, syncPerformedPrefix, &stat{}},
-	}
+	stats := make([]rawdb.DatabaseStat, len(extKeyCategories))
 
 	options := []rawdb.InspectDatabaseOption{
 		rawdb.WithDatabaseMetadataKeys(func(key []byte) bool {
@@ -34,9 +74,9 @@ func InspectDatabase(db ethdb.Database, keyPrefix, keyStart []byte) error {
 				bytes.Equal(key, syncRootKey)
 		}),
 		rawdb.WithDatabaseStatRecorder(func(key []byte, size common.StorageSize) bool {
-			for _, s := range stats {
-				if len(key) == s.keyLen && bytes.HasPrefix(key, s.keyPrefix) {
-					s.stat.Add(size)
+			for i, c := range extKeyCategories {
+				if len(key) == c.keyLen && bytes.HasPrefix(key, c.keyPrefix) {
+					stats[i].Add(size)
 					return true
 				}
 			}
@@ -53,8 +93,8 @@ func InspectDatabase(db ethdb.Database, keyPrefix, keyStart []byte) error {
 					newRows = append(newRows, row)
 				}
 			}
-			for _, s := range stats {
-				newRows = append(newRows, []string{"State sync", s.name, s.stat.Size(), s.stat.Count()})
+			for i, c := range extKeyCategories {
+				newRows = append(newRows, []string{c.database, c.name, stats[i].Size(), stats[i].Count()})
 			}
 			return newRows
 		}),
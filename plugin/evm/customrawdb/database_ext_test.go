@@ -62,6 +62,11 @@ func ExampleInspectDatabase() {
 	// | State sync      | Storage tries to fetch  | 77.00 B  |     1 |
 	// | State sync      | Code to fetch           | 34.00 B  |     1 |
 	// | State sync      | Block numbers synced to | 23.00 B  |     1 |
+	// | State history   | State histories         | 0.00 B   |     0 |
+	// | State history   | Account index           | 0.00 B   |     0 |
+	// | State history   | Storage index           | 0.00 B   |     0 |
+	// | Log index       | Address index           | 0.00 B   |     0 |
+	// | Log index       | Topic index             | 0.00 B   |     0 |
 	// +-----------------+-------------------------+----------+-------+
 	// |                            TOTAL          | 305.00 B |       |
 	// +-----------------+-------------------------+----------+-------+
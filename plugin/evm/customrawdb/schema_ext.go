@@ -20,6 +20,8 @@ var (
 	pruningDisabledKey = []byte("PruningDisabled")
 	// acceptorTipKey tracks the tip of the last accepted block that has been fully processed.
 	acceptorTipKey = []byte("AcceptorTipKey")
+	// blockBackfillKey tracks the progress of the block backfill after state sync.
+	blockBackfillKey = []byte("BlockBackfill")
 )
 
 // State sync progress keys and prefixes
@@ -52,4 +54,51 @@ var (
 	syncPerformedKeyLength = len(syncPerformedPrefix) + wrappers.LongLen
 )
 
+// State history keys and prefixes
+var (
+	// stateHistoryIndexKey tracks the range and size of the indexed state histories.
+	stateHistoryIndexKey = []byte("StateHistoryIndex")
+	// stateHistoryPrefix + state id (uint64 big endian) -> state history
+	stateHistoryPrefix = []byte("shd")
+	// stateHistoryAccountPrefix + address + state id -> empty value
+	// indicates the state history with the id modifies the account.
+	stateHistoryAccountPrefix = []byte("sha")
+	// stateHistoryStoragePrefix + address + slot hash + state id -> empty value
+	// indicates the state history with the id modifies the storage slot.
+	stateHistoryStoragePrefix = []byte("shs")
+)
+
+// State history key lengths
+var (
+	stateHistoryKeyLength        = len(stateHistoryPrefix) + wrappers.LongLen
+	stateHistoryAccountKeyLength = len(stateHistoryAccountPrefix) + common.AddressLength + wrappers.LongLen
+	stateHistoryStorageKeyLength = len(stateHistoryStoragePrefix) + common.AddressLength + common.HashLength + wrappers.LongLen
+)
+
+// Chain history retention keys
+var (
+	// bodyTailKey tracks the number of the oldest block whose body is retained.
+	bodyTailKey = []byte("BodyTail")
+	// receiptTailKey tracks the number of the oldest block whose receipts are retained.
+	receiptTailKey = []byte("ReceiptTail")
+)
+
+// Log index keys and prefixes
+var (
+	// logIndexRangeKey tracks the range of blocks whose logs are indexed.
+	logIndexRangeKey = []byte("LogIndexRange")
+	// logIndexAddressPrefix + address + block number (uint64 big endian) -> empty value
+	// indicates the block contains logs emitted by the address.
+	logIndexAddressPrefix = []byte("lia")
+	// logIndexTopicPrefix + address + topic position + topic + block number -> empty value
+	// indicates the block contains logs emitted by the address with the topic at the position.
+	logIndexTopicPrefix = []byte("lit")
+)
+
+// Log index key lengths
+var (
+	logIndexAddressKeyLength = len(logIndexAddressPrefix) + common.AddressLength + wrappers.LongLen
+	logIndexTopicKeyLength   = len(logIndexTopicPrefix) + common.AddressLength + wrappers.ByteLen + common.HashLength + wrappers.LongLen
+)
+
 var FirewoodScheme = "firewood"
@@ -6,6 +6,7 @@ package extension
 import (
 	"context"
 	"errors"
+	"net/http"
 
 	"github.com/MetalBlockchain/metalgo/database"
 	"github.com/MetalBlockchain/metalgo/database/versiondb"
@@ -68,6 +69,9 @@ type ExtensibleVM interface {
 	VersionDB() *versiondb.Database
 	// SyncerClient returns the syncer client for the VM
 	SyncerClient() vmsync.Client
+	// AuthHandler wraps the handler of the [namespace] API so that it requires
+	// the RPC authentication configured for the VM, if any
+	AuthHandler(namespace string, handler http.Handler) (http.Handler, error)
 }
 
 // InnerVM is the interface that must be implemented by the VM
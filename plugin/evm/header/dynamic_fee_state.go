@@ -30,23 +30,35 @@ func feeStateBeforeBlock(
 		)
 	}
 
-	var state acp176.State
-	if config.IsFortuna(parent.Time) && parent.Number.Cmp(common.Big0) != 0 {
-		// If the parent block was running with ACP-176, we start with the
-		// resulting fee state from the parent block. It is assumed that the
-		// parent has been verified, so the claimed fee state equals the actual
-		// fee state.
-		var err error
-		state, err = acp176.ParseState(parent.Extra)
-		if err != nil {
-			return acp176.State{}, fmt.Errorf("parsing parent fee state: %w", err)
-		}
+	state, err := ParentFeeState(config, parent)
+	if err != nil {
+		return acp176.State{}, err
 	}
 
 	state.AdvanceTime(timestamp - parent.Time)
 	return state, nil
 }
 
+// ParentFeeState returns the fee state resulting from the execution of the
+// parent block. If the parent block was not running with ACP-176, the zero
+// state is returned, which is the initial state of the first ACP-176 block.
+func ParentFeeState(
+	config *extras.ChainConfig,
+	parent *types.Header,
+) (acp176.State, error) {
+	if !config.IsFortuna(parent.Time) || parent.Number.Cmp(common.Big0) == 0 {
+		return acp176.State{}, nil
+	}
+
+	// It is assumed that the parent has been verified, so the claimed fee state
+	// equals the actual fee state.
+	state, err := acp176.ParseState(parent.Extra)
+	if err != nil {
+		return acp176.State{}, fmt.Errorf("parsing parent fee state: %w", err)
+	}
+	return state, nil
+}
+
 // feeStateAfterBlock takes the previous header and returns the fee state after
 // the execution of the provided child.
 func feeStateAfterBlock(
@@ -4,6 +4,7 @@
 package header
 
 import (
+	"bytes"
 	"math/big"
 	"testing"
 
@@ -821,3 +822,49 @@ func TestPredicateBytesExtra(t *testing.T) {
 		})
 	}
 }
+
+func FuzzVerifyExtra(f *testing.F) {
+	rulesList := []extras.AvalancheRules{
+		{},
+		{IsApricotPhase1: true},
+		{IsApricotPhase1: true, IsApricotPhase3: true},
+		{IsApricotPhase1: true, IsApricotPhase3: true, IsDurango: true},
+		{IsApricotPhase1: true, IsApricotPhase3: true, IsDurango: true, IsFortuna: true},
+	}
+	for _, size := range []int{0, 1, ap3.WindowSize, acp176.StateSize, int(ap0.MaximumExtraDataSize)} {
+		f.Add(make([]byte, size), []byte{})
+		f.Add(make([]byte, size+1), []byte{1, 2, 3})
+	}
+	f.Fuzz(func(t *testing.T, extra []byte, predicateBytes []byte) {
+		require := require.New(t)
+
+		for _, rules := range rulesList {
+			if err := VerifyExtra(rules, extra); err != nil {
+				require.ErrorIs(err, errInvalidExtraLength)
+				continue
+			}
+			if rules.IsFortuna {
+				_, err := acp176.ParseState(extra)
+				require.NoError(err)
+			}
+			if !rules.IsApricotPhase3 {
+				continue
+			}
+
+			// Splitting valid extra data into its prefix and predicate results
+			// must be lossless.
+			existing := PredicateBytesFromExtra(rules, extra)
+			require.Equal(extra, SetPredicateBytesInExtra(rules, bytes.Clone(extra), existing))
+
+			withPredicate := SetPredicateBytesInExtra(rules, bytes.Clone(extra), predicateBytes)
+			if len(predicateBytes) == 0 {
+				require.Empty(PredicateBytesFromExtra(rules, withPredicate))
+			} else {
+				require.Equal(predicateBytes, PredicateBytesFromExtra(rules, withPredicate))
+			}
+			if rules.IsDurango {
+				require.NoError(VerifyExtra(rules, withPredicate))
+			}
+		}
+	})
+}
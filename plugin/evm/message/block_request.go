@@ -14,6 +14,8 @@ import (
 var _ Request = (*BlockRequest)(nil)
 
 // BlockRequest is a request to retrieve Parents number of blocks starting from Hash from newest-oldest manner
+// If Hash is empty, the blocks start from the canonical block at Height. Peers
+// which do not support this respond with no blocks.
 type BlockRequest struct {
 	Hash    common.Hash `serialize:"true"`
 	Height  uint64      `serialize:"true"`
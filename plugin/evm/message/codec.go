@@ -38,6 +38,12 @@ func init() {
 	// See https://github.com/MetalBlockchain/coreth/pull/999
 	c.SkipRegistrations(3)
 
+	errs.Add(
+		// block backfill types
+		c.RegisterType(ReceiptsRequest{}),
+		c.RegisterType(ReceiptsResponse{}),
+	)
+
 	Codec.RegisterCodec(Version, c)
 
 	if errs.Errored() {
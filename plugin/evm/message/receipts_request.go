@@ -0,0 +1,44 @@
+// Copyright (C) 2019-2025, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package message
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/MetalBlockchain/metalgo/ids"
+	"github.com/MetalBlockchain/libevm/common"
+)
+
+// MaxReceiptsHashesPerRequest is the maximum number of blocks whose receipts
+// can be requested at once.
+const MaxReceiptsHashesPerRequest = 64
+
+var _ Request = ReceiptsRequest{}
+
+// ReceiptsRequest is a request to retrieve the receipts of the blocks with
+// the specified Hashes
+type ReceiptsRequest struct {
+	Hashes []common.Hash `serialize:"true"`
+}
+
+func (r ReceiptsRequest) String() string {
+	if len(r.Hashes) == 0 {
+		return "ReceiptsRequest(Hashes=[])"
+	}
+	return fmt.Sprintf("ReceiptsRequest(First=%s, Len=%d)", r.Hashes[0], len(r.Hashes))
+}
+
+func (r ReceiptsRequest) Handle(ctx context.Context, nodeID ids.NodeID, requestID uint32, handler RequestHandler) ([]byte, error) {
+	return handler.HandleReceiptsRequest(ctx, nodeID, requestID, r)
+}
+
+// ReceiptsResponse is a response to a ReceiptsRequest
+// Receipts holds the RLP encoded receipts, in their storage format, of a
+// prefix of the blocks requested in ReceiptsRequest.Hashes. The response may
+// omit trailing blocks to fit the message size limit.
+// handler: handlers.ReceiptsRequestHandler
+type ReceiptsResponse struct {
+	Receipts [][]byte `serialize:"true"`
+}
@@ -14,6 +14,13 @@ type Syncable interface {
 	GetBlockRoot() common.Hash
 }
 
+// ExtendedSyncable is a [Syncable] that commits to tries other than the
+// state trie, keyed by the [NodeType] used to request their leaves.
+type ExtendedSyncable interface {
+	Syncable
+	ExtraTrieRoots() map[NodeType]common.Hash
+}
+
 type SyncableParser interface {
 	Parse(summaryBytes []byte, acceptImpl AcceptImplFn) (Syncable, error)
 }
@@ -23,9 +23,10 @@ var _ message.RequestHandler = (*networkHandler)(nil)
 type LeafHandlers map[message.NodeType]syncHandlers.LeafRequestHandler
 
 type networkHandler struct {
-	leafRequestHandlers LeafHandlers
-	blockRequestHandler *syncHandlers.BlockRequestHandler
-	codeRequestHandler  *syncHandlers.CodeRequestHandler
+	leafRequestHandlers    LeafHandlers
+	blockRequestHandler    *syncHandlers.BlockRequestHandler
+	codeRequestHandler     *syncHandlers.CodeRequestHandler
+	receiptsRequestHandler *syncHandlers.ReceiptsRequestHandler
 }
 
 type LeafRequestTypeConfig struct {
@@ -39,15 +40,16 @@ type LeafRequestTypeConfig struct {
 // newNetworkHandler constructs the handler for serving network requests.
 func newNetworkHandler(
 	provider syncHandlers.SyncDataProvider,
-	diskDB ethdb.KeyValueReader,
+	diskDB ethdb.Reader,
 	networkCodec codec.Manager,
 	leafRequestHandlers LeafHandlers,
 	syncStats stats.HandlerStats,
 ) *networkHandler {
 	return &networkHandler{
-		leafRequestHandlers: leafRequestHandlers,
-		blockRequestHandler: syncHandlers.NewBlockRequestHandler(provider, networkCodec, syncStats),
-		codeRequestHandler:  syncHandlers.NewCodeRequestHandler(diskDB, networkCodec, syncStats),
+		leafRequestHandlers:    leafRequestHandlers,
+		blockRequestHandler:    syncHandlers.NewBlockRequestHandler(provider, networkCodec, syncStats),
+		codeRequestHandler:     syncHandlers.NewCodeRequestHandler(diskDB, networkCodec, syncStats),
+		receiptsRequestHandler: syncHandlers.NewReceiptsRequestHandler(diskDB, networkCodec, syncStats),
 	}
 }
 
@@ -67,3 +69,7 @@ func (n networkHandler) HandleBlockRequest(ctx context.Context, nodeID ids.NodeI
 func (n networkHandler) HandleCodeRequest(ctx context.Context, nodeID ids.NodeID, requestID uint32, codeRequest message.CodeRequest) ([]byte, error) {
 	return n.codeRequestHandler.OnCodeRequest(ctx, nodeID, requestID, codeRequest)
 }
+
+func (n networkHandler) HandleReceiptsRequest(ctx context.Context, nodeID ids.NodeID, requestID uint32, receiptsRequest message.ReceiptsRequest) ([]byte, error) {
+	return n.receiptsRequestHandler.OnReceiptsRequest(ctx, nodeID, requestID, receiptsRequest)
+}
@@ -0,0 +1,138 @@
+// Copyright (C) 2019-2025, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package evm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/MetalBlockchain/metalgo/database"
+	"github.com/MetalBlockchain/metalgo/ids"
+	"github.com/MetalBlockchain/metalgo/snow/engine/snowman/block"
+	"github.com/MetalBlockchain/libevm/log"
+
+	"github.com/MetalBlockchain/coreth/plugin/evm/message"
+	"github.com/MetalBlockchain/coreth/sync/syncfile"
+
+	statesyncclient "github.com/MetalBlockchain/coreth/sync/client"
+	vmsync "github.com/MetalBlockchain/coreth/sync/vm"
+)
+
+var errSummaryIDMismatch = errors.New("state sync snapshot summary ID mismatch")
+
+// exportStateSnapshot writes a state sync snapshot of the summary at
+// [height] to [dir]. If [height] is zero, the last summary is exported.
+//
+// The snapshot is read through the handlers serving state sync peers, and
+// every response is verified before it is written.
+func (vm *VM) exportStateSnapshot(ctx context.Context, dir string, height uint64) (*syncfile.Manifest, error) {
+	vm.ctx.Lock.Lock()
+	var (
+		stateSummary block.StateSummary
+		err          error
+	)
+	if height == 0 {
+		stateSummary, err = vm.Server.GetLastStateSummary(ctx)
+	} else {
+		stateSummary, err = vm.Server.GetStateSummary(ctx, height)
+	}
+	vm.ctx.Lock.Unlock()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get state summary: %w", err)
+	}
+	summary, ok := stateSummary.(message.Syncable)
+	if !ok {
+		return nil, fmt.Errorf("unexpected state summary type %T", stateSummary)
+	}
+
+	return syncfile.Export(ctx, statesyncclient.NewHandlerClient(vm.syncRequestHandler, vm.networkCodec, vm), syncfile.ExportConfig{
+		Dir:         dir,
+		Summary:     summary,
+		Codec:       vm.networkCodec,
+		RequestSize: vm.config.StateSyncRequestSize,
+		Blocks:      vmsync.BlocksToFetch,
+	})
+}
+
+// importStateSnapshot syncs to the summary of the state sync snapshot in
+// [vm.config.StateSyncSnapshotDir], unless the node has already accepted the
+// summary block. Returns the last accepted height after the sync.
+//
+// The snapshot is synced with the same syncers and verification as a state
+// sync from peers, and an interrupted import resumes on restart unless
+// [vm.config.StateSyncSkipResume] is set.
+func (vm *VM) importStateSnapshot(lastAcceptedHeight uint64) (uint64, error) {
+	dir := vm.config.StateSyncSnapshotDir
+	reader, err := syncfile.Open(os.DirFS(dir))
+	if err != nil {
+		return 0, err
+	}
+	defer reader.Close()
+
+	manifest := reader.Manifest()
+	if lastAcceptedHeight >= manifest.BlockNumber {
+		log.Info("skipping state sync snapshot at or below last accepted height",
+			"dir", dir,
+			"lastAccepted", lastAcceptedHeight,
+			"snapshotHeight", manifest.BlockNumber,
+		)
+		return lastAcceptedHeight, nil
+	}
+
+	stateSyncDone := make(chan struct{})
+	client := vmsync.NewClient(&vmsync.ClientConfig{
+		StateSyncDone:      stateSyncDone,
+		Chain:              vm.eth,
+		State:              vm.State,
+		Client:             statesyncclient.NewFileClient(reader, vm.networkCodec, vm),
+		Enabled:            true,
+		SkipResume:         vm.config.StateSyncSkipResume,
+		RequestSize:        vm.config.StateSyncRequestSize,
+		LastAcceptedHeight: lastAcceptedHeight,
+		ChainDB:            vm.chaindb,
+		VerDB:              vm.versiondb,
+		MetadataDB:         vm.metadataDB,
+		Acceptor:           vm,
+		Parser:             vm.extensionConfig.SyncableParser,
+		Extender:           vm.extensionConfig.SyncExtender,
+	})
+	// Load the summary of an interrupted import, so that it is resumed.
+	if _, err := client.GetOngoingSyncStateSummary(context.TODO()); err != nil && !errors.Is(err, database.ErrNotFound) {
+		return 0, err
+	}
+	summary, err := client.ParseStateSummary(context.TODO(), manifest.Summary)
+	if err != nil {
+		return 0, err
+	}
+	if summary.ID() != manifest.SummaryID {
+		return 0, fmt.Errorf("%w: manifest has %s but summary has %s", errSummaryIDMismatch, manifest.SummaryID, summary.ID())
+	}
+	// The snapshot is only verified against its own summary, which is
+	// therefore checked against the trusted ID of the config.
+	expectedID, err := ids.FromString(vm.config.StateSyncSnapshotSummaryID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse state-sync-snapshot-summary-id: %w", err)
+	}
+	if summary.ID() != expectedID {
+		return 0, fmt.Errorf("%w: expected %s but snapshot has %s", errSummaryIDMismatch, expectedID, summary.ID())
+	}
+
+	log.Info("importing state sync snapshot", "dir", dir, "summary", summary)
+	mode, err := summary.Accept(context.TODO())
+	if err != nil {
+		return 0, err
+	}
+	if mode == block.StateSyncSkipped {
+		return lastAcceptedHeight, nil
+	}
+	<-stateSyncDone
+	if err := client.Error(); err != nil {
+		return 0, err
+	}
+	lastAcceptedHeight = vm.blockChain.LastAcceptedBlock().NumberU64()
+	log.Info("imported state sync snapshot", "dir", dir, "height", lastAcceptedHeight)
+	return lastAcceptedHeight, nil
+}
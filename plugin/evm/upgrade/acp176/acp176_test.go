@@ -825,3 +825,48 @@ func BenchmarkBytes(b *testing.B) {
 		})
 	}
 }
+
+func FuzzParseState(f *testing.F) {
+	for _, test := range parseTests {
+		f.Add(test.bytes, uint64(0), uint64(0), uint64(0))
+	}
+	for _, test := range readerTests {
+		f.Add(test.state.Bytes(), uint64(1), uint64(MinTargetPerSecond), uint64(test.state.TargetExcess))
+	}
+	f.Fuzz(func(t *testing.T, bytes []byte, seconds uint64, gasUsed uint64, desiredTargetExcess uint64) {
+		require := require.New(t)
+
+		state, err := ParseState(bytes)
+		if len(bytes) < StateSize {
+			require.ErrorIs(err, ErrStateInsufficientLength)
+			return
+		}
+		require.NoError(err)
+		require.Equal(bytes[:StateSize], state.Bytes())
+
+		previous := state
+		state.AdvanceTime(seconds)
+		require.LessOrEqual(state.Gas.Excess, previous.Gas.Excess)
+		require.LessOrEqual(state.Gas.Capacity, state.MaxCapacity())
+		require.Equal(previous.TargetExcess, state.TargetExcess)
+
+		previous = state
+		if err := state.ConsumeGas(gasUsed, nil); err != nil {
+			require.ErrorIs(err, gas.ErrInsufficientCapacity)
+			require.Equal(previous, state)
+		} else {
+			require.Equal(previous.Gas.Capacity-gas.Gas(gasUsed), state.Gas.Capacity)
+			require.GreaterOrEqual(state.Gas.Excess, previous.Gas.Excess)
+		}
+
+		previous = state
+		state.UpdateTargetExcess(gas.Gas(desiredTargetExcess))
+		diff := max(state.TargetExcess, previous.TargetExcess) - min(state.TargetExcess, previous.TargetExcess)
+		require.LessOrEqual(diff, gas.Gas(MaxTargetExcessDiff))
+		require.LessOrEqual(state.Gas.Capacity, state.MaxCapacity())
+
+		parsed, err := ParseState(state.Bytes())
+		require.NoError(err)
+		require.Equal(state, parsed)
+	})
+}
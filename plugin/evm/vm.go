@@ -9,6 +9,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"math"
 	"math/big"
 	"net/http"
 	"os"
@@ -75,9 +76,11 @@ import (
 	"github.com/MetalBlockchain/coreth/plugin/evm/vmerrors"
 	"github.com/MetalBlockchain/coreth/precompile/precompileconfig"
 	"github.com/MetalBlockchain/coreth/rpc"
+	"github.com/MetalBlockchain/coreth/sync/blocksync"
 	"github.com/MetalBlockchain/coreth/sync/client/stats"
 	"github.com/MetalBlockchain/coreth/sync/handlers"
 	"github.com/MetalBlockchain/coreth/triedb/hashdb"
+	"github.com/MetalBlockchain/coreth/triedb/pathdb"
 	"github.com/MetalBlockchain/coreth/warp"
 
 	corethlog "github.com/MetalBlockchain/coreth/plugin/evm/log"
@@ -180,6 +183,8 @@ type VM struct {
 	ctx *snow.Context
 	// [cancel] may be nil until [snow.NormalOp] starts
 	cancel context.CancelFunc
+	// [backfillCancel] is nil unless a block backfill was started
+	backfillCancel context.CancelFunc
 	// *chain.State helps to implement the VM interface by wrapping blocks
 	// with an efficient caching layer.
 	*chain.State
@@ -245,6 +250,12 @@ type VM struct {
 	// State sync server and client
 	vmsync.Server
 	vmsync.Client
+	// syncRequestHandler serves state sync requests, and is used to export
+	// state sync snapshots.
+	syncRequestHandler message.RequestHandler
+	// syncClient requests blocks and receipts from peers, and is used to
+	// backfill the blocks preceding the last state sync.
+	syncClient statesyncclient.Client
 
 	// Avalanche Warp Messaging backend
 	// Used to serve BLS signatures of warp messages over RPC
@@ -255,6 +266,9 @@ type VM struct {
 	chainAlias string
 	// RPC handlers (should be stopped before closing chaindb)
 	rpcHandlers []interface{ Stop() }
+	// rpcAuth is the authentication required by the APIs, or nil if none is
+	// required. It is loaded by CreateHandlers.
+	rpcAuth *rpc.AuthConfig
 }
 
 // Initialize implements the snowman.ChainVM interface
@@ -376,10 +390,14 @@ func (vm *VM) Initialize(
 	vm.ethConfig.PopulateMissingTries = vm.config.PopulateMissingTries
 	vm.ethConfig.PopulateMissingTriesParallelism = vm.config.PopulateMissingTriesParallelism
 	vm.ethConfig.AllowMissingTries = vm.config.AllowMissingTries
-	vm.ethConfig.SnapshotDelayInit = vm.stateSyncEnabled(lastAcceptedHeight)
+	vm.ethConfig.SnapshotDelayInit = vm.stateSyncEnabled(lastAcceptedHeight) || len(vm.config.StateSyncSnapshotDir) > 0
 	vm.ethConfig.SnapshotWait = vm.config.SnapshotWait
 	vm.ethConfig.SnapshotVerify = vm.config.SnapshotVerify
 	vm.ethConfig.HistoricalProofQueryWindow = vm.config.HistoricalProofQueryWindow
+	vm.ethConfig.StateReconstruction = vm.config.StateReconstructionEnabled
+	vm.ethConfig.StateReconstructionCacheSize = vm.config.StateReconstructionCacheSize
+	vm.ethConfig.StateReconstructionMaxBlocks = vm.config.StateReconstructionMaxBlocks
+	vm.ethConfig.StateReconstructionParallelism = vm.config.StateReconstructionParallelism
 	vm.ethConfig.OfflinePruning = vm.config.OfflinePruning
 	vm.ethConfig.OfflinePruningBloomFilterSize = vm.config.OfflinePruningBloomFilterSize
 	vm.ethConfig.OfflinePruningDataDirectory = vm.config.OfflinePruningDataDirectory
@@ -389,6 +407,10 @@ func (vm *VM) Initialize(
 	vm.ethConfig.StateHistory = vm.config.StateHistory
 	vm.ethConfig.TransactionHistory = vm.config.TransactionHistory
 	vm.ethConfig.SkipTxIndexing = vm.config.SkipTxIndexing
+	vm.ethConfig.LogIndex = vm.config.LogIndexEnabled
+	vm.ethConfig.LogIndexHistory = vm.config.LogIndexHistory
+	vm.ethConfig.BodyHistory = vm.config.BodyHistory
+	vm.ethConfig.ReceiptHistory = vm.config.ReceiptHistory
 	vm.ethConfig.StateScheme = vm.config.StateScheme
 
 	if vm.ethConfig.StateScheme == customrawdb.FirewoodScheme {
@@ -405,13 +427,36 @@ func (vm *VM) Initialize(
 		if vm.config.OfflinePruning {
 			return errors.New("Offline pruning is not supported for Firewood")
 		}
-		if vm.config.StateSyncEnabled == nil || *vm.config.StateSyncEnabled {
+		if vm.config.StateSyncEnabled == nil || *vm.config.StateSyncEnabled || len(vm.config.StateSyncSnapshotDir) > 0 {
 			return errors.New("State sync is not yet supported for Firewood")
 		}
 	}
 	if vm.ethConfig.StateScheme == rawdb.PathScheme {
-		log.Error("Path state scheme is not supported. Please use HashDB or Firewood state schemes instead")
-		return errors.New("Path state scheme is not supported")
+		// The path scheme is only supported experimentally, by archive nodes
+		// indexing their state histories, since trie nodes of historical
+		// states are not persisted.
+		if !vm.config.ExperimentalPathScheme {
+			log.Error("Path state scheme is not supported. Please use HashDB or Firewood state schemes instead")
+			return errors.New("Path state scheme is not supported without experimental-path-scheme-enabled")
+		}
+		if !vm.config.StateHistoryIndex {
+			return errors.New("Path state scheme is not supported without state-history-index-enabled")
+		}
+		log.Warn("Path state scheme is enabled with indexed state histories")
+		log.Warn("This is untested in production, use at your own risk")
+		if vm.config.Pruning {
+			return errors.New("Pruning must be disabled to index state histories")
+		}
+		if vm.config.OfflinePruning {
+			return errors.New("Offline pruning is not supported with indexed state histories")
+		}
+		vm.ethConfig.StateHistoryIndex = true
+	} else if vm.config.StateHistoryIndex {
+		return errors.New("state-history-index-enabled requires the path state scheme")
+	}
+	// The hash scheme is used if no state scheme is configured.
+	if vm.config.StateReconstructionEnabled && vm.ethConfig.StateScheme != "" && vm.ethConfig.StateScheme != rawdb.HashScheme {
+		return fmt.Errorf("state-reconstruction-enabled is not supported with the %s state scheme", vm.ethConfig.StateScheme)
 	}
 
 	// Create directory for offline pruning
@@ -576,17 +621,26 @@ func (vm *VM) initializeChain(lastAcceptedHash common.Hash) error {
 	return vm.initChainState(vm.blockChain.LastAcceptedBlock())
 }
 
-// initializeStateSync initializes the vm for performing state sync and responding to peer requests.
-// If state sync is disabled, this function will wipe any ongoing summary from
-// disk to ensure that we do not continue syncing from an invalid snapshot.
-func (vm *VM) initializeStateSync(lastAcceptedHeight uint64) error {
-	// Create standalone EVM TrieDB (read only) for serving leafs requests.
-	// We create a standalone TrieDB here, so that it has a standalone cache from the one
-	// used by the node when processing blocks.
-	// However, Firewood does not support multiple TrieDBs, so we use the same one.
-	evmTrieDB := vm.eth.BlockChain().TrieDB()
-	if vm.ethConfig.StateScheme != customrawdb.FirewoodScheme {
-		evmTrieDB = triedb.NewDatabase(
+// stateLeafProvider returns the provider serving the state trie to syncing
+// peers, according to the state scheme.
+func (vm *VM) stateLeafProvider() handlers.LeafProvider {
+	switch vm.ethConfig.StateScheme {
+	case rawdb.PathScheme:
+		// The path scheme does not support multiple TrieDBs, so we use the
+		// same one, and read leaves from its persisted nodes rather than from
+		// the snapshot. Storage tries can be served for any state in the diff
+		// layers or the disk layer.
+		return handlers.NewPathLeafProvider(vm.eth.BlockChain().TrieDB(), vm.chaindb, pathdb.MaxDiffLayers+1)
+	case customrawdb.FirewoodScheme:
+		// Firewood stores values rather than trie nodes, so its tries can't be
+		// opened as [trie.Trie]s to prove leafs against.
+		log.Warn("Serving state sync is not supported by Firewood, dropping leafs requests for the state trie")
+		return handlers.NewUnsupportedLeafProvider(customrawdb.FirewoodScheme)
+	default:
+		// Create standalone EVM TrieDB (read only) for serving leafs requests.
+		// We create a standalone TrieDB here, so that it has a standalone cache from the one
+		// used by the node when processing blocks.
+		evmTrieDB := triedb.NewDatabase(
 			vm.chaindb,
 			&triedb.Config{
 				DBOverride: hashdb.Config{
@@ -594,7 +648,14 @@ func (vm *VM) initializeStateSync(lastAcceptedHeight uint64) error {
 				}.BackendConstructor,
 			},
 		)
+		return handlers.NewHashLeafProvider(evmTrieDB, vm.blockChain)
 	}
+}
+
+// initializeStateSync initializes the vm for performing state sync and responding to peer requests.
+// If state sync is disabled, this function will wipe any ongoing summary from
+// disk to ensure that we do not continue syncing from an invalid snapshot.
+func (vm *VM) initializeStateSync(lastAcceptedHeight uint64) error {
 	leafHandlers := make(LeafHandlers)
 	leafMetricsNames := make(map[message.NodeType]string)
 	// register default leaf request handler for state trie
@@ -602,9 +663,9 @@ func (vm *VM) initializeStateSync(lastAcceptedHeight uint64) error {
 	stateLeafRequestConfig := &extension.LeafRequestConfig{
 		LeafType:   message.StateTrieNode,
 		MetricName: "sync_state_trie_leaves",
-		Handler: handlers.NewLeafsRequestHandler(evmTrieDB,
+		Handler: handlers.NewLeafsRequestHandlerWithProvider(vm.stateLeafProvider(),
 			message.StateTrieKeyLength,
-			vm.blockChain, vm.networkCodec,
+			vm.networkCodec,
 			syncStats,
 		),
 	}
@@ -625,8 +686,16 @@ func (vm *VM) initializeStateSync(lastAcceptedHeight uint64) error {
 		syncStats,
 	)
 	vm.Network.SetRequestHandler(networkHandler)
+	vm.syncRequestHandler = networkHandler
 
 	vm.Server = vmsync.NewServer(vm.blockChain, vm.extensionConfig.SyncSummaryProvider, vm.config.StateSyncCommitInterval)
+	if len(vm.config.StateSyncSnapshotDir) > 0 {
+		var err error
+		lastAcceptedHeight, err = vm.importStateSnapshot(lastAcceptedHeight)
+		if err != nil {
+			return fmt.Errorf("failed to import state sync snapshot: %w", err)
+		}
+	}
 	stateSyncEnabled := vm.stateSyncEnabled(lastAcceptedHeight)
 	// parse nodeIDs from state sync IDs in vm config
 	var stateSyncIDs []ids.NodeID
@@ -643,19 +712,20 @@ func (vm *VM) initializeStateSync(lastAcceptedHeight uint64) error {
 	}
 
 	// Initialize the state sync client
+	vm.syncClient = statesyncclient.NewClient(
+		&statesyncclient.ClientConfig{
+			NetworkClient:    vm.Network,
+			Codec:            vm.networkCodec,
+			Stats:            stats.NewClientSyncerStats(leafMetricsNames),
+			StateSyncNodeIDs: stateSyncIDs,
+			BlockParser:      vm,
+		},
+	)
 	vm.Client = vmsync.NewClient(&vmsync.ClientConfig{
-		StateSyncDone: vm.stateSyncDone,
-		Chain:         vm.eth,
-		State:         vm.State,
-		Client: statesyncclient.NewClient(
-			&statesyncclient.ClientConfig{
-				NetworkClient:    vm.Network,
-				Codec:            vm.networkCodec,
-				Stats:            stats.NewClientSyncerStats(leafMetricsNames),
-				StateSyncNodeIDs: stateSyncIDs,
-				BlockParser:      vm,
-			},
-		),
+		StateSyncDone:      vm.stateSyncDone,
+		Chain:              vm.eth,
+		State:              vm.State,
+		Client:             vm.syncClient,
 		Enabled:            stateSyncEnabled,
 		SkipResume:         vm.config.StateSyncSkipResume,
 		MinBlocks:          vm.config.StateSyncMinBlocks,
@@ -749,7 +819,74 @@ func (vm *VM) onNormalOperationsStarted() error {
 	vm.bootstrapped.Set(true)
 	// Initialize goroutines related to block building
 	// once we enter normal operation as there is no need to handle mempool gossip before this point.
-	return vm.initBlockBuilding()
+	if err := vm.initBlockBuilding(); err != nil {
+		return err
+	}
+	return vm.startBlockBackfill()
+}
+
+// startBlockBackfill starts fetching the blocks and receipts preceding the
+// last state sync in the background, if enabled.
+func (vm *VM) startBlockBackfill() error {
+	if !vm.config.BlockBackfillEnabled {
+		return nil
+	}
+	syncHeight := customrawdb.GetLatestSyncPerformed(vm.chaindb)
+	if syncHeight == 0 {
+		// The node did not state sync, so it already has every block.
+		return nil
+	}
+	syncHash := rawdb.ReadCanonicalHash(vm.chaindb, syncHeight)
+	if syncHash == (common.Hash{}) {
+		return fmt.Errorf("missing canonical hash of state synced block %d", syncHeight)
+	}
+
+	// The bodies of the blocks older than the body history would be pruned,
+	// so these blocks are not backfilled.
+	toHeight := vm.config.BlockBackfillToHeight
+	if lastAccepted := vm.blockChain.LastAcceptedBlock().NumberU64(); vm.config.BodyHistory != 0 && lastAccepted >= vm.config.BodyHistory {
+		toHeight = max(toHeight, lastAccepted-vm.config.BodyHistory+1)
+	}
+	if toHeight >= syncHeight {
+		return nil
+	}
+
+	// Index the transactions of the blocks within the transaction history,
+	// as the indexer only indexes blocks above the state synced block.
+	txIndexFrom := uint64(0)
+	switch {
+	case vm.config.SkipTxIndexing:
+		txIndexFrom = math.MaxUint64
+	case vm.config.TransactionHistory != 0:
+		if lastAccepted := vm.blockChain.LastAcceptedBlock().NumberU64(); lastAccepted >= vm.config.TransactionHistory {
+			txIndexFrom = lastAccepted - vm.config.TransactionHistory + 1
+		}
+	}
+
+	backfiller := blocksync.NewBackfiller(vm.syncClient, vm.chaindb, blocksync.BackfillConfig{
+		FromHash:    syncHash,
+		FromHeight:  syncHeight,
+		ToHeight:    toHeight,
+		Parallelism: vm.config.BlockBackfillParallelism,
+		TxIndexFrom: txIndexFrom,
+		VerifyBlock: func(ethBlock *types.Block) error {
+			wrapped, err := wrapBlock(ethBlock, vm)
+			if err != nil {
+				return err
+			}
+			return wrapped.syntacticVerify()
+		},
+	})
+	ctx, cancel := context.WithCancel(context.TODO())
+	vm.backfillCancel = cancel
+	vm.shutdownWg.Add(1)
+	go func() {
+		defer vm.shutdownWg.Done()
+		if err := backfiller.Run(ctx); err != nil && ctx.Err() == nil {
+			log.Error("block backfill failed", "err", err)
+		}
+	}()
+	return nil
 }
 
 // initBlockBuilding starts goroutines to manage block building
@@ -882,6 +1019,9 @@ func (vm *VM) Shutdown(context.Context) error {
 	if vm.cancel != nil {
 		vm.cancel()
 	}
+	if vm.backfillCancel != nil {
+		vm.backfillCancel()
+	}
 	vm.Network.Shutdown()
 	if err := vm.Client.Shutdown(); err != nil {
 		log.Error("error stopping state syncer", "err", err)
@@ -1051,6 +1191,49 @@ func (vm *VM) CreateHandlers(context.Context) (map[string]http.Handler, error) {
 	if vm.config.HttpBodyLimit > 0 {
 		handler.SetHTTPBodyLimit(int(vm.config.HttpBodyLimit))
 	}
+	if vm.config.RPCAuthSecretFile != "" {
+		secret, err := rpc.LoadAuthSecret(vm.config.RPCAuthSecretFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load RPC auth secret: %w", err)
+		}
+		vm.rpcAuth = &rpc.AuthConfig{
+			Secret:           secret,
+			PublicNamespaces: vm.config.RPCAuthPublicNamespaces,
+		}
+		if err := handler.SetAuth(*vm.rpcAuth); err != nil {
+			return nil, fmt.Errorf("failed to enable RPC auth: %w", err)
+		}
+		log.Info("enabling RPC authentication", "publicNamespaces", vm.config.RPCAuthPublicNamespaces)
+	}
+	if vm.config.HTTPRateLimitRefillRate > 0 {
+		classes := make([]rpc.RateLimitClass, len(vm.config.HTTPRateLimitClasses))
+		for i, c := range vm.config.HTTPRateLimitClasses {
+			classes[i] = rpc.RateLimitClass{
+				Name:  c.Name,
+				Keys:  c.Keys,
+				Rate:  c.RefillRate,
+				Burst: c.MaxStored,
+			}
+		}
+		err := handler.SetRateLimit(rpc.RateLimitConfig{
+			Rate:             vm.config.HTTPRateLimitRefillRate,
+			Burst:            vm.config.HTTPRateLimitMaxStored,
+			KeyHeader:        vm.config.HTTPRateLimitKeyHeader,
+			Classes:          classes,
+			TrustedProxies:   vm.config.HTTPRateLimitTrustedProxies,
+			IPv6PrefixLength: vm.config.HTTPRateLimitIPv6PrefixLength,
+			MaxClients:       vm.config.HTTPRateLimitMaxClients,
+			MethodCosts:      vm.config.HTTPRateLimitMethodCosts,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to enable HTTP RPC rate limiting: %w", err)
+		}
+		log.Info("enabling HTTP RPC rate limiting",
+			"refillRate", vm.config.HTTPRateLimitRefillRate,
+			"maxStored", vm.config.HTTPRateLimitMaxStored,
+			"classes", len(classes),
+		)
+	}
 
 	enabledAPIs := vm.config.EthAPIs()
 	if err := attachEthService(handler, vm.eth.APIs(), enabledAPIs); err != nil {
@@ -1064,7 +1247,10 @@ func (vm *VM) CreateHandlers(context.Context) (map[string]http.Handler, error) {
 		if err != nil {
 			return nil, fmt.Errorf("failed to register service for admin API due to %w", err)
 		}
-		apis[adminEndpoint] = adminAPI
+		apis[adminEndpoint], err = vm.AuthHandler("admin", adminAPI)
+		if err != nil {
+			return nil, err
+		}
 		enabledAPIs = append(enabledAPIs, "coreth-admin")
 	}
 
@@ -1093,6 +1279,20 @@ func (vm *VM) CreateHandlers(context.Context) (map[string]http.Handler, error) {
 	return apis, nil
 }
 
+// AuthHandler wraps [handler], which serves the [namespace] API, so that it
+// requires the same authentication as the eth APIs. It must be called after
+// CreateHandlers.
+func (vm *VM) AuthHandler(namespace string, handler http.Handler) (http.Handler, error) {
+	if vm.rpcAuth == nil {
+		return handler, nil
+	}
+	authHandler, err := rpc.NewAuthHandler(*vm.rpcAuth, namespace, handler)
+	if err != nil {
+		return nil, fmt.Errorf("failed to enable auth of the %s API: %w", namespace, err)
+	}
+	return authHandler, nil
+}
+
 func (*VM) NewHTTPHandler(context.Context) (http.Handler, error) {
 	return nil, nil
 }
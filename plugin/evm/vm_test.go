@@ -9,6 +9,8 @@ import (
 	"errors"
 	"fmt"
 	"math/big"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
 	"strings"
@@ -2110,3 +2112,44 @@ func TestCreateHandlers(t *testing.T) {
 		require.ErrorIs(t, elem.Error, rpc.ErrMissingBatchResponse)
 	}
 }
+
+func TestCreateHandlersAuth(t *testing.T) {
+	var (
+		ctx        = context.Background()
+		fork       = upgradetest.Latest
+		vm         = newDefaultTestVM()
+		secret     = []byte("0123456789abcdef0123456789abcdef")
+		secretFile = filepath.Join(t.TempDir(), "secret")
+	)
+	require.NoError(t, os.WriteFile(secretFile, []byte(common.Bytes2Hex(secret)), 0o600))
+	vmtest.SetupTestVM(t, vm, vmtest.TestVMConfig{
+		Fork:       &fork,
+		ConfigJSON: fmt.Sprintf(`{"admin-api-enabled": true, "rpc-auth-secret-file": %q}`, secretFile),
+	})
+	defer func() {
+		require.NoError(t, vm.Shutdown(ctx))
+	}()
+
+	handlers, err := vm.CreateHandlers(ctx)
+	require.NoError(t, err)
+	server := httptest.NewServer(handlers[adminEndpoint])
+	defer server.Close()
+
+	call := func(token string) int {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, server.URL, strings.NewReader(`{"jsonrpc":"2.0","id":1,"method":"admin.getVMConfig","params":{}}`))
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", "application/json")
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		require.NoError(t, resp.Body.Close())
+		return resp.StatusCode
+	}
+	require.Equal(t, http.StatusUnauthorized, call(""))
+
+	token, err := rpc.NewAuthToken(secret, rpc.AuthClaims{Namespaces: []string{"admin"}})
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, call(token))
+}
@@ -15,6 +15,7 @@ import (
 	"github.com/MetalBlockchain/libevm/libevm/stateconf"
 	"github.com/holiman/uint256"
 
+	"github.com/MetalBlockchain/coreth/plugin/evm/upgrade/acp176"
 	"github.com/MetalBlockchain/coreth/precompile/precompileconfig"
 
 	ethtypes "github.com/MetalBlockchain/libevm/core/types"
@@ -33,6 +34,7 @@ type StateDB interface {
 
 	SetNonce(common.Address, uint64)
 	GetNonce(common.Address) uint64
+	SetCode(common.Address, []byte)
 
 	GetBalance(common.Address) *uint256.Int
 	AddBalance(common.Address, *uint256.Int)
@@ -73,6 +75,9 @@ type BlockContext interface {
 	// GetPredicateResults returns the result of verifying the predicates of the
 	// given transaction, precompile address pair.
 	GetPredicateResults(txHash common.Hash, precompileAddress common.Address) set.Bits
+	// GetParentFeeState returns the ACP-176 fee state resulting from the
+	// execution of the parent block.
+	GetParentFeeState() (acp176.State, error)
 }
 
 type Configurator interface {
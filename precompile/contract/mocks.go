@@ -13,6 +13,7 @@ import (
 	big "math/big"
 	reflect "reflect"
 
+	acp176 "github.com/MetalBlockchain/coreth/plugin/evm/upgrade/acp176"
 	precompileconfig "github.com/MetalBlockchain/coreth/precompile/precompileconfig"
 	common "github.com/MetalBlockchain/libevm/common"
 	types "github.com/MetalBlockchain/libevm/core/types"
@@ -213,6 +214,18 @@ func (mr *MockStateDBMockRecorder) RevertToSnapshot(arg0 any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevertToSnapshot", reflect.TypeOf((*MockStateDB)(nil).RevertToSnapshot), arg0)
 }
 
+// SetCode mocks base method.
+func (m *MockStateDB) SetCode(arg0 common.Address, arg1 []byte) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetCode", arg0, arg1)
+}
+
+// SetCode indicates an expected call of SetCode.
+func (mr *MockStateDBMockRecorder) SetCode(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetCode", reflect.TypeOf((*MockStateDB)(nil).SetCode), arg0, arg1)
+}
+
 // SetNonce mocks base method.
 func (m *MockStateDB) SetNonce(arg0 common.Address, arg1 uint64) {
 	m.ctrl.T.Helper()
@@ -400,6 +413,21 @@ func (m *MockBlockContext) EXPECT() *MockBlockContextMockRecorder {
 	return m.recorder
 }
 
+// GetParentFeeState mocks base method.
+func (m *MockBlockContext) GetParentFeeState() (acp176.State, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetParentFeeState")
+	ret0, _ := ret[0].(acp176.State)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetParentFeeState indicates an expected call of GetParentFeeState.
+func (mr *MockBlockContextMockRecorder) GetParentFeeState() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetParentFeeState", reflect.TypeOf((*MockBlockContext)(nil).GetParentFeeState))
+}
+
 // GetPredicateResults mocks base method.
 func (m *MockBlockContext) GetPredicateResults(txHash common.Hash, precompileAddress common.Address) set.Bits {
 	m.ctrl.T.Helper()
@@ -0,0 +1,106 @@
+// Copyright (C) 2019-2025, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package assettoken
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/MetalBlockchain/metalgo/ids"
+	"github.com/MetalBlockchain/metalgo/utils/set"
+	"github.com/MetalBlockchain/libevm/common"
+
+	"github.com/MetalBlockchain/coreth/precompile/precompileconfig"
+)
+
+var _ precompileconfig.Config = (*Config)(nil)
+
+var (
+	errEmptyAssetID     = errors.New("asset ID cannot be empty")
+	errDuplicateAssetID = errors.New("duplicate asset ID")
+	errTokenCollision   = errors.New("asset IDs share a token address")
+	errMetadataTooLong  = errors.New("token metadata exceeds 32 bytes")
+)
+
+// AssetConfig specifies the token of a multicoin asset that is registered when
+// the precompile is configured.
+type AssetConfig struct {
+	AssetID  ids.ID `json:"assetID"`
+	Name     string `json:"name,omitempty"`
+	Symbol   string `json:"symbol,omitempty"`
+	Decimals uint8  `json:"decimals,omitempty"`
+}
+
+// Config implements the precompileconfig.Config interface and
+// adds specific configuration for the asset token precompile.
+type Config struct {
+	precompileconfig.Upgrade
+	// Assets are registered, and have their token metadata set, when the
+	// precompile is configured.
+	Assets []AssetConfig `json:"assets,omitempty"`
+}
+
+// NewConfig returns a config for a network upgrade at [blockTimestamp] that
+// enables the asset token precompile and registers [assets].
+func NewConfig(blockTimestamp *uint64, assets []AssetConfig) *Config {
+	return &Config{
+		Upgrade: precompileconfig.Upgrade{BlockTimestamp: blockTimestamp},
+		Assets:  assets,
+	}
+}
+
+// NewDisableConfig returns config for a network upgrade at [blockTimestamp]
+// that disables the asset token precompile.
+func NewDisableConfig(blockTimestamp *uint64) *Config {
+	return &Config{
+		Upgrade: precompileconfig.Upgrade{
+			BlockTimestamp: blockTimestamp,
+			Disable:        true,
+		},
+	}
+}
+
+// Key returns the key for the asset token precompileconfig.
+// This should be the same key as used in the precompile module.
+func (*Config) Key() string { return ConfigKey }
+
+// Verify tries to verify Config and returns an error accordingly.
+func (c *Config) Verify(precompileconfig.ChainConfig) error {
+	var (
+		assetIDs = set.NewSet[ids.ID](len(c.Assets))
+		tokens   = make(map[common.Address]ids.ID, len(c.Assets))
+	)
+	for _, asset := range c.Assets {
+		if asset.AssetID == ids.Empty {
+			return errEmptyAssetID
+		}
+		// Asset IDs sharing a normalized asset ID share their token.
+		normalized := ids.ID(NormalizeAssetID(common.Hash(asset.AssetID)))
+		if assetIDs.Contains(normalized) {
+			return fmt.Errorf("%w: %s", errDuplicateAssetID, asset.AssetID)
+		}
+		assetIDs.Add(normalized)
+		// Only 8 bytes of the token address are derived from the asset ID.
+		token := TokenAddress(common.Hash(asset.AssetID))
+		if other, ok := tokens[token]; ok {
+			return fmt.Errorf("%w: %s and %s", errTokenCollision, other, asset.AssetID)
+		}
+		tokens[token] = asset.AssetID
+		if len(asset.Name) > maxMetadataLen || len(asset.Symbol) > maxMetadataLen {
+			return fmt.Errorf("%w: %s", errMetadataTooLong, asset.AssetID)
+		}
+	}
+	return nil
+}
+
+// Equal returns true if [s] is a [*Config] and it has been configured identical to [c].
+func (c *Config) Equal(s precompileconfig.Config) bool {
+	// typecast before comparison
+	other, ok := (s).(*Config)
+	if !ok {
+		return false
+	}
+	return c.Upgrade.Equal(&other.Upgrade) && slices.Equal(c.Assets, other.Assets)
+}
@@ -0,0 +1,101 @@
+// Copyright (C) 2019-2025, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package assettoken
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/MetalBlockchain/metalgo/ids"
+	"go.uber.org/mock/gomock"
+
+	"github.com/MetalBlockchain/coreth/precompile/precompileconfig"
+	"github.com/MetalBlockchain/coreth/precompile/precompiletest"
+	"github.com/MetalBlockchain/coreth/utils"
+)
+
+func TestVerify(t *testing.T) {
+	assetID := ids.GenerateTestID()
+	siblingAssetID := assetID
+	siblingAssetID[0] ^= 0x01
+	tests := map[string]precompiletest.ConfigVerifyTest{
+		"valid config": {
+			Config: NewConfig(utils.NewUint64(3), nil),
+		},
+		"valid config with assets": {
+			Config: NewConfig(utils.NewUint64(3), []AssetConfig{
+				{AssetID: assetID, Name: "Token", Symbol: "TKN", Decimals: 9},
+				{AssetID: ids.GenerateTestID()},
+			}),
+		},
+		"invalid empty asset ID": {
+			Config:        NewConfig(utils.NewUint64(3), []AssetConfig{{Name: "Token"}}),
+			ExpectedError: errEmptyAssetID.Error(),
+		},
+		"invalid duplicate asset ID": {
+			Config: NewConfig(utils.NewUint64(3), []AssetConfig{
+				{AssetID: assetID, Name: "Token"},
+				{AssetID: assetID, Name: "Other"},
+			}),
+			ExpectedError: errDuplicateAssetID.Error(),
+		},
+		"invalid duplicate normalized asset ID": {
+			Config: NewConfig(utils.NewUint64(3), []AssetConfig{
+				{AssetID: assetID, Name: "Token"},
+				{AssetID: siblingAssetID, Name: "Other"},
+			}),
+			ExpectedError: errDuplicateAssetID.Error(),
+		},
+		"invalid name too long": {
+			Config: NewConfig(utils.NewUint64(3), []AssetConfig{
+				{AssetID: assetID, Name: strings.Repeat("a", maxMetadataLen+1)},
+			}),
+			ExpectedError: errMetadataTooLong.Error(),
+		},
+		"invalid symbol too long": {
+			Config: NewConfig(utils.NewUint64(3), []AssetConfig{
+				{AssetID: assetID, Symbol: strings.Repeat("a", maxMetadataLen+1)},
+			}),
+			ExpectedError: errMetadataTooLong.Error(),
+		},
+	}
+	precompiletest.RunVerifyTests(t, tests)
+}
+
+func TestEqual(t *testing.T) {
+	assets := []AssetConfig{{AssetID: ids.GenerateTestID(), Name: "Token", Symbol: "TKN", Decimals: 9}}
+	tests := map[string]precompiletest.ConfigEqualTest{
+		"non-nil config and nil other": {
+			Config:   NewConfig(utils.NewUint64(3), nil),
+			Other:    nil,
+			Expected: false,
+		},
+		"different type": {
+			Config:   NewConfig(utils.NewUint64(3), nil),
+			Other:    precompileconfig.NewMockConfig(gomock.NewController(t)),
+			Expected: false,
+		},
+		"different timestamp": {
+			Config:   NewConfig(utils.NewUint64(3), nil),
+			Other:    NewConfig(utils.NewUint64(4), nil),
+			Expected: false,
+		},
+		"different disable": {
+			Config:   NewConfig(utils.NewUint64(3), nil),
+			Other:    NewDisableConfig(utils.NewUint64(3)),
+			Expected: false,
+		},
+		"different assets": {
+			Config:   NewConfig(utils.NewUint64(3), assets),
+			Other:    NewConfig(utils.NewUint64(3), []AssetConfig{{AssetID: assets[0].AssetID}}),
+			Expected: false,
+		},
+		"same config": {
+			Config:   NewConfig(utils.NewUint64(3), assets),
+			Other:    NewConfig(utils.NewUint64(3), assets),
+			Expected: true,
+		},
+	}
+	precompiletest.RunEqualTests(t, tests)
+}
@@ -0,0 +1,530 @@
+// Copyright (C) 2019-2025, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package assettoken
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/MetalBlockchain/libevm/common"
+	"github.com/MetalBlockchain/libevm/common/math"
+	"github.com/MetalBlockchain/libevm/core/types"
+	"github.com/MetalBlockchain/libevm/core/vm"
+	"github.com/MetalBlockchain/libevm/crypto"
+
+	_ "embed"
+
+	"github.com/MetalBlockchain/coreth/accounts/abi"
+	"github.com/MetalBlockchain/coreth/precompile/contract"
+
+	ethparams "github.com/MetalBlockchain/libevm/params"
+)
+
+const (
+	// TokenAddressGasCost is the cost of hashing an asset ID into the address
+	// of its token.
+	TokenAddressGasCost uint64 = ethparams.Keccak256Gas + ethparams.Keccak256WordGas
+	// ReadAssetIDGasCost is the cost of reading the asset ID of a token.
+	ReadAssetIDGasCost uint64 = contract.ReadGasCostPerSlot
+	// RegisterGasCost covers hashing the asset ID into the address of its
+	// token and reading the asset ID of the token.
+	RegisterGasCost uint64 = TokenAddressGasCost + ReadAssetIDGasCost
+	// ReadTokenGasCost additionally covers reading a single slot of the token,
+	// such as its metadata, an allowance or a balance.
+	ReadTokenGasCost uint64 = ReadAssetIDGasCost + contract.ReadGasCostPerSlot
+	// ApproveGasCost covers reading the asset ID of the token, writing the
+	// allowance and emitting the Approval event.
+	ApproveGasCost uint64 = ReadAssetIDGasCost + contract.WriteGasCostPerSlot + contract.LogGas + 3*contract.LogTopicGas + common.HashLength*contract.LogDataGas
+	// TransferGasCost covers reading the asset ID of the token, reading and
+	// writing both balances, and emitting the Transfer event.
+	TransferGasCost uint64 = ReadAssetIDGasCost + 2*contract.ReadGasCostPerSlot + 2*contract.WriteGasCostPerSlot + contract.LogGas + 3*contract.LogTopicGas + common.HashLength*contract.LogDataGas
+	// TransferFromGasCost additionally covers reading and writing the
+	// allowance.
+	TransferFromGasCost uint64 = TransferGasCost + contract.ReadGasCostPerSlot + contract.WriteGasCostPerSlot
+
+	// maxMetadataLen is the maximum length of the name and symbol of a token,
+	// which are each stored in a single slot.
+	maxMetadataLen = common.HashLength
+)
+
+var (
+	errInvalidInput           = errors.New("invalid input")
+	errCannotRegisterAVAX     = errors.New("AVAX is not a multicoin asset")
+	errTokenAddressTaken      = errors.New("token address is registered to another asset")
+	errUnknownAsset           = errors.New("asset is not registered by the precompile config")
+	errUnregisteredToken      = errors.New("token is not registered")
+	errInsufficientBalance    = errors.New("transfer amount exceeds balance")
+	errInsufficientAllowance  = errors.New("transfer amount exceeds allowance")
+	errTotalSupplyUnsupported = errors.New("totalSupply is unsupported, as multicoin balances aren't summed")
+)
+
+// Storage slots of a token's account.
+var (
+	assetIDSlot  = common.Hash{}
+	nameSlot     = common.Hash{31: 1}
+	symbolSlot   = common.Hash{31: 2}
+	decimalsSlot = common.Hash{31: 3}
+)
+
+// tokenAddressPrefix prefixes the address of every token, so that the addresses
+// can be recognized without reading the state. It lies in the 0x02 precompile
+// address space, clear of the 0x0200...00ff range of precompile contracts, so
+// that finding a key or contract colliding with a token would take ~2^96
+// hashes. The remaining 8 bytes are taken from the hash of the asset ID, so a
+// second asset ID colliding with a given one can be found with ~2^64 hashes.
+// Tokens are therefore only registered for the assets of the precompile config,
+// which are checked not to collide, rather than for any asset ID.
+var tokenAddressPrefix = [12]byte{0x02, 11: 0x01}
+
+// Singleton StatefulPrecompiledContract and signatures.
+var (
+	// AssetTokenRawABI contains the raw ABI of the asset token registry.
+	//go:embed contract.abi
+	AssetTokenRawABI string
+
+	// TokenRawABI contains the raw ABI of the ERC-20 token of each asset.
+	//go:embed token.abi
+	TokenRawABI string
+
+	AssetTokenABI = contract.ParseABI(AssetTokenRawABI)
+	TokenABI      = contract.ParseABI(TokenRawABI)
+
+	AssetTokenPrecompile = createAssetTokenPrecompile()
+)
+
+// NormalizeAssetID returns the asset ID the multicoin balances of [assetID] are
+// stored under, which has the lowest bit of its first byte set. Asset IDs only
+// differing in this bit share their balances, and therefore their token.
+func NormalizeAssetID(assetID common.Hash) common.Hash {
+	assetID[0] |= 0x01
+	return assetID
+}
+
+// TokenAddress returns the address of the ERC-20 token of [assetID], which is
+// derived from its normalized asset ID (see [NormalizeAssetID]).
+func TokenAddress(assetID common.Hash) common.Address {
+	assetID = NormalizeAssetID(assetID)
+	var addr common.Address
+	n := copy(addr[:], tokenAddressPrefix[:])
+	copy(addr[n:], crypto.Keccak256(assetID[:]))
+	return addr
+}
+
+// IsTokenAddress returns true if [addr] is the address of the ERC-20 token of
+// an asset, whether or not the asset is registered.
+func IsTokenAddress(addr common.Address) bool {
+	return bytes.HasPrefix(addr[:], tokenAddressPrefix[:])
+}
+
+// registerAsset creates the token of [assetID] if it doesn't exist yet, which
+// stores the normalized asset ID, and returns its address. It fails if the
+// address of the token is taken by another asset.
+func registerAsset(stateDB contract.StateDB, assetID common.Hash) (common.Address, error) {
+	assetID = NormalizeAssetID(assetID)
+	token := TokenAddress(assetID)
+	switch stateDB.GetState(token, assetIDSlot) {
+	case assetID:
+		return token, nil
+	case common.Hash{}:
+	default:
+		return common.Address{}, fmt.Errorf("%w: %s", errTokenAddressTaken, token)
+	}
+
+	// As is done when activating a precompile, the nonce marks the token as
+	// non-empty and the code allows it to be called from Solidity contracts.
+	stateDB.SetNonce(token, 1)
+	stateDB.SetCode(token, []byte{0x1})
+	stateDB.SetState(token, assetIDSlot, assetID)
+	return token, nil
+}
+
+// setMetadata stores the name, symbol and decimals of [asset] in [token].
+func setMetadata(stateDB contract.StateDB, token common.Address, asset AssetConfig) {
+	var name, symbol common.Hash
+	copy(name[:], asset.Name)
+	copy(symbol[:], asset.Symbol)
+	stateDB.SetState(token, nameSlot, name)
+	stateDB.SetState(token, symbolSlot, symbol)
+	stateDB.SetState(token, decimalsSlot, common.Hash{31: asset.Decimals})
+}
+
+// allowanceSlot returns the slot of the allowance given by [owner] to [spender].
+func allowanceSlot(owner common.Address, spender common.Address) common.Hash {
+	return crypto.Keccak256Hash(owner[:], spender[:])
+}
+
+// addLog adds a log emitted by [addr] to the state.
+func addLog(accessibleState contract.AccessibleState, addr common.Address, topics []common.Hash, data []byte) {
+	accessibleState.GetStateDB().AddLog(&types.Log{
+		Address:     addr,
+		Topics:      topics,
+		Data:        data,
+		BlockNumber: accessibleState.GetBlockContext().Number().Uint64(),
+	})
+}
+
+// PackRegister packs [assetID] of type common.Hash into the appropriate arguments for register.
+// the packed bytes include selector (first 4 func signature bytes).
+// This function is mostly used for tests.
+func PackRegister(assetID common.Hash) ([]byte, error) {
+	return AssetTokenABI.Pack("register", assetID)
+}
+
+// UnpackRegisterInput attempts to unpack [input] into the common.Hash type argument
+// assumes that [input] does not include selector (omits first 4 func signature bytes)
+func UnpackRegisterInput(input []byte) (common.Hash, error) {
+	res, err := AssetTokenABI.UnpackInput("register", input, false)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	unpacked := *abi.ConvertType(res[0], new(common.Hash)).(*common.Hash)
+	return unpacked, nil
+}
+
+// PackRegisterOutput attempts to pack given [token] of type common.Address
+// to conform the ABI outputs.
+func PackRegisterOutput(token common.Address) ([]byte, error) {
+	return AssetTokenABI.PackOutput("register", token)
+}
+
+// register returns the address of the token of the given asset, which must
+// have been registered by the precompile config. Unknown asset IDs are
+// rejected, as an asset ID colliding with the token address of an asset that
+// isn't registered yet could otherwise take it over.
+func register(accessibleState contract.AccessibleState, _ common.Address, _ common.Address, input []byte, suppliedGas uint64, _ bool) (ret []byte, remainingGas uint64, err error) {
+	if remainingGas, err = contract.DeductGas(suppliedGas, RegisterGasCost); err != nil {
+		return nil, 0, err
+	}
+	assetID, err := UnpackRegisterInput(input)
+	if err != nil {
+		return nil, remainingGas, fmt.Errorf("%w: %w", errInvalidInput, err)
+	}
+	switch NormalizeAssetID(assetID) {
+	case NormalizeAssetID(common.Hash{}):
+		return nil, remainingGas, errEmptyAssetID
+	case NormalizeAssetID(common.Hash(accessibleState.GetSnowContext().AVAXAssetID)):
+		return nil, remainingGas, errCannotRegisterAVAX
+	}
+
+	token := TokenAddress(assetID)
+	if accessibleState.GetStateDB().GetState(token, assetIDSlot) != NormalizeAssetID(assetID) {
+		return nil, remainingGas, fmt.Errorf("%w: %s", errUnknownAsset, assetID)
+	}
+
+	packedOutput, err := PackRegisterOutput(token)
+	if err != nil {
+		return nil, remainingGas, err
+	}
+	return packedOutput, remainingGas, nil
+}
+
+// PackTokenAddress packs [assetID] of type common.Hash into the appropriate arguments for tokenAddress.
+// the packed bytes include selector (first 4 func signature bytes).
+// This function is mostly used for tests.
+func PackTokenAddress(assetID common.Hash) ([]byte, error) {
+	return AssetTokenABI.Pack("tokenAddress", assetID)
+}
+
+// tokenAddress returns the address of the token of the given asset, whether or
+// not the asset is registered.
+func tokenAddress(_ contract.AccessibleState, _ common.Address, _ common.Address, input []byte, suppliedGas uint64, _ bool) (ret []byte, remainingGas uint64, err error) {
+	if remainingGas, err = contract.DeductGas(suppliedGas, TokenAddressGasCost); err != nil {
+		return nil, 0, err
+	}
+	res, err := AssetTokenABI.UnpackInput("tokenAddress", input, false)
+	if err != nil {
+		return nil, remainingGas, fmt.Errorf("%w: %w", errInvalidInput, err)
+	}
+	assetID := *abi.ConvertType(res[0], new(common.Hash)).(*common.Hash)
+
+	packedOutput, err := AssetTokenABI.PackOutput("tokenAddress", TokenAddress(assetID))
+	if err != nil {
+		return nil, remainingGas, err
+	}
+	return packedOutput, remainingGas, nil
+}
+
+// PackAssetID packs [token] of type common.Address into the appropriate arguments for assetID.
+// the packed bytes include selector (first 4 func signature bytes).
+// This function is mostly used for tests.
+func PackAssetID(token common.Address) ([]byte, error) {
+	return AssetTokenABI.Pack("assetID", token)
+}
+
+// getAssetID returns the normalized asset ID of the given token, which is empty
+// if the token isn't registered.
+func getAssetID(accessibleState contract.AccessibleState, _ common.Address, _ common.Address, input []byte, suppliedGas uint64, _ bool) (ret []byte, remainingGas uint64, err error) {
+	if remainingGas, err = contract.DeductGas(suppliedGas, ReadAssetIDGasCost); err != nil {
+		return nil, 0, err
+	}
+	res, err := AssetTokenABI.UnpackInput("assetID", input, false)
+	if err != nil {
+		return nil, remainingGas, fmt.Errorf("%w: %w", errInvalidInput, err)
+	}
+	token := *abi.ConvertType(res[0], new(common.Address)).(*common.Address)
+
+	var id common.Hash
+	if IsTokenAddress(token) {
+		id = accessibleState.GetStateDB().GetState(token, assetIDSlot)
+	}
+	packedOutput, err := AssetTokenABI.PackOutput("assetID", id)
+	if err != nil {
+		return nil, remainingGas, err
+	}
+	return packedOutput, remainingGas, nil
+}
+
+// PackTransferEvent packs the given arguments into Transfer events including topics and data.
+func PackTransferEvent(from common.Address, to common.Address, value *big.Int) ([]common.Hash, []byte, error) {
+	return TokenABI.PackEvent("Transfer", from, to, value)
+}
+
+// PackApprovalEvent packs the given arguments into Approval events including topics and data.
+func PackApprovalEvent(owner common.Address, spender common.Address, value *big.Int) ([]common.Hash, []byte, error) {
+	return TokenABI.PackEvent("Approval", owner, spender, value)
+}
+
+// TransferInput is the input of the transfer function of a token.
+type TransferInput struct {
+	To    common.Address
+	Value *big.Int
+}
+
+// TransferFromInput is the input of the transferFrom function of a token.
+type TransferFromInput struct {
+	From  common.Address
+	To    common.Address
+	Value *big.Int
+}
+
+// ApproveInput is the input of the approve function of a token.
+type ApproveInput struct {
+	Spender common.Address
+	Value   *big.Int
+}
+
+// AllowanceInput is the input of the allowance function of a token.
+type AllowanceInput struct {
+	Owner   common.Address
+	Spender common.Address
+}
+
+// handleToken charges [gasCost], unpacks [input] into [args], if non-nil, and
+// returns the output produced by [run] with the asset ID of [token].
+func handleToken(
+	accessibleState contract.AccessibleState,
+	token common.Address,
+	name string,
+	input []byte,
+	args any,
+	suppliedGas uint64,
+	gasCost uint64,
+	run func(stateDB contract.StateDB, assetID common.Hash) ([]any, error),
+) ([]byte, uint64, error) {
+	remainingGas, err := contract.DeductGas(suppliedGas, gasCost)
+	if err != nil {
+		return nil, 0, err
+	}
+	if args != nil {
+		if err := TokenABI.UnpackInputIntoInterface(args, name, input, false); err != nil {
+			return nil, remainingGas, fmt.Errorf("%w: %w", errInvalidInput, err)
+		}
+	}
+	stateDB := accessibleState.GetStateDB()
+	assetID := stateDB.GetState(token, assetIDSlot)
+	if assetID == (common.Hash{}) {
+		return nil, remainingGas, fmt.Errorf("%w: %s", errUnregisteredToken, token)
+	}
+	outputs, err := run(stateDB, assetID)
+	if err != nil {
+		return nil, remainingGas, err
+	}
+	packedOutput, err := TokenABI.PackOutput(name, outputs...)
+	if err != nil {
+		return nil, remainingGas, err
+	}
+	return packedOutput, remainingGas, nil
+}
+
+// transferAsset moves [value] of [assetID] from [from] to [to] and emits the
+// Transfer event of [token].
+func transferAsset(accessibleState contract.AccessibleState, token common.Address, assetID common.Hash, from common.Address, to common.Address, value *big.Int) error {
+	stateDB := accessibleState.GetStateDB()
+	if stateDB.GetBalanceMultiCoin(from, assetID).Cmp(value) < 0 {
+		return errInsufficientBalance
+	}
+	stateDB.SubBalanceMultiCoin(from, assetID, value)
+	stateDB.AddBalanceMultiCoin(to, assetID, value)
+
+	topics, data, err := PackTransferEvent(from, to, value)
+	if err != nil {
+		return err
+	}
+	addLog(accessibleState, token, topics, data)
+	return nil
+}
+
+// readString returns the string stored left-aligned in [slot] of [token].
+func readString(stateDB contract.StateDB, token common.Address, slot common.Hash) string {
+	value := stateDB.GetState(token, slot)
+	return string(bytes.TrimRight(value[:], "\x00"))
+}
+
+func tokenName(accessibleState contract.AccessibleState, _ common.Address, token common.Address, input []byte, suppliedGas uint64, _ bool) (ret []byte, remainingGas uint64, err error) {
+	return handleToken(accessibleState, token, "name", input, nil, suppliedGas, ReadTokenGasCost, func(stateDB contract.StateDB, _ common.Hash) ([]any, error) {
+		return []any{readString(stateDB, token, nameSlot)}, nil
+	})
+}
+
+func tokenSymbol(accessibleState contract.AccessibleState, _ common.Address, token common.Address, input []byte, suppliedGas uint64, _ bool) (ret []byte, remainingGas uint64, err error) {
+	return handleToken(accessibleState, token, "symbol", input, nil, suppliedGas, ReadTokenGasCost, func(stateDB contract.StateDB, _ common.Hash) ([]any, error) {
+		return []any{readString(stateDB, token, symbolSlot)}, nil
+	})
+}
+
+func tokenDecimals(accessibleState contract.AccessibleState, _ common.Address, token common.Address, input []byte, suppliedGas uint64, _ bool) (ret []byte, remainingGas uint64, err error) {
+	return handleToken(accessibleState, token, "decimals", input, nil, suppliedGas, ReadTokenGasCost, func(stateDB contract.StateDB, _ common.Hash) ([]any, error) {
+		return []any{stateDB.GetState(token, decimalsSlot)[31]}, nil
+	})
+}
+
+// totalSupply reverts, as the balances of multicoin assets aren't summed.
+func totalSupply(accessibleState contract.AccessibleState, _ common.Address, token common.Address, input []byte, suppliedGas uint64, _ bool) (ret []byte, remainingGas uint64, err error) {
+	return handleToken(accessibleState, token, "totalSupply", input, nil, suppliedGas, ReadAssetIDGasCost, func(contract.StateDB, common.Hash) ([]any, error) {
+		return nil, errTotalSupplyUnsupported
+	})
+}
+
+func tokenAssetID(accessibleState contract.AccessibleState, _ common.Address, token common.Address, input []byte, suppliedGas uint64, _ bool) (ret []byte, remainingGas uint64, err error) {
+	return handleToken(accessibleState, token, "assetID", input, nil, suppliedGas, ReadAssetIDGasCost, func(_ contract.StateDB, assetID common.Hash) ([]any, error) {
+		return []any{assetID}, nil
+	})
+}
+
+// balanceOf returns the multicoin balance of the given account.
+func balanceOf(accessibleState contract.AccessibleState, _ common.Address, token common.Address, input []byte, suppliedGas uint64, _ bool) (ret []byte, remainingGas uint64, err error) {
+	var account common.Address
+	return handleToken(accessibleState, token, "balanceOf", input, &account, suppliedGas, ReadTokenGasCost, func(stateDB contract.StateDB, assetID common.Hash) ([]any, error) {
+		return []any{stateDB.GetBalanceMultiCoin(account, assetID)}, nil
+	})
+}
+
+func allowance(accessibleState contract.AccessibleState, _ common.Address, token common.Address, input []byte, suppliedGas uint64, _ bool) (ret []byte, remainingGas uint64, err error) {
+	var args AllowanceInput
+	return handleToken(accessibleState, token, "allowance", input, &args, suppliedGas, ReadTokenGasCost, func(stateDB contract.StateDB, _ common.Hash) ([]any, error) {
+		return []any{stateDB.GetState(token, allowanceSlot(args.Owner, args.Spender)).Big()}, nil
+	})
+}
+
+func approve(accessibleState contract.AccessibleState, caller common.Address, token common.Address, input []byte, suppliedGas uint64, readOnly bool) (ret []byte, remainingGas uint64, err error) {
+	var args ApproveInput
+	return handleToken(accessibleState, token, "approve", input, &args, suppliedGas, ApproveGasCost, func(stateDB contract.StateDB, _ common.Hash) ([]any, error) {
+		if readOnly {
+			return nil, vm.ErrWriteProtection
+		}
+		stateDB.SetState(token, allowanceSlot(caller, args.Spender), common.BigToHash(args.Value))
+
+		topics, data, err := PackApprovalEvent(caller, args.Spender, args.Value)
+		if err != nil {
+			return nil, err
+		}
+		addLog(accessibleState, token, topics, data)
+		return []any{true}, nil
+	})
+}
+
+// transfer moves multicoin balance from the caller to the given account.
+func transfer(accessibleState contract.AccessibleState, caller common.Address, token common.Address, input []byte, suppliedGas uint64, readOnly bool) (ret []byte, remainingGas uint64, err error) {
+	var args TransferInput
+	return handleToken(accessibleState, token, "transfer", input, &args, suppliedGas, TransferGasCost, func(_ contract.StateDB, assetID common.Hash) ([]any, error) {
+		if readOnly {
+			return nil, vm.ErrWriteProtection
+		}
+		if err := transferAsset(accessibleState, token, assetID, caller, args.To, args.Value); err != nil {
+			return nil, err
+		}
+		return []any{true}, nil
+	})
+}
+
+// transferFrom moves multicoin balance between the given accounts, spending
+// the allowance given to the caller. An allowance of the maximum uint256 value
+// is never spent.
+func transferFrom(accessibleState contract.AccessibleState, caller common.Address, token common.Address, input []byte, suppliedGas uint64, readOnly bool) (ret []byte, remainingGas uint64, err error) {
+	var args TransferFromInput
+	return handleToken(accessibleState, token, "transferFrom", input, &args, suppliedGas, TransferFromGasCost, func(stateDB contract.StateDB, assetID common.Hash) ([]any, error) {
+		if readOnly {
+			return nil, vm.ErrWriteProtection
+		}
+		slot := allowanceSlot(args.From, caller)
+		allowed := stateDB.GetState(token, slot).Big()
+		if allowed.Cmp(args.Value) < 0 {
+			return nil, errInsufficientAllowance
+		}
+		if allowed.Cmp(math.MaxBig256) != 0 {
+			stateDB.SetState(token, slot, common.BigToHash(allowed.Sub(allowed, args.Value)))
+		}
+		if err := transferAsset(accessibleState, token, assetID, args.From, args.To, args.Value); err != nil {
+			return nil, err
+		}
+		return []any{true}, nil
+	})
+}
+
+// assetTokenPrecompile runs the registry at [ContractAddress] and the ERC-20
+// token of an asset at any other address.
+type assetTokenPrecompile struct {
+	registry contract.StatefulPrecompiledContract
+	token    contract.StatefulPrecompiledContract
+}
+
+func (p *assetTokenPrecompile) Run(accessibleState contract.AccessibleState, caller common.Address, addr common.Address, input []byte, suppliedGas uint64, readOnly bool) (ret []byte, remainingGas uint64, err error) {
+	if addr == ContractAddress {
+		return p.registry.Run(accessibleState, caller, addr, input, suppliedGas, readOnly)
+	}
+	return p.token.Run(accessibleState, caller, addr, input, suppliedGas, readOnly)
+}
+
+// createAssetTokenPrecompile returns a StatefulPrecompiledContract with the
+// functions of both the registry and the tokens.
+func createAssetTokenPrecompile() contract.StatefulPrecompiledContract {
+	return &assetTokenPrecompile{
+		registry: newStatefulPrecompile(AssetTokenABI, map[string]contract.RunStatefulPrecompileFunc{
+			"assetID":      getAssetID,
+			"register":     register,
+			"tokenAddress": tokenAddress,
+		}),
+		token: newStatefulPrecompile(TokenABI, map[string]contract.RunStatefulPrecompileFunc{
+			"allowance":    allowance,
+			"approve":      approve,
+			"assetID":      tokenAssetID,
+			"balanceOf":    balanceOf,
+			"decimals":     tokenDecimals,
+			"name":         tokenName,
+			"symbol":       tokenSymbol,
+			"totalSupply":  totalSupply,
+			"transfer":     transfer,
+			"transferFrom": transferFrom,
+		}),
+	}
+}
+
+func newStatefulPrecompile(contractABI abi.ABI, abiFunctionMap map[string]contract.RunStatefulPrecompileFunc) contract.StatefulPrecompiledContract {
+	var functions []*contract.StatefulPrecompileFunction
+	for methodName, function := range abiFunctionMap {
+		method, ok := contractABI.Methods[methodName]
+		if !ok {
+			panic(fmt.Errorf("given method (%s) does not exist in the ABI", methodName))
+		}
+		functions = append(functions, contract.NewStatefulPrecompileFunction(method.ID, function))
+	}
+	// Construct the contract with no fallback function.
+	statefulContract, err := contract.NewStatefulPrecompileContract(nil, functions)
+	if err != nil {
+		panic(err)
+	}
+	return statefulContract
+}
@@ -0,0 +1,365 @@
+// Copyright (C) 2019-2025, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package assettoken
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/MetalBlockchain/metalgo/ids"
+	"github.com/MetalBlockchain/metalgo/snow/snowtest"
+	"github.com/MetalBlockchain/libevm/common"
+	"github.com/MetalBlockchain/libevm/common/math"
+	"github.com/MetalBlockchain/libevm/core/vm"
+	"github.com/stretchr/testify/require"
+
+	"github.com/MetalBlockchain/coreth/precompile/contract"
+	"github.com/MetalBlockchain/coreth/precompile/modules"
+	"github.com/MetalBlockchain/coreth/precompile/precompiletest"
+	"github.com/MetalBlockchain/coreth/utils"
+)
+
+func TestTokenAddress(t *testing.T) {
+	require := require.New(t)
+	assetID := common.Hash(ids.GenerateTestID())
+
+	token := TokenAddress(assetID)
+	require.Equal(token, TokenAddress(assetID))
+	require.True(IsTokenAddress(token))
+	require.NotEqual(token, TokenAddress(common.Hash(ids.GenerateTestID())))
+
+	sibling := assetID
+	sibling[0] ^= 0x01
+	require.Equal(token, TokenAddress(sibling))
+	require.Equal(NormalizeAssetID(assetID), NormalizeAssetID(sibling))
+	require.False(IsTokenAddress(ContractAddress))
+	require.False(IsTokenAddress(common.Address{}))
+	require.False(IsTokenAddress(common.HexToAddress("0x02000000000000000000000000000000000000ff")))
+	require.False(modules.ReservedAddress(token))
+}
+
+func TestRegistry(t *testing.T) {
+	callerAddr := common.HexToAddress("0x0123")
+	assetID := NormalizeAssetID(common.Hash(ids.GenerateTestID()))
+	unnormalizedAssetID := assetID
+	unnormalizedAssetID[0] &^= 0x01
+	token := TokenAddress(assetID)
+	config := NewConfig(utils.NewUint64(0), []AssetConfig{{AssetID: ids.ID(assetID)}})
+	mustPack := func(output []byte, err error) []byte {
+		require.NoError(t, err)
+		return output
+	}
+
+	tests := map[string]precompiletest.PrecompileTest{
+		"register success": {
+			Caller:      callerAddr,
+			Input:       mustPack(PackRegister(assetID)),
+			SuppliedGas: RegisterGasCost,
+			Config:      config,
+			ExpectedRes: mustPack(PackRegisterOutput(token)),
+			AfterHook: func(t testing.TB, state contract.StateDB) {
+				require.Equal(t, assetID, state.GetState(token, assetIDSlot))
+				require.Equal(t, uint64(1), state.GetNonce(token))
+				require.Empty(t, state.Logs())
+			},
+		},
+		"register unnormalized asset ID": {
+			Caller:      callerAddr,
+			Input:       mustPack(PackRegister(unnormalizedAssetID)),
+			SuppliedGas: RegisterGasCost,
+			Config:      config,
+			ExpectedRes: mustPack(PackRegisterOutput(token)),
+		},
+		"register unknown asset ID": {
+			Caller:      callerAddr,
+			Input:       mustPack(PackRegister(assetID)),
+			SuppliedGas: RegisterGasCost,
+			ExpectedErr: errUnknownAsset.Error(),
+			AfterHook: func(t testing.TB, state contract.StateDB) {
+				require.Equal(t, common.Hash{}, state.GetState(token, assetIDSlot))
+			},
+		},
+		"register token address taken": {
+			Caller:      callerAddr,
+			Input:       mustPack(PackRegister(assetID)),
+			SuppliedGas: RegisterGasCost,
+			BeforeHook: func(_ testing.TB, state contract.StateDB) {
+				state.SetState(token, assetIDSlot, common.Hash{0x03})
+			},
+			ExpectedErr: errUnknownAsset.Error(),
+		},
+		"register AVAX": {
+			Caller:      callerAddr,
+			Input:       mustPack(PackRegister(common.Hash(snowtest.AVAXAssetID))),
+			SuppliedGas: RegisterGasCost,
+			ExpectedErr: errCannotRegisterAVAX.Error(),
+		},
+		"register empty asset ID": {
+			Caller:      callerAddr,
+			Input:       mustPack(PackRegister(common.Hash{})),
+			SuppliedGas: RegisterGasCost,
+			ExpectedErr: errEmptyAssetID.Error(),
+		},
+		"register read only": {
+			Caller:      callerAddr,
+			Input:       mustPack(PackRegister(assetID)),
+			SuppliedGas: RegisterGasCost,
+			Config:      config,
+			ReadOnly:    true,
+			ExpectedRes: mustPack(PackRegisterOutput(token)),
+		},
+		"register insufficient gas": {
+			Caller:      callerAddr,
+			Input:       mustPack(PackRegister(assetID)),
+			SuppliedGas: RegisterGasCost - 1,
+			ExpectedErr: vm.ErrOutOfGas.Error(),
+		},
+		"tokenAddress success": {
+			Caller:      callerAddr,
+			Input:       mustPack(PackTokenAddress(assetID)),
+			SuppliedGas: TokenAddressGasCost,
+			ReadOnly:    true,
+			ExpectedRes: mustPack(AssetTokenABI.PackOutput("tokenAddress", token)),
+		},
+		"assetID registered": {
+			Caller:      callerAddr,
+			Input:       mustPack(PackAssetID(token)),
+			SuppliedGas: ReadAssetIDGasCost,
+			ReadOnly:    true,
+			Config:      config,
+			ExpectedRes: mustPack(AssetTokenABI.PackOutput("assetID", assetID)),
+		},
+		"assetID unregistered": {
+			Caller:      callerAddr,
+			Input:       mustPack(PackAssetID(token)),
+			SuppliedGas: ReadAssetIDGasCost,
+			ReadOnly:    true,
+			ExpectedRes: mustPack(AssetTokenABI.PackOutput("assetID", common.Hash{})),
+		},
+		"assetID not a token": {
+			Caller:      callerAddr,
+			Input:       mustPack(PackAssetID(callerAddr)),
+			SuppliedGas: ReadAssetIDGasCost,
+			ReadOnly:    true,
+			Config:      config,
+			ExpectedRes: mustPack(AssetTokenABI.PackOutput("assetID", common.Hash{})),
+		},
+	}
+
+	precompiletest.RunPrecompileTests(t, Module, tests)
+}
+
+func TestToken(t *testing.T) {
+	var (
+		callerAddr = common.HexToAddress("0x0123")
+		ownerAddr  = common.HexToAddress("0x0456")
+		toAddr     = common.HexToAddress("0x0789")
+		assetID    = NormalizeAssetID(common.Hash(ids.GenerateTestID()))
+		token      = TokenAddress(assetID)
+		config     = NewConfig(utils.NewUint64(0), []AssetConfig{{
+			AssetID:  ids.ID(assetID),
+			Name:     "Token",
+			Symbol:   "TKN",
+			Decimals: 9,
+		}})
+	)
+	mustPack := func(output []byte, err error) []byte {
+		require.NoError(t, err)
+		return output
+	}
+	withBalance := func(addr common.Address, balance int64) func(testing.TB, contract.StateDB) {
+		return func(_ testing.TB, state contract.StateDB) {
+			state.AddBalanceMultiCoin(addr, assetID, big.NewInt(balance))
+		}
+	}
+	requireBalance := func(t testing.TB, state contract.StateDB, addr common.Address, balance int64) {
+		require.Equal(t, big.NewInt(balance), state.GetBalanceMultiCoin(addr, assetID))
+	}
+	requireTransferLog := func(t testing.TB, state contract.StateDB, from common.Address, value int64) {
+		logs := state.Logs()
+		require.Len(t, logs, 1)
+		topics, data, err := PackTransferEvent(from, toAddr, big.NewInt(value))
+		require.NoError(t, err)
+		require.Equal(t, token, logs[0].Address)
+		require.Equal(t, topics, logs[0].Topics)
+		require.Equal(t, data, logs[0].Data)
+	}
+
+	tests := map[string]precompiletest.PrecompileTest{
+		"name": {
+			Caller:          callerAddr,
+			ContractAddress: token,
+			Input:           mustPack(TokenABI.Pack("name")),
+			SuppliedGas:     ReadTokenGasCost,
+			ReadOnly:        true,
+			Config:          config,
+			ExpectedRes:     mustPack(TokenABI.PackOutput("name", "Token")),
+		},
+		"symbol": {
+			Caller:          callerAddr,
+			ContractAddress: token,
+			Input:           mustPack(TokenABI.Pack("symbol")),
+			SuppliedGas:     ReadTokenGasCost,
+			ReadOnly:        true,
+			Config:          config,
+			ExpectedRes:     mustPack(TokenABI.PackOutput("symbol", "TKN")),
+		},
+		"decimals": {
+			Caller:          callerAddr,
+			ContractAddress: token,
+			Input:           mustPack(TokenABI.Pack("decimals")),
+			SuppliedGas:     ReadTokenGasCost,
+			ReadOnly:        true,
+			Config:          config,
+			ExpectedRes:     mustPack(TokenABI.PackOutput("decimals", uint8(9))),
+		},
+		"assetID": {
+			Caller:          callerAddr,
+			ContractAddress: token,
+			Input:           mustPack(TokenABI.Pack("assetID")),
+			SuppliedGas:     ReadAssetIDGasCost,
+			ReadOnly:        true,
+			Config:          config,
+			ExpectedRes:     mustPack(TokenABI.PackOutput("assetID", assetID)),
+		},
+		"totalSupply": {
+			Caller:          callerAddr,
+			ContractAddress: token,
+			Input:           mustPack(TokenABI.Pack("totalSupply")),
+			SuppliedGas:     ReadAssetIDGasCost,
+			ReadOnly:        true,
+			Config:          config,
+			ExpectedErr:     errTotalSupplyUnsupported.Error(),
+		},
+		"balanceOf": {
+			Caller:          callerAddr,
+			ContractAddress: token,
+			Input:           mustPack(TokenABI.Pack("balanceOf", ownerAddr)),
+			SuppliedGas:     ReadTokenGasCost,
+			ReadOnly:        true,
+			Config:          config,
+			BeforeHook:      withBalance(ownerAddr, 100),
+			ExpectedRes:     mustPack(TokenABI.PackOutput("balanceOf", big.NewInt(100))),
+		},
+		"balanceOf unregistered": {
+			Caller:          callerAddr,
+			ContractAddress: TokenAddress(common.Hash(ids.GenerateTestID())),
+			Input:           mustPack(TokenABI.Pack("balanceOf", ownerAddr)),
+			SuppliedGas:     ReadTokenGasCost,
+			ReadOnly:        true,
+			Config:          config,
+			ExpectedErr:     errUnregisteredToken.Error(),
+		},
+		"transfer success": {
+			Caller:          callerAddr,
+			ContractAddress: token,
+			Input:           mustPack(TokenABI.Pack("transfer", toAddr, big.NewInt(60))),
+			SuppliedGas:     TransferGasCost,
+			Config:          config,
+			BeforeHook:      withBalance(callerAddr, 100),
+			ExpectedRes:     mustPack(TokenABI.PackOutput("transfer", true)),
+			AfterHook: func(t testing.TB, state contract.StateDB) {
+				requireBalance(t, state, callerAddr, 40)
+				requireBalance(t, state, toAddr, 60)
+				requireTransferLog(t, state, callerAddr, 60)
+			},
+		},
+		"transfer insufficient balance": {
+			Caller:          callerAddr,
+			ContractAddress: token,
+			Input:           mustPack(TokenABI.Pack("transfer", toAddr, big.NewInt(101))),
+			SuppliedGas:     TransferGasCost,
+			Config:          config,
+			BeforeHook:      withBalance(callerAddr, 100),
+			ExpectedErr:     errInsufficientBalance.Error(),
+		},
+		"transfer read only": {
+			Caller:          callerAddr,
+			ContractAddress: token,
+			Input:           mustPack(TokenABI.Pack("transfer", toAddr, big.NewInt(60))),
+			SuppliedGas:     TransferGasCost,
+			ReadOnly:        true,
+			Config:          config,
+			BeforeHook:      withBalance(callerAddr, 100),
+			ExpectedErr:     vm.ErrWriteProtection.Error(),
+		},
+		"transfer insufficient gas": {
+			Caller:          callerAddr,
+			ContractAddress: token,
+			Input:           mustPack(TokenABI.Pack("transfer", toAddr, big.NewInt(60))),
+			SuppliedGas:     TransferGasCost - 1,
+			Config:          config,
+			ExpectedErr:     vm.ErrOutOfGas.Error(),
+		},
+		"approve": {
+			Caller:          ownerAddr,
+			ContractAddress: token,
+			Input:           mustPack(TokenABI.Pack("approve", callerAddr, big.NewInt(60))),
+			SuppliedGas:     ApproveGasCost,
+			Config:          config,
+			ExpectedRes:     mustPack(TokenABI.PackOutput("approve", true)),
+			AfterHook: func(t testing.TB, state contract.StateDB) {
+				require.Equal(t, common.BigToHash(big.NewInt(60)), state.GetState(token, allowanceSlot(ownerAddr, callerAddr)))
+				require.Len(t, state.Logs(), 1)
+			},
+		},
+		"allowance": {
+			Caller:          callerAddr,
+			ContractAddress: token,
+			Input:           mustPack(TokenABI.Pack("allowance", ownerAddr, callerAddr)),
+			SuppliedGas:     ReadTokenGasCost,
+			ReadOnly:        true,
+			Config:          config,
+			BeforeHook: func(_ testing.TB, state contract.StateDB) {
+				state.SetState(token, allowanceSlot(ownerAddr, callerAddr), common.BigToHash(big.NewInt(60)))
+			},
+			ExpectedRes: mustPack(TokenABI.PackOutput("allowance", big.NewInt(60))),
+		},
+		"transferFrom success": {
+			Caller:          callerAddr,
+			ContractAddress: token,
+			Input:           mustPack(TokenABI.Pack("transferFrom", ownerAddr, toAddr, big.NewInt(60))),
+			SuppliedGas:     TransferFromGasCost,
+			Config:          config,
+			BeforeHook: func(t testing.TB, state contract.StateDB) {
+				withBalance(ownerAddr, 100)(t, state)
+				state.SetState(token, allowanceSlot(ownerAddr, callerAddr), common.BigToHash(big.NewInt(100)))
+			},
+			ExpectedRes: mustPack(TokenABI.PackOutput("transferFrom", true)),
+			AfterHook: func(t testing.TB, state contract.StateDB) {
+				requireBalance(t, state, ownerAddr, 40)
+				requireBalance(t, state, toAddr, 60)
+				requireTransferLog(t, state, ownerAddr, 60)
+				require.Equal(t, common.BigToHash(big.NewInt(40)), state.GetState(token, allowanceSlot(ownerAddr, callerAddr)))
+			},
+		},
+		"transferFrom unlimited allowance": {
+			Caller:          callerAddr,
+			ContractAddress: token,
+			Input:           mustPack(TokenABI.Pack("transferFrom", ownerAddr, toAddr, big.NewInt(60))),
+			SuppliedGas:     TransferFromGasCost,
+			Config:          config,
+			BeforeHook: func(t testing.TB, state contract.StateDB) {
+				withBalance(ownerAddr, 100)(t, state)
+				state.SetState(token, allowanceSlot(ownerAddr, callerAddr), common.BigToHash(math.MaxBig256))
+			},
+			ExpectedRes: mustPack(TokenABI.PackOutput("transferFrom", true)),
+			AfterHook: func(t testing.TB, state contract.StateDB) {
+				requireBalance(t, state, ownerAddr, 40)
+				require.Equal(t, common.BigToHash(math.MaxBig256), state.GetState(token, allowanceSlot(ownerAddr, callerAddr)))
+			},
+		},
+		"transferFrom insufficient allowance": {
+			Caller:          callerAddr,
+			ContractAddress: token,
+			Input:           mustPack(TokenABI.Pack("transferFrom", ownerAddr, toAddr, big.NewInt(60))),
+			SuppliedGas:     TransferFromGasCost,
+			Config:          config,
+			BeforeHook:      withBalance(ownerAddr, 100),
+			ExpectedErr:     errInsufficientAllowance.Error(),
+		},
+	}
+
+	precompiletest.RunPrecompileTests(t, Module, tests)
+}
@@ -0,0 +1,65 @@
+// Copyright (C) 2019-2025, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package assettoken
+
+import (
+	"fmt"
+
+	"github.com/MetalBlockchain/libevm/common"
+
+	"github.com/MetalBlockchain/coreth/precompile/contract"
+	"github.com/MetalBlockchain/coreth/precompile/modules"
+	"github.com/MetalBlockchain/coreth/precompile/precompileconfig"
+)
+
+var _ contract.Configurator = (*configurator)(nil)
+
+// ConfigKey is the key used in json config files to specify this precompile config.
+// must be unique across all precompiles.
+const ConfigKey = "assetTokenConfig"
+
+// ContractAddress is the address of the asset token registry precompile contract
+var ContractAddress = common.HexToAddress("0x0200000000000000000000000000000000000007")
+
+// Module is the precompile module. It is used to register the precompile contract.
+var Module = modules.Module{
+	ConfigKey:            ConfigKey,
+	Address:              ContractAddress,
+	Contract:             AssetTokenPrecompile,
+	Configurator:         &configurator{},
+	DerivedAddressPrefix: tokenAddressPrefix[:],
+}
+
+type configurator struct{}
+
+func init() {
+	// Register the precompile module.
+	// Each precompile contract registers itself through [RegisterModule] function.
+	if err := modules.RegisterModule(Module); err != nil {
+		panic(err)
+	}
+}
+
+// MakeConfig returns a new precompile config instance.
+// This is required to Marshal/Unmarshal the precompile config.
+func (*configurator) MakeConfig() precompileconfig.Config {
+	return new(Config)
+}
+
+// Configure registers the tokens of the configured assets and sets their
+// metadata.
+func (*configurator) Configure(_ precompileconfig.ChainConfig, cfg precompileconfig.Config, state contract.StateDB, _ contract.ConfigurationBlockContext) error {
+	config, ok := cfg.(*Config)
+	if !ok {
+		return fmt.Errorf("expected config type %T, got %T: %v", &Config{}, cfg, cfg)
+	}
+	for _, asset := range config.Assets {
+		token, err := registerAsset(state, common.Hash(asset.AssetID))
+		if err != nil {
+			return err
+		}
+		setMetadata(state, token, asset)
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+// Copyright (C) 2019-2025, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package feestate
+
+import (
+	"errors"
+
+	"github.com/MetalBlockchain/coreth/precompile/precompileconfig"
+)
+
+var _ precompileconfig.Config = (*Config)(nil)
+
+var errFeeStateCannotBeActivated = errors.New("fee state precompile cannot be activated before Fortuna")
+
+// Config implements the precompileconfig.Config interface and
+// adds specific configuration for the fee state precompile.
+type Config struct {
+	precompileconfig.Upgrade
+}
+
+// NewConfig returns a config for a network upgrade at [blockTimestamp] that
+// enables the fee state precompile.
+func NewConfig(blockTimestamp *uint64) *Config {
+	return &Config{
+		Upgrade: precompileconfig.Upgrade{BlockTimestamp: blockTimestamp},
+	}
+}
+
+// NewDisableConfig returns config for a network upgrade at [blockTimestamp]
+// that disables the fee state precompile.
+func NewDisableConfig(blockTimestamp *uint64) *Config {
+	return &Config{
+		Upgrade: precompileconfig.Upgrade{
+			BlockTimestamp: blockTimestamp,
+			Disable:        true,
+		},
+	}
+}
+
+// Key returns the key for the fee state precompileconfig.
+// This should be the same key as used in the precompile module.
+func (*Config) Key() string { return ConfigKey }
+
+// Verify tries to verify Config and returns an error accordingly.
+func (c *Config) Verify(chainConfig precompileconfig.ChainConfig) error {
+	// The ACP-176 fee state is only included in blocks after Fortuna.
+	if timestamp := c.Timestamp(); timestamp != nil && !chainConfig.IsFortuna(*timestamp) {
+		return errFeeStateCannotBeActivated
+	}
+	return nil
+}
+
+// Equal returns true if [s] is a [*Config] and it has been configured identical to [c].
+func (c *Config) Equal(s precompileconfig.Config) bool {
+	// typecast before comparison
+	other, ok := (s).(*Config)
+	if !ok {
+		return false
+	}
+	return c.Upgrade.Equal(&other.Upgrade)
+}
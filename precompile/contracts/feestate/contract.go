@@ -0,0 +1,195 @@
+// Copyright (C) 2019-2025, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package feestate
+
+import (
+	"fmt"
+
+	"github.com/MetalBlockchain/libevm/common"
+
+	_ "embed"
+
+	"github.com/MetalBlockchain/coreth/plugin/evm/upgrade/acp176"
+	"github.com/MetalBlockchain/coreth/precompile/contract"
+)
+
+const (
+	// GetFeeStateGasCost is the cost of reading the parent fee state, which is
+	// provided by the block context rather than read from the state trie.
+	GetFeeStateGasCost uint64 = 100 // Based on WarmStorageReadCostEIP2929
+	// GetTargetGasCost additionally covers calculating the target from the
+	// target excess.
+	GetTargetGasCost uint64 = GetFeeStateGasCost + 100
+	// GetMaxCapacityGasCost additionally covers calculating the target and
+	// scaling it to the maximum capacity.
+	GetMaxCapacityGasCost uint64 = GetTargetGasCost + 5
+	// GetGasPriceGasCost additionally covers calculating the target and the
+	// gas price from the excess.
+	GetGasPriceGasCost uint64 = GetTargetGasCost + 100
+)
+
+// Singleton StatefulPrecompiledContract and signatures.
+var (
+	// FeeStateRawABI contains the raw ABI of the fee state contract.
+	//go:embed contract.abi
+	FeeStateRawABI string
+
+	FeeStateABI = contract.ParseABI(FeeStateRawABI)
+
+	FeeStatePrecompile = createFeeStatePrecompile()
+)
+
+// GetFeeStateOutput is the ABI representation of the parent block's
+// [acp176.State].
+type GetFeeStateOutput struct {
+	Capacity     uint64
+	Excess       uint64
+	TargetExcess uint64
+}
+
+// PackGetFeeState packs the include selector (first 4 func signature bytes).
+// This function is mostly used for tests.
+func PackGetFeeState() ([]byte, error) {
+	return FeeStateABI.Pack("getFeeState")
+}
+
+// PackGetFeeStateOutput attempts to pack given [outputStruct] of type
+// GetFeeStateOutput to conform the ABI outputs.
+func PackGetFeeStateOutput(outputStruct GetFeeStateOutput) ([]byte, error) {
+	return FeeStateABI.PackOutput("getFeeState",
+		outputStruct.Capacity,
+		outputStruct.Excess,
+		outputStruct.TargetExcess,
+	)
+}
+
+// UnpackGetFeeStateOutput attempts to unpack [output] as GetFeeStateOutput
+// assumes that [output] does not include selector (omits first 4 func signature bytes)
+func UnpackGetFeeStateOutput(output []byte) (GetFeeStateOutput, error) {
+	outputStruct := GetFeeStateOutput{}
+	err := FeeStateABI.UnpackIntoInterface(&outputStruct, "getFeeState", output)
+
+	return outputStruct, err
+}
+
+// getFeeState returns the capacity, excess and target excess of the parent
+// block's fee state.
+func getFeeState(accessibleState contract.AccessibleState, _ common.Address, _ common.Address, _ []byte, suppliedGas uint64, _ bool) (ret []byte, remainingGas uint64, err error) {
+	return handleFeeState(accessibleState, suppliedGas, GetFeeStateGasCost, func(state *acp176.State) ([]byte, error) {
+		return PackGetFeeStateOutput(GetFeeStateOutput{
+			Capacity:     uint64(state.Gas.Capacity),
+			Excess:       uint64(state.Gas.Excess),
+			TargetExcess: uint64(state.TargetExcess),
+		})
+	})
+}
+
+// PackGetTarget packs the include selector (first 4 func signature bytes).
+// This function is mostly used for tests.
+func PackGetTarget() ([]byte, error) {
+	return FeeStateABI.Pack("getTarget")
+}
+
+// PackGetTargetOutput attempts to pack given target of type uint64
+// to conform the ABI outputs.
+func PackGetTargetOutput(target uint64) ([]byte, error) {
+	return FeeStateABI.PackOutput("getTarget", target)
+}
+
+// getTarget returns the target gas consumed per second of the parent block's
+// fee state.
+func getTarget(accessibleState contract.AccessibleState, _ common.Address, _ common.Address, _ []byte, suppliedGas uint64, _ bool) (ret []byte, remainingGas uint64, err error) {
+	return handleFeeState(accessibleState, suppliedGas, GetTargetGasCost, func(state *acp176.State) ([]byte, error) {
+		return PackGetTargetOutput(uint64(state.Target()))
+	})
+}
+
+// PackGetMaxCapacity packs the include selector (first 4 func signature bytes).
+// This function is mostly used for tests.
+func PackGetMaxCapacity() ([]byte, error) {
+	return FeeStateABI.Pack("getMaxCapacity")
+}
+
+// PackGetMaxCapacityOutput attempts to pack given maxCapacity of type uint64
+// to conform the ABI outputs.
+func PackGetMaxCapacityOutput(maxCapacity uint64) ([]byte, error) {
+	return FeeStateABI.PackOutput("getMaxCapacity", maxCapacity)
+}
+
+// getMaxCapacity returns the maximum possible accrued gas capacity of the
+// parent block's fee state.
+func getMaxCapacity(accessibleState contract.AccessibleState, _ common.Address, _ common.Address, _ []byte, suppliedGas uint64, _ bool) (ret []byte, remainingGas uint64, err error) {
+	return handleFeeState(accessibleState, suppliedGas, GetMaxCapacityGasCost, func(state *acp176.State) ([]byte, error) {
+		return PackGetMaxCapacityOutput(uint64(state.MaxCapacity()))
+	})
+}
+
+// PackGetGasPrice packs the include selector (first 4 func signature bytes).
+// This function is mostly used for tests.
+func PackGetGasPrice() ([]byte, error) {
+	return FeeStateABI.Pack("getGasPrice")
+}
+
+// PackGetGasPriceOutput attempts to pack given gasPrice of type uint64
+// to conform the ABI outputs.
+func PackGetGasPriceOutput(gasPrice uint64) ([]byte, error) {
+	return FeeStateABI.PackOutput("getGasPrice", gasPrice)
+}
+
+// getGasPrice returns the required fee per gas of the parent block's fee
+// state.
+func getGasPrice(accessibleState contract.AccessibleState, _ common.Address, _ common.Address, _ []byte, suppliedGas uint64, _ bool) (ret []byte, remainingGas uint64, err error) {
+	return handleFeeState(accessibleState, suppliedGas, GetGasPriceGasCost, func(state *acp176.State) ([]byte, error) {
+		return PackGetGasPriceOutput(uint64(state.GasPrice()))
+	})
+}
+
+// handleFeeState charges [gasCost], fetches the parent block's fee state and
+// returns the output produced by [pack].
+func handleFeeState(
+	accessibleState contract.AccessibleState,
+	suppliedGas uint64,
+	gasCost uint64,
+	pack func(state *acp176.State) ([]byte, error),
+) ([]byte, uint64, error) {
+	remainingGas, err := contract.DeductGas(suppliedGas, gasCost)
+	if err != nil {
+		return nil, 0, err
+	}
+	state, err := accessibleState.GetBlockContext().GetParentFeeState()
+	if err != nil {
+		return nil, remainingGas, err
+	}
+	packedOutput, err := pack(&state)
+	if err != nil {
+		return nil, remainingGas, err
+	}
+	return packedOutput, remainingGas, nil
+}
+
+// createFeeStatePrecompile returns a StatefulPrecompiledContract with getters for the precompile.
+func createFeeStatePrecompile() contract.StatefulPrecompiledContract {
+	var functions []*contract.StatefulPrecompileFunction
+
+	abiFunctionMap := map[string]contract.RunStatefulPrecompileFunc{
+		"getFeeState":    getFeeState,
+		"getGasPrice":    getGasPrice,
+		"getMaxCapacity": getMaxCapacity,
+		"getTarget":      getTarget,
+	}
+
+	for name, function := range abiFunctionMap {
+		method, ok := FeeStateABI.Methods[name]
+		if !ok {
+			panic(fmt.Errorf("given method (%s) does not exist in the ABI", name))
+		}
+		functions = append(functions, contract.NewStatefulPrecompileFunction(method.ID, function))
+	}
+	// Construct the contract with no fallback function.
+	statefulContract, err := contract.NewStatefulPrecompileContract(nil, functions)
+	if err != nil {
+		panic(err)
+	}
+	return statefulContract
+}
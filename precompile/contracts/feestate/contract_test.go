@@ -0,0 +1,132 @@
+// Copyright (C) 2019-2025, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package feestate
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/MetalBlockchain/metalgo/vms/components/gas"
+	"github.com/MetalBlockchain/libevm/common"
+	"github.com/MetalBlockchain/libevm/core/vm"
+	"github.com/stretchr/testify/require"
+
+	"github.com/MetalBlockchain/coreth/plugin/evm/upgrade/acp176"
+	"github.com/MetalBlockchain/coreth/precompile/contract"
+	"github.com/MetalBlockchain/coreth/precompile/precompiletest"
+)
+
+func TestFeeStateGetters(t *testing.T) {
+	callerAddr := common.HexToAddress("0x0123")
+	state := acp176.State{
+		Gas: gas.State{
+			Capacity: 1_000_000,
+			Excess:   2_000_000,
+		},
+		TargetExcess: 13_605_152,
+	}
+	errParse := errors.New("failed to parse")
+	withState := func(mbc *contract.MockBlockContext) {
+		mbc.EXPECT().GetParentFeeState().Return(state, nil)
+	}
+	pack := func(packFn func() ([]byte, error)) func(testing.TB) []byte {
+		return func(t testing.TB) []byte {
+			input, err := packFn()
+			require.NoError(t, err)
+			return input
+		}
+	}
+	mustPack := func(output []byte, err error) []byte {
+		require.NoError(t, err)
+		return output
+	}
+
+	tests := map[string]precompiletest.PrecompileTest{
+		"getFeeState success": {
+			Caller:            callerAddr,
+			InputFn:           pack(PackGetFeeState),
+			SuppliedGas:       GetFeeStateGasCost,
+			ReadOnly:          true,
+			SetupBlockContext: withState,
+			ExpectedRes: mustPack(PackGetFeeStateOutput(GetFeeStateOutput{
+				Capacity:     uint64(state.Gas.Capacity),
+				Excess:       uint64(state.Gas.Excess),
+				TargetExcess: uint64(state.TargetExcess),
+			})),
+		},
+		"getFeeState insufficient gas": {
+			Caller:      callerAddr,
+			InputFn:     pack(PackGetFeeState),
+			SuppliedGas: GetFeeStateGasCost - 1,
+			ReadOnly:    false,
+			ExpectedErr: vm.ErrOutOfGas.Error(),
+		},
+		"getFeeState unavailable": {
+			Caller:      callerAddr,
+			InputFn:     pack(PackGetFeeState),
+			SuppliedGas: GetFeeStateGasCost,
+			ReadOnly:    false,
+			SetupBlockContext: func(mbc *contract.MockBlockContext) {
+				mbc.EXPECT().GetParentFeeState().Return(acp176.State{}, errParse)
+			},
+			ExpectedErr: errParse.Error(),
+		},
+		"getTarget success": {
+			Caller:            callerAddr,
+			InputFn:           pack(PackGetTarget),
+			SuppliedGas:       GetTargetGasCost,
+			ReadOnly:          false,
+			SetupBlockContext: withState,
+			ExpectedRes:       mustPack(PackGetTargetOutput(uint64(state.Target()))),
+		},
+		"getTarget insufficient gas": {
+			Caller:      callerAddr,
+			InputFn:     pack(PackGetTarget),
+			SuppliedGas: GetTargetGasCost - 1,
+			ReadOnly:    false,
+			ExpectedErr: vm.ErrOutOfGas.Error(),
+		},
+		"getMaxCapacity success": {
+			Caller:            callerAddr,
+			InputFn:           pack(PackGetMaxCapacity),
+			SuppliedGas:       GetMaxCapacityGasCost,
+			ReadOnly:          false,
+			SetupBlockContext: withState,
+			ExpectedRes:       mustPack(PackGetMaxCapacityOutput(uint64(state.MaxCapacity()))),
+		},
+		"getGasPrice success": {
+			Caller:            callerAddr,
+			InputFn:           pack(PackGetGasPrice),
+			SuppliedGas:       GetGasPriceGasCost,
+			ReadOnly:          false,
+			SetupBlockContext: withState,
+			ExpectedRes:       mustPack(PackGetGasPriceOutput(uint64(state.GasPrice()))),
+		},
+		"getGasPrice insufficient gas": {
+			Caller:      callerAddr,
+			InputFn:     pack(PackGetGasPrice),
+			SuppliedGas: GetGasPriceGasCost - 1,
+			ReadOnly:    false,
+			ExpectedErr: vm.ErrOutOfGas.Error(),
+		},
+	}
+
+	precompiletest.RunPrecompileTests(t, Module, tests)
+}
+
+func TestUnpackGetFeeStateOutput(t *testing.T) {
+	require := require.New(t)
+
+	expected := GetFeeStateOutput{
+		Capacity:     1,
+		Excess:       2,
+		TargetExcess: 3,
+	}
+	packed, err := PackGetFeeStateOutput(expected)
+	require.NoError(err)
+
+	unpacked, err := UnpackGetFeeStateOutput(packed)
+	require.NoError(err)
+	require.Equal(expected, unpacked)
+}
@@ -692,3 +692,57 @@ func BenchmarkWarpPredicate(b *testing.B) {
 	predicateTests := makeWarpPredicateTests(b)
 	precompiletest.RunPredicateBenchmarks(b, predicateTests)
 }
+
+func FuzzVerifyPredicate(f *testing.F) {
+	const numKeys = 10
+	snowCtx := createSnowCtx(f, []validatorRange{
+		{
+			start:     0,
+			end:       numKeys,
+			weight:    20,
+			publicKey: true,
+		},
+	})
+	predicateContext := &precompileconfig.PredicateContext{
+		SnowCtx: snowCtx,
+		ProposerVMBlockCtx: &block.Context{
+			PChainHeight: 1,
+		},
+	}
+	config := NewDefaultConfig(utils.NewUint64(0))
+
+	for _, signers := range []int{1, numKeys / 2, numKeys} {
+		var predicateBytes []byte
+		for _, chunk := range createPredicate(signers) {
+			predicateBytes = append(predicateBytes, chunk[:]...)
+		}
+		f.Add(predicateBytes)
+	}
+	f.Fuzz(func(t *testing.T, predicateBytes []byte) {
+		require := require.New(t)
+
+		pred := make(predicate.Predicate, (len(predicateBytes)+common.HashLength-1)/common.HashLength)
+		for i := range pred {
+			copy(pred[i][:], predicateBytes[i*common.HashLength:])
+		}
+
+		if gas, err := config.PredicateGas(pred); err == nil {
+			require.GreaterOrEqual(gas, GasCostPerSignatureVerification+uint64(len(pred))*GasCostPerWarpMessageChunk)
+		}
+
+		err := config.VerifyPredicate(predicateContext, pred)
+		// Verification must not depend on anything other than its inputs.
+		require.Equal(fmt.Sprint(err), fmt.Sprint(config.VerifyPredicate(predicateContext, pred)))
+		if err != nil {
+			return
+		}
+
+		// Only [unsignedMsg] was signed by the validators, so any predicate
+		// that passes verification must carry it.
+		msgBytes, err := pred.Bytes()
+		require.NoError(err)
+		warpMsg, err := avalancheWarp.ParseMessage(msgBytes)
+		require.NoError(err)
+		require.Equal(unsignedMsg.Bytes(), warpMsg.UnsignedMessage.Bytes())
+	})
+}
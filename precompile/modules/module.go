@@ -21,6 +21,9 @@ type Module struct {
 	Contract contract.StatefulPrecompiledContract
 	// Configurator is used to configure the stateful precompile when the config is enabled.
 	contract.Configurator
+	// DerivedAddressPrefix optionally makes Contract also accessible at every address
+	// starting with it while the precompile is enabled.
+	DerivedAddressPrefix []byte
 }
 
 type moduleArray []Module
@@ -4,6 +4,7 @@
 package modules
 
 import (
+	"bytes"
 	"fmt"
 	"sort"
 
@@ -17,6 +18,9 @@ var (
 	// registeredModules is a list of Module to preserve order
 	// for deterministic iteration
 	registeredModules = make([]Module, 0)
+	// derivedModules is the subset of registeredModules with a
+	// DerivedAddressPrefix, which is checked on every address lookup.
+	derivedModules = make([]Module, 0)
 
 	reservedRanges = []utils.AddressRange{
 		{
@@ -65,11 +69,58 @@ func RegisterModule(stm Module) error {
 			return fmt.Errorf("address %s already used by a stateful precompile", address)
 		}
 	}
+	if derivedModule, ok := GetPrecompileModuleByDerivedAddress(address); ok {
+		return fmt.Errorf("address %s overlaps with the derived addresses of %s", address, derivedModule.ConfigKey)
+	}
+	if prefix := stm.DerivedAddressPrefix; len(prefix) > 0 {
+		if len(prefix) >= common.AddressLength {
+			return fmt.Errorf("derived address prefix %x must be shorter than an address", prefix)
+		}
+		// The derived addresses must not shadow any other precompile.
+		derived := derivedAddressRange(prefix)
+		if bytes.HasPrefix(constants.BlackholeAddr[:], prefix) {
+			return fmt.Errorf("derived address prefix %x overlaps with blackhole address", prefix)
+		}
+		for _, reservedRange := range reservedRanges {
+			if overlaps(derived, reservedRange) {
+				return fmt.Errorf("derived address prefix %x overlaps with reserved range %s-%s", prefix, reservedRange.Start, reservedRange.End)
+			}
+		}
+		for _, registeredModule := range registeredModules {
+			if bytes.HasPrefix(registeredModule.Address[:], prefix) {
+				return fmt.Errorf("derived address prefix %x overlaps with address %s of %s", prefix, registeredModule.Address, registeredModule.ConfigKey)
+			}
+		}
+		for _, derivedModule := range derivedModules {
+			other := derivedModule.DerivedAddressPrefix
+			if bytes.HasPrefix(prefix, other) || bytes.HasPrefix(other, prefix) {
+				return fmt.Errorf("derived address prefix %x overlaps with the one of %s", prefix, derivedModule.ConfigKey)
+			}
+		}
+		derivedModules = insertSortedByAddress(derivedModules, stm)
+	}
 	// sort by address to ensure deterministic iteration
 	registeredModules = insertSortedByAddress(registeredModules, stm)
 	return nil
 }
 
+// derivedAddressRange returns the range of the addresses starting with
+// [prefix].
+func derivedAddressRange(prefix []byte) utils.AddressRange {
+	var r utils.AddressRange
+	copy(r.Start[:], prefix)
+	n := copy(r.End[:], prefix)
+	for i := n; i < common.AddressLength; i++ {
+		r.End[i] = 0xff
+	}
+	return r
+}
+
+// overlaps returns true if the inclusive ranges [a] and [b] share an address.
+func overlaps(a, b utils.AddressRange) bool {
+	return bytes.Compare(a.Start[:], b.End[:]) <= 0 && bytes.Compare(b.Start[:], a.End[:]) <= 0
+}
+
 func GetPrecompileModuleByAddress(address common.Address) (Module, bool) {
 	for _, stm := range registeredModules {
 		if stm.Address == address {
@@ -79,6 +130,17 @@ func GetPrecompileModuleByAddress(address common.Address) (Module, bool) {
 	return Module{}, false
 }
 
+// GetPrecompileModuleByDerivedAddress returns the module whose
+// DerivedAddressPrefix [address] starts with, if any.
+func GetPrecompileModuleByDerivedAddress(address common.Address) (Module, bool) {
+	for _, stm := range derivedModules {
+		if bytes.HasPrefix(address[:], stm.DerivedAddressPrefix) {
+			return stm, true
+		}
+	}
+	return Module{}, false
+}
+
 func GetPrecompileModule(key string) (Module, bool) {
 	for _, stm := range registeredModules {
 		if stm.ConfigKey == key {
@@ -58,3 +58,52 @@ func TestRegisterModuleInvalidAddresses(t *testing.T) {
 	err = RegisterModule(m)
 	require.ErrorContains(t, err, "not in a reserved range")
 }
+
+func TestRegisterModuleDerivedAddressPrefix(t *testing.T) {
+	require := require.New(t)
+
+	m := Module{
+		ConfigKey:            "derived",
+		Address:              common.HexToAddress("0x03000000000000000000000000000000000000f0"),
+		DerivedAddressPrefix: []byte{0x04, 0x01},
+	}
+	require.NoError(RegisterModule(m))
+
+	derived := common.Address{0x04, 0x01, 0xff}
+	got, ok := GetPrecompileModuleByDerivedAddress(derived)
+	require.True(ok)
+	require.Equal(m.Address, got.Address)
+	_, ok = GetPrecompileModuleByDerivedAddress(common.Address{0x04, 0x02})
+	require.False(ok)
+
+	// Test overlapping derived address prefixes cannot be registered
+	overlapping := Module{
+		ConfigKey:            "overlapping",
+		Address:              common.HexToAddress("0x03000000000000000000000000000000000000f1"),
+		DerivedAddressPrefix: []byte{0x04},
+	}
+	err := RegisterModule(overlapping)
+	require.ErrorContains(err, "overlaps")
+
+	// Test a derived address prefix as long as an address cannot be registered
+	overlapping.DerivedAddressPrefix = make([]byte, common.AddressLength)
+	err = RegisterModule(overlapping)
+	require.ErrorContains(err, "shorter than an address")
+
+	// Test derived address prefixes cannot overlap with reserved ranges,
+	// including the addresses of registered modules
+	overlapping.DerivedAddressPrefix = []byte{0x03, 0x00}
+	err = RegisterModule(overlapping)
+	require.ErrorContains(err, "overlaps with reserved range")
+	overlapping.DerivedAddressPrefix = []byte{0x02}
+	err = RegisterModule(overlapping)
+	require.ErrorContains(err, "overlaps with reserved range")
+
+	// Test a derived address prefix can lie next to a reserved range
+	adjacent := Module{
+		ConfigKey:            "adjacent",
+		Address:              common.HexToAddress("0x03000000000000000000000000000000000000f2"),
+		DerivedAddressPrefix: common.Address{0x03, 18: 0x01}.Bytes()[:19],
+	}
+	require.NoError(RegisterModule(adjacent))
+}
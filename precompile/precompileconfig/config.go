@@ -77,4 +77,6 @@ type Accepter interface {
 type ChainConfig interface {
 	// IsDurango returns true if the time is after Durango.
 	IsDurango(time uint64) bool
+	// IsFortuna returns true if the time is after Fortuna.
+	IsFortuna(time uint64) bool
 }
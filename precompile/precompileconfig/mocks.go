@@ -202,6 +202,20 @@ func (mr *MockChainConfigMockRecorder) IsDurango(time any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsDurango", reflect.TypeOf((*MockChainConfig)(nil).IsDurango), time)
 }
 
+// IsFortuna mocks base method.
+func (m *MockChainConfig) IsFortuna(time uint64) bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsFortuna", time)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// IsFortuna indicates an expected call of IsFortuna.
+func (mr *MockChainConfigMockRecorder) IsFortuna(time any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsFortuna", reflect.TypeOf((*MockChainConfig)(nil).IsFortuna), time)
+}
+
 // MockAccepter is a mock of Accepter interface.
 type MockAccepter struct {
 	ctrl     *gomock.Controller
@@ -37,6 +37,7 @@ func RunVerifyTests(t *testing.T, tests map[string]ConfigVerifyTest) {
 				ctrl := gomock.NewController(t)
 				mockChainConfig := precompileconfig.NewMockChainConfig(ctrl)
 				mockChainConfig.EXPECT().IsDurango(gomock.Any()).AnyTimes().Return(true)
+				mockChainConfig.EXPECT().IsFortuna(gomock.Any()).AnyTimes().Return(true)
 				chainConfig = mockChainConfig
 			}
 			err := test.Config.Verify(chainConfig)
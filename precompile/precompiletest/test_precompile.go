@@ -26,6 +26,9 @@ import (
 type PrecompileTest struct {
 	// Caller is the address of the precompile caller
 	Caller common.Address
+	// ContractAddress is the address the precompile is called at.
+	// If empty, the address of the module is used.
+	ContractAddress common.Address
 	// Input the raw input bytes to the precompile
 	Input []byte
 	// InputFn is a function that returns the raw input bytes to the precompile
@@ -45,6 +48,8 @@ type PrecompileTest struct {
 	Predicates []predicate.Predicate
 	// SetupBlockContext sets the expected calls on MockBlockContext for the test execution.
 	SetupBlockContext func(*contract.MockBlockContext)
+	// BeforeHook is called before the precompile is called, after it is configured.
+	BeforeHook func(t testing.TB, state contract.StateDB)
 	// AfterHook is called after the precompile is called.
 	AfterHook func(t testing.TB, state contract.StateDB)
 	// ExpectedRes is the expected raw byte result returned by the precompile
@@ -90,6 +95,9 @@ func (test PrecompileTest) Run(t *testing.T, module modules.Module) {
 func (test PrecompileTest) setup(t testing.TB, module modules.Module, state *testStateDB) PrecompileRunparams {
 	t.Helper()
 	contractAddress := module.Address
+	if test.ContractAddress != (common.Address{}) {
+		contractAddress = test.ContractAddress
+	}
 
 	ctrl := gomock.NewController(t)
 
@@ -97,6 +105,7 @@ func (test PrecompileTest) setup(t testing.TB, module modules.Module, state *tes
 	if chainConfig == nil {
 		mockChainConfig := precompileconfig.NewMockChainConfig(ctrl)
 		mockChainConfig.EXPECT().IsDurango(gomock.Any()).AnyTimes().Return(true)
+		mockChainConfig.EXPECT().IsFortuna(gomock.Any()).AnyTimes().Return(true)
 		chainConfig = mockChainConfig
 	}
 
@@ -118,6 +127,9 @@ func (test PrecompileTest) setup(t testing.TB, module modules.Module, state *tes
 	if test.Config != nil {
 		require.NoError(t, module.Configure(chainConfig, test.Config, state, blockContext))
 	}
+	if test.BeforeHook != nil {
+		test.BeforeHook(t, state)
+	}
 
 	input := test.Input
 	if test.InputFn != nil {
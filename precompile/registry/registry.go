@@ -7,6 +7,8 @@ package registry
 // Force imports of each precompile to ensure each precompile's init function runs and registers itself
 // with the registry.
 import (
+	_ "github.com/MetalBlockchain/coreth/precompile/contracts/assettoken"
+	_ "github.com/MetalBlockchain/coreth/precompile/contracts/feestate"
 	_ "github.com/MetalBlockchain/coreth/precompile/contracts/warp"
 	// ADD PRECOMPILES BELOW
 	// _ "github.com/MetalBlockchain/coreth/precompile/contracts/newprecompile"
@@ -17,5 +19,7 @@ import (
 // in /coreth/contracts/contracts/**.
 
 // WarpMessengerAddress = common.HexToAddress("0x0200000000000000000000000000000000000005")
+// FeeStateAddress = common.HexToAddress("0x0200000000000000000000000000000000000006")
+// AssetTokenAddress = common.HexToAddress("0x0200000000000000000000000000000000000007")
 // ADD PRECOMPILES BELOW
 // NewPrecompileAddress = common.HexToAddress("0x02000000000000000000000000000000000000??")
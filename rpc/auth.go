@@ -0,0 +1,310 @@
+// Copyright (C) 2019-2025, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package rpc
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"slices"
+	"strings"
+	"time"
+)
+
+const (
+	// MinAuthSecretLength is the minimum length of the HS256 key used to
+	// verify bearer tokens.
+	MinAuthSecretLength = 32
+
+	// authClockSkew is the tolerance applied when checking the expiry and
+	// not-before claims of a token.
+	authClockSkew = 5 * time.Second
+
+	// authWildcard grants access to every namespace when listed in the
+	// namespaces claim.
+	authWildcard = "*"
+)
+
+var (
+	errAuthSecretTooShort   = fmt.Errorf("auth secret must be at least %d bytes", MinAuthSecretLength)
+	errMissingToken         = errors.New("missing bearer token")
+	errMalformedToken       = errors.New("malformed token")
+	errUnsupportedAlgorithm = errors.New("unsupported token algorithm")
+	errInvalidSignature     = errors.New("invalid token signature")
+	errTokenExpired         = errors.New("token is expired")
+	errTokenNotYetValid     = errors.New("token is not valid yet")
+	errNotPermitted         = errors.New("not permitted by token")
+
+	authTokenHeader = base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"HS256","typ":"JWT"}`))
+)
+
+// AuthConfig configures bearer-token authentication of the calls served by a
+// Server.
+type AuthConfig struct {
+	// Secret is the HS256 key used to verify tokens.
+	Secret []byte
+	// PublicNamespaces are the namespaces that can be called without a token.
+	// The "rpc" namespace is always public.
+	PublicNamespaces []string
+}
+
+// AuthClaims are the claims of a bearer token. A call is permitted if its
+// namespace is listed in Namespaces or the method itself is listed in
+// Methods.
+type AuthClaims struct {
+	// Namespaces the token grants access to, e.g. "debug". "*" grants access
+	// to every namespace.
+	Namespaces []string `json:"namespaces,omitempty"`
+	// Methods the token grants access to, e.g. "admin_startCPUProfile".
+	Methods []string `json:"methods,omitempty"`
+
+	ExpiresAt int64 `json:"exp,omitempty"`
+	NotBefore int64 `json:"nbf,omitempty"`
+	IssuedAt  int64 `json:"iat,omitempty"`
+}
+
+// expired returns true if the claims are no longer valid at [now].
+func (c *AuthClaims) expired(now time.Time) bool {
+	return c.ExpiresAt != 0 && now.Add(-authClockSkew).Unix() >= c.ExpiresAt
+}
+
+// expiry returns the time after which the claims are no longer valid, and
+// false if they never expire.
+func (c *AuthClaims) expiry() (time.Time, bool) {
+	if c.ExpiresAt == 0 {
+		return time.Time{}, false
+	}
+	return time.Unix(c.ExpiresAt, 0).Add(authClockSkew), true
+}
+
+// permits returns true if the claims grant access to [method] in [namespace].
+func (c *AuthClaims) permits(namespace, method string) bool {
+	return slices.Contains(c.Namespaces, authWildcard) ||
+		slices.Contains(c.Namespaces, namespace) ||
+		(method != "" && slices.Contains(c.Methods, method))
+}
+
+// SetAuth requires calls outside of [config.PublicNamespaces] to present a
+// bearer token signed with [config.Secret] that permits them.
+//
+// This method should be called before processing any requests via ServeHTTP
+// or WebsocketHandler.
+func (s *Server) SetAuth(config AuthConfig) error {
+	policy, err := newAuthPolicy(config)
+	if err != nil {
+		return err
+	}
+	s.auth = policy
+	return nil
+}
+
+// NewAuthHandler wraps [next], which serves the [namespace] API outside of a
+// Server, so that requests must present a bearer token permitting
+// [namespace] unless it is one of [config.PublicNamespaces].
+//
+// Requests without a valid token are rejected with 401 Unauthorized, and
+// requests whose token does not permit [namespace] with 403 Forbidden.
+func NewAuthHandler(config AuthConfig, namespace string, next http.Handler) (http.Handler, error) {
+	policy, err := newAuthPolicy(config)
+	if err != nil {
+		return nil, err
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		now := time.Now()
+		err := policy.authenticate(r.Header, now).authorizeNamespace(namespace, "", now)
+		switch {
+		case errors.Is(err, errNotPermitted):
+			http.Error(w, err.Error(), http.StatusForbidden)
+		case err != nil:
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+		default:
+			next.ServeHTTP(w, r)
+		}
+	}), nil
+}
+
+// NewAuthToken returns an HS256 token carrying [claims], signed with [secret].
+func NewAuthToken(secret []byte, claims AuthClaims) (string, error) {
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+	signingInput := authTokenHeader + "." + base64.RawURLEncoding.EncodeToString(payload)
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(signAuthToken(secret, signingInput)), nil
+}
+
+// LoadAuthSecret reads a hex encoded HS256 key from the file at [path].
+func LoadAuthSecret(path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	encoded := strings.TrimPrefix(strings.TrimSpace(string(data)), "0x")
+	secret, err := hex.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("invalid auth secret in %s: %w", path, err)
+	}
+	if len(secret) < MinAuthSecretLength {
+		return nil, fmt.Errorf("invalid auth secret in %s: %w", path, errAuthSecretTooShort)
+	}
+	return secret, nil
+}
+
+// authPolicy decides which calls a connection is permitted to make.
+type authPolicy struct {
+	secret []byte
+	public map[string]struct{}
+}
+
+func newAuthPolicy(config AuthConfig) (*authPolicy, error) {
+	if len(config.Secret) < MinAuthSecretLength {
+		return nil, errAuthSecretTooShort
+	}
+	public := map[string]struct{}{MetadataApi: {}}
+	for _, namespace := range config.PublicNamespaces {
+		public[namespace] = struct{}{}
+	}
+	return &authPolicy{
+		secret: slices.Clone(config.Secret),
+		public: public,
+	}, nil
+}
+
+// authenticate verifies the bearer token in [header], if any, at [now].
+func (p *authPolicy) authenticate(header http.Header, now time.Time) *authorization {
+	a := &authorization{policy: p}
+	token, ok := bearerToken(header)
+	if !ok {
+		return a
+	}
+	a.claims, a.err = p.verify(token, now)
+	return a
+}
+
+// verify returns the claims of [token] if it is correctly signed and valid at
+// [now].
+func (p *authPolicy) verify(token string, now time.Time) (*AuthClaims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, errMalformedToken
+	}
+	headerJSON, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return nil, errMalformedToken
+	}
+	var header struct {
+		Alg string `json:"alg"`
+	}
+	if err := json.Unmarshal(headerJSON, &header); err != nil {
+		return nil, errMalformedToken
+	}
+	if header.Alg != "HS256" {
+		return nil, fmt.Errorf("%w: %q", errUnsupportedAlgorithm, header.Alg)
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, errMalformedToken
+	}
+	if !hmac.Equal(signature, signAuthToken(p.secret, parts[0]+"."+parts[1])) {
+		return nil, errInvalidSignature
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, errMalformedToken
+	}
+	claims := new(AuthClaims)
+	if err := json.Unmarshal(payload, claims); err != nil {
+		return nil, errMalformedToken
+	}
+	if claims.expired(now) {
+		return nil, errTokenExpired
+	}
+	if claims.NotBefore != 0 && now.Add(authClockSkew).Unix() < claims.NotBefore {
+		return nil, errTokenNotYetValid
+	}
+	return claims, nil
+}
+
+func signAuthToken(secret []byte, signingInput string) []byte {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(signingInput))
+	return mac.Sum(nil)
+}
+
+// bearerToken returns the token of the bearer Authorization [header].
+func bearerToken(header http.Header) (string, bool) {
+	scheme, token, ok := strings.Cut(header.Get("Authorization"), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return "", false
+	}
+	token = strings.TrimSpace(token)
+	return token, token != ""
+}
+
+// authorization is the result of authenticating a connection. It is attached
+// to the context of every call made over the connection.
+type authorization struct {
+	policy *authPolicy
+	claims *AuthClaims // nil if no valid token was presented
+	err    error       // set if the presented token is invalid
+}
+
+// authorize returns an error if the connection is not permitted to call
+// [method] at [now], and records the decision in metrics.
+func (a *authorization) authorize(method string, now time.Time) error {
+	namespace, _, _ := strings.Cut(method, serviceMethodSeparator)
+	return a.authorizeNamespace(namespace, method, now)
+}
+
+// authorizeNamespace returns an error if the connection is not permitted to
+// call [method] of [namespace] at [now], or any method of [namespace] if
+// [method] is empty.
+//
+// Since a connection may outlive its token, the expiry of the token is
+// checked again for every call.
+func (a *authorization) authorizeNamespace(namespace, method string, now time.Time) error {
+	if _, ok := a.policy.public[namespace]; ok {
+		authPublicCounter.Inc(1)
+		return nil
+	}
+	target := method
+	if target == "" {
+		target = namespace
+	}
+	switch {
+	case a.err != nil:
+		authInvalidCounter.Inc(1)
+		return &unauthorizedError{method: target, err: a.err}
+	case a.claims == nil:
+		authMissingCounter.Inc(1)
+		return &unauthorizedError{method: target, err: errMissingToken}
+	case a.claims.expired(now):
+		authInvalidCounter.Inc(1)
+		return &unauthorizedError{method: target, err: errTokenExpired}
+	case !a.claims.permits(namespace, method):
+		authForbiddenCounter.Inc(1)
+		return &unauthorizedError{method: target, err: errNotPermitted}
+	default:
+		authGrantedCounter.Inc(1)
+		return nil
+	}
+}
+
+type authorizationContextKey struct{}
+
+// authorizationFromContext returns the authorization of the connection a call
+// was made over, or nil if the server does not require authentication.
+func authorizationFromContext(ctx context.Context) *authorization {
+	a, _ := ctx.Value(authorizationContextKey{}).(*authorization)
+	return a
+}
@@ -0,0 +1,386 @@
+// Copyright (C) 2019-2025, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package rpc
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+var testAuthSecret = []byte("0123456789abcdef0123456789abcdef")
+
+func newTestAuthServer(t *testing.T) *Server {
+	t.Helper()
+	server := newTestServer()
+	err := server.SetAuth(AuthConfig{
+		Secret:           testAuthSecret,
+		PublicNamespaces: []string{"nftest"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return server
+}
+
+func newTestAuthToken(t *testing.T, secret []byte, claims AuthClaims) string {
+	t.Helper()
+	token, err := NewAuthToken(secret, claims)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return token
+}
+
+func TestSetAuthSecretTooShort(t *testing.T) {
+	server := NewServer(0)
+	err := server.SetAuth(AuthConfig{Secret: testAuthSecret[:MinAuthSecretLength-1]})
+	if !errors.Is(err, errAuthSecretTooShort) {
+		t.Fatalf("wrong error: got %v, want %v", err, errAuthSecretTooShort)
+	}
+}
+
+func TestAuthVerify(t *testing.T) {
+	var (
+		now    = time.Unix(1_700_000_000, 0)
+		policy = &authPolicy{secret: testAuthSecret}
+	)
+	tests := []struct {
+		name    string
+		token   string
+		wantErr error
+	}{
+		{
+			name:  "valid",
+			token: newTestAuthToken(t, testAuthSecret, AuthClaims{Namespaces: []string{"debug"}}),
+		},
+		{
+			name:  "valid within expiry",
+			token: newTestAuthToken(t, testAuthSecret, AuthClaims{ExpiresAt: now.Unix() + 60, NotBefore: now.Unix() - 60}),
+		},
+		{
+			name:    "expired",
+			token:   newTestAuthToken(t, testAuthSecret, AuthClaims{ExpiresAt: now.Unix() - 60}),
+			wantErr: errTokenExpired,
+		},
+		{
+			name:    "not yet valid",
+			token:   newTestAuthToken(t, testAuthSecret, AuthClaims{NotBefore: now.Unix() + 60}),
+			wantErr: errTokenNotYetValid,
+		},
+		{
+			name:    "wrong secret",
+			token:   newTestAuthToken(t, []byte("fedcba9876543210fedcba9876543210"), AuthClaims{}),
+			wantErr: errInvalidSignature,
+		},
+		{
+			name:    "unsigned",
+			token:   "eyJhbGciOiJub25lIn0.e30.",
+			wantErr: errUnsupportedAlgorithm,
+		},
+		{
+			name:    "malformed",
+			token:   "not-a-token",
+			wantErr: errMalformedToken,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := policy.verify(test.token, now)
+			if !errors.Is(err, test.wantErr) {
+				t.Fatalf("wrong error: got %v, want %v", err, test.wantErr)
+			}
+		})
+	}
+}
+
+func TestAuthorize(t *testing.T) {
+	policy := &authPolicy{
+		secret: testAuthSecret,
+		public: map[string]struct{}{MetadataApi: {}, "eth": {}},
+	}
+	claims := &AuthClaims{
+		Namespaces: []string{"debug"},
+		Methods:    []string{"admin_exportChain"},
+	}
+	now := time.Now()
+	tests := []struct {
+		name    string
+		auth    *authorization
+		method  string
+		wantErr error
+	}{
+		{
+			name:   "public without token",
+			auth:   &authorization{policy: policy},
+			method: "eth_blockNumber",
+		},
+		{
+			name:   "metadata without token",
+			auth:   &authorization{policy: policy},
+			method: "rpc_modules",
+		},
+		{
+			name:    "private without token",
+			auth:    &authorization{policy: policy},
+			method:  "debug_traceTransaction",
+			wantErr: errMissingToken,
+		},
+		{
+			name:    "private with invalid token",
+			auth:    &authorization{policy: policy, err: errTokenExpired},
+			method:  "debug_traceTransaction",
+			wantErr: errTokenExpired,
+		},
+		{
+			name:   "permitted namespace",
+			auth:   &authorization{policy: policy, claims: claims},
+			method: "debug_traceTransaction",
+		},
+		{
+			name:   "permitted method",
+			auth:   &authorization{policy: policy, claims: claims},
+			method: "admin_exportChain",
+		},
+		{
+			name:    "forbidden method",
+			auth:    &authorization{policy: policy, claims: claims},
+			method:  "admin_importChain",
+			wantErr: errNotPermitted,
+		},
+		{
+			name:   "wildcard",
+			auth:   &authorization{policy: policy, claims: &AuthClaims{Namespaces: []string{"*"}}},
+			method: "txpool_content",
+		},
+		{
+			name: "expired after connecting",
+			auth: &authorization{policy: policy, claims: &AuthClaims{
+				Namespaces: []string{"debug"},
+				ExpiresAt:  now.Add(-time.Minute).Unix(),
+			}},
+			method:  "debug_traceTransaction",
+			wantErr: errTokenExpired,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := test.auth.authorize(test.method, now)
+			if !errors.Is(err, test.wantErr) {
+				t.Fatalf("wrong error: got %v, want %v", err, test.wantErr)
+			}
+		})
+	}
+}
+
+func TestHTTPAuth(t *testing.T) {
+	server := newTestAuthServer(t)
+	defer server.Stop()
+	httpsrv := httptest.NewServer(server)
+	defer httpsrv.Close()
+
+	call := func(t *testing.T, token string, method string, args ...interface{}) error {
+		t.Helper()
+		var opts []ClientOption
+		if token != "" {
+			opts = append(opts, WithHeader("Authorization", "Bearer "+token))
+		}
+		client, err := DialOptions(context.Background(), httpsrv.URL, opts...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer client.Close()
+		return client.Call(nil, method, args...)
+	}
+
+	t.Run("no token", func(t *testing.T) {
+		err := call(t, "", "test_echo", "hello", 1)
+		var rpcErr Error
+		if !errors.As(err, &rpcErr) || rpcErr.ErrorCode() != errcodeUnauthorized {
+			t.Fatalf("wrong error: got %v, want code %d", err, errcodeUnauthorized)
+		}
+	})
+	t.Run("forbidden", func(t *testing.T) {
+		token := newTestAuthToken(t, testAuthSecret, AuthClaims{Methods: []string{"test_sleep"}})
+		err := call(t, token, "test_echo", "hello", 1)
+		if err == nil || !strings.Contains(err.Error(), errNotPermitted.Error()) {
+			t.Fatalf("wrong error: got %v, want %v", err, errNotPermitted)
+		}
+	})
+	t.Run("permitted", func(t *testing.T) {
+		token := newTestAuthToken(t, testAuthSecret, AuthClaims{Namespaces: []string{"test"}})
+		if err := call(t, token, "test_echo", "hello", 1); err != nil {
+			t.Fatal(err)
+		}
+	})
+	t.Run("public", func(t *testing.T) {
+		if err := call(t, "", "nftest_echo", 1); err != nil {
+			t.Fatal(err)
+		}
+	})
+}
+
+func TestWebsocketAuth(t *testing.T) {
+	server := newTestAuthServer(t)
+	defer server.Stop()
+	httpsrv := httptest.NewServer(server.WebsocketHandler([]string{"*"}))
+	defer httpsrv.Close()
+	wsURL := "ws:" + strings.TrimPrefix(httpsrv.URL, "http:")
+
+	dial := func(t *testing.T, header http.Header) *Client {
+		t.Helper()
+		client, err := DialOptions(context.Background(), wsURL, WithHeaders(header))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return client
+	}
+
+	unauthenticated := dial(t, http.Header{})
+	defer unauthenticated.Close()
+	var result echoResult
+	err := unauthenticated.Call(&result, "test_echo", "hello", 1)
+	var rpcErr Error
+	if !errors.As(err, &rpcErr) || rpcErr.ErrorCode() != errcodeUnauthorized {
+		t.Fatalf("wrong error: got %v, want code %d", err, errcodeUnauthorized)
+	}
+
+	token := newTestAuthToken(t, testAuthSecret, AuthClaims{Namespaces: []string{"test"}})
+	authenticated := dial(t, http.Header{"Authorization": []string{"Bearer " + token}})
+	defer authenticated.Close()
+	if err := authenticated.Call(&result, "test_echo", "hello", 1); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestWebsocketAuthExpiry(t *testing.T) {
+	server := newTestAuthServer(t)
+	defer server.Stop()
+	httpsrv := httptest.NewServer(server.WebsocketHandler([]string{"*"}))
+	defer httpsrv.Close()
+	wsURL := "ws:" + strings.TrimPrefix(httpsrv.URL, "http:")
+
+	// The token is still accepted thanks to the clock skew tolerance, but
+	// expires about a second after connecting.
+	token := newTestAuthToken(t, testAuthSecret, AuthClaims{
+		Namespaces: []string{"test"},
+		ExpiresAt:  time.Now().Add(-authClockSkew + time.Second).Unix(),
+	})
+	client, err := DialOptions(context.Background(), wsURL, WithHeader("Authorization", "Bearer "+token))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+
+	ch := make(chan int)
+	sub, err := client.Subscribe(context.Background(), "nftest", ch, "hangSubscription", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-sub.Err():
+	case <-time.After(10 * time.Second):
+		t.Fatal("connection not closed after the token expired")
+	}
+}
+
+func TestAuthHandler(t *testing.T) {
+	next := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	config := AuthConfig{
+		Secret:           testAuthSecret,
+		PublicNamespaces: []string{"avax"},
+	}
+	admin, err := NewAuthHandler(config, "admin", next)
+	if err != nil {
+		t.Fatal(err)
+	}
+	avax, err := NewAuthHandler(config, "avax", next)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		handler  http.Handler
+		token    string
+		wantCode int
+	}{
+		{
+			name:     "no token",
+			handler:  admin,
+			wantCode: http.StatusUnauthorized,
+		},
+		{
+			name:     "invalid token",
+			handler:  admin,
+			token:    newTestAuthToken(t, []byte("fedcba9876543210fedcba9876543210"), AuthClaims{Namespaces: []string{"admin"}}),
+			wantCode: http.StatusUnauthorized,
+		},
+		{
+			name:     "forbidden",
+			handler:  admin,
+			token:    newTestAuthToken(t, testAuthSecret, AuthClaims{Namespaces: []string{"debug"}}),
+			wantCode: http.StatusForbidden,
+		},
+		{
+			name:     "method claims do not grant a namespace",
+			handler:  admin,
+			token:    newTestAuthToken(t, testAuthSecret, AuthClaims{Methods: []string{"admin_exportChain"}}),
+			wantCode: http.StatusForbidden,
+		},
+		{
+			name:     "permitted",
+			handler:  admin,
+			token:    newTestAuthToken(t, testAuthSecret, AuthClaims{Namespaces: []string{"admin"}}),
+			wantCode: http.StatusOK,
+		},
+		{
+			name:     "public",
+			handler:  avax,
+			wantCode: http.StatusOK,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/", nil)
+			if test.token != "" {
+				req.Header.Set("Authorization", "Bearer "+test.token)
+			}
+			rec := httptest.NewRecorder()
+			test.handler.ServeHTTP(rec, req)
+			if rec.Code != test.wantCode {
+				t.Fatalf("wrong status: got %d, want %d", rec.Code, test.wantCode)
+			}
+		})
+	}
+}
+
+func TestLoadAuthSecret(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "secret")
+	if err := os.WriteFile(path, []byte("0x3031323334353637383961626364656630313233343536373839616263646566\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	secret, err := LoadAuthSecret(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(secret) != string(testAuthSecret) {
+		t.Fatalf("wrong secret: got %x, want %x", secret, testAuthSecret)
+	}
+
+	if err := os.WriteFile(path, []byte("0x00"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadAuthSecret(path); !errors.Is(err, errAuthSecretTooShort) {
+		t.Fatalf("wrong error: got %v, want %v", err, errAuthSecretTooShort)
+	}
+}
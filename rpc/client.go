@@ -129,6 +129,9 @@ func (c *Client) newClientConn(conn ServerCodec, apiMaxDuration, refillRate, max
 	ctx := context.Background()
 	ctx = context.WithValue(ctx, clientContextKey{}, c)
 	ctx = context.WithValue(ctx, peerInfoContextKey{}, conn.peerInfo())
+	if ac, ok := conn.(interface{ authorization() *authorization }); ok && ac.authorization() != nil {
+		ctx = context.WithValue(ctx, authorizationContextKey{}, ac.authorization())
+	}
 	handler := newHandler(ctx, conn, c.idgen, c.services, c.batchItemLimit, c.batchResponseMaxSize)
 
 	// When [apiMaxDuration] or [refillRate]/[maxStored] is 0 (as is the case for
@@ -27,7 +27,11 @@
 
 package rpc
 
-import "fmt"
+import (
+	"fmt"
+	"math"
+	"time"
+)
 
 // HTTPError is returned by client operations when the HTTP status code of the
 // response is not a 2xx status.
@@ -66,12 +70,16 @@ var (
 	_ Error = new(invalidMessageError)
 	_ Error = new(invalidParamsError)
 	_ Error = new(internalServerError)
+	_ Error = new(unauthorizedError)
+	_ Error = new(rateLimitedError)
 )
 
 const (
 	errcodeDefault          = -32000
 	errcodeTimeout          = -32002
 	errcodeResponseTooLarge = -32003
+	errcodeUnauthorized     = -32010
+	errcodeRateLimited      = -32005
 	errcodePanic            = -32603
 	errcodeMarshalError     = -32603
 
@@ -165,3 +173,36 @@ type internalServerError struct {
 func (e *internalServerError) ErrorCode() int { return e.code }
 
 func (e *internalServerError) Error() string { return e.message }
+
+// unauthorizedError is returned for calls the connection is not permitted to
+// make.
+type unauthorizedError struct {
+	method string
+	err    error
+}
+
+func (e *unauthorizedError) ErrorCode() int { return errcodeUnauthorized }
+
+func (e *unauthorizedError) Error() string {
+	return fmt.Sprintf("unauthorized to call %s: %v", e.method, e.err)
+}
+
+func (e *unauthorizedError) Unwrap() error { return e.err }
+
+// rateLimitedError is returned for calls exceeding the rate limit of the
+// client making them.
+type rateLimitedError struct {
+	method     string
+	retryAfter time.Duration
+}
+
+func (e *rateLimitedError) ErrorCode() int { return errcodeRateLimited }
+
+func (e *rateLimitedError) Error() string {
+	return fmt.Sprintf("rate limit exceeded calling %s, retry after %s", e.method, e.retryAfter)
+}
+
+// ErrorData returns the number of seconds after which the call can be retried.
+func (e *rateLimitedError) ErrorData() interface{} {
+	return map[string]interface{}{"retryAfter": int64(math.Ceil(e.retryAfter.Seconds()))}
+}
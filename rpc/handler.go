@@ -593,6 +593,16 @@ func (h *handler) handleCallMsg(ctx *callProc, msg *jsonrpcMessage) *jsonrpcMess
 
 // handleCall processes method calls.
 func (h *handler) handleCall(cp *callProc, msg *jsonrpcMessage) *jsonrpcMessage {
+	if auth := authorizationFromContext(cp.ctx); auth != nil {
+		if err := auth.authorize(msg.Method, time.Now()); err != nil {
+			return msg.errorResponse(err)
+		}
+	}
+	if client := rateLimitFromContext(cp.ctx); client != nil {
+		if err := client.allow(msg.Method); err != nil {
+			return msg.errorResponse(err)
+		}
+	}
 	if msg.isSubscribe() {
 		return h.handleSubscribe(cp, msg)
 	}
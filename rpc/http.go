@@ -268,11 +268,16 @@ type httpServerConn struct {
 	r *http.Request
 }
 
-func (s *Server) newHTTPServerConn(r *http.Request, w http.ResponseWriter) ServerCodec {
+// newHTTPServerConn returns the codec of a request, which sets the Retry-After
+// header of the response if [rateLimit] is non-nil and rejected a call.
+func (s *Server) newHTTPServerConn(r *http.Request, w http.ResponseWriter, rateLimit *rateLimitClient) ServerCodec {
 	body := io.LimitReader(r.Body, int64(s.httpBodyLimit))
 	conn := &httpServerConn{Reader: body, Writer: w, r: r}
 
 	encoder := func(v any, isErrorResponse bool) error {
+		if rateLimit != nil {
+			rateLimit.setRetryAfter(w.Header())
+		}
 		if !isErrorResponse {
 			return json.NewEncoder(conn).Encode(v)
 		}
@@ -340,12 +345,20 @@ func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	connInfo.HTTP.UserAgent = r.Header.Get("User-Agent")
 	ctx := r.Context()
 	ctx = context.WithValue(ctx, peerInfoContextKey{}, connInfo)
+	if s.auth != nil {
+		ctx = context.WithValue(ctx, authorizationContextKey{}, s.auth.authenticate(r.Header, time.Now()))
+	}
+	var rateLimit *rateLimitClient
+	if s.rateLimit != nil {
+		rateLimit = s.rateLimit.client(r)
+		ctx = context.WithValue(ctx, rateLimitContextKey{}, rateLimit)
+	}
 
 	// All checks passed, create a codec that reads directly from the request body
 	// until EOF, writes the response to w, and orders the server to process a
 	// single request.
 	w.Header().Set("content-type", contentType)
-	codec := s.newHTTPServerConn(r, w)
+	codec := s.newHTTPServerConn(r, w, rateLimit)
 	defer codec.close()
 	s.serveSingleRequest(ctx, codec)
 }
@@ -52,6 +52,14 @@ var (
 	serveTimeHistName = "rpc/duration"
 
 	rpcServingTimer = metrics.GetOrRegisterTimer("rpc/duration/all", nil)
+
+	// Authorization decisions, only recorded if the server requires
+	// authentication.
+	authPublicCounter    = metrics.NewRegisteredCounter("rpc/auth/public", nil)
+	authGrantedCounter   = metrics.NewRegisteredCounter("rpc/auth/granted", nil)
+	authMissingCounter   = metrics.NewRegisteredCounter("rpc/auth/denied/missing", nil)
+	authInvalidCounter   = metrics.NewRegisteredCounter("rpc/auth/denied/invalid", nil)
+	authForbiddenCounter = metrics.NewRegisteredCounter("rpc/auth/denied/forbidden", nil)
 )
 
 // updateServeTimeHistogram tracks the serving time of a remote RPC call.
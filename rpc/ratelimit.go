@@ -0,0 +1,454 @@
+// Copyright (C) 2019-2025, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package rpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"net/netip"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/MetalBlockchain/libevm/common/lru"
+	"github.com/MetalBlockchain/libevm/metrics"
+)
+
+const (
+	// RateLimitClassIP is the class of the clients identified by their IP
+	// address, i.e. those not presenting a key of a configured class.
+	RateLimitClassIP = "ip"
+
+	// rateLimitWildcard matches any suffix when ending a method cost key.
+	rateLimitWildcard = "*"
+
+	// rateLimitSweepInterval is the minimum interval between the removals of
+	// the buckets of idle clients.
+	rateLimitSweepInterval = time.Minute
+
+	// DefaultRateLimitIPv6PrefixLength is the default length of the prefix of
+	// the IPv6 addresses identifying a client, as a single host is commonly
+	// assigned a whole /64.
+	DefaultRateLimitIPv6PrefixLength = 64
+
+	// DefaultRateLimitMaxClients is the default maximum number of clients
+	// tracked at once.
+	DefaultRateLimitMaxClients = 100_000
+)
+
+var (
+	errRateLimitRate  = errors.New("rate limit refill rate must be positive")
+	errRateLimitBurst = errors.New("rate limit max stored must be positive")
+	errRateLimitClass = errors.New("invalid rate limit class")
+	errRateLimitProxy = errors.New("invalid rate limit trusted proxy")
+	errRateLimitIPv6  = errors.New("invalid rate limit IPv6 prefix length")
+	errRateLimitMax   = errors.New("rate limit max clients must not be negative")
+)
+
+// DefaultRateLimitCosts are the costs of the calls that are more expensive to
+// serve than most. Other calls cost 1.
+var DefaultRateLimitCosts = map[string]float64{
+	"eth_call":             5,
+	"eth_estimateGas":      5,
+	"eth_createAccessList": 5,
+	"eth_getLogs":          20,
+	"eth_getFilterLogs":    20,
+	"debug_trace*":         50,
+}
+
+// RateLimitClass is a class of clients identified by a key, with their own
+// limits.
+type RateLimitClass struct {
+	// Name identifies the class in metrics.
+	Name string
+	// Keys are the values of the key header identifying the clients of the
+	// class. Each key is limited separately.
+	Keys []string
+	// Rate and Burst are the cost credited to each client per second, and
+	// the maximum cost a client can accumulate.
+	Rate  float64
+	Burst float64
+}
+
+// RateLimitConfig configures per-client rate limiting of the calls served
+// over HTTP by a Server. Each client is credited a cost per second, up to a
+// maximum, and each call consumes the cost of its method.
+type RateLimitConfig struct {
+	// Rate and Burst are the cost credited to each client identified by its
+	// IP address per second, and the maximum cost it can accumulate.
+	Rate  float64
+	Burst float64
+	// KeyHeader is the header presenting the key of a client of one of the
+	// Classes. Clients presenting no key, or an unknown one, are identified by
+	// their IP address.
+	KeyHeader string
+	Classes   []RateLimitClass
+	// TrustedProxies are the IP addresses or CIDR ranges of the proxies in
+	// front of the server. The requests they forward are identified by the
+	// last address of the X-Forwarded-For header not of a trusted proxy. If
+	// empty, clients are identified by the address of the connection, so
+	// the limiter must not be behind a proxy.
+	TrustedProxies []string
+	// IPv6PrefixLength is the length of the prefix of the IPv6 addresses
+	// identifying a client, so that a client cannot obtain fresh credit by
+	// rotating through the addresses assigned to it. Defaults to
+	// DefaultRateLimitIPv6PrefixLength if 0.
+	IPv6PrefixLength int
+	// MaxClients is the maximum number of clients tracked at once. Once
+	// reached, the least recently seen client is forgotten, and is credited
+	// the maximum again if it returns. Defaults to DefaultRateLimitMaxClients
+	// if 0.
+	MaxClients int
+	// MethodCosts are the costs of methods overriding DefaultRateLimitCosts.
+	// A key ending with "*" sets the cost of every method with its prefix,
+	// e.g. "debug_*". The longest matching key applies.
+	MethodCosts map[string]float64
+}
+
+// SetRateLimit limits the cost of the calls each client can make over HTTP
+// according to [config].
+//
+// This method should be called before processing any requests via ServeHTTP.
+func (s *Server) SetRateLimit(config RateLimitConfig) error {
+	limiter, err := newRateLimiter(config)
+	if err != nil {
+		return err
+	}
+	s.rateLimit = limiter
+	return nil
+}
+
+// rateLimitClass are the limits and metrics of a class of clients.
+type rateLimitClass struct {
+	name  string
+	rate  float64
+	burst float64
+
+	calls   metrics.Counter
+	cost    metrics.Counter
+	limited metrics.Counter
+}
+
+func newRateLimitClass(name string, rate, burst float64) (*rateLimitClass, error) {
+	switch {
+	case rate <= 0:
+		return nil, fmt.Errorf("%w of class %q", errRateLimitRate, name)
+	case burst <= 0:
+		return nil, fmt.Errorf("%w of class %q", errRateLimitBurst, name)
+	}
+	prefix := "rpc/ratelimit/" + name
+	return &rateLimitClass{
+		name:    name,
+		rate:    rate,
+		burst:   burst,
+		calls:   metrics.GetOrRegisterCounter(prefix+"/calls", nil),
+		cost:    metrics.GetOrRegisterCounter(prefix+"/cost", nil),
+		limited: metrics.GetOrRegisterCounter(prefix+"/limited", nil),
+	}, nil
+}
+
+// methodCost is the cost of the methods starting with prefix.
+type methodCost struct {
+	prefix string
+	cost   float64
+}
+
+// tokenBucket is the cost a client of class can consume.
+type tokenBucket struct {
+	class   *rateLimitClass
+	tokens  float64
+	updated time.Time
+}
+
+// rateLimiter tracks the cost each client can consume.
+type rateLimiter struct {
+	keyHeader  string
+	proxies    []netip.Prefix
+	ipv6Prefix int
+	ip         *rateLimitClass
+	keys       map[string]*rateLimitClass
+	costs      map[string]float64
+	prefixes   []methodCost // sorted by decreasing prefix length
+	now        func() time.Time
+
+	lock      sync.Mutex
+	buckets   lru.BasicLRU[string, *tokenBucket] // by client, least recently seen first
+	lastSweep time.Time
+}
+
+func newRateLimiter(config RateLimitConfig) (*rateLimiter, error) {
+	ip, err := newRateLimitClass(RateLimitClassIP, config.Rate, config.Burst)
+	if err != nil {
+		return nil, err
+	}
+	ipv6Prefix := config.IPv6PrefixLength
+	switch {
+	case ipv6Prefix == 0:
+		ipv6Prefix = DefaultRateLimitIPv6PrefixLength
+	case ipv6Prefix < 0 || ipv6Prefix > 128:
+		return nil, fmt.Errorf("%w %d: must be in the range [1, 128]", errRateLimitIPv6, ipv6Prefix)
+	}
+	maxClients := config.MaxClients
+	switch {
+	case maxClients == 0:
+		maxClients = DefaultRateLimitMaxClients
+	case maxClients < 0:
+		return nil, fmt.Errorf("%w: %d", errRateLimitMax, maxClients)
+	}
+	l := &rateLimiter{
+		keyHeader:  config.KeyHeader,
+		ipv6Prefix: ipv6Prefix,
+		ip:         ip,
+		keys:       make(map[string]*rateLimitClass),
+		costs:      make(map[string]float64),
+		now:        time.Now,
+		buckets:    lru.NewBasicLRU[string, *tokenBucket](maxClients),
+	}
+	for _, proxy := range config.TrustedProxies {
+		prefix, err := parseTrustedProxy(proxy)
+		if err != nil {
+			return nil, err
+		}
+		l.proxies = append(l.proxies, prefix)
+	}
+	for _, c := range config.Classes {
+		if c.Name == "" || c.Name == RateLimitClassIP {
+			return nil, fmt.Errorf("%w: reserved name %q", errRateLimitClass, c.Name)
+		}
+		if len(c.Keys) > 0 && config.KeyHeader == "" {
+			return nil, fmt.Errorf("%w: class %q has keys but no key header is set", errRateLimitClass, c.Name)
+		}
+		class, err := newRateLimitClass(c.Name, c.Rate, c.Burst)
+		if err != nil {
+			return nil, err
+		}
+		for _, key := range c.Keys {
+			if _, ok := l.keys[key]; ok {
+				return nil, fmt.Errorf("%w: key of class %q is used by another class", errRateLimitClass, c.Name)
+			}
+			l.keys[key] = class
+		}
+	}
+
+	costs := make(map[string]float64, len(DefaultRateLimitCosts)+len(config.MethodCosts))
+	for method, cost := range DefaultRateLimitCosts {
+		costs[method] = cost
+	}
+	for method, cost := range config.MethodCosts {
+		if cost < 0 {
+			return nil, fmt.Errorf("negative rate limit cost %f of %s", cost, method)
+		}
+		costs[method] = cost
+	}
+	for method, cost := range costs {
+		if prefix, ok := strings.CutSuffix(method, rateLimitWildcard); ok {
+			l.prefixes = append(l.prefixes, methodCost{prefix: prefix, cost: cost})
+		} else {
+			l.costs[method] = cost
+		}
+	}
+	sort.Slice(l.prefixes, func(i, j int) bool {
+		return len(l.prefixes[i].prefix) > len(l.prefixes[j].prefix)
+	})
+	return l, nil
+}
+
+// cost returns the cost of a call to [method].
+func (l *rateLimiter) cost(method string) float64 {
+	if cost, ok := l.costs[method]; ok {
+		return cost
+	}
+	for _, c := range l.prefixes {
+		if strings.HasPrefix(method, c.prefix) {
+			return c.cost
+		}
+	}
+	return 1
+}
+
+// client returns the client making the HTTP request [r].
+func (l *rateLimiter) client(r *http.Request) *rateLimitClient {
+	if l.keyHeader != "" {
+		key := r.Header.Get(l.keyHeader)
+		if class, ok := l.keys[key]; ok {
+			return &rateLimitClient{limiter: l, class: class, id: class.name + "/" + key}
+		}
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	return &rateLimitClient{limiter: l, class: l.ip, id: RateLimitClassIP + "/" + l.address(l.forwardedFor(host, r.Header))}
+}
+
+// address returns the identifier of the client at [host]. IPv6 addresses are
+// truncated to their prefix, as a host can use any address of its prefix.
+func (l *rateLimiter) address(host string) string {
+	addr, err := netip.ParseAddr(host)
+	if err != nil {
+		return host
+	}
+	addr = addr.Unmap()
+	if addr.Is4() {
+		return addr.String()
+	}
+	prefix, err := addr.Prefix(l.ipv6Prefix)
+	if err != nil {
+		return host
+	}
+	return prefix.String()
+}
+
+// forwardedFor returns the address of the client whose request was received
+// from [host]. If [host] is a trusted proxy, the X-Forwarded-For header is
+// read from the last address, which was appended by [host], skipping the
+// addresses of trusted proxies. Addresses before the first untrusted one may
+// be set by the client, so they are ignored.
+func (l *rateLimiter) forwardedFor(host string, header http.Header) string {
+	if !l.trusted(host) {
+		return host
+	}
+	var forwarded []string
+	for _, value := range header.Values("X-Forwarded-For") {
+		forwarded = append(forwarded, strings.Split(value, ",")...)
+	}
+	for i := len(forwarded) - 1; i >= 0; i-- {
+		addr := strings.TrimSpace(forwarded[i])
+		if !l.trusted(addr) {
+			return addr
+		}
+		host = addr
+	}
+	return host
+}
+
+// trusted returns whether [host] is the address of a trusted proxy.
+func (l *rateLimiter) trusted(host string) bool {
+	addr, err := netip.ParseAddr(host)
+	if err != nil {
+		return false
+	}
+	addr = addr.Unmap()
+	for _, proxy := range l.proxies {
+		if proxy.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseTrustedProxy parses an IP address or CIDR range of trusted proxies.
+func parseTrustedProxy(proxy string) (netip.Prefix, error) {
+	if strings.Contains(proxy, "/") {
+		prefix, err := netip.ParsePrefix(proxy)
+		if err != nil {
+			return netip.Prefix{}, fmt.Errorf("%w %q: %w", errRateLimitProxy, proxy, err)
+		}
+		return prefix.Masked(), nil
+	}
+	addr, err := netip.ParseAddr(proxy)
+	if err != nil {
+		return netip.Prefix{}, fmt.Errorf("%w %q: %w", errRateLimitProxy, proxy, err)
+	}
+	addr = addr.Unmap()
+	return netip.PrefixFrom(addr, addr.BitLen()), nil
+}
+
+// take consumes [cost] from the bucket of the client [id] of [class]. If the
+// bucket does not hold enough, the time until it will is returned. A cost
+// exceeding the maximum stored by the class consumes the whole bucket.
+func (l *rateLimiter) take(class *rateLimitClass, id string, cost float64) (time.Duration, bool) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	now := l.now()
+	l.sweep(now)
+
+	bucket, ok := l.buckets.Get(id)
+	if !ok {
+		// Evicts the least recently seen client if too many are tracked.
+		bucket = &tokenBucket{class: class, tokens: class.burst, updated: now}
+		l.buckets.Add(id, bucket)
+	}
+	bucket.tokens = min(class.burst, bucket.tokens+now.Sub(bucket.updated).Seconds()*class.rate)
+	bucket.updated = now
+
+	cost = min(cost, class.burst)
+	if bucket.tokens < cost {
+		missing := (cost - bucket.tokens) / class.rate
+		return time.Duration(math.Ceil(missing * float64(time.Second))), false
+	}
+	bucket.tokens -= cost
+	return 0, true
+}
+
+// sweep removes the buckets of the clients which have been idle long enough
+// to be refilled, as they are recreated full.
+//
+// Assumes the lock is held.
+func (l *rateLimiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < rateLimitSweepInterval {
+		return
+	}
+	l.lastSweep = now
+	for _, id := range l.buckets.Keys() {
+		bucket, _ := l.buckets.Peek(id)
+		if bucket.tokens+now.Sub(bucket.updated).Seconds()*bucket.class.rate >= bucket.class.burst {
+			l.buckets.Remove(id)
+		}
+	}
+}
+
+// rateLimitClient is the client of an HTTP request. It is attached to the
+// context of every call of the request.
+type rateLimitClient struct {
+	limiter *rateLimiter
+	class   *rateLimitClass
+	id      string
+
+	lock       sync.Mutex
+	retryAfter time.Duration // longest wait of the calls of the request rejected so far
+}
+
+// allow returns an error if the client cannot afford a call to [method], and
+// records the call in the metrics of its class.
+func (c *rateLimitClient) allow(method string) error {
+	cost := c.limiter.cost(method)
+	c.class.calls.Inc(1)
+	retryAfter, ok := c.limiter.take(c.class, c.id, cost)
+	if !ok {
+		c.class.limited.Inc(1)
+		c.lock.Lock()
+		c.retryAfter = max(c.retryAfter, retryAfter)
+		c.lock.Unlock()
+		return &rateLimitedError{method: method, retryAfter: retryAfter}
+	}
+	c.class.cost.Inc(int64(math.Ceil(cost)))
+	return nil
+}
+
+// setRetryAfter sets the Retry-After header of the response to the request,
+// in seconds, if any of its calls was rejected.
+func (c *rateLimitClient) setRetryAfter(header http.Header) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if c.retryAfter > 0 {
+		header.Set("Retry-After", strconv.FormatInt(int64(math.Ceil(c.retryAfter.Seconds())), 10))
+	}
+}
+
+type rateLimitContextKey struct{}
+
+// rateLimitFromContext returns the client a call was made by, or nil if the
+// server does not limit the rate of the calls made over the connection.
+func rateLimitFromContext(ctx context.Context) *rateLimitClient {
+	c, _ := ctx.Value(rateLimitContextKey{}).(*rateLimitClient)
+	return c
+}
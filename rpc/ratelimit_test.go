@@ -0,0 +1,321 @@
+// Copyright (C) 2019-2025, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package rpc
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRateLimiterCost(t *testing.T) {
+	limiter, err := newRateLimiter(RateLimitConfig{
+		Rate:  1,
+		Burst: 10,
+		MethodCosts: map[string]float64{
+			"eth_getLogs":              100,
+			"debug_*":                  10,
+			"debug_traceTransaction":   0,
+			"debug_traceBlockByNumber": 2,
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := map[string]float64{
+		"eth_blockNumber":          1,
+		"eth_call":                 DefaultRateLimitCosts["eth_call"],
+		"eth_getLogs":              100,
+		"debug_traceTransaction":   0,
+		"debug_traceBlockByNumber": 2,
+		"debug_traceCall":          DefaultRateLimitCosts["debug_trace*"],
+		"debug_getRawBlock":        10,
+	}
+	for method, want := range tests {
+		if got := limiter.cost(method); got != want {
+			t.Errorf("wrong cost of %s: got %f, want %f", method, got, want)
+		}
+	}
+}
+
+func TestRateLimiterTake(t *testing.T) {
+	limiter, err := newRateLimiter(RateLimitConfig{Rate: 2, Burst: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Unix(1_700_000_000, 0)
+	limiter.now = func() time.Time { return now }
+
+	take := func(id string, cost float64, wantOK bool, wantRetry time.Duration) {
+		t.Helper()
+		retry, ok := limiter.take(limiter.ip, id, cost)
+		if ok != wantOK || retry != wantRetry {
+			t.Fatalf("take %f by %s: got (%s, %t), want (%s, %t)", cost, id, retry, ok, wantRetry, wantOK)
+		}
+	}
+	take("a", 8, true, 0)
+	take("a", 3, false, 500*time.Millisecond)
+	take("b", 10, true, 0) // Clients are limited separately
+	now = now.Add(time.Second)
+	take("a", 3, true, 0)
+	// A cost above the maximum stored consumes the whole bucket.
+	now = now.Add(time.Hour)
+	take("a", 100, true, 0)
+	take("a", 1, false, 500*time.Millisecond)
+
+	// The buckets of idle clients are removed.
+	now = now.Add(time.Hour)
+	take("c", 1, true, 0)
+	if n := limiter.buckets.Len(); n != 1 {
+		t.Fatalf("wrong number of tracked clients: got %d, want 1", n)
+	}
+}
+
+func TestRateLimiterMaxClients(t *testing.T) {
+	limiter, err := newRateLimiter(RateLimitConfig{Rate: 1, Burst: 10, MaxClients: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Unix(1_700_000_000, 0)
+	limiter.now = func() time.Time { return now }
+
+	take := func(id string, cost float64, wantOK bool) {
+		t.Helper()
+		if _, ok := limiter.take(limiter.ip, id, cost); ok != wantOK {
+			t.Fatalf("take %f by %s: got %t, want %t", cost, id, ok, wantOK)
+		}
+	}
+	take("a", 10, true)
+	take("b", 10, true)
+	take("a", 1, false) // a is now the most recently seen client
+	take("c", 10, true) // evicts b
+	if n := limiter.buckets.Len(); n != 2 {
+		t.Fatalf("wrong number of tracked clients: got %d, want 2", n)
+	}
+	take("a", 1, false)
+	take("c", 1, false)
+	// The evicted client is credited the maximum again.
+	take("b", 10, true)
+}
+
+func TestRateLimiterClient(t *testing.T) {
+	limiter, err := newRateLimiter(RateLimitConfig{
+		Rate:           1,
+		Burst:          1,
+		TrustedProxies: []string{"10.0.0.0/8", "192.0.2.1"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := map[string]struct {
+		remoteAddr string
+		forwarded  []string
+		want       string
+	}{
+		"direct": {
+			remoteAddr: "198.51.100.1:1234",
+			want:       "ip/198.51.100.1",
+		},
+		"forwarded by untrusted host": {
+			remoteAddr: "198.51.100.1:1234",
+			forwarded:  []string{"203.0.113.1"},
+			want:       "ip/198.51.100.1",
+		},
+		"forwarded by trusted proxy": {
+			remoteAddr: "10.0.0.1:1234",
+			forwarded:  []string{"203.0.113.1"},
+			want:       "ip/203.0.113.1",
+		},
+		"forwarded by trusted proxies": {
+			remoteAddr: "10.0.0.1:1234",
+			forwarded:  []string{"203.0.113.1, 192.0.2.1", "10.1.2.3"},
+			want:       "ip/203.0.113.1",
+		},
+		"spoofed addresses are ignored": {
+			remoteAddr: "10.0.0.1:1234",
+			forwarded:  []string{"10.9.9.9, 203.0.113.1"},
+			want:       "ip/203.0.113.1",
+		},
+		"only trusted proxies": {
+			remoteAddr: "10.0.0.1:1234",
+			forwarded:  []string{"10.0.0.2"},
+			want:       "ip/10.0.0.2",
+		},
+		"trusted proxy without header": {
+			remoteAddr: "192.0.2.1:1234",
+			want:       "ip/192.0.2.1",
+		},
+		"ipv4-mapped ipv6": {
+			remoteAddr: "[::ffff:198.51.100.1]:1234",
+			want:       "ip/198.51.100.1",
+		},
+		"ipv6": {
+			remoteAddr: "[2001:db8::1]:1234",
+			want:       "ip/2001:db8::/64",
+		},
+		"ipv6 in the same prefix": {
+			remoteAddr: "[2001:db8::ffff:ffff:ffff:ffff]:1234",
+			want:       "ip/2001:db8::/64",
+		},
+		"ipv6 with zone": {
+			remoteAddr: "[fe80::1%eth0]:1234",
+			want:       "ip/fe80::/64",
+		},
+		"forwarded ipv6": {
+			remoteAddr: "10.0.0.1:1234",
+			forwarded:  []string{"2001:db8:0:1::5"},
+			want:       "ip/2001:db8:0:1::/64",
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			r := httptest.NewRequest("POST", "/", nil)
+			r.RemoteAddr = test.remoteAddr
+			for _, value := range test.forwarded {
+				r.Header.Add("X-Forwarded-For", value)
+			}
+			if got := limiter.client(r).id; got != test.want {
+				t.Fatalf("wrong client: got %s, want %s", got, test.want)
+			}
+		})
+	}
+}
+
+func TestRateLimiterIPv6PrefixLength(t *testing.T) {
+	limiter, err := newRateLimiter(RateLimitConfig{Rate: 1, Burst: 1, IPv6PrefixLength: 48})
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := httptest.NewRequest("POST", "/", nil)
+	r.RemoteAddr = "[2001:db8:1:2::1]:1234"
+	if got, want := limiter.client(r).id, "ip/2001:db8:1::/48"; got != want {
+		t.Fatalf("wrong client: got %s, want %s", got, want)
+	}
+}
+
+func TestSetRateLimitInvalid(t *testing.T) {
+	tests := map[string]RateLimitConfig{
+		"zero rate":         {Burst: 1},
+		"zero burst":        {Rate: 1},
+		"reserved class":    {Rate: 1, Burst: 1, Classes: []RateLimitClass{{Name: RateLimitClassIP, Rate: 1, Burst: 1}}},
+		"keys without name": {Rate: 1, Burst: 1, KeyHeader: "X-Api-Key", Classes: []RateLimitClass{{Keys: []string{"k"}, Rate: 1, Burst: 1}}},
+		"keys without header": {
+			Rate: 1, Burst: 1,
+			Classes: []RateLimitClass{{Name: "partner", Keys: []string{"k"}, Rate: 1, Burst: 1}},
+		},
+		"duplicate key": {
+			Rate: 1, Burst: 1, KeyHeader: "X-Api-Key",
+			Classes: []RateLimitClass{
+				{Name: "a", Keys: []string{"k"}, Rate: 1, Burst: 1},
+				{Name: "b", Keys: []string{"k"}, Rate: 1, Burst: 1},
+			},
+		},
+		"negative cost":        {Rate: 1, Burst: 1, MethodCosts: map[string]float64{"eth_call": -1}},
+		"invalid proxy":        {Rate: 1, Burst: 1, TrustedProxies: []string{"10.0.0.0/33"}},
+		"negative ipv6 prefix": {Rate: 1, Burst: 1, IPv6PrefixLength: -1},
+		"ipv6 prefix too long": {Rate: 1, Burst: 1, IPv6PrefixLength: 129},
+		"negative max clients": {Rate: 1, Burst: 1, MaxClients: -1},
+	}
+	for name, config := range tests {
+		t.Run(name, func(t *testing.T) {
+			if err := NewServer(0).SetRateLimit(config); err == nil {
+				t.Fatal("expected error")
+			}
+		})
+	}
+}
+
+func TestHTTPRateLimit(t *testing.T) {
+	server := newTestServer()
+	defer server.Stop()
+	err := server.SetRateLimit(RateLimitConfig{
+		Rate:      0.001,
+		Burst:     3,
+		KeyHeader: "X-Api-Key",
+		Classes: []RateLimitClass{
+			{Name: "partner", Keys: []string{"secret"}, Rate: 0.001, Burst: 5},
+		},
+		MethodCosts: map[string]float64{"test_sleep": 3},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	httpsrv := httptest.NewServer(server)
+	defer httpsrv.Close()
+
+	call := func(t *testing.T, key string, method string, args ...interface{}) error {
+		t.Helper()
+		var opts []ClientOption
+		if key != "" {
+			opts = append(opts, WithHeader("X-Api-Key", key))
+		}
+		client, err := DialOptions(context.Background(), httpsrv.URL, opts...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer client.Close()
+		return client.Call(nil, method, args...)
+	}
+	checkLimited := func(t *testing.T, err error) {
+		t.Helper()
+		var rpcErr Error
+		if !errors.As(err, &rpcErr) || rpcErr.ErrorCode() != errcodeRateLimited {
+			t.Fatalf("wrong error: got %v, want code %d", err, errcodeRateLimited)
+		}
+		var dataErr DataError
+		if !errors.As(err, &dataErr) {
+			t.Fatalf("error %v has no data", err)
+		}
+		data, ok := dataErr.ErrorData().(map[string]interface{})
+		if !ok || data["retryAfter"] == nil {
+			t.Fatalf("wrong error data: %#v", dataErr.ErrorData())
+		}
+	}
+	retryAfter := func(t *testing.T, key string) string {
+		t.Helper()
+		req, err := http.NewRequest(http.MethodPost, httpsrv.URL, strings.NewReader(`{"jsonrpc":"2.0","id":1,"method":"test_echo","params":["hello",1]}`))
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.Header.Set("Content-Type", "application/json")
+		if key != "" {
+			req.Header.Set("X-Api-Key", key)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		return resp.Header.Get("Retry-After")
+	}
+
+	t.Run("ip", func(t *testing.T) {
+		for i := 0; i < 3; i++ {
+			if err := call(t, "", "test_echo", "hello", 1); err != nil {
+				t.Fatal(err)
+			}
+		}
+		checkLimited(t, call(t, "", "test_echo", "hello", 1))
+		if got := retryAfter(t, ""); got == "" || got == "0" {
+			t.Fatalf("wrong Retry-After header: %q", got)
+		}
+	})
+	t.Run("unknown key", func(t *testing.T) {
+		// Unknown keys are limited by IP address.
+		checkLimited(t, call(t, "unknown", "test_echo", "hello", 1))
+	})
+	t.Run("class", func(t *testing.T) {
+		if got := retryAfter(t, "secret"); got != "" {
+			t.Fatalf("unexpected Retry-After header: %q", got)
+		}
+		if err := call(t, "secret", "test_sleep", time.Millisecond); err != nil {
+			t.Fatal(err)
+		}
+		checkLimited(t, call(t, "secret", "test_sleep", time.Millisecond))
+	})
+}
@@ -64,6 +64,8 @@ type Server struct {
 	batchItemLimit     int
 	batchResponseLimit int
 	httpBodyLimit      int
+	auth               *authPolicy  // nil if authentication is not required
+	rateLimit          *rateLimiter // nil if HTTP calls are not rate limited
 }
 
 // NewServer creates a new server instance with no registered handlers.
@@ -76,6 +76,19 @@ func (s *Server) WebsocketHandlerWithDuration(allowedOrigins []string, apiMaxDur
 			return
 		}
 		codec := newWebsocketCodec(conn, r.Host, r.Header, wsDefaultReadLimit)
+		if s.auth != nil {
+			auth := s.auth.authenticate(r.Header, time.Now())
+			codec.(*websocketCodec).auth = auth
+			// Calls made after the token expires are rejected, but
+			// subscriptions would keep delivering notifications, so the
+			// connection is closed once the token expires.
+			if auth.claims != nil {
+				if expiry, ok := auth.claims.expiry(); ok {
+					timer := time.AfterFunc(time.Until(expiry), codec.close)
+					defer timer.Stop()
+				}
+			}
+		}
 		s.ServeCodec(codec, 0, apiMaxDuration, refillRate, maxStored)
 	})
 }
@@ -296,6 +309,7 @@ type websocketCodec struct {
 	*jsonCodec
 	conn *websocket.Conn
 	info PeerInfo
+	auth *authorization // nil if the server does not require authentication
 
 	wg           sync.WaitGroup
 	pingReset    chan struct{}
@@ -343,6 +357,10 @@ func (wc *websocketCodec) peerInfo() PeerInfo {
 	return wc.info
 }
 
+func (wc *websocketCodec) authorization() *authorization {
+	return wc.auth
+}
+
 func (wc *websocketCodec) writeJSON(ctx context.Context, v interface{}, isError bool) error {
 	return wc.writeJSONSkipDeadline(ctx, v, isError, false)
 }
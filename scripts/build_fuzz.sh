@@ -0,0 +1,26 @@
+#!/usr/bin/env bash
+
+set -o errexit
+set -o nounset
+set -o pipefail
+
+# Avalanche root directory
+CORETH_PATH=$(
+  cd "$(dirname "${BASH_SOURCE[0]}")"
+  cd .. && pwd
+)
+
+# Load the constants
+source "$CORETH_PATH"/scripts/constants.sh
+
+# Runs every fuzz target for FUZZ_TIME. The seed corpora of the targets are
+# already run as regular tests by build_test.sh.
+fuzz_time="${FUZZ_TIME:-10s}"
+
+for pkg in $(go list ./... | grep -v github.com/MetalBlockchain/coreth/tests); do
+    dir=$(go list -f '{{.Dir}}' "$pkg")
+    for target in $(grep -ho '^func Fuzz[A-Za-z0-9_]*' "$dir"/*_test.go 2>/dev/null | sed 's/^func //' | sort -u); do
+        echo "Fuzzing ${pkg} ${target} for ${fuzz_time}"
+        go test -run='^$' -fuzz="^${target}\$" -fuzztime="${fuzz_time}" "$pkg"
+    done
+done
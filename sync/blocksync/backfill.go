@@ -0,0 +1,401 @@
+// Copyright (C) 2019-2025, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package blocksync
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/MetalBlockchain/libevm/common"
+	"github.com/MetalBlockchain/libevm/core/rawdb"
+	"github.com/MetalBlockchain/libevm/core/types"
+	"github.com/MetalBlockchain/libevm/ethdb"
+	"github.com/MetalBlockchain/libevm/log"
+	"github.com/MetalBlockchain/libevm/trie"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/MetalBlockchain/coreth/plugin/evm/customrawdb"
+	"github.com/MetalBlockchain/coreth/plugin/evm/message"
+
+	statesyncclient "github.com/MetalBlockchain/coreth/sync/client"
+)
+
+const (
+	// DefaultBackfillParallelism is the default number of segments of
+	// [blocksPerRequest] blocks fetched concurrently.
+	DefaultBackfillParallelism = 8
+	// DefaultBackfillRequestTimeout is the default timeout of a single
+	// request, after which it is retried.
+	DefaultBackfillRequestTimeout = 30 * time.Second
+	// DefaultBackfillMaxRetries is the default number of times a segment is
+	// fetched again after failing to be fetched or verified.
+	DefaultBackfillMaxRetries = 10
+
+	// The delay before retrying a segment doubles with every attempt, from
+	// backfillMinRetryInterval up to backfillMaxRetryInterval.
+	backfillMinRetryInterval = time.Second
+	backfillMaxRetryInterval = time.Minute
+	backfillLogInterval      = 30 * time.Second
+)
+
+var (
+	errBlockMismatch    = errors.New("backfilled block does not match its child")
+	errInvalidBody      = errors.New("backfilled block body does not match its header")
+	errInvalidReceipts  = errors.New("backfilled receipts do not match the block header")
+	errMissingFromBlock = errors.New("block to backfill the ancestors of is missing")
+	errTooManyRetries   = errors.New("too many backfill retries")
+)
+
+// BackfillConfig configures a [Backfiller].
+type BackfillConfig struct {
+	// FromHash and FromHeight identify the trusted block whose ancestors are
+	// backfilled, which is the block the node state synced to.
+	FromHash   common.Hash
+	FromHeight uint64
+	// ToHeight is the height of the oldest block to backfill.
+	ToHeight uint64
+	// Parallelism is the number of segments of blocks fetched concurrently.
+	Parallelism int
+	// RequestTimeout is the timeout of a single request, after which it is
+	// retried.
+	RequestTimeout time.Duration
+	// MaxRetries is the number of times a segment is fetched again after
+	// failing to be fetched or verified, before the backfill fails.
+	MaxRetries int
+	// TxIndexFrom is the height of the oldest block whose transactions are
+	// indexed. Blocks below it are not indexed.
+	TxIndexFrom uint64
+	// VerifyBlock, if non-nil, is called to verify each block fetched from
+	// peers, in addition to the verification of its hash and body.
+	VerifyBlock func(*types.Block) error
+}
+
+// Backfiller downloads the blocks and receipts preceding a state sync, so
+// that the node holds the full history down to [BackfillConfig.ToHeight].
+//
+// Blocks are fetched by height in segments, concurrently from multiple
+// peers, and are only written once they are verified to chain to the
+// trusted block by their hashes. Progress is recorded with each segment, so
+// the backfill resumes where it left off after a restart.
+type Backfiller struct {
+	client statesyncclient.Client
+	db     ethdb.Database
+	config BackfillConfig
+
+	minRetryInterval time.Duration
+	maxRetryInterval time.Duration
+}
+
+func NewBackfiller(client statesyncclient.Client, db ethdb.Database, config BackfillConfig) *Backfiller {
+	if config.Parallelism <= 0 {
+		config.Parallelism = DefaultBackfillParallelism
+	}
+	if config.RequestTimeout <= 0 {
+		config.RequestTimeout = DefaultBackfillRequestTimeout
+	}
+	if config.MaxRetries <= 0 {
+		config.MaxRetries = DefaultBackfillMaxRetries
+	}
+	return &Backfiller{
+		client:           client,
+		db:               db,
+		config:           config,
+		minRetryInterval: backfillMinRetryInterval,
+		maxRetryInterval: backfillMaxRetryInterval,
+	}
+}
+
+// segment holds the blocks from height hi down to height lo, and their
+// receipts.
+type segment struct {
+	lo, hi   uint64
+	blocks   []*types.Block // ordered from hi to lo
+	receipts []types.Receipts
+
+	// localBlocks and localReceipts are the number of leading blocks, and
+	// which receipts, that were read from disk rather than fetched.
+	localBlocks   int
+	localReceipts []bool
+}
+
+// Run backfills blocks until [BackfillConfig.ToHeight] is reached or [ctx]
+// is cancelled. Returns an error wrapping [errTooManyRetries] if a segment
+// still cannot be fetched or verified after [BackfillConfig.MaxRetries]
+// retries.
+func (b *Backfiller) Run(ctx context.Context) error {
+	expectedHash, next, done, err := b.start()
+	if err != nil || done {
+		return err
+	}
+	log.Info("starting block backfill", "from", next, "to", b.config.ToHeight)
+
+	var (
+		startTime = time.Now()
+		lastLog   = startTime
+		total     = next - b.config.ToHeight + 1
+		// failures is the number of consecutive times the segment from
+		// [next] failed to be verified.
+		failures int
+	)
+	for {
+		segments, err := b.fetchSegments(ctx, next)
+		if err != nil {
+			return err
+		}
+		for _, seg := range segments {
+			if err := b.verifySegment(seg, expectedHash); err != nil {
+				failures++
+				if failures > b.config.MaxRetries {
+					return fmt.Errorf("%w: failed to verify blocks %d-%d: %w", errTooManyRetries, seg.lo, seg.hi, err)
+				}
+				// Refetch the segment, and those after it, from other peers.
+				log.Warn("failed to verify backfilled blocks", "lo", seg.lo, "hi", seg.hi, "attempt", failures, "err", err)
+				if err := b.waitRetry(ctx, failures); err != nil {
+					return err
+				}
+				break
+			}
+			failures = 0
+			if err := b.writeSegment(seg); err != nil {
+				return err
+			}
+			lowest := seg.blocks[len(seg.blocks)-1]
+			if seg.lo == b.config.ToHeight {
+				log.Info("completed block backfill", "to", b.config.ToHeight, "duration", time.Since(startTime))
+				return nil
+			}
+			expectedHash, next = lowest.ParentHash(), seg.lo-1
+		}
+		if time.Since(lastLog) > backfillLogInterval {
+			log.Info("backfilling blocks", "height", next, "remaining", next-b.config.ToHeight+1, "total", total)
+			lastLog = time.Now()
+		}
+	}
+}
+
+// start returns the hash and height of the next block to backfill, or true
+// if the backfill is already complete.
+func (b *Backfiller) start() (common.Hash, uint64, bool, error) {
+	if b.config.FromHeight < b.config.ToHeight {
+		return common.Hash{}, 0, true, nil
+	}
+	progress, err := customrawdb.ReadBlockBackfillProgress(b.db)
+	if err != nil {
+		return common.Hash{}, 0, false, fmt.Errorf("failed to read block backfill progress: %w", err)
+	}
+	if progress == nil || progress.SyncHeight != b.config.FromHeight {
+		// Start over, as the node state synced again since the progress was
+		// recorded.
+		return b.config.FromHash, b.config.FromHeight, false, nil
+	}
+	if progress.Height <= b.config.ToHeight {
+		return common.Hash{}, 0, true, nil
+	}
+	header := rawdb.ReadHeader(b.db, progress.Hash, progress.Height)
+	if header == nil {
+		return common.Hash{}, 0, false, fmt.Errorf("%w: %s (%d)", errMissingFromBlock, progress.Hash, progress.Height)
+	}
+	return header.ParentHash, progress.Height - 1, false, nil
+}
+
+// fetchSegments concurrently fetches the segments of blocks from [next]
+// downwards. Each segment is retried up to [BackfillConfig.MaxRetries] times
+// until it is fetched or [ctx] is cancelled.
+func (b *Backfiller) fetchSegments(ctx context.Context, next uint64) ([]*segment, error) {
+	var segments []*segment
+	for i := 0; i < b.config.Parallelism; i++ {
+		hi := next
+		lo := b.config.ToHeight
+		if hi-lo >= blocksPerRequest {
+			lo = hi - blocksPerRequest + 1
+		}
+		segments = append(segments, &segment{lo: lo, hi: hi})
+		if lo == b.config.ToHeight {
+			break
+		}
+		next = lo - 1
+	}
+
+	eg, egCtx := errgroup.WithContext(ctx)
+	for _, seg := range segments {
+		eg.Go(func() error {
+			for attempt := 1; ; attempt++ {
+				err := b.fetchSegment(egCtx, seg)
+				if err == nil {
+					return nil
+				}
+				if egCtx.Err() != nil {
+					return egCtx.Err()
+				}
+				if attempt > b.config.MaxRetries {
+					return fmt.Errorf("%w: failed to fetch blocks %d-%d: %w", errTooManyRetries, seg.lo, seg.hi, err)
+				}
+				log.Debug("failed to fetch backfilled blocks, retrying", "lo", seg.lo, "hi", seg.hi, "attempt", attempt, "err", err)
+				if err := b.waitRetry(egCtx, attempt); err != nil {
+					return err
+				}
+			}
+		})
+	}
+	if err := eg.Wait(); err != nil {
+		return nil, err
+	}
+	return segments, nil
+}
+
+// waitRetry waits before the retry following the [attempt]th consecutive
+// failure, or until [ctx] is cancelled.
+func (b *Backfiller) waitRetry(ctx context.Context, attempt int) error {
+	timer := time.NewTimer(b.retryInterval(attempt))
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// retryInterval returns the delay before the retry following the [attempt]th
+// consecutive failure, which doubles with every attempt.
+func (b *Backfiller) retryInterval(attempt int) time.Duration {
+	interval := b.minRetryInterval
+	for i := 1; i < attempt && interval < b.maxRetryInterval; i++ {
+		interval *= 2
+	}
+	return min(interval, b.maxRetryInterval)
+}
+
+// fetchSegment fills [seg] with its blocks and receipts, reading those
+// already on disk and fetching the rest from peers.
+func (b *Backfiller) fetchSegment(ctx context.Context, seg *segment) error {
+	count := int(seg.hi - seg.lo + 1)
+	seg.blocks = seg.blocks[:0]
+	for height := seg.hi; len(seg.blocks) < count; height-- {
+		block := rawdb.ReadBlock(b.db, rawdb.ReadCanonicalHash(b.db, height), height)
+		if block == nil {
+			break
+		}
+		seg.blocks = append(seg.blocks, block)
+	}
+	seg.localBlocks = len(seg.blocks)
+
+	for len(seg.blocks) < count {
+		var (
+			hash   common.Hash // empty to request the canonical block at height
+			height = seg.hi
+		)
+		if len(seg.blocks) > 0 {
+			last := seg.blocks[len(seg.blocks)-1]
+			hash, height = last.ParentHash(), last.NumberU64()-1
+		}
+		reqCtx, cancel := context.WithTimeout(ctx, b.config.RequestTimeout)
+		blocks, err := b.client.GetBlocks(reqCtx, hash, height, uint16(count-len(seg.blocks)))
+		cancel()
+		if err != nil {
+			return err
+		}
+		seg.blocks = append(seg.blocks, blocks...)
+	}
+
+	seg.receipts = make([]types.Receipts, count)
+	seg.localReceipts = make([]bool, count)
+	var missing []int
+	for i, block := range seg.blocks {
+		if rawdb.HasReceipts(b.db, block.Hash(), block.NumberU64()) {
+			seg.receipts[i] = rawdb.ReadRawReceipts(b.db, block.Hash(), block.NumberU64())
+			seg.localReceipts[i] = true
+		} else {
+			missing = append(missing, i)
+		}
+	}
+	for len(missing) > 0 {
+		hashes := make([]common.Hash, 0, message.MaxReceiptsHashesPerRequest)
+		for _, i := range missing[:min(len(missing), message.MaxReceiptsHashesPerRequest)] {
+			hashes = append(hashes, seg.blocks[i].Hash())
+		}
+		reqCtx, cancel := context.WithTimeout(ctx, b.config.RequestTimeout)
+		receipts, err := b.client.GetReceipts(reqCtx, hashes)
+		cancel()
+		if err != nil {
+			return err
+		}
+		for j, blockReceipts := range receipts {
+			seg.receipts[missing[j]] = blockReceipts
+		}
+		missing = missing[len(receipts):]
+	}
+	return nil
+}
+
+// verifySegment verifies that the blocks of [seg] chain down from the block
+// with [expectedHash], and that their bodies and receipts match their
+// headers.
+func (b *Backfiller) verifySegment(seg *segment, expectedHash common.Hash) error {
+	for i, block := range seg.blocks {
+		if block.Hash() != expectedHash || block.NumberU64() != seg.hi-uint64(i) {
+			return fmt.Errorf("%w: (got %s at %d) (expected %s at %d)", errBlockMismatch, block.Hash(), block.NumberU64(), expectedHash, seg.hi-uint64(i))
+		}
+		expectedHash = block.ParentHash()
+
+		if i >= seg.localBlocks {
+			if types.DeriveSha(block.Transactions(), trie.NewStackTrie(nil)) != block.TxHash() ||
+				types.CalcUncleHash(block.Uncles()) != block.UncleHash() {
+				return fmt.Errorf("%w: %s", errInvalidBody, block.Hash())
+			}
+			if b.config.VerifyBlock != nil {
+				if err := b.config.VerifyBlock(block); err != nil {
+					return fmt.Errorf("%w: %s: %w", errInvalidBody, block.Hash(), err)
+				}
+			}
+		}
+
+		if seg.localReceipts[i] {
+			continue
+		}
+		receipts := seg.receipts[i]
+		txs := block.Transactions()
+		if len(receipts) != len(txs) {
+			return fmt.Errorf("%w: %s has %d receipts for %d transactions", errInvalidReceipts, block.Hash(), len(receipts), len(txs))
+		}
+		// The storage encoding of receipts omits their type, which is part of
+		// their consensus encoding.
+		for j, receipt := range receipts {
+			receipt.Type = txs[j].Type()
+		}
+		if root := types.DeriveSha(receipts, trie.NewStackTrie(nil)); root != block.ReceiptHash() {
+			return fmt.Errorf("%w: %s (got root %s) (expected %s)", errInvalidReceipts, block.Hash(), root, block.ReceiptHash())
+		}
+	}
+	return nil
+}
+
+// writeSegment writes the blocks and receipts of the verified [seg] to disk,
+// along with the backfill progress.
+func (b *Backfiller) writeSegment(seg *segment) error {
+	batch := b.db.NewBatch()
+	for i, block := range seg.blocks {
+		if i >= seg.localBlocks {
+			rawdb.WriteBlock(batch, block)
+			rawdb.WriteCanonicalHash(batch, block.Hash(), block.NumberU64())
+		}
+		if !seg.localReceipts[i] {
+			rawdb.WriteReceipts(batch, block.Hash(), block.NumberU64(), seg.receipts[i])
+		}
+		if block.NumberU64() >= b.config.TxIndexFrom {
+			rawdb.WriteTxLookupEntriesByBlock(batch, block)
+		}
+	}
+	lowest := seg.blocks[len(seg.blocks)-1]
+	if err := customrawdb.WriteBlockBackfillProgress(batch, &customrawdb.BlockBackfillProgress{
+		SyncHeight: b.config.FromHeight,
+		Hash:       lowest.Hash(),
+		Height:     lowest.NumberU64(),
+	}); err != nil {
+		return err
+	}
+	return batch.Write()
+}
@@ -0,0 +1,350 @@
+// Copyright (C) 2019-2025, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package blocksync
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/MetalBlockchain/libevm/common"
+	"github.com/MetalBlockchain/libevm/core/rawdb"
+	"github.com/MetalBlockchain/libevm/core/types"
+	"github.com/MetalBlockchain/libevm/crypto"
+	"github.com/MetalBlockchain/libevm/ethdb"
+	"github.com/stretchr/testify/require"
+
+	"github.com/MetalBlockchain/coreth/consensus/dummy"
+	"github.com/MetalBlockchain/coreth/core"
+	"github.com/MetalBlockchain/coreth/params"
+	"github.com/MetalBlockchain/coreth/plugin/evm/customrawdb"
+	"github.com/MetalBlockchain/coreth/plugin/evm/message"
+	"github.com/MetalBlockchain/coreth/sync/handlers"
+
+	syncclient "github.com/MetalBlockchain/coreth/sync/client"
+	handlerstats "github.com/MetalBlockchain/coreth/sync/handlers/stats"
+	ethparams "github.com/MetalBlockchain/libevm/params"
+)
+
+// canonicalBlockProvider serves blocks by hash and by height from [blocks],
+// indexed by height.
+type canonicalBlockProvider struct {
+	blocks []*types.Block
+}
+
+func (c *canonicalBlockProvider) GetBlock(hash common.Hash, height uint64) *types.Block {
+	if block := c.GetBlockByNumber(height); block != nil && block.Hash() == hash {
+		return block
+	}
+	return nil
+}
+
+func (c *canonicalBlockProvider) GetBlockByNumber(height uint64) *types.Block {
+	if height >= uint64(len(c.blocks)) {
+		return nil
+	}
+	return c.blocks[height]
+}
+
+type backfillTestEnvironment struct {
+	chainDB  ethdb.Database
+	client   *syncclient.TestClient
+	blocks   []*types.Block
+	receipts []types.Receipts
+}
+
+// newBackfillTestEnvironment generates [numBlocks] blocks with transactions,
+// served with their receipts by the client of the returned environment.
+func newBackfillTestEnvironment(t *testing.T, numBlocks int) *backfillTestEnvironment {
+	t.Helper()
+
+	var (
+		key, _ = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+		addr   = crypto.PubkeyToAddress(key.PublicKey)
+		signer = types.HomesteadSigner{}
+		gspec  = &core.Genesis{
+			Config: &params.ChainConfig{HomesteadBlock: new(big.Int)},
+			Alloc:  types.GenesisAlloc{addr: {Balance: big.NewInt(1000000000)}},
+		}
+	)
+	_, blocks, receipts, err := core.GenerateChainWithGenesis(gspec, dummy.NewETHFaker(), numBlocks, 0, func(i int, gen *core.BlockGen) {
+		// Leave some blocks empty.
+		for j := 0; j < i%3; j++ {
+			tx, _ := types.SignTx(types.NewTransaction(gen.TxNonce(addr), addr, big.NewInt(10), ethparams.TxGas, nil, nil), signer, key)
+			gen.AddTx(tx)
+		}
+	})
+	require.NoError(t, err)
+	blocks = append([]*types.Block{gspec.ToBlock()}, blocks...)
+	receipts = append([]types.Receipts{nil}, receipts...)
+
+	serverDB := rawdb.NewMemoryDatabase()
+	for i, block := range blocks {
+		rawdb.WriteBlock(serverDB, block)
+		rawdb.WriteCanonicalHash(serverDB, block.Hash(), block.NumberU64())
+		rawdb.WriteReceipts(serverDB, block.Hash(), block.NumberU64(), receipts[i])
+	}
+
+	client := syncclient.NewTestClient(
+		message.Codec,
+		nil,
+		nil,
+		handlers.NewBlockRequestHandler(&canonicalBlockProvider{blocks: blocks}, message.Codec, handlerstats.NewNoopHandlerStats()),
+	)
+	client.ReceiptsHandler = handlers.NewReceiptsRequestHandler(serverDB, message.Codec, handlerstats.NewNoopHandlerStats())
+	return &backfillTestEnvironment{
+		chainDB:  rawdb.NewMemoryDatabase(),
+		client:   client,
+		blocks:   blocks,
+		receipts: receipts,
+	}
+}
+
+func (e *backfillTestEnvironment) newBackfiller(fromHeight, toHeight, txIndexFrom uint64) *Backfiller {
+	backfiller := NewBackfiller(e.client, e.chainDB, BackfillConfig{
+		FromHash:    e.blocks[fromHeight].Hash(),
+		FromHeight:  fromHeight,
+		ToHeight:    toHeight,
+		Parallelism: 3,
+		TxIndexFrom: txIndexFrom,
+	})
+	backfiller.minRetryInterval = time.Millisecond
+	backfiller.maxRetryInterval = 4 * time.Millisecond
+	return backfiller
+}
+
+// verifyBackfilled checks that the blocks from [from] down to [to] are stored
+// with their receipts, and that only those at or above [txIndexFrom] are
+// indexed.
+func (e *backfillTestEnvironment) verifyBackfilled(t *testing.T, from, to, txIndexFrom uint64) {
+	t.Helper()
+	require := require.New(t)
+
+	for height := to; height <= from; height++ {
+		block := e.blocks[height]
+		require.Equal(block.Hash(), rawdb.ReadCanonicalHash(e.chainDB, height), "height %d", height)
+		stored := rawdb.ReadBlock(e.chainDB, block.Hash(), height)
+		require.NotNil(stored, "height %d", height)
+		require.Equal(block.Hash(), stored.Hash())
+
+		receipts := rawdb.ReadRawReceipts(e.chainDB, block.Hash(), height)
+		require.Len(receipts, len(e.receipts[height]), "height %d", height)
+		for i, receipt := range receipts {
+			require.Equal(e.receipts[height][i].CumulativeGasUsed, receipt.CumulativeGasUsed)
+			require.Equal(e.receipts[height][i].Status, receipt.Status)
+		}
+
+		for _, tx := range block.Transactions() {
+			indexed := rawdb.ReadTxLookupEntry(e.chainDB, tx.Hash())
+			if height >= txIndexFrom {
+				require.NotNil(indexed, "height %d", height)
+				require.Equal(height, *indexed)
+			} else {
+				require.Nil(indexed, "height %d", height)
+			}
+		}
+	}
+	if to > 0 {
+		require.Nil(rawdb.ReadBlock(e.chainDB, e.blocks[to-1].Hash(), to-1))
+	}
+}
+
+func TestBackfiller(t *testing.T) {
+	tests := []struct {
+		name        string
+		numBlocks   int
+		fromHeight  uint64
+		toHeight    uint64
+		txIndexFrom uint64
+	}{
+		{
+			name:        "backfill to genesis",
+			numBlocks:   200,
+			fromHeight:  200,
+			toHeight:    0,
+			txIndexFrom: 150,
+		},
+		{
+			name:        "backfill partial history",
+			numBlocks:   200,
+			fromHeight:  180,
+			toHeight:    50,
+			txIndexFrom: 0,
+		},
+		{
+			name:        "backfill single segment",
+			numBlocks:   20,
+			fromHeight:  10,
+			toHeight:    5,
+			txIndexFrom: math.MaxUint64,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := newBackfillTestEnvironment(t, tt.numBlocks)
+			require.NoError(t, env.newBackfiller(tt.fromHeight, tt.toHeight, tt.txIndexFrom).Run(context.Background()))
+			env.verifyBackfilled(t, tt.fromHeight, tt.toHeight, tt.txIndexFrom)
+
+			progress, err := customrawdb.ReadBlockBackfillProgress(env.chainDB)
+			require.NoError(t, err)
+			require.Equal(t, &customrawdb.BlockBackfillProgress{
+				SyncHeight: tt.fromHeight,
+				Hash:       env.blocks[tt.toHeight].Hash(),
+				Height:     tt.toHeight,
+			}, progress)
+		})
+	}
+}
+
+func TestBackfillerResumes(t *testing.T) {
+	require := require.New(t)
+	env := newBackfillTestEnvironment(t, 100)
+
+	// Record progress down to height 60, without the blocks above it, which
+	// must not be fetched again.
+	block := env.blocks[60]
+	rawdb.WriteHeader(env.chainDB, block.Header())
+	require.NoError(customrawdb.WriteBlockBackfillProgress(env.chainDB, &customrawdb.BlockBackfillProgress{
+		SyncHeight: 100,
+		Hash:       block.Hash(),
+		Height:     60,
+	}))
+
+	require.NoError(env.newBackfiller(100, 10, 0).Run(context.Background()))
+	env.verifyBackfilled(t, 59, 10, 0)
+	require.Nil(rawdb.ReadBlock(env.chainDB, env.blocks[61].Hash(), 61))
+	require.EqualValues(50, env.client.BlocksReceived())
+
+	// A completed backfill does nothing.
+	require.NoError(env.newBackfiller(100, 10, 0).Run(context.Background()))
+	require.EqualValues(50, env.client.BlocksReceived())
+}
+
+func TestBackfillerRestartsAfterNewSync(t *testing.T) {
+	require := require.New(t)
+	env := newBackfillTestEnvironment(t, 100)
+
+	require.NoError(customrawdb.WriteBlockBackfillProgress(env.chainDB, &customrawdb.BlockBackfillProgress{
+		SyncHeight: 50,
+		Hash:       env.blocks[20].Hash(),
+		Height:     20,
+	}))
+	require.NoError(env.newBackfiller(100, 30, 0).Run(context.Background()))
+	env.verifyBackfilled(t, 100, 30, 0)
+}
+
+func TestBackfillerRefetchesInvalidBlocks(t *testing.T) {
+	require := require.New(t)
+	env := newBackfillTestEnvironment(t, 100)
+
+	// Replace the first response with blocks that do not chain to the
+	// requested block.
+	corrupted := false
+	env.client.GetBlocksIntercept = func(_ message.BlockRequest, blocks types.Blocks) (types.Blocks, error) {
+		if corrupted {
+			return blocks, nil
+		}
+		corrupted = true
+		return env.blocks[1 : 1+len(blocks)], nil
+	}
+	backfiller := env.newBackfiller(100, 0, 0)
+	backfiller.config.Parallelism = 1
+	require.NoError(backfiller.Run(context.Background()))
+	env.verifyBackfilled(t, 100, 0, 0)
+}
+
+func TestBackfillerGivesUp(t *testing.T) {
+	errFetch := errors.New("fetch failed")
+	tests := []struct {
+		name      string
+		intercept func(*backfillTestEnvironment, types.Blocks) (types.Blocks, error)
+		wantErr   error
+	}{
+		{
+			name: "invalid_blocks",
+			intercept: func(env *backfillTestEnvironment, blocks types.Blocks) (types.Blocks, error) {
+				return env.blocks[1 : 1+len(blocks)], nil
+			},
+			wantErr: errBlockMismatch,
+		},
+		{
+			name: "failed_requests",
+			intercept: func(*backfillTestEnvironment, types.Blocks) (types.Blocks, error) {
+				return nil, errFetch
+			},
+			wantErr: errFetch,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require := require.New(t)
+			env := newBackfillTestEnvironment(t, 10)
+
+			var requests int
+			env.client.GetBlocksIntercept = func(_ message.BlockRequest, blocks types.Blocks) (types.Blocks, error) {
+				requests++
+				return test.intercept(env, blocks)
+			}
+			backfiller := env.newBackfiller(10, 0, 0)
+			backfiller.config.Parallelism = 1
+			backfiller.config.MaxRetries = 2
+
+			err := backfiller.Run(context.Background())
+			require.ErrorIs(err, errTooManyRetries)
+			require.ErrorIs(err, test.wantErr)
+			require.Equal(1+backfiller.config.MaxRetries, requests)
+		})
+	}
+}
+
+func TestBackfillerRetryInterval(t *testing.T) {
+	backfiller := NewBackfiller(nil, nil, BackfillConfig{})
+	tests := []struct {
+		attempt int
+		want    time.Duration
+	}{
+		{attempt: 1, want: time.Second},
+		{attempt: 2, want: 2 * time.Second},
+		{attempt: 3, want: 4 * time.Second},
+		{attempt: 6, want: 32 * time.Second},
+		{attempt: 7, want: time.Minute},
+		{attempt: 100, want: time.Minute},
+	}
+	for _, test := range tests {
+		require.Equal(t, test.want, backfiller.retryInterval(test.attempt), "attempt %d", test.attempt)
+	}
+}
+
+func TestBackfillerRejectsInvalidReceipts(t *testing.T) {
+	require := require.New(t)
+	env := newBackfillTestEnvironment(t, 10)
+
+	block := env.blocks[5]
+	receipts := types.Receipts{{Status: types.ReceiptStatusFailed, CumulativeGasUsed: 1}}
+	seg := &segment{
+		lo:            5,
+		hi:            5,
+		blocks:        []*types.Block{block},
+		receipts:      []types.Receipts{receipts},
+		localReceipts: []bool{false},
+	}
+	backfiller := env.newBackfiller(5, 5, 0)
+	err := backfiller.verifySegment(seg, block.Hash())
+	require.ErrorIs(err, errInvalidReceipts)
+
+	seg.receipts[0] = env.receipts[5]
+	require.NoError(backfiller.verifySegment(seg, block.Hash()))
+	require.ErrorIs(backfiller.verifySegment(seg, env.blocks[6].Hash()), errBlockMismatch)
+}
+
+func TestBackfillerContextCancellation(t *testing.T) {
+	env := newBackfillTestEnvironment(t, 10)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err := env.newBackfiller(10, 0, 0).Run(ctx)
+	require.ErrorIs(t, err, context.Canceled)
+}
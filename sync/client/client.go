@@ -20,6 +20,7 @@ import (
 	"github.com/MetalBlockchain/libevm/crypto"
 	"github.com/MetalBlockchain/libevm/ethdb"
 	"github.com/MetalBlockchain/libevm/log"
+	"github.com/MetalBlockchain/libevm/rlp"
 	"github.com/MetalBlockchain/libevm/trie"
 
 	"github.com/MetalBlockchain/coreth/network"
@@ -49,6 +50,8 @@ var (
 	errUnmarshalResponse      = errors.New("failed to unmarshal response")
 	errInvalidCodeResponseLen = errors.New("number of code bytes in response does not match requested hashes")
 	errMaxCodeSizeExceeded    = errors.New("max code size exceeded")
+	errTooManyReceipts        = errors.New("response contains receipts of more blocks than requested")
+	errHeightMismatch         = errors.New("height does not match expected value")
 )
 var _ Client = (*client)(nil)
 
@@ -61,10 +64,16 @@ type Client interface {
 
 	// GetBlocks synchronously retrieves blocks starting with specified common.Hash and height up to specified parents
 	// specified range from height to height-parents is inclusive
+	// If blockHash is empty, the blocks start with the canonical block at height.
 	GetBlocks(ctx context.Context, blockHash common.Hash, height uint64, parents uint16) ([]*types.Block, error)
 
 	// GetCode synchronously retrieves code associated with the given hashes
 	GetCode(ctx context.Context, hashes []common.Hash) ([][]byte, error)
+
+	// GetReceipts synchronously retrieves the receipts of the blocks with the given hashes
+	// Returns the receipts of a non-empty prefix of the blocks, which the caller must verify
+	// against the blocks' headers.
+	GetReceipts(ctx context.Context, hashes []common.Hash) ([]types.Receipts, error)
 }
 
 // parseResponseFn parses given response bytes in context of specified request
@@ -226,6 +235,14 @@ func (c *client) parseBlocks(codec codec.Manager, req message.Request, data []by
 			return nil, 0, fmt.Errorf("%w: %w", errUnmarshalResponse, err)
 		}
 
+		if i == 0 && (hash == common.Hash{}) {
+			// The request was for the canonical block at the requested height,
+			// so only its height can be checked.
+			if block.NumberU64() != blockRequest.Height {
+				return nil, 0, fmt.Errorf("%w for block: (got %d) (expected %d)", errHeightMismatch, block.NumberU64(), blockRequest.Height)
+			}
+			hash = block.Hash()
+		}
 		if block.Hash() != hash {
 			return nil, 0, fmt.Errorf("%w for block: (got %v) (expected %v)", errHashMismatch, block.Hash(), hash)
 		}
@@ -279,6 +296,48 @@ func parseCode(codec codec.Manager, req message.Request, data []byte) (interface
 	return response.Data, totalBytes, nil
 }
 
+func (c *client) GetReceipts(ctx context.Context, hashes []common.Hash) ([]types.Receipts, error) {
+	req := message.ReceiptsRequest{Hashes: hashes}
+
+	data, err := c.get(ctx, req, parseReceipts)
+	if err != nil {
+		return nil, fmt.Errorf("could not get receipts (%s): %w", req, err)
+	}
+
+	return data.([]types.Receipts), nil
+}
+
+// parseReceipts validates given object as message.ReceiptsResponse
+// assumes req is of type message.ReceiptsRequest
+// returns []types.Receipts as interface{}
+// returns a non-nil error if the request should be retried
+func parseReceipts(codec codec.Manager, req message.Request, data []byte) (interface{}, int, error) {
+	var response message.ReceiptsResponse
+	if _, err := codec.Unmarshal(data, &response); err != nil {
+		return nil, 0, fmt.Errorf("%w: %w", errUnmarshalResponse, err)
+	}
+	if len(response.Receipts) == 0 {
+		return nil, 0, errEmptyResponse
+	}
+	receiptsRequest := req.(message.ReceiptsRequest)
+	if len(response.Receipts) > len(receiptsRequest.Hashes) {
+		return nil, 0, errTooManyReceipts
+	}
+
+	receipts := make([]types.Receipts, len(response.Receipts))
+	for i, receiptsBytes := range response.Receipts {
+		var stored []*types.ReceiptForStorage
+		if err := rlp.DecodeBytes(receiptsBytes, &stored); err != nil {
+			return nil, 0, fmt.Errorf("%w: %w", errUnmarshalResponse, err)
+		}
+		receipts[i] = make(types.Receipts, len(stored))
+		for j, receipt := range stored {
+			receipts[i][j] = (*types.Receipt)(receipt)
+		}
+	}
+	return receipts, len(receipts), nil
+}
+
 // get submits given request and blockingly returns with either a parsed response object or an error
 // if [ctx] expires before the client can successfully retrieve a valid response.
 // Retries if there is a network error or if the [parseResponseFn] returns an error indicating an invalid response.
@@ -0,0 +1,150 @@
+// Copyright (C) 2019-2025, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package statesyncclient
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/MetalBlockchain/metalgo/codec"
+	"github.com/MetalBlockchain/libevm/common"
+	"github.com/MetalBlockchain/libevm/core/types"
+
+	"github.com/MetalBlockchain/coreth/plugin/evm/message"
+	"github.com/MetalBlockchain/coreth/sync/syncfile"
+)
+
+var (
+	_ Client = (*fileClient)(nil)
+
+	errReceiptsNotInSnapshot = errors.New("state sync snapshots do not include receipts")
+)
+
+// fileClient serves requests from a state sync snapshot written by
+// [syncfile.Export]. Every response is verified exactly like a response
+// received from a peer, so a corrupted or forged snapshot fails to sync.
+type fileClient struct {
+	reader      *syncfile.Reader
+	codec       codec.Manager
+	blockParser EthBlockParser
+}
+
+// NewFileClient returns a [Client] that serves requests from the snapshot
+// read by [reader].
+func NewFileClient(reader *syncfile.Reader, codec codec.Manager, blockParser EthBlockParser) Client {
+	return &fileClient{
+		reader:      reader,
+		codec:       codec,
+		blockParser: blockParser,
+	}
+}
+
+// GetLeafs serves [request] from the snapshot response covering
+// [request.Start]. That response is verified against the start key it was
+// exported with, after which the leaves outside of the requested range are
+// dropped. Since the dropped leaves are at the edges of a verified range, the
+// remaining leaves still form a complete range from [request.Start].
+func (f *fileClient) GetLeafs(_ context.Context, request message.LeafsRequest) (message.LeafsResponse, error) {
+	chunkStart, data, err := f.reader.Leafs(request.NodeType, request.Root, request.Start)
+	if err != nil {
+		return message.LeafsResponse{}, err
+	}
+	exported := message.LeafsRequest{
+		Root:     request.Root,
+		Account:  request.Account,
+		Start:    chunkStart,
+		Limit:    f.reader.Manifest().RequestSize,
+		NodeType: request.NodeType,
+	}
+	responseIntf, _, err := parseLeafsResponse(f.codec, exported, data)
+	if err != nil {
+		return message.LeafsResponse{}, err
+	}
+	response := responseIntf.(message.LeafsResponse)
+	// The proof is only valid for the exported range.
+	response.ProofVals = nil
+
+	first := sort.Search(len(response.Keys), func(i int) bool {
+		return bytes.Compare(response.Keys[i], request.Start) >= 0
+	})
+	response.Keys, response.Vals = response.Keys[first:], response.Vals[first:]
+
+	last := len(response.Keys)
+	if len(request.End) > 0 {
+		last = sort.Search(len(response.Keys), func(i int) bool {
+			return bytes.Compare(response.Keys[i], request.End) > 0
+		})
+	}
+	last = min(last, int(request.Limit))
+	if last < len(response.Keys) {
+		response.Keys, response.Vals = response.Keys[:last], response.Vals[:last]
+		response.More = true
+	}
+	return response, nil
+}
+
+func (f *fileClient) GetBlocks(_ context.Context, blockHash common.Hash, height uint64, parents uint16) ([]*types.Block, error) {
+	request := message.BlockRequest{
+		Hash:    blockHash,
+		Height:  height,
+		Parents: parents,
+	}
+	var response message.BlockResponse
+	hash := blockHash
+	for i := uint16(0); i < parents; i++ {
+		blockBytes, err := f.reader.Block(hash)
+		if errors.Is(err, syncfile.ErrNotFound) && i > 0 {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		block, err := f.blockParser.ParseEthBlock(blockBytes)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", errUnmarshalResponse, err)
+		}
+		response.Blocks = append(response.Blocks, blockBytes)
+		hash = block.ParentHash()
+	}
+	data, err := f.codec.Marshal(message.Version, response)
+	if err != nil {
+		return nil, err
+	}
+	c := &client{blockParser: f.blockParser}
+	blocks, _, err := c.parseBlocks(f.codec, request, data)
+	if err != nil {
+		return nil, err
+	}
+	return blocks.(types.Blocks), nil
+}
+
+func (f *fileClient) GetCode(_ context.Context, hashes []common.Hash) ([][]byte, error) {
+	request := message.NewCodeRequest(hashes)
+	response := message.CodeResponse{
+		Data: make([][]byte, len(hashes)),
+	}
+	for i, hash := range hashes {
+		code, err := f.reader.Code(hash)
+		if err != nil {
+			return nil, err
+		}
+		response.Data[i] = code
+	}
+	data, err := f.codec.Marshal(message.Version, response)
+	if err != nil {
+		return nil, err
+	}
+	code, _, err := parseCode(f.codec, request, data)
+	if err != nil {
+		return nil, err
+	}
+	return code.([][]byte), nil
+}
+
+func (*fileClient) GetReceipts(context.Context, []common.Hash) ([]types.Receipts, error) {
+	return nil, errReceiptsNotInSnapshot
+}
@@ -0,0 +1,110 @@
+// Copyright (C) 2019-2025, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package statesyncclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/MetalBlockchain/metalgo/codec"
+	"github.com/MetalBlockchain/metalgo/ids"
+	"github.com/MetalBlockchain/libevm/common"
+	"github.com/MetalBlockchain/libevm/core/types"
+
+	"github.com/MetalBlockchain/coreth/plugin/evm/message"
+)
+
+var (
+	_ Client = (*handlerClient)(nil)
+
+	errRequestDropped = errors.New("request dropped by handler")
+)
+
+// handlerClient serves requests from a local [message.RequestHandler],
+// verifying the responses exactly like responses received from peers.
+type handlerClient struct {
+	handler     message.RequestHandler
+	codec       codec.Manager
+	blockParser EthBlockParser
+}
+
+// NewHandlerClient returns a [Client] that serves requests from [handler]
+// instead of the network. This allows a node to read a state summary it
+// serves to peers, e.g. to export it.
+func NewHandlerClient(handler message.RequestHandler, codec codec.Manager, blockParser EthBlockParser) Client {
+	return &handlerClient{
+		handler:     handler,
+		codec:       codec,
+		blockParser: blockParser,
+	}
+}
+
+func (h *handlerClient) GetLeafs(ctx context.Context, request message.LeafsRequest) (message.LeafsResponse, error) {
+	response, err := h.handler.HandleLeafsRequest(ctx, ids.EmptyNodeID, 0, request)
+	if err := checkHandlerResponse(request, response, err); err != nil {
+		return message.LeafsResponse{}, err
+	}
+	leafsResponse, _, err := parseLeafsResponse(h.codec, request, response)
+	if err != nil {
+		return message.LeafsResponse{}, err
+	}
+	return leafsResponse.(message.LeafsResponse), nil
+}
+
+func (h *handlerClient) GetBlocks(ctx context.Context, blockHash common.Hash, height uint64, parents uint16) ([]*types.Block, error) {
+	request := message.BlockRequest{
+		Hash:    blockHash,
+		Height:  height,
+		Parents: parents,
+	}
+	response, err := h.handler.HandleBlockRequest(ctx, ids.EmptyNodeID, 0, request)
+	if err := checkHandlerResponse(request, response, err); err != nil {
+		return nil, err
+	}
+	c := &client{blockParser: h.blockParser}
+	blocks, _, err := c.parseBlocks(h.codec, request, response)
+	if err != nil {
+		return nil, err
+	}
+	return blocks.(types.Blocks), nil
+}
+
+func (h *handlerClient) GetCode(ctx context.Context, hashes []common.Hash) ([][]byte, error) {
+	request := message.NewCodeRequest(hashes)
+	response, err := h.handler.HandleCodeRequest(ctx, ids.EmptyNodeID, 0, request)
+	if err := checkHandlerResponse(request, response, err); err != nil {
+		return nil, err
+	}
+	code, _, err := parseCode(h.codec, request, response)
+	if err != nil {
+		return nil, err
+	}
+	return code.([][]byte), nil
+}
+
+func (h *handlerClient) GetReceipts(ctx context.Context, hashes []common.Hash) ([]types.Receipts, error) {
+	request := message.ReceiptsRequest{Hashes: hashes}
+	response, err := h.handler.HandleReceiptsRequest(ctx, ids.EmptyNodeID, 0, request)
+	if err := checkHandlerResponse(request, response, err); err != nil {
+		return nil, err
+	}
+	receipts, _, err := parseReceipts(h.codec, request, response)
+	if err != nil {
+		return nil, err
+	}
+	return receipts.([]types.Receipts), nil
+}
+
+// checkHandlerResponse returns an error if the handler failed or dropped
+// [request].
+func checkHandlerResponse(request message.Request, response []byte, err error) error {
+	if err != nil {
+		return fmt.Errorf("failed to handle %s: %w", request, err)
+	}
+	if response == nil {
+		return fmt.Errorf("%w: %s", errRequestDropped, request)
+	}
+	return nil
+}
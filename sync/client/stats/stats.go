@@ -78,7 +78,8 @@ func (m *messageMetric) UpdateRequestLatency(duration time.Duration) {
 type clientSyncerStats struct {
 	leafMetrics map[message.NodeType]MessageMetric
 	codeRequestMetric,
-	blockRequestMetric MessageMetric
+	blockRequestMetric,
+	receiptsRequestMetric MessageMetric
 }
 
 // NewClientSyncerStats returns stats for the client syncer
@@ -88,9 +89,10 @@ func NewClientSyncerStats(leafMetricNames map[message.NodeType]string) *clientSy
 		leafMetrics[nodeType] = NewMessageMetric(name)
 	}
 	return &clientSyncerStats{
-		leafMetrics:        leafMetrics,
-		codeRequestMetric:  NewMessageMetric("sync_code"),
-		blockRequestMetric: NewMessageMetric("sync_blocks"),
+		leafMetrics:           leafMetrics,
+		codeRequestMetric:     NewMessageMetric("sync_code"),
+		blockRequestMetric:    NewMessageMetric("sync_blocks"),
+		receiptsRequestMetric: NewMessageMetric("sync_receipts"),
 	}
 }
 
@@ -101,6 +103,8 @@ func (c *clientSyncerStats) GetMetric(msgIntf message.Request) (MessageMetric, e
 		return c.blockRequestMetric, nil
 	case message.CodeRequest:
 		return c.codeRequestMetric, nil
+	case message.ReceiptsRequest:
+		return c.receiptsRequestMetric, nil
 	case message.LeafsRequest:
 		metric, ok := c.leafMetrics[msg.NodeType]
 		if !ok {
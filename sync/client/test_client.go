@@ -40,6 +40,8 @@ type TestClient struct {
 	// GetBlocksIntercept is called on every GetBlocks request if set to a non-nil callback.
 	// The returned response will be returned by TestClient to the caller.
 	GetBlocksIntercept func(blockReq message.BlockRequest, blocks types.Blocks) (types.Blocks, error)
+	// ReceiptsHandler serves GetReceipts requests if set.
+	ReceiptsHandler *handlers.ReceiptsRequestHandler
 }
 
 func NewTestClient(
@@ -143,6 +145,28 @@ func (ml *TestClient) BlocksReceived() int32 {
 	return atomic.LoadInt32(&ml.blocksReceived)
 }
 
+func (ml *TestClient) GetReceipts(ctx context.Context, hashes []common.Hash) ([]types.Receipts, error) {
+	if ml.ReceiptsHandler == nil {
+		panic("no receipts handler for test client")
+	}
+	request := message.ReceiptsRequest{Hashes: hashes}
+	response, err := ml.ReceiptsHandler.OnReceiptsRequest(ctx, ids.GenerateTestNodeID(), 1, request)
+	if err != nil {
+		return nil, err
+	}
+	// Actual client retries until the context is canceled.
+	if response == nil {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+
+	receipts, _, err := parseReceipts(ml.codec, request, response)
+	if err != nil {
+		return nil, err
+	}
+	return receipts.([]types.Receipts), nil
+}
+
 type testBlockParser struct{}
 
 func newTestBlockParser() *testBlockParser {
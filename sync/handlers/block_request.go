@@ -26,7 +26,8 @@ const (
 )
 
 // BlockRequestHandler is a peer.RequestHandler for message.BlockRequest
-// serving requested blocks starting at specified hash
+// serving requested blocks starting at specified hash, or at the canonical
+// block at the specified height if the hash is empty
 type BlockRequestHandler struct {
 	stats         stats.BlockRequestHandlerStats
 	blockProvider BlockProvider
@@ -66,6 +67,12 @@ func (b *BlockRequestHandler) OnBlockRequest(ctx context.Context, nodeID ids.Nod
 
 	hash := blockRequest.Hash
 	height := blockRequest.Height
+	if canonical, ok := b.blockProvider.(CanonicalBlockProvider); ok && (hash == common.Hash{}) {
+		// A request without a hash is for the canonical block at [height].
+		if block := canonical.GetBlockByNumber(height); block != nil {
+			hash = block.Hash()
+		}
+	}
 	for i := 0; i < int(parents); i++ {
 		// we return whatever we have until ctx errors, limit is exceeded, or we reach the genesis block
 		// this will happen either when the ctx is cancelled or we hit the ctx deadline
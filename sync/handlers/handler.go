@@ -14,6 +14,12 @@ type BlockProvider interface {
 	GetBlock(common.Hash, uint64) *types.Block
 }
 
+// CanonicalBlockProvider is implemented by [BlockProvider]s which can look up
+// blocks by height, to serve block requests without a hash.
+type CanonicalBlockProvider interface {
+	GetBlockByNumber(uint64) *types.Block
+}
+
 type SnapshotProvider interface {
 	Snapshots() *snapshot.Tree
 }
@@ -0,0 +1,226 @@
+// Copyright (C) 2019-2025, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/MetalBlockchain/libevm/common"
+	"github.com/MetalBlockchain/libevm/common/lru"
+	"github.com/MetalBlockchain/libevm/core/types"
+	"github.com/MetalBlockchain/libevm/ethdb"
+	"github.com/MetalBlockchain/libevm/rlp"
+	"github.com/MetalBlockchain/libevm/trie"
+	"github.com/MetalBlockchain/libevm/triedb"
+
+	"github.com/MetalBlockchain/coreth/sync/syncutils"
+)
+
+// storageStatesCacheSize is the number of storage tries whose state root is
+// cached by [pathLeafProvider].
+const storageStatesCacheSize = 1024
+
+var (
+	_ LeafProvider = (*hashLeafProvider)(nil)
+	_ LeafProvider = (*pathLeafProvider)(nil)
+	_ LeafProvider = (*unsupportedLeafProvider)(nil)
+
+	errUnknownStorageTrie = errors.New("storage trie does not belong to a recently requested state")
+	errUnsupportedScheme  = errors.New("serving leafs is not supported by the state scheme")
+)
+
+// LeafProvider gives a [leafsRequestHandler] access to the tries it serves.
+// Each state scheme provides the trie to prove responses against, and
+// optionally a flat view of its leaves which is faster to read than
+// iterating the trie.
+type LeafProvider interface {
+	// OpenTrie opens the trie with [root]. [account] is the hash of the
+	// account owning the trie, or empty for the account trie.
+	OpenTrie(root common.Hash, account common.Hash) (*trie.Trie, error)
+
+	// NewLeafIterator returns an iterator over the leaves from [start] of the
+	// trie OpenTrie opens for the same arguments. The leaves are read
+	// optimistically and may not match the trie, so they are verified
+	// before they are served. Returns nil if no flat view of the trie is
+	// available.
+	NewLeafIterator(root common.Hash, account common.Hash, start common.Hash) ethdb.Iterator
+}
+
+// hashLeafProvider serves tries from a hash scheme [triedb.Database], using
+// the disk layer of the state snapshot as the flat view of their leaves.
+type hashLeafProvider struct {
+	trieDB           *triedb.Database
+	snapshotProvider SnapshotProvider
+}
+
+// NewHashLeafProvider returns a [LeafProvider] for tries stored with the hash
+// scheme in [trieDB]. [snapshotProvider] may be nil, or return nil, if the
+// state snapshot is unavailable.
+func NewHashLeafProvider(trieDB *triedb.Database, snapshotProvider SnapshotProvider) LeafProvider {
+	return &hashLeafProvider{
+		trieDB:           trieDB,
+		snapshotProvider: snapshotProvider,
+	}
+}
+
+func (h *hashLeafProvider) OpenTrie(root common.Hash, _ common.Hash) (*trie.Trie, error) {
+	return trie.New(trie.TrieID(root), h.trieDB)
+}
+
+func (h *hashLeafProvider) NewLeafIterator(_ common.Hash, account common.Hash, start common.Hash) ethdb.Iterator {
+	if h.snapshotProvider == nil {
+		return nil
+	}
+	snap := h.snapshotProvider.Snapshots()
+	if snap == nil {
+		return nil
+	}
+	if account == (common.Hash{}) {
+		return &syncutils.AccountIterator{AccountIterator: snap.DiskAccountIterator(start)}
+	}
+	return &syncutils.StorageIterator{StorageIterator: snap.DiskStorageIterator(account, start)}
+}
+
+// pathLeafProvider serves tries from a path scheme [triedb.Database], using
+// its persisted trie nodes as the flat view of their leaves.
+//
+// Path scheme storage tries can only be opened along with the root of the
+// state they belong to, which leafs requests do not include. Since peers
+// request the account trie before the storage tries it references, the
+// provider remembers the most recently requested account trie roots and
+// looks up the storage trie in each of them. The state a storage trie is found
+// in is cached, as a storage trie is usually requested in several chunks.
+type pathLeafProvider struct {
+	trieDB *triedb.Database
+	diskdb ethdb.KeyValueStore
+
+	lock          sync.Mutex
+	maxStateRoots int
+	stateRoots    []common.Hash // most recent first
+
+	storageStates *lru.Cache[storageTrieKey, common.Hash] // storage trie -> state root
+}
+
+// storageTrieKey identifies the storage trie with [root] of [account].
+type storageTrieKey struct {
+	account common.Hash
+	root    common.Hash
+}
+
+// NewPathLeafProvider returns a [LeafProvider] for tries stored with the path
+// scheme in [trieDB], whose nodes are persisted to [diskdb].
+//
+// [maxStateRoots] is the number of requested account trie roots remembered to
+// open storage tries. It should cover every state [trieDB] can open, so that
+// requests for other states cannot evict the root a peer is syncing to.
+func NewPathLeafProvider(trieDB *triedb.Database, diskdb ethdb.KeyValueStore, maxStateRoots int) LeafProvider {
+	return &pathLeafProvider{
+		trieDB:        trieDB,
+		diskdb:        diskdb,
+		maxStateRoots: max(maxStateRoots, 1),
+		storageStates: lru.NewCache[storageTrieKey, common.Hash](storageStatesCacheSize),
+	}
+}
+
+func (p *pathLeafProvider) OpenTrie(root common.Hash, account common.Hash) (*trie.Trie, error) {
+	if account == (common.Hash{}) {
+		t, err := trie.New(trie.StateTrieID(root), p.trieDB)
+		if err != nil {
+			return nil, err
+		}
+		p.addStateRoot(root)
+		return t, nil
+	}
+	key := storageTrieKey{account: account, root: root}
+	if stateRoot, ok := p.storageStates.Get(key); ok {
+		t, err := trie.New(trie.StorageTrieID(stateRoot, account, root), p.trieDB)
+		if err == nil {
+			return t, nil
+		}
+		// The state is no longer available, so look the trie up again.
+		p.storageStates.Remove(key)
+	}
+	for _, stateRoot := range p.recentStateRoots() {
+		storageRoot, err := p.storageRoot(stateRoot, account)
+		if err != nil || storageRoot != root {
+			continue
+		}
+		t, err := trie.New(trie.StorageTrieID(stateRoot, account, root), p.trieDB)
+		if err != nil {
+			return nil, err
+		}
+		p.storageStates.Add(key, stateRoot)
+		return t, nil
+	}
+	return nil, fmt.Errorf("%w: %s of account %s", errUnknownStorageTrie, root, account)
+}
+
+func (p *pathLeafProvider) NewLeafIterator(_ common.Hash, account common.Hash, start common.Hash) ethdb.Iterator {
+	return syncutils.NewPathLeafIterator(p.diskdb, account, start)
+}
+
+// storageRoot returns the storage root of [account] in the state with
+// [stateRoot].
+func (p *pathLeafProvider) storageRoot(stateRoot common.Hash, account common.Hash) (common.Hash, error) {
+	t, err := trie.New(trie.StateTrieID(stateRoot), p.trieDB)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	accountBytes, err := t.Get(account.Bytes())
+	if err != nil || accountBytes == nil {
+		return common.Hash{}, err
+	}
+	var acc types.StateAccount
+	if err := rlp.DecodeBytes(accountBytes, &acc); err != nil {
+		return common.Hash{}, err
+	}
+	return acc.Root, nil
+}
+
+// addStateRoot records [root] as the most recently requested account trie.
+func (p *pathLeafProvider) addStateRoot(root common.Hash) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	if len(p.stateRoots) > 0 && p.stateRoots[0] == root {
+		return
+	}
+	roots := make([]common.Hash, 0, p.maxStateRoots)
+	roots = append(roots, root)
+	for _, r := range p.stateRoots {
+		if r != root && len(roots) < p.maxStateRoots {
+			roots = append(roots, r)
+		}
+	}
+	p.stateRoots = roots
+}
+
+func (p *pathLeafProvider) recentStateRoots() []common.Hash {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	return p.stateRoots
+}
+
+// unsupportedLeafProvider is the [LeafProvider] of state schemes whose tries
+// can't be served, which fails to open every trie.
+type unsupportedLeafProvider struct {
+	scheme string
+}
+
+// NewUnsupportedLeafProvider returns a [LeafProvider] for the state [scheme]
+// which doesn't support serving leafs, so that every leafs request is dropped.
+func NewUnsupportedLeafProvider(scheme string) LeafProvider {
+	return &unsupportedLeafProvider{scheme: scheme}
+}
+
+func (u *unsupportedLeafProvider) OpenTrie(common.Hash, common.Hash) (*trie.Trie, error) {
+	return nil, fmt.Errorf("%w: %s", errUnsupportedScheme, u.scheme)
+}
+
+func (*unsupportedLeafProvider) NewLeafIterator(common.Hash, common.Hash, common.Hash) ethdb.Iterator {
+	return nil
+}
@@ -0,0 +1,61 @@
+// Copyright (C) 2019-2025, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package handlers
+
+import (
+	"testing"
+
+	"github.com/MetalBlockchain/libevm/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPathLeafProviderStateRoots(t *testing.T) {
+	tests := []struct {
+		name          string
+		maxStateRoots int
+		added         []common.Hash
+		want          []common.Hash
+	}{
+		{
+			name:          "below_limit",
+			maxStateRoots: 3,
+			added:         []common.Hash{{1}, {2}},
+			want:          []common.Hash{{2}, {1}},
+		},
+		{
+			name:          "evicts_least_recent",
+			maxStateRoots: 3,
+			added:         []common.Hash{{1}, {2}, {3}, {4}},
+			want:          []common.Hash{{4}, {3}, {2}},
+		},
+		{
+			name:          "moves_repeated_root_first",
+			maxStateRoots: 3,
+			added:         []common.Hash{{1}, {2}, {3}, {1}, {1}},
+			want:          []common.Hash{{1}, {3}, {2}},
+		},
+		{
+			name:          "remembers_at_least_one",
+			maxStateRoots: 0,
+			added:         []common.Hash{{1}, {2}},
+			want:          []common.Hash{{2}},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			p := NewPathLeafProvider(nil, nil, test.maxStateRoots).(*pathLeafProvider)
+			for _, root := range test.added {
+				p.addStateRoot(root)
+			}
+			require.Equal(t, test.want, p.recentStateRoots())
+		})
+	}
+}
+
+func TestUnsupportedLeafProvider(t *testing.T) {
+	p := NewUnsupportedLeafProvider("test")
+	_, err := p.OpenTrie(common.Hash{1}, common.Hash{})
+	require.ErrorIs(t, err, errUnsupportedScheme)
+	require.Nil(t, p.NewLeafIterator(common.Hash{1}, common.Hash{}, common.Hash{}))
+}
@@ -19,10 +19,8 @@ import (
 	"github.com/MetalBlockchain/libevm/trie"
 	"github.com/MetalBlockchain/libevm/triedb"
 
-	"github.com/MetalBlockchain/coreth/core/state/snapshot"
 	"github.com/MetalBlockchain/coreth/plugin/evm/message"
 	"github.com/MetalBlockchain/coreth/sync/handlers/stats"
-	"github.com/MetalBlockchain/coreth/sync/syncutils"
 	"github.com/MetalBlockchain/coreth/utils"
 )
 
@@ -48,21 +46,28 @@ type LeafRequestHandler interface {
 // leafsRequestHandler is a peer.RequestHandler for types.LeafsRequest
 // serving requested trie data
 type leafsRequestHandler struct {
-	trieDB           *triedb.Database
-	snapshotProvider SnapshotProvider
-	codec            codec.Manager
-	stats            stats.LeafsRequestHandlerStats
-	pool             sync.Pool
-	trieKeyLength    int
+	provider      LeafProvider
+	codec         codec.Manager
+	stats         stats.LeafsRequestHandlerStats
+	pool          sync.Pool
+	trieKeyLength int
 }
 
+// NewLeafsRequestHandler returns a handler serving tries stored with the hash
+// scheme in [trieDB], reading leaves from the state snapshot of
+// [snapshotProvider] when it is non-nil.
 func NewLeafsRequestHandler(trieDB *triedb.Database, trieKeyLength int, snapshotProvider SnapshotProvider, codec codec.Manager, syncerStats stats.LeafsRequestHandlerStats) *leafsRequestHandler {
+	return NewLeafsRequestHandlerWithProvider(NewHashLeafProvider(trieDB, snapshotProvider), trieKeyLength, codec, syncerStats)
+}
+
+// NewLeafsRequestHandlerWithProvider returns a handler serving the tries of
+// [provider].
+func NewLeafsRequestHandlerWithProvider(provider LeafProvider, trieKeyLength int, codec codec.Manager, syncerStats stats.LeafsRequestHandlerStats) *leafsRequestHandler {
 	return &leafsRequestHandler{
-		trieDB:           trieDB,
-		snapshotProvider: snapshotProvider,
-		codec:            codec,
-		stats:            syncerStats,
-		trieKeyLength:    trieKeyLength,
+		provider:      provider,
+		codec:         codec,
+		stats:         syncerStats,
+		trieKeyLength: trieKeyLength,
 		pool: sync.Pool{
 			New: func() interface{} { return make([][]byte, 0, maxLeavesLimit) },
 		},
@@ -98,11 +103,7 @@ func (lrh *leafsRequestHandler) OnLeafsRequest(ctx context.Context, nodeID ids.N
 		return nil, nil
 	}
 
-	// TODO: We should know the state root that accounts correspond to,
-	// as this information will be necessary to access storage tries when
-	// the trie is path based.
-	// stateRoot := common.Hash{}
-	t, err := trie.New(trie.TrieID(leafsRequest.Root), lrh.trieDB)
+	t, err := lrh.provider.OpenTrie(leafsRequest.Root, leafsRequest.Account)
 	if err != nil {
 		log.Debug("error opening trie when processing request, dropping request", "nodeID", nodeID, "requestID", requestID, "root", leafsRequest.Root, "err", err)
 		lrh.stats.IncMissingRoot()
@@ -133,14 +134,11 @@ func (lrh *leafsRequestHandler) OnLeafsRequest(ctx context.Context, nodeID ids.N
 		request:   &leafsRequest,
 		response:  &leafsResponse,
 		t:         t,
+		provider:  lrh.provider,
 		keyLength: lrh.trieKeyLength,
 		limit:     limit,
 		stats:     lrh.stats,
 	}
-	// pass snapshot to responseBuilder if non-nil snapshot getter provided
-	if lrh.snapshotProvider != nil {
-		responseBuilder.snap = lrh.snapshotProvider.Snapshots()
-	}
 	err = responseBuilder.handleRequest(ctx)
 
 	// ensure metrics are captured properly on all return paths
@@ -174,7 +172,7 @@ type responseBuilder struct {
 	request   *message.LeafsRequest
 	response  *message.LeafsResponse
 	t         *trie.Trie
-	snap      *snapshot.Tree
+	provider  LeafProvider
 	keyLength int
 	limit     uint16
 
@@ -185,9 +183,13 @@ type responseBuilder struct {
 }
 
 func (rb *responseBuilder) handleRequest(ctx context.Context) error {
-	// Read from snapshot if a [snapshot.Tree] was provided in initialization
-	if rb.snap != nil {
-		if done, err := rb.fillFromSnapshot(ctx); err != nil {
+	// Read from the flat view of the trie if the provider has one
+	snapIt := rb.provider.NewLeafIterator(rb.request.Root, rb.request.Account, common.BytesToHash(rb.request.Start))
+	if snapIt == nil {
+		rb.stats.IncSnapshotUnavailable()
+	} else {
+		defer snapIt.Release()
+		if done, err := rb.fillFromSnapshot(ctx, snapIt); err != nil {
 			return err
 		} else if done {
 			return nil
@@ -225,10 +227,10 @@ func (rb *responseBuilder) handleRequest(ctx context.Context) error {
 	return nil
 }
 
-// fillFromSnapshot reads data from snapshot and returns true if the response is complete.
+// fillFromSnapshot reads data from [snapIt] and returns true if the response is complete.
 // Otherwise, the caller should attempt to iterate the trie and determine if a range proof
 // should be added to the response.
-func (rb *responseBuilder) fillFromSnapshot(ctx context.Context) (bool, error) {
+func (rb *responseBuilder) fillFromSnapshot(ctx context.Context, snapIt ethdb.Iterator) (bool, error) {
 	snapshotReadStart := time.Now()
 	rb.stats.IncSnapshotReadAttempt()
 
@@ -248,7 +250,7 @@ func (rb *responseBuilder) fillFromSnapshot(ctx context.Context) (bool, error) {
 		snapCtx, cancel = context.WithDeadline(ctx, bufferedDeadline)
 		defer cancel()
 	}
-	snapKeys, snapVals, err := rb.readLeafsFromSnapshot(snapCtx)
+	snapKeys, snapVals, err := rb.readLeafsFromSnapshot(snapCtx, snapIt)
 	// Update read snapshot time here, so that we include the case that an error occurred.
 	rb.stats.UpdateSnapshotReadTime(time.Since(snapshotReadStart))
 	if err != nil {
@@ -454,24 +456,14 @@ func (rb *responseBuilder) fillFromTrie(ctx context.Context, end []byte) (bool,
 	return more, it.Err
 }
 
-// readLeafsFromSnapshot iterates the storage snapshot of the requested account
-// (or the main account trie if account is empty). Returns up to [rb.limit] key/value
-// pairs for keys that are in the request's range (inclusive).
-func (rb *responseBuilder) readLeafsFromSnapshot(ctx context.Context) ([][]byte, [][]byte, error) {
+// readLeafsFromSnapshot iterates [snapIt], the flat view of the storage trie of
+// the requested account (or the main account trie if account is empty). Returns
+// up to [rb.limit] key/value pairs for keys that are in the request's range (inclusive).
+func (rb *responseBuilder) readLeafsFromSnapshot(ctx context.Context, snapIt ethdb.Iterator) ([][]byte, [][]byte, error) {
 	var (
-		snapIt    ethdb.Iterator
-		startHash = common.BytesToHash(rb.request.Start)
-		keys      = make([][]byte, 0, rb.limit)
-		vals      = make([][]byte, 0, rb.limit)
+		keys = make([][]byte, 0, rb.limit)
+		vals = make([][]byte, 0, rb.limit)
 	)
-
-	// Get an iterator into the storage or the main account snapshot.
-	if rb.request.Account == (common.Hash{}) {
-		snapIt = &syncutils.AccountIterator{AccountIterator: rb.snap.DiskAccountIterator(startHash)}
-	} else {
-		snapIt = &syncutils.StorageIterator{StorageIterator: rb.snap.DiskStorageIterator(rb.request.Account, startHash)}
-	}
-	defer snapIt.Release()
 	for snapIt.Next() {
 		// if we're at the end, break this loop
 		if len(rb.request.End) > 0 && bytes.Compare(snapIt.Key(), rb.request.End) > 0 {
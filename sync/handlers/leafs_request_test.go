@@ -15,14 +15,18 @@ import (
 	"github.com/MetalBlockchain/libevm/core/types"
 	"github.com/MetalBlockchain/libevm/crypto"
 	"github.com/MetalBlockchain/libevm/ethdb"
+	"github.com/MetalBlockchain/libevm/rlp"
 	"github.com/MetalBlockchain/libevm/trie"
+	"github.com/MetalBlockchain/libevm/trie/trienode"
 	"github.com/MetalBlockchain/libevm/triedb"
+	"github.com/holiman/uint256"
 	"github.com/stretchr/testify/assert"
 
 	"github.com/MetalBlockchain/coreth/core/state/snapshot"
 	"github.com/MetalBlockchain/coreth/plugin/evm/message"
 	"github.com/MetalBlockchain/coreth/sync/handlers/stats/statstest"
 	"github.com/MetalBlockchain/coreth/sync/statesync/statesynctest"
+	"github.com/MetalBlockchain/coreth/triedb/pathdb"
 )
 
 func TestLeafsRequestHandler_OnLeafsRequest(t *testing.T) {
@@ -234,6 +238,7 @@ func TestLeafsRequestHandler_OnLeafsRequest(t *testing.T) {
 				assert.Len(t, leafsResponse.Keys, 500)
 				assert.Len(t, leafsResponse.Vals, 500)
 				assert.Len(t, leafsResponse.ProofVals, 0)
+				assert.EqualValues(t, 1, testHandlerStats.SnapshotUnavailableCount)
 			},
 		},
 		"nil end range treated like greatest possible value": {
@@ -473,6 +478,7 @@ func TestLeafsRequestHandler_OnLeafsRequest(t *testing.T) {
 				assert.EqualValues(t, 1, testHandlerStats.LeafsRequestCount)
 				assert.EqualValues(t, len(leafsResponse.Keys), testHandlerStats.LeafsReturnedSum)
 				assert.EqualValues(t, 1, testHandlerStats.SnapshotReadAttemptCount)
+				assert.Zero(t, testHandlerStats.SnapshotUnavailableCount)
 				assert.EqualValues(t, 1, testHandlerStats.SnapshotReadSuccessCount)
 				assertRangeProofIsValid(t, &request, &leafsResponse, true)
 			},
@@ -724,3 +730,88 @@ func assertRangeProofIsValid(t *testing.T, request *message.LeafsRequest, respon
 	assert.NoError(t, err)
 	assert.Equal(t, expectMore, more)
 }
+
+func TestLeafsRequestHandler_PathLeafProvider(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	testHandlerStats := &statstest.TestHandlerStats{}
+	diskdb := rawdb.NewMemoryDatabase()
+	trieDB := triedb.NewDatabase(diskdb, &triedb.Config{DBOverride: pathdb.Config{}.BackendConstructor})
+
+	// Commit an account trie with an account owning a storage trie.
+	storageAccount := common.Hash{1}
+	storageTrie, err := trie.New(trie.StorageTrieID(types.EmptyRootHash, storageAccount, types.EmptyRootHash), trieDB)
+	assert.NoError(t, err)
+	for i := 0; i < 2000; i++ {
+		key, val := make([]byte, common.HashLength), make([]byte, 1+r.Intn(40))
+		_, _ = r.Read(key)
+		_, _ = r.Read(val)
+		assert.NoError(t, storageTrie.Update(key, val))
+	}
+	storageRoot, storageNodes, err := storageTrie.Commit(false)
+	assert.NoError(t, err)
+
+	accountTrie, err := trie.New(trie.StateTrieID(types.EmptyRootHash), trieDB)
+	assert.NoError(t, err)
+	for i := 0; i < 2000; i++ {
+		key := make([]byte, common.HashLength)
+		_, _ = r.Read(key)
+		acc := types.StateAccount{
+			Nonce:    uint64(i),
+			Balance:  uint256.NewInt(uint64(i)),
+			Root:     types.EmptyRootHash,
+			CodeHash: types.EmptyCodeHash[:],
+		}
+		if i == 0 {
+			copy(key, storageAccount[:])
+			acc.Root = storageRoot
+		}
+		accBytes, err := rlp.EncodeToBytes(&acc)
+		assert.NoError(t, err)
+		assert.NoError(t, accountTrie.Update(key, accBytes))
+	}
+	stateRoot, accountNodes, err := accountTrie.Commit(false)
+	assert.NoError(t, err)
+
+	nodes := trienode.NewMergedNodeSet()
+	assert.NoError(t, nodes.Merge(accountNodes))
+	assert.NoError(t, nodes.Merge(storageNodes))
+	assert.NoError(t, trieDB.Update(stateRoot, types.EmptyRootHash, 0, nodes, nil))
+	assert.NoError(t, trieDB.Commit(stateRoot, false))
+
+	provider := NewPathLeafProvider(trieDB, diskdb, pathdb.MaxDiffLayers+1).(*pathLeafProvider)
+	leafsHandler := NewLeafsRequestHandlerWithProvider(provider, message.StateTrieKeyLength, message.Codec, testHandlerStats)
+	storageRequest := message.LeafsRequest{
+		Root:     storageRoot,
+		Account:  storageAccount,
+		Start:    bytes.Repeat([]byte{0x40}, common.HashLength),
+		Limit:    maxLeavesLimit,
+		NodeType: message.StateTrieNode,
+	}
+
+	// The storage trie cannot be opened before its state is requested.
+	response, err := leafsHandler.OnLeafsRequest(context.Background(), ids.GenerateTestNodeID(), 1, storageRequest)
+	assert.NoError(t, err)
+	assert.Nil(t, response)
+	assert.EqualValues(t, 1, testHandlerStats.MissingRootCount)
+
+	for _, request := range []message.LeafsRequest{
+		{Root: stateRoot, Limit: maxLeavesLimit, NodeType: message.StateTrieNode},
+		storageRequest,
+		{Root: storageRoot, Account: storageAccount, Start: bytes.Repeat([]byte{0xf0}, common.HashLength), Limit: maxLeavesLimit, NodeType: message.StateTrieNode},
+	} {
+		testHandlerStats.Reset()
+		response, err := leafsHandler.OnLeafsRequest(context.Background(), ids.GenerateTestNodeID(), 1, request)
+		assert.NoError(t, err)
+		var leafsResponse message.LeafsResponse
+		_, err = message.Codec.Unmarshal(response, &leafsResponse)
+		assert.NoError(t, err)
+		assert.NotEmpty(t, leafsResponse.Keys)
+		assert.EqualValues(t, 1, testHandlerStats.SnapshotReadSuccessCount)
+		assertRangeProofIsValid(t, &request, &leafsResponse, len(leafsResponse.Keys) == int(maxLeavesLimit))
+	}
+
+	// The state the storage trie was found in is cached for later requests.
+	cachedStateRoot, ok := provider.storageStates.Get(storageTrieKey{account: storageAccount, root: storageRoot})
+	assert.True(t, ok)
+	assert.Equal(t, stateRoot, cachedStateRoot)
+}
@@ -0,0 +1,101 @@
+// Copyright (C) 2019-2025, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package handlers
+
+import (
+	"context"
+	"time"
+
+	"github.com/MetalBlockchain/metalgo/codec"
+	"github.com/MetalBlockchain/metalgo/ids"
+	"github.com/MetalBlockchain/libevm/core/rawdb"
+	"github.com/MetalBlockchain/libevm/ethdb"
+	"github.com/MetalBlockchain/libevm/log"
+
+	"github.com/MetalBlockchain/coreth/plugin/evm/message"
+	"github.com/MetalBlockchain/coreth/sync/handlers/stats"
+)
+
+// ReceiptsRequestHandler is a peer.RequestHandler for message.ReceiptsRequest
+// serving the stored receipts of requested blocks
+type ReceiptsRequestHandler struct {
+	db    ethdb.Reader
+	codec codec.Manager
+	stats stats.ReceiptsRequestHandlerStats
+}
+
+func NewReceiptsRequestHandler(db ethdb.Reader, codec codec.Manager, handlerStats stats.ReceiptsRequestHandlerStats) *ReceiptsRequestHandler {
+	return &ReceiptsRequestHandler{
+		db:    db,
+		codec: codec,
+		stats: handlerStats,
+	}
+}
+
+// OnReceiptsRequest handles incoming message.ReceiptsRequest, returning the
+// receipts of the requested blocks in order.
+// Never returns error
+// Expects returned errors to be treated as FATAL
+// Returns the receipts of a prefix of the requested blocks if the receipts of
+// the next block are missing, or do not fit in the response.
+// Returns nothing if the receipts of the first block are missing.
+func (r *ReceiptsRequestHandler) OnReceiptsRequest(ctx context.Context, nodeID ids.NodeID, requestID uint32, receiptsRequest message.ReceiptsRequest) ([]byte, error) {
+	startTime := time.Now()
+	r.stats.IncReceiptsRequest()
+
+	receipts := make([][]byte, 0, len(receiptsRequest.Hashes))
+	totalBytes := 0
+
+	// ensure metrics are captured properly on all return paths
+	defer func() {
+		r.stats.UpdateReceiptsRequestProcessingTime(time.Since(startTime))
+		r.stats.UpdateReceiptsReturned(uint16(len(receipts)))
+	}()
+
+	if len(receiptsRequest.Hashes) > message.MaxReceiptsHashesPerRequest {
+		log.Debug("too many hashes requested, dropping request", "nodeID", nodeID, "requestID", requestID, "numHashes", len(receiptsRequest.Hashes))
+		return nil, nil
+	}
+
+	for _, hash := range receiptsRequest.Hashes {
+		if ctx.Err() != nil {
+			break
+		}
+
+		number := rawdb.ReadHeaderNumber(r.db, hash)
+		if number == nil {
+			r.stats.IncMissingReceipts()
+			break
+		}
+		data := rawdb.ReadReceiptsRLP(r.db, hash, *number)
+		if len(data) == 0 {
+			r.stats.IncMissingReceipts()
+			break
+		}
+
+		if len(data)+totalBytes > targetMessageByteSize && len(receipts) > 0 {
+			log.Debug("Skipping receipts due to max total bytes size", "totalReceiptsDataSize", totalBytes, "receiptsSize", len(data), "maxTotalBytesSize", targetMessageByteSize)
+			break
+		}
+
+		receipts = append(receipts, data)
+		totalBytes += len(data)
+	}
+
+	if len(receipts) == 0 {
+		// drop this request
+		log.Debug("no requested receipts found, dropping request", "nodeID", nodeID, "requestID", requestID, "request", receiptsRequest)
+		return nil, nil
+	}
+
+	response := message.ReceiptsResponse{
+		Receipts: receipts,
+	}
+	responseBytes, err := r.codec.Marshal(message.Version, response)
+	if err != nil {
+		log.Error("failed to marshal ReceiptsResponse, dropping request", "nodeID", nodeID, "requestID", requestID, "request", receiptsRequest, "err", err)
+		return nil, nil
+	}
+	return responseBytes, nil
+}
@@ -14,6 +14,7 @@ type HandlerStats interface {
 	BlockRequestHandlerStats
 	CodeRequestHandlerStats
 	LeafsRequestHandlerStats
+	ReceiptsRequestHandlerStats
 }
 
 type BlockRequestHandlerStats interface {
@@ -32,6 +33,13 @@ type CodeRequestHandlerStats interface {
 	UpdateCodeBytesReturned(bytes uint32)
 }
 
+type ReceiptsRequestHandlerStats interface {
+	IncReceiptsRequest()
+	IncMissingReceipts()
+	UpdateReceiptsReturned(num uint16)
+	UpdateReceiptsRequestProcessingTime(duration time.Duration)
+}
+
 type LeafsRequestHandlerStats interface {
 	IncLeafsRequest()
 	IncInvalidLeafsRequest()
@@ -49,6 +57,7 @@ type LeafsRequestHandlerStats interface {
 	IncSnapshotReadSuccess()
 	IncSnapshotSegmentValid()
 	IncSnapshotSegmentInvalid()
+	IncSnapshotUnavailable()
 }
 
 type handlerStats struct {
@@ -83,6 +92,13 @@ type handlerStats struct {
 	snapshotReadSuccess        metrics.Counter
 	snapshotSegmentValid       metrics.Counter
 	snapshotSegmentInvalid     metrics.Counter
+	snapshotUnavailable        metrics.Counter
+
+	// ReceiptsRequestHandler stats
+	receiptsRequest               metrics.Counter
+	missingReceipts               metrics.Counter
+	receiptsReturned              metrics.Histogram
+	receiptsRequestProcessingTime metrics.Timer
 }
 
 func (h *handlerStats) IncBlockRequest() {
@@ -165,6 +181,23 @@ func (h *handlerStats) IncSnapshotReadAttempt()    { h.snapshotReadAttempt.Inc(1
 func (h *handlerStats) IncSnapshotReadSuccess()    { h.snapshotReadSuccess.Inc(1) }
 func (h *handlerStats) IncSnapshotSegmentValid()   { h.snapshotSegmentValid.Inc(1) }
 func (h *handlerStats) IncSnapshotSegmentInvalid() { h.snapshotSegmentInvalid.Inc(1) }
+func (h *handlerStats) IncSnapshotUnavailable()    { h.snapshotUnavailable.Inc(1) }
+
+func (h *handlerStats) IncReceiptsRequest() {
+	h.receiptsRequest.Inc(1)
+}
+
+func (h *handlerStats) IncMissingReceipts() {
+	h.missingReceipts.Inc(1)
+}
+
+func (h *handlerStats) UpdateReceiptsReturned(num uint16) {
+	h.receiptsReturned.Update(int64(num))
+}
+
+func (h *handlerStats) UpdateReceiptsRequestProcessingTime(duration time.Duration) {
+	h.receiptsRequestProcessingTime.Update(duration)
+}
 
 // GetOrRegisterHandlerStats returns a [HandlerStats] to track state sync handler metrics.
 // If `enabled` is false, a no-op implementation is returned.
@@ -205,6 +238,13 @@ func GetOrRegisterHandlerStats(enabled bool) HandlerStats {
 		snapshotReadSuccess:        metrics.GetOrRegisterCounter("leafs_request_snapshot_read_success", nil),
 		snapshotSegmentValid:       metrics.GetOrRegisterCounter("leafs_request_snapshot_segment_valid", nil),
 		snapshotSegmentInvalid:     metrics.GetOrRegisterCounter("leafs_request_snapshot_segment_invalid", nil),
+		snapshotUnavailable:        metrics.GetOrRegisterCounter("leafs_request_snapshot_unavailable", nil),
+
+		// initialize receipts request stats
+		receiptsRequest:               metrics.GetOrRegisterCounter("receipts_request_count", nil),
+		missingReceipts:               metrics.GetOrRegisterCounter("receipts_request_missing_receipts", nil),
+		receiptsReturned:              metrics.GetOrRegisterHistogram("receipts_request_total_blocks", nil, metrics.NewExpDecaySample(1028, 0.015)),
+		receiptsRequestProcessingTime: metrics.GetOrRegisterTimer("receipts_request_processing_time", nil),
 	}
 }
 
@@ -216,29 +256,34 @@ func NewNoopHandlerStats() HandlerStats {
 }
 
 // all operations are no-ops
-func (*noopHandlerStats) IncBlockRequest()                               {}
-func (*noopHandlerStats) IncMissingBlockHash()                           {}
-func (*noopHandlerStats) UpdateBlocksReturned(uint16)                    {}
-func (*noopHandlerStats) UpdateBlockRequestProcessingTime(time.Duration) {}
-func (*noopHandlerStats) IncCodeRequest()                                {}
-func (*noopHandlerStats) IncMissingCodeHash()                            {}
-func (*noopHandlerStats) IncTooManyHashesRequested()                     {}
-func (*noopHandlerStats) IncDuplicateHashesRequested()                   {}
-func (*noopHandlerStats) UpdateCodeReadTime(time.Duration)               {}
-func (*noopHandlerStats) UpdateCodeBytesReturned(uint32)                 {}
-func (*noopHandlerStats) IncLeafsRequest()                               {}
-func (*noopHandlerStats) IncInvalidLeafsRequest()                        {}
-func (*noopHandlerStats) UpdateLeafsRequestProcessingTime(time.Duration) {}
-func (*noopHandlerStats) UpdateLeafsReturned(uint16)                     {}
-func (*noopHandlerStats) UpdateReadLeafsTime(_ time.Duration)            {}
-func (*noopHandlerStats) UpdateSnapshotReadTime(_ time.Duration)         {}
-func (*noopHandlerStats) UpdateGenerateRangeProofTime(_ time.Duration)   {}
-func (*noopHandlerStats) UpdateRangeProofValsReturned(_ int64)           {}
-func (*noopHandlerStats) IncMissingRoot()                                {}
-func (*noopHandlerStats) IncTrieError()                                  {}
-func (*noopHandlerStats) IncProofError()                                 {}
-func (*noopHandlerStats) IncSnapshotReadError()                          {}
-func (*noopHandlerStats) IncSnapshotReadAttempt()                        {}
-func (*noopHandlerStats) IncSnapshotReadSuccess()                        {}
-func (*noopHandlerStats) IncSnapshotSegmentValid()                       {}
-func (*noopHandlerStats) IncSnapshotSegmentInvalid()                     {}
+func (*noopHandlerStats) IncBlockRequest()                                  {}
+func (*noopHandlerStats) IncMissingBlockHash()                              {}
+func (*noopHandlerStats) UpdateBlocksReturned(uint16)                       {}
+func (*noopHandlerStats) UpdateBlockRequestProcessingTime(time.Duration)    {}
+func (*noopHandlerStats) IncCodeRequest()                                   {}
+func (*noopHandlerStats) IncMissingCodeHash()                               {}
+func (*noopHandlerStats) IncTooManyHashesRequested()                        {}
+func (*noopHandlerStats) IncDuplicateHashesRequested()                      {}
+func (*noopHandlerStats) UpdateCodeReadTime(time.Duration)                  {}
+func (*noopHandlerStats) UpdateCodeBytesReturned(uint32)                    {}
+func (*noopHandlerStats) IncLeafsRequest()                                  {}
+func (*noopHandlerStats) IncInvalidLeafsRequest()                           {}
+func (*noopHandlerStats) UpdateLeafsRequestProcessingTime(time.Duration)    {}
+func (*noopHandlerStats) UpdateLeafsReturned(uint16)                        {}
+func (*noopHandlerStats) UpdateReadLeafsTime(_ time.Duration)               {}
+func (*noopHandlerStats) UpdateSnapshotReadTime(_ time.Duration)            {}
+func (*noopHandlerStats) UpdateGenerateRangeProofTime(_ time.Duration)      {}
+func (*noopHandlerStats) UpdateRangeProofValsReturned(_ int64)              {}
+func (*noopHandlerStats) IncMissingRoot()                                   {}
+func (*noopHandlerStats) IncTrieError()                                     {}
+func (*noopHandlerStats) IncProofError()                                    {}
+func (*noopHandlerStats) IncSnapshotReadError()                             {}
+func (*noopHandlerStats) IncSnapshotReadAttempt()                           {}
+func (*noopHandlerStats) IncSnapshotReadSuccess()                           {}
+func (*noopHandlerStats) IncSnapshotSegmentValid()                          {}
+func (*noopHandlerStats) IncSnapshotSegmentInvalid()                        {}
+func (*noopHandlerStats) IncSnapshotUnavailable()                           {}
+func (*noopHandlerStats) IncReceiptsRequest()                               {}
+func (*noopHandlerStats) IncMissingReceipts()                               {}
+func (*noopHandlerStats) UpdateReceiptsReturned(uint16)                     {}
+func (*noopHandlerStats) UpdateReceiptsRequestProcessingTime(time.Duration) {}
@@ -38,12 +38,18 @@ type TestHandlerStats struct {
 	SnapshotReadAttemptCount,
 	SnapshotReadSuccessCount,
 	SnapshotSegmentValidCount,
-	SnapshotSegmentInvalidCount uint32
+	SnapshotSegmentInvalidCount,
+	SnapshotUnavailableCount uint32
 	ProofValsReturned int64
 	LeafsReadTime,
 	SnapshotReadTime,
 	GenerateRangeProofTime,
 	LeafRequestProcessingTimeSum time.Duration
+
+	ReceiptsRequestCount,
+	MissingReceiptsCount,
+	ReceiptsReturnedSum uint32
+	ReceiptsRequestProcessingTimeSum time.Duration
 }
 
 func (m *TestHandlerStats) Reset() {
@@ -70,11 +76,16 @@ func (m *TestHandlerStats) Reset() {
 	m.SnapshotReadSuccessCount = 0
 	m.SnapshotSegmentValidCount = 0
 	m.SnapshotSegmentInvalidCount = 0
+	m.SnapshotUnavailableCount = 0
 	m.ProofValsReturned = 0
 	m.LeafsReadTime = 0
 	m.SnapshotReadTime = 0
 	m.GenerateRangeProofTime = 0
 	m.LeafRequestProcessingTimeSum = 0
+	m.ReceiptsRequestCount = 0
+	m.MissingReceiptsCount = 0
+	m.ReceiptsReturnedSum = 0
+	m.ReceiptsRequestProcessingTimeSum = 0
 }
 
 func (m *TestHandlerStats) IncBlockRequest() {
@@ -232,3 +243,33 @@ func (m *TestHandlerStats) IncSnapshotSegmentInvalid() {
 	defer m.lock.Unlock()
 	m.SnapshotSegmentInvalidCount++
 }
+
+func (m *TestHandlerStats) IncSnapshotUnavailable() {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.SnapshotUnavailableCount++
+}
+
+func (m *TestHandlerStats) IncReceiptsRequest() {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.ReceiptsRequestCount++
+}
+
+func (m *TestHandlerStats) IncMissingReceipts() {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.MissingReceiptsCount++
+}
+
+func (m *TestHandlerStats) UpdateReceiptsReturned(num uint16) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.ReceiptsReturnedSum += uint32(num)
+}
+
+func (m *TestHandlerStats) UpdateReceiptsRequestProcessingTime(duration time.Duration) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.ReceiptsRequestProcessingTimeSum += duration
+}
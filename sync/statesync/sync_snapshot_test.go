@@ -0,0 +1,104 @@
+// Copyright (C) 2019-2025, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package statesync
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/MetalBlockchain/libevm/common"
+	"github.com/MetalBlockchain/libevm/core/rawdb"
+	"github.com/MetalBlockchain/libevm/ethdb"
+	"github.com/MetalBlockchain/libevm/triedb"
+	"github.com/stretchr/testify/require"
+
+	"github.com/MetalBlockchain/coreth/plugin/evm/message"
+	"github.com/MetalBlockchain/coreth/sync/handlers"
+	"github.com/MetalBlockchain/coreth/sync/syncfile"
+
+	statesyncclient "github.com/MetalBlockchain/coreth/sync/client"
+	handlerstats "github.com/MetalBlockchain/coreth/sync/handlers/stats"
+)
+
+// exportTestSnapshot exports the state at [root] in [serverDB] to a new
+// snapshot with [requestSize] leaves per response.
+func exportTestSnapshot(t *testing.T, serverDB ethdb.Database, serverTrieDB *triedb.Database, root common.Hash, requestSize uint16) (string, message.Syncable) {
+	t.Helper()
+	leafsRequestHandler := handlers.NewLeafsRequestHandler(serverTrieDB, message.StateTrieKeyLength, nil, message.Codec, handlerstats.NewNoopHandlerStats())
+	codeRequestHandler := handlers.NewCodeRequestHandler(serverDB, message.Codec, handlerstats.NewNoopHandlerStats())
+	source := statesyncclient.NewTestClient(message.Codec, leafsRequestHandler, codeRequestHandler, nil)
+
+	summary, err := message.NewBlockSyncSummary(common.Hash{1}, 1, root)
+	require.NoError(t, err)
+	dir := t.TempDir()
+	_, err = syncfile.Export(context.Background(), source, syncfile.ExportConfig{
+		Dir:           dir,
+		Summary:       summary,
+		Codec:         message.Codec,
+		RequestSize:   requestSize,
+		ChunkFileSize: 64 * 1024, // Use small chunks to get test coverage of multiple chunk files.
+	})
+	require.NoError(t, err)
+	return dir, summary
+}
+
+func TestSyncFromSnapshot(t *testing.T) {
+	for _, requestSize := range []uint16{100, testRequestSize, 2 * testRequestSize} {
+		t.Run(fmt.Sprintf("request size %d", requestSize), func(t *testing.T) {
+			require := require.New(t)
+			serverDB := rawdb.NewMemoryDatabase()
+			serverTrieDB := triedb.NewDatabase(serverDB, nil)
+			root := fillAccountsWithStorage(t, serverDB, serverTrieDB, common.Hash{}, 2000)
+			dir, summary := exportTestSnapshot(t, serverDB, serverTrieDB, root, requestSize)
+
+			reader, err := syncfile.Open(os.DirFS(dir))
+			require.NoError(err)
+			defer reader.Close()
+			client := statesyncclient.NewFileClient(reader, message.Codec, nil)
+
+			require.NoError(syncfile.Verify(context.Background(), client, summary, reader.Manifest()))
+
+			clientDB := rawdb.NewMemoryDatabase()
+			s, err := NewSyncer(client, clientDB, root, Config{
+				BatchSize:   1000,
+				RequestSize: testRequestSize,
+			})
+			require.NoError(err)
+			require.NoError(s.Sync(context.Background()))
+			assertDBConsistency(t, root, clientDB, serverTrieDB, triedb.NewDatabase(clientDB, nil))
+		})
+	}
+}
+
+func TestSyncFromCorruptedSnapshot(t *testing.T) {
+	require := require.New(t)
+	serverDB := rawdb.NewMemoryDatabase()
+	serverTrieDB := triedb.NewDatabase(serverDB, nil)
+	root := fillAccountsWithStorage(t, serverDB, serverTrieDB, common.Hash{}, 250)
+	dir, _ := exportTestSnapshot(t, serverDB, serverTrieDB, root, testRequestSize)
+
+	// Flip a byte in the middle of the first chunk, which holds the first
+	// leafs of the account trie.
+	chunk := filepath.Join(dir, "chunk-000000.bin")
+	data, err := os.ReadFile(chunk)
+	require.NoError(err)
+	data[len(data)/2] ^= 0xff
+	require.NoError(os.WriteFile(chunk, data, 0o644))
+
+	reader, err := syncfile.Open(os.DirFS(dir))
+	if err != nil {
+		// The corrupted byte was part of a record header.
+		return
+	}
+	defer reader.Close()
+	s, err := NewSyncer(statesyncclient.NewFileClient(reader, message.Codec, nil), rawdb.NewMemoryDatabase(), root, Config{
+		BatchSize:   1000,
+		RequestSize: testRequestSize,
+	})
+	require.NoError(err)
+	require.Error(s.Sync(context.Background()))
+}
@@ -0,0 +1,334 @@
+// Copyright (C) 2019-2025, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package syncfile
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/MetalBlockchain/metalgo/codec"
+	"github.com/MetalBlockchain/metalgo/utils/units"
+	"github.com/MetalBlockchain/libevm/common"
+	"github.com/MetalBlockchain/libevm/core/types"
+	"github.com/MetalBlockchain/libevm/log"
+	"github.com/MetalBlockchain/libevm/rlp"
+
+	"github.com/MetalBlockchain/coreth/plugin/evm/message"
+	"github.com/MetalBlockchain/coreth/utils"
+)
+
+const (
+	// DefaultChunkFileSize is the size after which a new chunk file is started.
+	DefaultChunkFileSize = 256 * units.MiB
+
+	// blocksPerRequest is the number of blocks requested from the source at
+	// a time.
+	blocksPerRequest = 32
+	// codesPerRequest is the number of code hashes requested from the source
+	// at a time.
+	codesPerRequest = 5
+)
+
+var (
+	errNoRequestSize = errors.New("request size must be non-zero")
+	errNoLeafs       = errors.New("found no keys in a response with more set to true")
+
+	errManifestMismatch = errors.New("snapshot does not match manifest")
+)
+
+// Source serves the data of a state summary. It is implemented by the state
+// sync clients, which verify every response before returning it.
+type Source interface {
+	GetLeafs(ctx context.Context, request message.LeafsRequest) (message.LeafsResponse, error)
+	GetCode(ctx context.Context, hashes []common.Hash) ([][]byte, error)
+	GetBlocks(ctx context.Context, blockHash common.Hash, height uint64, parents uint16) ([]*types.Block, error)
+}
+
+// ExportConfig configures the export of a snapshot.
+type ExportConfig struct {
+	// Dir is the directory to write the snapshot to. It must not already
+	// contain a snapshot.
+	Dir string
+	// Summary is the state summary to export.
+	Summary message.Syncable
+	// Codec marshals the exported [message.LeafsResponse]s.
+	Codec codec.Manager
+	// RequestSize is the maximum number of leaves in each response.
+	RequestSize uint16
+	// Blocks is the number of blocks to export, ending at the summary block.
+	Blocks uint64
+	// ChunkFileSize is the size after which a new chunk file is started. If
+	// zero, [DefaultChunkFileSize] is used.
+	ChunkFileSize int64
+}
+
+// exporter writes the responses of a [Source] to a snapshot. If [writer] is
+// nil, the responses are only read, to verify a snapshot.
+type exporter struct {
+	source   Source
+	config   ExportConfig
+	writer   *writer
+	manifest *Manifest
+
+	storageRoots map[common.Hash]struct{}
+	codeHashes   map[common.Hash]struct{}
+}
+
+// Export writes a snapshot of [config.Summary], as served by [source], to
+// [config.Dir]. The account trie, every storage trie it references, the
+// code of every contract, any extra tries committed to by the summary (see
+// [message.ExtendedSyncable]) and the [config.Blocks] blocks ending at the
+// summary block are exported.
+func Export(ctx context.Context, source Source, config ExportConfig) (*Manifest, error) {
+	if config.RequestSize == 0 {
+		return nil, errNoRequestSize
+	}
+	if config.ChunkFileSize == 0 {
+		config.ChunkFileSize = DefaultChunkFileSize
+	}
+	w, err := newWriter(config.Dir, config.ChunkFileSize)
+	if err != nil {
+		return nil, err
+	}
+	summary := config.Summary
+	e := &exporter{
+		source:       source,
+		config:       config,
+		writer:       w,
+		manifest:     newManifest(summary, config.RequestSize),
+		storageRoots: make(map[common.Hash]struct{}),
+		codeHashes:   make(map[common.Hash]struct{}),
+	}
+
+	log.Info("exporting state sync snapshot", "dir", config.Dir, "summary", summary)
+	exportErr := e.export(ctx)
+	chunks, err := w.close()
+	if exportErr != nil {
+		return nil, exportErr
+	}
+	if err != nil {
+		return nil, err
+	}
+	e.manifest.Chunks = chunks
+	if err := writeManifest(config.Dir, e.manifest); err != nil {
+		return nil, err
+	}
+	log.Info("exported state sync snapshot", "dir", config.Dir, "chunks", len(chunks),
+		"leafs", e.manifest.Leafs, "codes", e.manifest.Codes, "blocks", e.manifest.Blocks)
+	return e.manifest, nil
+}
+
+// Verify checks that the snapshot described by [manifest] holds the complete
+// state of [summary], by reading every trie, contract and block from
+// [source] the same way they were exported. [source] is expected to verify
+// each response, as the state sync clients do.
+func Verify(ctx context.Context, source Source, summary message.Syncable, manifest *Manifest) error {
+	if manifest.RequestSize == 0 {
+		return errNoRequestSize
+	}
+	expected := newManifest(summary, manifest.RequestSize)
+	if manifest.SummaryID != expected.SummaryID {
+		return fmt.Errorf("%w: summary ID %s does not match %s", errManifestMismatch, manifest.SummaryID, expected.SummaryID)
+	}
+	if manifest.BlockHash != expected.BlockHash || manifest.BlockNumber != expected.BlockNumber || manifest.BlockRoot != expected.BlockRoot {
+		return fmt.Errorf("%w: block %d (%s) with root %s does not match the summary", errManifestMismatch, manifest.BlockNumber, manifest.BlockHash, manifest.BlockRoot)
+	}
+	if !slices.Equal(manifest.Tries, expected.Tries) {
+		return fmt.Errorf("%w: tries do not match the summary", errManifestMismatch)
+	}
+
+	e := &exporter{
+		source:       source,
+		config:       ExportConfig{RequestSize: manifest.RequestSize, Blocks: manifest.Blocks},
+		manifest:     expected,
+		storageRoots: make(map[common.Hash]struct{}),
+		codeHashes:   make(map[common.Hash]struct{}),
+	}
+	if err := e.export(ctx); err != nil {
+		return err
+	}
+	if e.manifest.Leafs != manifest.Leafs || e.manifest.Codes != manifest.Codes || e.manifest.Blocks != manifest.Blocks {
+		return fmt.Errorf("%w: read %d leafs, %d codes and %d blocks but expected %d, %d and %d", errManifestMismatch,
+			e.manifest.Leafs, e.manifest.Codes, e.manifest.Blocks, manifest.Leafs, manifest.Codes, manifest.Blocks)
+	}
+	return nil
+}
+
+// newManifest returns the manifest of a snapshot of [summary], without any
+// chunks.
+func newManifest(summary message.Syncable, requestSize uint16) *Manifest {
+	manifest := &Manifest{
+		Version:     Version,
+		Summary:     summary.Bytes(),
+		SummaryID:   summary.ID(),
+		BlockHash:   summary.GetBlockHash(),
+		BlockNumber: summary.Height(),
+		BlockRoot:   summary.GetBlockRoot(),
+		Tries:       []Trie{{NodeType: message.StateTrieNode, Root: summary.GetBlockRoot()}},
+		RequestSize: requestSize,
+	}
+	if extended, ok := summary.(message.ExtendedSyncable); ok {
+		for nodeType, root := range extended.ExtraTrieRoots() {
+			manifest.Tries = append(manifest.Tries, Trie{NodeType: nodeType, Root: root})
+		}
+		// Sort the extra tries so that manifests can be compared.
+		slices.SortFunc(manifest.Tries[1:], func(a, b Trie) int {
+			return int(a.NodeType) - int(b.NodeType)
+		})
+	}
+	return manifest
+}
+
+func (e *exporter) export(ctx context.Context) error {
+	for _, trie := range e.manifest.Tries {
+		var onLeafs func(ctx context.Context, keys, vals [][]byte) error
+		if trie.NodeType == message.StateTrieNode {
+			onLeafs = e.onAccounts
+		}
+		if err := e.exportTrie(ctx, trie.NodeType, trie.Root, common.Hash{}, onLeafs); err != nil {
+			return fmt.Errorf("failed to export trie %s: %w", trie.Root, err)
+		}
+	}
+	return e.exportBlocks(ctx)
+}
+
+// exportTrie writes the leaves of the trie with [root] in responses of at
+// most [ExportConfig.RequestSize] leaves, invoking [onLeafs] (if non-nil)
+// with the leaves of each response.
+//
+// Each response starts one past the last key of the previous one, as
+// requested by the state syncer. Since the syncer may also start requests
+// from other keys (e.g. when splitting a trie into segments, or resuming),
+// nodes syncing from the snapshot serve those requests from the response
+// covering the requested start key.
+func (e *exporter) exportTrie(ctx context.Context, nodeType message.NodeType, root common.Hash, account common.Hash, onLeafs func(ctx context.Context, keys, vals [][]byte) error) error {
+	var start []byte
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		response, err := e.source.GetLeafs(ctx, message.LeafsRequest{
+			Root:     root,
+			Account:  account,
+			Start:    start,
+			Limit:    e.config.RequestSize,
+			NodeType: nodeType,
+		})
+		if err != nil {
+			return err
+		}
+		if e.writer != nil {
+			payload, err := e.config.Codec.Marshal(message.Version, response)
+			if err != nil {
+				return err
+			}
+			header := &recordHeader{kind: leafsRecord, nodeType: nodeType, hash: root, start: start}
+			if err := e.writer.write(header, payload); err != nil {
+				return err
+			}
+		}
+		e.manifest.Leafs += uint64(len(response.Keys))
+		if onLeafs != nil {
+			if err := onLeafs(ctx, response.Keys, response.Vals); err != nil {
+				return err
+			}
+		}
+
+		if !response.More {
+			return nil
+		}
+		if len(response.Keys) == 0 {
+			return errNoLeafs
+		}
+		start = common.CopyBytes(response.Keys[len(response.Keys)-1])
+		utils.IncrOne(start)
+	}
+}
+
+// onAccounts exports the storage tries and code referenced by the accounts
+// in [vals] that have not been exported yet.
+func (e *exporter) onAccounts(ctx context.Context, keys, vals [][]byte) error {
+	var codeHashes []common.Hash
+	for i, key := range keys {
+		var acc types.StateAccount
+		if err := rlp.DecodeBytes(vals[i], &acc); err != nil {
+			return fmt.Errorf("could not decode account %x: %w", key, err)
+		}
+		if acc.Root != (common.Hash{}) && acc.Root != types.EmptyRootHash {
+			if _, ok := e.storageRoots[acc.Root]; !ok {
+				e.storageRoots[acc.Root] = struct{}{}
+				// Storage tries are exported as soon as they are found, so
+				// that only their roots need to be kept in memory.
+				err := e.exportTrie(ctx, message.StateTrieNode, acc.Root, common.BytesToHash(key), nil)
+				if err != nil {
+					return fmt.Errorf("failed to export storage trie %s of account %x: %w", acc.Root, key, err)
+				}
+			}
+		}
+		codeHash := common.BytesToHash(acc.CodeHash)
+		if codeHash != (common.Hash{}) && codeHash != types.EmptyCodeHash {
+			if _, ok := e.codeHashes[codeHash]; !ok {
+				e.codeHashes[codeHash] = struct{}{}
+				codeHashes = append(codeHashes, codeHash)
+			}
+		}
+	}
+	return e.exportCode(ctx, codeHashes)
+}
+
+func (e *exporter) exportCode(ctx context.Context, hashes []common.Hash) error {
+	for len(hashes) > 0 {
+		batch := hashes[:min(len(hashes), codesPerRequest)]
+		hashes = hashes[len(batch):]
+		codes, err := e.source.GetCode(ctx, batch)
+		if err != nil {
+			return err
+		}
+		for i, code := range codes {
+			if err := e.write(&recordHeader{kind: codeRecord, hash: batch[i]}, code); err != nil {
+				return err
+			}
+			e.manifest.Codes++
+		}
+	}
+	return nil
+}
+
+// exportBlocks writes the [ExportConfig.Blocks] blocks ending at the summary
+// block, or all blocks down to genesis if there are fewer.
+func (e *exporter) exportBlocks(ctx context.Context) error {
+	hash := e.manifest.BlockHash
+	height := e.manifest.BlockNumber
+	for e.manifest.Blocks < e.config.Blocks && hash != (common.Hash{}) {
+		parents := min(e.config.Blocks-e.manifest.Blocks, blocksPerRequest)
+		blocks, err := e.source.GetBlocks(ctx, hash, height, uint16(parents))
+		if err != nil {
+			return fmt.Errorf("failed to export block %d (%s): %w", height, hash, err)
+		}
+		for _, block := range blocks {
+			encoded, err := rlp.EncodeToBytes(block)
+			if err != nil {
+				return err
+			}
+			if err := e.write(&recordHeader{kind: blockRecord, hash: block.Hash()}, encoded); err != nil {
+				return err
+			}
+			e.manifest.Blocks++
+			hash = block.ParentHash()
+			height--
+		}
+	}
+	return nil
+}
+
+// write writes a record to the snapshot, unless the snapshot is only being
+// verified.
+func (e *exporter) write(header *recordHeader, payload []byte) error {
+	if e.writer == nil {
+		return nil
+	}
+	return e.writer.write(header, payload)
+}
@@ -0,0 +1,105 @@
+// Copyright (C) 2019-2025, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package syncfile implements a file-based format for state sync snapshots.
+//
+// A snapshot is a directory holding a manifest and a set of chunk files. The
+// chunk files hold the responses a node would serve to state sync peers for a
+// single state summary: the leaves of every trie with their range proofs in
+// the [message.LeafsResponse] format, the contract code and the blocks
+// preceding the summary. Since the range proofs are included, a node syncing
+// from a snapshot verifies it against the summary roots exactly like it
+// verifies responses received from the network.
+package syncfile
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/MetalBlockchain/metalgo/ids"
+	"github.com/MetalBlockchain/libevm/common"
+	"github.com/MetalBlockchain/libevm/common/hexutil"
+
+	"github.com/MetalBlockchain/coreth/plugin/evm/message"
+)
+
+const (
+	// ManifestName is the name of the manifest within a snapshot directory.
+	// It is written last, so its presence marks a complete snapshot.
+	ManifestName = "manifest.json"
+
+	// Version is the version of the snapshot format.
+	Version = 1
+
+	chunkNameFormat = "chunk-%06d.bin"
+)
+
+var errUnsupportedVersion = errors.New("unsupported snapshot version")
+
+// Trie identifies a trie exported in a snapshot.
+type Trie struct {
+	NodeType message.NodeType `json:"nodeType"`
+	Root     common.Hash      `json:"root"`
+}
+
+// Manifest describes the contents of a snapshot.
+type Manifest struct {
+	Version int `json:"version"`
+
+	// Summary is the state summary the snapshot was exported at, as returned
+	// by the VM's summary provider.
+	Summary     hexutil.Bytes `json:"summary"`
+	SummaryID   ids.ID        `json:"summaryID"`
+	BlockHash   common.Hash   `json:"blockHash"`
+	BlockNumber uint64        `json:"blockNumber"`
+	BlockRoot   common.Hash   `json:"blockRoot"`
+
+	// Tries are the top-level tries of the snapshot, i.e. the account trie
+	// and any extra tries committed to by the summary. Storage tries are
+	// exported as they are referenced by the account trie.
+	Tries []Trie `json:"tries"`
+
+	// RequestSize is the maximum number of leaves in each response. Nodes
+	// syncing from the snapshot must request at least this many leaves.
+	RequestSize uint16 `json:"requestSize"`
+
+	// Chunks are the names of the chunk files, relative to the manifest.
+	Chunks []string `json:"chunks"`
+
+	Leafs  uint64 `json:"leafs"`
+	Codes  uint64 `json:"codes"`
+	Blocks uint64 `json:"blocks"`
+}
+
+// ReadManifest reads the manifest of the snapshot in [fsys].
+func ReadManifest(fsys fs.FS) (*Manifest, error) {
+	data, err := fs.ReadFile(fsys, ManifestName)
+	if err != nil {
+		return nil, err
+	}
+	manifest := new(Manifest)
+	if err := json.Unmarshal(data, manifest); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", ManifestName, err)
+	}
+	if manifest.Version != Version {
+		return nil, fmt.Errorf("%w: %d", errUnsupportedVersion, manifest.Version)
+	}
+	return manifest, nil
+}
+
+// writeManifest atomically writes [manifest] to [dir].
+func writeManifest(dir string, manifest *Manifest) error {
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := filepath.Join(dir, ManifestName+".tmp")
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, filepath.Join(dir, ManifestName))
+}
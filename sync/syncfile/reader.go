@@ -0,0 +1,201 @@
+// Copyright (C) 2019-2025, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package syncfile
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"slices"
+
+	"github.com/MetalBlockchain/libevm/common"
+
+	"github.com/MetalBlockchain/coreth/plugin/evm/message"
+)
+
+// ErrNotFound is returned when a snapshot does not contain the requested
+// data.
+var ErrNotFound = errors.New("not found in snapshot")
+
+// location is the position of a record's payload within the chunk files.
+type location struct {
+	chunk  int
+	offset int64
+	length uint32
+}
+
+// leafsLocation is the location of a leafs record starting at [start].
+type leafsLocation struct {
+	start []byte
+	location
+}
+
+type trieKey struct {
+	nodeType message.NodeType
+	root     common.Hash
+}
+
+// Reader serves the records of a snapshot. Its methods are safe for
+// concurrent use.
+type Reader struct {
+	manifest *Manifest
+	chunks   []io.ReaderAt
+	closers  []io.Closer
+
+	// leafs holds the leafs records of each trie, sorted by start key.
+	leafs  map[trieKey][]leafsLocation
+	codes  map[common.Hash]location
+	blocks map[common.Hash]location
+}
+
+// Open reads the manifest of the snapshot in [fsys] and indexes its chunk
+// files. [fsys] is typically a local directory opened with [os.DirFS], but
+// may be backed by any storage, such as an object store.
+//
+// Chunk files that do not implement [io.ReaderAt] are read into memory.
+func Open(fsys fs.FS) (*Reader, error) {
+	manifest, err := ReadManifest(fsys)
+	if err != nil {
+		return nil, err
+	}
+	r := &Reader{
+		manifest: manifest,
+		leafs:    make(map[trieKey][]leafsLocation),
+		codes:    make(map[common.Hash]location),
+		blocks:   make(map[common.Hash]location),
+	}
+	for i, name := range manifest.Chunks {
+		chunk, size, err := r.openChunk(fsys, name)
+		if err != nil {
+			_ = r.Close()
+			return nil, fmt.Errorf("failed to open chunk %s: %w", name, err)
+		}
+		r.chunks = append(r.chunks, chunk)
+		if err := r.index(i, chunk, size); err != nil {
+			_ = r.Close()
+			return nil, fmt.Errorf("failed to index chunk %s: %w", name, err)
+		}
+	}
+	for _, locations := range r.leafs {
+		slices.SortFunc(locations, func(a, b leafsLocation) int {
+			return bytes.Compare(a.start, b.start)
+		})
+	}
+	return r, nil
+}
+
+// openChunk opens the chunk file [name] and returns it along with its size.
+func (r *Reader) openChunk(fsys fs.FS, name string) (io.ReaderAt, int64, error) {
+	file, err := fsys.Open(name)
+	if err != nil {
+		return nil, 0, err
+	}
+	if readerAt, ok := file.(io.ReaderAt); ok {
+		r.closers = append(r.closers, file)
+		info, err := file.Stat()
+		if err != nil {
+			return nil, 0, err
+		}
+		return readerAt, info.Size(), nil
+	}
+	defer file.Close()
+	data, err := io.ReadAll(file)
+	if err != nil {
+		return nil, 0, err
+	}
+	return bytes.NewReader(data), int64(len(data)), nil
+}
+
+// index records the location of every record in [chunk] of [size] bytes.
+func (r *Reader) index(i int, chunk io.ReaderAt, size int64) error {
+	for offset := int64(0); offset < size; {
+		header, err := readRecordHeader(chunk, offset)
+		if err != nil {
+			return err
+		}
+		loc := location{
+			chunk:  i,
+			offset: offset + header.size(),
+			length: header.length,
+		}
+		offset = loc.offset + int64(loc.length)
+		if offset > size {
+			return fmt.Errorf("%w: truncated payload at offset %d", errInvalidRecord, loc.offset)
+		}
+		switch header.kind {
+		case leafsRecord:
+			key := trieKey{nodeType: header.nodeType, root: header.hash}
+			r.leafs[key] = append(r.leafs[key], leafsLocation{start: header.start, location: loc})
+		case codeRecord:
+			r.codes[header.hash] = loc
+		case blockRecord:
+			r.blocks[header.hash] = loc
+		}
+	}
+	return nil
+}
+
+// Manifest returns the manifest of the snapshot.
+func (r *Reader) Manifest() *Manifest {
+	return r.manifest
+}
+
+// Leafs returns the marshalled [message.LeafsResponse] of the trie of
+// [nodeType] with [root] that covers [start], along with the start key it
+// was requested with. The response starts at or before [start].
+func (r *Reader) Leafs(nodeType message.NodeType, root common.Hash, start []byte) ([]byte, []byte, error) {
+	locations := r.leafs[trieKey{nodeType: nodeType, root: root}]
+	// Find the last response starting at or before [start].
+	i, found := slices.BinarySearchFunc(locations, start, func(l leafsLocation, start []byte) int {
+		return bytes.Compare(l.start, start)
+	})
+	if !found {
+		i--
+	}
+	if i < 0 {
+		return nil, nil, fmt.Errorf("%w: leafs of trie %s (type %d) at %x", ErrNotFound, root, nodeType, start)
+	}
+	response, err := r.read(locations[i].location)
+	if err != nil {
+		return nil, nil, err
+	}
+	return locations[i].start, response, nil
+}
+
+// Code returns the code with [hash].
+func (r *Reader) Code(hash common.Hash) ([]byte, error) {
+	loc, ok := r.codes[hash]
+	if !ok {
+		return nil, fmt.Errorf("%w: code %s", ErrNotFound, hash)
+	}
+	return r.read(loc)
+}
+
+// Block returns the RLP encoding of the block with [hash].
+func (r *Reader) Block(hash common.Hash) ([]byte, error) {
+	loc, ok := r.blocks[hash]
+	if !ok {
+		return nil, fmt.Errorf("%w: block %s", ErrNotFound, hash)
+	}
+	return r.read(loc)
+}
+
+func (r *Reader) read(loc location) ([]byte, error) {
+	data := make([]byte, loc.length)
+	if n, err := r.chunks[loc.chunk].ReadAt(data, loc.offset); n < len(data) {
+		return nil, fmt.Errorf("failed to read %s at offset %d: %w", r.manifest.Chunks[loc.chunk], loc.offset, err)
+	}
+	return data, nil
+}
+
+// Close closes the chunk files of the snapshot.
+func (r *Reader) Close() error {
+	var errs []error
+	for _, closer := range r.closers {
+		errs = append(errs, closer.Close())
+	}
+	return errors.Join(errs...)
+}
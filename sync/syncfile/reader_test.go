@@ -0,0 +1,101 @@
+// Copyright (C) 2019-2025, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package syncfile
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/MetalBlockchain/libevm/common"
+	"github.com/stretchr/testify/require"
+
+	"github.com/MetalBlockchain/coreth/plugin/evm/message"
+)
+
+// writeTestSnapshot writes a snapshot with leafs records of a single trie
+// starting at [starts], each holding its start key as payload.
+func writeTestSnapshot(t *testing.T, root common.Hash, starts [][]byte, chunkFileSize int64) string {
+	t.Helper()
+	require := require.New(t)
+	dir := t.TempDir()
+	w, err := newWriter(dir, chunkFileSize)
+	require.NoError(err)
+	for _, start := range starts {
+		header := &recordHeader{kind: leafsRecord, nodeType: message.StateTrieNode, hash: root, start: start}
+		require.NoError(w.write(header, append([]byte("leafs"), start...)))
+	}
+	require.NoError(w.write(&recordHeader{kind: codeRecord, hash: common.Hash{2}}, []byte("code")))
+	require.NoError(w.write(&recordHeader{kind: blockRecord, hash: common.Hash{3}}, []byte("block")))
+	chunks, err := w.close()
+	require.NoError(err)
+	require.NoError(writeManifest(dir, &Manifest{
+		Version:     Version,
+		RequestSize: 1,
+		Chunks:      chunks,
+	}))
+	return dir
+}
+
+func TestReader(t *testing.T) {
+	require := require.New(t)
+	root := common.Hash{1}
+	// Write the records out of order, across several chunk files.
+	dir := writeTestSnapshot(t, root, [][]byte{{0x80}, nil, {0x40}}, 1)
+
+	reader, err := Open(os.DirFS(dir))
+	require.NoError(err)
+	defer reader.Close()
+	require.Len(reader.Manifest().Chunks, 5)
+
+	tests := []struct {
+		start         []byte
+		expectedStart []byte
+	}{
+		{start: nil, expectedStart: nil},
+		{start: []byte{0x00}, expectedStart: nil},
+		{start: []byte{0x40}, expectedStart: []byte{0x40}},
+		{start: []byte{0x7f, 0xff}, expectedStart: []byte{0x40}},
+		{start: []byte{0x80, 0x00}, expectedStart: []byte{0x80}},
+		{start: []byte{0xff}, expectedStart: []byte{0x80}},
+	}
+	for _, test := range tests {
+		start, response, err := reader.Leafs(message.StateTrieNode, root, test.start)
+		require.NoError(err)
+		require.Equal(test.expectedStart, start)
+		require.Equal(append([]byte("leafs"), test.expectedStart...), response)
+	}
+
+	_, _, err = reader.Leafs(message.StateTrieNode, common.Hash{2}, nil)
+	require.ErrorIs(err, ErrNotFound)
+
+	code, err := reader.Code(common.Hash{2})
+	require.NoError(err)
+	require.Equal([]byte("code"), code)
+	_, err = reader.Code(common.Hash{3})
+	require.ErrorIs(err, ErrNotFound)
+
+	block, err := reader.Block(common.Hash{3})
+	require.NoError(err)
+	require.Equal([]byte("block"), block)
+}
+
+func TestReaderTruncatedChunk(t *testing.T) {
+	require := require.New(t)
+	dir := writeTestSnapshot(t, common.Hash{1}, [][]byte{nil}, DefaultChunkFileSize)
+
+	chunk := filepath.Join(dir, "chunk-000000.bin")
+	data, err := os.ReadFile(chunk)
+	require.NoError(err)
+	require.NoError(os.WriteFile(chunk, data[:len(data)-1], 0o644))
+
+	_, err = Open(os.DirFS(dir))
+	require.ErrorIs(err, errInvalidRecord)
+}
+
+func TestWriterRefusesExistingSnapshot(t *testing.T) {
+	dir := writeTestSnapshot(t, common.Hash{1}, nil, DefaultChunkFileSize)
+	_, err := newWriter(dir, DefaultChunkFileSize)
+	require.ErrorIs(t, err, errSnapshotExists)
+}
@@ -0,0 +1,94 @@
+// Copyright (C) 2019-2025, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package syncfile
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/MetalBlockchain/libevm/common"
+
+	"github.com/MetalBlockchain/coreth/plugin/evm/message"
+)
+
+// recordKind is the type of the payload of a record.
+type recordKind byte
+
+const (
+	// leafsRecord holds a marshalled [message.LeafsResponse] for the leaves
+	// of the trie with root [recordHeader.hash], starting at
+	// [recordHeader.start].
+	leafsRecord recordKind = iota + 1
+	// codeRecord holds the code with hash [recordHeader.hash].
+	codeRecord
+	// blockRecord holds the RLP encoding of the block with hash
+	// [recordHeader.hash].
+	blockRecord
+)
+
+// maxStartLength is the maximum length of the start key of a leafs record.
+const maxStartLength = 255
+
+var errInvalidRecord = errors.New("invalid record")
+
+// recordHeader precedes the payload of each record in a chunk file, and is
+// encoded as:
+//
+//	kind (1 byte) | node type (1 byte) | hash (32 bytes) |
+//	start length (1 byte) | start | payload length (4 bytes)
+type recordHeader struct {
+	kind     recordKind
+	nodeType message.NodeType
+	hash     common.Hash
+	start    []byte
+	length   uint32
+}
+
+// fixedHeaderLength is the length of a record header with an empty start key.
+const fixedHeaderLength = 1 + 1 + common.HashLength + 1 + 4
+
+func (h *recordHeader) size() int64 {
+	return int64(fixedHeaderLength + len(h.start))
+}
+
+func (h *recordHeader) encode() ([]byte, error) {
+	if len(h.start) > maxStartLength {
+		return nil, fmt.Errorf("%w: start key of length %d", errInvalidRecord, len(h.start))
+	}
+	buf := make([]byte, 0, h.size())
+	buf = append(buf, byte(h.kind), byte(h.nodeType))
+	buf = append(buf, h.hash[:]...)
+	buf = append(buf, byte(len(h.start)))
+	buf = append(buf, h.start...)
+	return binary.BigEndian.AppendUint32(buf, h.length), nil
+}
+
+// readRecordHeader reads the header at [offset] of [r].
+func readRecordHeader(r io.ReaderAt, offset int64) (*recordHeader, error) {
+	fixed := make([]byte, fixedHeaderLength-4)
+	// [io.ReaderAt] may return [io.EOF] along with a full read at the end of
+	// the input, so only the number of bytes read is checked.
+	if n, err := r.ReadAt(fixed, offset); n < len(fixed) {
+		return nil, fmt.Errorf("%w: truncated header at offset %d: %w", errInvalidRecord, offset, err)
+	}
+	h := &recordHeader{
+		kind:     recordKind(fixed[0]),
+		nodeType: message.NodeType(fixed[1]),
+		hash:     common.BytesToHash(fixed[2 : 2+common.HashLength]),
+	}
+	if h.kind < leafsRecord || h.kind > blockRecord {
+		return nil, fmt.Errorf("%w: unknown kind %d at offset %d", errInvalidRecord, h.kind, offset)
+	}
+	rest := make([]byte, int(fixed[len(fixed)-1])+4)
+	if n, err := r.ReadAt(rest, offset+int64(len(fixed))); n < len(rest) {
+		return nil, fmt.Errorf("%w: truncated header at offset %d: %w", errInvalidRecord, offset, err)
+	}
+	if len(rest) > 4 {
+		h.start = rest[:len(rest)-4]
+	}
+	h.length = binary.BigEndian.Uint32(rest[len(rest)-4:])
+	return h, nil
+}
@@ -0,0 +1,100 @@
+// Copyright (C) 2019-2025, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package syncfile
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+var errSnapshotExists = errors.New("directory already contains a snapshot")
+
+// writer appends records to chunk files in a directory, starting a new chunk
+// file once the current one exceeds the configured size.
+type writer struct {
+	dir           string
+	chunkFileSize int64
+
+	file   *os.File
+	buf    *bufio.Writer
+	size   int64
+	chunks []string
+}
+
+func newWriter(dir string, chunkFileSize int64) (*writer, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	if _, err := os.Stat(filepath.Join(dir, ManifestName)); err == nil {
+		return nil, fmt.Errorf("%w: %s", errSnapshotExists, dir)
+	}
+	return &writer{
+		dir:           dir,
+		chunkFileSize: chunkFileSize,
+	}, nil
+}
+
+// write appends a record with [header] and [payload] to the current chunk
+// file.
+func (w *writer) write(header *recordHeader, payload []byte) error {
+	if w.file == nil || w.size >= w.chunkFileSize {
+		if err := w.nextChunk(); err != nil {
+			return err
+		}
+	}
+	header.length = uint32(len(payload))
+	encoded, err := header.encode()
+	if err != nil {
+		return err
+	}
+	if _, err := w.buf.Write(encoded); err != nil {
+		return err
+	}
+	if _, err := w.buf.Write(payload); err != nil {
+		return err
+	}
+	w.size += int64(len(encoded) + len(payload))
+	return nil
+}
+
+// nextChunk closes the current chunk file, if any, and creates the next one.
+func (w *writer) nextChunk() error {
+	if err := w.closeChunk(); err != nil {
+		return err
+	}
+	name := fmt.Sprintf(chunkNameFormat, len(w.chunks))
+	file, err := os.Create(filepath.Join(w.dir, name))
+	if err != nil {
+		return err
+	}
+	w.file = file
+	w.buf = bufio.NewWriterSize(file, 1<<20)
+	w.size = 0
+	w.chunks = append(w.chunks, name)
+	return nil
+}
+
+func (w *writer) closeChunk() error {
+	if w.file == nil {
+		return nil
+	}
+	if err := w.buf.Flush(); err != nil {
+		return err
+	}
+	if err := w.file.Sync(); err != nil {
+		return err
+	}
+	err := w.file.Close()
+	w.file, w.buf = nil, nil
+	return err
+}
+
+// close flushes and closes the current chunk file and returns the names of
+// every chunk file written.
+func (w *writer) close() ([]string, error) {
+	return w.chunks, w.closeChunk()
+}
@@ -0,0 +1,302 @@
+// Copyright (C) 2019-2025, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package syncutils
+
+import (
+	"bytes"
+	"slices"
+
+	"github.com/MetalBlockchain/libevm/common"
+	"github.com/MetalBlockchain/libevm/core/rawdb"
+	"github.com/MetalBlockchain/libevm/ethdb"
+	"github.com/MetalBlockchain/libevm/rlp"
+)
+
+var _ ethdb.Iterator = (*PathLeafIterator)(nil)
+
+const (
+	// terminatorNibble marks a hex key as ending in a value.
+	terminatorNibble = 16
+	// fullNodeChildren is the number of elements in an encoded full node.
+	fullNodeChildren = 17
+)
+
+// pathLeaf is a leaf found while scanning the trie nodes.
+type pathLeaf struct {
+	path []byte // hex path of the leaf, without the terminator
+	key  []byte
+	val  []byte
+}
+
+// PathLeafIterator iterates the leaves of a trie stored with the path scheme
+// by scanning its persisted nodes in key order, rather than by resolving them
+// from the root. Since nodes are keyed by their path, this reads the trie
+// sequentially from disk.
+//
+// The persisted nodes are those of the disk layer, which may lag behind or
+// include nodes of other tries that have not been deleted yet, so the leaves
+// must be verified against the requested trie before they are served.
+// Malformed nodes are skipped.
+type PathLeafIterator struct {
+	db     ethdb.KeyValueReader
+	it     ethdb.Iterator
+	prefix []byte // prefix of the keys of the trie's nodes
+	start  []byte // hex path of the first leaf to return
+
+	// next is the node read from [it] that has not been processed yet.
+	nextPath, nextBlob []byte
+	exhausted          bool
+
+	// pending are the leaves found in processed nodes, sorted by path.
+	pending []pathLeaf
+
+	key, val []byte
+}
+
+// NewPathLeafIterator returns an iterator over the leaves of the account trie
+// (if [owner] is empty) or the storage trie of [owner] persisted in [db], from
+// [start] onwards.
+func NewPathLeafIterator(db ethdb.KeyValueStore, owner common.Hash, start common.Hash) *PathLeafIterator {
+	prefix := slices.Clone(rawdb.TrieNodeAccountPrefix)
+	if owner != (common.Hash{}) {
+		prefix = append(slices.Clone(rawdb.TrieNodeStoragePrefix), owner.Bytes()...)
+	}
+	startPath := keybytesToHex(start.Bytes())
+	startPath = startPath[:len(startPath)-1] // drop the terminator
+	it := &PathLeafIterator{
+		db:     db,
+		it:     db.NewIterator(prefix, startPath),
+		prefix: prefix,
+		start:  startPath,
+	}
+	it.descend()
+	return it
+}
+
+// descend processes the persisted nodes on the path to [it.start], which
+// precede [it.start] in key order and so are not visited by [it.it], but may
+// hold leaves after [it.start].
+func (it *PathLeafIterator) descend() {
+	var path []byte
+	for {
+		blob, err := it.db.Get(append(slices.Clone(it.prefix), path...))
+		if err != nil || len(blob) == 0 {
+			return
+		}
+		child, ok := it.processNode(path, blob, it.start[len(path):])
+		if !ok {
+			return
+		}
+		path = child
+	}
+}
+
+// processNode adds the leaves of the node at [path] encoded as [blob], and
+// of any node embedded in it, to [it.pending]. If [towards] is non-empty,
+// processNode also returns the path of the persisted child of the node on
+// the path to [towards], if any.
+func (it *PathLeafIterator) processNode(path, blob, towards []byte) ([]byte, bool) {
+	elems, _, err := rlp.SplitList(blob)
+	if err != nil {
+		return nil, false
+	}
+	return it.processElems(path, elems, towards)
+}
+
+func (it *PathLeafIterator) processElems(path, elems, towards []byte) ([]byte, bool) {
+	count, err := rlp.CountValues(elems)
+	if err != nil {
+		return nil, false
+	}
+	switch count {
+	case 2:
+		compactKey, rest, err := rlp.SplitString(elems)
+		if err != nil {
+			return nil, false
+		}
+		hexKey := compactToHex(compactKey)
+		childPath := append(slices.Clone(path), hexKey...)
+		if len(hexKey) > 0 && hexKey[len(hexKey)-1] == terminatorNibble {
+			val, _, err := rlp.SplitString(rest)
+			if err != nil {
+				return nil, false
+			}
+			it.addLeaf(childPath[:len(childPath)-1], val)
+			return nil, false
+		}
+		child, ok := it.processChild(childPath, rest)
+		if ok && len(towards) >= len(hexKey) && bytes.HasPrefix(towards, hexKey) {
+			return child, true
+		}
+		return nil, false
+	case fullNodeChildren:
+		var (
+			next   []byte
+			isNext bool
+		)
+		for i := byte(0); i < fullNodeChildren-1; i++ {
+			kind, content, rest, err := rlp.Split(elems)
+			if err != nil {
+				return nil, false
+			}
+			var child []byte
+			childPath := append(slices.Clone(path), i)
+			if kind == rlp.List {
+				it.processElems(childPath, content, nil)
+			} else if len(content) == common.HashLength {
+				child = childPath
+			}
+			if child != nil && len(towards) > 0 && towards[0] == i {
+				next, isNext = child, true
+			}
+			elems = rest
+		}
+		return next, isNext
+	default:
+		return nil, false
+	}
+}
+
+// processChild processes the child of a short node at [path], encoded as
+// the first element of [elems]. Returns [path] if the child is persisted
+// separately, rather than embedded.
+func (it *PathLeafIterator) processChild(path, elems []byte) ([]byte, bool) {
+	kind, content, _, err := rlp.Split(elems)
+	if err != nil {
+		return nil, false
+	}
+	if kind == rlp.List {
+		it.processElems(path, content, nil)
+		return nil, false
+	}
+	return path, len(content) == common.HashLength
+}
+
+// addLeaf adds the leaf at [path] to [it.pending], unless it precedes
+// [it.start].
+func (it *PathLeafIterator) addLeaf(path, val []byte) {
+	if len(path)%2 != 0 || bytes.Compare(path, it.start) < 0 {
+		return
+	}
+	i, found := slices.BinarySearchFunc(it.pending, path, func(l pathLeaf, path []byte) int {
+		return bytes.Compare(l.path, path)
+	})
+	if found {
+		return
+	}
+	it.pending = slices.Insert(it.pending, i, pathLeaf{
+		path: path,
+		key:  hexToKeybytes(path),
+		val:  common.CopyBytes(val),
+	})
+}
+
+// Next moves the iterator to the next leaf, returning false once there are
+// no more leaves.
+func (it *PathLeafIterator) Next() bool {
+	for {
+		if it.nextPath == nil && !it.exhausted {
+			it.readNext()
+		}
+		// A leaf precedes every node after it in key order, except those
+		// on its path, which have already been processed.
+		if len(it.pending) > 0 && (it.nextPath == nil || bytes.Compare(it.pending[0].path, it.nextPath) < 0) {
+			it.key, it.val = it.pending[0].key, it.pending[0].val
+			it.pending = it.pending[1:]
+			return true
+		}
+		if it.nextPath == nil {
+			it.key, it.val = nil, nil
+			return false
+		}
+		it.processNode(it.nextPath, it.nextBlob, nil)
+		it.nextPath, it.nextBlob = nil, nil
+	}
+}
+
+// readNext reads the next node of the trie from [it.it] into [it.nextPath]
+// and [it.nextBlob].
+func (it *PathLeafIterator) readNext() {
+	for it.it.Next() {
+		key := it.it.Key()
+		if !bytes.HasPrefix(key, it.prefix) {
+			break
+		}
+		path := key[len(it.prefix):]
+		if !isHexPath(path) {
+			// The key belongs to a different storage trie, or is not a
+			// trie node.
+			continue
+		}
+		it.nextPath = common.CopyBytes(path)
+		it.nextBlob = common.CopyBytes(it.it.Value())
+		return
+	}
+	it.exhausted = true
+}
+
+// Error returns any error encountered while reading the persisted nodes.
+func (it *PathLeafIterator) Error() error {
+	return it.it.Error()
+}
+
+// Key returns the key of the current leaf.
+func (it *PathLeafIterator) Key() []byte {
+	return it.key
+}
+
+// Value returns the value of the current leaf.
+func (it *PathLeafIterator) Value() []byte {
+	return it.val
+}
+
+// Release releases the underlying database iterator.
+func (it *PathLeafIterator) Release() {
+	it.it.Release()
+}
+
+func isHexPath(path []byte) bool {
+	for _, nibble := range path {
+		if nibble >= terminatorNibble {
+			return false
+		}
+	}
+	return true
+}
+
+// keybytesToHex, hexToKeybytes and compactToHex mirror the unexported key
+// encodings of the trie package.
+
+func keybytesToHex(str []byte) []byte {
+	l := len(str)*2 + 1
+	nibbles := make([]byte, l)
+	for i, b := range str {
+		nibbles[i*2] = b / 16
+		nibbles[i*2+1] = b % 16
+	}
+	nibbles[l-1] = terminatorNibble
+	return nibbles
+}
+
+func hexToKeybytes(hex []byte) []byte {
+	key := make([]byte, len(hex)/2)
+	for bi, ni := 0, 0; ni < len(hex)-1; bi, ni = bi+1, ni+2 {
+		key[bi] = hex[ni]<<4 | hex[ni+1]
+	}
+	return key
+}
+
+func compactToHex(compact []byte) []byte {
+	if len(compact) == 0 {
+		return compact
+	}
+	base := keybytesToHex(compact)
+	// delete terminator flag
+	if base[0] < 2 {
+		base = base[:len(base)-1]
+	}
+	// apply odd flag
+	chop := 2 - base[0]&1
+	return base[chop:]
+}
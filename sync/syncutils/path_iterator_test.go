@@ -0,0 +1,119 @@
+// Copyright (C) 2019-2025, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package syncutils
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/MetalBlockchain/libevm/common"
+	"github.com/MetalBlockchain/libevm/core/rawdb"
+	"github.com/MetalBlockchain/libevm/core/types"
+	"github.com/MetalBlockchain/libevm/ethdb"
+	"github.com/MetalBlockchain/libevm/trie"
+	"github.com/MetalBlockchain/libevm/trie/trienode"
+	"github.com/MetalBlockchain/libevm/triedb"
+	"github.com/stretchr/testify/require"
+
+	"github.com/MetalBlockchain/coreth/triedb/pathdb"
+)
+
+// updatePathTrie deletes [numDeletes] of [keys] from the trie with [id] and
+// applies [numUpdates] random updates, with values small enough that some
+// leaves are embedded in their parents. Returns the nodes of the updated
+// trie, its root and its keys.
+func updatePathTrie(t *testing.T, r *rand.Rand, db *triedb.Database, id *trie.ID, keys [][]byte, numUpdates, numDeletes int) (*trienode.NodeSet, common.Hash, [][]byte) {
+	t.Helper()
+	tr, err := trie.New(id, db)
+	require.NoError(t, err)
+	for i := 0; i < numDeletes && len(keys) > 0; i++ {
+		j := r.Intn(len(keys))
+		require.NoError(t, tr.Delete(keys[j]))
+		keys = append(keys[:j], keys[j+1:]...)
+	}
+	for i := 0; i < numUpdates; i++ {
+		key := make([]byte, common.HashLength)
+		if len(keys) > 0 && r.Intn(4) == 0 {
+			copy(key, keys[r.Intn(len(keys))])
+		} else {
+			_, _ = r.Read(key)
+			keys = append(keys, key)
+		}
+		val := make([]byte, 1+r.Intn(40))
+		_, _ = r.Read(val)
+		require.NoError(t, tr.Update(key, val))
+	}
+	root, nodes, err := tr.Commit(false)
+	require.NoError(t, err)
+	return nodes, root, keys
+}
+
+// assertPathLeaves checks that iterating the persisted nodes of [tr] from
+// [start] returns the same leaves as iterating [tr].
+func assertPathLeaves(t *testing.T, diskdb ethdb.KeyValueStore, tr *trie.Trie, owner common.Hash, start common.Hash) {
+	t.Helper()
+	require := require.New(t)
+	nodeIt, err := tr.NodeIterator(start.Bytes())
+	require.NoError(err)
+	expected := trie.NewIterator(nodeIt)
+
+	it := NewPathLeafIterator(diskdb, owner, start)
+	defer it.Release()
+	for expected.Next() {
+		require.True(it.Next(), "missing leaf %x", expected.Key)
+		require.Equal(expected.Key, it.Key())
+		require.Equal(expected.Value, it.Value())
+	}
+	require.NoError(expected.Err)
+	require.False(it.Next(), "unexpected leaf %x", it.Key())
+	require.NoError(it.Error())
+}
+
+func TestPathLeafIterator(t *testing.T) {
+	var (
+		r      = rand.New(rand.NewSource(1))
+		diskdb = rawdb.NewMemoryDatabase()
+		db     = triedb.NewDatabase(diskdb, &triedb.Config{DBOverride: pathdb.Config{}.BackendConstructor})
+		owner  = common.Hash{1}
+
+		stateRoot, storageRoot = types.EmptyRootHash, types.EmptyRootHash
+		accountKeys, slotKeys  [][]byte
+	)
+	// Commit several versions of the tries, so the iterator must skip the
+	// nodes deleted by each version.
+	for _, numUpdates := range []int{1, 2000, 200} {
+		var (
+			accountNodes, storageNodes   *trienode.NodeSet
+			newStateRoot, newStorageRoot common.Hash
+		)
+		accountNodes, newStateRoot, accountKeys = updatePathTrie(t, r, db, trie.StateTrieID(stateRoot), accountKeys, numUpdates, numUpdates/4)
+		storageNodes, newStorageRoot, slotKeys = updatePathTrie(t, r, db, trie.StorageTrieID(stateRoot, owner, storageRoot), slotKeys, numUpdates, numUpdates/4)
+
+		nodes := trienode.NewMergedNodeSet()
+		require.NoError(t, nodes.Merge(accountNodes))
+		require.NoError(t, nodes.Merge(storageNodes))
+		require.NoError(t, db.Update(newStateRoot, stateRoot, 0, nodes, nil))
+		require.NoError(t, db.Commit(newStateRoot, false))
+		stateRoot, storageRoot = newStateRoot, newStorageRoot
+
+		accountTrie, err := trie.New(trie.StateTrieID(stateRoot), db)
+		require.NoError(t, err)
+		storageTrie, err := trie.New(trie.StorageTrieID(stateRoot, owner, storageRoot), db)
+		require.NoError(t, err)
+
+		starts := []common.Hash{{}, {0xff}}
+		for i := 0; i < 10; i++ {
+			// Start at an existing key, and at a key between existing keys.
+			starts = append(starts, common.BytesToHash(accountKeys[r.Intn(len(accountKeys))]))
+			starts = append(starts, common.BytesToHash(slotKeys[r.Intn(len(slotKeys))]))
+			var start common.Hash
+			_, _ = r.Read(start[:])
+			starts = append(starts, start)
+		}
+		for _, start := range starts {
+			assertPathLeaves(t, diskdb, accountTrie, common.Hash{}, start)
+			assertPathLeaves(t, diskdb, storageTrie, owner, start)
+		}
+	}
+}
@@ -357,6 +357,34 @@ var Forks = map[string]*params.ChainConfig{
 			},
 		},
 	),
+	"Shanghai": params.WithExtra(
+		&params.ChainConfig{
+			ChainID:             big.NewInt(1),
+			HomesteadBlock:      big.NewInt(0),
+			EIP150Block:         big.NewInt(0),
+			EIP155Block:         big.NewInt(0),
+			EIP158Block:         big.NewInt(0),
+			ByzantiumBlock:      big.NewInt(0),
+			ConstantinopleBlock: big.NewInt(0),
+			PetersburgBlock:     big.NewInt(0),
+			IstanbulBlock:       big.NewInt(0),
+			BerlinBlock:         big.NewInt(0),
+			LondonBlock:         big.NewInt(0),
+			ShanghaiTime:        utils.NewUint64(0),
+		},
+		&extras.ChainConfig{
+			NetworkUpgrades: extras.NetworkUpgrades{
+				ApricotPhase1BlockTimestamp: utils.NewUint64(0),
+				ApricotPhase2BlockTimestamp: utils.NewUint64(0),
+				ApricotPhase3BlockTimestamp: utils.NewUint64(0),
+				ApricotPhase4BlockTimestamp: utils.NewUint64(0),
+				ApricotPhase5BlockTimestamp: utils.NewUint64(0),
+				BanffBlockTimestamp:         utils.NewUint64(0),
+				CortinaBlockTimestamp:       utils.NewUint64(0),
+				DurangoBlockTimestamp:       utils.NewUint64(0),
+			},
+		},
+	),
 	"Cancun": params.WithExtra(
 		&params.ChainConfig{
 			ChainID:             big.NewInt(1),
@@ -386,6 +414,132 @@ var Forks = map[string]*params.ChainConfig{
 			},
 		},
 	),
+	"Etna": params.WithExtra(
+		&params.ChainConfig{
+			ChainID:             big.NewInt(1),
+			HomesteadBlock:      big.NewInt(0),
+			EIP150Block:         big.NewInt(0),
+			EIP155Block:         big.NewInt(0),
+			EIP158Block:         big.NewInt(0),
+			ByzantiumBlock:      big.NewInt(0),
+			ConstantinopleBlock: big.NewInt(0),
+			PetersburgBlock:     big.NewInt(0),
+			IstanbulBlock:       big.NewInt(0),
+			BerlinBlock:         big.NewInt(0),
+			LondonBlock:         big.NewInt(0),
+			ShanghaiTime:        utils.NewUint64(0),
+			CancunTime:          utils.NewUint64(0),
+		},
+		&extras.ChainConfig{
+			NetworkUpgrades: extras.NetworkUpgrades{
+				ApricotPhase1BlockTimestamp: utils.NewUint64(0),
+				ApricotPhase2BlockTimestamp: utils.NewUint64(0),
+				ApricotPhase3BlockTimestamp: utils.NewUint64(0),
+				ApricotPhase4BlockTimestamp: utils.NewUint64(0),
+				ApricotPhase5BlockTimestamp: utils.NewUint64(0),
+				BanffBlockTimestamp:         utils.NewUint64(0),
+				CortinaBlockTimestamp:       utils.NewUint64(0),
+				DurangoBlockTimestamp:       utils.NewUint64(0),
+				EtnaTimestamp:               utils.NewUint64(0),
+			},
+		},
+	),
+	"Fortuna": params.WithExtra(
+		&params.ChainConfig{
+			ChainID:             big.NewInt(1),
+			HomesteadBlock:      big.NewInt(0),
+			EIP150Block:         big.NewInt(0),
+			EIP155Block:         big.NewInt(0),
+			EIP158Block:         big.NewInt(0),
+			ByzantiumBlock:      big.NewInt(0),
+			ConstantinopleBlock: big.NewInt(0),
+			PetersburgBlock:     big.NewInt(0),
+			IstanbulBlock:       big.NewInt(0),
+			BerlinBlock:         big.NewInt(0),
+			LondonBlock:         big.NewInt(0),
+			ShanghaiTime:        utils.NewUint64(0),
+			CancunTime:          utils.NewUint64(0),
+		},
+		&extras.ChainConfig{
+			NetworkUpgrades: extras.NetworkUpgrades{
+				ApricotPhase1BlockTimestamp: utils.NewUint64(0),
+				ApricotPhase2BlockTimestamp: utils.NewUint64(0),
+				ApricotPhase3BlockTimestamp: utils.NewUint64(0),
+				ApricotPhase4BlockTimestamp: utils.NewUint64(0),
+				ApricotPhase5BlockTimestamp: utils.NewUint64(0),
+				BanffBlockTimestamp:         utils.NewUint64(0),
+				CortinaBlockTimestamp:       utils.NewUint64(0),
+				DurangoBlockTimestamp:       utils.NewUint64(0),
+				EtnaTimestamp:               utils.NewUint64(0),
+				FortunaTimestamp:            utils.NewUint64(0),
+			},
+		},
+	),
+	"Granite": params.WithExtra(
+		&params.ChainConfig{
+			ChainID:             big.NewInt(1),
+			HomesteadBlock:      big.NewInt(0),
+			EIP150Block:         big.NewInt(0),
+			EIP155Block:         big.NewInt(0),
+			EIP158Block:         big.NewInt(0),
+			ByzantiumBlock:      big.NewInt(0),
+			ConstantinopleBlock: big.NewInt(0),
+			PetersburgBlock:     big.NewInt(0),
+			IstanbulBlock:       big.NewInt(0),
+			BerlinBlock:         big.NewInt(0),
+			LondonBlock:         big.NewInt(0),
+			ShanghaiTime:        utils.NewUint64(0),
+			CancunTime:          utils.NewUint64(0),
+		},
+		&extras.ChainConfig{
+			NetworkUpgrades: extras.NetworkUpgrades{
+				ApricotPhase1BlockTimestamp: utils.NewUint64(0),
+				ApricotPhase2BlockTimestamp: utils.NewUint64(0),
+				ApricotPhase3BlockTimestamp: utils.NewUint64(0),
+				ApricotPhase4BlockTimestamp: utils.NewUint64(0),
+				ApricotPhase5BlockTimestamp: utils.NewUint64(0),
+				BanffBlockTimestamp:         utils.NewUint64(0),
+				CortinaBlockTimestamp:       utils.NewUint64(0),
+				DurangoBlockTimestamp:       utils.NewUint64(0),
+				EtnaTimestamp:               utils.NewUint64(0),
+				FortunaTimestamp:            utils.NewUint64(0),
+				GraniteTimestamp:            utils.NewUint64(0),
+			},
+		},
+	),
+}
+
+// EthereumForks maps the Ethereum forks of the official state tests to the
+// entry in [Forks] of the Avalanche upgrade which activates them. Other forks
+// share their name with their entry.
+var EthereumForks = map[string]string{
+	"Berlin": "ApricotPhase2",
+	"London": "ApricotPhase3",
+	// coreth does not distinguish the Merge from the forks around it.
+	"Merge":  "Cortina",
+	"Paris":  "Cortina",
+	"Cancun": "Etna",
+	// Shanghai has its own entry in [Forks], which also activates Durango.
+	// Prague is not activated by any Avalanche upgrade, so its state tests
+	// are skipped as unsupported.
+}
+
+// GetChainConfig returns the chain config to run the state tests of the
+// Ethereum fork [forkString] with.
+//
+// Forks preceding ApricotPhase1 are unsupported: no Avalanche chain runs them
+// without ApricotPhase0, whose SSTORE gas accounting diverges from Ethereum in
+// a way the state tests can't tell apart from other divergences.
+func GetChainConfig(forkString string) (*params.ChainConfig, error) {
+	name := forkString
+	if avalancheFork, ok := EthereumForks[forkString]; ok {
+		name = avalancheFork
+	}
+	config, ok := Forks[name]
+	if !ok || !params.GetExtra(config).IsApricotPhase1(0) {
+		return nil, UnsupportedForkError{forkString}
+	}
+	return config, nil
 }
 
 // AvailableForks returns the set of defined fork names
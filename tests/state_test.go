@@ -0,0 +1,112 @@
+// Copyright (C) 2019-2025, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+//
+// This file is a derived work, based on the go-ethereum library whose original
+// notices appear below.
+//
+// It is distributed under a license compatible with the licensing terms of the
+// original code from which it is derived.
+//
+// Much love to the original authors for their work.
+// **********
+// Copyright 2015 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package tests
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/MetalBlockchain/libevm/core/rawdb"
+	"github.com/MetalBlockchain/libevm/core/vm"
+)
+
+var (
+	// stateFixtureDir holds state tests vendored in the repository, which are
+	// always run.
+	stateFixtureDir = filepath.Join("fixtures", "state")
+	// stateTestDir is where the official Ethereum state tests are expected. It
+	// is populated by checking out https://github.com/ethereum/tests into
+	// testdata.
+	stateTestDir = filepath.Join("testdata", "GeneralStateTests")
+)
+
+// TestState runs the vendored and the official Ethereum state tests and reports
+// every divergence of coreth, which must be explained by [AvalancheExceptions].
+func TestState(t *testing.T) {
+	t.Run("fixtures", func(t *testing.T) {
+		runStateTests(t, stateFixtureDir)
+	})
+	t.Run("ethereum", func(t *testing.T) {
+		if _, err := os.Stat(stateTestDir); err != nil {
+			t.Skipf("state tests not found in %s", stateTestDir)
+		}
+		runStateTests(t, stateTestDir)
+	})
+}
+
+// runStateTests runs every state test found in [dir].
+func runStateTests(t *testing.T, dir string) {
+	descriptions := make(map[string]string, len(AvalancheExceptions))
+	for _, exception := range AvalancheExceptions {
+		descriptions[exception.Name] = exception.Description
+	}
+
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() || !strings.HasSuffix(path, ".json") {
+			return err
+		}
+		name := strings.TrimSuffix(filepath.ToSlash(strings.TrimPrefix(path, dir+string(filepath.Separator))), ".json")
+		t.Run(name, func(t *testing.T) {
+			data, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var tests map[string]StateTest
+			if err := json.Unmarshal(data, &tests); err != nil {
+				t.Fatalf("failed to parse %s: %v", path, err)
+			}
+			for key, test := range tests {
+				for _, subtest := range test.Subtests() {
+					t.Run(key+"/"+subtest.Fork+"/"+strconv.Itoa(subtest.Index), func(t *testing.T) {
+						exceptions, err := test.Run(subtest, vm.Config{}, false, rawdb.HashScheme)
+						var unsupported UnsupportedForkError
+						if errors.As(err, &unsupported) {
+							t.Skip(err)
+						}
+						if err != nil {
+							t.Error(err)
+							return
+						}
+						for _, name := range exceptions {
+							t.Logf("expected divergence %s: %s", name, descriptions[name])
+						}
+					})
+				}
+			}
+		})
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
@@ -28,24 +28,475 @@
 package tests
 
 import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
 
+	"github.com/MetalBlockchain/coreth/consensus"
+	"github.com/MetalBlockchain/coreth/core"
 	"github.com/MetalBlockchain/coreth/core/extstate"
 	"github.com/MetalBlockchain/coreth/core/state/snapshot"
+	"github.com/MetalBlockchain/coreth/params"
 	"github.com/MetalBlockchain/coreth/plugin/evm/customrawdb"
+	"github.com/MetalBlockchain/coreth/plugin/evm/upgrade/acp176"
 	"github.com/MetalBlockchain/coreth/triedb/firewood"
 	"github.com/MetalBlockchain/coreth/triedb/hashdb"
 	"github.com/MetalBlockchain/coreth/triedb/pathdb"
 	"github.com/MetalBlockchain/libevm/common"
+	"github.com/MetalBlockchain/libevm/common/hexutil"
+	"github.com/MetalBlockchain/libevm/common/math"
+	"github.com/MetalBlockchain/libevm/consensus/misc/eip4844"
 	"github.com/MetalBlockchain/libevm/core/rawdb"
 	"github.com/MetalBlockchain/libevm/core/state"
 	"github.com/MetalBlockchain/libevm/core/types"
+	"github.com/MetalBlockchain/libevm/core/vm"
+	"github.com/MetalBlockchain/libevm/crypto"
 	"github.com/MetalBlockchain/libevm/ethdb"
+	ethparams "github.com/MetalBlockchain/libevm/params"
 	"github.com/MetalBlockchain/libevm/triedb"
 	"github.com/holiman/uint256"
 )
 
+// StateTest checks transaction processing without block context.
+// See https://github.com/ethereum/EIPs/issues/176 for the test format specification.
+type StateTest struct {
+	json stJSON
+}
+
+// StateSubtest selects a specific configuration of a General State Test.
+type StateSubtest struct {
+	Fork  string
+	Index int
+}
+
+func (t *StateTest) UnmarshalJSON(in []byte) error {
+	return json.Unmarshal(in, &t.json)
+}
+
+type stJSON struct {
+	Env  stEnv                    `json:"env"`
+	Pre  types.GenesisAlloc       `json:"pre"`
+	Tx   stTransaction            `json:"transaction"`
+	Out  hexutil.Bytes            `json:"out"`
+	Post map[string][]stPostState `json:"post"`
+}
+
+type stPostState struct {
+	Root            common.UnprefixedHash `json:"hash"`
+	Logs            common.UnprefixedHash `json:"logs"`
+	TxBytes         hexutil.Bytes         `json:"txbytes"`
+	ExpectException string                `json:"expectException"`
+	Indexes         struct {
+		Data  int `json:"data"`
+		Gas   int `json:"gas"`
+		Value int `json:"value"`
+	}
+}
+
+type stEnv struct {
+	Coinbase      common.Address        `json:"currentCoinbase"`
+	Difficulty    *math.HexOrDecimal256 `json:"currentDifficulty"`
+	Random        *math.HexOrDecimal256 `json:"currentRandom"`
+	GasLimit      math.HexOrDecimal64   `json:"currentGasLimit"`
+	Number        math.HexOrDecimal64   `json:"currentNumber"`
+	Timestamp     math.HexOrDecimal64   `json:"currentTimestamp"`
+	BaseFee       *math.HexOrDecimal256 `json:"currentBaseFee"`
+	ExcessBlobGas *math.HexOrDecimal64  `json:"currentExcessBlobGas"`
+}
+
+type stTransaction struct {
+	GasPrice             *math.HexOrDecimal256 `json:"gasPrice"`
+	MaxFeePerGas         *math.HexOrDecimal256 `json:"maxFeePerGas"`
+	MaxPriorityFeePerGas *math.HexOrDecimal256 `json:"maxPriorityFeePerGas"`
+	Nonce                math.HexOrDecimal64   `json:"nonce"`
+	To                   string                `json:"to"`
+	Data                 []string              `json:"data"`
+	AccessLists          []*types.AccessList   `json:"accessLists,omitempty"`
+	GasLimit             []math.HexOrDecimal64 `json:"gasLimit"`
+	Value                []string              `json:"value"`
+	PrivateKey           hexutil.Bytes         `json:"secretKey"`
+	Sender               *common.Address       `json:"sender"`
+	BlobVersionedHashes  []common.Hash         `json:"blobVersionedHashes,omitempty"`
+	BlobGasFeeCap        *math.HexOrDecimal256 `json:"maxFeePerBlobGas,omitempty"`
+}
+
+// AvalancheException is a rule in which coreth intentionally diverges from the
+// Ethereum fork activated by an Avalanche upgrade.
+type AvalancheException struct {
+	Name        string
+	Description string
+	// applies reports whether the exception is part of the rules.
+	applies func(params.Rules) bool
+	// undo reverts the effect of the exception on the post state of an
+	// execution, and reports whether there was any.
+	undo func(*execution) bool
+}
+
+// execution is the result of applying a message of a state test.
+type execution struct {
+	rules    params.Rules
+	msg      *core.Message
+	coinbase common.Address
+	baseFee  *big.Int
+	statedb  *state.StateDB
+	// gasUsed is the gas used by the message under the Ethereum rules.
+	gasUsed uint64
+}
+
+// AvalancheExceptions is the explicit list of expected divergences of coreth
+// from the official state tests of the Ethereum forks it activates.
+var AvalancheExceptions = []AvalancheException{
+	{
+		Name:        "no-gas-refunds",
+		Description: "ApricotPhase1 removed gas refunds",
+		applies: func(rules params.Rules) bool {
+			return params.GetRulesExtra(rules).IsApricotPhase1
+		},
+		undo: undoNoGasRefunds,
+	},
+	{
+		Name:        "base-fee-to-coinbase",
+		Description: "The base fee is paid to the coinbase instead of being burned",
+		applies: func(rules params.Rules) bool {
+			return rules.IsLondon
+		},
+		undo: undoBaseFeeToCoinbase,
+	},
+}
+
+func undoNoGasRefunds(e *execution) bool {
+	quotient := ethparams.RefundQuotient
+	if e.rules.IsLondon {
+		quotient = ethparams.RefundQuotientEIP3529
+	}
+	refund := min(e.gasUsed/quotient, e.statedb.GetRefund())
+	if refund == 0 {
+		return false
+	}
+	e.gasUsed -= refund
+
+	value := new(uint256.Int).Mul(uint256.NewInt(refund), uint256.MustFromBig(e.msg.GasPrice))
+	e.statedb.AddBalance(e.msg.From, value)
+	e.statedb.SubBalance(e.coinbase, value)
+	return true
+}
+
+func undoBaseFeeToCoinbase(e *execution) bool {
+	if e.baseFee == nil || e.baseFee.Sign() == 0 || e.gasUsed == 0 {
+		return false
+	}
+	burnt := new(uint256.Int).Mul(uint256.NewInt(e.gasUsed), uint256.MustFromBig(e.baseFee))
+	e.statedb.SubBalance(e.coinbase, burnt)
+	return true
+}
+
+// Subtests returns all valid subtests of the test.
+func (t *StateTest) Subtests() []StateSubtest {
+	var sub []StateSubtest
+	for fork, pss := range t.json.Post {
+		for i := range pss {
+			sub = append(sub, StateSubtest{fork, i})
+		}
+	}
+	sort.Slice(sub, func(i, j int) bool {
+		if sub[i].Fork != sub[j].Fork {
+			return sub[i].Fork < sub[j].Fork
+		}
+		return sub[i].Index < sub[j].Index
+	})
+	return sub
+}
+
+// checkError checks if the error returned by the state transition matches any expected error.
+// A failing expectation returns a wrapped version of the original error, if any,
+// or a new error detailing the failing expectation.
+// This function does not return or modify the original error, it only evaluates and returns expectations for the error.
+func (t *StateTest) checkError(subtest StateSubtest, err error) error {
+	expectedError := t.json.Post[subtest.Fork][subtest.Index].ExpectException
+	if err == nil && expectedError == "" {
+		return nil
+	}
+	if err == nil && expectedError != "" {
+		return fmt.Errorf("expected error %q, got no error", expectedError)
+	}
+	if err != nil && expectedError == "" {
+		return fmt.Errorf("unexpected error: %w", err)
+	}
+	return nil
+}
+
+// Run executes a specific subtest and verifies the post-state and logs. The
+// post-state may only diverge from the expected one due to
+// [AvalancheExceptions], whose names are returned.
+func (t *StateTest) Run(subtest StateSubtest, vmconfig vm.Config, snapshotter bool, scheme string) ([]string, error) {
+	st, result, err := t.RunNoVerify(subtest, vmconfig, snapshotter, scheme)
+	defer st.Close()
+
+	if checkedErr := t.checkError(subtest, err); checkedErr != nil {
+		return nil, checkedErr
+	}
+	// The error has been checked; if it was unexpected, it's already returned.
+	if err != nil {
+		// Here, an error exists but it was expected.
+		// We do not check the post state or logs.
+		return nil, nil
+	}
+	post := t.json.Post[subtest.Fork][subtest.Index]
+	if result.Logs != common.Hash(post.Logs) {
+		return nil, fmt.Errorf("post state logs hash mismatch: got %x, want %x", result.Logs, post.Logs)
+	}
+	switch want := common.Hash(post.Root); {
+	case result.Root == want:
+		return nil, nil
+	case result.EthereumRoot == want && len(result.Exceptions) != 0:
+		return result.Exceptions, nil
+	default:
+		return nil, fmt.Errorf("post state root mismatch: got %x, want %x (%x after undoing Avalanche exceptions %v)", result.Root, want, result.EthereumRoot, result.Exceptions)
+	}
+}
+
+// StateTestResult is the outcome of executing a subtest.
+type StateTestResult struct {
+	// Root is the post state root under the coreth rules.
+	Root common.Hash
+	// Logs is the hash of the logs emitted by the execution.
+	Logs common.Hash
+	// EthereumRoot is the post state root after undoing the effect of
+	// [Exceptions].
+	EthereumRoot common.Hash
+	// Exceptions are the names of the [AvalancheExceptions] which affected
+	// the execution.
+	Exceptions []string
+}
+
+// RunNoVerify runs a specific subtest and returns the statedb and the result
+// of the execution.
+func (t *StateTest) RunNoVerify(subtest StateSubtest, vmconfig vm.Config, snapshotter bool, scheme string) (StateTestState, StateTestResult, error) {
+	config, err := GetChainConfig(subtest.Fork)
+	if err != nil {
+		return StateTestState{}, StateTestResult{}, err
+	}
+	st := MakePreState(rawdb.NewMemoryDatabase(), t.json.Pre, snapshotter, scheme)
+
+	var baseFee *big.Int
+	if config.IsLondon(new(big.Int)) {
+		baseFee = (*big.Int)(t.json.Env.BaseFee)
+		if baseFee == nil {
+			// Retesteth uses `0x10` for genesis baseFee. Therefore, it defaults to
+			// parent - 2 : 0xa as the basefee for 'this' context.
+			baseFee = big.NewInt(0x0a)
+		}
+	}
+	post := t.json.Post[subtest.Fork][subtest.Index]
+	msg, err := t.json.Tx.toMessage(post, baseFee)
+	if err != nil {
+		return st, StateTestResult{}, err
+	}
+
+	// Try to recover tx with current signer
+	if len(post.TxBytes) != 0 {
+		var ttx types.Transaction
+		if err := ttx.UnmarshalBinary(post.TxBytes); err != nil {
+			return st, StateTestResult{}, err
+		}
+		if _, err := types.Sender(types.LatestSigner(config), &ttx); err != nil {
+			return st, StateTestResult{}, err
+		}
+	}
+
+	// Prepare the EVM.
+	header := t.header(baseFee)
+	context, err := core.NewEVMBlockContext(header, stChain{config}, &t.json.Env.Coinbase)
+	if err != nil {
+		return st, StateTestResult{}, err
+	}
+	context.GetHash = vmTestBlockHash
+	if config.IsLondon(new(big.Int)) && t.json.Env.Random != nil {
+		rnd := common.BigToHash((*big.Int)(t.json.Env.Random))
+		context.Random = &rnd
+		context.Difficulty = big.NewInt(0)
+	}
+	if config.IsCancun(header.Number, header.Time) && t.json.Env.ExcessBlobGas != nil {
+		context.BlobBaseFee = eip4844.CalcBlobFee(uint64(*t.json.Env.ExcessBlobGas))
+	}
+	evm := vm.NewEVM(context, core.NewEVMTxContext(msg), st.StateDB, config, vmconfig)
+	rules := config.Rules(header.Number, params.IsMergeTODO, header.Time)
+
+	// Execute the message.
+	snapshot := st.StateDB.Snapshot()
+	gaspool := new(core.GasPool)
+	gaspool.AddGas(header.GasLimit)
+	applied, err := core.ApplyMessage(evm, msg, gaspool)
+	if err != nil {
+		st.StateDB.RevertToSnapshot(snapshot)
+	}
+
+	var result StateTestResult
+	ethereum := &execution{
+		rules:    rules,
+		msg:      msg,
+		coinbase: header.Coinbase,
+		baseFee:  baseFee,
+		statedb:  st.StateDB.Copy(),
+	}
+	if applied != nil {
+		ethereum.gasUsed = applied.UsedGas
+	}
+	for _, exception := range AvalancheExceptions {
+		if err == nil && exception.applies(rules) && exception.undo(ethereum) {
+			result.Exceptions = append(result.Exceptions, exception.Name)
+		}
+	}
+
+	// Add 0-value mining reward. This only makes a difference in the cases
+	// where
+	// - the coinbase self-destructed, or
+	// - there are only 'bad' transactions, which aren't executed. In those cases,
+	//   the coinbase gets no txfee, so isn't created, and thus needs to be touched
+	st.StateDB.AddBalance(header.Coinbase, new(uint256.Int))
+	ethereum.statedb.AddBalance(header.Coinbase, new(uint256.Int))
+
+	deleteEmptyObjects := config.IsEIP158(header.Number)
+	result.Logs = types.RLPHash(st.StateDB.Logs())
+	result.Root = st.StateDB.IntermediateRoot(deleteEmptyObjects)
+	result.EthereumRoot = ethereum.statedb.IntermediateRoot(deleteEmptyObjects)
+	return st, result, err
+}
+
+func (t *StateTest) header(baseFee *big.Int) *types.Header {
+	difficulty := new(big.Int)
+	if t.json.Env.Difficulty != nil {
+		difficulty.Set((*big.Int)(t.json.Env.Difficulty))
+	}
+	return &types.Header{
+		Coinbase:   t.json.Env.Coinbase,
+		Difficulty: difficulty,
+		GasLimit:   uint64(t.json.Env.GasLimit),
+		Number:     new(big.Int).SetUint64(uint64(t.json.Env.Number)),
+		Time:       uint64(t.json.Env.Timestamp),
+		BaseFee:    baseFee,
+	}
+}
+
+func (tx *stTransaction) toMessage(ps stPostState, baseFee *big.Int) (*core.Message, error) {
+	var from common.Address
+	// If 'sender' field is present, use that
+	if tx.Sender != nil {
+		from = *tx.Sender
+	} else if len(tx.PrivateKey) > 0 {
+		// Derive sender from private key if needed.
+		key, err := crypto.ToECDSA(tx.PrivateKey)
+		if err != nil {
+			return nil, fmt.Errorf("invalid private key: %v", err)
+		}
+		from = crypto.PubkeyToAddress(key.PublicKey)
+	}
+	// Parse recipient if present.
+	var to *common.Address
+	if tx.To != "" {
+		to = new(common.Address)
+		if err := to.UnmarshalText([]byte(tx.To)); err != nil {
+			return nil, fmt.Errorf("invalid to address: %v", err)
+		}
+	}
+
+	// Get values specific to this post state.
+	if ps.Indexes.Data >= len(tx.Data) {
+		return nil, fmt.Errorf("tx data index %d out of bounds", ps.Indexes.Data)
+	}
+	if ps.Indexes.Value >= len(tx.Value) {
+		return nil, fmt.Errorf("tx value index %d out of bounds", ps.Indexes.Value)
+	}
+	if ps.Indexes.Gas >= len(tx.GasLimit) {
+		return nil, fmt.Errorf("tx gas limit index %d out of bounds", ps.Indexes.Gas)
+	}
+	dataHex := tx.Data[ps.Indexes.Data]
+	valueHex := tx.Value[ps.Indexes.Value]
+	gasLimit := tx.GasLimit[ps.Indexes.Gas]
+	// Value, Data hex encoding is messy: https://github.com/ethereum/tests/issues/203
+	value := new(big.Int)
+	if valueHex != "0x" {
+		v, ok := math.ParseBig256(valueHex)
+		if !ok {
+			return nil, fmt.Errorf("invalid tx value %q", valueHex)
+		}
+		value = v
+	}
+	data, err := hex.DecodeString(strings.TrimPrefix(dataHex, "0x"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid tx data %q", dataHex)
+	}
+	var accessList types.AccessList
+	if tx.AccessLists != nil && tx.AccessLists[ps.Indexes.Data] != nil {
+		accessList = *tx.AccessLists[ps.Indexes.Data]
+	}
+	// If baseFee provided, set gasPrice to effectiveGasPrice.
+	gasPrice := (*big.Int)(tx.GasPrice)
+	gasFeeCap := (*big.Int)(tx.MaxFeePerGas)
+	gasTipCap := (*big.Int)(tx.MaxPriorityFeePerGas)
+	if baseFee != nil {
+		if gasFeeCap == nil {
+			gasFeeCap = gasPrice
+		}
+		if gasFeeCap == nil {
+			gasFeeCap = new(big.Int)
+		}
+		if gasTipCap == nil {
+			gasTipCap = gasFeeCap
+		}
+		gasPrice = new(big.Int).Add(gasTipCap, baseFee)
+		if gasPrice.Cmp(gasFeeCap) > 0 {
+			gasPrice = gasFeeCap
+		}
+	}
+	if gasPrice == nil {
+		return nil, errors.New("no gas price provided")
+	}
+
+	msg := &core.Message{
+		From:          from,
+		To:            to,
+		Nonce:         uint64(tx.Nonce),
+		Value:         value,
+		GasLimit:      uint64(gasLimit),
+		GasPrice:      gasPrice,
+		GasFeeCap:     gasFeeCap,
+		GasTipCap:     gasTipCap,
+		Data:          data,
+		AccessList:    accessList,
+		BlobHashes:    tx.BlobVersionedHashes,
+		BlobGasFeeCap: (*big.Int)(tx.BlobGasFeeCap),
+	}
+	return msg, nil
+}
+
+// stChain is the chain of a state test. The ancestors of the block executing
+// the test are empty, other than starting with the initial ACP-176 fee state.
+type stChain struct {
+	config *params.ChainConfig
+}
+
+func (c stChain) Config() *params.ChainConfig { return c.config }
+
+func (stChain) Engine() consensus.Engine { return nil }
+
+func (stChain) GetHeader(_ common.Hash, number uint64) *types.Header {
+	return &types.Header{
+		Number: new(big.Int).SetUint64(number),
+		Extra:  make([]byte, acp176.StateSize),
+	}
+}
+
+func vmTestBlockHash(n uint64) common.Hash {
+	return common.BytesToHash(crypto.Keccak256([]byte(big.NewInt(int64(n)).String())))
+}
+
 // StateTestState groups all the state database objects together for use in tests.
 type StateTestState struct {
 	StateDB   *state.StateDB
@@ -34,6 +34,7 @@ import (
 	"sync"
 
 	"github.com/MetalBlockchain/coreth/params"
+	"github.com/MetalBlockchain/coreth/plugin/evm/customrawdb"
 	"github.com/MetalBlockchain/libevm/common"
 	"github.com/MetalBlockchain/libevm/core/rawdb"
 	"github.com/MetalBlockchain/libevm/core/types"
@@ -47,8 +48,8 @@ import (
 )
 
 const (
-	// maxDiffLayers is the maximum diff layers allowed in the layer tree.
-	maxDiffLayers = 128
+	// MaxDiffLayers is the maximum diff layers allowed in the layer tree.
+	MaxDiffLayers = 128
 
 	// defaultCleanSize is the default memory allowance of clean cache.
 	defaultCleanSize = 16 * 1024 * 1024
@@ -103,6 +104,12 @@ type Config struct {
 	CleanCacheSize int    // Maximum memory allowance (in bytes) for caching clean nodes
 	DirtyCacheSize int    // Maximum memory allowance (in bytes) for caching dirty nodes
 	ReadOnly       bool   // Flag whether the database is opened in read only mode.
+
+	// IndexHistory writes the state history of every block merged into the
+	// disk layer to the key-value store, indexed by account and storage slot,
+	// so that historical states can be read without their trie nodes. Indexed
+	// state histories are never pruned.
+	IndexHistory bool
 }
 
 func (c Config) BackendConstructor(diskdb ethdb.Database) triedb.DBOverride {
@@ -223,6 +230,16 @@ func New(diskdb ethdb.Database, config *Config) *Database {
 	// 		log.Crit("Failed to disable database", "err", err) // impossible to happen
 	// 	}
 	// }
+	if config.IndexHistory {
+		index, err := customrawdb.ReadStateHistoryIndex(diskdb)
+		if err != nil {
+			log.Crit("Failed to read state history index", "err", err)
+		}
+		if index != nil {
+			updateHistoryIndexGauges(index)
+			log.Info("Loaded state history index", "tail", index.Tail, "head", index.Head, "data", common.StorageSize(index.DataBytes), "index", common.StorageSize(index.IndexBytes))
+		}
+	}
 	log.Warn("Path-based state scheme is an experimental feature")
 	return db
 }
@@ -260,7 +277,7 @@ func (db *Database) Update(root common.Hash, parentRoot common.Hash, block uint6
 	// - head-1 layer is paired with HEAD-1 state
 	// - head-127 layer(bottom-most diff layer) is paired with HEAD-127 state
 	// - head-128 layer(disk layer) is paired with HEAD-128 state
-	return db.tree.cap(root, maxDiffLayers)
+	return db.tree.cap(root, MaxDiffLayers)
 }
 
 // Commit traverses downwards the layer tree from a specified layer with the
@@ -190,6 +190,13 @@ func (dl *diskLayer) commit(bottom *diffLayer, force bool) (*diskLayer, error) {
 		overflow bool
 		oldest   uint64
 	)
+	// Without a freezer, the state history is only written if it is indexed,
+	// and indexed state histories are never truncated.
+	if dl.db.config.IndexHistory {
+		if err := writeIndexedHistory(dl.db.diskdb, bottom); err != nil {
+			return nil, err
+		}
+	}
 	// NOTE(freezer): This is disabled since we do not have a freezer.
 	// if dl.db.freezer != nil {
 	// 	err := writeHistory(dl.db.freezer, bottom)
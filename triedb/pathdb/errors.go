@@ -62,6 +62,11 @@ var (
 	// errUnexpectedNode is returned if the requested node with specified path is
 	// not hash matched with expectation.
 	errUnexpectedNode = errors.New("unexpected node")
+
+	// ErrHistoricalStateUnsupported is returned when proving or iterating a
+	// historical state read from the indexed state histories, which do not
+	// hold its trie nodes.
+	ErrHistoricalStateUnsupported = errors.New("proofs and trie iteration are not supported for historical states read from the state history index")
 )
 
 func newUnexpectedNodeError(loc string, expHash common.Hash, gotHash common.Hash, owner common.Hash, path []byte, blob []byte) error {
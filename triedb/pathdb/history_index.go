@@ -0,0 +1,427 @@
+// Copyright (C) 2019-2025, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package pathdb
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/MetalBlockchain/libevm/common"
+	"github.com/MetalBlockchain/libevm/core/rawdb"
+	"github.com/MetalBlockchain/libevm/core/types"
+	"github.com/MetalBlockchain/libevm/crypto"
+	"github.com/MetalBlockchain/libevm/ethdb"
+	"github.com/MetalBlockchain/libevm/log"
+	"github.com/MetalBlockchain/libevm/rlp"
+	"github.com/MetalBlockchain/libevm/trie"
+	"github.com/MetalBlockchain/libevm/triedb/database"
+
+	"github.com/MetalBlockchain/coreth/plugin/evm/customrawdb"
+)
+
+// Indexed state histories
+//
+// Without a freezer, state histories are not written by default. When
+// [Config.IndexHistory] is set, the state history of every diff layer merged
+// into the disk layer is written to the key-value store, keyed by its state
+// id, along with an index entry for each account and storage slot it modifies:
+//
+//	account index: address + state id -> empty
+//	storage index: address + slot hash + state id -> empty
+//
+// Since a state history records the values *before* the state transition,
+// the value of an account or slot in the state with id N is the value recorded
+// in the first state history after N modifying it. It is found with a single
+// seek in the index, and a binary search in that state history. If no state
+// history after N modifies it, the value is unchanged in the disk layer.
+
+const maxHistoryReadAttempts = 3 // Retries when the disk layer is flattened during a read
+
+var (
+	// errHistoryIndexDisabled is returned if historical states are requested
+	// from a database not indexing state histories.
+	errHistoryIndexDisabled = errors.New("state history index is disabled")
+
+	// errHistoryUnavailable is returned if the state histories required to
+	// read a historical state are not indexed.
+	errHistoryUnavailable = errors.New("state history is not available")
+
+	// errIncompleteHistory is returned if the storage of an account cannot be
+	// read from the state histories, as the storage deleted with the account
+	// was too large to be recorded.
+	errIncompleteHistory = errors.New("incomplete state history")
+
+	// errCorruptedHistory is returned if an indexed state history does not
+	// contain the account or storage slot indexed for it.
+	errCorruptedHistory = errors.New("corrupted state history")
+)
+
+// encodedHistory is the format in which state histories are stored in the
+// key-value store.
+type encodedHistory struct {
+	Meta           []byte
+	AccountIndexes []byte
+	StorageIndexes []byte
+	AccountData    []byte
+	StorageData    []byte
+}
+
+// writeIndexedHistory writes the state history of the diff layer [dl] and its
+// index entries to [db]. State histories that were already indexed, as their
+// layers are merged again after an unclean shutdown, are skipped.
+func writeIndexedHistory(db ethdb.KeyValueStore, dl *diffLayer) error {
+	index, err := customrawdb.ReadStateHistoryIndex(db)
+	if err != nil {
+		return err
+	}
+	if index != nil && dl.stateID() <= index.Head {
+		return nil
+	}
+	if index == nil {
+		index = &customrawdb.StateHistoryIndex{Tail: dl.stateID()}
+	}
+
+	var (
+		start   = time.Now()
+		h       = newHistory(dl.rootHash(), dl.parentLayer().rootHash(), dl.block, dl.states)
+		id      = dl.stateID()
+		batch   = db.NewBatch()
+		encoded encodedHistory
+	)
+	encoded.Meta = h.meta.encode()
+	encoded.AccountData, encoded.StorageData, encoded.AccountIndexes, encoded.StorageIndexes = h.encode()
+	blob, err := rlp.EncodeToBytes(&encoded)
+	if err != nil {
+		return err
+	}
+	if err := customrawdb.WriteStateHistory(batch, id, blob); err != nil {
+		return err
+	}
+	dataSize := batch.ValueSize()
+
+	for _, addr := range h.accountList {
+		if err := customrawdb.WriteStateHistoryAccount(batch, addr, id); err != nil {
+			return err
+		}
+		for _, slotHash := range h.storageList[addr] {
+			if err := customrawdb.WriteStateHistoryStorage(batch, addr, slotHash, id); err != nil {
+				return err
+			}
+		}
+	}
+	indexSize := batch.ValueSize() - dataSize
+
+	index.Head = id
+	index.DataBytes += uint64(dataSize)
+	index.IndexBytes += uint64(indexSize)
+	if err := customrawdb.WriteStateHistoryIndex(batch, index); err != nil {
+		return err
+	}
+	if err := batch.Write(); err != nil {
+		return err
+	}
+
+	historyDataBytesMeter.Mark(int64(dataSize))
+	historyIndexBytesMeter.Mark(int64(indexSize))
+	historyBuildTimeMeter.UpdateSince(start)
+	updateHistoryIndexGauges(index)
+	log.Debug("Indexed state history", "id", id, "block", dl.block, "accounts", len(h.accountList), "size", common.StorageSize(dataSize+indexSize), "elapsed", common.PrettyDuration(time.Since(start)))
+	return nil
+}
+
+// updateHistoryIndexGauges reports the range and size of the indexed state
+// histories.
+func updateHistoryIndexGauges(index *customrawdb.StateHistoryIndex) {
+	historyIndexTailGauge.Update(int64(index.Tail))
+	historyIndexHeadGauge.Update(int64(index.Head))
+	historyStoredDataGauge.Update(int64(index.DataBytes))
+	historyStoredIndexGauge.Update(int64(index.IndexBytes))
+}
+
+// indexedHistory is a state history read from the key-value store. Its
+// accounts and storage slots are found by binary search, without decoding the
+// whole state history.
+type indexedHistory struct {
+	meta meta
+	encodedHistory
+}
+
+// readIndexedHistory reads the state history with [id] from [db].
+func readIndexedHistory(db ethdb.KeyValueReader, id uint64) (*indexedHistory, error) {
+	blob, err := customrawdb.ReadStateHistory(db, id)
+	if err != nil {
+		return nil, fmt.Errorf("%w: state history %d: %w", errCorruptedHistory, id, err)
+	}
+	h := new(indexedHistory)
+	if err := rlp.DecodeBytes(blob, &h.encodedHistory); err != nil {
+		return nil, fmt.Errorf("%w: state history %d: %w", errCorruptedHistory, id, err)
+	}
+	if err := h.meta.decode(h.Meta); err != nil {
+		return nil, fmt.Errorf("%w: state history %d: %w", errCorruptedHistory, id, err)
+	}
+	if len(h.AccountIndexes)%accountIndexSize != 0 || len(h.StorageIndexes)%slotIndexSize != 0 {
+		return nil, fmt.Errorf("%w: state history %d has misaligned indexes", errCorruptedHistory, id)
+	}
+	return h, nil
+}
+
+// account returns the index of the account at [addr], and whether the state
+// history modifies it.
+func (h *indexedHistory) account(addr common.Address) (accountIndex, bool) {
+	n := len(h.AccountIndexes) / accountIndexSize
+	i := sort.Search(n, func(i int) bool {
+		pos := i * accountIndexSize
+		return bytes.Compare(h.AccountIndexes[pos:pos+common.AddressLength], addr[:]) >= 0
+	})
+	if i == n {
+		return accountIndex{}, false
+	}
+	var index accountIndex
+	index.decode(h.AccountIndexes[i*accountIndexSize : (i+1)*accountIndexSize])
+	return index, index.address == addr
+}
+
+// accountData returns the slim RLP encoded account at [addr] before the state
+// transition, or nil if it did not exist.
+func (h *indexedHistory) accountData(addr common.Address) ([]byte, error) {
+	index, ok := h.account(addr)
+	if !ok {
+		return nil, fmt.Errorf("%w: account %#x not found in state history of block %d", errCorruptedHistory, addr, h.meta.block)
+	}
+	end := uint64(index.offset) + uint64(index.length)
+	if end > uint64(len(h.AccountData)) {
+		return nil, fmt.Errorf("%w: account data of block %d out of range", errCorruptedHistory, h.meta.block)
+	}
+	return h.AccountData[index.offset:end], nil
+}
+
+// storageData returns the RLP encoded value of the slot with [slotHash] of
+// the account at [addr] before the state transition, or nil if it was empty.
+func (h *indexedHistory) storageData(addr common.Address, slotHash common.Hash) ([]byte, error) {
+	accIndex, ok := h.account(addr)
+	if !ok {
+		return nil, fmt.Errorf("%w: account %#x not found in state history of block %d", errCorruptedHistory, addr, h.meta.block)
+	}
+	var (
+		first = int(accIndex.storageOffset)
+		n     = int(accIndex.storageSlots)
+	)
+	if (first+n)*slotIndexSize > len(h.StorageIndexes) {
+		return nil, fmt.Errorf("%w: storage index of block %d out of range", errCorruptedHistory, h.meta.block)
+	}
+	i := sort.Search(n, func(i int) bool {
+		pos := (first + i) * slotIndexSize
+		return bytes.Compare(h.StorageIndexes[pos:pos+common.HashLength], slotHash[:]) >= 0
+	})
+	if i == n {
+		return nil, fmt.Errorf("%w: slot %#x not found in state history of block %d", errCorruptedHistory, slotHash, h.meta.block)
+	}
+	var index slotIndex
+	index.decode(h.StorageIndexes[(first+i)*slotIndexSize : (first+i+1)*slotIndexSize])
+	if index.hash != slotHash {
+		return nil, fmt.Errorf("%w: slot %#x not found in state history of block %d", errCorruptedHistory, slotHash, h.meta.block)
+	}
+	end := uint64(index.offset) + uint64(index.length)
+	if end > uint64(len(h.StorageData)) {
+		return nil, fmt.Errorf("%w: storage data of block %d out of range", errCorruptedHistory, h.meta.block)
+	}
+	return h.StorageData[index.offset:end], nil
+}
+
+// incomplete returns whether the storage of the account at [addr] deleted in
+// the state transition is not recorded in full.
+func (h *indexedHistory) incomplete(addr common.Address) bool {
+	for _, incomplete := range h.meta.incomplete {
+		if incomplete == addr {
+			return true
+		}
+	}
+	return false
+}
+
+// HistoryReader reads the accounts and storage slots of a state that is no
+// longer held by the layer tree, from the indexed state histories.
+type HistoryReader struct {
+	db   *Database
+	root common.Hash
+	id   uint64
+}
+
+// HistoryReader returns a reader of the state with [root] from the indexed
+// state histories.
+func (db *Database) HistoryReader(root common.Hash) (*HistoryReader, error) {
+	if !db.config.IndexHistory {
+		return nil, errHistoryIndexDisabled
+	}
+	id := rawdb.ReadStateID(db.diskdb, root)
+	if id == nil {
+		return nil, fmt.Errorf("%w: unknown state %#x", errHistoryUnavailable, root)
+	}
+	index, err := customrawdb.ReadStateHistoryIndex(db.diskdb)
+	if err != nil {
+		return nil, err
+	}
+	// The state with id N is reverted from the state history N+1, so the
+	// state before the first indexed state history is also available.
+	if index == nil || *id+1 < index.Tail {
+		return nil, fmt.Errorf("%w: state %#x with id %d", errHistoryUnavailable, root, *id)
+	}
+	return &HistoryReader{
+		db:   db,
+		root: root,
+		id:   *id,
+	}, nil
+}
+
+// HistoryIndexed returns whether the database indexes state histories.
+func (db *Database) HistoryIndexed() bool {
+	return db.config.IndexHistory
+}
+
+// IsHistoricalState returns whether the state with [root] is not held by the
+// database, and can only be read from the indexed state histories.
+func (db *Database) IsHistoricalState(root common.Hash) bool {
+	return db.config.IndexHistory && db.tree.get(root) == nil
+}
+
+// Root returns the root of the state read.
+func (r *HistoryReader) Root() common.Hash {
+	return r.root
+}
+
+// Account returns the account at [addr], or nil if it does not exist.
+func (r *HistoryReader) Account(addr common.Address) (*types.StateAccount, error) {
+	for attempt := 1; ; attempt++ {
+		dl, err := r.diskLayer()
+		if err != nil {
+			return nil, err
+		}
+		id, changed, err := customrawdb.ReadStateHistoryAccountChange(r.db.diskdb, addr, r.id, dl.stateID())
+		if err != nil {
+			return nil, err
+		}
+		if changed {
+			h, err := readIndexedHistory(r.db.diskdb, id)
+			if err != nil {
+				return nil, err
+			}
+			data, err := h.accountData(addr)
+			if err != nil || len(data) == 0 {
+				return nil, err
+			}
+			return types.FullAccount(data)
+		}
+		account, err := readDiskAccount(dl, addr)
+		if errors.Is(err, errSnapshotStale) && attempt < maxHistoryReadAttempts {
+			continue
+		}
+		return account, err
+	}
+}
+
+// Storage returns the value of the storage slot [key] of the account at
+// [addr], or nil if it is empty.
+func (r *HistoryReader) Storage(addr common.Address, key []byte) ([]byte, error) {
+	slotHash := crypto.Keccak256Hash(key)
+	for attempt := 1; ; attempt++ {
+		dl, err := r.diskLayer()
+		if err != nil {
+			return nil, err
+		}
+		accountID, accountChanged, err := customrawdb.ReadStateHistoryAccountChange(r.db.diskdb, addr, r.id, dl.stateID())
+		if err != nil {
+			return nil, err
+		}
+		slotID, slotChanged, err := customrawdb.ReadStateHistoryStorageChange(r.db.diskdb, addr, slotHash, r.id, dl.stateID())
+		if err != nil {
+			return nil, err
+		}
+		// The account is modified whenever its storage is, so the slot is
+		// modified first, or by the same state history as the account. If the
+		// account is deleted first, the slot may be missing from an incomplete
+		// state history.
+		if accountChanged && (!slotChanged || slotID > accountID) {
+			h, err := readIndexedHistory(r.db.diskdb, accountID)
+			if err != nil {
+				return nil, err
+			}
+			if h.incomplete(addr) {
+				return nil, fmt.Errorf("%w: storage of account %#x deleted in block %d", errIncompleteHistory, addr, h.meta.block)
+			}
+		}
+		if slotChanged {
+			h, err := readIndexedHistory(r.db.diskdb, slotID)
+			if err != nil {
+				return nil, err
+			}
+			data, err := h.storageData(addr, slotHash)
+			if err != nil || len(data) == 0 {
+				return nil, err
+			}
+			_, content, _, err := rlp.Split(data)
+			return content, err
+		}
+		value, err := readDiskStorage(dl, addr, key)
+		if errors.Is(err, errSnapshotStale) && attempt < maxHistoryReadAttempts {
+			continue
+		}
+		return value, err
+	}
+}
+
+// diskLayer returns the disk layer the state is reverted from.
+func (r *HistoryReader) diskLayer() (*diskLayer, error) {
+	dl := r.db.tree.bottom()
+	if dl == nil {
+		return nil, errSnapshotStale
+	}
+	if r.id > dl.stateID() {
+		return nil, fmt.Errorf("%w: state %#x is above the disk layer", errHistoryUnavailable, r.root)
+	}
+	return dl, nil
+}
+
+// diskLayerDatabase is a trie database reading the trie nodes of a single disk
+// layer, so that reads fail with [errSnapshotStale] once it is flattened.
+type diskLayerDatabase struct {
+	dl *diskLayer
+}
+
+func (db diskLayerDatabase) Reader(root common.Hash) (database.Reader, error) {
+	if root != db.dl.rootHash() {
+		return nil, fmt.Errorf("state %#x is not the disk layer", root)
+	}
+	return db.dl, nil
+}
+
+func (diskLayerDatabase) Preimage(common.Hash) []byte {
+	return nil
+}
+
+// readDiskAccount reads the account at [addr] from the trie of [dl].
+func readDiskAccount(dl *diskLayer, addr common.Address) (*types.StateAccount, error) {
+	tr, err := trie.NewStateTrie(trie.StateTrieID(dl.rootHash()), diskLayerDatabase{dl})
+	if err != nil {
+		return nil, err
+	}
+	return tr.GetAccount(addr)
+}
+
+// readDiskStorage reads the storage slot [key] of the account at [addr] from
+// the tries of [dl].
+func readDiskStorage(dl *diskLayer, addr common.Address, key []byte) ([]byte, error) {
+	account, err := readDiskAccount(dl, addr)
+	if err != nil || account == nil {
+		return nil, err
+	}
+	id := trie.StorageTrieID(dl.rootHash(), crypto.Keccak256Hash(addr.Bytes()), account.Root)
+	tr, err := trie.NewStateTrie(id, diskLayerDatabase{dl})
+	if err != nil {
+		return nil, err
+	}
+	return tr.GetStorage(addr, key)
+}
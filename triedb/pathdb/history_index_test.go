@@ -0,0 +1,98 @@
+// Copyright (C) 2019-2025, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package pathdb
+
+import (
+	"testing"
+
+	"github.com/MetalBlockchain/libevm/core/rawdb"
+	"github.com/MetalBlockchain/libevm/core/types"
+	"github.com/MetalBlockchain/libevm/trie/testutil"
+	"github.com/stretchr/testify/require"
+
+	"github.com/MetalBlockchain/coreth/plugin/evm/customrawdb"
+)
+
+func TestWriteIndexedHistory(t *testing.T) {
+	require := require.New(t)
+
+	var (
+		db           = rawdb.NewMemoryDatabase()
+		parent layer = emptyLayer()
+		layers []*diffLayer
+	)
+	for i := 1; i <= 3; i++ {
+		dl := newDiffLayer(parent, testutil.RandomHash(), uint64(i), uint64(i), nil, randomStateSet(3))
+		require.NoError(writeIndexedHistory(db, dl))
+		layers = append(layers, dl)
+		parent = dl
+	}
+
+	index, err := customrawdb.ReadStateHistoryIndex(db)
+	require.NoError(err)
+	require.NotNil(index)
+	require.Equal(uint64(1), index.Tail)
+	require.Equal(uint64(3), index.Head)
+	require.NotZero(index.DataBytes)
+	require.NotZero(index.IndexBytes)
+
+	// Layers merged again after an unclean shutdown are not indexed twice.
+	require.NoError(writeIndexedHistory(db, layers[1]))
+	again, err := customrawdb.ReadStateHistoryIndex(db)
+	require.NoError(err)
+	require.Equal(index, again)
+
+	for _, dl := range layers {
+		h, err := readIndexedHistory(db, dl.stateID())
+		require.NoError(err)
+		require.Equal(dl.block, h.meta.block)
+		require.Equal(dl.rootHash(), h.meta.root)
+
+		for addr, account := range dl.states.Accounts {
+			data, err := h.accountData(addr)
+			require.NoError(err)
+			require.Equal(account, data)
+
+			id, ok, err := customrawdb.ReadStateHistoryAccountChange(db, addr, 0, index.Head)
+			require.NoError(err)
+			require.True(ok)
+			require.Equal(dl.stateID(), id)
+
+			for slotHash, value := range dl.states.Storages[addr] {
+				data, err := h.storageData(addr, slotHash)
+				require.NoError(err)
+				require.Equal(value, data)
+
+				id, ok, err := customrawdb.ReadStateHistoryStorageChange(db, addr, slotHash, 0, index.Head)
+				require.NoError(err)
+				require.True(ok)
+				require.Equal(dl.stateID(), id)
+			}
+			_, err = h.storageData(addr, testutil.RandomHash())
+			require.ErrorIs(err, errCorruptedHistory)
+		}
+		_, err = h.accountData(testutil.RandomAddress())
+		require.ErrorIs(err, errCorruptedHistory)
+	}
+}
+
+func TestHistoryReaderDisabled(t *testing.T) {
+	db := New(rawdb.NewMemoryDatabase(), nil)
+	require.False(t, db.HistoryIndexed())
+	_, err := db.HistoryReader(testutil.RandomHash())
+	require.ErrorIs(t, err, errHistoryIndexDisabled)
+}
+
+func TestHistoricalStateUnsupported(t *testing.T) {
+	require := require.New(t)
+
+	db := New(rawdb.NewMemoryDatabase(), &Config{IndexHistory: true})
+	require.False(db.IsHistoricalState(types.EmptyRootHash))
+	require.True(db.IsHistoricalState(testutil.RandomHash()))
+
+	tr := &HistoryTrie{}
+	_, err := tr.NodeIterator(nil)
+	require.ErrorIs(err, ErrHistoricalStateUnsupported)
+	require.ErrorIs(tr.Prove(nil, rawdb.NewMemoryDatabase()), ErrHistoricalStateUnsupported)
+}
@@ -0,0 +1,177 @@
+// Copyright (C) 2019-2025, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package pathdb
+
+import (
+	"errors"
+
+	"github.com/MetalBlockchain/libevm/common"
+	"github.com/MetalBlockchain/libevm/core/types"
+	"github.com/MetalBlockchain/libevm/ethdb"
+	"github.com/MetalBlockchain/libevm/trie"
+	"github.com/MetalBlockchain/libevm/trie/trienode"
+)
+
+var errHistoryTrieReadOnly = errors.New("historical state cannot be committed")
+
+// HistoryTrie implements state.Trie for the account trie of a historical
+// state, reading accounts and storage slots through a [HistoryReader] rather
+// than from trie nodes. Updates are kept in memory, so that transactions can
+// be executed on top of the historical state, but its hash is not recomputed
+// and it cannot be committed.
+type HistoryTrie struct {
+	reader   *HistoryReader
+	accounts map[common.Address]*types.StateAccount // Updated accounts, nil if deleted
+	storages map[common.Address]map[string][]byte   // Updated storage slots by key, nil if deleted
+}
+
+// NewHistoryTrie returns the account trie of the historical state with [root].
+func NewHistoryTrie(root common.Hash, db *Database) (*HistoryTrie, error) {
+	reader, err := db.HistoryReader(root)
+	if err != nil {
+		return nil, err
+	}
+	return &HistoryTrie{
+		reader:   reader,
+		accounts: make(map[common.Address]*types.StateAccount),
+		storages: make(map[common.Address]map[string][]byte),
+	}, nil
+}
+
+// GetAccount implements state.Trie.
+func (t *HistoryTrie) GetAccount(addr common.Address) (*types.StateAccount, error) {
+	if account, ok := t.accounts[addr]; ok {
+		if account == nil {
+			return nil, nil
+		}
+		return account.Copy(), nil
+	}
+	return t.reader.Account(addr)
+}
+
+// GetStorage implements state.Trie.
+func (t *HistoryTrie) GetStorage(addr common.Address, key []byte) ([]byte, error) {
+	if account, ok := t.accounts[addr]; ok && account == nil {
+		return nil, nil
+	}
+	if value, ok := t.storages[addr][string(key)]; ok {
+		return common.CopyBytes(value), nil
+	}
+	return t.reader.Storage(addr, key)
+}
+
+// UpdateAccount implements state.Trie.
+func (t *HistoryTrie) UpdateAccount(addr common.Address, account *types.StateAccount) error {
+	t.accounts[addr] = account.Copy()
+	return nil
+}
+
+// UpdateStorage implements state.Trie.
+func (t *HistoryTrie) UpdateStorage(addr common.Address, key []byte, value []byte) error {
+	t.setStorage(addr, key, common.CopyBytes(value))
+	return nil
+}
+
+// DeleteAccount implements state.Trie.
+func (t *HistoryTrie) DeleteAccount(addr common.Address) error {
+	t.accounts[addr] = nil
+	delete(t.storages, addr)
+	return nil
+}
+
+// DeleteStorage implements state.Trie.
+func (t *HistoryTrie) DeleteStorage(addr common.Address, key []byte) error {
+	t.setStorage(addr, key, nil)
+	return nil
+}
+
+func (t *HistoryTrie) setStorage(addr common.Address, key []byte, value []byte) {
+	storage, ok := t.storages[addr]
+	if !ok {
+		storage = make(map[string][]byte)
+		t.storages[addr] = storage
+	}
+	storage[string(key)] = value
+}
+
+// UpdateContractCode implements state.Trie.
+// Contract code is controlled by rawdb, so we don't need to do anything here.
+func (*HistoryTrie) UpdateContractCode(_ common.Address, _ common.Hash, _ []byte) error {
+	return nil
+}
+
+// Hash implements state.Trie. It returns the root of the historical state,
+// regardless of the updates.
+func (t *HistoryTrie) Hash() common.Hash {
+	return t.reader.Root()
+}
+
+// Commit implements state.Trie.
+func (*HistoryTrie) Commit(_ bool) (common.Hash, *trienode.NodeSet, error) {
+	return common.Hash{}, nil, errHistoryTrieReadOnly
+}
+
+// GetKey implements state.Trie.
+func (*HistoryTrie) GetKey(_ []byte) []byte {
+	return nil // Not implemented, as this is only used in APIs
+}
+
+// NodeIterator implements state.Trie.
+func (*HistoryTrie) NodeIterator(_ []byte) (trie.NodeIterator, error) {
+	return nil, ErrHistoricalStateUnsupported
+}
+
+// Prove implements state.Trie.
+func (*HistoryTrie) Prove(_ []byte, _ ethdb.KeyValueWriter) error {
+	return ErrHistoricalStateUnsupported
+}
+
+// Copy returns a deep copy of the trie.
+func (t *HistoryTrie) Copy() *HistoryTrie {
+	cpy := &HistoryTrie{
+		reader:   t.reader,
+		accounts: make(map[common.Address]*types.StateAccount, len(t.accounts)),
+		storages: make(map[common.Address]map[string][]byte, len(t.storages)),
+	}
+	for addr, account := range t.accounts {
+		if account != nil {
+			account = account.Copy()
+		}
+		cpy.accounts[addr] = account
+	}
+	for addr, storage := range t.storages {
+		cpy.storages[addr] = make(map[string][]byte, len(storage))
+		for key, value := range storage {
+			cpy.storages[addr][key] = common.CopyBytes(value)
+		}
+	}
+	return cpy
+}
+
+// HistoryStorageTrie is a wrapper around a [HistoryTrie], which reads and
+// updates the storage slots of every account.
+type HistoryStorageTrie struct {
+	*HistoryTrie
+	storageRoot common.Hash
+}
+
+// NewHistoryStorageTrie returns a storage trie of the historical state read
+// by [accountTrie].
+func NewHistoryStorageTrie(accountTrie *HistoryTrie, storageRoot common.Hash) *HistoryStorageTrie {
+	return &HistoryStorageTrie{
+		HistoryTrie: accountTrie,
+		storageRoot: storageRoot,
+	}
+}
+
+// Hash returns the storage root of the account in the historical state,
+// regardless of the updates.
+func (s *HistoryStorageTrie) Hash() common.Hash {
+	return s.storageRoot
+}
+
+// Commit implements state.Trie.
+func (s *HistoryStorageTrie) Commit(_ bool) (common.Hash, *trienode.NodeSet, error) {
+	return s.storageRoot, nil, errHistoryTrieReadOnly
+}
@@ -72,3 +72,10 @@ var (
 	historyDataBytesMeter  = metrics.GetOrRegisterMeter("pathdb/history/bytes/data", nil)
 	historyIndexBytesMeter = metrics.GetOrRegisterMeter("pathdb/history/bytes/index", nil)
 )
+
+var (
+	historyIndexTailGauge   = metrics.NewRegisteredGauge("pathdb/history/indexed/tail", nil)
+	historyIndexHeadGauge   = metrics.NewRegisteredGauge("pathdb/history/indexed/head", nil)
+	historyStoredDataGauge  = metrics.NewRegisteredGauge("pathdb/history/indexed/bytes/data", nil)
+	historyStoredIndexGauge = metrics.NewRegisteredGauge("pathdb/history/indexed/bytes/index", nil)
+)